@@ -0,0 +1,188 @@
+package client
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/filter"
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+// AllocationQuery holds the parameters of an /allocation query. Only Window is
+// required.
+type AllocationQuery struct {
+	Window opencost.Window
+	// Step is the duration of each AllocationSet of the range. Defaults to the
+	// duration of the window.
+	Step      time.Duration
+	Aggregate []string
+	Filter    filter.Filter
+	// Accumulate sums the sets of the range by the given option.
+	Accumulate                            opencost.AccumulateOption
+	IncludeIdle                           bool
+	ShareIdle                             bool
+	IdleByNode                            bool
+	ShareLB                               bool
+	IncludeProportionalAssetResourceCosts bool
+	IncludeAggregatedMetadata             bool
+	IncludeCustomCosts                    bool
+	IncludeSharedCostBreakdown            bool
+	// ShareNamespaces and ShareLabels select the allocations whose costs are
+	// shared with the others, split by ShareSplit, either "weighted" (the
+	// default) or "even".
+	ShareNamespaces   []string
+	ShareLabels       map[string][]string
+	ShareSplit        string
+	ShareTenancyCosts bool
+}
+
+func (q AllocationQuery) params() (url.Values, error) {
+	params := url.Values{}
+
+	window, err := windowParam(q.Window)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("window", window)
+
+	if q.Step > 0 {
+		params.Set("step", q.Step.String())
+	}
+	if len(q.Aggregate) > 0 {
+		params.Set("aggregate", strings.Join(q.Aggregate, ","))
+	}
+	if err := setFilter(params, q.Filter); err != nil {
+		return nil, err
+	}
+	if q.Accumulate != opencost.AccumulateOptionNone {
+		params.Set("accumulateBy", string(q.Accumulate))
+	}
+
+	setBool(params, "includeIdle", q.IncludeIdle)
+	setBool(params, "shareIdle", q.ShareIdle)
+	setBool(params, "idleByNode", q.IdleByNode)
+	setBool(params, "sharelb", q.ShareLB)
+	setBool(params, "includeProportionalAssetResourceCosts", q.IncludeProportionalAssetResourceCosts)
+	setBool(params, "includeAggregatedMetadata", q.IncludeAggregatedMetadata)
+	setBool(params, "includeCustomCosts", q.IncludeCustomCosts)
+	setBool(params, "includeSharedCostBreakdown", q.IncludeSharedCostBreakdown)
+	setBool(params, "shareTenancyCosts", q.ShareTenancyCosts)
+
+	if len(q.ShareNamespaces) > 0 {
+		params.Set("shareNamespaces", strings.Join(q.ShareNamespaces, ","))
+	}
+	if len(q.ShareLabels) > 0 {
+		var shareLabels []string
+		for _, key := range slices.Sorted(maps.Keys(q.ShareLabels)) {
+			for _, value := range q.ShareLabels[key] {
+				shareLabels = append(shareLabels, key+":"+value)
+			}
+		}
+		params.Set("shareLabels", strings.Join(shareLabels, ","))
+	}
+	if q.ShareSplit != "" {
+		params.Set("shareSplit", q.ShareSplit)
+	}
+
+	return params, nil
+}
+
+// QueryAllocation queries /allocation. The API does not encode the windows of the
+// sets, so the window of each set spans the allocations it contains, and is empty
+// for sets without allocations.
+func (c *Client) QueryAllocation(ctx context.Context, query AllocationQuery) (*opencost.AllocationSetRange, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []map[string]*opencost.Allocation
+	if err := c.get(ctx, "/allocation", params, &sets); err != nil {
+		return nil, err
+	}
+
+	asr := opencost.NewAllocationSetRange()
+	for _, allocs := range sets {
+		var start, end time.Time
+		for _, alloc := range allocs {
+			if alloc == nil {
+				continue
+			}
+			if start.IsZero() || alloc.Start.Before(start) {
+				start = alloc.Start
+			}
+			if alloc.End.After(end) {
+				end = alloc.End
+			}
+		}
+
+		as := opencost.NewAllocationSet(start, end)
+		for name, alloc := range allocs {
+			if alloc == nil {
+				continue
+			}
+			as.Allocations[name] = alloc
+		}
+		asr.Append(as)
+	}
+
+	return asr, nil
+}
+
+// AllocationSummaryQuery holds the parameters of an /allocation/summary query. Only
+// Window is required.
+type AllocationSummaryQuery struct {
+	Window opencost.Window
+	// Step is the duration of each SummaryAllocationSet of the range. Defaults to
+	// the duration of the window.
+	Step      time.Duration
+	Aggregate []string
+	Filter    filter.Filter
+	// Accumulate sums the sets of the range into one.
+	Accumulate         bool
+	IncludeCustomCosts bool
+}
+
+func (q AllocationSummaryQuery) params() (url.Values, error) {
+	params := url.Values{}
+
+	window, err := windowParam(q.Window)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("window", window)
+
+	if q.Step > 0 {
+		params.Set("step", q.Step.String())
+	}
+	if len(q.Aggregate) > 0 {
+		params.Set("aggregate", strings.Join(q.Aggregate, ","))
+	}
+	if err := setFilter(params, q.Filter); err != nil {
+		return nil, err
+	}
+
+	setBool(params, "accumulate", q.Accumulate)
+	setBool(params, "includeCustomCosts", q.IncludeCustomCosts)
+
+	return params, nil
+}
+
+// QueryAllocationSummary queries /allocation/summary.
+func (c *Client) QueryAllocationSummary(ctx context.Context, query AllocationSummaryQuery) (*opencost.SummaryAllocationSetRange, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	sasr := &opencost.SummaryAllocationSetRange{}
+	if err := c.get(ctx, "/allocation/summary", params, sasr); err != nil {
+		return nil, err
+	}
+
+	return sasr, nil
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/opencost/opencost/core/pkg/filter"
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+// AssetQuery holds the parameters of an /assets query. Only Window is required.
+type AssetQuery struct {
+	Window opencost.Window
+	Filter filter.Filter
+}
+
+func (q AssetQuery) params() (url.Values, error) {
+	params := url.Values{}
+
+	window, err := windowParam(q.Window)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("window", window)
+
+	if err := setFilter(params, q.Filter); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// QueryAssets queries /assets, returning an AssetSet spanning the window of the query.
+func (c *Client) QueryAssets(ctx context.Context, query AssetQuery) (*opencost.AssetSet, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp opencost.AssetSetResponse
+	if err := c.get(ctx, "/assets", params, &resp); err != nil {
+		return nil, err
+	}
+
+	as := opencost.NewAssetSet(*query.Window.Start(), *query.Window.End())
+	for _, asset := range resp.Assets {
+		if err := as.Insert(asset, nil); err != nil {
+			return nil, fmt.Errorf("inserting asset: %w", err)
+		}
+	}
+
+	return as, nil
+}
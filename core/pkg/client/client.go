@@ -0,0 +1,231 @@
+// Package client provides a typed Go client for the OpenCost HTTP API.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/filter"
+	"github.com/opencost/opencost/core/pkg/filter/ast"
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+const (
+	defaultTimeout    = 5 * time.Minute
+	defaultRetries    = 3
+	defaultRetryDelay = 500 * time.Millisecond
+)
+
+// Client queries the OpenCost HTTP API and decodes the responses into the core types
+// used by the server to produce them, or client types mirroring the server's responses
+// where those are not part of core.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retries    int
+	retryDelay time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http.Client used to send requests, e.g. to configure TLS or
+// authentication.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetries sets the number of times a request is retried after a network error or a
+// transient server error, and the delay before the first retry. The delay doubles with
+// each subsequent retry. Zero retries disables retrying.
+func WithRetries(retries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.retryDelay = delay
+	}
+}
+
+// NewClient creates a new Client for the OpenCost API served at the provided base URL,
+// e.g. "http://opencost.opencost:9003".
+func NewClient(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("base url '%s' must be absolute", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		retryDelay: defaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Error is returned when the API responds with a non-successful status code.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("opencost api returned %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is an API error with a 404 status code.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// response is the envelope the API wraps around response data.
+type response struct {
+	Code    int             `json:"code"`
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message,omitempty"`
+}
+
+// get sends a GET request for the provided path and query parameters and decodes the
+// data of the response into data.
+func (c *Client) get(ctx context.Context, path string, params url.Values, data any) error {
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = params.Encode()
+
+	var body []byte
+	var err error
+
+	delay := c.retryDelay
+	for attempt := 0; ; attempt++ {
+		body, err = c.do(ctx, u.String())
+		if err == nil || attempt >= c.retries || !isRetryable(err) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	if err != nil {
+		return err
+	}
+
+	var resp response
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("decoding response from %s: %w", path, err)
+	}
+	if len(resp.Data) == 0 || string(resp.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Data, data); err != nil {
+		return fmt.Errorf("decoding data from %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, &Error{
+			StatusCode: res.StatusCode,
+			Message:    errorMessage(body),
+		}
+	}
+
+	return body, nil
+}
+
+// errorMessage extracts the message of an error response, which is either a JSON
+// envelope or plain text depending on the endpoint.
+func errorMessage(body []byte) string {
+	var resp response
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Message != "" {
+		return resp.Message
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// isRetryable returns true for network errors and transient server errors. Request
+// errors and endpoints which are not enabled on the server are not retried.
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// windowParam formats a closed window as a pair of RFC3339 timestamps, which every
+// endpoint accepts.
+func windowParam(window opencost.Window) (string, error) {
+	if window.IsOpen() {
+		return "", fmt.Errorf("window must be closed: %s", window)
+	}
+
+	start, end := window.Start().UTC(), window.End().UTC()
+	return start.Format(time.RFC3339) + "," + end.Format(time.RFC3339), nil
+}
+
+// setFilter sets the filter parameter to the filter language representation of the
+// provided filter, if there is one.
+func setFilter(params url.Values, f filter.Filter) error {
+	s, err := ast.ToFilterString(f)
+	if err != nil {
+		return fmt.Errorf("formatting filter: %w", err)
+	}
+	if s != "" {
+		params.Set("filter", s)
+	}
+	return nil
+}
+
+func setBool(params url.Values, key string, value bool) {
+	if value {
+		params.Set(key, "true")
+	}
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/protocol"
+)
+
+var (
+	testStart  = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testEnd    = testStart.Add(24 * time.Hour)
+	testWindow = opencost.NewClosedWindow(testStart, testEnd)
+)
+
+var proto = protocol.HTTP()
+
+// testServer serves canned data for each path, recording the query parameters of the
+// last request to each. The cloud cost and custom cost queries, whose response types
+// the client keeps its own copies of, are tested against the real handlers in
+// pkg/cloudcost and pkg/customcost instead.
+type testServer struct {
+	router  *httprouter.Router
+	queries map[string]url.Values
+}
+
+func newTestServer() *testServer {
+	return &testServer{
+		router:  httprouter.New(),
+		queries: map[string]url.Values{},
+	}
+}
+
+func (s *testServer) serve(path string, data any) {
+	s.router.GET(path, func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		s.queries[path] = r.URL.Query()
+		proto.WriteData(w, data)
+	})
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+	return client
+}
+
+func TestStatusEndpoints(t *testing.T) {
+	server := newTestServer()
+	server.serve("/clusterInfo", map[string]string{"id": "cluster-one", "provider": "AWS"})
+	server.serve("/pricingSourceStatus", map[string]any{
+		"spot": map[string]any{"name": "spot", "enabled": true, "available": true},
+	})
+	server.serve("/pricingSourceCounts", map[string]any{
+		"TotalNodes":  3,
+		"PricingType": map[string]int{"api": 2, "spot": 1},
+	})
+	server.serve("/pricingSourceSummary", map[string]string{"region": "us-east-1"})
+	client := newTestClient(t, server.router)
+	ctx := context.Background()
+
+	info, err := client.ClusterInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "cluster-one", info["id"])
+
+	status, err := client.PricingSourceStatus(ctx)
+	require.NoError(t, err)
+	require.Contains(t, status, "spot")
+	assert.True(t, status["spot"].Available)
+
+	counts, err := client.PricingSourceCounts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, counts.TotalNodes)
+	assert.Equal(t, 1, counts.PricingTypeCounts["spot"])
+
+	summary, err := client.PricingSourceSummary(ctx)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"region":"us-east-1"}`, string(summary))
+}
+
+func TestQueryAllocationAndAssets(t *testing.T) {
+	props := &opencost.AllocationProperties{Cluster: "cluster-one", Namespace: "kubecost"}
+	alloc := opencost.NewMockUnitAllocation("kubecost", testStart, 24*time.Hour, props)
+	asr := opencost.NewAllocationSetRange(opencost.NewAllocationSet(testStart, testEnd, alloc))
+
+	sasr := opencost.NewSummaryAllocationSetRange(opencost.NewMockUnitSummaryAllocationSet(testStart, 24*time.Hour))
+
+	node := opencost.NewNode("node1", "cluster-one", "node1", testStart, testEnd, testWindow)
+	node.CPUCost = 3
+	assetSet := opencost.NewAssetSet(testStart, testEnd, node)
+
+	server := newTestServer()
+	server.serve("/allocation", asr)
+	server.serve("/allocation/summary", sasr)
+	server.serve("/assets", assetSet)
+	client := newTestClient(t, server.router)
+	ctx := context.Background()
+
+	result, err := client.QueryAllocation(ctx, AllocationQuery{
+		Window:      testWindow,
+		Step:        24 * time.Hour,
+		Aggregate:   []string{"namespace"},
+		Accumulate:  opencost.AccumulateOptionDay,
+		IncludeIdle: true,
+		ShareLabels: map[string][]string{"team": {"platform", "infra"}, "app": {"proxy"}},
+		ShareSplit:  "even",
+	})
+	require.NoError(t, err)
+	query := server.queries["/allocation"]
+	assert.Equal(t, []string{"2024-01-01T00:00:00Z,2024-01-02T00:00:00Z"}, query["window"])
+	assert.Equal(t, []string{"namespace"}, query["aggregate"])
+	assert.Equal(t, []string{"day"}, query["accumulateBy"])
+	assert.Equal(t, []string{"true"}, query["includeIdle"])
+	assert.NotContains(t, query, "shareIdle")
+	assert.Equal(t, []string{"app:proxy,team:platform,team:infra"}, query["shareLabels"])
+	assert.Equal(t, []string{"even"}, query["shareSplit"])
+	assert.NotContains(t, query, "shareNamespaces")
+
+	require.Equal(t, 1, result.Length())
+	as := result.Allocations[0]
+	assert.Equal(t, testStart, as.Start())
+	assert.Equal(t, testEnd, as.End())
+	require.Contains(t, as.Allocations, "kubecost")
+	assert.Equal(t, "kubecost", as.Allocations["kubecost"].Properties.Namespace)
+	assert.InDelta(t, alloc.TotalCost(), as.Allocations["kubecost"].TotalCost(), 1e-9)
+
+	summary, err := client.QueryAllocationSummary(ctx, AllocationSummaryQuery{Window: testWindow})
+	require.NoError(t, err)
+	require.Len(t, summary.SummaryAllocationSets, 1)
+	assert.Equal(t, len(sasr.SummaryAllocationSets[0].SummaryAllocations), len(summary.SummaryAllocationSets[0].SummaryAllocations))
+
+	assets, err := client.QueryAssets(ctx, AssetQuery{Window: testWindow})
+	require.NoError(t, err)
+	require.Equal(t, 1, assets.Length())
+	require.Len(t, assets.Nodes, 1)
+	for _, n := range assets.Nodes {
+		assert.Equal(t, 3.0, n.CPUCost)
+	}
+}
+
+func TestErrorsAndRetries(t *testing.T) {
+	var requests atomic.Int32
+	router := httprouter.New()
+	router.GET("/cloudCost", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		requests.Add(1)
+		proto.WriteError(w, proto.BadRequest("invalid aggregation"))
+	})
+	router.GET("/clusterInfo", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		if requests.Add(1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		proto.WriteData(w, map[string]string{"id": "cluster-one"})
+	})
+	router.GET("/clusterInfoMap", func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		requests.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	client := newTestClient(t, router)
+	ctx := context.Background()
+
+	// Request errors are returned without retrying
+	_, err := client.QueryCloudCosts(ctx, CloudCostQuery{Window: testWindow, Aggregate: []string{"invalid"}})
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Open windows are rejected before sending a request
+	now := time.Now()
+	_, err = client.QueryCloudCosts(ctx, CloudCostQuery{Window: opencost.NewWindow(&now, nil)})
+	assert.Error(t, err)
+
+	// Transient errors are retried
+	requests.Store(0)
+	info, err := client.ClusterInfo(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "cluster-one", info["id"])
+	assert.Equal(t, int32(3), requests.Load())
+
+	// Retries are limited
+	requests.Store(0)
+	_, err = client.ClusterInfoMap(ctx)
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "unavailable", apiErr.Message)
+	assert.Equal(t, int32(3), requests.Load())
+
+	assert.True(t, IsNotFound(func() error {
+		_, err := client.PricingSourceCounts(ctx)
+		return err
+	}()))
+}
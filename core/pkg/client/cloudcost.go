@@ -0,0 +1,220 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/filter"
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+// SortDirection orders the results of the cloud cost view and custom cost queries.
+type SortDirection string
+
+const (
+	SortDirectionNone       SortDirection = ""
+	SortDirectionAscending  SortDirection = "asc"
+	SortDirectionDescending SortDirection = "desc"
+)
+
+// CloudCostSortField is the field the cloud cost view queries sort by.
+type CloudCostSortField string
+
+const (
+	CloudCostSortFieldNone              CloudCostSortField = ""
+	CloudCostSortFieldName              CloudCostSortField = "name"
+	CloudCostSortFieldCost              CloudCostSortField = "cost"
+	CloudCostSortFieldKubernetesPercent CloudCostSortField = "kubernetesPercent"
+)
+
+// CloudCostViewGraphData is the response of /cloudCost/view/graph, with one data set
+// per step of the window.
+type CloudCostViewGraphData []*CloudCostViewGraphDataSet
+
+// CloudCostViewGraphDataSet holds the cost of each item over one step of the window.
+type CloudCostViewGraphDataSet struct {
+	Start time.Time                       `json:"start"`
+	End   time.Time                       `json:"end"`
+	Items []CloudCostViewGraphDataSetItem `json:"items"`
+}
+
+type CloudCostViewGraphDataSetItem struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// CloudCostViewTableRow is a row of the /cloudCost/view/table response, or the
+// combined totals of /cloudCost/view/totals.
+type CloudCostViewTableRow struct {
+	Name              string            `json:"name"`
+	Names             []string          `json:"names,omitempty"`
+	Token             string            `json:"token,omitempty"`
+	Labels            map[string]string `json:"labels"`
+	KubernetesPercent float64           `json:"kubernetesPercent"`
+	Cost              float64           `json:"cost"`
+}
+
+// CloudCostViewTotals is the response of /cloudCost/view/totals.
+type CloudCostViewTotals struct {
+	NumResults int                    `json:"numResults"`
+	Combined   *CloudCostViewTableRow `json:"combined"`
+}
+
+// CloudCostStatus gives the details and metadata of a cloud cost integration. The
+// configuration is specific to the integration, so it is left undecoded.
+type CloudCostStatus struct {
+	Key              string          `json:"key"`
+	Source           string          `json:"source"`
+	Provider         string          `json:"provider"`
+	Active           bool            `json:"active"`
+	Valid            bool            `json:"valid"`
+	LastRun          time.Time       `json:"lastRun"`
+	NextRun          time.Time       `json:"nextRun"`
+	RefreshRate      string          `json:"RefreshRate"`
+	Created          time.Time       `json:"created"`
+	Runs             int             `json:"runs"`
+	Coverage         string          `json:"coverage"`
+	ConnectionStatus string          `json:"connectionStatus"`
+	Config           json.RawMessage `json:"config"`
+}
+
+// CloudCostQuery holds the parameters of a /cloudCost query. Only Window is required.
+type CloudCostQuery struct {
+	Window     opencost.Window
+	Aggregate  []string
+	Accumulate opencost.AccumulateOption
+	Filter     filter.Filter
+}
+
+func (q CloudCostQuery) params() (url.Values, error) {
+	params := url.Values{}
+
+	window, err := windowParam(q.Window)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("window", window)
+
+	if len(q.Aggregate) > 0 {
+		params.Set("aggregate", strings.Join(q.Aggregate, ","))
+	}
+	if q.Accumulate != opencost.AccumulateOptionNone {
+		params.Set("accumulate", string(q.Accumulate))
+	}
+	if err := setFilter(params, q.Filter); err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// CloudCostViewQuery holds the parameters of the /cloudCost/view queries. Only Window
+// is required.
+type CloudCostViewQuery struct {
+	CloudCostQuery
+	CostMetric    opencost.CostMetricName
+	Limit         int
+	Offset        int
+	SortBy        CloudCostSortField
+	SortDirection SortDirection
+}
+
+func (q CloudCostViewQuery) params() (url.Values, error) {
+	params, err := q.CloudCostQuery.params()
+	if err != nil {
+		return nil, err
+	}
+
+	if q.CostMetric != "" {
+		params.Set("costMetric", string(q.CostMetric))
+	}
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Offset > 0 {
+		params.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.SortBy != CloudCostSortFieldNone {
+		params.Set("sortBy", string(q.SortBy))
+	}
+	if q.SortDirection != SortDirectionNone {
+		params.Set("sortByOrder", string(q.SortDirection))
+	}
+
+	return params, nil
+}
+
+// QueryCloudCosts queries /cloudCost.
+func (c *Client) QueryCloudCosts(ctx context.Context, query CloudCostQuery) (*opencost.CloudCostSetRange, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	ccsr := &opencost.CloudCostSetRange{}
+	if err := c.get(ctx, "/cloudCost", params, ccsr); err != nil {
+		return nil, err
+	}
+
+	return ccsr, nil
+}
+
+// QueryCloudCostViewGraph queries /cloudCost/view/graph.
+func (c *Client) QueryCloudCostViewGraph(ctx context.Context, query CloudCostViewQuery) (CloudCostViewGraphData, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	var data CloudCostViewGraphData
+	if err := c.get(ctx, "/cloudCost/view/graph", params, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// QueryCloudCostViewTotals queries /cloudCost/view/totals.
+func (c *Client) QueryCloudCostViewTotals(ctx context.Context, query CloudCostViewQuery) (*CloudCostViewTotals, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &CloudCostViewTotals{}
+	if err := c.get(ctx, "/cloudCost/view/totals", params, totals); err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// QueryCloudCostViewTable queries /cloudCost/view/table.
+func (c *Client) QueryCloudCostViewTable(ctx context.Context, query CloudCostViewQuery) ([]*CloudCostViewTableRow, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*CloudCostViewTableRow
+	if err := c.get(ctx, "/cloudCost/view/table", params, &rows); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// CloudCostStatus queries /cloudCost/status, returning the status of each cloud cost
+// integration.
+func (c *Client) CloudCostStatus(ctx context.Context) ([]CloudCostStatus, error) {
+	var status []CloudCostStatus
+	if err := c.get(ctx, "/cloudCost/status", nil, &status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
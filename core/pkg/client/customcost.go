@@ -0,0 +1,171 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/filter"
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+// CustomCostType selects which of the costs reported by custom cost plugins is used.
+type CustomCostType string
+
+const (
+	CustomCostTypeBlended CustomCostType = "blended"
+	CustomCostTypeList    CustomCostType = "list"
+	CustomCostTypeBilled  CustomCostType = "billed"
+)
+
+// CustomCostSortProperty is the property the custom cost queries sort by.
+type CustomCostSortProperty string
+
+const (
+	CustomCostSortPropertyCost      CustomCostSortProperty = "cost"
+	CustomCostSortPropertyAggregate CustomCostSortProperty = "aggregate"
+	CustomCostSortPropertyCostType  CustomCostSortProperty = "costType"
+)
+
+// CustomCostResponse is the response of /customCost/total, and each step of
+// /customCost/timeseries.
+type CustomCostResponse struct {
+	Window        opencost.Window `json:"window"`
+	TotalCost     float32         `json:"totalCost"`
+	TotalCostType CustomCostType  `json:"totalCostType"`
+	CustomCosts   []*CustomCost   `json:"customCosts"`
+}
+
+// CustomCost is a single, possibly aggregated, cost reported by a custom cost plugin.
+type CustomCost struct {
+	Id             string         `json:"id"`
+	Zone           string         `json:"zone"`
+	AccountName    string         `json:"account_name"`
+	ChargeCategory string         `json:"charge_category"`
+	Description    string         `json:"description"`
+	ResourceName   string         `json:"resource_name"`
+	ResourceType   string         `json:"resource_type"`
+	ProviderId     string         `json:"provider_id"`
+	Cost           float32        `json:"cost"`
+	ListUnitPrice  float32        `json:"list_unit_price"`
+	UsageQuantity  float32        `json:"usage_quantity"`
+	UsageUnit      string         `json:"usage_unit"`
+	Domain         string         `json:"domain"`
+	CostSource     string         `json:"cost_source"`
+	Aggregate      string         `json:"aggregate"`
+	CostType       CustomCostType `json:"cost_type"`
+
+	Labels             map[string]string `json:"labels,omitempty"`
+	ExtendedAttributes map[string]string `json:"extended_attributes,omitempty"`
+}
+
+// CustomCostTimeseriesResponse is the response of /customCost/timeseries.
+type CustomCostTimeseriesResponse struct {
+	Window     opencost.Window       `json:"window"`
+	Timeseries []*CustomCostResponse `json:"timeseries"`
+}
+
+// CustomCostStatus gives the details and metadata of the custom cost pipeline.
+type CustomCostStatus struct {
+	Enabled           bool                       `json:"enabled"`
+	Domains           []string                   `json:"domains"`
+	Key               string                     `json:"key,omitempty"`
+	Source            string                     `json:"source,omitempty"`
+	Provider          string                     `json:"provider,omitempty"`
+	Active            bool                       `json:"active,omitempty"`
+	Valid             bool                       `json:"valid,omitempty"`
+	LastRun           time.Time                  `json:"lastRun,omitempty"`
+	NextRun           time.Time                  `json:"nextRun,omitempty"`
+	RefreshRateDaily  string                     `json:"RefreshRateDaily,omitempty"`
+	RefreshRateHourly string                     `json:"RefreshRateHourly,omitempty"`
+	Created           time.Time                  `json:"created,omitempty"`
+	Runs              int                        `json:"runs,omitempty"`
+	CoverageHourly    map[string]opencost.Window `json:"coverageHourly,omitempty"`
+	CoverageDaily     map[string]opencost.Window `json:"coverageDaily,omitempty"`
+	ConnectionStatus  string                     `json:"connectionStatus,omitempty"`
+}
+
+// CustomCostQuery holds the parameters of the /customCost queries. Only Window is
+// required.
+type CustomCostQuery struct {
+	Window    opencost.Window
+	Aggregate []string
+	// Accumulate defaults to daily accumulation on the server.
+	Accumulate    opencost.AccumulateOption
+	Filter        filter.Filter
+	CostType      CustomCostType
+	SortBy        CustomCostSortProperty
+	SortDirection SortDirection
+}
+
+func (q CustomCostQuery) params() (url.Values, error) {
+	params := url.Values{}
+
+	window, err := windowParam(q.Window)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("window", window)
+
+	if len(q.Aggregate) > 0 {
+		params.Set("aggregate", strings.Join(q.Aggregate, ","))
+	}
+	if q.Accumulate != opencost.AccumulateOptionNone {
+		params.Set("accumulate", string(q.Accumulate))
+	}
+	if err := setFilter(params, q.Filter); err != nil {
+		return nil, err
+	}
+	if q.CostType != "" {
+		params.Set("costType", string(q.CostType))
+	}
+	if q.SortBy != "" {
+		params.Set("sortBy", string(q.SortBy))
+	}
+	if q.SortDirection != "" {
+		params.Set("sortDirection", string(q.SortDirection))
+	}
+
+	return params, nil
+}
+
+// QueryCustomCostTotal queries /customCost/total.
+func (c *Client) QueryCustomCostTotal(ctx context.Context, query CustomCostQuery) (*CustomCostResponse, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CustomCostResponse{}
+	if err := c.get(ctx, "/customCost/total", params, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// QueryCustomCostTimeseries queries /customCost/timeseries.
+func (c *Client) QueryCustomCostTimeseries(ctx context.Context, query CustomCostQuery) (*CustomCostTimeseriesResponse, error) {
+	params, err := query.params()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CustomCostTimeseriesResponse{}
+	if err := c.get(ctx, "/customCost/timeseries", params, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// CustomCostStatus queries /customCost/status.
+func (c *Client) CustomCostStatus(ctx context.Context) (*CustomCostStatus, error) {
+	status := &CustomCostStatus{}
+	if err := c.get(ctx, "/customCost/status", nil, status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
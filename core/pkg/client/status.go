@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/opencost/opencost/core/pkg/clusters"
+)
+
+// PricingSource is the status of one of the pricing sources of the cloud provider.
+type PricingSource struct {
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+	Available bool   `json:"available"`
+	Error     string `json:"error"`
+}
+
+// PricingMatchMetadata counts the nodes priced by each pricing type, e.g. "api" or
+// "spot".
+type PricingMatchMetadata struct {
+	TotalNodes        int            `json:"TotalNodes"`
+	PricingTypeCounts map[string]int `json:"PricingType"`
+}
+
+// ClusterInfo queries /clusterInfo, returning the properties of the cluster, e.g. its
+// id, provider and version.
+func (c *Client) ClusterInfo(ctx context.Context) (map[string]string, error) {
+	var info map[string]string
+	if err := c.get(ctx, "/clusterInfo", nil, &info); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// ClusterInfoMap queries /clusterInfoMap, returning each known cluster keyed by id.
+func (c *Client) ClusterInfoMap(ctx context.Context) (map[string]*clusters.ClusterInfo, error) {
+	var infos map[string]*clusters.ClusterInfo
+	if err := c.get(ctx, "/clusterInfoMap", nil, &infos); err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// PricingSourceStatus queries /pricingSourceStatus, returning the status of each
+// pricing source of the cloud provider keyed by name.
+func (c *Client) PricingSourceStatus(ctx context.Context) (map[string]*PricingSource, error) {
+	var status map[string]*PricingSource
+	if err := c.get(ctx, "/pricingSourceStatus", nil, &status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// PricingSourceCounts queries /pricingSourceCounts, returning the number of nodes
+// priced by each pricing type.
+func (c *Client) PricingSourceCounts(ctx context.Context) (*PricingMatchMetadata, error) {
+	counts := &PricingMatchMetadata{}
+	if err := c.get(ctx, "/pricingSourceCounts", nil, counts); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// PricingSourceSummary queries /pricingSourceSummary. The summary is specific to the
+// cloud provider, so it is returned undecoded.
+func (c *Client) PricingSourceSummary(ctx context.Context) (json.RawMessage, error) {
+	var summary json.RawMessage
+	if err := c.get(ctx, "/pricingSourceSummary", nil, &summary); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
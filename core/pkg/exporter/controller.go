@@ -79,7 +79,9 @@ func (cd *EventExportController[T]) Start(interval time.Duration) bool {
 				continue
 			}
 
+			spanCtx, span := startExportSpan(cd.typeName, t, t)
 			err := cd.exporter.Export(t, evt)
+			endExportSpan(spanCtx, span, cd.typeName, err)
 			if err != nil {
 				log.Warnf("[%s] Error during Write: %s", cd.typeName, err)
 			}
@@ -209,13 +211,18 @@ func (cd *ComputeExportController[T]) exportWindowsFor(now time.Time) []opencost
 }
 
 // export computes and exports the data for a given time window
-func (cd *ComputeExportController[T]) export(window opencost.Window) error {
+func (cd *ComputeExportController[T]) export(window opencost.Window) (err error) {
 	if window.IsOpen() {
 		return fmt.Errorf("window is open: %s", window.String())
 	}
 
 	start, end := *window.Start(), *window.End()
 
+	ctx, span := startExportSpan(cd.Name(), start, end)
+	defer func() {
+		endExportSpan(ctx, span, cd.Name(), err)
+	}()
+
 	log.Debugf("[%s] Reporting for window: %s - %s", cd.typeName, start.UTC(), end.UTC())
 
 	if !cd.source.CanCompute(start, end) {
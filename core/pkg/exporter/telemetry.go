@@ -0,0 +1,49 @@
+package exporter
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/opencost/opencost/core/pkg/exporter"
+
+// exportRuns is created from the global MeterProvider, which forwards it to the provider configured by the application
+var exportRuns, _ = otel.Meter(tracerName).Int64Counter(
+	"opencost.export.runs",
+	metric.WithUnit("{run}"),
+	metric.WithDescription("The number of export runs, by exporter and whether the run succeeded"),
+)
+
+// RecordExportRun counts a run of the named exporter, which succeeded if err is nil.
+func RecordExportRun(ctx context.Context, exporter string, err error) {
+	exportRuns.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("exporter", exporter),
+		attribute.Bool("success", err == nil),
+	))
+}
+
+// startExportSpan starts the span of an export run of the named exporter over the given window.
+func startExportSpan(name string, start, end time.Time) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(context.Background(), "export "+name, trace.WithAttributes(
+		attribute.String("exporter", name),
+		attribute.String("window.start", start.UTC().Format(time.RFC3339)),
+		attribute.String("window.end", end.UTC().Format(time.RFC3339)),
+	))
+}
+
+// endExportSpan ends the span of an export run, and counts the run.
+func endExportSpan(ctx context.Context, span trace.Span, name string, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+
+	RecordExportRun(ctx, name, err)
+}
@@ -0,0 +1,96 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToFilterString formats the provided tree as a string in the filter language accepted
+// by the parser, such that parsing the result yields an equivalent tree. This is the
+// inverse of FilterParser.Parse, and is useful for sending filters built in code to an
+// API which accepts filter strings. A nil or void tree produces an empty string.
+func ToFilterString(node FilterNode) (string, error) {
+	if node == nil || IsVoid(node) {
+		return "", nil
+	}
+
+	return formatNode(node)
+}
+
+func formatNode(node FilterNode) (string, error) {
+	switch n := node.(type) {
+	case *AndOp:
+		return formatGroup(n.Operands, "+")
+	case *OrOp:
+		return formatGroup(n.Operands, "|")
+	case *NotOp:
+		return formatComparison(n.Operand, true)
+	case *ContradictionOp:
+		return "", fmt.Errorf("contradictions cannot be expressed in the filter language")
+	case *VoidOp:
+		return "", fmt.Errorf("void operations are only supported as the root of a filter")
+	default:
+		return formatComparison(node, false)
+	}
+}
+
+// formatGroup joins the operands with the group operator. Nested groups are always
+// parenthesized, as the parser does not allow mixing group operators at one depth.
+func formatGroup(operands []FilterNode, op string) (string, error) {
+	if len(operands) == 0 {
+		return "", fmt.Errorf("group operations require at least one operand")
+	}
+
+	parts := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		s, err := formatNode(operand)
+		if err != nil {
+			return "", err
+		}
+
+		switch operand.(type) {
+		case *AndOp, *OrOp:
+			s = "(" + s + ")"
+		}
+		parts = append(parts, s)
+	}
+
+	return strings.Join(parts, op), nil
+}
+
+// formatComparison formats a leaf comparison. Negations are only supported directly
+// on comparisons, which the filter language expresses with the '!' operator prefix.
+func formatComparison(node FilterNode, negate bool) (string, error) {
+	var left Identifier
+	var right string
+	var op string
+
+	switch n := node.(type) {
+	case *EqualOp:
+		left, right, op = n.Left, n.Right, ":"
+	case *ContainsOp:
+		left, right, op = n.Left, n.Right, "~:"
+	case *ContainsPrefixOp:
+		left, right, op = n.Left, n.Right, "<~:"
+	case *ContainsSuffixOp:
+		left, right, op = n.Left, n.Right, "~>:"
+	default:
+		if negate {
+			return "", fmt.Errorf("negation of '%s' cannot be expressed in the filter language", node.Op())
+		}
+		return "", fmt.Errorf("unsupported filter operation: %s", node.Op())
+	}
+
+	if left.Field == nil {
+		return "", fmt.Errorf("%s operation has no field", node.Op())
+	}
+	if strings.Contains(right, `"`) {
+		return "", fmt.Errorf("value %q cannot be expressed in the filter language", right)
+	}
+
+	if negate {
+		op = "!" + op
+	}
+
+	return fmt.Sprintf(`%s%s"%s"`, left.String(), op, right), nil
+}
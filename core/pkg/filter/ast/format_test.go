@@ -0,0 +1,109 @@
+package ast
+
+import (
+	"testing"
+)
+
+var formatTestFields = []*Field{
+	NewField("namespace"),
+	NewField("cluster"),
+	NewSliceField("services"),
+	NewMapField("label"),
+}
+
+func TestToFilterStringRoundTrip(t *testing.T) {
+	parser := NewFilterParser(formatTestFields)
+
+	cases := map[string]string{
+		"equals":            `namespace:"kubecost"`,
+		"not equals":        `namespace!:"kubecost"`,
+		"keyed access":      `label[app]:"cost-analyzer"`,
+		"contains":          `namespace~:"cost"`,
+		"slice contains":    `services:"frontend"`,
+		"prefix and suffix": `namespace<~:"kube"+cluster!~>:"-dev"`,
+		"multiple values":   `namespace:"kubecost","default"`,
+		"negated values":    `namespace!:"kubecost","default"`,
+		"nested groups":     `cluster:"one"+(namespace:"a"|label[team]!:"b")`,
+	}
+
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			tree, err := parser.Parse(input)
+			if err != nil {
+				t.Fatalf("parsing %q: %s", input, err)
+			}
+
+			formatted, err := ToFilterString(tree)
+			if err != nil {
+				t.Fatalf("formatting %q: %s", input, err)
+			}
+
+			reparsed, err := parser.Parse(formatted)
+			if err != nil {
+				t.Fatalf("parsing formatted filter %q: %s", formatted, err)
+			}
+
+			if expected, actual := ToPreOrderString(tree), ToPreOrderString(reparsed); expected != actual {
+				t.Errorf("round trip of %q through %q changed the tree:\nexpected:\n%s\nactual:\n%s", input, formatted, expected, actual)
+			}
+		})
+	}
+}
+
+func TestToFilterString(t *testing.T) {
+	namespace := Identifier{Field: formatTestFields[0]}
+
+	cases := map[string]struct {
+		input    FilterNode
+		expected string
+		err      bool
+	}{
+		"nil": {
+			input: nil,
+		},
+		"void": {
+			input: &VoidOp{},
+		},
+		"negated equals": {
+			input:    &NotOp{Operand: &EqualOp{Left: namespace, Right: "kubecost"}},
+			expected: `namespace!:"kubecost"`,
+		},
+		"or": {
+			input: &OrOp{Operands: []FilterNode{
+				&ContainsPrefixOp{Left: namespace, Right: "kube"},
+				&ContainsSuffixOp{Left: namespace, Right: "system"},
+			}},
+			expected: `namespace<~:"kube"|namespace~>:"system"`,
+		},
+		"contradiction": {
+			input: &ContradictionOp{},
+			err:   true,
+		},
+		"negated group": {
+			input: &NotOp{Operand: &AndOp{Operands: []FilterNode{&EqualOp{Left: namespace, Right: "a"}}}},
+			err:   true,
+		},
+		"quoted value": {
+			input: &EqualOp{Left: namespace, Right: `a"b`},
+			err:   true,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ToFilterString(c.input)
+			if c.err {
+				if err == nil {
+					t.Fatalf("expected error, got %q", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,620 @@
+package kubemodel
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	"github.com/opencost/opencost/core/pkg/clusters"
+	"github.com/opencost/opencost/core/pkg/env"
+	modelpb "github.com/opencost/opencost/core/pkg/model/pb"
+	pb "github.com/opencost/opencost/core/pkg/model/pb/kubemodel"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/core/pkg/util"
+)
+
+// ErrInvalidWindow is returned for windows which no snapshot can be built for.
+var ErrInvalidWindow = errors.New("invalid snapshot window")
+
+// SnapshotBuilder builds kubemodel snapshots from the resources of a cluster
+// cache and the usage metrics of the snapshot window.
+type SnapshotBuilder struct {
+	clusterInfo clusters.ClusterInfoProvider
+	cache       clustercache.ClusterCache
+	metrics     source.MetricsQuerier
+}
+
+// NewSnapshotBuilder creates a new SnapshotBuilder.
+func NewSnapshotBuilder(clusterInfo clusters.ClusterInfoProvider, cache clustercache.ClusterCache, metrics source.MetricsQuerier) *SnapshotBuilder {
+	return &SnapshotBuilder{
+		clusterInfo: clusterInfo,
+		cache:       cache,
+		metrics:     metrics,
+	}
+}
+
+// ComputeSnapshot builds the snapshot of the window [start, end), which must
+// be 10 minutes, 1 hour or 1 day long. Resources are read from the cluster
+// cache, so only resources which currently exist are included.
+func (b *SnapshotBuilder) ComputeSnapshot(start, end time.Time) (*pb.Snapshot, error) {
+	resolution, err := resolutionFor(end.Sub(start))
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := b.queryUsage(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster := b.cluster(start, resolution)
+	sb := &snapshotBuilder{
+		snapshot:     &pb.Snapshot{Cluster: cluster},
+		clusterID:    cluster.ID,
+		end:          end,
+		hours:        end.Sub(start).Hours(),
+		usage:        usage,
+		nodeIDs:      map[string]string{},
+		namespaceIDs: map[string]string{},
+		volumeIDs:    map[string]string{},
+		gpuDevices:   map[string]*pb.GPUDevice{},
+	}
+
+	pods := b.cache.GetAllPods()
+
+	sb.addNodes(b.cache.GetAllNodes())
+	sb.addNamespaces(b.cache.GetAllNamespaces())
+	sb.addControllers(b.cache, pods)
+	sb.addPods(pods)
+	sb.addVolumes(b.cache.GetAllPersistentVolumes())
+	sb.addPersistentVolumeClaims(b.cache.GetAllPersistentVolumeClaims(), pods)
+	sb.addServices(b.cache.GetAllServices())
+
+	return sb.snapshot, nil
+}
+
+func (b *SnapshotBuilder) cluster(start time.Time, resolution modelpb.Resolution) *pb.Cluster {
+	var info map[string]string
+	if b.clusterInfo != nil {
+		info = b.clusterInfo.GetClusterInfo()
+	}
+
+	id := info[clusters.ClusterInfoIdKey]
+	if id == "" {
+		id = env.GetClusterID()
+	}
+
+	return &pb.Cluster{
+		ID:       id,
+		Provider: providerFor(info[clusters.ClusterInfoProviderKey]),
+		Account:  info[clusters.ClusterInfoAccountKey],
+		Name:     info[clusters.ClusterInfoNameKey],
+		Window: &modelpb.Window{
+			Resolution: resolution,
+			Start:      timestamppb.New(start),
+		},
+	}
+}
+
+// usageResults holds the usage metrics of the window, keyed by container or
+// pod key.
+type usageResults struct {
+	cpuRequests  map[containerKey][]*util.Vector
+	cpuUsageAvg  map[containerKey][]*util.Vector
+	cpuUsageMax  map[containerKey][]*util.Vector
+	ramRequests  map[containerKey][]*util.Vector
+	ramUsageAvg  map[containerKey][]*util.Vector
+	ramUsageMax  map[containerKey][]*util.Vector
+	gpuAllocated map[containerKey][]*util.Vector
+	gpuRequested map[containerKey][]*util.Vector
+	gpuUsageAvg  map[containerKey][]*util.Vector
+	gpuUsageMax  map[containerKey][]*util.Vector
+	gpuInfo      map[containerKey][]*source.GPUInfoResult
+	netTransfer  map[containerKey]float64
+	netReceive   map[containerKey]float64
+	gpuIsShared  map[containerKey]bool
+}
+
+type containerKey struct {
+	namespace string
+	pod       string
+	container string
+}
+
+func (b *SnapshotBuilder) queryUsage(start, end time.Time) (*usageResults, error) {
+	grp := source.NewQueryGroup()
+
+	resChCPURequests := source.WithGroup(grp, b.metrics.QueryCPURequests(start, end))
+	resChCPUUsageAvg := source.WithGroup(grp, b.metrics.QueryCPUUsageAvg(start, end))
+	resChCPUUsageMax := source.WithGroup(grp, b.metrics.QueryCPUUsageMax(start, end))
+	resChRAMRequests := source.WithGroup(grp, b.metrics.QueryRAMRequests(start, end))
+	resChRAMUsageAvg := source.WithGroup(grp, b.metrics.QueryRAMUsageAvg(start, end))
+	resChRAMUsageMax := source.WithGroup(grp, b.metrics.QueryRAMUsageMax(start, end))
+	resChGPUsAllocated := source.WithGroup(grp, b.metrics.QueryGPUsAllocated(start, end))
+	resChGPUsRequested := source.WithGroup(grp, b.metrics.QueryGPUsRequested(start, end))
+	resChGPUsUsageAvg := source.WithGroup(grp, b.metrics.QueryGPUsUsageAvg(start, end))
+	resChGPUsUsageMax := source.WithGroup(grp, b.metrics.QueryGPUsUsageMax(start, end))
+	resChGPUInfo := source.WithGroup(grp, b.metrics.QueryGPUInfo(start, end))
+	resChIsGPUShared := source.WithGroup(grp, b.metrics.QueryIsGPUShared(start, end))
+	resChNetTransferBytes := source.WithGroup(grp, b.metrics.QueryNetTransferBytes(start, end))
+	resChNetReceiveBytes := source.WithGroup(grp, b.metrics.QueryNetReceiveBytes(start, end))
+
+	resCPURequests, _ := resChCPURequests.Await()
+	resCPUUsageAvg, _ := resChCPUUsageAvg.Await()
+	resCPUUsageMax, _ := resChCPUUsageMax.Await()
+	resRAMRequests, _ := resChRAMRequests.Await()
+	resRAMUsageAvg, _ := resChRAMUsageAvg.Await()
+	resRAMUsageMax, _ := resChRAMUsageMax.Await()
+	resGPUsAllocated, _ := resChGPUsAllocated.Await()
+	resGPUsRequested, _ := resChGPUsRequested.Await()
+	resGPUsUsageAvg, _ := resChGPUsUsageAvg.Await()
+	resGPUsUsageMax, _ := resChGPUsUsageMax.Await()
+	resGPUInfo, _ := resChGPUInfo.Await()
+	resIsGPUShared, _ := resChIsGPUShared.Await()
+	resNetTransferBytes, _ := resChNetTransferBytes.Await()
+	resNetReceiveBytes, _ := resChNetReceiveBytes.Await()
+
+	if grp.HasErrors() {
+		return nil, grp.Error()
+	}
+
+	usage := &usageResults{
+		cpuRequests:  containerVectors(resCPURequests),
+		cpuUsageAvg:  containerVectors(resCPUUsageAvg),
+		cpuUsageMax:  containerVectors(resCPUUsageMax),
+		ramRequests:  containerVectors(resRAMRequests),
+		ramUsageAvg:  containerVectors(resRAMUsageAvg),
+		ramUsageMax:  containerVectors(resRAMUsageMax),
+		gpuAllocated: map[containerKey][]*util.Vector{},
+		gpuRequested: map[containerKey][]*util.Vector{},
+		gpuUsageAvg:  map[containerKey][]*util.Vector{},
+		gpuUsageMax:  map[containerKey][]*util.Vector{},
+		gpuInfo:      map[containerKey][]*source.GPUInfoResult{},
+		netTransfer:  map[containerKey]float64{},
+		netReceive:   map[containerKey]float64{},
+		gpuIsShared:  map[containerKey]bool{},
+	}
+
+	for _, res := range resGPUsAllocated {
+		usage.gpuAllocated[containerKey{res.Namespace, res.Pod, res.Container}] = res.Data
+	}
+	for _, res := range resGPUsRequested {
+		usage.gpuRequested[containerKey{res.Namespace, res.Pod, res.Container}] = res.Data
+	}
+	for _, res := range resGPUsUsageAvg {
+		usage.gpuUsageAvg[containerKey{res.Namespace, res.Pod, res.Container}] = res.Data
+	}
+	for _, res := range resGPUsUsageMax {
+		usage.gpuUsageMax[containerKey{res.Namespace, res.Pod, res.Container}] = res.Data
+	}
+	for _, res := range resGPUInfo {
+		key := containerKey{res.Namespace, res.Pod, res.Container}
+		usage.gpuInfo[key] = append(usage.gpuInfo[key], res)
+	}
+	for _, res := range resIsGPUShared {
+		usage.gpuIsShared[containerKey{res.Namespace, res.Pod, res.Container}] = true
+	}
+
+	// network usage is attributed to pods, so it is keyed without a container
+	for _, res := range resNetTransferBytes {
+		usage.netTransfer[containerKey{namespace: res.Namespace, pod: res.Pod}] += maxValue(res.Data)
+	}
+	for _, res := range resNetReceiveBytes {
+		usage.netReceive[containerKey{namespace: res.Namespace, pod: res.Pod}] += maxValue(res.Data)
+	}
+
+	return usage, nil
+}
+
+func containerVectors(results []*source.ContainerMetricResult) map[containerKey][]*util.Vector {
+	vectors := make(map[containerKey][]*util.Vector, len(results))
+	for _, res := range results {
+		vectors[containerKey{res.Namespace, res.Pod, res.Container}] = res.Data
+	}
+	return vectors
+}
+
+// snapshotBuilder holds the state of a single snapshot build.
+type snapshotBuilder struct {
+	snapshot  *pb.Snapshot
+	clusterID string
+	end       time.Time
+	hours     float64
+	usage     *usageResults
+
+	// IDs by name, or by namespace/name for namespaced resources
+	nodeIDs      map[string]string
+	namespaceIDs map[string]string
+	volumeIDs    map[string]string
+
+	// controller IDs by kind/namespace/name
+	controllerIDs map[string]string
+
+	// GPU devices by UUID
+	gpuDevices map[string]*pb.GPUDevice
+}
+
+func (sb *snapshotBuilder) addNodes(nodes []*clustercache.Node) {
+	for _, node := range nodes {
+		sb.nodeIDs[node.Name] = string(node.UID)
+		sb.snapshot.Nodes = append(sb.snapshot.Nodes, &pb.Node{
+			ID:                 string(node.UID),
+			ClusterID:          sb.clusterID,
+			ProviderResourceID: node.SpecProviderID,
+			Name:               node.Name,
+			Labels:             node.Labels,
+			Annotations:        node.Annotations,
+		})
+	}
+}
+
+func (sb *snapshotBuilder) addNamespaces(namespaces []*clustercache.Namespace) {
+	for _, ns := range namespaces {
+		sb.namespaceIDs[ns.Name] = string(ns.UID)
+		sb.snapshot.Namespaces = append(sb.snapshot.Namespaces, &pb.Namespace{
+			ID:          string(ns.UID),
+			ClusterID:   sb.clusterID,
+			Name:        ns.Name,
+			Labels:      ns.Labels,
+			Annotations: ns.Annotations,
+		})
+	}
+}
+
+func (sb *snapshotBuilder) addControllers(cache clustercache.ClusterCache, pods []*clustercache.Pod) {
+	// DaemonSets are cached without their UID, so it is taken from the owner
+	// references of their pods.
+	sb.controllerIDs = map[string]string{}
+	for _, pod := range pods {
+		if ref := clustercache.GetControllerOfNoCopy(pod); ref != nil {
+			sb.controllerIDs[controllerKey(ref.Kind, pod.Namespace, ref.Name)] = string(ref.UID)
+		}
+	}
+
+	add := func(kind pb.ControllerKind, kindName, namespace, name, uid string, labels, annotations map[string]string) {
+		key := controllerKey(kindName, namespace, name)
+		if uid == "" {
+			uid = sb.controllerIDs[key]
+		}
+		if uid == "" {
+			uid = key
+		}
+		sb.controllerIDs[key] = uid
+
+		sb.snapshot.Controllers = append(sb.snapshot.Controllers, &pb.Controller{
+			ID:          uid,
+			NamespaceID: sb.namespaceIDs[namespace],
+			Name:        name,
+			Kind:        kind,
+			Labels:      labels,
+			Annotations: annotations,
+		})
+	}
+
+	for _, d := range cache.GetAllDeployments() {
+		add(pb.ControllerKind_DEPLOYMENT, "Deployment", d.Namespace, d.Name, string(d.UID), d.Labels, d.Annotations)
+	}
+	for _, ss := range cache.GetAllStatefulSets() {
+		add(pb.ControllerKind_STATEFULSET, "StatefulSet", ss.Namespace, ss.Name, string(ss.UID), ss.Labels, ss.Annotations)
+	}
+	for _, ds := range cache.GetAllDaemonSets() {
+		add(pb.ControllerKind_DAEMONSET, "DaemonSet", ds.Namespace, ds.Name, "", ds.Labels, nil)
+	}
+	for _, job := range cache.GetAllJobs() {
+		add(pb.ControllerKind_JOB, "Job", job.Namespace, job.Name, string(job.UID), nil, nil)
+	}
+	for _, rs := range cache.GetAllReplicaSets() {
+		add(pb.ControllerKind_REPLICASET, "ReplicaSet", rs.Namespace, rs.Name, string(rs.UID), nil, nil)
+	}
+}
+
+func controllerKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func (sb *snapshotBuilder) addPods(pods []*clustercache.Pod) {
+	for _, pod := range pods {
+		podID := string(pod.UID)
+
+		p := &pb.Pod{
+			ID:           podID,
+			NamespaceID:  sb.namespaceIDs[pod.Namespace],
+			NodeID:       sb.nodeIDs[pod.Spec.NodeName],
+			Name:         pod.Name,
+			Labels:       pod.Labels,
+			Annotations:  pod.Annotations,
+			DeletionTime: sb.deletionTime(pod.DeletionTimestamp),
+		}
+		if ref := clustercache.GetControllerOfNoCopy(pod); ref != nil {
+			p.ControllerID = sb.controllerIDs[controllerKey(ref.Kind, pod.Namespace, ref.Name)]
+		}
+
+		// pod usage is the sum of its containers' usage, so the pod maximums
+		// are an upper bound of the actual maximums
+		for _, c := range pod.Spec.Containers {
+			container := sb.container(pod, podID, c.Name, p.DeletionTime)
+			sb.snapshot.Containers = append(sb.snapshot.Containers, container)
+
+			p.CpuCoreHours += container.CpuCoreHours
+			p.CpuCoreRequestAverage += container.CpuCoreRequestAverage
+			p.CpuCoreUsageAverage += container.CpuCoreUsageAverage
+			p.CpuCoreUsageMax += container.CpuCoreUsageMax
+			p.RamByteHours += container.RamByteHours
+			p.RamBytesRequestAverage += container.RamBytesRequestAverage
+			p.RamBytesUsageAverage += container.RamBytesUsageAverage
+			p.RamBytesUsageMax += container.RamBytesUsageMax
+		}
+
+		podKey := containerKey{namespace: pod.Namespace, pod: pod.Name}
+		p.NetworkTransferBytes = int64(sb.usage.netTransfer[podKey])
+		p.NetworkReceiveBytes = int64(sb.usage.netReceive[podKey])
+
+		sb.snapshot.Pods = append(sb.snapshot.Pods, p)
+	}
+
+	for _, device := range sb.gpuDevices {
+		sb.snapshot.GpuDevices = append(sb.snapshot.GpuDevices, device)
+	}
+	slices.SortFunc(sb.snapshot.GpuDevices, func(a, b *pb.GPUDevice) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+func (sb *snapshotBuilder) container(pod *clustercache.Pod, podID, name string, deletionTime *timestamppb.Timestamp) *pb.Container {
+	key := containerKey{pod.Namespace, pod.Name, name}
+	usage := sb.usage
+
+	cpuUsageAvg := meanValue(usage.cpuUsageAvg[key])
+	ramUsageAvg := meanValue(usage.ramUsageAvg[key])
+
+	container := &pb.Container{
+		PodID:                  podID,
+		Name:                   name,
+		DeletionTime:           deletionTime,
+		CpuCoreHours:           float32(cpuUsageAvg * sb.hours),
+		CpuCoreRequestAverage:  float32(meanValue(usage.cpuRequests[key])),
+		CpuCoreUsageAverage:    float32(cpuUsageAvg),
+		CpuCoreUsageMax:        float32(maxValue(usage.cpuUsageMax[key])),
+		RamByteHours:           int64(ramUsageAvg * sb.hours),
+		RamBytesRequestAverage: int64(meanValue(usage.ramRequests[key])),
+		RamBytesUsageAverage:   int64(ramUsageAvg),
+		RamBytesUsageMax:       int64(maxValue(usage.ramUsageMax[key])),
+	}
+
+	sb.addGPUUsage(key, containerID(podID, name), sb.nodeIDs[pod.Spec.NodeName])
+
+	return container
+}
+
+// containerID identifies a container, which has no UID of its own, by its pod
+// and name.
+func containerID(podID, name string) string {
+	return podID + "/" + name
+}
+
+// addGPUUsage adds the GPU usage of a container. A container allocated several
+// GPU devices is assumed to use them equally.
+func (sb *snapshotBuilder) addGPUUsage(key containerKey, containerID, nodeID string) {
+	usage := sb.usage
+
+	allocated := meanValue(usage.gpuAllocated[key])
+	if allocated == 0 {
+		return
+	}
+
+	requestPct := float32(meanValue(usage.gpuRequested[key]) * 100)
+	usageAvgPct := float32(meanValue(usage.gpuUsageAvg[key]) * 100)
+	usageMaxPct := float32(maxValue(usage.gpuUsageMax[key]) * 100)
+
+	infos := usage.gpuInfo[key]
+	if len(infos) == 0 {
+		sb.snapshot.GpuUsages = append(sb.snapshot.GpuUsages, &pb.GPUUsage{
+			ContainerID:          containerID,
+			GpuHours:             float32(allocated * sb.hours),
+			GpuRequestPercentage: requestPct,
+			GpuUsageAverage:      usageAvgPct,
+			GpuUsageMax:          usageMaxPct,
+		})
+		return
+	}
+
+	gpuHours := float32(allocated * sb.hours / float64(len(infos)))
+	for _, info := range infos {
+		device, ok := sb.gpuDevices[info.UUID]
+		if !ok {
+			device = &pb.GPUDevice{
+				ID:           info.UUID,
+				NodeID:       nodeID,
+				DeviceNumber: deviceNumber(info.Device),
+				ModelName:    info.ModelName,
+				GpuHours:     float32(sb.hours),
+			}
+			sb.gpuDevices[info.UUID] = device
+		}
+		if usage.gpuIsShared[key] {
+			device.IsShared = true
+		}
+		device.GpuRequestAverage += requestPct
+		device.GpuUsageAverage += usageAvgPct
+		device.GpuUsageMax = max(device.GpuUsageMax, usageMaxPct)
+
+		sb.snapshot.GpuUsages = append(sb.snapshot.GpuUsages, &pb.GPUUsage{
+			ContainerID:          containerID,
+			GpuDeviceID:          info.UUID,
+			GpuHours:             gpuHours,
+			GpuRequestPercentage: requestPct,
+			GpuUsageAverage:      usageAvgPct,
+			GpuUsageMax:          usageMaxPct,
+		})
+	}
+}
+
+// deviceNumber parses the number of a GPU device name, e.g. 0 for "nvidia0".
+func deviceNumber(device string) int32 {
+	n, err := strconv.ParseInt(strings.TrimLeft(device, "abcdefghijklmnopqrstuvwxyz"), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(n)
+}
+
+func (sb *snapshotBuilder) addVolumes(pvs []*clustercache.PersistentVolume) {
+	for _, pv := range pvs {
+		sb.volumeIDs[pv.Name] = string(pv.UID)
+
+		capacity := pv.Spec.Capacity[v1.ResourceStorage]
+		sb.snapshot.Volumes = append(sb.snapshot.Volumes, &pb.Volume{
+			ID:            string(pv.UID),
+			ClusterID:     sb.clusterID,
+			Name:          pv.Name,
+			StorageClass:  pv.Spec.StorageClassName,
+			Labels:        pv.Labels,
+			Annotations:   pv.Annotations,
+			CapacityBytes: capacity.Value(),
+		})
+	}
+}
+
+func (sb *snapshotBuilder) addPersistentVolumeClaims(pvcs []*clustercache.PersistentVolumeClaim, pods []*clustercache.Pod) {
+	// pods refer to claims by name within their namespace
+	podIDByClaim := map[string]string{}
+	podByID := map[string]*pb.Pod{}
+	for _, pod := range sb.snapshot.Pods {
+		podByID[pod.ID] = pod
+	}
+	for _, pod := range pods {
+		for _, vol := range pod.Spec.Volumes {
+			if vol.PersistentVolumeClaim != nil {
+				podIDByClaim[pod.Namespace+"/"+vol.PersistentVolumeClaim.ClaimName] = string(pod.UID)
+			}
+		}
+	}
+
+	for _, pvc := range pvcs {
+		requested := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+		claim := &pb.PersistentVolumeClaim{
+			ID:               string(pvc.UID),
+			NamespaceID:      sb.namespaceIDs[pvc.Namespace],
+			Name:             pvc.Name,
+			Labels:           pvc.Labels,
+			Annotations:      pvc.Annotations,
+			RequestedBytes:   requested.Value(),
+			StorageByteHours: int64(float64(requested.Value()) * sb.hours),
+		}
+		if pvc.Spec.StorageClassName != nil {
+			claim.StorageClass = *pvc.Spec.StorageClassName
+		}
+		if volumeID, ok := sb.volumeIDs[pvc.Spec.VolumeName]; ok {
+			claim.VolumeID = &volumeID
+		}
+		if podID, ok := podIDByClaim[pvc.Namespace+"/"+pvc.Name]; ok {
+			claim.PodID = &podID
+			if pod, ok := podByID[podID]; ok {
+				pod.StorageByteHours += claim.StorageByteHours
+			}
+		}
+
+		sb.snapshot.PersistentVolumeClaims = append(sb.snapshot.PersistentVolumeClaims, claim)
+	}
+}
+
+func (sb *snapshotBuilder) addServices(services []*clustercache.Service) {
+	for _, svc := range services {
+		sb.snapshot.Services = append(sb.snapshot.Services, &pb.Service{
+			ID:          string(svc.UID),
+			ClusterID:   sb.clusterID,
+			Name:        svc.Name,
+			ServiceType: string(svc.Type),
+		})
+	}
+}
+
+// deletionTime returns the deletion time of a resource if it falls within the
+// snapshot window.
+func (sb *snapshotBuilder) deletionTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || !t.Before(sb.end) {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// ValidateCurrentWindow returns an error wrapping ErrInvalidWindow unless the
+// window [start, end) has a supported duration and contains now. Snapshots are
+// built from the current state of the cluster, so only the window in progress
+// can be built on demand.
+func ValidateCurrentWindow(start, end, now time.Time) error {
+	if _, err := resolutionFor(end.Sub(start)); err != nil {
+		return err
+	}
+	if now.Before(start) || !now.Before(end) {
+		return fmt.Errorf("%w: %s does not contain the current time", ErrInvalidWindow, opencost.NewClosedWindow(start, end))
+	}
+	return nil
+}
+
+// resolutionFor returns the resolution of a window of the given duration.
+func resolutionFor(d time.Duration) (modelpb.Resolution, error) {
+	switch d {
+	case 10 * time.Minute:
+		return modelpb.Resolution_RESOLUTION_10M, nil
+	case time.Hour:
+		return modelpb.Resolution_RESOLUTION_1H, nil
+	case 24 * time.Hour:
+		return modelpb.Resolution_RESOLUTION_1D, nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported duration %s: must be 10m, 1h or 1d", ErrInvalidWindow, d)
+	}
+}
+
+// providerFor returns the kubemodel provider of a cluster info provider.
+func providerFor(provider string) pb.Provider {
+	switch opencost.ParseProvider(provider) {
+	case opencost.AWSProvider:
+		return pb.Provider_PROVIDER_AWS
+	case opencost.GCPProvider:
+		return pb.Provider_PROVIDER_GCP
+	case opencost.AzureProvider:
+		return pb.Provider_PROVIDER_AZURE
+	case opencost.OracleProvider:
+		return pb.Provider_PROVIDER_ORACLE
+	case opencost.DigitalOceanProvider:
+		return pb.Provider_PROVIDER_DIGITALOCEAN
+	}
+
+	switch strings.ToLower(provider) {
+	case "alibaba":
+		return pb.Provider_PROVIDER_ALIBABA
+	case "":
+		return pb.Provider_PROVIDER_UNSPECIFIED
+	default:
+		return pb.Provider_PROVIDER_ON_PREMISES
+	}
+}
+
+func meanValue(data []*util.Vector) float64 {
+	if len(data) == 0 {
+		return 0
+	}
+
+	var sum float64
+	for _, v := range data {
+		sum += v.Value
+	}
+	return sum / float64(len(data))
+}
+
+func maxValue(data []*util.Vector) float64 {
+	var m float64
+	for _, v := range data {
+		m = max(m, v.Value)
+	}
+	return m
+}
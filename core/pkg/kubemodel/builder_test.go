@@ -0,0 +1,350 @@
+package kubemodel
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	"github.com/opencost/opencost/core/pkg/clusters"
+	modelpb "github.com/opencost/opencost/core/pkg/model/pb"
+	pb "github.com/opencost/opencost/core/pkg/model/pb/kubemodel"
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/core/pkg/util"
+)
+
+type testClusterCache struct {
+	clustercache.ClusterCache
+
+	nodes       []*clustercache.Node
+	namespaces  []*clustercache.Namespace
+	pods        []*clustercache.Pod
+	deployments []*clustercache.Deployment
+	daemonSets  []*clustercache.DaemonSet
+	replicaSets []*clustercache.ReplicaSet
+	pvs         []*clustercache.PersistentVolume
+	pvcs        []*clustercache.PersistentVolumeClaim
+	services    []*clustercache.Service
+}
+
+func (c *testClusterCache) GetAllNodes() []*clustercache.Node           { return c.nodes }
+func (c *testClusterCache) GetAllNamespaces() []*clustercache.Namespace { return c.namespaces }
+func (c *testClusterCache) GetAllPods() []*clustercache.Pod             { return c.pods }
+func (c *testClusterCache) GetAllDeployments() []*clustercache.Deployment {
+	return c.deployments
+}
+func (c *testClusterCache) GetAllStatefulSets() []*clustercache.StatefulSet { return nil }
+func (c *testClusterCache) GetAllDaemonSets() []*clustercache.DaemonSet     { return c.daemonSets }
+func (c *testClusterCache) GetAllJobs() []*clustercache.Job                 { return nil }
+func (c *testClusterCache) GetAllReplicaSets() []*clustercache.ReplicaSet   { return c.replicaSets }
+func (c *testClusterCache) GetAllPersistentVolumes() []*clustercache.PersistentVolume {
+	return c.pvs
+}
+func (c *testClusterCache) GetAllPersistentVolumeClaims() []*clustercache.PersistentVolumeClaim {
+	return c.pvcs
+}
+func (c *testClusterCache) GetAllServices() []*clustercache.Service { return c.services }
+
+type testMetricsQuerier struct {
+	source.MetricsQuerier
+
+	containers map[string][]*source.ContainerMetricResult
+	gpuInfo    []*source.GPUInfoResult
+	gpus       []*source.GPUsAllocatedResult
+	gpuUsage   []*source.GPUsUsageAvgResult
+	netBytes   []*source.NetTransferBytesResult
+}
+
+func (q *testMetricsQuerier) container(name string) *source.Future[source.ContainerMetricResult] {
+	return source.NewFutureFrom(append([]*source.ContainerMetricResult{}, q.containers[name]...))
+}
+
+func (q *testMetricsQuerier) QueryCPURequests(_, _ time.Time) *source.Future[source.CPURequestsResult] {
+	return q.container("cpuRequests")
+}
+func (q *testMetricsQuerier) QueryCPUUsageAvg(_, _ time.Time) *source.Future[source.CPUUsageAvgResult] {
+	return q.container("cpuUsageAvg")
+}
+func (q *testMetricsQuerier) QueryCPUUsageMax(_, _ time.Time) *source.Future[source.CPUUsageMaxResult] {
+	return q.container("cpuUsageMax")
+}
+func (q *testMetricsQuerier) QueryRAMRequests(_, _ time.Time) *source.Future[source.RAMRequestsResult] {
+	return q.container("ramRequests")
+}
+func (q *testMetricsQuerier) QueryRAMUsageAvg(_, _ time.Time) *source.Future[source.RAMUsageAvgResult] {
+	return q.container("ramUsageAvg")
+}
+func (q *testMetricsQuerier) QueryRAMUsageMax(_, _ time.Time) *source.Future[source.RAMUsageMaxResult] {
+	return q.container("ramUsageMax")
+}
+func (q *testMetricsQuerier) QueryGPUsAllocated(_, _ time.Time) *source.Future[source.GPUsAllocatedResult] {
+	return source.NewFutureFrom(append([]*source.GPUsAllocatedResult{}, q.gpus...))
+}
+func (q *testMetricsQuerier) QueryGPUsRequested(_, _ time.Time) *source.Future[source.GPUsRequestedResult] {
+	return source.NewFutureFrom(append([]*source.GPUsRequestedResult{}, q.gpus...))
+}
+func (q *testMetricsQuerier) QueryGPUsUsageAvg(_, _ time.Time) *source.Future[source.GPUsUsageAvgResult] {
+	return source.NewFutureFrom(append([]*source.GPUsUsageAvgResult{}, q.gpuUsage...))
+}
+func (q *testMetricsQuerier) QueryGPUsUsageMax(_, _ time.Time) *source.Future[source.GPUsUsageMaxResult] {
+	return source.NewFutureFrom([]*source.GPUsUsageMaxResult{})
+}
+func (q *testMetricsQuerier) QueryGPUInfo(_, _ time.Time) *source.Future[source.GPUInfoResult] {
+	return source.NewFutureFrom(append([]*source.GPUInfoResult{}, q.gpuInfo...))
+}
+func (q *testMetricsQuerier) QueryIsGPUShared(_, _ time.Time) *source.Future[source.IsGPUSharedResult] {
+	return source.NewFutureFrom([]*source.IsGPUSharedResult{})
+}
+func (q *testMetricsQuerier) QueryNetTransferBytes(_, _ time.Time) *source.Future[source.NetTransferBytesResult] {
+	return source.NewFutureFrom(append([]*source.NetTransferBytesResult{}, q.netBytes...))
+}
+func (q *testMetricsQuerier) QueryNetReceiveBytes(_, _ time.Time) *source.Future[source.NetReceiveBytesResult] {
+	return source.NewFutureFrom([]*source.NetReceiveBytesResult{})
+}
+
+type testClusterInfo map[string]string
+
+func (info testClusterInfo) GetClusterInfo() map[string]string { return info }
+
+func vectors(values ...float64) []*util.Vector {
+	vs := make([]*util.Vector, 0, len(values))
+	for _, v := range values {
+		vs = append(vs, &util.Vector{Value: v})
+	}
+	return vs
+}
+
+func containerResult(pod, container string, values ...float64) []*source.ContainerMetricResult {
+	return []*source.ContainerMetricResult{{Namespace: "app", Pod: pod, Container: container, Data: vectors(values...)}}
+}
+
+func TestComputeSnapshot(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	deleted := start.Add(30 * time.Minute)
+
+	cache := &testClusterCache{
+		nodes: []*clustercache.Node{
+			{UID: "node-uid", Name: "node-1", SpecProviderID: "aws:///us-east-1a/i-1234", Labels: map[string]string{"zone": "a"}},
+		},
+		namespaces: []*clustercache.Namespace{
+			{UID: "ns-uid", Name: "app"},
+		},
+		deployments: []*clustercache.Deployment{
+			{UID: "deploy-uid", Name: "web", Namespace: "app"},
+		},
+		replicaSets: []*clustercache.ReplicaSet{
+			{UID: "rs-uid", Name: "web-abc", Namespace: "app"},
+		},
+		daemonSets: []*clustercache.DaemonSet{
+			{Name: "agent", Namespace: "app"},
+		},
+		pods: []*clustercache.Pod{
+			{
+				UID:       "web-uid",
+				Name:      "web-abc-1",
+				Namespace: "app",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: "web-abc", UID: "rs-uid", Controller: ptr(true)},
+				},
+				Spec: clustercache.PodSpec{
+					NodeName:   "node-1",
+					Containers: []clustercache.Container{{Name: "web"}, {Name: "sidecar"}},
+					Volumes: []v1.Volume{
+						{Name: "data", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}},
+					},
+				},
+			},
+			{
+				UID:       "agent-uid",
+				Name:      "agent-1",
+				Namespace: "app",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "DaemonSet", Name: "agent", UID: "ds-uid", Controller: ptr(true)},
+				},
+				DeletionTimestamp: &deleted,
+				Spec: clustercache.PodSpec{
+					NodeName:   "node-1",
+					Containers: []clustercache.Container{{Name: "agent"}},
+				},
+			},
+		},
+		pvs: []*clustercache.PersistentVolume{
+			{UID: "pv-uid", Name: "pv-1", Spec: v1.PersistentVolumeSpec{
+				StorageClassName: "gp3",
+				Capacity:         v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+			}},
+		},
+		pvcs: []*clustercache.PersistentVolumeClaim{
+			{UID: "pvc-uid", Name: "data", Namespace: "app", Spec: v1.PersistentVolumeClaimSpec{
+				VolumeName:       "pv-1",
+				StorageClassName: ptr("gp3"),
+				Resources: v1.VolumeResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("8Gi")},
+				},
+			}},
+		},
+		services: []*clustercache.Service{
+			{UID: "svc-uid", Name: "web", Namespace: "app", Type: v1.ServiceTypeLoadBalancer},
+		},
+	}
+
+	metrics := &testMetricsQuerier{
+		containers: map[string][]*source.ContainerMetricResult{
+			"cpuRequests": append(containerResult("web-abc-1", "web", 0.5, 1.5), containerResult("web-abc-1", "sidecar", 0.25)...),
+			"cpuUsageAvg": append(containerResult("web-abc-1", "web", 0.75), containerResult("web-abc-1", "sidecar", 0.25)...),
+			"cpuUsageMax": containerResult("web-abc-1", "web", 0.5, 2),
+			"ramRequests": containerResult("web-abc-1", "web", 1024),
+			"ramUsageAvg": containerResult("web-abc-1", "web", 512),
+			"ramUsageMax": containerResult("web-abc-1", "web", 2048),
+		},
+		gpus: []*source.GPUsAllocatedResult{
+			{Namespace: "app", Pod: "web-abc-1", Container: "web", Data: vectors(1)},
+		},
+		gpuUsage: []*source.GPUsUsageAvgResult{
+			{Namespace: "app", Pod: "web-abc-1", Container: "web", Data: vectors(0.5)},
+		},
+		gpuInfo: []*source.GPUInfoResult{
+			{Namespace: "app", Pod: "web-abc-1", Container: "web", Device: "nvidia1", ModelName: "Tesla T4", UUID: "GPU-1"},
+		},
+		netBytes: []*source.NetTransferBytesResult{
+			{Namespace: "app", Pod: "web-abc-1", Container: "web", Data: vectors(100)},
+		},
+	}
+
+	builder := NewSnapshotBuilder(testClusterInfo{
+		clusters.ClusterInfoIdKey:       "cluster-one",
+		clusters.ClusterInfoProviderKey: "AWS",
+		clusters.ClusterInfoAccountKey:  "123456789012",
+	}, cache, metrics)
+
+	snapshot, err := builder.ComputeSnapshot(start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	cluster := snapshot.GetCluster()
+	if cluster.GetID() != "cluster-one" || cluster.GetProvider() != pb.Provider_PROVIDER_AWS || cluster.GetAccount() != "123456789012" {
+		t.Errorf("unexpected cluster: %v", cluster)
+	}
+	if cluster.GetWindow().GetResolution() != modelpb.Resolution_RESOLUTION_1H || !cluster.GetWindow().GetStart().AsTime().Equal(start) {
+		t.Errorf("unexpected window: %v", cluster.GetWindow())
+	}
+
+	if len(snapshot.GetNodes()) != 1 || snapshot.GetNodes()[0].GetProviderResourceID() != "aws:///us-east-1a/i-1234" {
+		t.Errorf("unexpected nodes: %v", snapshot.GetNodes())
+	}
+
+	controllers := map[string]*pb.Controller{}
+	for _, c := range snapshot.GetControllers() {
+		controllers[c.GetName()] = c
+	}
+	if len(controllers) != 3 {
+		t.Fatalf("expected 3 controllers, got %d", len(controllers))
+	}
+	if c := controllers["agent"]; c.GetID() != "ds-uid" || c.GetKind() != pb.ControllerKind_DAEMONSET || c.GetNamespaceID() != "ns-uid" {
+		t.Errorf("unexpected daemonset controller: %v", c)
+	}
+
+	if len(snapshot.GetPods()) != 2 {
+		t.Fatalf("expected 2 pods, got %d", len(snapshot.GetPods()))
+	}
+	web, agent := snapshot.GetPods()[0], snapshot.GetPods()[1]
+	if web.GetControllerID() != "rs-uid" || web.GetNodeID() != "node-uid" || web.GetNamespaceID() != "ns-uid" {
+		t.Errorf("unexpected pod references: %v", web)
+	}
+	if web.GetCpuCoreRequestAverage() != 1.25 || web.GetCpuCoreUsageAverage() != 1 || web.GetCpuCoreHours() != 1 || web.GetCpuCoreUsageMax() != 2 {
+		t.Errorf("unexpected pod cpu usage: %v", web)
+	}
+	if web.GetRamBytesRequestAverage() != 1024 || web.GetRamBytesUsageAverage() != 512 || web.GetRamBytesUsageMax() != 2048 {
+		t.Errorf("unexpected pod ram usage: %v", web)
+	}
+	if web.GetNetworkTransferBytes() != 100 {
+		t.Errorf("expected 100 network transfer bytes, got %d", web.GetNetworkTransferBytes())
+	}
+	if web.GetStorageByteHours() != 8*1024*1024*1024 {
+		t.Errorf("expected 8GiB storage byte hours, got %d", web.GetStorageByteHours())
+	}
+	if web.DeletionTime != nil || !agent.GetDeletionTime().AsTime().Equal(deleted) {
+		t.Errorf("unexpected deletion times: %v, %v", web.DeletionTime, agent.DeletionTime)
+	}
+	if agent.GetControllerID() != "ds-uid" {
+		t.Errorf("expected agent controller ds-uid, got %s", agent.GetControllerID())
+	}
+
+	if len(snapshot.GetContainers()) != 3 || snapshot.GetContainers()[0].GetPodID() != "web-uid" {
+		t.Errorf("unexpected containers: %v", snapshot.GetContainers())
+	}
+
+	if len(snapshot.GetGpuDevices()) != 1 {
+		t.Fatalf("expected 1 gpu device, got %d", len(snapshot.GetGpuDevices()))
+	}
+	device := snapshot.GetGpuDevices()[0]
+	if device.GetID() != "GPU-1" || device.GetNodeID() != "node-uid" || device.GetDeviceNumber() != 1 || device.GetGpuUsageAverage() != 50 {
+		t.Errorf("unexpected gpu device: %v", device)
+	}
+	if len(snapshot.GetGpuUsages()) != 1 || snapshot.GetGpuUsages()[0].GetContainerID() != "web-uid/web" || snapshot.GetGpuUsages()[0].GetGpuHours() != 1 {
+		t.Errorf("unexpected gpu usages: %v", snapshot.GetGpuUsages())
+	}
+
+	if len(snapshot.GetVolumes()) != 1 || snapshot.GetVolumes()[0].GetCapacityBytes() != 10*1024*1024*1024 {
+		t.Errorf("unexpected volumes: %v", snapshot.GetVolumes())
+	}
+	if len(snapshot.GetPersistentVolumeClaims()) != 1 {
+		t.Fatalf("expected 1 pvc, got %d", len(snapshot.GetPersistentVolumeClaims()))
+	}
+	pvc := snapshot.GetPersistentVolumeClaims()[0]
+	if pvc.GetVolumeID() != "pv-uid" || pvc.GetPodID() != "web-uid" || pvc.GetStorageClass() != "gp3" {
+		t.Errorf("unexpected pvc: %v", pvc)
+	}
+
+	if len(snapshot.GetServices()) != 1 || snapshot.GetServices()[0].GetServiceType() != "LoadBalancer" {
+		t.Errorf("unexpected services: %v", snapshot.GetServices())
+	}
+}
+
+func TestComputeSnapshotUnsupportedWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	builder := NewSnapshotBuilder(nil, &testClusterCache{}, &testMetricsQuerier{})
+	if _, err := builder.ComputeSnapshot(start, start.Add(2*time.Hour)); !errors.Is(err, ErrInvalidWindow) {
+		t.Errorf("expected invalid window error for a 2h window, got %v", err)
+	}
+}
+
+func TestValidateCurrentWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(30 * time.Minute)
+
+	cases := map[string]struct {
+		start, end time.Time
+		valid      bool
+	}{
+		"current hour":     {start, start.Add(time.Hour), true},
+		"current day":      {start, start.Add(24 * time.Hour), true},
+		"unsupported":      {start, start.Add(2 * time.Hour), false},
+		"past window":      {start.Add(-time.Hour), start, false},
+		"future window":    {start.Add(time.Hour), start.Add(2 * time.Hour), false},
+		"ended 10m window": {start, start.Add(10 * time.Minute), false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateCurrentWindow(tc.start, tc.end, now)
+			if tc.valid && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !tc.valid && !errors.Is(err, ErrInvalidWindow) {
+				t.Errorf("expected invalid window error, got %v", err)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,12 @@
+package kubemodel
+
+// IsEmpty returns true if the snapshot contains no resources.
+func (s *Snapshot) IsEmpty() bool {
+	return s == nil ||
+		len(s.Nodes) == 0 &&
+			len(s.Namespaces) == 0 &&
+			len(s.Pods) == 0 &&
+			len(s.Volumes) == 0 &&
+			len(s.PersistentVolumeClaims) == 0 &&
+			len(s.Services) == 0
+}
@@ -0,0 +1,252 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v6.32.1
+// source: kubemodel/snapshot.proto
+
+package kubemodel
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Snapshot is the complete model of a cluster for the window of its Cluster.
+// Resources refer to each other by ID.
+type Snapshot struct {
+	state                  protoimpl.MessageState   `protogen:"open.v1"`
+	Cluster                *Cluster                 `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	Nodes                  []*Node                  `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Namespaces             []*Namespace             `protobuf:"bytes,3,rep,name=namespaces,proto3" json:"namespaces,omitempty"`
+	Controllers            []*Controller            `protobuf:"bytes,4,rep,name=controllers,proto3" json:"controllers,omitempty"`
+	Pods                   []*Pod                   `protobuf:"bytes,5,rep,name=pods,proto3" json:"pods,omitempty"`
+	Containers             []*Container             `protobuf:"bytes,6,rep,name=containers,proto3" json:"containers,omitempty"`
+	Volumes                []*Volume                `protobuf:"bytes,7,rep,name=volumes,proto3" json:"volumes,omitempty"`
+	PersistentVolumeClaims []*PersistentVolumeClaim `protobuf:"bytes,8,rep,name=persistentVolumeClaims,proto3" json:"persistentVolumeClaims,omitempty"`
+	GpuDevices             []*GPUDevice             `protobuf:"bytes,9,rep,name=gpuDevices,proto3" json:"gpuDevices,omitempty"`
+	GpuUsages              []*GPUUsage              `protobuf:"bytes,10,rep,name=gpuUsages,proto3" json:"gpuUsages,omitempty"`
+	Services               []*Service               `protobuf:"bytes,11,rep,name=services,proto3" json:"services,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_kubemodel_snapshot_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_kubemodel_snapshot_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_kubemodel_snapshot_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Snapshot) GetCluster() *Cluster {
+	if x != nil {
+		return x.Cluster
+	}
+	return nil
+}
+
+func (x *Snapshot) GetNodes() []*Node {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *Snapshot) GetNamespaces() []*Namespace {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *Snapshot) GetControllers() []*Controller {
+	if x != nil {
+		return x.Controllers
+	}
+	return nil
+}
+
+func (x *Snapshot) GetPods() []*Pod {
+	if x != nil {
+		return x.Pods
+	}
+	return nil
+}
+
+func (x *Snapshot) GetContainers() []*Container {
+	if x != nil {
+		return x.Containers
+	}
+	return nil
+}
+
+func (x *Snapshot) GetVolumes() []*Volume {
+	if x != nil {
+		return x.Volumes
+	}
+	return nil
+}
+
+func (x *Snapshot) GetPersistentVolumeClaims() []*PersistentVolumeClaim {
+	if x != nil {
+		return x.PersistentVolumeClaims
+	}
+	return nil
+}
+
+func (x *Snapshot) GetGpuDevices() []*GPUDevice {
+	if x != nil {
+		return x.GpuDevices
+	}
+	return nil
+}
+
+func (x *Snapshot) GetGpuUsages() []*GPUUsage {
+	if x != nil {
+		return x.GpuUsages
+	}
+	return nil
+}
+
+func (x *Snapshot) GetServices() []*Service {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+var File_kubemodel_snapshot_proto protoreflect.FileDescriptor
+
+const file_kubemodel_snapshot_proto_rawDesc = "" +
+	"\n" +
+	"\x18kubemodel/snapshot.proto\x12\tkubemodel\x1a\x17kubemodel/cluster.proto\x1a\x19kubemodel/container.proto\x1a\x1akubemodel/controller.proto\x1a\x13kubemodel/gpu.proto\x1a\x19kubemodel/namespace.proto\x1a\x17kubemodel/network.proto\x1a\x14kubemodel/node.proto\x1a\x13kubemodel/pod.proto\x1a\x17kubemodel/storage.proto\"\xc8\x04\n" +
+	"\bSnapshot\x12,\n" +
+	"\acluster\x18\x01 \x01(\v2\x12.kubemodel.ClusterR\acluster\x12%\n" +
+	"\x05nodes\x18\x02 \x03(\v2\x0f.kubemodel.NodeR\x05nodes\x124\n" +
+	"\n" +
+	"namespaces\x18\x03 \x03(\v2\x14.kubemodel.NamespaceR\n" +
+	"namespaces\x127\n" +
+	"\vcontrollers\x18\x04 \x03(\v2\x15.kubemodel.ControllerR\vcontrollers\x12\"\n" +
+	"\x04pods\x18\x05 \x03(\v2\x0e.kubemodel.PodR\x04pods\x124\n" +
+	"\n" +
+	"containers\x18\x06 \x03(\v2\x14.kubemodel.ContainerR\n" +
+	"containers\x12+\n" +
+	"\avolumes\x18\a \x03(\v2\x11.kubemodel.VolumeR\avolumes\x12X\n" +
+	"\x16persistentVolumeClaims\x18\b \x03(\v2 .kubemodel.PersistentVolumeClaimR\x16persistentVolumeClaims\x124\n" +
+	"\n" +
+	"gpuDevices\x18\t \x03(\v2\x14.kubemodel.GPUDeviceR\n" +
+	"gpuDevices\x121\n" +
+	"\tgpuUsages\x18\n" +
+	" \x03(\v2\x13.kubemodel.GPUUsageR\tgpuUsages\x12.\n" +
+	"\bservices\x18\v \x03(\v2\x12.kubemodel.ServiceR\bservicesB:Z8github.com/opencost/opencost/core/pkg/model/pb/kubemodelb\x06proto3"
+
+var (
+	file_kubemodel_snapshot_proto_rawDescOnce sync.Once
+	file_kubemodel_snapshot_proto_rawDescData []byte
+)
+
+func file_kubemodel_snapshot_proto_rawDescGZIP() []byte {
+	file_kubemodel_snapshot_proto_rawDescOnce.Do(func() {
+		file_kubemodel_snapshot_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_kubemodel_snapshot_proto_rawDesc), len(file_kubemodel_snapshot_proto_rawDesc)))
+	})
+	return file_kubemodel_snapshot_proto_rawDescData
+}
+
+var file_kubemodel_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_kubemodel_snapshot_proto_goTypes = []any{
+	(*Snapshot)(nil),              // 0: kubemodel.Snapshot
+	(*Cluster)(nil),               // 1: kubemodel.Cluster
+	(*Node)(nil),                  // 2: kubemodel.Node
+	(*Namespace)(nil),             // 3: kubemodel.Namespace
+	(*Controller)(nil),            // 4: kubemodel.Controller
+	(*Pod)(nil),                   // 5: kubemodel.Pod
+	(*Container)(nil),             // 6: kubemodel.Container
+	(*Volume)(nil),                // 7: kubemodel.Volume
+	(*PersistentVolumeClaim)(nil), // 8: kubemodel.PersistentVolumeClaim
+	(*GPUDevice)(nil),             // 9: kubemodel.GPUDevice
+	(*GPUUsage)(nil),              // 10: kubemodel.GPUUsage
+	(*Service)(nil),               // 11: kubemodel.Service
+}
+var file_kubemodel_snapshot_proto_depIdxs = []int32{
+	1,  // 0: kubemodel.Snapshot.cluster:type_name -> kubemodel.Cluster
+	2,  // 1: kubemodel.Snapshot.nodes:type_name -> kubemodel.Node
+	3,  // 2: kubemodel.Snapshot.namespaces:type_name -> kubemodel.Namespace
+	4,  // 3: kubemodel.Snapshot.controllers:type_name -> kubemodel.Controller
+	5,  // 4: kubemodel.Snapshot.pods:type_name -> kubemodel.Pod
+	6,  // 5: kubemodel.Snapshot.containers:type_name -> kubemodel.Container
+	7,  // 6: kubemodel.Snapshot.volumes:type_name -> kubemodel.Volume
+	8,  // 7: kubemodel.Snapshot.persistentVolumeClaims:type_name -> kubemodel.PersistentVolumeClaim
+	9,  // 8: kubemodel.Snapshot.gpuDevices:type_name -> kubemodel.GPUDevice
+	10, // 9: kubemodel.Snapshot.gpuUsages:type_name -> kubemodel.GPUUsage
+	11, // 10: kubemodel.Snapshot.services:type_name -> kubemodel.Service
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_kubemodel_snapshot_proto_init() }
+func file_kubemodel_snapshot_proto_init() {
+	if File_kubemodel_snapshot_proto != nil {
+		return
+	}
+	file_kubemodel_cluster_proto_init()
+	file_kubemodel_container_proto_init()
+	file_kubemodel_controller_proto_init()
+	file_kubemodel_gpu_proto_init()
+	file_kubemodel_namespace_proto_init()
+	file_kubemodel_network_proto_init()
+	file_kubemodel_node_proto_init()
+	file_kubemodel_pod_proto_init()
+	file_kubemodel_storage_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_kubemodel_snapshot_proto_rawDesc), len(file_kubemodel_snapshot_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_kubemodel_snapshot_proto_goTypes,
+		DependencyIndexes: file_kubemodel_snapshot_proto_depIdxs,
+		MessageInfos:      file_kubemodel_snapshot_proto_msgTypes,
+	}.Build()
+	File_kubemodel_snapshot_proto = out.File
+	file_kubemodel_snapshot_proto_goTypes = nil
+	file_kubemodel_snapshot_proto_depIdxs = nil
+}
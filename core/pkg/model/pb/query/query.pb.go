@@ -0,0 +1,2550 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v6.32.1
+// source: query/query.proto
+
+package query
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AllocationRequest mirrors the parameters of the /allocation HTTP API.
+type AllocationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the window to query, e.g. "7d" or "2024-01-01T00:00:00Z,2024-01-02T00:00:00Z"
+	Window string `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	// the duration of each AllocationSet, e.g. "1h". Defaults to the window.
+	Step string `protobuf:"bytes,2,opt,name=step,proto3" json:"step,omitempty"`
+	// properties to aggregate by, e.g. "namespace" or "label:app"
+	Aggregate []string `protobuf:"bytes,3,rep,name=aggregate,proto3" json:"aggregate,omitempty"`
+	// allocation filter expression
+	Filter string `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	// accumulate all steps into a single set
+	Accumulate bool `protobuf:"varint,5,opt,name=accumulate,proto3" json:"accumulate,omitempty"`
+	// accumulate steps by a resolution, e.g. "day" or "week"
+	AccumulateBy                          string `protobuf:"bytes,6,opt,name=accumulate_by,json=accumulateBy,proto3" json:"accumulate_by,omitempty"`
+	IncludeIdle                           bool   `protobuf:"varint,7,opt,name=include_idle,json=includeIdle,proto3" json:"include_idle,omitempty"`
+	IdleByNode                            bool   `protobuf:"varint,8,opt,name=idle_by_node,json=idleByNode,proto3" json:"idle_by_node,omitempty"`
+	ShareIdle                             bool   `protobuf:"varint,9,opt,name=share_idle,json=shareIdle,proto3" json:"share_idle,omitempty"`
+	ShareLb                               bool   `protobuf:"varint,10,opt,name=share_lb,json=shareLb,proto3" json:"share_lb,omitempty"`
+	IncludeProportionalAssetResourceCosts bool   `protobuf:"varint,11,opt,name=include_proportional_asset_resource_costs,json=includeProportionalAssetResourceCosts,proto3" json:"include_proportional_asset_resource_costs,omitempty"`
+	IncludeAggregatedMetadata             bool   `protobuf:"varint,12,opt,name=include_aggregated_metadata,json=includeAggregatedMetadata,proto3" json:"include_aggregated_metadata,omitempty"`
+	IncludeCustomCosts                    bool   `protobuf:"varint,13,opt,name=include_custom_costs,json=includeCustomCosts,proto3" json:"include_custom_costs,omitempty"`
+	// break down the costs shared with each allocation by sharing rule
+	IncludeSharedCostBreakdown bool `protobuf:"varint,14,opt,name=include_shared_cost_breakdown,json=includeSharedCostBreakdown,proto3" json:"include_shared_cost_breakdown,omitempty"`
+	// namespaces whose costs are shared with the other allocations
+	ShareNamespaces []string `protobuf:"bytes,15,rep,name=share_namespaces,json=shareNamespaces,proto3" json:"share_namespaces,omitempty"`
+	// labels, as "key:value" pairs, of the allocations whose costs are shared
+	ShareLabels []string `protobuf:"bytes,16,rep,name=share_labels,json=shareLabels,proto3" json:"share_labels,omitempty"`
+	// how shared costs are split, either "weighted" (the default) or "even"
+	ShareSplit string `protobuf:"bytes,17,opt,name=share_split,json=shareSplit,proto3" json:"share_split,omitempty"`
+	// share the cluster management costs with the allocations
+	ShareTenancyCosts bool `protobuf:"varint,18,opt,name=share_tenancy_costs,json=shareTenancyCosts,proto3" json:"share_tenancy_costs,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AllocationRequest) Reset() {
+	*x = AllocationRequest{}
+	mi := &file_query_query_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocationRequest) ProtoMessage() {}
+
+func (x *AllocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocationRequest.ProtoReflect.Descriptor instead.
+func (*AllocationRequest) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AllocationRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *AllocationRequest) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *AllocationRequest) GetAggregate() []string {
+	if x != nil {
+		return x.Aggregate
+	}
+	return nil
+}
+
+func (x *AllocationRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *AllocationRequest) GetAccumulate() bool {
+	if x != nil {
+		return x.Accumulate
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetAccumulateBy() string {
+	if x != nil {
+		return x.AccumulateBy
+	}
+	return ""
+}
+
+func (x *AllocationRequest) GetIncludeIdle() bool {
+	if x != nil {
+		return x.IncludeIdle
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetIdleByNode() bool {
+	if x != nil {
+		return x.IdleByNode
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetShareIdle() bool {
+	if x != nil {
+		return x.ShareIdle
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetShareLb() bool {
+	if x != nil {
+		return x.ShareLb
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetIncludeProportionalAssetResourceCosts() bool {
+	if x != nil {
+		return x.IncludeProportionalAssetResourceCosts
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetIncludeAggregatedMetadata() bool {
+	if x != nil {
+		return x.IncludeAggregatedMetadata
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetIncludeCustomCosts() bool {
+	if x != nil {
+		return x.IncludeCustomCosts
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetIncludeSharedCostBreakdown() bool {
+	if x != nil {
+		return x.IncludeSharedCostBreakdown
+	}
+	return false
+}
+
+func (x *AllocationRequest) GetShareNamespaces() []string {
+	if x != nil {
+		return x.ShareNamespaces
+	}
+	return nil
+}
+
+func (x *AllocationRequest) GetShareLabels() []string {
+	if x != nil {
+		return x.ShareLabels
+	}
+	return nil
+}
+
+func (x *AllocationRequest) GetShareSplit() string {
+	if x != nil {
+		return x.ShareSplit
+	}
+	return ""
+}
+
+func (x *AllocationRequest) GetShareTenancyCosts() bool {
+	if x != nil {
+		return x.ShareTenancyCosts
+	}
+	return false
+}
+
+type AllocationSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Allocations   []*Allocation          `protobuf:"bytes,3,rep,name=allocations,proto3" json:"allocations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AllocationSet) Reset() {
+	*x = AllocationSet{}
+	mi := &file_query_query_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllocationSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocationSet) ProtoMessage() {}
+
+func (x *AllocationSet) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocationSet.ProtoReflect.Descriptor instead.
+func (*AllocationSet) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AllocationSet) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *AllocationSet) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *AllocationSet) GetAllocations() []*Allocation {
+	if x != nil {
+		return x.Allocations
+	}
+	return nil
+}
+
+type AllocationProperties struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Cluster              string                 `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	Node                 string                 `protobuf:"bytes,2,opt,name=node,proto3" json:"node,omitempty"`
+	Container            string                 `protobuf:"bytes,3,opt,name=container,proto3" json:"container,omitempty"`
+	Controller           string                 `protobuf:"bytes,4,opt,name=controller,proto3" json:"controller,omitempty"`
+	ControllerKind       string                 `protobuf:"bytes,5,opt,name=controller_kind,json=controllerKind,proto3" json:"controller_kind,omitempty"`
+	Namespace            string                 `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Pod                  string                 `protobuf:"bytes,7,opt,name=pod,proto3" json:"pod,omitempty"`
+	Services             []string               `protobuf:"bytes,8,rep,name=services,proto3" json:"services,omitempty"`
+	ProviderId           string                 `protobuf:"bytes,9,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	Labels               map[string]string      `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Annotations          map[string]string      `protobuf:"bytes,11,rep,name=annotations,proto3" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	NamespaceLabels      map[string]string      `protobuf:"bytes,12,rep,name=namespace_labels,json=namespaceLabels,proto3" json:"namespace_labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	NamespaceAnnotations map[string]string      `protobuf:"bytes,13,rep,name=namespace_annotations,json=namespaceAnnotations,proto3" json:"namespace_annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *AllocationProperties) Reset() {
+	*x = AllocationProperties{}
+	mi := &file_query_query_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllocationProperties) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllocationProperties) ProtoMessage() {}
+
+func (x *AllocationProperties) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllocationProperties.ProtoReflect.Descriptor instead.
+func (*AllocationProperties) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AllocationProperties) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetNode() string {
+	if x != nil {
+		return x.Node
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetContainer() string {
+	if x != nil {
+		return x.Container
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetController() string {
+	if x != nil {
+		return x.Controller
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetControllerKind() string {
+	if x != nil {
+		return x.ControllerKind
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetPod() string {
+	if x != nil {
+		return x.Pod
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *AllocationProperties) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *AllocationProperties) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *AllocationProperties) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *AllocationProperties) GetNamespaceLabels() map[string]string {
+	if x != nil {
+		return x.NamespaceLabels
+	}
+	return nil
+}
+
+func (x *AllocationProperties) GetNamespaceAnnotations() map[string]string {
+	if x != nil {
+		return x.NamespaceAnnotations
+	}
+	return nil
+}
+
+type Allocation struct {
+	state                      protoimpl.MessageState `protogen:"open.v1"`
+	Name                       string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Properties                 *AllocationProperties  `protobuf:"bytes,2,opt,name=properties,proto3" json:"properties,omitempty"`
+	Start                      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End                        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	CpuCoreHours               float64                `protobuf:"fixed64,5,opt,name=cpu_core_hours,json=cpuCoreHours,proto3" json:"cpu_core_hours,omitempty"`
+	CpuCoreRequestAverage      float64                `protobuf:"fixed64,6,opt,name=cpu_core_request_average,json=cpuCoreRequestAverage,proto3" json:"cpu_core_request_average,omitempty"`
+	CpuCoreUsageAverage        float64                `protobuf:"fixed64,7,opt,name=cpu_core_usage_average,json=cpuCoreUsageAverage,proto3" json:"cpu_core_usage_average,omitempty"`
+	CpuCost                    float64                `protobuf:"fixed64,8,opt,name=cpu_cost,json=cpuCost,proto3" json:"cpu_cost,omitempty"`
+	CpuCostAdjustment          float64                `protobuf:"fixed64,9,opt,name=cpu_cost_adjustment,json=cpuCostAdjustment,proto3" json:"cpu_cost_adjustment,omitempty"`
+	GpuHours                   float64                `protobuf:"fixed64,10,opt,name=gpu_hours,json=gpuHours,proto3" json:"gpu_hours,omitempty"`
+	GpuCost                    float64                `protobuf:"fixed64,11,opt,name=gpu_cost,json=gpuCost,proto3" json:"gpu_cost,omitempty"`
+	GpuCostAdjustment          float64                `protobuf:"fixed64,12,opt,name=gpu_cost_adjustment,json=gpuCostAdjustment,proto3" json:"gpu_cost_adjustment,omitempty"`
+	NetworkTransferBytes       float64                `protobuf:"fixed64,13,opt,name=network_transfer_bytes,json=networkTransferBytes,proto3" json:"network_transfer_bytes,omitempty"`
+	NetworkReceiveBytes        float64                `protobuf:"fixed64,14,opt,name=network_receive_bytes,json=networkReceiveBytes,proto3" json:"network_receive_bytes,omitempty"`
+	NetworkCost                float64                `protobuf:"fixed64,15,opt,name=network_cost,json=networkCost,proto3" json:"network_cost,omitempty"`
+	NetworkCostAdjustment      float64                `protobuf:"fixed64,16,opt,name=network_cost_adjustment,json=networkCostAdjustment,proto3" json:"network_cost_adjustment,omitempty"`
+	LoadBalancerCost           float64                `protobuf:"fixed64,17,opt,name=load_balancer_cost,json=loadBalancerCost,proto3" json:"load_balancer_cost,omitempty"`
+	LoadBalancerCostAdjustment float64                `protobuf:"fixed64,18,opt,name=load_balancer_cost_adjustment,json=loadBalancerCostAdjustment,proto3" json:"load_balancer_cost_adjustment,omitempty"`
+	PvByteHours                float64                `protobuf:"fixed64,19,opt,name=pv_byte_hours,json=pvByteHours,proto3" json:"pv_byte_hours,omitempty"`
+	PvCost                     float64                `protobuf:"fixed64,20,opt,name=pv_cost,json=pvCost,proto3" json:"pv_cost,omitempty"`
+	PvCostAdjustment           float64                `protobuf:"fixed64,21,opt,name=pv_cost_adjustment,json=pvCostAdjustment,proto3" json:"pv_cost_adjustment,omitempty"`
+	RamByteHours               float64                `protobuf:"fixed64,22,opt,name=ram_byte_hours,json=ramByteHours,proto3" json:"ram_byte_hours,omitempty"`
+	RamBytesRequestAverage     float64                `protobuf:"fixed64,23,opt,name=ram_bytes_request_average,json=ramBytesRequestAverage,proto3" json:"ram_bytes_request_average,omitempty"`
+	RamBytesUsageAverage       float64                `protobuf:"fixed64,24,opt,name=ram_bytes_usage_average,json=ramBytesUsageAverage,proto3" json:"ram_bytes_usage_average,omitempty"`
+	RamCost                    float64                `protobuf:"fixed64,25,opt,name=ram_cost,json=ramCost,proto3" json:"ram_cost,omitempty"`
+	RamCostAdjustment          float64                `protobuf:"fixed64,26,opt,name=ram_cost_adjustment,json=ramCostAdjustment,proto3" json:"ram_cost_adjustment,omitempty"`
+	SharedCost                 float64                `protobuf:"fixed64,27,opt,name=shared_cost,json=sharedCost,proto3" json:"shared_cost,omitempty"`
+	ExternalCost               float64                `protobuf:"fixed64,28,opt,name=external_cost,json=externalCost,proto3" json:"external_cost,omitempty"`
+	TotalCost                  float64                `protobuf:"fixed64,29,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	// the costs shared with the allocation, by sharing rule, when requested
+	SharedCostBreakdown map[string]*SharedCostBreakdown `protobuf:"bytes,30,rep,name=shared_cost_breakdown,json=sharedCostBreakdown,proto3" json:"shared_cost_breakdown,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Allocation) Reset() {
+	*x = Allocation{}
+	mi := &file_query_query_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Allocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Allocation) ProtoMessage() {}
+
+func (x *Allocation) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Allocation.ProtoReflect.Descriptor instead.
+func (*Allocation) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Allocation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Allocation) GetProperties() *AllocationProperties {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *Allocation) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Allocation) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Allocation) GetCpuCoreHours() float64 {
+	if x != nil {
+		return x.CpuCoreHours
+	}
+	return 0
+}
+
+func (x *Allocation) GetCpuCoreRequestAverage() float64 {
+	if x != nil {
+		return x.CpuCoreRequestAverage
+	}
+	return 0
+}
+
+func (x *Allocation) GetCpuCoreUsageAverage() float64 {
+	if x != nil {
+		return x.CpuCoreUsageAverage
+	}
+	return 0
+}
+
+func (x *Allocation) GetCpuCost() float64 {
+	if x != nil {
+		return x.CpuCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetCpuCostAdjustment() float64 {
+	if x != nil {
+		return x.CpuCostAdjustment
+	}
+	return 0
+}
+
+func (x *Allocation) GetGpuHours() float64 {
+	if x != nil {
+		return x.GpuHours
+	}
+	return 0
+}
+
+func (x *Allocation) GetGpuCost() float64 {
+	if x != nil {
+		return x.GpuCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetGpuCostAdjustment() float64 {
+	if x != nil {
+		return x.GpuCostAdjustment
+	}
+	return 0
+}
+
+func (x *Allocation) GetNetworkTransferBytes() float64 {
+	if x != nil {
+		return x.NetworkTransferBytes
+	}
+	return 0
+}
+
+func (x *Allocation) GetNetworkReceiveBytes() float64 {
+	if x != nil {
+		return x.NetworkReceiveBytes
+	}
+	return 0
+}
+
+func (x *Allocation) GetNetworkCost() float64 {
+	if x != nil {
+		return x.NetworkCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetNetworkCostAdjustment() float64 {
+	if x != nil {
+		return x.NetworkCostAdjustment
+	}
+	return 0
+}
+
+func (x *Allocation) GetLoadBalancerCost() float64 {
+	if x != nil {
+		return x.LoadBalancerCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetLoadBalancerCostAdjustment() float64 {
+	if x != nil {
+		return x.LoadBalancerCostAdjustment
+	}
+	return 0
+}
+
+func (x *Allocation) GetPvByteHours() float64 {
+	if x != nil {
+		return x.PvByteHours
+	}
+	return 0
+}
+
+func (x *Allocation) GetPvCost() float64 {
+	if x != nil {
+		return x.PvCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetPvCostAdjustment() float64 {
+	if x != nil {
+		return x.PvCostAdjustment
+	}
+	return 0
+}
+
+func (x *Allocation) GetRamByteHours() float64 {
+	if x != nil {
+		return x.RamByteHours
+	}
+	return 0
+}
+
+func (x *Allocation) GetRamBytesRequestAverage() float64 {
+	if x != nil {
+		return x.RamBytesRequestAverage
+	}
+	return 0
+}
+
+func (x *Allocation) GetRamBytesUsageAverage() float64 {
+	if x != nil {
+		return x.RamBytesUsageAverage
+	}
+	return 0
+}
+
+func (x *Allocation) GetRamCost() float64 {
+	if x != nil {
+		return x.RamCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetRamCostAdjustment() float64 {
+	if x != nil {
+		return x.RamCostAdjustment
+	}
+	return 0
+}
+
+func (x *Allocation) GetSharedCost() float64 {
+	if x != nil {
+		return x.SharedCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetExternalCost() float64 {
+	if x != nil {
+		return x.ExternalCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *Allocation) GetSharedCostBreakdown() map[string]*SharedCostBreakdown {
+	if x != nil {
+		return x.SharedCostBreakdown
+	}
+	return nil
+}
+
+type SharedCostBreakdown struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TotalCost        float64                `protobuf:"fixed64,2,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	CpuCost          float64                `protobuf:"fixed64,3,opt,name=cpu_cost,json=cpuCost,proto3" json:"cpu_cost,omitempty"`
+	GpuCost          float64                `protobuf:"fixed64,4,opt,name=gpu_cost,json=gpuCost,proto3" json:"gpu_cost,omitempty"`
+	RamCost          float64                `protobuf:"fixed64,5,opt,name=ram_cost,json=ramCost,proto3" json:"ram_cost,omitempty"`
+	PvCost           float64                `protobuf:"fixed64,6,opt,name=pv_cost,json=pvCost,proto3" json:"pv_cost,omitempty"`
+	NetworkCost      float64                `protobuf:"fixed64,7,opt,name=network_cost,json=networkCost,proto3" json:"network_cost,omitempty"`
+	LoadBalancerCost float64                `protobuf:"fixed64,8,opt,name=load_balancer_cost,json=loadBalancerCost,proto3" json:"load_balancer_cost,omitempty"`
+	ExternalCost     float64                `protobuf:"fixed64,9,opt,name=external_cost,json=externalCost,proto3" json:"external_cost,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SharedCostBreakdown) Reset() {
+	*x = SharedCostBreakdown{}
+	mi := &file_query_query_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SharedCostBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SharedCostBreakdown) ProtoMessage() {}
+
+func (x *SharedCostBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SharedCostBreakdown.ProtoReflect.Descriptor instead.
+func (*SharedCostBreakdown) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SharedCostBreakdown) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SharedCostBreakdown) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *SharedCostBreakdown) GetCpuCost() float64 {
+	if x != nil {
+		return x.CpuCost
+	}
+	return 0
+}
+
+func (x *SharedCostBreakdown) GetGpuCost() float64 {
+	if x != nil {
+		return x.GpuCost
+	}
+	return 0
+}
+
+func (x *SharedCostBreakdown) GetRamCost() float64 {
+	if x != nil {
+		return x.RamCost
+	}
+	return 0
+}
+
+func (x *SharedCostBreakdown) GetPvCost() float64 {
+	if x != nil {
+		return x.PvCost
+	}
+	return 0
+}
+
+func (x *SharedCostBreakdown) GetNetworkCost() float64 {
+	if x != nil {
+		return x.NetworkCost
+	}
+	return 0
+}
+
+func (x *SharedCostBreakdown) GetLoadBalancerCost() float64 {
+	if x != nil {
+		return x.LoadBalancerCost
+	}
+	return 0
+}
+
+func (x *SharedCostBreakdown) GetExternalCost() float64 {
+	if x != nil {
+		return x.ExternalCost
+	}
+	return 0
+}
+
+// AssetRequest mirrors the parameters of the /assets HTTP API.
+type AssetRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Window string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	// asset filter expression
+	Filter        string `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetRequest) Reset() {
+	*x = AssetRequest{}
+	mi := &file_query_query_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetRequest) ProtoMessage() {}
+
+func (x *AssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetRequest.ProtoReflect.Descriptor instead.
+func (*AssetRequest) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AssetRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *AssetRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+type AssetSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Assets        []*Asset               `protobuf:"bytes,3,rep,name=assets,proto3" json:"assets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetSet) Reset() {
+	*x = AssetSet{}
+	mi := &file_query_query_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetSet) ProtoMessage() {}
+
+func (x *AssetSet) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetSet.ProtoReflect.Descriptor instead.
+func (*AssetSet) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AssetSet) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *AssetSet) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *AssetSet) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+type AssetProperties struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Account       string                 `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	Project       string                 `protobuf:"bytes,4,opt,name=project,proto3" json:"project,omitempty"`
+	Service       string                 `protobuf:"bytes,5,opt,name=service,proto3" json:"service,omitempty"`
+	Cluster       string                 `protobuf:"bytes,6,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	Name          string                 `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+	ProviderId    string                 `protobuf:"bytes,8,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetProperties) Reset() {
+	*x = AssetProperties{}
+	mi := &file_query_query_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetProperties) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetProperties) ProtoMessage() {}
+
+func (x *AssetProperties) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetProperties.ProtoReflect.Descriptor instead.
+func (*AssetProperties) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AssetProperties) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *AssetProperties) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *AssetProperties) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *AssetProperties) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *AssetProperties) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *AssetProperties) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+func (x *AssetProperties) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AssetProperties) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+// Asset flattens each asset type into a single message. Fields which do not
+// apply to an asset's type are left unset.
+type Asset struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// the asset type, e.g. "Node" or "Disk"
+	Type       string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Properties *AssetProperties       `protobuf:"bytes,2,opt,name=properties,proto3" json:"properties,omitempty"`
+	Labels     map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Start      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	Minutes    float64                `protobuf:"fixed64,6,opt,name=minutes,proto3" json:"minutes,omitempty"`
+	Adjustment float64                `protobuf:"fixed64,7,opt,name=adjustment,proto3" json:"adjustment,omitempty"`
+	TotalCost  float64                `protobuf:"fixed64,8,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	// Node
+	NodeType     string  `protobuf:"bytes,9,opt,name=node_type,json=nodeType,proto3" json:"node_type,omitempty"`
+	CpuCoreHours float64 `protobuf:"fixed64,10,opt,name=cpu_core_hours,json=cpuCoreHours,proto3" json:"cpu_core_hours,omitempty"`
+	RamByteHours float64 `protobuf:"fixed64,11,opt,name=ram_byte_hours,json=ramByteHours,proto3" json:"ram_byte_hours,omitempty"`
+	GpuHours     float64 `protobuf:"fixed64,12,opt,name=gpu_hours,json=gpuHours,proto3" json:"gpu_hours,omitempty"`
+	GpuCount     float64 `protobuf:"fixed64,13,opt,name=gpu_count,json=gpuCount,proto3" json:"gpu_count,omitempty"`
+	CpuCost      float64 `protobuf:"fixed64,14,opt,name=cpu_cost,json=cpuCost,proto3" json:"cpu_cost,omitempty"`
+	GpuCost      float64 `protobuf:"fixed64,15,opt,name=gpu_cost,json=gpuCost,proto3" json:"gpu_cost,omitempty"`
+	RamCost      float64 `protobuf:"fixed64,16,opt,name=ram_cost,json=ramCost,proto3" json:"ram_cost,omitempty"`
+	Discount     float64 `protobuf:"fixed64,17,opt,name=discount,proto3" json:"discount,omitempty"`
+	Preemptible  float64 `protobuf:"fixed64,18,opt,name=preemptible,proto3" json:"preemptible,omitempty"`
+	// Disk
+	ByteHours      float64 `protobuf:"fixed64,19,opt,name=byte_hours,json=byteHours,proto3" json:"byte_hours,omitempty"`
+	StorageClass   string  `protobuf:"bytes,20,opt,name=storage_class,json=storageClass,proto3" json:"storage_class,omitempty"`
+	VolumeName     string  `protobuf:"bytes,21,opt,name=volume_name,json=volumeName,proto3" json:"volume_name,omitempty"`
+	ClaimName      string  `protobuf:"bytes,22,opt,name=claim_name,json=claimName,proto3" json:"claim_name,omitempty"`
+	ClaimNamespace string  `protobuf:"bytes,23,opt,name=claim_namespace,json=claimNamespace,proto3" json:"claim_namespace,omitempty"`
+	Local          float64 `protobuf:"fixed64,24,opt,name=local,proto3" json:"local,omitempty"`
+	// LoadBalancer
+	Private bool   `protobuf:"varint,25,opt,name=private,proto3" json:"private,omitempty"`
+	Ip      string `protobuf:"bytes,26,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Cloud
+	Credit        float64 `protobuf:"fixed64,27,opt,name=credit,proto3" json:"credit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Asset) Reset() {
+	*x = Asset{}
+	mi := &file_query_query_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Asset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Asset) ProtoMessage() {}
+
+func (x *Asset) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Asset.ProtoReflect.Descriptor instead.
+func (*Asset) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Asset) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Asset) GetProperties() *AssetProperties {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *Asset) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Asset) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Asset) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Asset) GetMinutes() float64 {
+	if x != nil {
+		return x.Minutes
+	}
+	return 0
+}
+
+func (x *Asset) GetAdjustment() float64 {
+	if x != nil {
+		return x.Adjustment
+	}
+	return 0
+}
+
+func (x *Asset) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *Asset) GetNodeType() string {
+	if x != nil {
+		return x.NodeType
+	}
+	return ""
+}
+
+func (x *Asset) GetCpuCoreHours() float64 {
+	if x != nil {
+		return x.CpuCoreHours
+	}
+	return 0
+}
+
+func (x *Asset) GetRamByteHours() float64 {
+	if x != nil {
+		return x.RamByteHours
+	}
+	return 0
+}
+
+func (x *Asset) GetGpuHours() float64 {
+	if x != nil {
+		return x.GpuHours
+	}
+	return 0
+}
+
+func (x *Asset) GetGpuCount() float64 {
+	if x != nil {
+		return x.GpuCount
+	}
+	return 0
+}
+
+func (x *Asset) GetCpuCost() float64 {
+	if x != nil {
+		return x.CpuCost
+	}
+	return 0
+}
+
+func (x *Asset) GetGpuCost() float64 {
+	if x != nil {
+		return x.GpuCost
+	}
+	return 0
+}
+
+func (x *Asset) GetRamCost() float64 {
+	if x != nil {
+		return x.RamCost
+	}
+	return 0
+}
+
+func (x *Asset) GetDiscount() float64 {
+	if x != nil {
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *Asset) GetPreemptible() float64 {
+	if x != nil {
+		return x.Preemptible
+	}
+	return 0
+}
+
+func (x *Asset) GetByteHours() float64 {
+	if x != nil {
+		return x.ByteHours
+	}
+	return 0
+}
+
+func (x *Asset) GetStorageClass() string {
+	if x != nil {
+		return x.StorageClass
+	}
+	return ""
+}
+
+func (x *Asset) GetVolumeName() string {
+	if x != nil {
+		return x.VolumeName
+	}
+	return ""
+}
+
+func (x *Asset) GetClaimName() string {
+	if x != nil {
+		return x.ClaimName
+	}
+	return ""
+}
+
+func (x *Asset) GetClaimNamespace() string {
+	if x != nil {
+		return x.ClaimNamespace
+	}
+	return ""
+}
+
+func (x *Asset) GetLocal() float64 {
+	if x != nil {
+		return x.Local
+	}
+	return 0
+}
+
+func (x *Asset) GetPrivate() bool {
+	if x != nil {
+		return x.Private
+	}
+	return false
+}
+
+func (x *Asset) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Asset) GetCredit() float64 {
+	if x != nil {
+		return x.Credit
+	}
+	return 0
+}
+
+// CloudCostRequest mirrors the parameters of the /cloudCost HTTP API.
+type CloudCostRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Window    string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Aggregate []string               `protobuf:"bytes,2,rep,name=aggregate,proto3" json:"aggregate,omitempty"`
+	// accumulate steps by a resolution, e.g. "day" or "week"
+	Accumulate string `protobuf:"bytes,3,opt,name=accumulate,proto3" json:"accumulate,omitempty"`
+	// cloud cost filter expression
+	Filter        string `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloudCostRequest) Reset() {
+	*x = CloudCostRequest{}
+	mi := &file_query_query_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloudCostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudCostRequest) ProtoMessage() {}
+
+func (x *CloudCostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudCostRequest.ProtoReflect.Descriptor instead.
+func (*CloudCostRequest) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CloudCostRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *CloudCostRequest) GetAggregate() []string {
+	if x != nil {
+		return x.Aggregate
+	}
+	return nil
+}
+
+func (x *CloudCostRequest) GetAccumulate() string {
+	if x != nil {
+		return x.Accumulate
+	}
+	return ""
+}
+
+func (x *CloudCostRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+type CloudCostSetRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sets          []*CloudCostSet        `protobuf:"bytes,1,rep,name=sets,proto3" json:"sets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloudCostSetRange) Reset() {
+	*x = CloudCostSetRange{}
+	mi := &file_query_query_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloudCostSetRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudCostSetRange) ProtoMessage() {}
+
+func (x *CloudCostSetRange) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudCostSetRange.ProtoReflect.Descriptor instead.
+func (*CloudCostSetRange) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CloudCostSetRange) GetSets() []*CloudCostSet {
+	if x != nil {
+		return x.Sets
+	}
+	return nil
+}
+
+type CloudCostSet struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Start                 *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End                   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	AggregationProperties []string               `protobuf:"bytes,3,rep,name=aggregation_properties,json=aggregationProperties,proto3" json:"aggregation_properties,omitempty"`
+	CloudCosts            []*CloudCost           `protobuf:"bytes,4,rep,name=cloud_costs,json=cloudCosts,proto3" json:"cloud_costs,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *CloudCostSet) Reset() {
+	*x = CloudCostSet{}
+	mi := &file_query_query_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloudCostSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudCostSet) ProtoMessage() {}
+
+func (x *CloudCostSet) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudCostSet.ProtoReflect.Descriptor instead.
+func (*CloudCostSet) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CloudCostSet) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *CloudCostSet) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *CloudCostSet) GetAggregationProperties() []string {
+	if x != nil {
+		return x.AggregationProperties
+	}
+	return nil
+}
+
+func (x *CloudCostSet) GetCloudCosts() []*CloudCost {
+	if x != nil {
+		return x.CloudCosts
+	}
+	return nil
+}
+
+type CloudCostProperties struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ProviderId        string                 `protobuf:"bytes,1,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	Provider          string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	AccountId         string                 `protobuf:"bytes,3,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	AccountName       string                 `protobuf:"bytes,4,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
+	InvoiceEntityId   string                 `protobuf:"bytes,5,opt,name=invoice_entity_id,json=invoiceEntityId,proto3" json:"invoice_entity_id,omitempty"`
+	InvoiceEntityName string                 `protobuf:"bytes,6,opt,name=invoice_entity_name,json=invoiceEntityName,proto3" json:"invoice_entity_name,omitempty"`
+	RegionId          string                 `protobuf:"bytes,7,opt,name=region_id,json=regionId,proto3" json:"region_id,omitempty"`
+	AvailabilityZone  string                 `protobuf:"bytes,8,opt,name=availability_zone,json=availabilityZone,proto3" json:"availability_zone,omitempty"`
+	Service           string                 `protobuf:"bytes,9,opt,name=service,proto3" json:"service,omitempty"`
+	Category          string                 `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
+	Labels            map[string]string      `protobuf:"bytes,11,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CloudCostProperties) Reset() {
+	*x = CloudCostProperties{}
+	mi := &file_query_query_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloudCostProperties) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudCostProperties) ProtoMessage() {}
+
+func (x *CloudCostProperties) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudCostProperties.ProtoReflect.Descriptor instead.
+func (*CloudCostProperties) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CloudCostProperties) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetAccountName() string {
+	if x != nil {
+		return x.AccountName
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetInvoiceEntityId() string {
+	if x != nil {
+		return x.InvoiceEntityId
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetInvoiceEntityName() string {
+	if x != nil {
+		return x.InvoiceEntityName
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetRegionId() string {
+	if x != nil {
+		return x.RegionId
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetAvailabilityZone() string {
+	if x != nil {
+		return x.AvailabilityZone
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CloudCostProperties) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type CostMetric struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Cost              float64                `protobuf:"fixed64,1,opt,name=cost,proto3" json:"cost,omitempty"`
+	KubernetesPercent float64                `protobuf:"fixed64,2,opt,name=kubernetes_percent,json=kubernetesPercent,proto3" json:"kubernetes_percent,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CostMetric) Reset() {
+	*x = CostMetric{}
+	mi := &file_query_query_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostMetric) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostMetric) ProtoMessage() {}
+
+func (x *CostMetric) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostMetric.ProtoReflect.Descriptor instead.
+func (*CostMetric) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CostMetric) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *CostMetric) GetKubernetesPercent() float64 {
+	if x != nil {
+		return x.KubernetesPercent
+	}
+	return 0
+}
+
+type CloudCost struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Properties       *CloudCostProperties   `protobuf:"bytes,1,opt,name=properties,proto3" json:"properties,omitempty"`
+	Start            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End              *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	ListCost         *CostMetric            `protobuf:"bytes,4,opt,name=list_cost,json=listCost,proto3" json:"list_cost,omitempty"`
+	NetCost          *CostMetric            `protobuf:"bytes,5,opt,name=net_cost,json=netCost,proto3" json:"net_cost,omitempty"`
+	AmortizedNetCost *CostMetric            `protobuf:"bytes,6,opt,name=amortized_net_cost,json=amortizedNetCost,proto3" json:"amortized_net_cost,omitempty"`
+	InvoicedCost     *CostMetric            `protobuf:"bytes,7,opt,name=invoiced_cost,json=invoicedCost,proto3" json:"invoiced_cost,omitempty"`
+	AmortizedCost    *CostMetric            `protobuf:"bytes,8,opt,name=amortized_cost,json=amortizedCost,proto3" json:"amortized_cost,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CloudCost) Reset() {
+	*x = CloudCost{}
+	mi := &file_query_query_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloudCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloudCost) ProtoMessage() {}
+
+func (x *CloudCost) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloudCost.ProtoReflect.Descriptor instead.
+func (*CloudCost) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CloudCost) GetProperties() *CloudCostProperties {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+func (x *CloudCost) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *CloudCost) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *CloudCost) GetListCost() *CostMetric {
+	if x != nil {
+		return x.ListCost
+	}
+	return nil
+}
+
+func (x *CloudCost) GetNetCost() *CostMetric {
+	if x != nil {
+		return x.NetCost
+	}
+	return nil
+}
+
+func (x *CloudCost) GetAmortizedNetCost() *CostMetric {
+	if x != nil {
+		return x.AmortizedNetCost
+	}
+	return nil
+}
+
+func (x *CloudCost) GetInvoicedCost() *CostMetric {
+	if x != nil {
+		return x.InvoicedCost
+	}
+	return nil
+}
+
+func (x *CloudCost) GetAmortizedCost() *CostMetric {
+	if x != nil {
+		return x.AmortizedCost
+	}
+	return nil
+}
+
+// CustomCostRequest mirrors the parameters of the /customCost/timeseries HTTP
+// API.
+type CustomCostRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Window    string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Aggregate []string               `protobuf:"bytes,2,rep,name=aggregate,proto3" json:"aggregate,omitempty"`
+	// accumulate steps by a resolution. Defaults to "day".
+	Accumulate string `protobuf:"bytes,3,opt,name=accumulate,proto3" json:"accumulate,omitempty"`
+	// custom cost filter expression
+	Filter string `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	// one of "blended", "list" or "billed". Defaults to "blended".
+	CostType string `protobuf:"bytes,5,opt,name=cost_type,json=costType,proto3" json:"cost_type,omitempty"`
+	// one of "cost", "aggregate" or "costType". Defaults to "cost".
+	SortBy string `protobuf:"bytes,6,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// one of "asc" or "desc". Defaults to "desc".
+	SortDirection string `protobuf:"bytes,7,opt,name=sort_direction,json=sortDirection,proto3" json:"sort_direction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomCostRequest) Reset() {
+	*x = CustomCostRequest{}
+	mi := &file_query_query_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomCostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomCostRequest) ProtoMessage() {}
+
+func (x *CustomCostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomCostRequest.ProtoReflect.Descriptor instead.
+func (*CustomCostRequest) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CustomCostRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *CustomCostRequest) GetAggregate() []string {
+	if x != nil {
+		return x.Aggregate
+	}
+	return nil
+}
+
+func (x *CustomCostRequest) GetAccumulate() string {
+	if x != nil {
+		return x.Accumulate
+	}
+	return ""
+}
+
+func (x *CustomCostRequest) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *CustomCostRequest) GetCostType() string {
+	if x != nil {
+		return x.CostType
+	}
+	return ""
+}
+
+func (x *CustomCostRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *CustomCostRequest) GetSortDirection() string {
+	if x != nil {
+		return x.SortDirection
+	}
+	return ""
+}
+
+type CustomCostTimeseries struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Timeseries    []*CustomCostSet       `protobuf:"bytes,3,rep,name=timeseries,proto3" json:"timeseries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomCostTimeseries) Reset() {
+	*x = CustomCostTimeseries{}
+	mi := &file_query_query_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomCostTimeseries) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomCostTimeseries) ProtoMessage() {}
+
+func (x *CustomCostTimeseries) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomCostTimeseries.ProtoReflect.Descriptor instead.
+func (*CustomCostTimeseries) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CustomCostTimeseries) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *CustomCostTimeseries) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *CustomCostTimeseries) GetTimeseries() []*CustomCostSet {
+	if x != nil {
+		return x.Timeseries
+	}
+	return nil
+}
+
+type CustomCostSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	TotalCost     float64                `protobuf:"fixed64,3,opt,name=total_cost,json=totalCost,proto3" json:"total_cost,omitempty"`
+	TotalCostType string                 `protobuf:"bytes,4,opt,name=total_cost_type,json=totalCostType,proto3" json:"total_cost_type,omitempty"`
+	CustomCosts   []*CustomCost          `protobuf:"bytes,5,rep,name=custom_costs,json=customCosts,proto3" json:"custom_costs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomCostSet) Reset() {
+	*x = CustomCostSet{}
+	mi := &file_query_query_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomCostSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomCostSet) ProtoMessage() {}
+
+func (x *CustomCostSet) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomCostSet.ProtoReflect.Descriptor instead.
+func (*CustomCostSet) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CustomCostSet) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *CustomCostSet) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *CustomCostSet) GetTotalCost() float64 {
+	if x != nil {
+		return x.TotalCost
+	}
+	return 0
+}
+
+func (x *CustomCostSet) GetTotalCostType() string {
+	if x != nil {
+		return x.TotalCostType
+	}
+	return ""
+}
+
+func (x *CustomCostSet) GetCustomCosts() []*CustomCost {
+	if x != nil {
+		return x.CustomCosts
+	}
+	return nil
+}
+
+type CustomCost struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Zone               string                 `protobuf:"bytes,2,opt,name=zone,proto3" json:"zone,omitempty"`
+	AccountName        string                 `protobuf:"bytes,3,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
+	ChargeCategory     string                 `protobuf:"bytes,4,opt,name=charge_category,json=chargeCategory,proto3" json:"charge_category,omitempty"`
+	Description        string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	ResourceName       string                 `protobuf:"bytes,6,opt,name=resource_name,json=resourceName,proto3" json:"resource_name,omitempty"`
+	ResourceType       string                 `protobuf:"bytes,7,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	ProviderId         string                 `protobuf:"bytes,8,opt,name=provider_id,json=providerId,proto3" json:"provider_id,omitempty"`
+	Cost               float64                `protobuf:"fixed64,9,opt,name=cost,proto3" json:"cost,omitempty"`
+	ListUnitPrice      float64                `protobuf:"fixed64,10,opt,name=list_unit_price,json=listUnitPrice,proto3" json:"list_unit_price,omitempty"`
+	UsageQuantity      float64                `protobuf:"fixed64,11,opt,name=usage_quantity,json=usageQuantity,proto3" json:"usage_quantity,omitempty"`
+	UsageUnit          string                 `protobuf:"bytes,12,opt,name=usage_unit,json=usageUnit,proto3" json:"usage_unit,omitempty"`
+	Domain             string                 `protobuf:"bytes,13,opt,name=domain,proto3" json:"domain,omitempty"`
+	CostSource         string                 `protobuf:"bytes,14,opt,name=cost_source,json=costSource,proto3" json:"cost_source,omitempty"`
+	Aggregate          string                 `protobuf:"bytes,15,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+	CostType           string                 `protobuf:"bytes,16,opt,name=cost_type,json=costType,proto3" json:"cost_type,omitempty"`
+	Labels             map[string]string      `protobuf:"bytes,17,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ExtendedAttributes map[string]string      `protobuf:"bytes,18,rep,name=extended_attributes,json=extendedAttributes,proto3" json:"extended_attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CustomCost) Reset() {
+	*x = CustomCost{}
+	mi := &file_query_query_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomCost) ProtoMessage() {}
+
+func (x *CustomCost) ProtoReflect() protoreflect.Message {
+	mi := &file_query_query_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomCost.ProtoReflect.Descriptor instead.
+func (*CustomCost) Descriptor() ([]byte, []int) {
+	return file_query_query_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CustomCost) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CustomCost) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *CustomCost) GetAccountName() string {
+	if x != nil {
+		return x.AccountName
+	}
+	return ""
+}
+
+func (x *CustomCost) GetChargeCategory() string {
+	if x != nil {
+		return x.ChargeCategory
+	}
+	return ""
+}
+
+func (x *CustomCost) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CustomCost) GetResourceName() string {
+	if x != nil {
+		return x.ResourceName
+	}
+	return ""
+}
+
+func (x *CustomCost) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *CustomCost) GetProviderId() string {
+	if x != nil {
+		return x.ProviderId
+	}
+	return ""
+}
+
+func (x *CustomCost) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *CustomCost) GetListUnitPrice() float64 {
+	if x != nil {
+		return x.ListUnitPrice
+	}
+	return 0
+}
+
+func (x *CustomCost) GetUsageQuantity() float64 {
+	if x != nil {
+		return x.UsageQuantity
+	}
+	return 0
+}
+
+func (x *CustomCost) GetUsageUnit() string {
+	if x != nil {
+		return x.UsageUnit
+	}
+	return ""
+}
+
+func (x *CustomCost) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *CustomCost) GetCostSource() string {
+	if x != nil {
+		return x.CostSource
+	}
+	return ""
+}
+
+func (x *CustomCost) GetAggregate() string {
+	if x != nil {
+		return x.Aggregate
+	}
+	return ""
+}
+
+func (x *CustomCost) GetCostType() string {
+	if x != nil {
+		return x.CostType
+	}
+	return ""
+}
+
+func (x *CustomCost) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CustomCost) GetExtendedAttributes() map[string]string {
+	if x != nil {
+		return x.ExtendedAttributes
+	}
+	return nil
+}
+
+var File_query_query_proto protoreflect.FileDescriptor
+
+const file_query_query_proto_rawDesc = "" +
+	"\n" +
+	"\x11query/query.proto\x12\x05query\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe7\x05\n" +
+	"\x11AllocationRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\tR\x04step\x12\x1c\n" +
+	"\taggregate\x18\x03 \x03(\tR\taggregate\x12\x16\n" +
+	"\x06filter\x18\x04 \x01(\tR\x06filter\x12\x1e\n" +
+	"\n" +
+	"accumulate\x18\x05 \x01(\bR\n" +
+	"accumulate\x12#\n" +
+	"\raccumulate_by\x18\x06 \x01(\tR\faccumulateBy\x12!\n" +
+	"\finclude_idle\x18\a \x01(\bR\vincludeIdle\x12 \n" +
+	"\fidle_by_node\x18\b \x01(\bR\n" +
+	"idleByNode\x12\x1d\n" +
+	"\n" +
+	"share_idle\x18\t \x01(\bR\tshareIdle\x12\x19\n" +
+	"\bshare_lb\x18\n" +
+	" \x01(\bR\ashareLb\x12X\n" +
+	")include_proportional_asset_resource_costs\x18\v \x01(\bR%includeProportionalAssetResourceCosts\x12>\n" +
+	"\x1binclude_aggregated_metadata\x18\f \x01(\bR\x19includeAggregatedMetadata\x120\n" +
+	"\x14include_custom_costs\x18\r \x01(\bR\x12includeCustomCosts\x12A\n" +
+	"\x1dinclude_shared_cost_breakdown\x18\x0e \x01(\bR\x1aincludeSharedCostBreakdown\x12)\n" +
+	"\x10share_namespaces\x18\x0f \x03(\tR\x0fshareNamespaces\x12!\n" +
+	"\fshare_labels\x18\x10 \x03(\tR\vshareLabels\x12\x1f\n" +
+	"\vshare_split\x18\x11 \x01(\tR\n" +
+	"shareSplit\x12.\n" +
+	"\x13share_tenancy_costs\x18\x12 \x01(\bR\x11shareTenancyCosts\"\xa4\x01\n" +
+	"\rAllocationSet\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x123\n" +
+	"\vallocations\x18\x03 \x03(\v2\x11.query.AllocationR\vallocations\"\xfa\x06\n" +
+	"\x14AllocationProperties\x12\x18\n" +
+	"\acluster\x18\x01 \x01(\tR\acluster\x12\x12\n" +
+	"\x04node\x18\x02 \x01(\tR\x04node\x12\x1c\n" +
+	"\tcontainer\x18\x03 \x01(\tR\tcontainer\x12\x1e\n" +
+	"\n" +
+	"controller\x18\x04 \x01(\tR\n" +
+	"controller\x12'\n" +
+	"\x0fcontroller_kind\x18\x05 \x01(\tR\x0econtrollerKind\x12\x1c\n" +
+	"\tnamespace\x18\x06 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03pod\x18\a \x01(\tR\x03pod\x12\x1a\n" +
+	"\bservices\x18\b \x03(\tR\bservices\x12\x1f\n" +
+	"\vprovider_id\x18\t \x01(\tR\n" +
+	"providerId\x12?\n" +
+	"\x06labels\x18\n" +
+	" \x03(\v2'.query.AllocationProperties.LabelsEntryR\x06labels\x12N\n" +
+	"\vannotations\x18\v \x03(\v2,.query.AllocationProperties.AnnotationsEntryR\vannotations\x12[\n" +
+	"\x10namespace_labels\x18\f \x03(\v20.query.AllocationProperties.NamespaceLabelsEntryR\x0fnamespaceLabels\x12j\n" +
+	"\x15namespace_annotations\x18\r \x03(\v25.query.AllocationProperties.NamespaceAnnotationsEntryR\x14namespaceAnnotations\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
+	"\x10AnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aB\n" +
+	"\x14NamespaceLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aG\n" +
+	"\x19NamespaceAnnotationsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb1\v\n" +
+	"\n" +
+	"Allocation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12;\n" +
+	"\n" +
+	"properties\x18\x02 \x01(\v2\x1b.query.AllocationPropertiesR\n" +
+	"properties\x120\n" +
+	"\x05start\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12$\n" +
+	"\x0ecpu_core_hours\x18\x05 \x01(\x01R\fcpuCoreHours\x127\n" +
+	"\x18cpu_core_request_average\x18\x06 \x01(\x01R\x15cpuCoreRequestAverage\x123\n" +
+	"\x16cpu_core_usage_average\x18\a \x01(\x01R\x13cpuCoreUsageAverage\x12\x19\n" +
+	"\bcpu_cost\x18\b \x01(\x01R\acpuCost\x12.\n" +
+	"\x13cpu_cost_adjustment\x18\t \x01(\x01R\x11cpuCostAdjustment\x12\x1b\n" +
+	"\tgpu_hours\x18\n" +
+	" \x01(\x01R\bgpuHours\x12\x19\n" +
+	"\bgpu_cost\x18\v \x01(\x01R\agpuCost\x12.\n" +
+	"\x13gpu_cost_adjustment\x18\f \x01(\x01R\x11gpuCostAdjustment\x124\n" +
+	"\x16network_transfer_bytes\x18\r \x01(\x01R\x14networkTransferBytes\x122\n" +
+	"\x15network_receive_bytes\x18\x0e \x01(\x01R\x13networkReceiveBytes\x12!\n" +
+	"\fnetwork_cost\x18\x0f \x01(\x01R\vnetworkCost\x126\n" +
+	"\x17network_cost_adjustment\x18\x10 \x01(\x01R\x15networkCostAdjustment\x12,\n" +
+	"\x12load_balancer_cost\x18\x11 \x01(\x01R\x10loadBalancerCost\x12A\n" +
+	"\x1dload_balancer_cost_adjustment\x18\x12 \x01(\x01R\x1aloadBalancerCostAdjustment\x12\"\n" +
+	"\rpv_byte_hours\x18\x13 \x01(\x01R\vpvByteHours\x12\x17\n" +
+	"\apv_cost\x18\x14 \x01(\x01R\x06pvCost\x12,\n" +
+	"\x12pv_cost_adjustment\x18\x15 \x01(\x01R\x10pvCostAdjustment\x12$\n" +
+	"\x0eram_byte_hours\x18\x16 \x01(\x01R\framByteHours\x129\n" +
+	"\x19ram_bytes_request_average\x18\x17 \x01(\x01R\x16ramBytesRequestAverage\x125\n" +
+	"\x17ram_bytes_usage_average\x18\x18 \x01(\x01R\x14ramBytesUsageAverage\x12\x19\n" +
+	"\bram_cost\x18\x19 \x01(\x01R\aramCost\x12.\n" +
+	"\x13ram_cost_adjustment\x18\x1a \x01(\x01R\x11ramCostAdjustment\x12\x1f\n" +
+	"\vshared_cost\x18\x1b \x01(\x01R\n" +
+	"sharedCost\x12#\n" +
+	"\rexternal_cost\x18\x1c \x01(\x01R\fexternalCost\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x1d \x01(\x01R\ttotalCost\x12^\n" +
+	"\x15shared_cost_breakdown\x18\x1e \x03(\v2*.query.Allocation.SharedCostBreakdownEntryR\x13sharedCostBreakdown\x1ab\n" +
+	"\x18SharedCostBreakdownEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x120\n" +
+	"\x05value\x18\x02 \x01(\v2\x1a.query.SharedCostBreakdownR\x05value:\x028\x01\"\xa8\x02\n" +
+	"\x13SharedCostBreakdown\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x02 \x01(\x01R\ttotalCost\x12\x19\n" +
+	"\bcpu_cost\x18\x03 \x01(\x01R\acpuCost\x12\x19\n" +
+	"\bgpu_cost\x18\x04 \x01(\x01R\agpuCost\x12\x19\n" +
+	"\bram_cost\x18\x05 \x01(\x01R\aramCost\x12\x17\n" +
+	"\apv_cost\x18\x06 \x01(\x01R\x06pvCost\x12!\n" +
+	"\fnetwork_cost\x18\a \x01(\x01R\vnetworkCost\x12,\n" +
+	"\x12load_balancer_cost\x18\b \x01(\x01R\x10loadBalancerCost\x12#\n" +
+	"\rexternal_cost\x18\t \x01(\x01R\fexternalCost\">\n" +
+	"\fAssetRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x16\n" +
+	"\x06filter\x18\x02 \x01(\tR\x06filter\"\x90\x01\n" +
+	"\bAssetSet\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12$\n" +
+	"\x06assets\x18\x03 \x03(\v2\f.query.AssetR\x06assets\"\xe6\x01\n" +
+	"\x0fAssetProperties\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x18\n" +
+	"\aaccount\x18\x03 \x01(\tR\aaccount\x12\x18\n" +
+	"\aproject\x18\x04 \x01(\tR\aproject\x12\x18\n" +
+	"\aservice\x18\x05 \x01(\tR\aservice\x12\x18\n" +
+	"\acluster\x18\x06 \x01(\tR\acluster\x12\x12\n" +
+	"\x04name\x18\a \x01(\tR\x04name\x12\x1f\n" +
+	"\vprovider_id\x18\b \x01(\tR\n" +
+	"providerId\"\xb0\a\n" +
+	"\x05Asset\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x126\n" +
+	"\n" +
+	"properties\x18\x02 \x01(\v2\x16.query.AssetPropertiesR\n" +
+	"properties\x120\n" +
+	"\x06labels\x18\x03 \x03(\v2\x18.query.Asset.LabelsEntryR\x06labels\x120\n" +
+	"\x05start\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x18\n" +
+	"\aminutes\x18\x06 \x01(\x01R\aminutes\x12\x1e\n" +
+	"\n" +
+	"adjustment\x18\a \x01(\x01R\n" +
+	"adjustment\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\b \x01(\x01R\ttotalCost\x12\x1b\n" +
+	"\tnode_type\x18\t \x01(\tR\bnodeType\x12$\n" +
+	"\x0ecpu_core_hours\x18\n" +
+	" \x01(\x01R\fcpuCoreHours\x12$\n" +
+	"\x0eram_byte_hours\x18\v \x01(\x01R\framByteHours\x12\x1b\n" +
+	"\tgpu_hours\x18\f \x01(\x01R\bgpuHours\x12\x1b\n" +
+	"\tgpu_count\x18\r \x01(\x01R\bgpuCount\x12\x19\n" +
+	"\bcpu_cost\x18\x0e \x01(\x01R\acpuCost\x12\x19\n" +
+	"\bgpu_cost\x18\x0f \x01(\x01R\agpuCost\x12\x19\n" +
+	"\bram_cost\x18\x10 \x01(\x01R\aramCost\x12\x1a\n" +
+	"\bdiscount\x18\x11 \x01(\x01R\bdiscount\x12 \n" +
+	"\vpreemptible\x18\x12 \x01(\x01R\vpreemptible\x12\x1d\n" +
+	"\n" +
+	"byte_hours\x18\x13 \x01(\x01R\tbyteHours\x12#\n" +
+	"\rstorage_class\x18\x14 \x01(\tR\fstorageClass\x12\x1f\n" +
+	"\vvolume_name\x18\x15 \x01(\tR\n" +
+	"volumeName\x12\x1d\n" +
+	"\n" +
+	"claim_name\x18\x16 \x01(\tR\tclaimName\x12'\n" +
+	"\x0fclaim_namespace\x18\x17 \x01(\tR\x0eclaimNamespace\x12\x14\n" +
+	"\x05local\x18\x18 \x01(\x01R\x05local\x12\x18\n" +
+	"\aprivate\x18\x19 \x01(\bR\aprivate\x12\x0e\n" +
+	"\x02ip\x18\x1a \x01(\tR\x02ip\x12\x16\n" +
+	"\x06credit\x18\x1b \x01(\x01R\x06credit\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x80\x01\n" +
+	"\x10CloudCostRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x1c\n" +
+	"\taggregate\x18\x02 \x03(\tR\taggregate\x12\x1e\n" +
+	"\n" +
+	"accumulate\x18\x03 \x01(\tR\n" +
+	"accumulate\x12\x16\n" +
+	"\x06filter\x18\x04 \x01(\tR\x06filter\"<\n" +
+	"\x11CloudCostSetRange\x12'\n" +
+	"\x04sets\x18\x01 \x03(\v2\x13.query.CloudCostSetR\x04sets\"\xd8\x01\n" +
+	"\fCloudCostSet\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x125\n" +
+	"\x16aggregation_properties\x18\x03 \x03(\tR\x15aggregationProperties\x121\n" +
+	"\vcloud_costs\x18\x04 \x03(\v2\x10.query.CloudCostR\n" +
+	"cloudCosts\"\xeb\x03\n" +
+	"\x13CloudCostProperties\x12\x1f\n" +
+	"\vprovider_id\x18\x01 \x01(\tR\n" +
+	"providerId\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x03 \x01(\tR\taccountId\x12!\n" +
+	"\faccount_name\x18\x04 \x01(\tR\vaccountName\x12*\n" +
+	"\x11invoice_entity_id\x18\x05 \x01(\tR\x0finvoiceEntityId\x12.\n" +
+	"\x13invoice_entity_name\x18\x06 \x01(\tR\x11invoiceEntityName\x12\x1b\n" +
+	"\tregion_id\x18\a \x01(\tR\bregionId\x12+\n" +
+	"\x11availability_zone\x18\b \x01(\tR\x10availabilityZone\x12\x18\n" +
+	"\aservice\x18\t \x01(\tR\aservice\x12\x1a\n" +
+	"\bcategory\x18\n" +
+	" \x01(\tR\bcategory\x12>\n" +
+	"\x06labels\x18\v \x03(\v2&.query.CloudCostProperties.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"O\n" +
+	"\n" +
+	"CostMetric\x12\x12\n" +
+	"\x04cost\x18\x01 \x01(\x01R\x04cost\x12-\n" +
+	"\x12kubernetes_percent\x18\x02 \x01(\x01R\x11kubernetesPercent\"\xb8\x03\n" +
+	"\tCloudCost\x12:\n" +
+	"\n" +
+	"properties\x18\x01 \x01(\v2\x1a.query.CloudCostPropertiesR\n" +
+	"properties\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12.\n" +
+	"\tlist_cost\x18\x04 \x01(\v2\x11.query.CostMetricR\blistCost\x12,\n" +
+	"\bnet_cost\x18\x05 \x01(\v2\x11.query.CostMetricR\anetCost\x12?\n" +
+	"\x12amortized_net_cost\x18\x06 \x01(\v2\x11.query.CostMetricR\x10amortizedNetCost\x126\n" +
+	"\rinvoiced_cost\x18\a \x01(\v2\x11.query.CostMetricR\finvoicedCost\x128\n" +
+	"\x0eamortized_cost\x18\b \x01(\v2\x11.query.CostMetricR\ramortizedCost\"\xde\x01\n" +
+	"\x11CustomCostRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x1c\n" +
+	"\taggregate\x18\x02 \x03(\tR\taggregate\x12\x1e\n" +
+	"\n" +
+	"accumulate\x18\x03 \x01(\tR\n" +
+	"accumulate\x12\x16\n" +
+	"\x06filter\x18\x04 \x01(\tR\x06filter\x12\x1b\n" +
+	"\tcost_type\x18\x05 \x01(\tR\bcostType\x12\x17\n" +
+	"\asort_by\x18\x06 \x01(\tR\x06sortBy\x12%\n" +
+	"\x0esort_direction\x18\a \x01(\tR\rsortDirection\"\xac\x01\n" +
+	"\x14CustomCostTimeseries\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x124\n" +
+	"\n" +
+	"timeseries\x18\x03 \x03(\v2\x14.query.CustomCostSetR\n" +
+	"timeseries\"\xec\x01\n" +
+	"\rCustomCostSet\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x1d\n" +
+	"\n" +
+	"total_cost\x18\x03 \x01(\x01R\ttotalCost\x12&\n" +
+	"\x0ftotal_cost_type\x18\x04 \x01(\tR\rtotalCostType\x124\n" +
+	"\fcustom_costs\x18\x05 \x03(\v2\x11.query.CustomCostR\vcustomCosts\"\x94\x06\n" +
+	"\n" +
+	"CustomCost\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04zone\x18\x02 \x01(\tR\x04zone\x12!\n" +
+	"\faccount_name\x18\x03 \x01(\tR\vaccountName\x12'\n" +
+	"\x0fcharge_category\x18\x04 \x01(\tR\x0echargeCategory\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12#\n" +
+	"\rresource_name\x18\x06 \x01(\tR\fresourceName\x12#\n" +
+	"\rresource_type\x18\a \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vprovider_id\x18\b \x01(\tR\n" +
+	"providerId\x12\x12\n" +
+	"\x04cost\x18\t \x01(\x01R\x04cost\x12&\n" +
+	"\x0flist_unit_price\x18\n" +
+	" \x01(\x01R\rlistUnitPrice\x12%\n" +
+	"\x0eusage_quantity\x18\v \x01(\x01R\rusageQuantity\x12\x1d\n" +
+	"\n" +
+	"usage_unit\x18\f \x01(\tR\tusageUnit\x12\x16\n" +
+	"\x06domain\x18\r \x01(\tR\x06domain\x12\x1f\n" +
+	"\vcost_source\x18\x0e \x01(\tR\n" +
+	"costSource\x12\x1c\n" +
+	"\taggregate\x18\x0f \x01(\tR\taggregate\x12\x1b\n" +
+	"\tcost_type\x18\x10 \x01(\tR\bcostType\x125\n" +
+	"\x06labels\x18\x11 \x03(\v2\x1d.query.CustomCost.LabelsEntryR\x06labels\x12Z\n" +
+	"\x13extended_attributes\x18\x12 \x03(\v2).query.CustomCost.ExtendedAttributesEntryR\x12extendedAttributes\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aE\n" +
+	"\x17ExtendedAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\x9a\x02\n" +
+	"\fQueryService\x12D\n" +
+	"\x10QueryAllocations\x12\x18.query.AllocationRequest\x1a\x14.query.AllocationSet0\x01\x123\n" +
+	"\vQueryAssets\x12\x13.query.AssetRequest\x1a\x0f.query.AssetSet\x12D\n" +
+	"\x0fQueryCloudCosts\x12\x17.query.CloudCostRequest\x1a\x18.query.CloudCostSetRange\x12I\n" +
+	"\x10QueryCustomCosts\x12\x18.query.CustomCostRequest\x1a\x1b.query.CustomCostTimeseriesB6Z4github.com/opencost/opencost/core/pkg/model/pb/queryb\x06proto3"
+
+var (
+	file_query_query_proto_rawDescOnce sync.Once
+	file_query_query_proto_rawDescData []byte
+)
+
+func file_query_query_proto_rawDescGZIP() []byte {
+	file_query_query_proto_rawDescOnce.Do(func() {
+		file_query_query_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_query_query_proto_rawDesc), len(file_query_query_proto_rawDesc)))
+	})
+	return file_query_query_proto_rawDescData
+}
+
+var file_query_query_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_query_query_proto_goTypes = []any{
+	(*AllocationRequest)(nil),     // 0: query.AllocationRequest
+	(*AllocationSet)(nil),         // 1: query.AllocationSet
+	(*AllocationProperties)(nil),  // 2: query.AllocationProperties
+	(*Allocation)(nil),            // 3: query.Allocation
+	(*SharedCostBreakdown)(nil),   // 4: query.SharedCostBreakdown
+	(*AssetRequest)(nil),          // 5: query.AssetRequest
+	(*AssetSet)(nil),              // 6: query.AssetSet
+	(*AssetProperties)(nil),       // 7: query.AssetProperties
+	(*Asset)(nil),                 // 8: query.Asset
+	(*CloudCostRequest)(nil),      // 9: query.CloudCostRequest
+	(*CloudCostSetRange)(nil),     // 10: query.CloudCostSetRange
+	(*CloudCostSet)(nil),          // 11: query.CloudCostSet
+	(*CloudCostProperties)(nil),   // 12: query.CloudCostProperties
+	(*CostMetric)(nil),            // 13: query.CostMetric
+	(*CloudCost)(nil),             // 14: query.CloudCost
+	(*CustomCostRequest)(nil),     // 15: query.CustomCostRequest
+	(*CustomCostTimeseries)(nil),  // 16: query.CustomCostTimeseries
+	(*CustomCostSet)(nil),         // 17: query.CustomCostSet
+	(*CustomCost)(nil),            // 18: query.CustomCost
+	nil,                           // 19: query.AllocationProperties.LabelsEntry
+	nil,                           // 20: query.AllocationProperties.AnnotationsEntry
+	nil,                           // 21: query.AllocationProperties.NamespaceLabelsEntry
+	nil,                           // 22: query.AllocationProperties.NamespaceAnnotationsEntry
+	nil,                           // 23: query.Allocation.SharedCostBreakdownEntry
+	nil,                           // 24: query.Asset.LabelsEntry
+	nil,                           // 25: query.CloudCostProperties.LabelsEntry
+	nil,                           // 26: query.CustomCost.LabelsEntry
+	nil,                           // 27: query.CustomCost.ExtendedAttributesEntry
+	(*timestamppb.Timestamp)(nil), // 28: google.protobuf.Timestamp
+}
+var file_query_query_proto_depIdxs = []int32{
+	28, // 0: query.AllocationSet.start:type_name -> google.protobuf.Timestamp
+	28, // 1: query.AllocationSet.end:type_name -> google.protobuf.Timestamp
+	3,  // 2: query.AllocationSet.allocations:type_name -> query.Allocation
+	19, // 3: query.AllocationProperties.labels:type_name -> query.AllocationProperties.LabelsEntry
+	20, // 4: query.AllocationProperties.annotations:type_name -> query.AllocationProperties.AnnotationsEntry
+	21, // 5: query.AllocationProperties.namespace_labels:type_name -> query.AllocationProperties.NamespaceLabelsEntry
+	22, // 6: query.AllocationProperties.namespace_annotations:type_name -> query.AllocationProperties.NamespaceAnnotationsEntry
+	2,  // 7: query.Allocation.properties:type_name -> query.AllocationProperties
+	28, // 8: query.Allocation.start:type_name -> google.protobuf.Timestamp
+	28, // 9: query.Allocation.end:type_name -> google.protobuf.Timestamp
+	23, // 10: query.Allocation.shared_cost_breakdown:type_name -> query.Allocation.SharedCostBreakdownEntry
+	28, // 11: query.AssetSet.start:type_name -> google.protobuf.Timestamp
+	28, // 12: query.AssetSet.end:type_name -> google.protobuf.Timestamp
+	8,  // 13: query.AssetSet.assets:type_name -> query.Asset
+	7,  // 14: query.Asset.properties:type_name -> query.AssetProperties
+	24, // 15: query.Asset.labels:type_name -> query.Asset.LabelsEntry
+	28, // 16: query.Asset.start:type_name -> google.protobuf.Timestamp
+	28, // 17: query.Asset.end:type_name -> google.protobuf.Timestamp
+	11, // 18: query.CloudCostSetRange.sets:type_name -> query.CloudCostSet
+	28, // 19: query.CloudCostSet.start:type_name -> google.protobuf.Timestamp
+	28, // 20: query.CloudCostSet.end:type_name -> google.protobuf.Timestamp
+	14, // 21: query.CloudCostSet.cloud_costs:type_name -> query.CloudCost
+	25, // 22: query.CloudCostProperties.labels:type_name -> query.CloudCostProperties.LabelsEntry
+	12, // 23: query.CloudCost.properties:type_name -> query.CloudCostProperties
+	28, // 24: query.CloudCost.start:type_name -> google.protobuf.Timestamp
+	28, // 25: query.CloudCost.end:type_name -> google.protobuf.Timestamp
+	13, // 26: query.CloudCost.list_cost:type_name -> query.CostMetric
+	13, // 27: query.CloudCost.net_cost:type_name -> query.CostMetric
+	13, // 28: query.CloudCost.amortized_net_cost:type_name -> query.CostMetric
+	13, // 29: query.CloudCost.invoiced_cost:type_name -> query.CostMetric
+	13, // 30: query.CloudCost.amortized_cost:type_name -> query.CostMetric
+	28, // 31: query.CustomCostTimeseries.start:type_name -> google.protobuf.Timestamp
+	28, // 32: query.CustomCostTimeseries.end:type_name -> google.protobuf.Timestamp
+	17, // 33: query.CustomCostTimeseries.timeseries:type_name -> query.CustomCostSet
+	28, // 34: query.CustomCostSet.start:type_name -> google.protobuf.Timestamp
+	28, // 35: query.CustomCostSet.end:type_name -> google.protobuf.Timestamp
+	18, // 36: query.CustomCostSet.custom_costs:type_name -> query.CustomCost
+	26, // 37: query.CustomCost.labels:type_name -> query.CustomCost.LabelsEntry
+	27, // 38: query.CustomCost.extended_attributes:type_name -> query.CustomCost.ExtendedAttributesEntry
+	4,  // 39: query.Allocation.SharedCostBreakdownEntry.value:type_name -> query.SharedCostBreakdown
+	0,  // 40: query.QueryService.QueryAllocations:input_type -> query.AllocationRequest
+	5,  // 41: query.QueryService.QueryAssets:input_type -> query.AssetRequest
+	9,  // 42: query.QueryService.QueryCloudCosts:input_type -> query.CloudCostRequest
+	15, // 43: query.QueryService.QueryCustomCosts:input_type -> query.CustomCostRequest
+	1,  // 44: query.QueryService.QueryAllocations:output_type -> query.AllocationSet
+	6,  // 45: query.QueryService.QueryAssets:output_type -> query.AssetSet
+	10, // 46: query.QueryService.QueryCloudCosts:output_type -> query.CloudCostSetRange
+	16, // 47: query.QueryService.QueryCustomCosts:output_type -> query.CustomCostTimeseries
+	44, // [44:48] is the sub-list for method output_type
+	40, // [40:44] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
+}
+
+func init() { file_query_query_proto_init() }
+func file_query_query_proto_init() {
+	if File_query_query_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_query_query_proto_rawDesc), len(file_query_query_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_query_query_proto_goTypes,
+		DependencyIndexes: file_query_query_proto_depIdxs,
+		MessageInfos:      file_query_query_proto_msgTypes,
+	}.Build()
+	File_query_query_proto = out.File
+	file_query_query_proto_goTypes = nil
+	file_query_query_proto_depIdxs = nil
+}
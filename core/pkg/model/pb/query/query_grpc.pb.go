@@ -0,0 +1,257 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: query/query.proto
+
+package query
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	QueryService_QueryAllocations_FullMethodName = "/query.QueryService/QueryAllocations"
+	QueryService_QueryAssets_FullMethodName      = "/query.QueryService/QueryAssets"
+	QueryService_QueryCloudCosts_FullMethodName  = "/query.QueryService/QueryCloudCosts"
+	QueryService_QueryCustomCosts_FullMethodName = "/query.QueryService/QueryCustomCosts"
+)
+
+// QueryServiceClient is the client API for QueryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// QueryService exposes the OpenCost query APIs over gRPC. Requests accept the
+// same window, aggregate and filter expressions as the equivalent HTTP APIs.
+type QueryServiceClient interface {
+	// QueryAllocations streams one AllocationSet per step of the window, as
+	// each step is computed.
+	QueryAllocations(ctx context.Context, in *AllocationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AllocationSet], error)
+	// QueryAssets returns the assets of the window.
+	QueryAssets(ctx context.Context, in *AssetRequest, opts ...grpc.CallOption) (*AssetSet, error)
+	// QueryCloudCosts returns the cloud costs of the window.
+	QueryCloudCosts(ctx context.Context, in *CloudCostRequest, opts ...grpc.CallOption) (*CloudCostSetRange, error)
+	// QueryCustomCosts returns the custom costs of the window, per accumulated
+	// step.
+	QueryCustomCosts(ctx context.Context, in *CustomCostRequest, opts ...grpc.CallOption) (*CustomCostTimeseries, error)
+}
+
+type queryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQueryServiceClient(cc grpc.ClientConnInterface) QueryServiceClient {
+	return &queryServiceClient{cc}
+}
+
+func (c *queryServiceClient) QueryAllocations(ctx context.Context, in *AllocationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AllocationSet], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[0], QueryService_QueryAllocations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AllocationRequest, AllocationSet]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QueryService_QueryAllocationsClient = grpc.ServerStreamingClient[AllocationSet]
+
+func (c *queryServiceClient) QueryAssets(ctx context.Context, in *AssetRequest, opts ...grpc.CallOption) (*AssetSet, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssetSet)
+	err := c.cc.Invoke(ctx, QueryService_QueryAssets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) QueryCloudCosts(ctx context.Context, in *CloudCostRequest, opts ...grpc.CallOption) (*CloudCostSetRange, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloudCostSetRange)
+	err := c.cc.Invoke(ctx, QueryService_QueryCloudCosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *queryServiceClient) QueryCustomCosts(ctx context.Context, in *CustomCostRequest, opts ...grpc.CallOption) (*CustomCostTimeseries, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CustomCostTimeseries)
+	err := c.cc.Invoke(ctx, QueryService_QueryCustomCosts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QueryServiceServer is the server API for QueryService service.
+// All implementations must embed UnimplementedQueryServiceServer
+// for forward compatibility.
+//
+// QueryService exposes the OpenCost query APIs over gRPC. Requests accept the
+// same window, aggregate and filter expressions as the equivalent HTTP APIs.
+type QueryServiceServer interface {
+	// QueryAllocations streams one AllocationSet per step of the window, as
+	// each step is computed.
+	QueryAllocations(*AllocationRequest, grpc.ServerStreamingServer[AllocationSet]) error
+	// QueryAssets returns the assets of the window.
+	QueryAssets(context.Context, *AssetRequest) (*AssetSet, error)
+	// QueryCloudCosts returns the cloud costs of the window.
+	QueryCloudCosts(context.Context, *CloudCostRequest) (*CloudCostSetRange, error)
+	// QueryCustomCosts returns the custom costs of the window, per accumulated
+	// step.
+	QueryCustomCosts(context.Context, *CustomCostRequest) (*CustomCostTimeseries, error)
+	mustEmbedUnimplementedQueryServiceServer()
+}
+
+// UnimplementedQueryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedQueryServiceServer struct{}
+
+func (UnimplementedQueryServiceServer) QueryAllocations(*AllocationRequest, grpc.ServerStreamingServer[AllocationSet]) error {
+	return status.Errorf(codes.Unimplemented, "method QueryAllocations not implemented")
+}
+func (UnimplementedQueryServiceServer) QueryAssets(context.Context, *AssetRequest) (*AssetSet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAssets not implemented")
+}
+func (UnimplementedQueryServiceServer) QueryCloudCosts(context.Context, *CloudCostRequest) (*CloudCostSetRange, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryCloudCosts not implemented")
+}
+func (UnimplementedQueryServiceServer) QueryCustomCosts(context.Context, *CustomCostRequest) (*CustomCostTimeseries, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryCustomCosts not implemented")
+}
+func (UnimplementedQueryServiceServer) mustEmbedUnimplementedQueryServiceServer() {}
+func (UnimplementedQueryServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeQueryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QueryServiceServer will
+// result in compilation errors.
+type UnsafeQueryServiceServer interface {
+	mustEmbedUnimplementedQueryServiceServer()
+}
+
+func RegisterQueryServiceServer(s grpc.ServiceRegistrar, srv QueryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedQueryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&QueryService_ServiceDesc, srv)
+}
+
+func _QueryService_QueryAllocations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AllocationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).QueryAllocations(m, &grpc.GenericServerStream[AllocationRequest, AllocationSet]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type QueryService_QueryAllocationsServer = grpc.ServerStreamingServer[AllocationSet]
+
+func _QueryService_QueryAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).QueryAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_QueryAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).QueryAssets(ctx, req.(*AssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_QueryCloudCosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloudCostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).QueryCloudCosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_QueryCloudCosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).QueryCloudCosts(ctx, req.(*CloudCostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QueryService_QueryCustomCosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CustomCostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).QueryCustomCosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_QueryCustomCosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).QueryCustomCosts(ctx, req.(*CustomCostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// QueryService_ServiceDesc is the grpc.ServiceDesc for QueryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var QueryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "query.QueryService",
+	HandlerType: (*QueryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "QueryAssets",
+			Handler:    _QueryService_QueryAssets_Handler,
+		},
+		{
+			MethodName: "QueryCloudCosts",
+			Handler:    _QueryService_QueryCloudCosts_Handler,
+		},
+		{
+			MethodName: "QueryCustomCosts",
+			Handler:    _QueryService_QueryCustomCosts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "QueryAllocations",
+			Handler:       _QueryService_QueryAllocations_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "query/query.proto",
+}
@@ -1,6 +1,7 @@
 package opencost
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sort"
@@ -234,6 +235,86 @@ type RawAllocationOnlyData struct {
 	CPUCoreUsageMax  float64  `json:"cpuCoreUsageMax"`
 	RAMBytesUsageMax float64  `json:"ramByteUsageMax"`
 	GPUUsageMax      *float64 `json:"gpuUsageMax"` //@bingen:field[version=23]
+
+	// Usage percentiles are, like usage maximums, only meaningful for a single
+	// container, so are only available on raw allocations.
+	CPUCoreUsagePercentiles  *UsagePercentiles `json:"cpuCoreUsagePercentiles,omitempty"` //@bingen:field[version=25]
+	RAMBytesUsagePercentiles *UsagePercentiles `json:"ramByteUsagePercentiles,omitempty"` //@bingen:field[version=25]
+}
+
+// UsagePercentiles are the percentiles of a resource's usage over the window
+// of an allocation, which are used to size requests where the average
+// underestimates and the maximum overestimates what a workload needs.
+type UsagePercentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+// Set sets the percentile for the given quantile, returning false if the
+// quantile is not one of 0.5, 0.9, 0.95 or 0.99.
+func (up *UsagePercentiles) Set(quantile float64, value float64) bool {
+	switch quantile {
+	case 0.5:
+		up.P50 = value
+	case 0.9:
+		up.P90 = value
+	case 0.95:
+		up.P95 = value
+	case 0.99:
+		up.P99 = value
+	default:
+		return false
+	}
+	return true
+}
+
+// Clone returns a copy of the given UsagePercentiles
+func (up *UsagePercentiles) Clone() *UsagePercentiles {
+	if up == nil {
+		return nil
+	}
+
+	clone := *up
+	return &clone
+}
+
+// Equal returns true if the UsagePercentiles are approximately equal
+func (up *UsagePercentiles) Equal(that *UsagePercentiles) bool {
+	if up == nil && that == nil {
+		return true
+	}
+	if up == nil || that == nil {
+		return false
+	}
+	return util.IsApproximately(up.P50, that.P50) &&
+		util.IsApproximately(up.P90, that.P90) &&
+		util.IsApproximately(up.P95, that.P95) &&
+		util.IsApproximately(up.P99, that.P99)
+}
+
+// Max sets each percentile to the larger of it and the matching percentile of
+// that. Percentiles of separate windows cannot be combined exactly, so the max
+// is used as a conservative estimate for sizing.
+func (up *UsagePercentiles) Max(that *UsagePercentiles) {
+	if up == nil || that == nil {
+		return
+	}
+	up.P50 = math.Max(up.P50, that.P50)
+	up.P90 = math.Max(up.P90, that.P90)
+	up.P95 = math.Max(up.P95, that.P95)
+	up.P99 = math.Max(up.P99, that.P99)
+}
+
+func (up *UsagePercentiles) sanitizeNaN(name string) {
+	if up == nil {
+		return
+	}
+	if math.IsNaN(up.P50) || math.IsNaN(up.P90) || math.IsNaN(up.P95) || math.IsNaN(up.P99) {
+		log.DedupedWarningf(5, "RawAllocationOnlyData: Unexpected NaN found for %s", name)
+		*up = UsagePercentiles{}
+	}
 }
 
 // Clone returns a deep copy of the given RawAllocationOnlyData
@@ -246,6 +327,9 @@ func (r *RawAllocationOnlyData) Clone() *RawAllocationOnlyData {
 		CPUCoreUsageMax:  r.CPUCoreUsageMax,
 		RAMBytesUsageMax: r.RAMBytesUsageMax,
 		GPUUsageMax:      r.GPUUsageMax,
+
+		CPUCoreUsagePercentiles:  r.CPUCoreUsagePercentiles.Clone(),
+		RAMBytesUsagePercentiles: r.RAMBytesUsagePercentiles.Clone(),
 	}
 }
 
@@ -266,6 +350,9 @@ func (r *RawAllocationOnlyData) Equal(that *RawAllocationOnlyData) bool {
 		cmpResult = false
 	}
 
+	cmpResult = cmpResult && r.CPUCoreUsagePercentiles.Equal(that.CPUCoreUsagePercentiles) &&
+		r.RAMBytesUsagePercentiles.Equal(that.RAMBytesUsagePercentiles)
+
 	return cmpResult
 }
 
@@ -285,6 +372,8 @@ func (r *RawAllocationOnlyData) SanitizeNaN() {
 		log.DedupedWarningf(5, "RawAllocationOnlyData: Unexpected NaN found for GPUUsageMax")
 		r.GPUUsageMax = nil
 	}
+	r.CPUCoreUsagePercentiles.sanitizeNaN("CPUCoreUsagePercentiles")
+	r.RAMBytesUsagePercentiles.sanitizeNaN("RAMBytesUsagePercentiles")
 }
 
 // PVAllocations is a map of Disk Asset Identifiers to the
@@ -1518,6 +1607,7 @@ type AllocationAggregationOptions struct {
 	IncludeSharedCostBreakdown            bool
 	SplitIdle                             bool
 	IncludeAggregatedMetadata             bool
+	SharingRules                          []*SharingRule
 }
 
 func isFilterEmpty(filter AllocationMatcher) bool {
@@ -1619,6 +1709,11 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 		}
 	}
 
+	sharingRules, err := compileSharingRules(options.SharingRules, options.LabelConfig, as.Window)
+	if err != nil {
+		return err
+	}
+
 	var allocatedTotalsMap map[string]map[string]float64
 
 	// If aggregateBy is nil, we don't aggregate anything. On the other hand,
@@ -1626,7 +1721,7 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 	// generateKey for why that makes sense.
 	shouldAggregate := aggregateBy != nil
 	shouldFilter := !isFilterEmpty(filter)
-	shouldShare := len(options.SharedHourlyCosts) > 0 || sharer != nil
+	shouldShare := len(options.SharedHourlyCosts) > 0 || sharer != nil || len(sharingRules) > 0
 	if !shouldAggregate && !shouldFilter && !shouldShare && options.ShareIdle == ShareNone && !options.IncludeProportionalAssetResourceCosts {
 		// There is nothing for AggregateBy to do, so simply return nil
 		// before returning, set aggregated metadata inclusion in properties
@@ -1712,6 +1807,14 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 			shareSet.Insert(alloc)
 			continue
 		}
+
+		// Allocations matching the source of a sharing rule are moved to
+		// that rule's share set, to be distributed among its targets.
+		if rule := matchSharingRule(sharingRules, alloc); rule != nil {
+			delete(as.Allocations, alloc.Name)
+			rule.shareSet.Insert(alloc)
+			continue
+		}
 	}
 
 	// It's possible that no more un-shared, non-idle, non-external allocations
@@ -1751,14 +1854,29 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 	// coefficients are computed for the full set of allocations prior to
 	// adding shared overhead and prior to applying filters.
 
-	var err error
+	// Idle coefficients are computed over every shared allocation, whether
+	// shared by the sharer or by a sharing rule.
+	idleShareSet := shareSet
+	if len(sharingRules) > 0 {
+		idleShareSet = &AllocationSet{
+			Window: as.Window.Clone(),
+		}
+		for _, alloc := range shareSet.Allocations {
+			idleShareSet.Insert(alloc)
+		}
+		for _, rule := range sharingRules {
+			for _, alloc := range rule.shareSet.Allocations {
+				idleShareSet.Insert(alloc)
+			}
+		}
+	}
 
 	// (2a) If there are idle costs to be shared, compute the coefficients for
 	// sharing them among the non-idle, non-aggregated allocations (including
 	// the shared allocations).
 	var idleCoefficients map[string]map[string]map[string]float64
 	if idleSet.Length() > 0 && options.ShareIdle != ShareNone {
-		idleCoefficients, allocatedTotalsMap, err = computeIdleCoeffs(options, as, shareSet)
+		idleCoefficients, allocatedTotalsMap, err = computeIdleCoeffs(options, as, idleShareSet)
 		if err != nil {
 			log.Warnf("AllocationSet.AggregateBy: compute idle coeff: %s", err)
 			return fmt.Errorf("error computing idle coefficients: %s", err)
@@ -1768,7 +1886,7 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 	// (2b) If proportional asset resource costs are to be included, compute them
 	// and add them to the allocations.
 	if options.IncludeProportionalAssetResourceCosts {
-		err := deriveProportionalAssetResourceCosts(options, as, shareSet, parcSet)
+		err := deriveProportionalAssetResourceCosts(options, as, idleShareSet, parcSet)
 		if err != nil {
 			log.Debugf("AggregateBy: failed to derive proportional asset resource costs from idle coefficients: %s", err)
 			return fmt.Errorf("AggregateBy: failed to derive proportional asset resource costs from idle coefficients: %s", err)
@@ -1801,7 +1919,7 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 	// need to track this on a per-cluster or per-node, per-allocation, per-resource basis.
 	var idleFiltrationCoefficients map[string]map[string]map[string]float64
 	if shouldFilter && options.ShareIdle == ShareNone {
-		idleFiltrationCoefficients, _, err = computeIdleCoeffs(options, as, shareSet)
+		idleFiltrationCoefficients, _, err = computeIdleCoeffs(options, as, idleShareSet)
 		if err != nil {
 			return fmt.Errorf("error computing idle filtration coefficients: %s", err)
 		}
@@ -1843,6 +1961,17 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 		}
 	}
 
+	// (2f) Likewise, convert the fixed hourly costs of each sharing rule to
+	// shared allocations and compute the rule's share coefficients over its
+	// targets.
+	ruleShareCoefficients := make([]map[string]float64, len(sharingRules))
+	for i, rule := range sharingRules {
+		rule.insertHourlyCost(as)
+		if rule.shareSet.Length() > 0 {
+			ruleShareCoefficients[i] = rule.computeCoeffs(aggregateBy, options, filter, as)
+		}
+	}
+
 	// (3-5) Filter, distribute idle cost, and aggregate (in that order)
 	for _, alloc := range as.Allocations {
 		idleId, err := alloc.getIdleId(options)
@@ -1925,8 +2054,8 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 	// amount of idle cost will be shared with a shared resource. Distribute
 	// that idle allocation, if it exists, to the respective shared allocations
 	// before sharing with the aggregated allocations.
-	if idleSet.Length() > 0 && shareSet.Length() > 0 {
-		for _, alloc := range shareSet.Allocations {
+	if idleSet.Length() > 0 && idleShareSet.Length() > 0 {
+		for _, alloc := range idleShareSet.Allocations {
 			idleId, err := alloc.getIdleId(options)
 			if err != nil {
 				log.DedupedWarningf(3, "AllocationSet.AggregateBy: missing idleId for allocation: %s", alloc.Name)
@@ -2059,6 +2188,18 @@ func (as *AllocationSet) AggregateBy(aggregateBy []string, options *AllocationAg
 		}
 	}
 
+	// Distribute the shared costs of each sharing rule according to the
+	// rule's share coefficients.
+	for i, rule := range sharingRules {
+		if rule.shareSet.Length() == 0 {
+			continue
+		}
+
+		for _, alloc := range aggSet.Allocations {
+			rule.distribute(alloc, ruleShareCoefficients[i], options)
+		}
+	}
+
 	// (9) Aggregate external allocations into aggregated allocations. This may
 	// not be possible for every external allocation, but attempt to find an
 	// exact key match, given each external allocation's proerties, and
@@ -3426,9 +3567,20 @@ func (asr *AllocationSetRange) accumulateByWeek() (*AllocationSetRange, error) {
 // AggregateBy aggregates each AllocationSet in the range by the given
 // properties and options.
 func (asr *AllocationSetRange) AggregateBy(aggregateBy []string, options *AllocationAggregationOptions) error {
+	return asr.AggregateByContext(context.Background(), aggregateBy, options)
+}
+
+// AggregateByContext aggregates each AllocationSet in the range by the given
+// properties and options, returning the context's error if it is cancelled
+// before every set has been aggregated.
+func (asr *AllocationSetRange) AggregateByContext(ctx context.Context, aggregateBy []string, options *AllocationAggregationOptions) error {
 	aggRange := &AllocationSetRange{Allocations: []*AllocationSet{}}
 
 	for _, as := range asr.Allocations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		err := as.AggregateBy(aggregateBy, options)
 		if err != nil {
 			return err
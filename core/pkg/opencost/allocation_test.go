@@ -1,6 +1,8 @@
 package opencost
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
@@ -2782,6 +2784,23 @@ func TestAllocationSetRange_AccumulateBy_Month(t *testing.T) {
 // TODO niko
 // func TestAllocationSetRange_AggregateBy(t *testing.T) {}
 
+func TestAllocationSetRange_AggregateByContext_Canceled(t *testing.T) {
+	yesterday := time.Now().UTC().Truncate(day).Add(-day)
+	today := time.Now().UTC().Truncate(day)
+
+	as := NewAllocationSet(yesterday, today)
+	as.Set(NewMockUnitAllocation("a", yesterday, day, nil))
+	asr := NewAllocationSetRange(as)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := asr.AggregateByContext(ctx, []string{AllocationNamespaceProp}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AggregateByContext with a cancelled context: expected %s; actual %v", context.Canceled, err)
+	}
+}
+
 // TODO niko
 // func TestAllocationSetRange_Append(t *testing.T) {}
 
@@ -3809,6 +3828,18 @@ func TestRawAllocationOnlyData_SanitizeNaN(t *testing.T) {
 		t.Fatalf("want: 0.0, got: %v", nilRawAllocation.RAMBytesUsageMax)
 	}
 
+	// SanitizeNaN zeroes percentiles if any is NaN
+	percentiles := &RawAllocationOnlyData{
+		CPUCoreUsagePercentiles:  &UsagePercentiles{P50: 0.5, P90: nan, P95: 1, P99: 2},
+		RAMBytesUsagePercentiles: &UsagePercentiles{P50: 1, P90: 2, P95: 3, P99: 4},
+	}
+	percentiles.SanitizeNaN()
+	if *percentiles.CPUCoreUsagePercentiles != (UsagePercentiles{}) {
+		t.Fatalf("want: zero percentiles, got: %v", percentiles.CPUCoreUsagePercentiles)
+	}
+	if *percentiles.RAMBytesUsagePercentiles != (UsagePercentiles{P50: 1, P90: 2, P95: 3, P99: 4}) {
+		t.Fatalf("want: unchanged percentiles, got: %v", percentiles.RAMBytesUsagePercentiles)
+	}
 }
 
 func getMockRawAllocationOnlyData(f float64) *RawAllocationOnlyData {
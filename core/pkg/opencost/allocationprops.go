@@ -190,6 +190,9 @@ type AllocationProperties struct {
 	// When set to true, maintain the intersection of all labels + annotations
 	// in the aggregated AllocationProperties object
 	AggregatedMetadata bool `json:"-"` //@bingen:field[ignore]
+	// CostCenters holds the cost center of each level of a CostCenterMapping,
+	// by which the allocation can be aggregated
+	CostCenters map[string]string `json:"-"` //@bingen:field[ignore]
 }
 
 // AllocationLabels is a schema-free mapping of key/value pairs that can be
@@ -244,6 +247,7 @@ func (p *AllocationProperties) Clone() *AllocationProperties {
 	clone.NamespaceAnnotations = nsAnnotations
 
 	clone.AggregatedMetadata = p.AggregatedMetadata
+	clone.CostCenters = cloneCostCenters(p.CostCenters)
 	return clone
 }
 
@@ -449,6 +453,8 @@ func (p *AllocationProperties) GenerateKey(aggregateBy []string, labelConfig *La
 					names = append(names, UnallocatedSuffix)
 				}
 			}
+		case strings.HasPrefix(agg, CostCenterPropPrefix):
+			names = append(names, costCenterValue(p.CostCenters, agg))
 		case agg == AllocationDepartmentProp:
 			labels := p.Labels
 			annotations := p.Annotations
@@ -620,6 +626,9 @@ func (p *AllocationProperties) Intersection(that *AllocationProperties) *Allocat
 	if p.ProviderID == that.ProviderID {
 		intersectionProps.ProviderID = p.ProviderID
 	}
+	if p.CostCenters != nil && that.CostCenters != nil {
+		intersectionProps.CostCenters = mapIntersection(p.CostCenters, that.CostCenters)
+	}
 
 	return intersectionProps
 }
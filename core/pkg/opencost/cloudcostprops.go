@@ -90,6 +90,11 @@ func ParseCloudCostProperty(text string) (CloudCostProperty, error) {
 		return CloudCostProperty(fmt.Sprintf("label:%s", label)), nil
 	}
 
+	if strings.HasPrefix(text, CostCenterPropPrefix) {
+		level := strings.TrimSpace(strings.TrimPrefix(text, CostCenterPropPrefix))
+		return CloudCostProperty(CostCenterPropPrefix + level), nil
+	}
+
 	return "", fmt.Errorf("invalid cloud cost property: %s", text)
 }
 
@@ -175,6 +180,9 @@ type CloudCostProperties struct {
 	Service           string          `json:"service,omitempty"`
 	Category          string          `json:"category,omitempty"`
 	Labels            CloudCostLabels `json:"labels,omitempty"`
+	// CostCenters holds the cost center of each level of a CostCenterMapping,
+	// by which the cloud cost can be aggregated
+	CostCenters map[string]string `json:"-"` //@bingen:field[ignore]
 }
 
 func (ccp *CloudCostProperties) Equal(that *CloudCostProperties) bool {
@@ -204,6 +212,7 @@ func (ccp *CloudCostProperties) Clone() *CloudCostProperties {
 		Service:           ccp.Service,
 		Category:          ccp.Category,
 		Labels:            ccp.Labels.Clone(),
+		CostCenters:       cloneCostCenters(ccp.CostCenters),
 	}
 }
 
@@ -252,6 +261,9 @@ func (ccp *CloudCostProperties) Intersection(that *CloudCostProperties) *CloudCo
 		intersectionCCP.Category = ccp.Category
 	}
 	intersectionCCP.Labels = ccp.Labels.Intersection(that.Labels)
+	if ccp.CostCenters != nil && that.CostCenters != nil {
+		intersectionCCP.CostCenters = mapIntersection(ccp.CostCenters, that.CostCenters)
+	}
 
 	return intersectionCCP
 }
@@ -322,6 +334,8 @@ func (ccp *CloudCostProperties) GenerateKeys(props []string) []string {
 					propVal = labelValue
 				}
 			}
+		case strings.HasPrefix(prop, CostCenterPropPrefix):
+			propVal = costCenterValue(ccp.CostCenters, prop)
 		default:
 			// This case should never be reached, as input up until this point
 			// should be checked and rejected if invalid. But if we do get a
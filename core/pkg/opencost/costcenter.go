@@ -0,0 +1,267 @@
+package opencost
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CostCenterPropPrefix prefixes the aggregation properties which aggregate by
+// a level of the cost center hierarchy, e.g. "costcenter:department".
+const CostCenterPropPrefix = "costcenter:"
+
+// CostCenterUnassigned is the cost center of allocations and cloud costs which
+// are not mapped to a unit at the aggregated level.
+const CostCenterUnassigned = "__unassigned__"
+
+// CostCenterConfig describes a tree of organizational units, and the ordered
+// rules which map allocations and cloud costs onto them.
+type CostCenterConfig struct {
+	// Levels are the levels of the hierarchy, from the root down, e.g.
+	// ["company", "department", "team"].
+	Levels []string          `json:"levels"`
+	Units  []CostCenterUnit  `json:"units"`
+	Rules  []*CostCenterRule `json:"rules"`
+}
+
+// CostCenterUnit is an organizational unit at one level of the hierarchy.
+type CostCenterUnit struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+	// Parent is the name of the unit's parent, which must be at a higher
+	// level. Empty for root units.
+	Parent string `json:"parent,omitempty"`
+}
+
+// CostCenterRule maps the allocations or cloud costs matching all of its
+// conditions to a unit. Namespace, Labels and Annotations match allocations,
+// and Tags match the labels of cloud costs.
+type CostCenterRule struct {
+	Unit        string            `json:"unit"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func (ccr *CostCenterRule) matchesAllocation(p *AllocationProperties) bool {
+	if ccr.Namespace == "" && len(ccr.Labels) == 0 && len(ccr.Annotations) == 0 {
+		return false
+	}
+
+	if ccr.Namespace != "" && ccr.Namespace != p.Namespace {
+		return false
+	}
+
+	for k, v := range ccr.Labels {
+		if value, ok := p.Labels[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	for k, v := range ccr.Annotations {
+		if value, ok := p.Annotations[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ccr *CostCenterRule) matchesCloudCost(p *CloudCostProperties) bool {
+	if len(ccr.Tags) == 0 {
+		return false
+	}
+
+	for k, v := range ccr.Tags {
+		if value, ok := p.Labels[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CostCenterMapping is a validated CostCenterConfig, which assigns allocations
+// and cloud costs their cost center at each level of the hierarchy, so that
+// they can be aggregated by CostCenterPropPrefix properties.
+type CostCenterMapping struct {
+	levels []string
+	// paths holds, for each unit, the names of the unit and its ancestors
+	// keyed by level
+	paths map[string]map[string]string
+	rules []*CostCenterRule
+}
+
+// NewCostCenterMapping validates the given config and returns a
+// CostCenterMapping.
+func NewCostCenterMapping(config *CostCenterConfig) (*CostCenterMapping, error) {
+	if config == nil {
+		return nil, fmt.Errorf("cost center config is nil")
+	}
+
+	if len(config.Levels) == 0 {
+		return nil, fmt.Errorf("cost center config requires at least one level")
+	}
+
+	levelIndex := make(map[string]int, len(config.Levels))
+	for i, level := range config.Levels {
+		if level == "" {
+			return nil, fmt.Errorf("cost center level %d: missing name", i)
+		}
+		if _, ok := levelIndex[level]; ok {
+			return nil, fmt.Errorf("duplicate cost center level: %s", level)
+		}
+		levelIndex[level] = i
+	}
+
+	units := make(map[string]CostCenterUnit, len(config.Units))
+	for i, unit := range config.Units {
+		if unit.Name == "" {
+			return nil, fmt.Errorf("cost center unit %d: missing name", i)
+		}
+		if _, ok := units[unit.Name]; ok {
+			return nil, fmt.Errorf("duplicate cost center unit: %s", unit.Name)
+		}
+		if _, ok := levelIndex[unit.Level]; !ok {
+			return nil, fmt.Errorf("cost center unit %s: unknown level: %s", unit.Name, unit.Level)
+		}
+		units[unit.Name] = unit
+	}
+
+	// Parents are at a strictly higher level than their children, so walking
+	// up from any unit terminates.
+	paths := make(map[string]map[string]string, len(units))
+	for name, unit := range units {
+		path := map[string]string{unit.Level: name}
+		for unit.Parent != "" {
+			parent, ok := units[unit.Parent]
+			if !ok {
+				return nil, fmt.Errorf("cost center unit %s: unknown parent: %s", unit.Name, unit.Parent)
+			}
+			if levelIndex[parent.Level] >= levelIndex[unit.Level] {
+				return nil, fmt.Errorf("cost center unit %s: parent %s must be at a higher level", unit.Name, parent.Name)
+			}
+			path[parent.Level] = parent.Name
+			unit = parent
+		}
+		paths[name] = path
+	}
+
+	for i, rule := range config.Rules {
+		if rule == nil {
+			return nil, fmt.Errorf("cost center rule %d: nil rule", i)
+		}
+		if _, ok := units[rule.Unit]; !ok {
+			return nil, fmt.Errorf("cost center rule %d: unknown unit: %s", i, rule.Unit)
+		}
+		if rule.Namespace == "" && len(rule.Labels) == 0 && len(rule.Annotations) == 0 && len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("cost center rule %d: no conditions", i)
+		}
+	}
+
+	return &CostCenterMapping{
+		levels: append([]string{}, config.Levels...),
+		paths:  paths,
+		rules:  config.Rules,
+	}, nil
+}
+
+// Levels returns the levels of the hierarchy, from the root down.
+func (ccm *CostCenterMapping) Levels() []string {
+	if ccm == nil {
+		return nil
+	}
+	return append([]string{}, ccm.levels...)
+}
+
+// AllocationCostCenter returns the unit to which the first matching rule maps
+// the given properties, and whether any rule matched.
+func (ccm *CostCenterMapping) AllocationCostCenter(p *AllocationProperties) (string, bool) {
+	if ccm == nil || p == nil {
+		return "", false
+	}
+
+	for _, rule := range ccm.rules {
+		if rule.matchesAllocation(p) {
+			return rule.Unit, true
+		}
+	}
+
+	return "", false
+}
+
+// CloudCostCostCenter returns the unit to which the first matching rule maps
+// the given properties, and whether any rule matched.
+func (ccm *CostCenterMapping) CloudCostCostCenter(p *CloudCostProperties) (string, bool) {
+	if ccm == nil || p == nil {
+		return "", false
+	}
+
+	for _, rule := range ccm.rules {
+		if rule.matchesCloudCost(p) {
+			return rule.Unit, true
+		}
+	}
+
+	return "", false
+}
+
+// AssignAllocations records the cost center of each allocation of the given
+// set at each level of the hierarchy in its properties. Idle, shared and
+// unmounted allocations are not assigned a cost center, and so are aggregated
+// as CostCenterUnassigned.
+func (ccm *CostCenterMapping) AssignAllocations(as *AllocationSet) {
+	if ccm == nil || as == nil {
+		return
+	}
+
+	for _, alloc := range as.Allocations {
+		if alloc == nil || alloc.Properties == nil || alloc.IsIdle() || alloc.IsUnmounted() {
+			continue
+		}
+
+		unit, ok := ccm.AllocationCostCenter(alloc.Properties)
+		if !ok {
+			continue
+		}
+
+		alloc.Properties.CostCenters = copyStringMap(ccm.paths[unit])
+	}
+}
+
+// AssignCloudCost returns a clone of the given CloudCost, with its cost center
+// at each level of the hierarchy recorded in its properties, or the CloudCost
+// itself if no rule matches.
+func (ccm *CostCenterMapping) AssignCloudCost(cc *CloudCost) *CloudCost {
+	if ccm == nil || cc == nil || cc.Properties == nil {
+		return cc
+	}
+
+	unit, ok := ccm.CloudCostCostCenter(cc.Properties)
+	if !ok {
+		return cc
+	}
+
+	assigned := cc.Clone()
+	assigned.Properties.CostCenters = copyStringMap(ccm.paths[unit])
+
+	return assigned
+}
+
+// cloneCostCenters copies the given cost centers, preserving nil
+func cloneCostCenters(costCenters map[string]string) map[string]string {
+	if costCenters == nil {
+		return nil
+	}
+	return copyStringMap(costCenters)
+}
+
+// costCenterValue returns the cost center recorded in the given cost centers
+// for the level of the given CostCenterPropPrefix property.
+func costCenterValue(costCenters map[string]string, prop string) string {
+	level := strings.TrimPrefix(prop, CostCenterPropPrefix)
+	if value, ok := costCenters[level]; ok && value != "" {
+		return value
+	}
+	return CostCenterUnassigned
+}
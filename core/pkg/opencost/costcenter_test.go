@@ -0,0 +1,141 @@
+package opencost
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCostCenterMapping(t *testing.T) *CostCenterMapping {
+	ccm, err := NewCostCenterMapping(&CostCenterConfig{
+		Levels: []string{"company", "department", "team"},
+		Units: []CostCenterUnit{
+			{Name: "acme", Level: "company"},
+			{Name: "engineering", Level: "department", Parent: "acme"},
+			{Name: "payments", Level: "team", Parent: "engineering"},
+			{Name: "search", Level: "team", Parent: "engineering"},
+			{Name: "sales", Level: "department", Parent: "acme"},
+		},
+		Rules: []*CostCenterRule{
+			{Unit: "payments", Namespace: "payments"},
+			{Unit: "search", Labels: map[string]string{"team": "search"}},
+			{Unit: "sales", Annotations: map[string]string{"owner": "sales"}},
+			{Unit: "search", Tags: map[string]string{"Owner": "search-team"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return ccm
+}
+
+func TestCostCenterMapping_AggregateBy(t *testing.T) {
+	ccm := newTestCostCenterMapping(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	as := NewAllocationSet(start, start.Add(day))
+	as.Set(NewMockUnitAllocation("payments", start, day, &AllocationProperties{Namespace: "payments"}))
+	as.Set(NewMockUnitAllocation("search", start, day, &AllocationProperties{Namespace: "default", Labels: AllocationLabels{"team": "search"}}))
+	as.Set(NewMockUnitAllocation("sales", start, day, &AllocationProperties{Namespace: "crm", Annotations: AllocationAnnotations{"owner": "sales"}}))
+	as.Set(NewMockUnitAllocation("other", start, day, &AllocationProperties{Namespace: "other"}))
+
+	ccm.AssignAllocations(as)
+
+	search := as.Get("search").Properties
+	if cc := search.CostCenters["department"]; cc != "engineering" {
+		t.Errorf("expected search department engineering, got %s", cc)
+	}
+	if len(search.Labels) != 1 {
+		t.Errorf("expected search labels to be unmodified, got %v", search.Labels)
+	}
+
+	testCases := map[string]map[string]float64{
+		"company":    {"acme": 18.0, CostCenterUnassigned: 6.0},
+		"department": {"engineering": 12.0, "sales": 6.0, CostCenterUnassigned: 6.0},
+		"team":       {"payments": 6.0, "search": 6.0, CostCenterUnassigned: 12.0},
+	}
+
+	for level, expected := range testCases {
+		t.Run(level, func(t *testing.T) {
+			aggSet := as.Clone()
+			err := aggSet.AggregateBy([]string{CostCenterPropPrefix + level}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if aggSet.Length() != len(expected) {
+				t.Fatalf("expected %d allocations, got %d", len(expected), aggSet.Length())
+			}
+
+			for name, cost := range expected {
+				alloc := aggSet.Get(name)
+				if alloc == nil {
+					t.Fatalf("missing allocation %s", name)
+				}
+				if alloc.TotalCost() != cost {
+					t.Errorf("expected %s total cost %f, got %f", name, cost, alloc.TotalCost())
+				}
+			}
+
+			// cost centers are internal to aggregation
+			b, err := json.Marshal(aggSet.Get(CostCenterUnassigned).Properties)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if strings.Contains(string(b), "acme") || strings.Contains(string(b), "costCenters") {
+				t.Errorf("expected cost centers not to be serialized, got %s", b)
+			}
+		})
+	}
+}
+
+func TestCostCenterMapping_AssignCloudCost(t *testing.T) {
+	ccm := newTestCostCenterMapping(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(day)
+
+	tagged := NewCloudCost(start, end, &CloudCostProperties{ProviderID: "a", Labels: CloudCostLabels{"Owner": "search-team"}}, 0, 10, 10, 10, 10, 10)
+	untagged := NewCloudCost(start, end, &CloudCostProperties{ProviderID: "b"}, 0, 5, 5, 5, 5, 5)
+
+	ccs := NewCloudCostSet(start, end)
+	ccs.AggregationProperties = []string{CostCenterPropPrefix + "department"}
+	ccs.Insert(ccm.AssignCloudCost(tagged))
+	ccs.Insert(ccm.AssignCloudCost(untagged))
+
+	if tagged.Properties.CostCenters != nil {
+		t.Errorf("expected original cloud cost to be unmodified")
+	}
+
+	if cc, ok := ccs.CloudCosts["engineering"]; !ok || cc.NetCost.Cost != 10 {
+		t.Errorf("expected engineering cloud cost of 10, got %v", ccs.CloudCosts)
+	}
+	if cc, ok := ccs.CloudCosts[CostCenterUnassigned]; !ok || cc.NetCost.Cost != 5 {
+		t.Errorf("expected unassigned cloud cost of 5, got %v", ccs.CloudCosts)
+	}
+}
+
+func TestNewCostCenterMapping_Invalid(t *testing.T) {
+	testCases := map[string]*CostCenterConfig{
+		"nil config":     nil,
+		"no levels":      {},
+		"unknown level":  {Levels: []string{"team"}, Units: []CostCenterUnit{{Name: "a", Level: "department"}}},
+		"unknown parent": {Levels: []string{"team"}, Units: []CostCenterUnit{{Name: "a", Level: "team", Parent: "b"}}},
+		"parent level": {
+			Levels: []string{"department", "team"},
+			Units:  []CostCenterUnit{{Name: "a", Level: "department", Parent: "b"}, {Name: "b", Level: "team"}},
+		},
+		"unknown unit":  {Levels: []string{"team"}, Rules: []*CostCenterRule{{Unit: "a", Namespace: "a"}}},
+		"no conditions": {Levels: []string{"team"}, Units: []CostCenterUnit{{Name: "a", Level: "team"}}, Rules: []*CostCenterRule{{Unit: "a"}}},
+	}
+
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewCostCenterMapping(config)
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
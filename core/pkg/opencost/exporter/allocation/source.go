@@ -1,6 +1,7 @@
 package allocation
 
 import (
+	"context"
 	"time"
 
 	"github.com/opencost/opencost/core/pkg/exporter"
@@ -9,7 +10,7 @@ import (
 )
 
 type AllocationSource interface {
-	ComputeAllocation(start, end time.Time) (*opencost.AllocationSet, error)
+	ComputeAllocation(ctx context.Context, start, end time.Time) (*opencost.AllocationSet, error)
 }
 
 type AllocationComputeSource struct {
@@ -34,7 +35,8 @@ func (acs *AllocationComputeSource) CanCompute(start, end time.Time) bool {
 
 // Compute should compute a single T for the given time range.
 func (acs *AllocationComputeSource) Compute(start, end time.Time) (*opencost.AllocationSet, error) {
-	return acs.src.ComputeAllocation(start, end)
+	// exports run on a schedule rather than on behalf of a request, so there is no request to cancel them
+	return acs.src.ComputeAllocation(context.Background(), start, end)
 }
 
 // Name returns the name of the ComputeSource
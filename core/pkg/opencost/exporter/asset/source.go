@@ -1,6 +1,7 @@
 package asset
 
 import (
+	"context"
 	"time"
 
 	"github.com/opencost/opencost/core/pkg/exporter"
@@ -9,7 +10,7 @@ import (
 )
 
 type AssetSource interface {
-	ComputeAssets(start, end time.Time) (*opencost.AssetSet, error)
+	ComputeAssets(ctx context.Context, start, end time.Time) (*opencost.AssetSet, error)
 }
 
 type AssetsComputeSource struct {
@@ -34,7 +35,8 @@ func (acs *AssetsComputeSource) CanCompute(start, end time.Time) bool {
 
 // Compute should compute a single T for the given time range.
 func (acs *AssetsComputeSource) Compute(start, end time.Time) (*opencost.AssetSet, error) {
-	return acs.src.ComputeAssets(start, end)
+	// exports run on a schedule rather than on behalf of a request, so there is no request to cancel them
+	return acs.src.ComputeAssets(context.Background(), start, end)
 }
 
 // Name returns the name of the ComputeSource
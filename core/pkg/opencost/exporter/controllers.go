@@ -5,9 +5,11 @@ import (
 
 	export "github.com/opencost/opencost/core/pkg/exporter"
 	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/model/pb/kubemodel"
 	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/opencost/exporter/allocation"
 	"github.com/opencost/opencost/core/pkg/opencost/exporter/asset"
+	kubemodelexporter "github.com/opencost/opencost/core/pkg/opencost/exporter/kubemodel"
 	"github.com/opencost/opencost/core/pkg/opencost/exporter/networkinsight"
 	"github.com/opencost/opencost/core/pkg/source"
 	"github.com/opencost/opencost/core/pkg/storage"
@@ -149,3 +151,22 @@ func (pec *PipelineExportControllers) Stop() {
 	pec.AssetExportController.Stop()
 	pec.NetworkInsightExportController.Stop()
 }
+
+// NewKubeModelExportControllers creates the export controllers for kubemodel snapshots of the given resolutions,
+// which must each be 10 minutes, 1 hour or 1 day.
+func NewKubeModelExportControllers(clusterId string, store storage.Storage, src kubemodelexporter.SnapshotSource, resolutions []time.Duration) *export.ComputeExportControllerGroup[kubemodel.Snapshot] {
+	snapshotSource := kubemodelexporter.NewSnapshotComputeSource(src)
+	controllers := []*export.ComputeExportController[kubemodel.Snapshot]{}
+
+	for _, res := range resolutions {
+		controller, err := NewProtobufComputePipelineExportController[kubemodel.Snapshot](clusterId, store, snapshotSource, res)
+		if err != nil {
+			log.Errorf("Failed to create kubemodel export controller for resolution: %s - %v", timeutil.DurationString(res), err)
+			continue
+		}
+
+		controllers = append(controllers, controller)
+	}
+
+	return export.NewComputeExportControllerGroup(controllers...)
+}
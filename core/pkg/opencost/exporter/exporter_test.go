@@ -1,6 +1,7 @@
 package exporter
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -9,10 +10,12 @@ import (
 	"github.com/opencost/opencost/core/pkg/diagnostics"
 	"github.com/opencost/opencost/core/pkg/exporter"
 	"github.com/opencost/opencost/core/pkg/exporter/pathing"
+	"github.com/opencost/opencost/core/pkg/model/pb/kubemodel"
 	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/pipelines"
 	"github.com/opencost/opencost/core/pkg/source"
 	"github.com/opencost/opencost/core/pkg/storage"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
@@ -105,10 +108,10 @@ func NewMockPipelineComputeSourceWith(srcResolution time.Duration) *MockPipeline
 	}
 }
 
-func (mpcs *MockPipelineComputeSource) ComputeAllocation(start, end time.Time) (*opencost.AllocationSet, error) {
+func (mpcs *MockPipelineComputeSource) ComputeAllocation(ctx context.Context, start, end time.Time) (*opencost.AllocationSet, error) {
 	return mpcs.allocSource.Compute(start, end)
 }
-func (mpcs *MockPipelineComputeSource) ComputeAssets(start, end time.Time) (*opencost.AssetSet, error) {
+func (mpcs *MockPipelineComputeSource) ComputeAssets(ctx context.Context, start, end time.Time) (*opencost.AssetSet, error) {
 	return mpcs.assetSource.Compute(start, end)
 }
 func (mpcs *MockPipelineComputeSource) ComputeNetworkInsights(start, end time.Time) (*opencost.NetworkInsightSet, error) {
@@ -371,6 +374,51 @@ func TestPipelineExportControllers(t *testing.T) {
 	})
 }
 
+type mockSnapshotSource struct{}
+
+func (mss *mockSnapshotSource) ComputeSnapshot(start, end time.Time) (*kubemodel.Snapshot, error) {
+	return &kubemodel.Snapshot{
+		Cluster: &kubemodel.Cluster{ID: TestClusterId},
+		Nodes:   []*kubemodel.Node{{ID: "node-uid", ClusterID: TestClusterId, Name: "node-1"}},
+	}, nil
+}
+
+func TestKubeModelExportControllers(t *testing.T) {
+	memStore := storage.NewMemoryStorage()
+
+	exportControllers := NewKubeModelExportControllers(TestClusterId, memStore, &mockSnapshotSource{}, []time.Duration{TestResolution})
+	if len(exportControllers.Resolutions()) != 1 {
+		t.Fatalf("expected 1 kubemodel resolution, got %d", len(exportControllers.Resolutions()))
+	}
+
+	start := time.Now().UTC().Truncate(TestResolution)
+	end := start.Add(TestResolution)
+
+	// allow a single export to occur
+	exportControllers.Start(time.Second)
+	time.Sleep(time.Second + (750 * time.Millisecond))
+	exportControllers.Stop()
+
+	p, err := pathing.NewDefaultStoragePathFormatter(TestClusterId, pipelines.KubeModelPipelineName, ptr(TestResolution))
+	if err != nil {
+		t.Fatalf("failed to create kubemodel path formatter: %v", err)
+	}
+
+	expectedPath := p.ToFullPath("", opencost.NewClosedWindow(start, end), "binpb")
+	fileContents, err := memStore.Read(expectedPath)
+	if err != nil {
+		t.Fatalf("failed to read file %s: %v", expectedPath, err)
+	}
+
+	var snapshot kubemodel.Snapshot
+	if err := proto.Unmarshal(fileContents, &snapshot); err != nil {
+		t.Fatalf("failed to unmarshal data: %v", err)
+	}
+	if snapshot.IsEmpty() || snapshot.GetNodes()[0].GetName() != "node-1" {
+		t.Fatalf("unexpected snapshot: %v", &snapshot)
+	}
+}
+
 // test helper function that will load a path from a storage implementation and ensure that the file is not empty and can be decoded, etc...
 func validateFileCreation[T any, U PipelineData[T]](t *testing.T, memStore storage.Storage, p pathing.StoragePathFormatter[opencost.Window], start, end time.Time) {
 	t.Helper()
@@ -52,3 +52,44 @@ func NewComputePipelineExportController[T any, U export.BinaryMarshalerPtr[T], S
 
 	return export.NewComputeExportController(source, exporter, resolution), nil
 }
+
+// NewProtobufComputePipelineExporter creates a new `ComputeExporter[T]` instance which is used to export computed
+// protobuf messages by window for a specific pipeline.
+func NewProtobufComputePipelineExporter[T any, U export.ProtoMessagePtr[T], S validator.SetConstraint[T]](
+	clusterId string,
+	resolution time.Duration,
+	store storage.Storage,
+) (export.ComputeExporter[T], error) {
+	pipelineName := pipelines.NameFor[T]()
+	if pipelineName == "" {
+		return nil, fmt.Errorf("failed to extract pipeline name for type: %s", typeutil.TypeOf[T]())
+	}
+
+	pathing, err := pathing.NewDefaultStoragePathFormatter(clusterId, pipelineName, &resolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create path formatter: %w", err)
+	}
+
+	return export.NewComputeStorageExporter(
+		pathing,
+		export.NewProtobufEncoder[T, U](),
+		store,
+		validator.NewSetValidator[T, S](resolution),
+	), nil
+}
+
+// NewProtobufComputePipelineExportController creates a new `ComputeExportController[T]` instance which is used to
+// export computed protobuf messages using the provided source, storage and resolution.
+func NewProtobufComputePipelineExportController[T any, U export.ProtoMessagePtr[T], S validator.SetConstraint[T]](
+	clusterId string,
+	store storage.Storage,
+	source export.ComputeSource[T],
+	resolution time.Duration,
+) (*export.ComputeExportController[T], error) {
+	exporter, err := NewProtobufComputePipelineExporter[T, U, S](clusterId, resolution, store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute exporter: %w", err)
+	}
+
+	return export.NewComputeExportController(source, exporter, resolution), nil
+}
@@ -0,0 +1,42 @@
+package kubemodel
+
+import (
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/exporter"
+	"github.com/opencost/opencost/core/pkg/model/pb/kubemodel"
+	"github.com/opencost/opencost/core/pkg/pipelines"
+)
+
+type SnapshotSource interface {
+	ComputeSnapshot(start, end time.Time) (*kubemodel.Snapshot, error)
+}
+
+type SnapshotComputeSource struct {
+	src SnapshotSource
+}
+
+// NewSnapshotComputeSource creates an `exporter.ComputeSource[kubemodel.Snapshot]` implementation
+func NewSnapshotComputeSource(src SnapshotSource) exporter.ComputeSource[kubemodel.Snapshot] {
+	return &SnapshotComputeSource{
+		src: src,
+	}
+}
+
+// CanCompute should return true iff the ComputeSource can effectively act as
+// a source of T data for the given time range. Snapshots are built from the
+// current state of the cluster, so windows which ended more than one window
+// duration ago cannot be computed.
+func (scs *SnapshotComputeSource) CanCompute(start, end time.Time) bool {
+	return time.Now().UTC().Before(end.Add(end.Sub(start)))
+}
+
+// Compute should compute a single T for the given time range.
+func (scs *SnapshotComputeSource) Compute(start, end time.Time) (*kubemodel.Snapshot, error) {
+	return scs.src.ComputeSnapshot(start, end)
+}
+
+// Name returns the name of the ComputeSource
+func (scs *SnapshotComputeSource) Name() string {
+	return pipelines.KubeModelPipelineName
+}
@@ -0,0 +1,305 @@
+package opencost
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/opencost/opencost/core/pkg/util/promutil"
+)
+
+// LabelRuleAction is the normalization applied by a LabelRule.
+type LabelRuleAction string
+
+const (
+	// LabelRuleAlias sets the rule's label to the value of the first of its
+	// sources which is present, unless the label is already set
+	LabelRuleAlias LabelRuleAction = "alias"
+	// LabelRuleLowercase lowercases the value of the rule's label or, if the
+	// rule has no label, the names of all labels
+	LabelRuleLowercase LabelRuleAction = "lowercase"
+	// LabelRuleRegex rewrites the value of the rule's label by replacing
+	// matches of the rule's pattern with its replacement
+	LabelRuleRegex LabelRuleAction = "regex"
+	// LabelRuleDefault sets the rule's label to the rule's value, unless the
+	// label is already set
+	LabelRuleDefault LabelRuleAction = "default"
+	// LabelRuleInherit sets the rule's label from the allocation's namespace,
+	// or from other allocations of the same controller, unless the label is
+	// already set
+	LabelRuleInherit LabelRuleAction = "inherit"
+)
+
+// LabelRuleScope is a set of labels to which a LabelRule applies.
+type LabelRuleScope string
+
+const (
+	LabelRuleScopeLabels      LabelRuleScope = "labels"
+	LabelRuleScopeAnnotations LabelRuleScope = "annotations"
+	LabelRuleScopeCloudCost   LabelRuleScope = "cloudCost"
+)
+
+// Sources from which LabelRuleInherit rules inherit labels.
+const (
+	LabelRuleInheritNamespace  = "namespace"
+	LabelRuleInheritController = "controller"
+)
+
+// LabelRule is a single label normalization step. Rules are applied in order,
+// so that e.g. an alias may be applied to labels which were lowercased by a
+// previous rule.
+type LabelRule struct {
+	Action LabelRuleAction `json:"action"`
+	// Label is the canonical label written by the rule
+	Label string `json:"label,omitempty"`
+	// Sources are the labels aliased to Label, in order of precedence
+	Sources []string `json:"sources,omitempty"`
+	// Pattern and Replacement rewrite the value of Label. Replacement may
+	// refer to capture groups, e.g. "$1".
+	Pattern     string `json:"pattern,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	// Value is the default value of Label
+	Value string `json:"value,omitempty"`
+	// From is the source inherited from: "namespace" or "controller"
+	From string `json:"from,omitempty"`
+	// Scopes restricts the rule to the given sets of labels. Empty applies
+	// the rule to allocation labels and annotations, and cloud cost labels.
+	Scopes []LabelRuleScope `json:"scopes,omitempty"`
+}
+
+type labelRule struct {
+	*LabelRule
+	sources []string
+	pattern *regexp.Regexp
+	scopes  map[LabelRuleScope]bool
+}
+
+func (lr *labelRule) appliesTo(scope LabelRuleScope) bool {
+	return len(lr.scopes) == 0 || lr.scopes[scope]
+}
+
+// apply applies the rule to the given labels, which must be writable. Inherit
+// rules are applied by the caller, which knows the source of inherited labels.
+func (lr *labelRule) apply(labels map[string]string) {
+	switch lr.Action {
+	case LabelRuleAlias:
+		if labels[lr.Label] != "" {
+			return
+		}
+		for _, source := range lr.sources {
+			if value := labels[source]; value != "" {
+				labels[lr.Label] = value
+				return
+			}
+		}
+	case LabelRuleLowercase:
+		if lr.Label != "" {
+			if value, ok := labels[lr.Label]; ok {
+				labels[lr.Label] = strings.ToLower(value)
+			}
+			return
+		}
+		for name, value := range labels {
+			lower := strings.ToLower(name)
+			if lower == name {
+				continue
+			}
+			delete(labels, name)
+			// Prefer the value of an existing lowercase label
+			if _, ok := labels[lower]; !ok {
+				labels[lower] = value
+			}
+		}
+	case LabelRuleRegex:
+		if value, ok := labels[lr.Label]; ok {
+			labels[lr.Label] = lr.pattern.ReplaceAllString(value, lr.Replacement)
+		}
+	case LabelRuleDefault:
+		if labels[lr.Label] == "" {
+			labels[lr.Label] = lr.Value
+		}
+	}
+}
+
+// LabelNormalizer applies an ordered list of LabelRules to the labels and
+// annotations of allocations, and the labels of cloud costs, so that they can
+// be filtered and aggregated consistently.
+type LabelNormalizer struct {
+	rules []*labelRule
+}
+
+// NewLabelNormalizer validates and compiles the given rules.
+func NewLabelNormalizer(rules []*LabelRule) (*LabelNormalizer, error) {
+	compiled := make([]*labelRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule == nil {
+			return nil, fmt.Errorf("label rule %d: nil rule", i)
+		}
+
+		lr := &labelRule{LabelRule: rule}
+
+		if rule.Label == "" && rule.Action != LabelRuleLowercase {
+			return nil, fmt.Errorf("label rule %d: %s requires a label", i, rule.Action)
+		}
+
+		switch rule.Action {
+		case LabelRuleAlias:
+			if len(rule.Sources) == 0 {
+				return nil, fmt.Errorf("label rule %d: alias requires sources", i)
+			}
+			// Kubernetes label names are sanitized when they are recorded as
+			// metrics, so match either form
+			for _, source := range rule.Sources {
+				lr.sources = append(lr.sources, source)
+				if sanitized := promutil.SanitizeLabelName(source); sanitized != source {
+					lr.sources = append(lr.sources, sanitized)
+				}
+			}
+		case LabelRuleLowercase, LabelRuleDefault:
+		case LabelRuleRegex:
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("label rule %d: invalid pattern: %w", i, err)
+			}
+			lr.pattern = pattern
+		case LabelRuleInherit:
+			if rule.From != LabelRuleInheritNamespace && rule.From != LabelRuleInheritController {
+				return nil, fmt.Errorf("label rule %d: unsupported inherit source: %s", i, rule.From)
+			}
+		default:
+			return nil, fmt.Errorf("label rule %d: unsupported action: %s", i, rule.Action)
+		}
+
+		for _, scope := range rule.Scopes {
+			switch scope {
+			case LabelRuleScopeLabels, LabelRuleScopeAnnotations, LabelRuleScopeCloudCost:
+			default:
+				return nil, fmt.Errorf("label rule %d: unsupported scope: %s", i, scope)
+			}
+			if lr.scopes == nil {
+				lr.scopes = map[LabelRuleScope]bool{}
+			}
+			lr.scopes[scope] = true
+		}
+
+		compiled = append(compiled, lr)
+	}
+
+	return &LabelNormalizer{rules: compiled}, nil
+}
+
+// NormalizeAllocations applies the rules to the labels and annotations of each
+// allocation in the given set. Labels are copied before they are modified, as
+// they may be shared between allocations.
+func (ln *LabelNormalizer) NormalizeAllocations(as *AllocationSet) {
+	if ln == nil || len(ln.rules) == 0 || as == nil {
+		return
+	}
+
+	var allocs []*Allocation
+	for _, alloc := range as.Allocations {
+		if alloc == nil || alloc.Properties == nil || alloc.IsIdle() || alloc.IsUnmounted() {
+			continue
+		}
+
+		alloc.Properties.Labels = copyLabels(alloc.Properties.Labels)
+		alloc.Properties.Annotations = copyLabels(alloc.Properties.Annotations)
+		allocs = append(allocs, alloc)
+	}
+
+	for _, rule := range ln.rules {
+		if rule.appliesTo(LabelRuleScopeLabels) {
+			ln.applyToAllocations(rule, allocs, func(p *AllocationProperties) (map[string]string, map[string]string) {
+				return p.Labels, p.NamespaceLabels
+			})
+		}
+		if rule.appliesTo(LabelRuleScopeAnnotations) {
+			ln.applyToAllocations(rule, allocs, func(p *AllocationProperties) (map[string]string, map[string]string) {
+				return p.Annotations, p.NamespaceAnnotations
+			})
+		}
+	}
+}
+
+// applyToAllocations applies the rule to the labels returned by get, which
+// also returns the corresponding namespace labels for inherit rules.
+func (ln *LabelNormalizer) applyToAllocations(rule *labelRule, allocs []*Allocation, get func(*AllocationProperties) (map[string]string, map[string]string)) {
+	if rule.Action != LabelRuleInherit {
+		for _, alloc := range allocs {
+			labels, _ := get(alloc.Properties)
+			rule.apply(labels)
+		}
+		return
+	}
+
+	if rule.From == LabelRuleInheritNamespace {
+		for _, alloc := range allocs {
+			labels, nsLabels := get(alloc.Properties)
+			if labels[rule.Label] == "" && nsLabels[rule.Label] != "" {
+				labels[rule.Label] = nsLabels[rule.Label]
+			}
+		}
+		return
+	}
+
+	// Inherit from controller: allocations of the same controller which are
+	// missing the label take the value of the first which has it
+	values := map[string]string{}
+	for _, alloc := range allocs {
+		key := controllerKey(alloc.Properties)
+		if key == "" {
+			continue
+		}
+		labels, _ := get(alloc.Properties)
+		if value := labels[rule.Label]; value != "" {
+			if _, ok := values[key]; !ok {
+				values[key] = value
+			}
+		}
+	}
+	for _, alloc := range allocs {
+		labels, _ := get(alloc.Properties)
+		if labels[rule.Label] != "" {
+			continue
+		}
+		if value, ok := values[controllerKey(alloc.Properties)]; ok {
+			labels[rule.Label] = value
+		}
+	}
+}
+
+// NormalizeCloudCost returns a clone of the given CloudCost with the rules
+// applied to its labels. Inherit rules do not apply to cloud costs.
+func (ln *LabelNormalizer) NormalizeCloudCost(cc *CloudCost) *CloudCost {
+	if ln == nil || len(ln.rules) == 0 || cc == nil || cc.Properties == nil {
+		return cc
+	}
+
+	normalized := cc.Clone()
+	if normalized.Properties.Labels == nil {
+		normalized.Properties.Labels = CloudCostLabels{}
+	}
+	for _, rule := range ln.rules {
+		if rule.Action == LabelRuleInherit || !rule.appliesTo(LabelRuleScopeCloudCost) {
+			continue
+		}
+		rule.apply(normalized.Properties.Labels)
+	}
+
+	return normalized
+}
+
+func controllerKey(p *AllocationProperties) string {
+	if p.Controller == "" {
+		return ""
+	}
+	return strings.Join([]string{p.Cluster, p.Namespace, p.ControllerKind, p.Controller}, "/")
+}
+
+func copyLabels[T ~map[string]string](labels T) T {
+	c := make(T, len(labels))
+	for k, v := range labels {
+		c[k] = v
+	}
+	return c
+}
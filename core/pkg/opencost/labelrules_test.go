@@ -0,0 +1,112 @@
+package opencost
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLabelNormalizer_NormalizeAllocations(t *testing.T) {
+	normalizer, err := NewLabelNormalizer([]*LabelRule{
+		{Action: LabelRuleLowercase},
+		{Action: LabelRuleAlias, Label: "team", Sources: []string{"app.kubernetes.io/part-of", "owner"}},
+		{Action: LabelRuleInherit, Label: "team", From: LabelRuleInheritNamespace},
+		{Action: LabelRuleInherit, Label: "team", From: LabelRuleInheritController, Scopes: []LabelRuleScope{LabelRuleScopeLabels}},
+		{Action: LabelRuleRegex, Label: "team", Pattern: `^team-(.*)$`, Replacement: "$1"},
+		{Action: LabelRuleLowercase, Label: "team"},
+		{Action: LabelRuleDefault, Label: "team", Value: "unowned", Scopes: []LabelRuleScope{LabelRuleScopeLabels}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sharedLabels := AllocationLabels{"Team": "Payments"}
+
+	as := NewAllocationSet(start, start.Add(day))
+	as.Set(NewMockUnitAllocation("a", start, day, &AllocationProperties{Labels: sharedLabels}))
+	as.Set(NewMockUnitAllocation("b", start, day, &AllocationProperties{Labels: AllocationLabels{"app_kubernetes_io_part_of": "team-search"}}))
+	as.Set(NewMockUnitAllocation("c", start, day, &AllocationProperties{Namespace: "ml", NamespaceLabels: AllocationLabels{"team": "ML"}}))
+	as.Set(NewMockUnitAllocation("d1", start, day, &AllocationProperties{Namespace: "ns", ControllerKind: "deployment", Controller: "api", Labels: AllocationLabels{"owner": "Billing"}}))
+	as.Set(NewMockUnitAllocation("d2", start, day, &AllocationProperties{Namespace: "ns", ControllerKind: "deployment", Controller: "api"}))
+	as.Set(NewMockUnitAllocation("e", start, day, &AllocationProperties{Namespace: "other"}))
+
+	normalizer.NormalizeAllocations(as)
+
+	expected := map[string]string{
+		"a":  "payments",
+		"b":  "search",
+		"c":  "ml",
+		"d1": "billing",
+		"d2": "billing",
+		"e":  "unowned",
+	}
+	for name, team := range expected {
+		if got := as.Get(name).Properties.Labels["team"]; got != team {
+			t.Errorf("%s: expected team %s, got %s", name, team, got)
+		}
+	}
+
+	if _, ok := sharedLabels["team"]; ok {
+		t.Errorf("expected shared labels to be unmodified")
+	}
+
+	// Default is scoped to labels, so annotations are unchanged
+	if _, ok := as.Get("e").Properties.Annotations["team"]; ok {
+		t.Errorf("expected no default team annotation")
+	}
+
+	err = as.AggregateBy([]string{"label:team"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if as.Length() != len(expected)-1 {
+		t.Errorf("expected %d aggregated allocations, got %d", len(expected)-1, as.Length())
+	}
+}
+
+func TestLabelNormalizer_NormalizeCloudCost(t *testing.T) {
+	normalizer, err := NewLabelNormalizer([]*LabelRule{
+		{Action: LabelRuleAlias, Label: "team", Sources: []string{"Owner"}},
+		{Action: LabelRuleLowercase, Label: "team"},
+		{Action: LabelRuleInherit, Label: "team", From: LabelRuleInheritNamespace},
+		{Action: LabelRuleDefault, Label: "env", Value: "prod", Scopes: []LabelRuleScope{LabelRuleScopeLabels}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cc := NewCloudCost(start, start.Add(day), &CloudCostProperties{Labels: CloudCostLabels{"Owner": "Search"}}, 0, 1, 1, 1, 1, 1)
+
+	normalized := normalizer.NormalizeCloudCost(cc)
+	if got := normalized.Properties.Labels["team"]; got != "search" {
+		t.Errorf("expected team search, got %s", got)
+	}
+	if _, ok := normalized.Properties.Labels["env"]; ok {
+		t.Errorf("expected default scoped to allocation labels not to apply")
+	}
+	if _, ok := cc.Properties.Labels["team"]; ok {
+		t.Errorf("expected original cloud cost to be unmodified")
+	}
+}
+
+func TestNewLabelNormalizer_Invalid(t *testing.T) {
+	testCases := map[string]*LabelRule{
+		"nil rule":        nil,
+		"missing label":   {Action: LabelRuleDefault, Value: "a"},
+		"missing sources": {Action: LabelRuleAlias, Label: "team"},
+		"bad pattern":     {Action: LabelRuleRegex, Label: "team", Pattern: "("},
+		"bad inherit":     {Action: LabelRuleInherit, Label: "team", From: "node"},
+		"bad action":      {Action: "drop", Label: "team"},
+		"bad scope":       {Action: LabelRuleLowercase, Scopes: []LabelRuleScope{"nodes"}},
+	}
+
+	for name, rule := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewLabelNormalizer([]*LabelRule{rule})
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -40,7 +40,7 @@ const (
 	AssetsCodecVersion uint8 = 21
 
 	// AllocationCodecVersion is used for any resources listed in the Allocation version set
-	AllocationCodecVersion uint8 = 24
+	AllocationCodecVersion uint8 = 25
 
 	// CloudCostCodecVersion is used for any resources listed in the CloudCost version set
 	CloudCostCodecVersion uint8 = 3
@@ -86,6 +86,7 @@ var typeMap map[string]reflect.Type = map[string]reflect.Type{
 	"PVKey":                 reflect.TypeOf((*PVKey)(nil)).Elem(),
 	"RawAllocationOnlyData": reflect.TypeOf((*RawAllocationOnlyData)(nil)).Elem(),
 	"SharedAsset":           reflect.TypeOf((*SharedAsset)(nil)).Elem(),
+	"UsagePercentiles":      reflect.TypeOf((*UsagePercentiles)(nil)).Elem(),
 	"Window":                reflect.TypeOf((*Window)(nil)).Elem(),
 }
 
@@ -7570,6 +7571,34 @@ func (target *RawAllocationOnlyData) MarshalBinaryWithContext(ctx *EncodingConte
 
 		buff.WriteFloat64(*target.GPUUsageMax) // write float64
 	}
+	if target.CPUCoreUsagePercentiles == nil {
+		buff.WriteUInt8(uint8(0)) // write nil byte
+	} else {
+		buff.WriteUInt8(uint8(1)) // write non-nil byte
+
+		// --- [begin][write][struct](UsagePercentiles) ---
+		buff.WriteInt(0) // [compatibility, unused]
+		errA := target.CPUCoreUsagePercentiles.MarshalBinaryWithContext(ctx)
+		if errA != nil {
+			return errA
+		}
+		// --- [end][write][struct](UsagePercentiles) ---
+
+	}
+	if target.RAMBytesUsagePercentiles == nil {
+		buff.WriteUInt8(uint8(0)) // write nil byte
+	} else {
+		buff.WriteUInt8(uint8(1)) // write non-nil byte
+
+		// --- [begin][write][struct](UsagePercentiles) ---
+		buff.WriteInt(0) // [compatibility, unused]
+		errB := target.RAMBytesUsagePercentiles.MarshalBinaryWithContext(ctx)
+		if errB != nil {
+			return errB
+		}
+		// --- [end][write][struct](UsagePercentiles) ---
+
+	}
 	return nil
 }
 
@@ -7647,6 +7676,48 @@ func (target *RawAllocationOnlyData) UnmarshalBinaryWithContext(ctx *DecodingCon
 
 	}
 
+	// field version check
+	if uint8(25) <= version {
+		if buff.ReadUInt8() == uint8(0) {
+			target.CPUCoreUsagePercentiles = nil
+		} else {
+			// --- [begin][read][struct](UsagePercentiles) ---
+			d := &UsagePercentiles{}
+			buff.ReadInt() // [compatibility, unused]
+			errA := d.UnmarshalBinaryWithContext(ctx)
+			if errA != nil {
+				return errA
+			}
+			target.CPUCoreUsagePercentiles = d
+			// --- [end][read][struct](UsagePercentiles) ---
+
+		}
+	} else {
+		target.CPUCoreUsagePercentiles = nil
+
+	}
+
+	// field version check
+	if uint8(25) <= version {
+		if buff.ReadUInt8() == uint8(0) {
+			target.RAMBytesUsagePercentiles = nil
+		} else {
+			// --- [begin][read][struct](UsagePercentiles) ---
+			e := &UsagePercentiles{}
+			buff.ReadInt() // [compatibility, unused]
+			errB := e.UnmarshalBinaryWithContext(ctx)
+			if errB != nil {
+				return errB
+			}
+			target.RAMBytesUsagePercentiles = e
+			// --- [end][read][struct](UsagePercentiles) ---
+
+		}
+	} else {
+		target.RAMBytesUsagePercentiles = nil
+
+	}
+
 	return nil
 }
 
@@ -7867,6 +7938,122 @@ func (target *SharedAsset) UnmarshalBinaryWithContext(ctx *DecodingContext) (err
 	return nil
 }
 
+//--------------------------------------------------------------------------
+//  UsagePercentiles
+//--------------------------------------------------------------------------
+
+// MarshalBinary serializes the internal properties of this UsagePercentiles instance
+// into a byte array
+func (target *UsagePercentiles) MarshalBinary() (data []byte, err error) {
+	ctx := &EncodingContext{
+		Buffer: util.NewBuffer(),
+		Table:  nil,
+	}
+
+	e := target.MarshalBinaryWithContext(ctx)
+	if e != nil {
+		return nil, e
+	}
+
+	encBytes := ctx.Buffer.Bytes()
+	return encBytes, nil
+}
+
+// MarshalBinaryWithContext serializes the internal properties of this UsagePercentiles instance
+// into a byte array leveraging a predefined context.
+func (target *UsagePercentiles) MarshalBinaryWithContext(ctx *EncodingContext) (err error) {
+	// panics are recovered and propagated as errors
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else if s, ok := r.(string); ok {
+				err = fmt.Errorf("Unexpected panic: %s", s)
+			} else {
+				err = fmt.Errorf("Unexpected panic: %+v", r)
+			}
+		}
+	}()
+
+	buff := ctx.Buffer
+	buff.WriteUInt8(AllocationCodecVersion) // version
+
+	buff.WriteFloat64(target.P50) // write float64
+	buff.WriteFloat64(target.P90) // write float64
+	buff.WriteFloat64(target.P95) // write float64
+	buff.WriteFloat64(target.P99) // write float64
+	return nil
+}
+
+// UnmarshalBinary uses the data passed byte array to set all the internal properties of
+// the UsagePercentiles type
+func (target *UsagePercentiles) UnmarshalBinary(data []byte) error {
+	var table []string
+	buff := util.NewBufferFromBytes(data)
+
+	// string table header validation
+	if isBinaryTag(data, BinaryTagStringTable) {
+		buff.ReadBytes(len(BinaryTagStringTable)) // strip tag length
+		tl := buff.ReadInt()                      // table length
+		if tl > 0 {
+			table = make([]string, tl, tl)
+			for i := 0; i < tl; i++ {
+				table[i] = buff.ReadString()
+			}
+		}
+	}
+
+	ctx := &DecodingContext{
+		Buffer: buff,
+		Table:  table,
+	}
+
+	err := target.UnmarshalBinaryWithContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnmarshalBinaryWithContext uses the context containing a string table and binary buffer to set all the internal properties of
+// the UsagePercentiles type
+func (target *UsagePercentiles) UnmarshalBinaryWithContext(ctx *DecodingContext) (err error) {
+	// panics are recovered and propagated as errors
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else if s, ok := r.(string); ok {
+				err = fmt.Errorf("Unexpected panic: %s", s)
+			} else {
+				err = fmt.Errorf("Unexpected panic: %+v", r)
+			}
+		}
+	}()
+
+	buff := ctx.Buffer
+	version := buff.ReadUInt8()
+
+	if version > AllocationCodecVersion {
+		return fmt.Errorf("Invalid Version Unmarshaling UsagePercentiles. Expected %d or less, got %d", AllocationCodecVersion, version)
+	}
+
+	a := buff.ReadFloat64() // read float64
+	target.P50 = a
+
+	b := buff.ReadFloat64() // read float64
+	target.P90 = b
+
+	c := buff.ReadFloat64() // read float64
+	target.P95 = c
+
+	d := buff.ReadFloat64() // read float64
+	target.P99 = d
+
+	return nil
+}
+
 //--------------------------------------------------------------------------
 //  Window
 //--------------------------------------------------------------------------
@@ -650,3 +650,33 @@ func TestWindow_BinaryEncoding(t *testing.T) {
 		t.Fatalf("Window.Binary: expected %v; found %v", w0.End(), w1.End())
 	}
 }
+
+func TestRawAllocationOnlyData_BinaryEncoding(t *testing.T) {
+	gpuUsageMax := 0.75
+
+	r0 := &RawAllocationOnlyData{
+		CPUCoreUsageMax:          2,
+		RAMBytesUsageMax:         1024,
+		GPUUsageMax:              &gpuUsageMax,
+		CPUCoreUsagePercentiles:  &UsagePercentiles{P50: 0.5, P90: 1, P95: 1.5, P99: 1.9},
+		RAMBytesUsagePercentiles: nil,
+	}
+
+	bs, err := r0.MarshalBinary()
+	if err != nil {
+		t.Fatalf("RawAllocationOnlyData.Binary: unexpected error: %s", err)
+	}
+
+	r1 := &RawAllocationOnlyData{}
+	err = r1.UnmarshalBinary(bs)
+	if err != nil {
+		t.Fatalf("RawAllocationOnlyData.Binary: unexpected error: %s", err)
+	}
+
+	if !r1.Equal(r0) {
+		t.Fatalf("RawAllocationOnlyData.Binary: expected %v; found %v", r0, r1)
+	}
+	if r1.RAMBytesUsagePercentiles != nil {
+		t.Fatalf("RawAllocationOnlyData.Binary: expected nil RAM percentiles; found %v", r1.RAMBytesUsagePercentiles)
+	}
+}
@@ -0,0 +1,295 @@
+package opencost
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/filter"
+	"github.com/opencost/opencost/core/pkg/filter/ast"
+	"github.com/opencost/opencost/core/pkg/filter/matcher"
+	"github.com/opencost/opencost/core/pkg/log"
+)
+
+// ShareDistribution is the key by which a SharingRule distributes its shared
+// costs among its target allocations.
+type ShareDistribution string
+
+const (
+	// ShareDistributionEven gives each aggregated target an equal share
+	ShareDistributionEven ShareDistribution = "even"
+	// ShareDistributionTotalCost shares in proportion to each target's
+	// unshared total cost
+	ShareDistributionTotalCost ShareDistribution = "totalCost"
+	// ShareDistributionCPURequest shares in proportion to each target's
+	// requested CPU core-hours
+	ShareDistributionCPURequest ShareDistribution = "cpuRequest"
+	// ShareDistributionRAMRequest shares in proportion to each target's
+	// requested RAM byte-hours
+	ShareDistributionRAMRequest ShareDistribution = "ramRequest"
+	// ShareDistributionWeights shares in proportion to the rule's Weights,
+	// which are keyed by the WeightBy property of each target. Weights may be
+	// fixed, or derived from an external metric.
+	ShareDistributionWeights ShareDistribution = "weights"
+)
+
+// ParseShareDistribution returns the ShareDistribution matching the given
+// string, or an error if there is none.
+func ParseShareDistribution(distribution string) (ShareDistribution, error) {
+	switch ShareDistribution(distribution) {
+	case ShareDistributionEven, ShareDistributionTotalCost, ShareDistributionCPURequest, ShareDistributionRAMRequest, ShareDistributionWeights:
+		return ShareDistribution(distribution), nil
+	case "":
+		return ShareDistributionTotalCost, nil
+	}
+
+	return "", fmt.Errorf("unsupported share distribution: %s", distribution)
+}
+
+// SharingRule describes a set of costs to share and how to distribute them
+// among a set of target allocations during AggregateBy. A rule's costs are
+// the allocations matching Source plus a fixed HourlyCost, either of which
+// may be empty.
+type SharingRule struct {
+	// Name identifies the rule in each target's SharedCostBreakdown
+	Name string
+	// Source selects the allocations which are shared by this rule. Sources
+	// are removed from the aggregated results.
+	Source filter.Filter
+	// HourlyCost is a fixed hourly cost to share, e.g. a negotiated support
+	// contract, or an amortized cloud cost.
+	HourlyCost float64
+	// Target selects the allocations which receive the shared costs. A nil
+	// Target shares with all unshared allocations.
+	Target filter.Filter
+	// DistributeBy determines the proportion of the shared costs received by
+	// each target.
+	DistributeBy ShareDistribution
+	// WeightBy is the aggregation property (e.g. "namespace" or "label:team")
+	// by which Weights are keyed when DistributeBy is ShareDistributionWeights.
+	WeightBy string
+	// Weights are relative weights keyed by WeightBy values. Targets whose
+	// value has no weight receive no share.
+	Weights map[string]float64
+}
+
+// compiledSharingRule is a SharingRule with its filters compiled, and the
+// AllocationSet of shared costs collected during AggregateBy.
+type compiledSharingRule struct {
+	*SharingRule
+	source   AllocationMatcher
+	target   AllocationMatcher
+	weightBy []string
+	shareSet *AllocationSet
+}
+
+func compileSharingRules(rules []*SharingRule, labelConfig *LabelConfig, window Window) ([]*compiledSharingRule, error) {
+	compiler := NewAllocationMatchCompiler(labelConfig)
+
+	compiled := make([]*compiledSharingRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+
+		var err error
+		csr := &compiledSharingRule{
+			SharingRule: rule,
+			shareSet:    &AllocationSet{Window: window.Clone()},
+		}
+
+		if rule.Source != nil {
+			csr.source, err = compiler.Compile(rule.Source)
+			if err != nil {
+				return nil, fmt.Errorf("compiling source of sharing rule '%s': '%s': %w", rule.Name, ast.ToPreOrderShortString(rule.Source), err)
+			}
+		}
+
+		if rule.Target == nil {
+			csr.target = &matcher.AllPass[*Allocation]{}
+		} else {
+			csr.target, err = compiler.Compile(rule.Target)
+			if err != nil {
+				return nil, fmt.Errorf("compiling target of sharing rule '%s': '%s': %w", rule.Name, ast.ToPreOrderShortString(rule.Target), err)
+			}
+		}
+
+		if rule.DistributeBy == ShareDistributionWeights {
+			if rule.WeightBy == "" {
+				return nil, fmt.Errorf("sharing rule '%s': weights distribution requires a weightBy property", rule.Name)
+			}
+			csr.weightBy = []string{rule.WeightBy}
+		}
+
+		compiled = append(compiled, csr)
+	}
+
+	return compiled, nil
+}
+
+// matchSharingRule returns the first rule whose source matches the given
+// allocation, or nil if no rule matches.
+func matchSharingRule(rules []*compiledSharingRule, alloc *Allocation) *compiledSharingRule {
+	for _, rule := range rules {
+		if rule.source != nil && rule.source.Matches(alloc) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// insertHourlyCost converts the rule's HourlyCost into a shared allocation
+// covering the given AllocationSet's window.
+func (csr *compiledSharingRule) insertHourlyCost(as *AllocationSet) {
+	if csr.HourlyCost <= 0.0 {
+		return
+	}
+
+	hours := as.Resolution().Hours()
+
+	// If set ends in the future, adjust hours accordingly
+	diff := time.Since(as.End())
+	if diff < 0.0 {
+		hours += diff.Hours()
+	}
+
+	csr.shareSet.Insert(&Allocation{
+		Name:       fmt.Sprintf("%s/%s", csr.Name, SharedSuffix),
+		Start:      as.Start(),
+		End:        as.End(),
+		SharedCost: csr.HourlyCost * hours,
+		Properties: &AllocationProperties{Cluster: SharedSuffix},
+	})
+}
+
+// computeCoeffs computes the proportion of the rule's shared costs to be
+// received by each post-aggregation key. As with computeShareCoeffs, targets
+// which fail the filter contribute to a "__filtered__" bin, so that filtering
+// does not inflate the share of the remaining targets.
+func (csr *compiledSharingRule) computeCoeffs(aggregateBy []string, options *AllocationAggregationOptions, filter AllocationMatcher, as *AllocationSet) map[string]float64 {
+	coeffs := map[string]float64{}
+
+	// For weighted distributions, split each key's weight evenly among the
+	// allocations sharing that key.
+	var weightCounts map[string]float64
+	if csr.DistributeBy == ShareDistributionWeights {
+		weightCounts = map[string]float64{}
+		for _, alloc := range as.Allocations {
+			if !csr.isTarget(alloc) {
+				continue
+			}
+			weightCounts[alloc.generateKey(csr.weightBy, options.LabelConfig)]++
+		}
+	}
+
+	total := 0.0
+	for _, alloc := range as.Allocations {
+		if !csr.isTarget(alloc) {
+			continue
+		}
+
+		name := alloc.generateKey(aggregateBy, options.LabelConfig)
+		if !filter.Matches(alloc) {
+			name = "__filtered__"
+		}
+
+		var weight float64
+		switch csr.DistributeBy {
+		case ShareDistributionEven:
+			// Even distribution is not additive, so count each key once
+			if _, ok := coeffs[name]; !ok {
+				coeffs[name] = 1.0
+				total += 1.0
+			}
+			continue
+		case ShareDistributionCPURequest:
+			weight = alloc.CPUCoreRequestAverage * alloc.Minutes() / 60.0
+		case ShareDistributionRAMRequest:
+			weight = alloc.RAMBytesRequestAverage * alloc.Minutes() / 60.0
+		case ShareDistributionWeights:
+			key := alloc.generateKey(csr.weightBy, options.LabelConfig)
+			if count := weightCounts[key]; count > 0 {
+				weight = csr.Weights[key] / count
+			}
+		default:
+			weight = alloc.TotalCost() - alloc.SharedCost - alloc.UnmountedPVCost
+		}
+
+		if weight <= 0.0 {
+			continue
+		}
+
+		coeffs[name] += weight
+		total += weight
+	}
+
+	for name := range coeffs {
+		if total > 0.0 {
+			coeffs[name] /= total
+		} else {
+			coeffs[name] = 0.0
+		}
+	}
+
+	if total <= 0.0 && csr.shareSet.Length() > 0 {
+		log.Warnf("Allocation: sharing rule '%s' has no targets with a non-zero %s", csr.Name, csr.DistributeBy)
+	}
+
+	return coeffs
+}
+
+func (csr *compiledSharingRule) isTarget(alloc *Allocation) bool {
+	return !alloc.IsIdle() && !alloc.IsUnmounted() && csr.target.Matches(alloc)
+}
+
+// distribute shares the rule's shared costs with the given aggregated
+// allocation according to its coefficient.
+func (csr *compiledSharingRule) distribute(alloc *Allocation, coeffs map[string]float64, options *AllocationAggregationOptions) {
+	coeff, ok := coeffs[alloc.Name]
+	if !ok || coeff == 0.0 {
+		return
+	}
+
+	for _, sharedAlloc := range csr.shareSet.Allocations {
+		if options.IncludeSharedCostBreakdown {
+			if alloc.SharedCostBreakdown == nil {
+				alloc.SharedCostBreakdown = SharedCostBreakdowns{}
+			}
+
+			alloc.SharedCostBreakdown.Insert(SharedCostBreakdown{
+				Name:         csr.Name,
+				TotalCost:    sharedAlloc.TotalCost() * coeff,
+				CPUCost:      sharedAlloc.CPUTotalCost() * coeff,
+				GPUCost:      sharedAlloc.GPUTotalCost() * coeff,
+				RAMCost:      sharedAlloc.RAMTotalCost() * coeff,
+				PVCost:       sharedAlloc.PVCost() * coeff,
+				NetworkCost:  sharedAlloc.NetworkTotalCost() * coeff,
+				LBCost:       sharedAlloc.LBTotalCost() * coeff,
+				ExternalCost: sharedAlloc.ExternalCost * coeff,
+			})
+		}
+
+		alloc.SharedCost += sharedAlloc.TotalCost() * coeff
+	}
+}
+
+// String returns a short description of the rule, for logging.
+func (sr *SharingRule) String() string {
+	if sr == nil {
+		return "<nil>"
+	}
+
+	var parts []string
+	if sr.Source != nil {
+		parts = append(parts, fmt.Sprintf("source=%s", ast.ToPreOrderShortString(sr.Source)))
+	}
+	if sr.HourlyCost > 0 {
+		parts = append(parts, fmt.Sprintf("hourlyCost=%f", sr.HourlyCost))
+	}
+	if sr.Target != nil {
+		parts = append(parts, fmt.Sprintf("target=%s", ast.ToPreOrderShortString(sr.Target)))
+	}
+	parts = append(parts, fmt.Sprintf("distributeBy=%s", sr.DistributeBy))
+
+	return fmt.Sprintf("%s{%s}", sr.Name, strings.Join(parts, ", "))
+}
@@ -0,0 +1,137 @@
+package opencost
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/filter/allocation"
+)
+
+func newSharingRuleTestAllocationSet(start time.Time) *AllocationSet {
+	as := NewAllocationSet(start, start.Add(day))
+
+	for i, ns := range []string{"payments", "search", "search", "monitoring"} {
+		pod := fmt.Sprintf("pod%d", i)
+		as.Set(NewMockUnitAllocation(fmt.Sprintf("cluster1/node1/%s/%s/container1", ns, pod), start, day, &AllocationProperties{
+			Cluster:   "cluster1",
+			Node:      "node1",
+			Namespace: ns,
+			Pod:       pod,
+			Container: "container1",
+			Labels:    AllocationLabels{"team": ns},
+		}))
+	}
+
+	return as
+}
+
+func TestAllocationSet_AggregateBy_SharingRules(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	parser := allocation.NewAllocationFilterParser()
+
+	monitoring, err := parser.Parse(`namespace:"monitoring"`)
+	if err != nil {
+		t.Fatalf("parsing filter: %s", err)
+	}
+	payments, err := parser.Parse(`namespace:"payments"`)
+	if err != nil {
+		t.Fatalf("parsing filter: %s", err)
+	}
+
+	testCases := map[string]struct {
+		rules    []*SharingRule
+		expected map[string]float64
+	}{
+		"source by total cost": {
+			rules: []*SharingRule{
+				{Name: "monitoring", Source: monitoring},
+			},
+			// 6.0 of monitoring shared 1:2 between payments and search
+			expected: map[string]float64{"payments": 8.0, "search": 16.0},
+		},
+		"source evenly": {
+			rules: []*SharingRule{
+				{Name: "monitoring", Source: monitoring, DistributeBy: ShareDistributionEven},
+			},
+			expected: map[string]float64{"payments": 9.0, "search": 15.0},
+		},
+		"source to target": {
+			rules: []*SharingRule{
+				{Name: "monitoring", Source: monitoring, Target: payments},
+			},
+			expected: map[string]float64{"payments": 12.0, "search": 12.0},
+		},
+		"hourly cost by weights": {
+			rules: []*SharingRule{
+				{
+					Name:         "support",
+					HourlyCost:   1.0,
+					DistributeBy: ShareDistributionWeights,
+					WeightBy:     "label:team",
+					Weights:      map[string]float64{"payments": 3.0, "search": 1.0},
+				},
+			},
+			// 24.0 shared 3:1 between payments and search
+			expected: map[string]float64{"payments": 24.0, "search": 18.0, "monitoring": 6.0},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			as := newSharingRuleTestAllocationSet(start)
+
+			err := as.AggregateBy([]string{AllocationNamespaceProp}, &AllocationAggregationOptions{
+				SharingRules:               tc.rules,
+				IncludeSharedCostBreakdown: true,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if as.Length() != len(tc.expected) {
+				t.Fatalf("expected %d allocations, got %d", len(tc.expected), as.Length())
+			}
+
+			for name, cost := range tc.expected {
+				alloc := as.Get(name)
+				if alloc == nil {
+					t.Fatalf("missing allocation %s", name)
+				}
+				if math.Abs(alloc.TotalCost()-cost) > 0.0001 {
+					t.Errorf("expected %s total cost %f, got %f", name, cost, alloc.TotalCost())
+				}
+				if alloc.SharedCost > 0 && len(alloc.SharedCostBreakdown) == 0 {
+					t.Errorf("expected %s shared cost breakdown", name)
+				}
+			}
+		})
+	}
+}
+
+func TestAllocationSet_AggregateBy_SharingRulesInvalid(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	as := newSharingRuleTestAllocationSet(start)
+
+	err := as.AggregateBy([]string{AllocationNamespaceProp}, &AllocationAggregationOptions{
+		SharingRules: []*SharingRule{
+			{Name: "support", HourlyCost: 1.0, DistributeBy: ShareDistributionWeights},
+		},
+	})
+	if err == nil {
+		t.Errorf("expected error for weights distribution without weightBy")
+	}
+}
+
+func TestParseShareDistribution(t *testing.T) {
+	if d, err := ParseShareDistribution(""); err != nil || d != ShareDistributionTotalCost {
+		t.Errorf("expected default %s, got %s (%v)", ShareDistributionTotalCost, d, err)
+	}
+	if d, err := ParseShareDistribution("cpuRequest"); err != nil || d != ShareDistributionCPURequest {
+		t.Errorf("expected %s, got %s (%v)", ShareDistributionCPURequest, d, err)
+	}
+	if _, err := ParseShareDistribution("pods"); err == nil {
+		t.Errorf("expected error for unsupported distribution")
+	}
+}
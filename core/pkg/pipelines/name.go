@@ -3,6 +3,7 @@ package pipelines
 import (
 	"github.com/opencost/opencost/core/pkg/diagnostics"
 	"github.com/opencost/opencost/core/pkg/heartbeat"
+	"github.com/opencost/opencost/core/pkg/model/pb/kubemodel"
 	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/util/typeutil"
 )
@@ -16,6 +17,7 @@ const (
 	TurbonomicActionsPipelineName string = "turbonomicactions"
 	HeartbeatPipelineName         string = "heartbeat"
 	DiagnosticsPipelineName       string = "diagnostics"
+	KubeModelPipelineName         string = "kubemodel"
 )
 
 var nameByType map[string]string
@@ -36,6 +38,7 @@ func init() {
 
 	heartbeatKey := typeutil.TypeOf[heartbeat.Heartbeat]()
 	diagnosticsKey := typeutil.TypeOf[diagnostics.DiagnosticsRunReport]()
+	kubeModelKey := typeutil.TypeOf[kubemodel.Snapshot]()
 
 	nameByType = map[string]string{
 		allocSetKey:          AllocationPipelineName,
@@ -48,6 +51,7 @@ func init() {
 		networkInsightKey:    NetworkInsightPipelineName,
 		heartbeatKey:         HeartbeatPipelineName,
 		diagnosticsKey:       DiagnosticsPipelineName,
+		kubeModelKey:         KubeModelPipelineName,
 	}
 }
 
@@ -200,6 +200,24 @@ func (hp HTTPProtocol) WriteDataWithMessage(w http.ResponseWriter, data interfac
 	}
 }
 
+// WriteDataWithMeta writes the data payload similiar to WriteData except it provides additional metadata about the
+// response.
+func (hp HTTPProtocol) WriteDataWithMeta(w http.ResponseWriter, data interface{}, meta map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	status := http.StatusOK
+	resp := &HTTPResponse{
+		Code: status,
+		Data: data,
+		Meta: meta,
+	}
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Error("Failed to encode response with meta: " + err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(internalServerErrorJSON))
+	}
+}
+
 // WriteProtoWithMessage uses the protojson package to convert proto3 response to json response and
 // return it to the requester. Proto3 drops messages with default values but overriding the param
 // EmitUnpopulated to true it returns default values in the Json response payload. If error is
@@ -151,6 +151,14 @@ func TestHTTPProtocol_WriteDataWithMessageAndWarning(t *testing.T) {
 	assert.Contains(t, rw.Body.String(), "warn")
 }
 
+func TestHTTPProtocol_WriteDataWithMeta(t *testing.T) {
+	hp := HTTPProtocol{}
+	rw := httptest.NewRecorder()
+	hp.WriteDataWithMeta(rw, map[string]string{"foo": "bar"}, map[string]interface{}{"explain": "baz"})
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Contains(t, rw.Body.String(), `"meta":{"explain":"baz"}`)
+}
+
 func TestHTTPProtocol_WriteError(t *testing.T) {
 	hp := HTTPProtocol{}
 	rw := httptest.NewRecorder()
@@ -1,6 +1,7 @@
 package source
 
 import (
+	"context"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -8,6 +9,10 @@ import (
 	"github.com/opencost/opencost/core/pkg/diagnostics"
 )
 
+// UsageQuantiles are the quantiles of container CPU and RAM usage which are
+// queried for allocations, and which every MetricsQuerier must support.
+var UsageQuantiles = []float64{0.5, 0.9, 0.95, 0.99}
+
 type MetricsQuerier interface {
 	// Cluster Disks
 	QueryPVActiveMinutes(start, end time.Time) *Future[PVActiveMinutesResult]
@@ -52,6 +57,7 @@ type MetricsQuerier interface {
 	QueryRAMLimits(start, end time.Time) *Future[RAMLimitsResult]
 	QueryRAMUsageAvg(start, end time.Time) *Future[RAMUsageAvgResult]
 	QueryRAMUsageMax(start, end time.Time) *Future[RAMUsageMaxResult]
+	QueryRAMUsageQuantile(start, end time.Time, quantile float64) *Future[RAMUsageQuantileResult]
 	QueryNodeRAMPricePerGiBHr(start, end time.Time) *Future[NodeRAMPricePerGiBHrResult]
 
 	// CPU
@@ -60,6 +66,7 @@ type MetricsQuerier interface {
 	QueryCPULimits(start, end time.Time) *Future[CPULimitsResult]
 	QueryCPUUsageAvg(start, end time.Time) *Future[CPUUsageAvgResult]
 	QueryCPUUsageMax(start, end time.Time) *Future[CPUUsageMaxResult]
+	QueryCPUUsageQuantile(start, end time.Time, quantile float64) *Future[CPUUsageQuantileResult]
 	QueryNodeCPUPricePerHr(start, end time.Time) *Future[NodeCPUPricePerHrResult]
 
 	// GPU
@@ -139,6 +146,24 @@ type MetricsQuerier interface {
 	QueryDataCoverage(limitDays int) (time.Time, time.Time, error)
 }
 
+// ContextMetricsQuerier is implemented by MetricsQuerier implementations whose queries can be cancelled.
+type ContextMetricsQuerier interface {
+	MetricsQuerier
+
+	// WithContext returns a MetricsQuerier which runs its queries with the given context, so that queries are
+	// abandoned once the context is cancelled or its deadline is exceeded.
+	WithContext(ctx context.Context) MetricsQuerier
+}
+
+// MetricsWithContext returns a MetricsQuerier which runs its queries with the given context if the MetricsQuerier
+// supports cancellation, or the MetricsQuerier itself otherwise.
+func MetricsWithContext(ctx context.Context, mq MetricsQuerier) MetricsQuerier {
+	if cmq, ok := mq.(ContextMetricsQuerier); ok && ctx != nil {
+		return cmq.WithContext(ctx)
+	}
+	return mq
+}
+
 type OpenCostDataSource interface {
 	// RegisterEndPoints registers any custom endpoints that can be used for diagnostics or debug purposes.
 	RegisterEndPoints(router *httprouter.Router)
@@ -587,6 +587,12 @@ func DecodeRAMUsageMaxResult(result *QueryResult) *RAMUsageMaxResult {
 	return DecodeContainerMetricResult(result)
 }
 
+type RAMUsageQuantileResult = ContainerMetricResult
+
+func DecodeRAMUsageQuantileResult(result *QueryResult) *RAMUsageQuantileResult {
+	return DecodeContainerMetricResult(result)
+}
+
 type NodeRAMPricePerGiBHrResult struct {
 	UID          string
 	Cluster      string
@@ -643,6 +649,12 @@ func DecodeCPUUsageMaxResult(result *QueryResult) *CPUUsageMaxResult {
 	return DecodeContainerMetricResult(result)
 }
 
+type CPUUsageQuantileResult = ContainerMetricResult
+
+func DecodeCPUUsageQuantileResult(result *QueryResult) *CPUUsageQuantileResult {
+	return DecodeContainerMetricResult(result)
+}
+
 type NodeCPUPricePerHrResult struct {
 	UID          string
 	Cluster      string
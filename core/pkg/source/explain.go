@@ -0,0 +1,172 @@
+package source
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// ExplainSourcePrometheus identifies queries made to Prometheus
+	ExplainSourcePrometheus = "prometheus"
+
+	// ExplainSourceCollector identifies queries made to the collector
+	ExplainSourceCollector = "collector"
+)
+
+// ExplainQuery describes a single query issued by a MetricsQuerier.
+type ExplainQuery struct {
+	// Source is the data source which served the query, e.g. prometheus or collector
+	Source string `json:"source"`
+	// Name is the name of the query context, if any, e.g. allocation or cluster
+	Name string `json:"name,omitempty"`
+	// Query is the resolved PromQL, or the collector metric queried
+	Query string `json:"query"`
+	// Start is the start of the queried window, for range queries and collector queries
+	Start *time.Time `json:"start,omitempty"`
+	// End is the end of the queried window, or the evaluation time of instant queries
+	End time.Time `json:"end"`
+	// QueueTimeMs is the time the query waited in the request queue of the client
+	QueueTimeMs float64 `json:"queueTimeMs"`
+	// ExecutionTimeMs is the time from leaving the queue until the results were parsed
+	ExecutionTimeMs float64 `json:"executionTimeMs"`
+	// Series is the number of series the query returned
+	Series int `json:"series"`
+	// Error is the error of the query, if it failed
+	Error string `json:"error,omitempty"`
+}
+
+// ExplainBatch describes one of the windows a computation was split into, so that no single query exceeds the
+// BatchDuration of the data source.
+type ExplainBatch struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	TimeMs float64   `json:"timeMs"`
+}
+
+// ExplainStage describes the time spent in one stage of a computation over the given window, such as computing idle
+// allocations or aggregating.
+type ExplainStage struct {
+	Name   string    `json:"name"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	TimeMs float64   `json:"timeMs"`
+}
+
+// ExplainReport is the JSON representation of everything an Explain recorded.
+type ExplainReport struct {
+	TotalTimeMs float64        `json:"totalTimeMs"`
+	Queries     []ExplainQuery `json:"queries"`
+	Batches     []ExplainBatch `json:"batches"`
+	Stages      []ExplainStage `json:"stages"`
+}
+
+// Explain records how a request was computed: the queries it issued, the batches its window was split into, and the
+// time spent in each stage of the computation. An Explain is carried on the context of the request, and is safe for
+// concurrent use. All methods of a nil *Explain do nothing, so callers can record without checking that the request
+// asked for an explanation.
+type Explain struct {
+	lock    sync.Mutex
+	start   time.Time
+	queries []ExplainQuery
+	batches []ExplainBatch
+	stages  []ExplainStage
+}
+
+// NewExplain creates an Explain which measures the total time of the request from now.
+func NewExplain() *Explain {
+	return &Explain{
+		start: time.Now(),
+	}
+}
+
+type explainKey struct{}
+
+// ContextWithExplain returns a copy of the context which carries the given Explain.
+func ContextWithExplain(ctx context.Context, e *Explain) context.Context {
+	return context.WithValue(ctx, explainKey{}, e)
+}
+
+// ExplainFromContext returns the Explain carried by the context, or nil if there is none.
+func ExplainFromContext(ctx context.Context) *Explain {
+	if ctx == nil {
+		return nil
+	}
+	e, _ := ctx.Value(explainKey{}).(*Explain)
+	return e
+}
+
+// RecordQuery records a query issued to a data source.
+func (e *Explain) RecordQuery(q ExplainQuery) {
+	if e == nil {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.queries = append(e.queries, q)
+}
+
+// RecordBatch records a batch of the computation which began at the given time.
+func (e *Explain) RecordBatch(start, end, began time.Time) {
+	if e == nil {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.batches = append(e.batches, ExplainBatch{
+		Start:  start,
+		End:    end,
+		TimeMs: ExplainMs(time.Since(began)),
+	})
+}
+
+// StartStage begins timing the named stage of the computation over the given window, and returns the function which
+// ends it.
+func (e *Explain) StartStage(name string, start, end time.Time) func() {
+	if e == nil {
+		return func() {}
+	}
+
+	began := time.Now()
+	return func() {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+
+		e.stages = append(e.stages, ExplainStage{
+			Name:   name,
+			Start:  start,
+			End:    end,
+			TimeMs: ExplainMs(time.Since(began)),
+		})
+	}
+}
+
+// Report returns everything recorded so far.
+func (e *Explain) Report() *ExplainReport {
+	if e == nil {
+		return nil
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	report := &ExplainReport{
+		TotalTimeMs: ExplainMs(time.Since(e.start)),
+		Queries:     make([]ExplainQuery, len(e.queries)),
+		Batches:     make([]ExplainBatch, len(e.batches)),
+		Stages:      make([]ExplainStage, len(e.stages)),
+	}
+	copy(report.Queries, e.queries)
+	copy(report.Batches, e.batches)
+	copy(report.Stages, e.stages)
+	return report
+}
+
+// ExplainMs converts the duration to the milliseconds reported by an Explain.
+func ExplainMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,69 @@
+package source
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExplain_NilRecordsNothing(t *testing.T) {
+	var e *Explain
+
+	if ExplainFromContext(context.Background()) != nil {
+		t.Fatalf("Expected no Explain on a context without one")
+	}
+
+	// none of these should panic
+	e.RecordQuery(ExplainQuery{Query: "up"})
+	e.RecordBatch(time.Now(), time.Now(), time.Now())
+	e.StartStage("aggregate", time.Now(), time.Now())()
+
+	if e.Report() != nil {
+		t.Fatalf("Expected a nil report from a nil Explain")
+	}
+}
+
+func TestExplain_Report(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	e := NewExplain()
+	ctx := ContextWithExplain(context.Background(), e)
+	if ExplainFromContext(ctx) != e {
+		t.Fatalf("Expected the Explain carried by the context")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ExplainFromContext(ctx).RecordQuery(ExplainQuery{Source: ExplainSourcePrometheus, Query: "up", End: end})
+		}()
+	}
+	wg.Wait()
+
+	e.RecordBatch(start, end, time.Now().Add(-time.Second))
+	done := e.StartStage("aggregate", start, end)
+	done()
+
+	report := e.Report()
+	if len(report.Queries) != 10 {
+		t.Errorf("Expected 10 queries. Got: %d", len(report.Queries))
+	}
+	if len(report.Batches) != 1 || !report.Batches[0].Start.Equal(start) || !report.Batches[0].End.Equal(end) {
+		t.Errorf("Unexpected batches: %+v", report.Batches)
+	} else if report.Batches[0].TimeMs < 1000 {
+		t.Errorf("Expected a batch time of at least 1000ms. Got: %f", report.Batches[0].TimeMs)
+	}
+	if len(report.Stages) != 1 || report.Stages[0].Name != "aggregate" {
+		t.Errorf("Unexpected stages: %+v", report.Stages)
+	}
+
+	// the report is a snapshot, which later records do not change
+	e.RecordQuery(ExplainQuery{Query: "up"})
+	if len(report.Queries) != 10 {
+		t.Errorf("Expected the report to be unchanged by later records. Got: %d queries", len(report.Queries))
+	}
+}
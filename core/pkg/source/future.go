@@ -1,5 +1,7 @@
 package source
 
+import "context"
+
 // ResultDecoder[T] is a function that decodes a `QueryResult` into a `*T` type.
 type ResultDecoder[T any] func(*QueryResult) *T
 
@@ -29,18 +31,22 @@ func NewFutureFrom[T any](results []*T) *Future[T] {
 	}
 }
 
-// awaitWith allows internal callers to pass an error collector for grouping futures
-func (f *Future[T]) awaitWith(errorCollector *QueryErrorCollector) ([]*T, error) {
+// awaitWith allows internal callers to pass the context and error collector of a group of futures
+func (f *Future[T]) awaitWith(ctx context.Context, errorCollector *QueryErrorCollector) ([]*T, error) {
 	if f.results != nil {
 		return f.results, nil
 	}
 
+	span := startAwaitSpan(ctx)
+	defer span.End()
+
 	defer close(f.resultsChan)
 	result := <-f.resultsChan
 
 	q := result.Query
 	err := result.Error
 
+	endAwaitSpan(span, q, len(result.Results), err)
 	if err != nil {
 		errorCollector.AppendError(&QueryError{Query: q, Error: err})
 		return nil, err
@@ -1,14 +1,28 @@
 package source
 
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/opencost/opencost/core/pkg/source"
+
 // QueryGroupAsyncResult is a representation of a single async query in a group.
 type QueryGroupAsyncResult struct {
+	ctx            context.Context
 	errorCollector *QueryErrorCollector
 	resultsChan    QueryResultsChan
 }
 
-// newQueryGroupAsyncResult creates a new QueryGroupAsyncResult with the given error collector and results channel.
-func newQueryGroupAsyncResult(collector *QueryErrorCollector, resultsChan QueryResultsChan) *QueryGroupAsyncResult {
+// newQueryGroupAsyncResult creates a new QueryGroupAsyncResult with the given context, error collector and results
+// channel.
+func newQueryGroupAsyncResult(ctx context.Context, collector *QueryErrorCollector, resultsChan QueryResultsChan) *QueryGroupAsyncResult {
 	return &QueryGroupAsyncResult{
+		ctx:            ctx,
 		errorCollector: collector,
 		resultsChan:    resultsChan,
 	}
@@ -17,12 +31,16 @@ func newQueryGroupAsyncResult(collector *QueryErrorCollector, resultsChan QueryR
 // Await blocks and waits for the `QueryGroupAsyncResult` to resolve, and returns a slice of generic `QueryResult`
 // instances if successful, or an error otherwise.
 func (qgar *QueryGroupAsyncResult) Await() ([]*QueryResult, error) {
+	span := startAwaitSpan(qgar.ctx)
+	defer span.End()
+
 	defer close(qgar.resultsChan)
 	result := <-qgar.resultsChan
 
 	q := result.Query
 	err := result.Error
 
+	endAwaitSpan(span, q, len(result.Results), err)
 	if err != nil {
 		qgar.errorCollector.AppendError(&QueryError{Query: q, Error: err})
 		return nil, err
@@ -33,6 +51,7 @@ func (qgar *QueryGroupAsyncResult) Await() ([]*QueryResult, error) {
 
 // QueryGroupFuture[T] is a representation of a single async query in a group with a typed result.
 type QueryGroupFuture[T any] struct {
+	ctx            context.Context
 	errorCollector *QueryErrorCollector
 	future         *Future[T]
 }
@@ -41,6 +60,7 @@ type QueryGroupFuture[T any] struct {
 // This is the specific way to add a typed `Future[T]` to a `QueryGroup`.
 func WithGroup[T any](g *QueryGroup, f *Future[T]) *QueryGroupFuture[T] {
 	return &QueryGroupFuture[T]{
+		ctx:            g.ctx,
 		errorCollector: g.errorCollector,
 		future:         f,
 	}
@@ -49,7 +69,7 @@ func WithGroup[T any](g *QueryGroup, f *Future[T]) *QueryGroupFuture[T] {
 // Await blocks and waits for the `QueryGroupFuture[T]` to resolve, and returns a slice of `*T` instances if successful,
 // or an error otherwise.
 func (qgf *QueryGroupFuture[T]) Await() ([]*T, error) {
-	return qgf.future.awaitWith(qgf.errorCollector)
+	return qgf.future.awaitWith(qgf.ctx, qgf.errorCollector)
 }
 
 // QueryGroup is a representation of multiple async queries. It provides a shared error collector
@@ -68,6 +88,7 @@ func (qgf *QueryGroupFuture[T]) Await() ([]*T, error) {
 //		return grp.Error() // <-- error return type
 //	}
 type QueryGroup struct {
+	ctx            context.Context
 	errorCollector *QueryErrorCollector
 }
 
@@ -81,10 +102,18 @@ func NewQueryGroup() *QueryGroup {
 	}
 }
 
+// NewQueryGroupWithContext creates a new QueryGroup like NewQueryGroup, which traces the wait for each of its queries
+// in a span which is a child of the span on the given context.
+func NewQueryGroupWithContext(ctx context.Context) *QueryGroup {
+	qg := NewQueryGroup()
+	qg.ctx = ctx
+	return qg
+}
+
 // With adds the given `QueryResultsChan` to the QueryGroup instance and returns a `QueryGroupAsyncResult` instance to be
 // awaited
 func (qg *QueryGroup) With(resultsChan QueryResultsChan) *QueryGroupAsyncResult {
-	return newQueryGroupAsyncResult(qg.errorCollector, resultsChan)
+	return newQueryGroupAsyncResult(qg.ctx, qg.errorCollector, resultsChan)
 }
 
 // HasErrors returns true if any of the async queries in the group have errored. Note that all results must be awaited
@@ -108,3 +137,29 @@ func (qg *QueryGroup) Error() error {
 func (qg *QueryGroup) Errors() []*QueryError {
 	return qg.errorCollector.Errors()
 }
+
+// startAwaitSpan starts the span of the wait for a query in a group created with a context. For other groups, it
+// returns a span which records nothing.
+func startAwaitSpan(ctx context.Context) trace.Span {
+	if ctx == nil {
+		return trace.SpanFromContext(context.Background())
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "QueryGroup.Await")
+	return span
+}
+
+// endAwaitSpan records the query awaited in the span, along with the number of series it returned or its error.
+func endAwaitSpan(span trace.Span, query string, series int, err error) {
+	if !span.IsRecording() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("query", query))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(attribute.Int("series", series))
+}
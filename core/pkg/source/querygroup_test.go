@@ -0,0 +1,82 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func resolvedResultsChan(query string, err error, results ...*QueryResult) QueryResultsChan {
+	ch := make(QueryResultsChan, 1)
+	qr := NewQueryResults(query)
+	qr.Results = results
+	qr.Error = err
+	ch <- qr
+	return ch
+}
+
+func TestQueryGroupWithContext_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.Background())
+
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(provider)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "compute")
+
+	grp := NewQueryGroupWithContext(ctx)
+	upFuture := WithGroup(grp, NewFuture(func(qr *QueryResult) *QueryResult { return qr }, resolvedResultsChan("up", nil, &QueryResult{}, &QueryResult{})))
+	downResult := grp.With(resolvedResultsChan("down", errors.New("boom")))
+
+	if up, err := upFuture.Await(); err != nil || len(up) != 2 {
+		t.Fatalf("Expected 2 results for up. Got: %d, %v", len(up), err)
+	}
+	if _, err := downResult.Await(); err == nil {
+		t.Fatalf("Expected an error for down")
+	}
+	parent.End()
+
+	if !grp.HasErrors() {
+		t.Errorf("Expected the group to collect the error of down")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans. Got: %d", len(spans))
+	}
+
+	for _, span := range spans[:2] {
+		if span.Name() != "QueryGroup.Await" {
+			t.Errorf("Unexpected span name: %s", span.Name())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("Expected the await span to be a child of the span on the context of the group")
+		}
+	}
+
+	attrs := map[string]interface{}{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs["query"] != "up" || attrs["series"] != int64(2) {
+		t.Errorf("Unexpected attributes of the up span: %v", attrs)
+	}
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("Expected the down span to record its error. Got status: %v", spans[1].Status())
+	}
+
+	// groups without a context record nothing
+	recorder.Reset()
+	_, _ = WithGroup(NewQueryGroup(), NewFutureFrom([]*QueryResult{{}})).Await()
+	_, _ = NewQueryGroup().With(resolvedResultsChan("up", nil)).Await()
+	if len(recorder.Ended()) != 0 {
+		t.Errorf("Expected no spans for a group without a context. Got: %d", len(recorder.Ended()))
+	}
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// windowStorageTimeFmt is the format of the window start in the name of each file
+const windowStorageTimeFmt = "20060102150405"
+
+// WindowStorage stores a file for each key and window start in a Storage, in a directory for each key.
+// Repositories which persist their sets to a Storage use it, so that replicas which share the Storage
+// serve the sets written by the replica which ingests them.
+type WindowStorage struct {
+	store Storage
+	dir   string
+	ext   string
+}
+
+// NewWindowStorage creates a WindowStorage which stores files with the given extension in the given
+// directory of the storage.
+func NewWindowStorage(store Storage, dir string, ext string) *WindowStorage {
+	return &WindowStorage{
+		store: store,
+		dir:   dir,
+		ext:   ext,
+	}
+}
+
+func (ws *WindowStorage) keyDir(key string) string {
+	return path.Join(ws.dir, url.PathEscape(key))
+}
+
+func (ws *WindowStorage) filePath(start time.Time, key string) string {
+	return path.Join(ws.keyDir(key), start.UTC().Format(windowStorageTimeFmt)+ws.ext)
+}
+
+// Has returns true if there is a file for the key and window start
+func (ws *WindowStorage) Has(start time.Time, key string) (bool, error) {
+	exists, err := ws.store.Exists(ws.filePath(start, key))
+	if err != nil {
+		return false, fmt.Errorf("WindowStorage: Has: %w", err)
+	}
+	return exists, nil
+}
+
+// Read returns the contents of the file for the key and window start, or nil if there is none
+func (ws *WindowStorage) Read(start time.Time, key string) ([]byte, error) {
+	p := ws.filePath(start, key)
+	exists, err := ws.store.Exists(p)
+	if err != nil {
+		return nil, fmt.Errorf("WindowStorage: Read: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	b, err := ws.store.Read(p)
+	if err != nil {
+		return nil, fmt.Errorf("WindowStorage: Read: failed to read %s: %w", p, err)
+	}
+	return b, nil
+}
+
+// Write replaces the file for the key and window start with the given contents
+func (ws *WindowStorage) Write(start time.Time, key string, b []byte) error {
+	if err := ws.store.Write(ws.filePath(start, key), b); err != nil {
+		return fmt.Errorf("WindowStorage: Write: %w", err)
+	}
+	return nil
+}
+
+// Keys returns the keys which have at least one file in the storage
+func (ws *WindowStorage) Keys() ([]string, error) {
+	dirs, err := ws.store.ListDirectories(ws.dir)
+	if err != nil {
+		return nil, fmt.Errorf("WindowStorage: Keys: %w", err)
+	}
+
+	var keys []string
+	for _, dir := range dirs {
+		key, err := url.PathUnescape(path.Base(strings.TrimSuffix(dir.Name, DirDelim)))
+		if err != nil {
+			continue
+		}
+		files, err := ws.store.List(ws.keyDir(key))
+		if err != nil {
+			return nil, fmt.Errorf("WindowStorage: Keys: %w", err)
+		}
+		if len(files) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Expire deletes the files of every key with a window start before the given limit
+func (ws *WindowStorage) Expire(limit time.Time) error {
+	keys, err := ws.Keys()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		files, err := ws.store.List(ws.keyDir(key))
+		if err != nil {
+			return fmt.Errorf("WindowStorage: Expire: %w", err)
+		}
+		for _, file := range files {
+			name := path.Base(file.Name)
+			start, err := time.Parse(windowStorageTimeFmt, strings.TrimSuffix(name, ws.ext))
+			if err != nil {
+				continue
+			}
+			if start.Before(limit) {
+				if err := ws.store.Remove(path.Join(ws.keyDir(key), name)); err != nil {
+					return fmt.Errorf("WindowStorage: Expire: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestWindowStorage(t *testing.T) {
+	ws := NewWindowStorage(NewFileStorage(t.TempDir()), "windows", ".bin")
+
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	// keys are escaped, so they may contain the directory delimiter
+	for _, key := range []string{"integration", "domain/with/slashes"} {
+		for _, start := range []time.Time{day1, day2} {
+			if err := ws.Write(start, key, []byte(key+start.String())); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+		}
+	}
+
+	keys, err := ws.Keys()
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	slices.Sort(keys)
+	if want := []string{"domain/with/slashes", "integration"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Keys() = %v, want %v", keys, want)
+	}
+
+	b, err := ws.Read(day2, "domain/with/slashes")
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if want := "domain/with/slashes" + day2.String(); string(b) != want {
+		t.Errorf("Read() = %s, want %s", b, want)
+	}
+
+	if err := ws.Expire(day2); err != nil {
+		t.Fatalf("Expire() error = %v", err)
+	}
+	for start, want := range map[time.Time]bool{day1: false, day2: true} {
+		has, err := ws.Has(start, "integration")
+		if err != nil {
+			t.Fatalf("Has() error = %v", err)
+		}
+		if has != want {
+			t.Errorf("Has(%s) = %t, want %t", start, has, want)
+		}
+	}
+
+	// missing files are read as nil
+	b, err = ws.Read(day1, "integration")
+	if err != nil || b != nil {
+		t.Errorf("Read() of an expired file = %v, %v, want nil", b, err)
+	}
+}
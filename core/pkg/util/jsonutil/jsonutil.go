@@ -2,8 +2,12 @@ package jsonutil
 
 import (
 	"bytes"
+	gojson "encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"math"
+	"os"
 
 	"github.com/opencost/opencost/core/pkg/util/json"
 )
@@ -34,4 +38,23 @@ func Encode(buffer *bytes.Buffer, name string, obj interface{}, comma string) {
 		buffer.Write(bytes)
 	}
 	buffer.WriteString(comma)
-}
\ No newline at end of file
+}
+
+// LoadFile unmarshals the JSON file at the given path into v, returning false
+// if the file does not exist.
+func LoadFile(path string, v any) (bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	err = gojson.Unmarshal(data, v)
+	if err != nil {
+		return false, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return true, nil
+}
@@ -3,6 +3,8 @@ package jsonutil
 import (
 	"bytes"
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -73,4 +75,33 @@ func TestEncode(t *testing.T) {
 	if buffer.String() != expected {
 		t.Errorf("Expected %s, got %s", expected, buffer.String())
 	}
-}
\ No newline at end of file
+}
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+
+	type config struct {
+		Name string `json:"name"`
+	}
+
+	c := &config{}
+	ok, err := LoadFile(filepath.Join(dir, "missing.json"), c)
+	if ok || err != nil {
+		t.Errorf("Expected a missing file to not be loaded without error, got %t, %v", ok, err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"name": "test"}`), 0600); err != nil {
+		t.Fatalf("failed to write config: %s", err)
+	}
+	ok, err = LoadFile(path, c)
+	if !ok || err != nil || c.Name != "test" {
+		t.Errorf("Expected config to be loaded, got %t, %v, %+v", ok, err, c)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"name": `), 0600); err != nil {
+		t.Fatalf("failed to write config: %s", err)
+	}
+	if _, err = LoadFile(path, c); err == nil {
+		t.Errorf("Expected an error parsing invalid JSON")
+	}
+}
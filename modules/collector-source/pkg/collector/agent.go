@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	"github.com/opencost/opencost/core/pkg/clusters"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/nodestats"
+	"github.com/opencost/opencost/modules/collector-source/pkg/env"
+	"github.com/opencost/opencost/modules/collector-source/pkg/push"
+	"github.com/opencost/opencost/modules/collector-source/pkg/scrape"
+)
+
+// CollectorAgent scrapes the metrics of the local cluster like the collector data source, but rather than
+// storing and querying them itself, it pushes each scrape to a central collector.
+type CollectorAgent struct {
+	scrapeController *scrape.ScrapeController
+	pusher           *push.Pusher
+}
+
+// NewDefaultCollectorAgent creates a CollectorAgent configured from the environment
+func NewDefaultCollectorAgent(
+	clusterInfoProvider clusters.ClusterInfoProvider,
+	clusterCache clustercache.ClusterCache,
+	statSummaryClient nodestats.StatSummaryClient,
+) (*CollectorAgent, error) {
+	return NewCollectorAgent(
+		NewOpenCostCollectorConfigFromEnv(),
+		clusterInfoProvider,
+		clusterCache,
+		statSummaryClient,
+	)
+}
+
+func NewCollectorAgent(
+	config CollectorConfig,
+	clusterInfoProvider clusters.ClusterInfoProvider,
+	clusterCache clustercache.ClusterCache,
+	statSummaryClient nodestats.StatSummaryClient,
+) (*CollectorAgent, error) {
+	if config.PushToken == "" {
+		return nil, fmt.Errorf("collector agent requires %s to be set", env.CollectorPushToken)
+	}
+
+	interval, err := time.ParseDuration(config.PushInterval)
+	if err != nil && config.PushInterval != "" {
+		return nil, fmt.Errorf("invalid push interval '%s': %w", config.PushInterval, err)
+	}
+
+	pusher, err := push.NewPusher(
+		push.PusherConfig{
+			URL:        config.PushURL,
+			Token:      config.PushToken,
+			ClusterID:  config.ClusterID,
+			Interval:   interval,
+			BufferSize: config.PushBufferSize,
+		},
+		clusterInfoProvider,
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pusher: %w", err)
+	}
+
+	scrapeController := scrape.NewScrapeController(
+		config.ScrapeInterval,
+		config.NetworkPort,
+		pusher,
+		clusterCache,
+		statSummaryClient,
+		config.ScrapeConfigs,
+	)
+
+	return &CollectorAgent{
+		scrapeController: scrapeController,
+		pusher:           pusher,
+	}, nil
+}
+
+// Start starts scraping the local cluster and pushing the scrapes
+func (a *CollectorAgent) Start() {
+	log.Infof("Pushing collector metrics to %s", a.pusher.URL())
+	a.pusher.Start()
+	a.scrapeController.Start()
+}
+
+// Stop stops scraping and pushing
+func (a *CollectorAgent) Stop() {
+	a.scrapeController.Stop()
+	a.pusher.Stop()
+}
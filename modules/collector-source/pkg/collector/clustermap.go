@@ -2,6 +2,8 @@ package collector
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/opencost/opencost/core/pkg/clusters"
 	"github.com/opencost/opencost/core/pkg/log"
@@ -9,6 +11,7 @@ import (
 
 type collectorClusterMap struct {
 	clusterInfo clusters.ClusterInfoProvider
+	remote      *remoteClusters
 }
 
 func newCollectorClusterMap(clusterInfo clusters.ClusterInfoProvider) *collectorClusterMap {
@@ -28,27 +31,44 @@ func (c *collectorClusterMap) getLocalClusterInfo() (*clusters.ClusterInfo, erro
 	return clusterInfo, nil
 }
 
+// getRemoteClusterInfos returns the info of the clusters whose collector agents push to this collector
+func (c *collectorClusterMap) getRemoteClusterInfos() map[string]*clusters.ClusterInfo {
+	if c.remote == nil {
+		return nil
+	}
+	return c.remote.clusterInfos()
+}
+
 func (c *collectorClusterMap) GetClusterIDs() []string {
+	var ids []string
 	info, err := c.getLocalClusterInfo()
 	if err != nil {
 		log.Errorf("%s", err.Error())
-		return nil
+	} else {
+		ids = append(ids, info.ID)
 	}
-	return []string{info.ID}
+	return append(ids, slices.Sorted(maps.Keys(c.getRemoteClusterInfos()))...)
 }
 
 func (c *collectorClusterMap) AsMap() map[string]*clusters.ClusterInfo {
+	infos := c.getRemoteClusterInfos()
 	info, err := c.getLocalClusterInfo()
 	if err != nil {
 		log.Errorf("%s", err.Error())
-		return nil
+		return infos
 	}
-	return map[string]*clusters.ClusterInfo{
-		info.ID: info,
+	if infos == nil {
+		infos = make(map[string]*clusters.ClusterInfo, 1)
 	}
+	infos[info.ID] = info
+	return infos
 }
 
 func (c *collectorClusterMap) InfoFor(clusterID string) *clusters.ClusterInfo {
+	if info, ok := c.getRemoteClusterInfos()[clusterID]; ok {
+		return info
+	}
+
 	info, err := c.getLocalClusterInfo()
 	if err != nil {
 		log.Errorf("%s", err.Error())
@@ -62,24 +82,14 @@ func (c *collectorClusterMap) InfoFor(clusterID string) *clusters.ClusterInfo {
 }
 
 func (c *collectorClusterMap) NameFor(clusterID string) string {
-	info, err := c.getLocalClusterInfo()
-	if err != nil {
-		log.Errorf("%s", err.Error())
-		return ""
-	}
-	if info.ID == clusterID {
+	if info := c.InfoFor(clusterID); info != nil {
 		return info.Name
 	}
 	return ""
 }
 
 func (c *collectorClusterMap) NameIDFor(clusterID string) string {
-	info, err := c.getLocalClusterInfo()
-	if err != nil {
-		log.Errorf("%s", err.Error())
-		return clusterID
-	}
-	if info.ID == clusterID {
+	if info := c.InfoFor(clusterID); info != nil {
 		return fmt.Sprintf("%s/%s", info.Name, clusterID)
 	}
 	return clusterID
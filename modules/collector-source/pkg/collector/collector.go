@@ -42,11 +42,13 @@ func NewOpenCostMetricStore() metric.MetricStore {
 	memStore.Register(NewRAMLimitsMetricCollector())
 	memStore.Register(NewRAMUsageAverageMetricCollector())
 	memStore.Register(NewRAMUsageMaxMetricCollector())
+	memStore.Register(NewRAMUsageQuantileMetricCollector())
 	memStore.Register(NewCPUCoresAllocatedMetricCollector())
 	memStore.Register(NewCPURequestsMetricCollector())
 	memStore.Register(NewCPULimitsMetricCollector())
 	memStore.Register(NewCPUUsageAverageMetricCollector())
 	memStore.Register(NewCPUUsageMaxMetricCollector())
+	memStore.Register(NewCPUUsageQuantileMetricCollector())
 	memStore.Register(NewGPUsRequestedMetricCollector())
 	memStore.Register(NewGPUsUsageAverageMetricCollector())
 	memStore.Register(NewGPUsUsageMaxMetricCollector())
@@ -819,6 +821,40 @@ func NewRAMUsageMaxMetricCollector() *metric.MetricCollector {
 	)
 }
 
+//	max(
+//		quantile_over_time(
+//			%f,
+//			container_memory_working_set_bytes{
+//				container_name!="",
+//				container!="",
+//				container_name!="POD",
+//				container!="POD",
+//				<some_custom_filter>
+//			}[%s]
+//		)
+//	) by (container_name, container, pod_name, pod, namespace, node, instance, %s)
+//
+// for each of source.UsageQuantiles
+
+func NewRAMUsageQuantileMetricCollector() *metric.MetricCollector {
+	return metric.NewMetricCollector(
+		metric.RAMUsageQuantilesID,
+		metric.ContainerMemoryWorkingSetBytes,
+		[]string{
+			source.NodeLabel,
+			source.InstanceLabel,
+			source.NamespaceLabel,
+			source.PodLabel,
+			source.UIDLabel,
+			source.ContainerLabel,
+		},
+		aggregator.QuantileOverTime,
+		func(labels map[string]string) bool {
+			return labels[source.ContainerLabel] != "" && labels[source.ContainerLabel] != "POD" && labels[source.NodeLabel] != ""
+		},
+	)
+}
+
 //	avg(
 //		avg_over_time(
 //			oci_lens_cost_container_cpu_allocation{
@@ -974,6 +1010,39 @@ func NewCPUUsageMaxMetricCollector() *metric.MetricCollector {
 	)
 }
 
+//	max(
+//		quantile_over_time(
+//			%f,
+//			irate(
+//				container_cpu_usage_seconds_total{
+//					container!="POD",
+//					container!="",
+//					<some_custom_filter>
+//				}[5m]
+//			)[%s:%s]
+//		)
+//	) by (container, pod_name, pod, namespace, node, instance, cluster_id)
+//
+// for each of source.UsageQuantiles
+func NewCPUUsageQuantileMetricCollector() *metric.MetricCollector {
+	return metric.NewMetricCollector(
+		metric.CPUUsageQuantilesID,
+		metric.ContainerCPUUsageSecondsTotal,
+		[]string{
+			source.NodeLabel,
+			source.InstanceLabel,
+			source.NamespaceLabel,
+			source.PodLabel,
+			source.UIDLabel,
+			source.ContainerLabel,
+		},
+		aggregator.IRateQuantileOverTime,
+		func(labels map[string]string) bool {
+			return labels[source.ContainerLabel] != "" && labels[source.ContainerLabel] != "POD"
+		},
+	)
+}
+
 //	avg(
 //		avg_over_time(
 //			kube_pod_container_resource_requests{
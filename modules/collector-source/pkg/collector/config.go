@@ -1,17 +1,46 @@
 package collector
 
 import (
+	"time"
+
 	coreenv "github.com/opencost/opencost/core/pkg/env"
+	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/modules/collector-source/pkg/env"
+	"github.com/opencost/opencost/modules/collector-source/pkg/scrape"
 	"github.com/opencost/opencost/modules/collector-source/pkg/util"
 )
 
+const (
+	// MemoryStoreType keeps every window of each resolution in memory
+	MemoryStoreType = "memory"
+	// DiskStoreType keeps the current window of each resolution in memory, and the completed windows in block
+	// files in the StoreDirectory
+	DiskStoreType = "disk"
+
+	// defaultStoreGrace is the grace period of the disk store type when the configured grace period is invalid
+	defaultStoreGrace = 5 * time.Minute
+
+	// defaultPushMaxClusters is the maximum number of remote clusters when the configured maximum is not positive
+	defaultPushMaxClusters = 100
+)
+
 type CollectorConfig struct {
 	Resolutions     []util.ResolutionConfiguration `json:"resolutions"`
 	ScrapeInterval  string                         `json:"scrape_interval"`
 	ClusterID       string                         `json:"cluster_id"`
 	ApplicationName string                         `json:"application_name"`
 	NetworkPort     int                            `json:"network_port"`
+	ScrapeConfigs   []*scrape.ScrapeConfig         `json:"scrape_configs"`
+	RemoteWrite     bool                           `json:"remote_write"`
+	StoreType       string                         `json:"store_type"`
+	StoreDirectory  string                         `json:"store_directory"`
+	StoreGrace      string                         `json:"store_grace_period"`
+	PushReceiver    bool                           `json:"push_receiver"`
+	PushURL         string                         `json:"push_url"`
+	PushToken       string                         `json:"push_token"`
+	PushInterval    string                         `json:"push_interval"`
+	PushBufferSize  int                            `json:"push_buffer_size"`
+	PushMaxClusters int                            `json:"push_max_clusters"`
 }
 
 func NewOpenCostCollectorConfigFromEnv() CollectorConfig {
@@ -34,5 +63,31 @@ func NewOpenCostCollectorConfigFromEnv() CollectorConfig {
 		ClusterID:       coreenv.GetClusterID(),
 		ApplicationName: coreenv.GetAppName(),
 		NetworkPort:     env.GetNetworkPort(),
+		ScrapeConfigs:   loadScrapeConfigs(env.GetCollectorScrapeConfigFile()),
+		RemoteWrite:     env.IsCollectorRemoteWriteEnabled(),
+		StoreType:       env.GetCollectorStoreType(),
+		StoreDirectory:  env.GetCollectorStoreDirectory(),
+		StoreGrace:      env.GetCollectorStoreGracePeriod(),
+		PushReceiver:    env.IsCollectorPushReceiverEnabled(),
+		PushURL:         env.GetCollectorPushURL(),
+		PushToken:       env.GetCollectorPushToken(),
+		PushInterval:    env.GetCollectorPushInterval(),
+		PushBufferSize:  env.GetCollectorPushBufferSize(),
+		PushMaxClusters: env.GetCollectorPushMaxClusters(),
+	}
+}
+
+// loadScrapeConfigs loads the scrape jobs declared in the file at the given path. Jobs
+// are optional, so none are loaded if the path is empty or the file is invalid.
+func loadScrapeConfigs(path string) []*scrape.ScrapeConfig {
+	if path == "" {
+		return nil
+	}
+
+	scrapeConfigs, err := scrape.LoadScrapeConfigs(path)
+	if err != nil {
+		log.Errorf("failed to load scrape configs: %s", err.Error())
+		return nil
 	}
+	return scrapeConfigs
 }
@@ -12,7 +12,10 @@ import (
 	"github.com/opencost/opencost/core/pkg/nodestats"
 	"github.com/opencost/opencost/core/pkg/source"
 	"github.com/opencost/opencost/core/pkg/storage"
+	"github.com/opencost/opencost/modules/collector-source/pkg/env"
 	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+	"github.com/opencost/opencost/modules/collector-source/pkg/push"
+	"github.com/opencost/opencost/modules/collector-source/pkg/remotewrite"
 	"github.com/opencost/opencost/modules/collector-source/pkg/scrape"
 	"github.com/opencost/opencost/modules/collector-source/pkg/util"
 )
@@ -23,6 +26,8 @@ type collectorDataSource struct {
 	clusterInfo       clusters.ClusterInfoProvider
 	config            CollectorConfig
 	diagnosticsModule *metric.DiagnosticsModule
+	updater           metric.Updater
+	remoteClusters    *remoteClusters
 }
 
 func NewDefaultCollectorDataSource(
@@ -58,27 +63,8 @@ func NewCollectorDataSource(
 		resolutions = append(resolutions, resolution)
 	}
 
-	repo := metric.NewMetricRepository(
-		resolutions,
-		NewOpenCostMetricStore,
-	)
-	var updater metric.Updater
-	updater = repo
-	if store != nil {
-		wal, err := metric.NewWalinator(
-			config.ClusterID,
-			config.ApplicationName,
-			store,
-			resolutions,
-			updater,
-		)
-		if err != nil {
-			log.Errorf("failed to initialize the walinator: %s", err.Error())
-		} else {
-			wal.Start()
-			updater = wal
-		}
-	}
+	repo := newMetricRepository(config, resolutions, config.StoreDirectory)
+	updater := newWalUpdater(config, config.ClusterID, store, resolutions, repo)
 
 	diagnosticsModule := metric.NewDiagnosticsModule()
 	scrapeController := scrape.NewScrapeController(
@@ -87,6 +73,7 @@ func NewCollectorDataSource(
 		updater,
 		clusterCache,
 		statSummaryClient,
+		config.ScrapeConfigs,
 	)
 	scrapeController.Start()
 
@@ -97,17 +84,102 @@ func NewCollectorDataSource(
 
 	clusterMap := newCollectorClusterMap(clusterInfo)
 
+	// serve the metrics pushed by the collector agents of other clusters along with the local metrics
+	var remote *remoteClusters
+	if config.PushReceiver {
+		remote = newRemoteClusters(config, resolutions, store)
+		remote.restore()
+		metricQuerier.collectorProvider = newMultiClusterStoreProvider(metricQuerier.collectorProvider, remote)
+		clusterMap.remote = remote
+	}
+
 	return &collectorDataSource{
 		config:            config,
 		metricsQuerier:    metricQuerier,
 		clusterInfo:       clusterInfo,
 		clusterMap:        clusterMap,
 		diagnosticsModule: diagnosticsModule,
+		updater:           updater,
+		remoteClusters:    remote,
+	}
+}
+
+// newMetricRepository creates the repository of the metrics of a cluster, of the store type of the config. The
+// completed windows of the disk store type are stored in dir.
+func newMetricRepository(config CollectorConfig, resolutions []*util.Resolution, dir string) *metric.MetricRepository {
+	switch config.StoreType {
+	case DiskStoreType:
+		grace, err := time.ParseDuration(config.StoreGrace)
+		if err != nil || grace < 0 {
+			log.Errorf("invalid collector store grace period '%s', using %s", config.StoreGrace, defaultStoreGrace)
+			grace = defaultStoreGrace
+		}
+		log.Infof("Storing completed collector windows in %s after a grace period of %s", dir, grace)
+		return metric.NewBlockMetricRepository(
+			resolutions,
+			NewOpenCostMetricStore,
+			dir,
+			grace,
+		)
+	case MemoryStoreType, "":
+		return metric.NewMetricRepository(
+			resolutions,
+			NewOpenCostMetricStore,
+		)
+	default:
+		log.Errorf("unknown collector store type '%s', storing metrics in memory", config.StoreType)
+		return metric.NewMetricRepository(
+			resolutions,
+			NewOpenCostMetricStore,
+		)
+	}
+}
+
+// newWalUpdater returns the updater of the repository of a cluster, which writes each update to the storage
+// first if there is one, so that the repository is restored from the storage on restart.
+func newWalUpdater(
+	config CollectorConfig,
+	clusterID string,
+	store storage.Storage,
+	resolutions []*util.Resolution,
+	repo *metric.MetricRepository,
+) metric.Updater {
+	if store == nil {
+		return repo
+	}
+
+	wal, err := metric.NewWalinator(
+		clusterID,
+		config.ApplicationName,
+		store,
+		resolutions,
+		repo,
+	)
+	if err != nil {
+		log.Errorf("failed to initialize the walinator: %s", err.Error())
+		return repo
 	}
+	wal.Start()
+	return wal
 }
 
 func (c *collectorDataSource) RegisterEndPoints(router *httprouter.Router) {
-	return
+	if c.config.RemoteWrite {
+		if c.config.PushToken == "" {
+			log.Errorf("Prometheus remote-write requires %s to be set: not accepting remote-write requests", env.CollectorPushToken)
+		} else {
+			log.Infof("Accepting Prometheus remote-write requests at %s", remotewrite.WritePath)
+			router.POST(remotewrite.WritePath, remotewrite.NewReceiver(c.updater, c.config.PushToken).Handle)
+		}
+	}
+	if c.remoteClusters != nil {
+		if c.config.PushToken == "" {
+			log.Errorf("Collector agent pushes require %s to be set: not accepting the metrics of collector agents", env.CollectorPushToken)
+		} else {
+			log.Infof("Accepting the metrics of collector agents at %s", push.PushPath)
+			router.POST(push.PushPath, push.NewReceiver(c.remoteClusters, c.config.PushToken).Handle)
+		}
+	}
 }
 
 func (c *collectorDataSource) RegisterDiagnostics(diagService diagnostics.DiagnosticService) {
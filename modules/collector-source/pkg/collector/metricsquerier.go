@@ -1,6 +1,9 @@
 package collector
 
 import (
+	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/opencost/opencost/core/pkg/source"
@@ -13,6 +16,8 @@ const LocalStorageCostPerGiBHr = 0.04 / 730.0
 
 type collectorMetricsQuerier struct {
 	collectorProvider StoreProvider
+	// explain records the queries of a request which asked for an explanation
+	explain *source.Explain
 }
 
 func newCollectorMetricsQuerier(repo *metric.MetricRepository, resoluationConfigs []util.ResolutionConfiguration) *collectorMetricsQuerier {
@@ -21,7 +26,37 @@ func newCollectorMetricsQuerier(repo *metric.MetricRepository, resoluationConfig
 	}
 }
 
+// WithContext returns a collectorMetricsQuerier which records its queries in the source.Explain carried by the context,
+// if any. Collector queries are served from memory, so they are not abandoned when the context is cancelled.
+func (c *collectorMetricsQuerier) WithContext(ctx context.Context) source.MetricsQuerier {
+	return &collectorMetricsQuerier{
+		collectorProvider: c.collectorProvider,
+		explain:           source.ExplainFromContext(ctx),
+	}
+}
+
+// record records a query which began at the given time in the Explain of the request, if any
+func (c *collectorMetricsQuerier) record(start, end, began time.Time, queryResults *source.QueryResults) {
+	if c.explain == nil {
+		return
+	}
+
+	q := source.ExplainQuery{
+		Source:          source.ExplainSourceCollector,
+		Query:           queryResults.Query,
+		Start:           &start,
+		End:             end,
+		ExecutionTimeMs: source.ExplainMs(time.Since(began)),
+		Series:          len(queryResults.Results),
+	}
+	if queryResults.Error != nil {
+		q.Error = queryResults.Error.Error()
+	}
+	c.explain.RecordQuery(q)
+}
+
 func queryCollector[T any](c *collectorMetricsQuerier, start, end time.Time, id metric.MetricCollectorID, decoder source.ResultDecoder[T]) *source.Future[T] {
+	began := time.Now()
 	queryResults := source.NewQueryResults(string(id))
 	collector := c.collectorProvider.GetStore(start, end)
 	if collector != nil {
@@ -32,13 +67,41 @@ func queryCollector[T any](c *collectorMetricsQuerier, start, end time.Time, id
 		}
 	}
 	ch := make(source.QueryResultsChan, 1)
+	c.record(start, end, began, queryResults)
 	ch <- queryResults
 	f := source.NewFuture[T](decoder, ch)
 	return f
 
 }
 
+// queryCollectorQuantile queries a collector whose results hold a value for each of source.UsageQuantiles,
+// keeping only the value of the given quantile.
+func queryCollectorQuantile[T any](c *collectorMetricsQuerier, start, end time.Time, id metric.MetricCollectorID, quantile float64, decoder source.ResultDecoder[T]) *source.Future[T] {
+	began := time.Now()
+	queryResults := source.NewQueryResults(string(id))
+	index := slices.Index(source.UsageQuantiles, quantile)
+	collector := c.collectorProvider.GetStore(start, end)
+	if index < 0 {
+		queryResults.Error = fmt.Errorf("unsupported quantile %v for %s, supported quantiles are %v", quantile, id, source.UsageQuantiles)
+	} else if collector != nil {
+		results, err := collector.Query(id)
+		queryResults.Error = err
+		for _, result := range results {
+			if index >= len(result.Values) {
+				continue
+			}
+			result.Values = result.Values[index : index+1]
+			queryResults.Results = append(queryResults.Results, result.ToQueryResult())
+		}
+	}
+	ch := make(source.QueryResultsChan, 1)
+	c.record(start, end, began, queryResults)
+	ch <- queryResults
+	return source.NewFuture[T](decoder, ch)
+}
+
 func queryCollectorGiB[T any](c *collectorMetricsQuerier, start, end time.Time, id metric.MetricCollectorID, decoder source.ResultDecoder[T]) *source.Future[T] {
+	began := time.Now()
 	queryResults := source.NewQueryResults(string(id))
 	collector := c.collectorProvider.GetStore(start, end)
 	if collector != nil {
@@ -52,6 +115,7 @@ func queryCollectorGiB[T any](c *collectorMetricsQuerier, start, end time.Time,
 		}
 	}
 	ch := make(source.QueryResultsChan, 1)
+	c.record(start, end, began, queryResults)
 	ch <- queryResults
 	f := source.NewFuture[T](decoder, ch)
 	return f
@@ -75,6 +139,7 @@ func (c *collectorMetricsQuerier) QueryLocalStorageActiveMinutes(start, end time
 }
 
 func (c *collectorMetricsQuerier) QueryLocalStorageCost(start, end time.Time) *source.Future[source.LocalStorageCostResult] {
+	began := time.Now()
 	queryResults := source.NewQueryResults("LocalStorageCost")
 	collector := c.collectorProvider.GetStore(start, end)
 	if collector != nil {
@@ -118,11 +183,13 @@ func (c *collectorMetricsQuerier) QueryLocalStorageCost(start, end time.Time) *s
 		}
 	}
 	ch := make(source.QueryResultsChan, 1)
+	c.record(start, end, began, queryResults)
 	ch <- queryResults
 	return source.NewFuture(source.DecodeLocalStorageCostResult, ch)
 }
 
 func (c *collectorMetricsQuerier) QueryLocalStorageUsedCost(start, end time.Time) *source.Future[source.LocalStorageUsedCostResult] {
+	began := time.Now()
 	queryResults := source.NewQueryResults("LocalStorageUsedCost")
 	collector := c.collectorProvider.GetStore(start, end)
 	if collector != nil {
@@ -166,6 +233,7 @@ func (c *collectorMetricsQuerier) QueryLocalStorageUsedCost(start, end time.Time
 		}
 	}
 	ch := make(source.QueryResultsChan, 1)
+	c.record(start, end, began, queryResults)
 	ch <- queryResults
 	return source.NewFuture(source.DecodeLocalStorageUsedCostResult, ch)
 }
@@ -216,6 +284,7 @@ func (c *collectorMetricsQuerier) QueryNodeIsSpot(start, end time.Time) *source.
 }
 
 func (c *collectorMetricsQuerier) QueryNodeRAMSystemPercent(start, end time.Time) *source.Future[source.NodeRAMSystemPercentResult] {
+	began := time.Now()
 	queryResults := source.NewQueryResults("NodeRAMSystemPercent")
 	collector := c.collectorProvider.GetStore(start, end)
 	if collector != nil {
@@ -248,12 +317,14 @@ func (c *collectorMetricsQuerier) QueryNodeRAMSystemPercent(start, end time.Time
 		}
 	}
 	ch := make(source.QueryResultsChan, 1)
+	c.record(start, end, began, queryResults)
 	ch <- queryResults
 	f := source.NewFuture(source.DecodeNodeRAMSystemPercentResult, ch)
 	return f
 }
 
 func (c *collectorMetricsQuerier) QueryNodeRAMUserPercent(start, end time.Time) *source.Future[source.NodeRAMUserPercentResult] {
+	began := time.Now()
 	queryResults := source.NewQueryResults("NodeRAMUserPercent")
 	collector := c.collectorProvider.GetStore(start, end)
 	if collector != nil {
@@ -286,6 +357,7 @@ func (c *collectorMetricsQuerier) QueryNodeRAMUserPercent(start, end time.Time)
 		}
 	}
 	ch := make(source.QueryResultsChan, 1)
+	c.record(start, end, began, queryResults)
 	ch <- queryResults
 	f := source.NewFuture(source.DecodeNodeRAMUserPercentResult, ch)
 	return f
@@ -336,6 +408,10 @@ func (c *collectorMetricsQuerier) QueryRAMUsageMax(start, end time.Time) *source
 	return queryCollector(c, start, end, metric.RAMUsageMaxID, source.DecodeRAMUsageMaxResult)
 }
 
+func (c *collectorMetricsQuerier) QueryRAMUsageQuantile(start, end time.Time, quantile float64) *source.Future[source.RAMUsageQuantileResult] {
+	return queryCollectorQuantile(c, start, end, metric.RAMUsageQuantilesID, quantile, source.DecodeRAMUsageQuantileResult)
+}
+
 func (c *collectorMetricsQuerier) QueryNodeRAMPricePerGiBHr(start, end time.Time) *source.Future[source.NodeRAMPricePerGiBHrResult] {
 	return queryCollector(c, start, end, metric.NodeRAMPricePerGiBHourID, source.DecodeNodeRAMPricePerGiBHrResult)
 }
@@ -360,6 +436,10 @@ func (c *collectorMetricsQuerier) QueryCPUUsageMax(start, end time.Time) *source
 	return queryCollector(c, start, end, metric.CPUUsageMaxID, source.DecodeCPUUsageMaxResult)
 }
 
+func (c *collectorMetricsQuerier) QueryCPUUsageQuantile(start, end time.Time, quantile float64) *source.Future[source.CPUUsageQuantileResult] {
+	return queryCollectorQuantile(c, start, end, metric.CPUUsageQuantilesID, quantile, source.DecodeCPUUsageQuantileResult)
+}
+
 func (c *collectorMetricsQuerier) QueryNodeCPUPricePerHr(start, end time.Time) *source.Future[source.NodeCPUPricePerHrResult] {
 	return queryCollector(c, start, end, metric.NodeCPUPricePerHourID, source.DecodeNodeCPUPricePerHrResult)
 }
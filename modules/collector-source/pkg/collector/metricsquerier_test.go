@@ -2,6 +2,7 @@ package collector
 
 import (
 	"cmp"
+	"context"
 	"reflect"
 	"slices"
 	"testing"
@@ -213,6 +214,38 @@ func TestCollectorMetricsQuerier_QueryLocalStorageCost(t *testing.T) {
 	}
 }
 
+func TestCollectorMetricsQuerier_WithContext_Explain(t *testing.T) {
+	start1, _ := time.Parse(time.RFC3339, Start1Str)
+	end1, _ := time.Parse(time.RFC3339, End1Str)
+
+	c := &collectorMetricsQuerier{
+		collectorProvider: GetMockCollectorProvider(),
+	}
+
+	explain := source.NewExplain()
+	mq := c.WithContext(source.ContextWithExplain(context.Background(), explain))
+	if _, err := mq.QueryLocalStorageCost(start1, end1).Await(); err != nil {
+		t.Errorf("unexpected error: %v", err.Error())
+	}
+
+	// queries of the original querier are not recorded
+	if _, err := c.QueryLocalStorageCost(start1, end1).Await(); err != nil {
+		t.Errorf("unexpected error: %v", err.Error())
+	}
+
+	queries := explain.Report().Queries
+	if len(queries) != 1 {
+		t.Fatalf("recorded queries: got = %d, want 1", len(queries))
+	}
+	q := queries[0]
+	if q.Source != source.ExplainSourceCollector || q.Query != "LocalStorageCost" || q.Series != 1 {
+		t.Errorf("recorded query did not match: got = %+v", q)
+	}
+	if q.Start == nil || !q.Start.Equal(start1) || !q.End.Equal(end1) {
+		t.Errorf("recorded query window did not match: got = %v - %v, want %v - %v", q.Start, q.End, start1, end1)
+	}
+}
+
 func TestCollectorMetricsQuerier_QueryLocalStorageUsedCost(t *testing.T) {
 	start1, _ := time.Parse(time.RFC3339, Start1Str)
 	end1, _ := time.Parse(time.RFC3339, End1Str)
@@ -468,6 +501,50 @@ func Test_collectorMetricsQuerier_QueryCPUUsageMax(t *testing.T) {
 	}
 }
 
+func Test_collectorMetricsQuerier_QueryCPUUsageQuantile(t *testing.T) {
+	start1, _ := time.Parse(time.RFC3339, Start1Str)
+	end1, _ := time.Parse(time.RFC3339, End1Str)
+
+	c := collectorMetricsQuerier{
+		collectorProvider: GetMockCollectorProvider(),
+	}
+	// the irates of the two intervals are 8 and 12, and the median is interpolated between them
+	resCh := c.QueryCPUUsageQuantile(start1, end1, 0.5)
+	res, err := resCh.Await()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err.Error())
+	}
+	expected := []*source.CPUUsageQuantileResult{
+		{
+			UID:       "pod-uuid1",
+			Cluster:   "",
+			Namespace: "namespace1",
+			Node:      "node1",
+			Instance:  "node1",
+			Pod:       "pod1",
+			Container: "container1",
+			Data: []*util.Vector{
+				{
+					Value: 10,
+				},
+			},
+		},
+	}
+	if len(res) != len(expected) {
+		t.Errorf("length of result was not as expected: got = %d, want %d", len(res), len(expected))
+	}
+	for i, got := range res {
+		if !reflect.DeepEqual(got, expected[i]) {
+			t.Errorf("result at index %d did not match: got = %v, want %v", i, got, expected[i])
+		}
+	}
+
+	// quantiles which are not collected are an error
+	if _, err := c.QueryCPUUsageQuantile(start1, end1, 0.75).Await(); err == nil {
+		t.Errorf("expected error for unsupported quantile")
+	}
+}
+
 func TestCollectorMetricsQuerier_QueryGPUsUsageAvg(t *testing.T) {
 	start1, _ := time.Parse(time.RFC3339, Start1Str)
 	end1, _ := time.Parse(time.RFC3339, End1Str)
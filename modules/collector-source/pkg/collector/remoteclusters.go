@@ -0,0 +1,332 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/clusters"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/core/pkg/storage"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric/aggregator"
+	"github.com/opencost/opencost/modules/collector-source/pkg/push"
+	"github.com/opencost/opencost/modules/collector-source/pkg/util"
+)
+
+// remoteClustersDirectory is the sub-directory of the store directory which holds the blocks of each
+// remote cluster when the disk store type is used
+const remoteClustersDirectory = "clusters"
+
+// remoteClusters holds the metrics pushed by the collector agents of other clusters, in a MetricRepository
+// for each cluster which is created when the cluster first pushes updates, or when its stored metrics are
+// restored. At most maxClusters clusters are held. It implements push.ClusterUpdater.
+type remoteClusters struct {
+	lock        sync.RWMutex
+	config      CollectorConfig
+	resolutions []*util.Resolution
+	store       storage.Storage
+	maxClusters int
+	clusters    map[string]*remoteCluster
+}
+
+type remoteCluster struct {
+	provider *repoStoreProvider
+	updater  metric.Updater
+	info     map[string]string
+}
+
+func newRemoteClusters(config CollectorConfig, resolutions []*util.Resolution, store storage.Storage) *remoteClusters {
+	maxClusters := config.PushMaxClusters
+	if maxClusters <= 0 {
+		log.Errorf("invalid maximum number of pushing clusters %d, using %d", maxClusters, defaultPushMaxClusters)
+		maxClusters = defaultPushMaxClusters
+	}
+	return &remoteClusters{
+		config:      config,
+		resolutions: resolutions,
+		store:       store,
+		maxClusters: maxClusters,
+		clusters:    make(map[string]*remoteCluster),
+	}
+}
+
+// restore opens the repository of each remote cluster with stored metrics, so that the metrics which clusters
+// pushed before a restart are served before they push again. Clusters are found from the directories of their
+// collector event files in the storage, and of their blocks in the store directory.
+func (rc *remoteClusters) restore() {
+	var clusterIDs []string
+	if rc.store != nil {
+		dirs, err := rc.store.ListDirectories(rc.config.ApplicationName)
+		if err != nil {
+			log.Errorf("failed to list the stored clusters: %s", err.Error())
+		}
+		for _, dir := range dirs {
+			events, err := rc.store.ListDirectories(path.Join(rc.config.ApplicationName, dir.Name))
+			if err != nil {
+				log.Errorf("failed to list the events of cluster '%s': %s", dir.Name, err.Error())
+				continue
+			}
+			if slices.ContainsFunc(events, func(event *storage.StorageInfo) bool { return event.Name == metric.CollectorEventName }) {
+				clusterIDs = append(clusterIDs, dir.Name)
+			}
+		}
+	}
+
+	if rc.config.StoreType == DiskStoreType {
+		entries, err := os.ReadDir(filepath.Join(rc.config.StoreDirectory, remoteClustersDirectory))
+		if err != nil && !os.IsNotExist(err) {
+			log.Errorf("failed to list the blocks of the stored clusters: %s", err.Error())
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				clusterIDs = append(clusterIDs, entry.Name())
+			}
+		}
+	}
+
+	slices.Sort(clusterIDs)
+	for _, clusterID := range slices.Compact(clusterIDs) {
+		if clusterID == rc.config.ClusterID || push.ValidateClusterID(clusterID) != nil {
+			continue
+		}
+		if _, err := rc.cluster(clusterID); err != nil {
+			log.Warnf("not restoring the stored collector metrics of cluster '%s': %s", clusterID, err.Error())
+			continue
+		}
+		log.Infof("Restored the stored collector metrics of cluster '%s'", clusterID)
+	}
+}
+
+// UpdateCluster applies the UpdateSets pushed by the collector agent of the cluster, and records its cluster info
+func (rc *remoteClusters) UpdateCluster(clusterID string, clusterInfo map[string]string, updateSets []*metric.UpdateSet) error {
+	if clusterID == rc.config.ClusterID {
+		return fmt.Errorf("cluster id '%s' is the id of the local cluster", clusterID)
+	}
+
+	cluster, err := rc.cluster(clusterID)
+	if err != nil {
+		return err
+	}
+
+	if clusterInfo != nil {
+		// the metrics of the cluster are identified by the id it pushes with
+		info := maps.Clone(clusterInfo)
+		info[clusters.ClusterInfoIdKey] = clusterID
+
+		rc.lock.Lock()
+		cluster.info = info
+		rc.lock.Unlock()
+	}
+
+	for _, updateSet := range updateSets {
+		if updateSet != nil {
+			cluster.updater.Update(updateSet)
+		}
+	}
+	return nil
+}
+
+// cluster returns the remote cluster with the given id, creating its repository if it does not exist. It returns
+// push.ErrClusterLimit if the cluster does not exist and the maximum number of clusters has been reached.
+func (rc *remoteClusters) cluster(clusterID string) (*remoteCluster, error) {
+	rc.lock.RLock()
+	cluster, ok := rc.clusters[clusterID]
+	rc.lock.RUnlock()
+	if ok {
+		return cluster, nil
+	}
+
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	if cluster, ok := rc.clusters[clusterID]; ok {
+		return cluster, nil
+	}
+	if len(rc.clusters) >= rc.maxClusters {
+		return nil, fmt.Errorf("%w: %d clusters", push.ErrClusterLimit, rc.maxClusters)
+	}
+
+	log.Infof("Serving the collector metrics of cluster '%s'", clusterID)
+	repo := newMetricRepository(rc.config, rc.resolutions, filepath.Join(rc.config.StoreDirectory, remoteClustersDirectory, clusterID))
+	cluster = &remoteCluster{
+		provider: newRepoStoreProvider(repo, rc.config.Resolutions),
+		updater:  newWalUpdater(rc.config, clusterID, rc.store, rc.resolutions, repo),
+	}
+	rc.clusters[clusterID] = cluster
+	return cluster, nil
+}
+
+// providers returns the StoreProvider of each remote cluster by cluster id
+func (rc *remoteClusters) providers() map[string]StoreProvider {
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+
+	providers := make(map[string]StoreProvider, len(rc.clusters))
+	for clusterID, cluster := range rc.clusters {
+		providers[clusterID] = cluster.provider
+	}
+	return providers
+}
+
+// clusterInfos returns the ClusterInfo of each remote cluster by cluster id
+func (rc *remoteClusters) clusterInfos() map[string]*clusters.ClusterInfo {
+	rc.lock.RLock()
+	defer rc.lock.RUnlock()
+
+	infos := make(map[string]*clusters.ClusterInfo, len(rc.clusters))
+	for clusterID, cluster := range rc.clusters {
+		info, err := clusters.MapToClusterInfo(cluster.info)
+		if err != nil {
+			// the cluster has not pushed its cluster info
+			info = &clusters.ClusterInfo{
+				ID:   clusterID,
+				Name: clusterID,
+			}
+		}
+		infos[clusterID] = info
+	}
+	return infos
+}
+
+// multiClusterStoreProvider is a StoreProvider which serves the metrics of the local cluster along with
+// the metrics of the remote clusters which push to this collector.
+type multiClusterStoreProvider struct {
+	local  StoreProvider
+	remote *remoteClusters
+}
+
+func newMultiClusterStoreProvider(local StoreProvider, remote *remoteClusters) *multiClusterStoreProvider {
+	return &multiClusterStoreProvider{
+		local:  local,
+		remote: remote,
+	}
+}
+
+func (m *multiClusterStoreProvider) GetStore(start, end time.Time) metric.MetricStore {
+	store := &multiClusterMetricStore{
+		local:  m.local.GetStore(start, end),
+		remote: make(map[string]metric.MetricStore),
+	}
+	for clusterID, provider := range m.remote.providers() {
+		if remoteStore := provider.GetStore(start, end); remoteStore != nil {
+			store.remote[clusterID] = remoteStore
+		}
+	}
+
+	if store.local == nil && len(store.remote) == 0 {
+		return nil
+	}
+	return store
+}
+
+// GetDailyDataCoverage returns the union of the daily coverage of the local and remote clusters
+func (m *multiClusterStoreProvider) GetDailyDataCoverage(limitDays int) (time.Time, time.Time, error) {
+	start, end, localErr := m.local.GetDailyDataCoverage(limitDays)
+	found := localErr == nil
+	for _, provider := range m.remote.providers() {
+		s, e, err := provider.GetDailyDataCoverage(limitDays)
+		if err != nil {
+			continue
+		}
+		if !found || s.Before(start) {
+			start = s
+		}
+		if !found || e.After(end) {
+			end = e
+		}
+		found = true
+	}
+	if !found {
+		return time.Time{}, time.Time{}, localErr
+	}
+	return start, end, nil
+}
+
+// multiClusterMetricStore is a read-only MetricStore which returns the results of the local and remote
+// clusters for the same window. Results of remote clusters are labeled with their cluster id, while results
+// of the local cluster are left unlabeled as they are when only the local cluster is served.
+type multiClusterMetricStore struct {
+	local  metric.MetricStore
+	remote map[string]metric.MetricStore
+}
+
+func (m *multiClusterMetricStore) Register(collector *metric.MetricCollector) error {
+	return fmt.Errorf("cannot register metric collectors to the stores of multiple clusters")
+}
+
+func (m *multiClusterMetricStore) Unregister(collectorID metric.MetricCollectorID) bool {
+	return false
+}
+
+func (m *multiClusterMetricStore) CollectorIDs() []metric.MetricCollectorID {
+	var ids []metric.MetricCollectorID
+	for _, store := range m.stores() {
+		for _, id := range store.CollectorIDs() {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func (m *multiClusterMetricStore) Query(collectorID metric.MetricCollectorID) ([]*aggregator.MetricResult, error) {
+	var results []*aggregator.MetricResult
+	var errs []error
+
+	if m.local != nil {
+		localResults, err := m.local.Query(collectorID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		results = append(results, localResults...)
+	}
+
+	for _, clusterID := range slices.Sorted(maps.Keys(m.remote)) {
+		remoteResults, err := m.remote[clusterID].Query(collectorID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster '%s': %w", clusterID, err))
+		}
+		for _, result := range remoteResults {
+			labels := maps.Clone(result.MetricLabels)
+			if labels == nil {
+				labels = make(map[string]string, 1)
+			}
+			labels[source.ClusterIDLabel] = clusterID
+			results = append(results, &aggregator.MetricResult{
+				MetricLabels: labels,
+				Values:       result.Values,
+			})
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// Update is a no-op, as updates are applied to the repository of each cluster
+func (m *multiClusterMetricStore) Update(
+	metricName string,
+	labels map[string]string,
+	value float64,
+	timestamp time.Time,
+	additionalInformation map[string]string,
+) {
+}
+
+func (m *multiClusterMetricStore) stores() []metric.MetricStore {
+	var stores []metric.MetricStore
+	if m.local != nil {
+		stores = append(stores, m.local)
+	}
+	for _, clusterID := range slices.Sorted(maps.Keys(m.remote)) {
+		stores = append(stores, m.remote[clusterID])
+	}
+	return stores
+}
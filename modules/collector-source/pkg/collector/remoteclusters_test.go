@@ -0,0 +1,235 @@
+package collector
+
+import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/clusters"
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/core/pkg/storage"
+	"github.com/opencost/opencost/core/pkg/util"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+	"github.com/opencost/opencost/modules/collector-source/pkg/push"
+	collectorutil "github.com/opencost/opencost/modules/collector-source/pkg/util"
+)
+
+type staticClusterInfoProvider map[string]string
+
+func (s staticClusterInfoProvider) GetClusterInfo() map[string]string {
+	return s
+}
+
+func nodeCostUpdateSet(timestamp time.Time, node string) *metric.UpdateSet {
+	return &metric.UpdateSet{
+		Timestamp: timestamp,
+		Updates: []metric.Update{
+			{
+				Name: metric.NodeTotalHourlyCost,
+				Labels: map[string]string{
+					"node":        node,
+					"provider_id": node,
+				},
+				Value: 1,
+			},
+		},
+	}
+}
+
+func TestRemoteClusters(t *testing.T) {
+	config := CollectorConfig{
+		Resolutions: []collectorutil.ResolutionConfiguration{
+			{
+				Interval:  "1h",
+				Retention: 3,
+			},
+		},
+		ClusterID: "local",
+	}
+	res1h, _ := collectorutil.NewResolution(config.Resolutions[0])
+	resolutions := []*collectorutil.Resolution{res1h}
+
+	repo := newMetricRepository(config, resolutions, "")
+	remote := newRemoteClusters(config, resolutions, nil)
+	querier := newCollectorMetricsQuerier(repo, config.Resolutions)
+	querier.collectorProvider = newMultiClusterStoreProvider(querier.collectorProvider, remote)
+
+	clusterMap := newCollectorClusterMap(staticClusterInfoProvider{
+		clusters.ClusterInfoIdKey:   "local",
+		clusters.ClusterInfoNameKey: "Local",
+	})
+	clusterMap.remote = remote
+
+	start := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	end := start.Add(time.Hour)
+
+	repo.Update(nodeCostUpdateSet(start, "local-node"))
+	err := remote.UpdateCluster("remote-1", map[string]string{clusters.ClusterInfoNameKey: "Remote One"}, []*metric.UpdateSet{
+		nodeCostUpdateSet(start, "remote-node"),
+		nodeCostUpdateSet(start.Add(30*time.Minute), "remote-node"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCluster() error = %v", err)
+	}
+
+	// the local cluster cannot be pushed to
+	if err := remote.UpdateCluster("local", nil, []*metric.UpdateSet{nodeCostUpdateSet(start, "other-node")}); err == nil {
+		t.Errorf("UpdateCluster() with the local cluster id succeeded, want error")
+	}
+
+	res, err := querier.QueryNodeActiveMinutes(start, end).Await()
+	if err != nil {
+		t.Fatalf("QueryNodeActiveMinutes() error = %v", err)
+	}
+	expected := []*source.NodeActiveMinutesResult{
+		{
+			Cluster:    "",
+			Node:       "local-node",
+			ProviderID: "local-node",
+			Data: []*util.Vector{
+				{
+					Timestamp: float64(start.Unix()),
+					Value:     1,
+				},
+			},
+		},
+		{
+			Cluster:    "remote-1",
+			Node:       "remote-node",
+			ProviderID: "remote-node",
+			Data: []*util.Vector{
+				{
+					Timestamp: float64(start.Unix()),
+					Value:     1,
+				},
+				{
+					Timestamp: float64(start.Add(30 * time.Minute).Unix()),
+					Value:     1,
+				},
+			},
+		},
+	}
+	if len(res) != len(expected) {
+		t.Fatalf("length of result was not as expected: got = %d, want %d", len(res), len(expected))
+	}
+	for i, got := range res {
+		if !reflect.DeepEqual(got, expected[i]) {
+			t.Errorf("result at index %d did not match: got = %v, want %v", i, got, expected[i])
+		}
+	}
+
+	if got, want := clusterMap.GetClusterIDs(), []string{"local", "remote-1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetClusterIDs() = %v, want %v", got, want)
+	}
+	if got := clusterMap.NameIDFor("remote-1"); got != "Remote One/remote-1" {
+		t.Errorf("NameIDFor(remote-1) = %s, want Remote One/remote-1", got)
+	}
+	if got := clusterMap.NameFor("local"); got != "Local" {
+		t.Errorf("NameFor(local) = %s, want Local", got)
+	}
+	if got := len(clusterMap.AsMap()); got != 2 {
+		t.Errorf("AsMap() has %d clusters, want 2", got)
+	}
+}
+
+func TestRemoteClustersRestore(t *testing.T) {
+	config := CollectorConfig{
+		Resolutions: []collectorutil.ResolutionConfiguration{
+			{
+				Interval:  "1h",
+				Retention: 3,
+			},
+		},
+		ClusterID:       "local",
+		ApplicationName: "test",
+		StoreType:       DiskStoreType,
+		StoreDirectory:  t.TempDir(),
+		StoreGrace:      "0s",
+	}
+	res1h, _ := collectorutil.NewResolution(config.Resolutions[0])
+	resolutions := []*collectorutil.Resolution{res1h}
+	store := storage.NewFileStorage(t.TempDir())
+
+	start := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	end := start.Add(time.Hour)
+
+	remote := newRemoteClusters(config, resolutions, store)
+	err := remote.UpdateCluster("remote-1", nil, []*metric.UpdateSet{
+		nodeCostUpdateSet(start, "remote-node"),
+		nodeCostUpdateSet(start.Add(30*time.Minute), "remote-node"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateCluster() error = %v", err)
+	}
+
+	// the event files of the local cluster, and the blocks of a cluster without event files
+	if err := store.Write("test/local/collector/placeholder", []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(config.StoreDirectory, remoteClustersDirectory, "remote-2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// a restarted collector serves the stored clusters before they push again
+	restarted := newRemoteClusters(config, resolutions, store)
+	restarted.restore()
+
+	providers := restarted.providers()
+	if got, want := slices.Sorted(maps.Keys(providers)), []string{"remote-1", "remote-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("restored clusters = %v, want %v", got, want)
+	}
+
+	repo := newMetricRepository(config, resolutions, "")
+	querier := newCollectorMetricsQuerier(repo, config.Resolutions)
+	querier.collectorProvider = newMultiClusterStoreProvider(querier.collectorProvider, restarted)
+
+	res, err := querier.QueryNodeActiveMinutes(start, end).Await()
+	if err != nil {
+		t.Fatalf("QueryNodeActiveMinutes() error = %v", err)
+	}
+	if len(res) != 1 || res[0].Cluster != "remote-1" || len(res[0].Data) != 2 {
+		t.Errorf("QueryNodeActiveMinutes() = %v, want the 2 samples of remote-1", res)
+	}
+}
+
+func TestRemoteClustersMaxClusters(t *testing.T) {
+	config := CollectorConfig{
+		Resolutions: []collectorutil.ResolutionConfiguration{
+			{
+				Interval:  "1h",
+				Retention: 3,
+			},
+		},
+		ClusterID:       "local",
+		PushMaxClusters: 2,
+	}
+	res1h, _ := collectorutil.NewResolution(config.Resolutions[0])
+	resolutions := []*collectorutil.Resolution{res1h}
+
+	start := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+
+	remote := newRemoteClusters(config, resolutions, nil)
+	for _, clusterID := range []string{"remote-1", "remote-2"} {
+		if err := remote.UpdateCluster(clusterID, nil, []*metric.UpdateSet{nodeCostUpdateSet(start, "node")}); err != nil {
+			t.Fatalf("UpdateCluster(%s) error = %v", clusterID, err)
+		}
+	}
+
+	// clusters past the maximum are rejected, while the held clusters keep pushing
+	err := remote.UpdateCluster("remote-3", nil, []*metric.UpdateSet{nodeCostUpdateSet(start, "node")})
+	if !errors.Is(err, push.ErrClusterLimit) {
+		t.Errorf("UpdateCluster(remote-3) error = %v, want %v", err, push.ErrClusterLimit)
+	}
+	if err := remote.UpdateCluster("remote-1", nil, []*metric.UpdateSet{nodeCostUpdateSet(start, "node")}); err != nil {
+		t.Errorf("UpdateCluster(remote-1) error = %v", err)
+	}
+
+	if got, want := slices.Sorted(maps.Keys(remote.providers())), []string{"remote-1", "remote-2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("clusters = %v, want %v", got, want)
+	}
+}
@@ -5,9 +5,20 @@ import (
 )
 
 const (
-	CollectorEnvVarPrefix   = "COLLECTOR_"
-	CollectorScrapeInterval = "COLLECTOR_SCRAPE_INTERVAL"
-	NetworkPortEnvVar       = "NETWORK_PORT"
+	CollectorEnvVarPrefix    = "COLLECTOR_"
+	CollectorScrapeInterval  = "COLLECTOR_SCRAPE_INTERVAL"
+	CollectorScrapeConfig    = "COLLECTOR_SCRAPE_CONFIG_FILE"
+	CollectorRemoteWrite     = "COLLECTOR_REMOTE_WRITE_ENABLED"
+	CollectorStoreType       = "COLLECTOR_STORE_TYPE"
+	CollectorStoreDirectory  = "COLLECTOR_STORE_DIRECTORY"
+	CollectorStoreGrace      = "COLLECTOR_STORE_GRACE_PERIOD"
+	CollectorPushReceiver    = "COLLECTOR_PUSH_RECEIVER_ENABLED"
+	CollectorPushURL         = "COLLECTOR_PUSH_URL"
+	CollectorPushToken       = "COLLECTOR_PUSH_TOKEN"
+	CollectorPushInterval    = "COLLECTOR_PUSH_INTERVAL"
+	CollectorPushBufferSize  = "COLLECTOR_PUSH_BUFFER_SIZE"
+	CollectorPushMaxClusters = "COLLECTOR_PUSH_MAX_CLUSTERS"
+	NetworkPortEnvVar        = "NETWORK_PORT"
 )
 
 func GetNetworkPort() int {
@@ -29,3 +40,69 @@ func GetCollectionResolution1dRetention() int {
 func GetCollectorScrapeIntervalSeconds() string {
 	return env.Get(CollectorScrapeInterval, "30s")
 }
+
+// GetCollectorScrapeConfigFile returns the path of the file declaring the scrape jobs run in
+// addition to the built-in scrapers, or an empty string if there are none.
+func GetCollectorScrapeConfigFile() string {
+	return env.Get(CollectorScrapeConfig, "")
+}
+
+// IsCollectorRemoteWriteEnabled returns true if the collector accepts Prometheus remote-write
+// requests, letting metrics be pushed to it instead of scraped. Requests must carry the push token,
+// and are not accepted if it is empty.
+func IsCollectorRemoteWriteEnabled() bool {
+	return env.GetBool(CollectorRemoteWrite, false)
+}
+
+// GetCollectorStoreType returns the type of store which holds the collected metrics, either "memory",
+// or "disk" to keep the completed windows of each resolution in block files.
+func GetCollectorStoreType() string {
+	return env.Get(CollectorStoreType, "memory")
+}
+
+// GetCollectorStoreDirectory returns the local directory of the block files of the "disk" store type.
+func GetCollectorStoreDirectory() string {
+	return env.Get(CollectorStoreDirectory, env.GetPathFromConfig("collector", "blocks"))
+}
+
+// GetCollectorStoreGracePeriod returns how long the "disk" store type keeps a completed window in memory, applying
+// late and out of order updates to it, before writing it to a block file.
+func GetCollectorStoreGracePeriod() string {
+	return env.Get(CollectorStoreGrace, "5m")
+}
+
+// IsCollectorPushReceiverEnabled returns true if the collector accepts the metrics pushed by the collector
+// agents of other clusters, serving them along with the metrics of the local cluster. Pushes must carry the
+// push token, and are not accepted if it is empty.
+func IsCollectorPushReceiverEnabled() bool {
+	return env.GetBool(CollectorPushReceiver, false)
+}
+
+// GetCollectorPushURL returns the URL of the push endpoint of the central collector, which the collector
+// agent pushes the metrics of its cluster to.
+func GetCollectorPushURL() string {
+	return env.Get(CollectorPushURL, "")
+}
+
+// GetCollectorPushToken returns the bearer token which authenticates collector agents and remote-write
+// clients to the central collector.
+func GetCollectorPushToken() string {
+	return env.Get(CollectorPushToken, "")
+}
+
+// GetCollectorPushInterval returns the interval at which the collector agent pushes its buffered metrics.
+func GetCollectorPushInterval() string {
+	return env.Get(CollectorPushInterval, "1m")
+}
+
+// GetCollectorPushBufferSize returns the maximum number of scrapes the collector agent buffers while the
+// central collector is unreachable, past which the oldest are dropped.
+func GetCollectorPushBufferSize() int {
+	return env.GetInt(CollectorPushBufferSize, 2880)
+}
+
+// GetCollectorPushMaxClusters returns the maximum number of clusters whose pushed metrics the central collector
+// holds, past which the pushes of other clusters are rejected.
+func GetCollectorPushMaxClusters() int {
+	return env.GetInt(CollectorPushMaxClusters, 100)
+}
@@ -0,0 +1,131 @@
+package aggregator
+
+import (
+	"sync"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/source"
+)
+
+// quantileOverTimeAggregator is a MetricAggregator which returns the estimated value of each of the
+// source.UsageQuantiles over the values passed to it through the Update function.
+type quantileOverTimeAggregator struct {
+	lock        sync.Mutex
+	labelValues []string
+	digest      *TDigest
+}
+
+func QuantileOverTime(labelValues []string) MetricAggregator {
+	return &quantileOverTimeAggregator{
+		labelValues: labelValues,
+		digest:      NewTDigest(DefaultCompression),
+	}
+}
+
+func (a *quantileOverTimeAggregator) AdditionInfo() map[string]string {
+	return nil
+}
+
+func (a *quantileOverTimeAggregator) LabelValues() []string {
+	return a.labelValues
+}
+
+func (a *quantileOverTimeAggregator) Update(value float64, timestamp time.Time, additionalInfo map[string]string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.digest.Add(value)
+}
+
+func (a *quantileOverTimeAggregator) Value() []MetricValue {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return quantileValues(a.digest)
+}
+
+// iRateQuantileOverTimeAggregator is a MetricAggregator which returns the estimated value of each of the
+// source.UsageQuantiles over the rates per second between consecutive samples. Values passed with the same
+// timestamp are summed, and to function properly calls to Update must have a timestamp greater than or equal
+// to the last call to update. As with irate, a decrease is treated as a counter reset.
+type iRateQuantileOverTimeAggregator struct {
+	lock         sync.Mutex
+	labelValues  []string
+	initialized  bool
+	previousTime time.Time
+	currentTime  time.Time
+	previous     float64
+	current      float64
+	digest       *TDigest
+}
+
+func IRateQuantileOverTime(labelValues []string) MetricAggregator {
+	return &iRateQuantileOverTimeAggregator{
+		labelValues: labelValues,
+		digest:      NewTDigest(DefaultCompression),
+	}
+}
+
+func (a *iRateQuantileOverTimeAggregator) AdditionInfo() map[string]string {
+	return nil
+}
+
+func (a *iRateQuantileOverTimeAggregator) LabelValues() []string {
+	return a.labelValues
+}
+
+func (a *iRateQuantileOverTimeAggregator) Update(value float64, timestamp time.Time, additionalInfo map[string]string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if !a.initialized {
+		a.previousTime = timestamp
+		a.currentTime = timestamp
+		a.initialized = true
+	}
+
+	if a.currentTime.Before(timestamp) {
+		// the current sample is complete, so its rate can be added to the digest
+		if irate, ok := a.irate(); ok {
+			a.digest.Add(irate)
+		}
+		a.previousTime = a.currentTime
+		a.previous = a.current
+		a.currentTime = timestamp
+		a.current = 0
+	}
+	a.current += value
+}
+
+// irate returns the rate per second between the previous and current samples, if there are two. If the
+// counter was reset between them, the current sample is the increase since the reset.
+func (a *iRateQuantileOverTimeAggregator) irate() (float64, bool) {
+	seconds := a.currentTime.Sub(a.previousTime).Seconds()
+	if seconds == 0 {
+		return 0, false
+	}
+	increase := a.current - a.previous
+	if a.current < a.previous {
+		increase = a.current
+	}
+	return increase / seconds, true
+}
+
+func (a *iRateQuantileOverTimeAggregator) Value() []MetricValue {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	// include the rate of the current sample without adding it to the digest, as it may still be updated
+	digest := a.digest
+	if irate, ok := a.irate(); ok {
+		digest = a.digest.Clone()
+		digest.Add(irate)
+	}
+	return quantileValues(digest)
+}
+
+// quantileValues returns a MetricValue for each of the source.UsageQuantiles, in order.
+func quantileValues(digest *TDigest) []MetricValue {
+	values := make([]MetricValue, len(source.UsageQuantiles))
+	for i, q := range source.UsageQuantiles {
+		values[i] = MetricValue{Value: digest.Quantile(q)}
+	}
+	return values
+}
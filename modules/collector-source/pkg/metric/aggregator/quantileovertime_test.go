@@ -0,0 +1,114 @@
+package aggregator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuantileOverTimeAggregator_Value(t *testing.T) {
+	time1 := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	type update struct {
+		value     float64
+		timestamp time.Time
+	}
+	tests := map[string]struct {
+		updates []update
+		want    []MetricValue
+	}{
+		"no update": {
+			updates: []update{},
+			want:    []MetricValue{{Value: 0}, {Value: 0}, {Value: 0}, {Value: 0}},
+		},
+		"single update": {
+			updates: []update{
+				{value: 2, timestamp: time1},
+			},
+			want: []MetricValue{{Value: 2}, {Value: 2}, {Value: 2}, {Value: 2}},
+		},
+		"two updates": {
+			updates: []update{
+				{value: 1, timestamp: time1},
+				{value: 3, timestamp: time1.Add(time.Minute)},
+			},
+			want: []MetricValue{{Value: 2}, {Value: 3}, {Value: 3}, {Value: 3}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := QuantileOverTime(nil)
+			for _, u := range tt.updates {
+				a.Update(u.value, u.timestamp, nil)
+			}
+			if got := a.Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIRateQuantileOverTimeAggregator_Value(t *testing.T) {
+	time1 := time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	time2 := time.Date(1, 1, 1, 0, 0, 1, 0, time.UTC)
+	time3 := time.Date(1, 1, 1, 0, 0, 2, 0, time.UTC)
+	type update struct {
+		value     float64
+		timestamp time.Time
+	}
+	tests := map[string]struct {
+		updates []update
+		want    []MetricValue
+	}{
+		"no update": {
+			updates: []update{},
+			want:    []MetricValue{{Value: 0}, {Value: 0}, {Value: 0}, {Value: 0}},
+		},
+		"single update": {
+			updates: []update{
+				{value: 1, timestamp: time1},
+			},
+			want: []MetricValue{{Value: 0}, {Value: 0}, {Value: 0}, {Value: 0}},
+		},
+		"single rate": {
+			updates: []update{
+				{value: 1, timestamp: time1},
+				{value: 3, timestamp: time2},
+			},
+			want: []MetricValue{{Value: 2}, {Value: 2}, {Value: 2}, {Value: 2}},
+		},
+		"same timestamp values are summed": {
+			updates: []update{
+				{value: 1, timestamp: time1},
+				{value: 1, timestamp: time2},
+				{value: 2, timestamp: time2},
+				{value: 7, timestamp: time3},
+			},
+			// rates of 2 and 4
+			want: []MetricValue{{Value: 3}, {Value: 4}, {Value: 4}, {Value: 4}},
+		},
+		"counter reset": {
+			updates: []update{
+				{value: 10, timestamp: time1},
+				{value: 14, timestamp: time2},
+				{value: 2, timestamp: time3},
+			},
+			// rates of 4, and 2 since the reset
+			want: []MetricValue{{Value: 3}, {Value: 4}, {Value: 4}, {Value: 4}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			a := IRateQuantileOverTime(nil)
+			for _, u := range tt.updates {
+				a.Update(u.value, u.timestamp, nil)
+			}
+			if got := a.Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Value() = %v, want %v", got, tt.want)
+			}
+			// reading the value does not change it
+			if got := a.Value(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("second Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package aggregator
+
+import (
+	"math"
+	"slices"
+)
+
+// DefaultCompression is the compression of the TDigests used by the quantile aggregators, which
+// bounds their size to a few hundred centroids while keeping the error of the tail quantiles
+// well under a percent.
+const DefaultCompression = 100
+
+// TDigest is a sketch of a distribution of values which estimates quantiles, most
+// accurately at the tails, see https://arxiv.org/abs/1902.04023. Values are buffered and merged
+// into centroids, whose size is bounded by the compression and shrinks towards the tails. It is
+// not safe for concurrent use.
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+}
+
+// centroid is the mean of a cluster of values and the number of values in it.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+func NewTDigest(compression float64) *TDigest {
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Count returns the number of values added to the digest.
+func (d *TDigest) Count() float64 {
+	return d.count
+}
+
+// Add adds a value to the digest. NaN values are ignored.
+func (d *TDigest) Add(value float64) {
+	d.add(centroid{mean: value, weight: 1})
+}
+
+func (d *TDigest) add(c centroid) {
+	if math.IsNaN(c.mean) || c.weight <= 0 {
+		return
+	}
+
+	d.buffer = append(d.buffer, c)
+	d.count += c.weight
+	d.min = math.Min(d.min, c.mean)
+	d.max = math.Max(d.max, c.mean)
+
+	if len(d.buffer) >= d.bufferSize() {
+		d.compress()
+	}
+}
+
+// Clone returns a deep copy of the digest.
+func (d *TDigest) Clone() *TDigest {
+	clone := *d
+	clone.centroids = slices.Clone(d.centroids)
+	clone.buffer = slices.Clone(d.buffer)
+	return &clone
+}
+
+// Quantile returns the estimated value at the quantile q, which is clamped to [0, 1], or 0 if
+// no values have been added.
+func (d *TDigest) Quantile(q float64) float64 {
+	d.compress()
+
+	if d.count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return d.min
+	}
+	if q >= 1 {
+		return d.max
+	}
+
+	// the rank of the quantile, which is interpolated between the centers of the centroids,
+	// and between the min and max and the centers of the first and last centroid
+	rank := q * d.count
+
+	prevRank := 0.0
+	prevMean := d.min
+	cumulative := 0.0
+	for _, c := range d.centroids {
+		center := cumulative + c.weight/2
+		if rank < center {
+			return interpolate(rank, prevRank, center, prevMean, c.mean)
+		}
+		prevRank = center
+		prevMean = c.mean
+		cumulative += c.weight
+	}
+	return interpolate(rank, prevRank, d.count, prevMean, d.max)
+}
+
+func interpolate(x, x0, x1, y0, y1 float64) float64 {
+	if x1 <= x0 {
+		return y1
+	}
+	return y0 + (x-x0)/(x1-x0)*(y1-y0)
+}
+
+func (d *TDigest) bufferSize() int {
+	return int(5 * d.compression)
+}
+
+// compress merges the buffered values into the centroids, merging neighbouring centroids as long
+// as the merged centroid does not exceed the size limit for its quantile.
+func (d *TDigest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := append(d.centroids, d.buffer...)
+	slices.SortFunc(all, func(a, b centroid) int {
+		if a.mean < b.mean {
+			return -1
+		}
+		if a.mean > b.mean {
+			return 1
+		}
+		return 0
+	})
+
+	merged := make([]centroid, 0, len(d.centroids)+1)
+	current := all[0]
+	cumulative := 0.0
+	for _, next := range all[1:] {
+		proposed := current.weight + next.weight
+		q := (cumulative + proposed/2) / d.count
+		limit := 4 * d.count * q * (1 - q) / d.compression
+
+		if proposed <= limit {
+			current.mean += (next.mean - current.mean) * next.weight / proposed
+			current.weight = proposed
+			continue
+		}
+
+		merged = append(merged, current)
+		cumulative += current.weight
+		current = next
+	}
+	merged = append(merged, current)
+
+	d.centroids = merged
+	d.buffer = d.buffer[:0]
+}
@@ -0,0 +1,65 @@
+package aggregator
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// exactQuantile returns the quantile of the sorted values, interpolated between the nearest ranks.
+func exactQuantile(sorted []float64, q float64) float64 {
+	rank := q * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+func TestTDigest_Quantile(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	tests := map[string]func() float64{
+		"uniform":     func() float64 { return r.Float64() * 100 },
+		"exponential": r.ExpFloat64,
+		"normal":      func() float64 { return 50 + 10*r.NormFloat64() },
+	}
+
+	for name, sample := range tests {
+		t.Run(name, func(t *testing.T) {
+			digest := NewTDigest(DefaultCompression)
+			values := make([]float64, 10000)
+			for i := range values {
+				values[i] = sample()
+				digest.Add(values[i])
+			}
+			slices.Sort(values)
+
+			for _, q := range []float64{0.01, 0.5, 0.9, 0.95, 0.99} {
+				got := digest.Quantile(q)
+				// compare the error in rank, which is what the digest bounds
+				rank, _ := slices.BinarySearch(values, got)
+				if rankErr := math.Abs(float64(rank)/float64(len(values)) - q); rankErr > 0.005 {
+					t.Errorf("Quantile(%v) = %v, want %v (rank error %v)", q, got, exactQuantile(values, q), rankErr)
+				}
+			}
+
+			if got := digest.Quantile(0); got != values[0] {
+				t.Errorf("Quantile(0) = %v, want min %v", got, values[0])
+			}
+			if got := digest.Quantile(1); got != values[len(values)-1] {
+				t.Errorf("Quantile(1) = %v, want max %v", got, values[len(values)-1])
+			}
+		})
+	}
+}
+
+func TestTDigest_Empty(t *testing.T) {
+	digest := NewTDigest(DefaultCompression)
+	digest.Add(math.NaN())
+	if digest.Count() != 0 {
+		t.Errorf("Count() = %v, want 0", digest.Count())
+	}
+	if got := digest.Quantile(0.5); got != 0 {
+		t.Errorf("Quantile(0.5) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,436 @@
+package metric
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric/aggregator"
+)
+
+// Block files hold the results of the collectors of one or more completed windows of a resolution:
+//
+//	header:  blockMagic, blockVersion
+//	windows: an encoded window for each window in the block
+//	index:   uvarint window count, then for each window its varint start in unix milliseconds,
+//	         and the uvarint offset and length of its encoding
+//	footer:  little endian uint64 offset of the index, blockMagic
+//
+// Each window is encoded independently, so that windows can be copied between blocks as they are
+// compacted without being decoded:
+//
+//	strings:    uvarint count, then for each string its uvarint length and bytes
+//	collectors: uvarint count, then for each collector the string reference of its id, and the
+//	            uvarint offset and length of its results within the results section
+//	results:    for each collector, the uvarint count of its results, then for each result the
+//	            uvarint count of its labels and the string references of each name and value,
+//	            and the uvarint count of its values, then for each value its little endian float64
+//	            bits, and a byte which is 1 if it is followed by a varint unix nanosecond timestamp
+//
+// Label names and values repeat across collectors and results, so they are written once per window
+// in its string table and referenced by their index in it.
+const (
+	blockMagic     = "OCMBLOCK"
+	blockVersion   = 1
+	blockExtension = ".block"
+	blockTempExt   = ".tmp"
+
+	blockHeaderSize = len(blockMagic) + 1
+	blockFooterSize = 8 + len(blockMagic)
+)
+
+var errCorruptBlock = errors.New("corrupt block")
+
+// blockWindow is the location of an encoded window within a block
+type blockWindow struct {
+	key    int64
+	offset uint64
+	length uint64
+}
+
+// block is a read-only, memory mapped block file
+type block struct {
+	path    string
+	data    []byte
+	windows []blockWindow
+	unmap   func() error
+}
+
+// openBlock memory maps the block file at the given path and reads its index.
+func openBlock(path string) (*block, error) {
+	data, unmap, err := mmapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to map block '%s': %w", path, err)
+	}
+
+	windows, err := readBlockIndex(data)
+	if err != nil {
+		unmap()
+		return nil, fmt.Errorf("failed to read block '%s': %w", path, err)
+	}
+
+	return &block{
+		path:    path,
+		data:    data,
+		windows: windows,
+		unmap:   unmap,
+	}, nil
+}
+
+func readBlockIndex(data []byte) ([]blockWindow, error) {
+	if len(data) < blockHeaderSize+blockFooterSize {
+		return nil, errCorruptBlock
+	}
+	if string(data[:len(blockMagic)]) != blockMagic || string(data[len(data)-len(blockMagic):]) != blockMagic {
+		return nil, errCorruptBlock
+	}
+	if version := data[len(blockMagic)]; version != blockVersion {
+		return nil, fmt.Errorf("unsupported block version %d", version)
+	}
+
+	footer := len(data) - blockFooterSize
+	indexOffset := binary.LittleEndian.Uint64(data[footer:])
+	if indexOffset < uint64(blockHeaderSize) || indexOffset > uint64(footer) {
+		return nil, errCorruptBlock
+	}
+
+	d := &decoder{data: data[indexOffset:footer]}
+	count := d.uvarint()
+	var windows []blockWindow
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		w := blockWindow{
+			key:    d.varint(),
+			offset: d.uvarint(),
+			length: d.uvarint(),
+		}
+		if w.offset < uint64(blockHeaderSize) || w.offset > indexOffset || w.length > indexOffset-w.offset {
+			return nil, errCorruptBlock
+		}
+		windows = append(windows, w)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return windows, nil
+}
+
+// window returns the encoding of the window with the given key, if the block contains it
+func (b *block) window(key int64) ([]byte, bool) {
+	for _, w := range b.windows {
+		if w.key == key {
+			return b.data[w.offset : w.offset+w.length], true
+		}
+	}
+	return nil, false
+}
+
+func (b *block) close() error {
+	return b.unmap()
+}
+
+// writeBlock atomically writes a block file containing the given encoded windows to the path.
+func writeBlock(path string, keys []int64, windows [][]byte) error {
+	var buf bytes.Buffer
+	buf.WriteString(blockMagic)
+	buf.WriteByte(blockVersion)
+
+	index := binary.AppendUvarint(nil, uint64(len(windows)))
+	for i, window := range windows {
+		index = binary.AppendVarint(index, keys[i])
+		index = binary.AppendUvarint(index, uint64(buf.Len()))
+		index = binary.AppendUvarint(index, uint64(len(window)))
+		buf.Write(window)
+	}
+
+	indexOffset := uint64(buf.Len())
+	buf.Write(index)
+	buf.Write(binary.LittleEndian.AppendUint64(nil, indexOffset))
+	buf.WriteString(blockMagic)
+
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// writeFileAtomic writes the data to a temporary file which is synced and renamed to the path, so
+// that a partially written file is never read as a block.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + blockTempExt
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// blockFileName returns the name of a block file containing windows from the first to the last key
+func blockFileName(dir string, firstKey, lastKey int64) string {
+	return filepath.Join(dir, fmt.Sprintf("%d-%d%s", firstKey, lastKey, blockExtension))
+}
+
+// encodeWindow encodes the results of each of the collectors of the store.
+func encodeWindow(store MetricStore) ([]byte, error) {
+	ids := store.CollectorIDs()
+	slices.Sort(ids)
+
+	strs := newStringTable()
+	var collectors, results []byte
+	for _, id := range ids {
+		metricResults, err := store.Query(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query collector '%s': %w", id, err)
+		}
+
+		offset := len(results)
+		results = binary.AppendUvarint(results, uint64(len(metricResults)))
+		for _, mr := range metricResults {
+			results = binary.AppendUvarint(results, uint64(len(mr.MetricLabels)))
+			for name, value := range mr.MetricLabels {
+				results = binary.AppendUvarint(results, strs.ref(name))
+				results = binary.AppendUvarint(results, strs.ref(value))
+			}
+
+			results = binary.AppendUvarint(results, uint64(len(mr.Values)))
+			for _, v := range mr.Values {
+				results = binary.LittleEndian.AppendUint64(results, math.Float64bits(v.Value))
+				if v.Timestamp == nil {
+					results = append(results, 0)
+					continue
+				}
+				results = append(results, 1)
+				results = binary.AppendVarint(results, v.Timestamp.UnixNano())
+			}
+		}
+
+		collectors = binary.AppendUvarint(collectors, strs.ref(string(id)))
+		collectors = binary.AppendUvarint(collectors, uint64(offset))
+		collectors = binary.AppendUvarint(collectors, uint64(len(results)-offset))
+	}
+
+	window := binary.AppendUvarint(nil, uint64(len(strs.values)))
+	for _, s := range strs.values {
+		window = binary.AppendUvarint(window, uint64(len(s)))
+		window = append(window, s...)
+	}
+	window = binary.AppendUvarint(window, uint64(len(ids)))
+	window = append(window, collectors...)
+	window = append(window, results...)
+	return window, nil
+}
+
+// decodeWindowCollectorIDs returns the ids of the collectors in the encoded window
+func decodeWindowCollectorIDs(window []byte) ([]MetricCollectorID, error) {
+	d := &decoder{data: window}
+	strs := d.strings()
+	count := d.uvarint()
+	var ids []MetricCollectorID
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		ids = append(ids, MetricCollectorID(d.ref(strs)))
+		d.uvarint()
+		d.uvarint()
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return ids, nil
+}
+
+// decodeWindowResults decodes the results of a single collector from the encoded window, without
+// decoding those of other collectors.
+func decodeWindowResults(window []byte, id MetricCollectorID) ([]*aggregator.MetricResult, error) {
+	d := &decoder{data: window}
+	strs := d.strings()
+
+	count := d.uvarint()
+	found := false
+	var offset, length uint64
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		collectorID := d.ref(strs)
+		o, l := d.uvarint(), d.uvarint()
+		if collectorID == string(id) {
+			found = true
+			offset, length = o, l
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if !found {
+		return nil, fmt.Errorf("metric with ID: %s does not exist", id)
+	}
+
+	resultsSection := d.data[d.pos:]
+	if offset > uint64(len(resultsSection)) || length > uint64(len(resultsSection))-offset {
+		return nil, errCorruptBlock
+	}
+
+	d = &decoder{data: resultsSection[offset : offset+length]}
+	numResults := d.uvarint()
+	results := make([]*aggregator.MetricResult, 0, min(numResults, uint64(len(d.data))))
+	for i := uint64(0); i < numResults && d.err == nil; i++ {
+		numLabels := d.uvarint()
+		labels := make(map[string]string, min(numLabels, uint64(len(d.data))))
+		for j := uint64(0); j < numLabels && d.err == nil; j++ {
+			name := d.ref(strs)
+			labels[name] = d.ref(strs)
+		}
+
+		numValues := d.uvarint()
+		values := make([]aggregator.MetricValue, 0, min(numValues, uint64(len(d.data))))
+		for j := uint64(0); j < numValues && d.err == nil; j++ {
+			value := aggregator.MetricValue{
+				Value: math.Float64frombits(d.uint64()),
+			}
+			if d.byte() == 1 {
+				ts := time.Unix(0, d.varint()).UTC()
+				value.Timestamp = &ts
+			}
+			values = append(values, value)
+		}
+
+		results = append(results, &aggregator.MetricResult{
+			MetricLabels: labels,
+			Values:       values,
+		})
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return results, nil
+}
+
+// stringTable assigns each distinct string a reference, in the order they are first seen
+type stringTable struct {
+	refs   map[string]uint64
+	values []string
+}
+
+func newStringTable() *stringTable {
+	return &stringTable{
+		refs: make(map[string]uint64),
+	}
+}
+
+func (t *stringTable) ref(s string) uint64 {
+	if ref, ok := t.refs[s]; ok {
+		return ref
+	}
+	ref := uint64(len(t.values))
+	t.refs[s] = ref
+	t.values = append(t.values, s)
+	return ref
+}
+
+// decoder reads the values of an encoding, recording the first error so that callers need only
+// check it once they are done.
+type decoder struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		d.err = errCorruptBlock
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		d.err = errCorruptBlock
+		return 0
+	}
+	d.pos += n
+	return v
+}
+
+func (d *decoder) uint64() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data)-d.pos < 8 {
+		d.err = errCorruptBlock
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.data[d.pos:])
+	d.pos += 8
+	return v
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if d.pos >= len(d.data) {
+		d.err = errCorruptBlock
+		return 0
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b
+}
+
+// strings decodes a string table, copying the strings out of the data so that they remain valid
+// once the block is unmapped
+func (d *decoder) strings() []string {
+	count := d.uvarint()
+	strs := make([]string, 0, min(count, uint64(len(d.data))))
+	for i := uint64(0); i < count && d.err == nil; i++ {
+		length := d.uvarint()
+		if d.err != nil {
+			break
+		}
+		if length > uint64(len(d.data)-d.pos) {
+			d.err = errCorruptBlock
+			break
+		}
+		strs = append(strs, string(d.data[d.pos:d.pos+int(length)]))
+		d.pos += int(length)
+	}
+	return strs
+}
+
+func (d *decoder) ref(strs []string) string {
+	ref := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if ref >= uint64(len(strs)) {
+		d.err = errCorruptBlock
+		return ""
+	}
+	return strs[ref]
+}
@@ -0,0 +1,131 @@
+package metric
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric/aggregator"
+)
+
+// sortResults sorts results by their "test" label, as stores return results in no particular order
+func sortResults(results []*aggregator.MetricResult) []*aggregator.MetricResult {
+	slices.SortFunc(results, func(a, b *aggregator.MetricResult) int {
+		return strings.Compare(a.MetricLabels["test"], b.MetricLabels["test"])
+	})
+	return results
+}
+
+func resultsEqual(a, b []*aggregator.MetricResult) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = sortResults(slices.Clone(a)), sortResults(slices.Clone(b))
+	for i := range a {
+		if len(a[i].MetricLabels) != len(b[i].MetricLabels) || len(a[i].Values) != len(b[i].Values) {
+			return false
+		}
+		for k, v := range a[i].MetricLabels {
+			if b[i].MetricLabels[k] != v {
+				return false
+			}
+		}
+		for j := range a[i].Values {
+			av, bv := a[i].Values[j], b[i].Values[j]
+			if av.Value != bv.Value || (av.Timestamp == nil) != (bv.Timestamp == nil) {
+				return false
+			}
+			if av.Timestamp != nil && !av.Timestamp.Equal(*bv.Timestamp) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestBlock_WindowEncoding(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := testMetricCollector()
+	for i, label := range []string{"a", "b", "c"} {
+		store.Update(TestMetric, map[string]string{"test": label}, float64(i), start, nil)
+		store.Update(TestMetric, map[string]string{"test": label}, float64(i*2), start.Add(time.Minute), nil)
+	}
+
+	window, err := encodeWindow(store)
+	if err != nil {
+		t.Fatalf("encodeWindow() error = %v", err)
+	}
+
+	path := blockFileName(t.TempDir(), start.UnixMilli(), start.UnixMilli())
+	if err := writeBlock(path, []int64{start.UnixMilli()}, [][]byte{window}); err != nil {
+		t.Fatalf("writeBlock() error = %v", err)
+	}
+	b, err := openBlock(path)
+	if err != nil {
+		t.Fatalf("openBlock() error = %v", err)
+	}
+	defer b.close()
+
+	encoded, ok := b.window(start.UnixMilli())
+	if !ok {
+		t.Fatalf("block is missing window %d", start.UnixMilli())
+	}
+
+	ids, err := decodeWindowCollectorIDs(encoded)
+	if err != nil {
+		t.Fatalf("decodeWindowCollectorIDs() error = %v", err)
+	}
+	if want := []MetricCollectorID{TestActiveMinutesID, TestAverageID}; !slices.Equal(ids, want) {
+		t.Errorf("decodeWindowCollectorIDs() = %v, want %v", ids, want)
+	}
+
+	for _, id := range ids {
+		want, _ := store.Query(id)
+		got, err := decodeWindowResults(encoded, id)
+		if err != nil {
+			t.Fatalf("decodeWindowResults(%s) error = %v", id, err)
+		}
+		if !resultsEqual(got, want) {
+			t.Errorf("decodeWindowResults(%s) = %v, want %v", id, got, want)
+		}
+	}
+
+	if _, err := decodeWindowResults(encoded, "missing"); err == nil {
+		t.Errorf("decodeWindowResults() of a missing collector succeeded")
+	}
+	if _, err := decodeWindowResults(encoded[:len(encoded)-3], TestAverageID); err == nil {
+		t.Errorf("decodeWindowResults() of a truncated window succeeded")
+	}
+}
+
+func TestBlock_Corrupt(t *testing.T) {
+	dir := t.TempDir()
+	path := blockFileName(dir, 0, 0)
+	window, _ := encodeWindow(testMetricCollector())
+	if err := writeBlock(path, []int64{0}, [][]byte{window}); err != nil {
+		t.Fatalf("writeBlock() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+
+	tests := map[string][]byte{
+		"empty":     {},
+		"truncated": data[:len(data)-1],
+		"bad magic": append([]byte("NOTBLOCK"), data[len(blockMagic):]...),
+		"bad index": append(append(slices.Clone(data[:len(data)-blockFooterSize]), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff), blockMagic...),
+	}
+	for name, corrupt := range tests {
+		t.Run(name, func(t *testing.T) {
+			corruptPath := filepath.Join(dir, name+blockExtension)
+			if err := os.WriteFile(corruptPath, corrupt, 0644); err != nil {
+				t.Fatal(err)
+			}
+			if b, err := openBlock(corruptPath); err == nil {
+				b.close()
+				t.Errorf("openBlock() of %s block succeeded", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package metric
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric/aggregator"
+	"github.com/opencost/opencost/modules/collector-source/pkg/util"
+)
+
+// blockCompactionWindows is the number of windows which blocks are compacted into, so that the number of
+// block files of a resolution stays small regardless of its interval.
+const blockCompactionWindows = 24
+
+// blockStorage holds the blocks of the completed windows of a resolution in a local directory. Each completed
+// window is written to its own block, and blocks are periodically compacted into larger blocks, dropping the
+// windows which are past the retention of the resolution.
+type blockStorage struct {
+	lock       sync.RWMutex
+	dir        string
+	resolution *util.Resolution
+	blocks     []*block         // sorted by the key of their first window
+	windows    map[int64]*block // the block containing each window, by the window key
+}
+
+// openBlockStorage opens the blocks of the resolution in its sub-directory of dir, creating it if it
+// does not exist.
+func openBlockStorage(dir string, resolution *util.Resolution) (*blockStorage, error) {
+	dir = filepath.Join(dir, resolution.Interval())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create block directory '%s': %w", dir, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block directory '%s': %w", dir, err)
+	}
+
+	var blocks []*block
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+
+		// remove blocks which were being written when the process stopped
+		if strings.HasSuffix(entry.Name(), blockTempExt) {
+			if err := os.Remove(path); err != nil {
+				log.Warnf("failed to remove incomplete block '%s': %s", path, err.Error())
+			}
+			continue
+		}
+		if !strings.HasSuffix(entry.Name(), blockExtension) {
+			continue
+		}
+
+		b, err := openBlock(path)
+		if err != nil {
+			log.Errorf("removing unreadable block: %s", err.Error())
+			if err := os.Remove(path); err != nil {
+				log.Warnf("failed to remove block '%s': %s", path, err.Error())
+			}
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+
+	// If the process stopped during compaction, a compacted block may exist alongside the blocks it replaced.
+	// Windows are taken from the blocks with the most windows first, and blocks left with no windows which are
+	// not in another block are removed.
+	slices.SortStableFunc(blocks, func(a, b *block) int {
+		return len(b.windows) - len(a.windows)
+	})
+	s := &blockStorage{
+		dir:        dir,
+		resolution: resolution,
+		windows:    make(map[int64]*block),
+	}
+	for _, b := range blocks {
+		unique := false
+		for _, w := range b.windows {
+			if _, ok := s.windows[w.key]; !ok {
+				unique = true
+			}
+		}
+		if !unique {
+			s.remove(b)
+			continue
+		}
+		for _, w := range b.windows {
+			if _, ok := s.windows[w.key]; !ok {
+				s.windows[w.key] = b
+			}
+		}
+		s.blocks = append(s.blocks, b)
+	}
+	s.sortBlocks()
+
+	return s, nil
+}
+
+func (s *blockStorage) sortBlocks() {
+	slices.SortFunc(s.blocks, func(a, b *block) int {
+		return cmp.Compare(firstKey(a), firstKey(b))
+	})
+}
+
+// blockPath returns the path for a new block containing the windows from the first to the last key. If
+// a block which is still in use has the same path, the path is made unique, so that writing the new
+// block does not replace the file of the block still in use.
+func (s *blockStorage) blockPath(first, last int64) string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	path := blockFileName(s.dir, first, last)
+	if slices.ContainsFunc(s.blocks, func(b *block) bool { return b.path == path }) {
+		path = strings.TrimSuffix(path, blockExtension) + fmt.Sprintf("-%d%s", time.Now().UnixNano(), blockExtension)
+	}
+	return path
+}
+
+func firstKey(b *block) int64 {
+	if len(b.windows) == 0 {
+		return 0
+	}
+	return b.windows[0].key
+}
+
+// keys returns the keys of the windows in the storage
+func (s *blockStorage) keys() []int64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	keys := make([]int64, 0, len(s.windows))
+	for key := range s.windows {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// write writes the results of the collectors of the store for the window with the given key to a new block.
+func (s *blockStorage) write(key int64, store MetricStore) error {
+	window, err := encodeWindow(store)
+	if err != nil {
+		return fmt.Errorf("failed to encode window: %w", err)
+	}
+
+	path := s.blockPath(key, key)
+	if err := writeBlock(path, []int64{key}, [][]byte{window}); err != nil {
+		return fmt.Errorf("failed to write block '%s': %w", path, err)
+	}
+
+	b, err := openBlock(path)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if old, ok := s.windows[key]; ok {
+		// the window was rewritten, so the old window is replaced, which leaves its block to be compacted
+		log.Warnf("replacing window %d of block '%s'", key, old.path)
+	}
+	s.windows[key] = b
+	s.blocks = append(s.blocks, b)
+	s.sortBlocks()
+	return nil
+}
+
+// query decodes the results of the collector with the given id in the window with the given key.
+func (s *blockStorage) query(key int64, id MetricCollectorID) ([]*aggregator.MetricResult, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	window, err := s.window(key)
+	if err != nil {
+		return nil, err
+	}
+	results, err := decodeWindowResults(window, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode results of '%s' for window %d: %w", id, key, err)
+	}
+	return results, nil
+}
+
+func (s *blockStorage) collectorIDs(key int64) ([]MetricCollectorID, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	window, err := s.window(key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeWindowCollectorIDs(window)
+}
+
+// window returns the encoding of the window with the given key. The caller must hold the lock, as the
+// encoding is only valid until its block is compacted.
+func (s *blockStorage) window(key int64) ([]byte, error) {
+	b, ok := s.windows[key]
+	if !ok {
+		return nil, fmt.Errorf("no block for window %d of resolution '%s'", key, s.resolution.Interval())
+	}
+	window, ok := b.window(key)
+	if !ok {
+		return nil, fmt.Errorf("window %d missing from block '%s'", key, b.path)
+	}
+	return window, nil
+}
+
+// compact removes the windows before the limit key, and merges consecutive blocks into blocks of up to
+// blockCompactionWindows windows. Merged blocks are written before the blocks they replace are removed,
+// so that windows are never lost if the process stops while compacting.
+func (s *blockStorage) compact(limitKey int64) error {
+	// blocks are only removed by compaction, so they can be read without holding the lock while the
+	// compacted blocks are written
+	s.lock.RLock()
+	blocks := slices.Clone(s.blocks)
+	live := make(map[*block][]blockWindow, len(blocks))
+	for _, b := range blocks {
+		for _, w := range b.windows {
+			if w.key >= limitKey && s.windows[w.key] == b {
+				live[b] = append(live[b], w)
+			}
+		}
+	}
+	s.lock.RUnlock()
+
+	var expired []*block
+	var groups [][]*block
+	var group []*block
+	groupWindows := 0
+	for _, b := range blocks {
+		if len(live[b]) == 0 {
+			expired = append(expired, b)
+			continue
+		}
+		if groupWindows+len(live[b]) > blockCompactionWindows && len(group) > 0 {
+			groups = append(groups, group)
+			group, groupWindows = nil, 0
+		}
+		group = append(group, b)
+		groupWindows += len(live[b])
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	var errs []error
+	for _, group := range groups {
+		if len(group) == 1 && len(live[group[0]]) == len(group[0].windows) {
+			continue
+		}
+		if err := s.merge(group, live); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(expired) > 0 {
+		s.lock.Lock()
+		for _, b := range expired {
+			s.removeBlock(b)
+		}
+		s.lock.Unlock()
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to compact blocks of resolution '%s': %v", s.resolution.Interval(), errs)
+	}
+	return nil
+}
+
+// merge writes the live windows of the group of blocks to a single block which replaces them.
+func (s *blockStorage) merge(group []*block, live map[*block][]blockWindow) error {
+	var keys []int64
+	var windows [][]byte
+	for _, b := range group {
+		for _, w := range live[b] {
+			keys = append(keys, w.key)
+			windows = append(windows, b.data[w.offset:w.offset+w.length])
+		}
+	}
+
+	path := s.blockPath(keys[0], keys[len(keys)-1])
+	if err := writeBlock(path, keys, windows); err != nil {
+		return fmt.Errorf("failed to write block '%s': %w", path, err)
+	}
+	merged, err := openBlock(path)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, w := range merged.windows {
+		// a window may have been rewritten to a new block while merging, which takes precedence
+		if current, ok := s.windows[w.key]; ok && !slices.Contains(group, current) {
+			continue
+		}
+		s.windows[w.key] = merged
+	}
+	s.blocks = append(s.blocks, merged)
+	for _, b := range group {
+		s.removeBlock(b)
+	}
+	s.sortBlocks()
+	return nil
+}
+
+// removeBlock removes a block from the storage and deletes its file. The caller must hold the lock.
+func (s *blockStorage) removeBlock(b *block) {
+	s.blocks = slices.DeleteFunc(s.blocks, func(other *block) bool { return other == b })
+	for key, other := range s.windows {
+		if other == b {
+			delete(s.windows, key)
+		}
+	}
+	s.remove(b)
+}
+
+// remove unmaps a block and deletes its file
+func (s *blockStorage) remove(b *block) {
+	if err := b.close(); err != nil {
+		log.Warnf("failed to unmap block '%s': %s", b.path, err.Error())
+	}
+	if err := os.Remove(b.path); err != nil {
+		log.Warnf("failed to remove block '%s': %s", b.path, err.Error())
+	}
+}
+
+// blockMetricStore is a read-only MetricStore implementation which serves the results of the collectors of a
+// completed window from the blocks of a blockStorage. Results are decoded from the memory mapped blocks as they
+// are queried, so completed windows take no memory between queries.
+type blockMetricStore struct {
+	storage *blockStorage
+	key     int64
+}
+
+func newBlockMetricStore(storage *blockStorage, key int64) MetricStore {
+	return &blockMetricStore{
+		storage: storage,
+		key:     key,
+	}
+}
+
+func (b *blockMetricStore) Register(collector *MetricCollector) error {
+	return fmt.Errorf("cannot register metric with ID: %s to the block of a completed window", collector.id)
+}
+
+func (b *blockMetricStore) Unregister(collectorID MetricCollectorID) bool {
+	return false
+}
+
+func (b *blockMetricStore) CollectorIDs() []MetricCollectorID {
+	ids, err := b.storage.collectorIDs(b.key)
+	if err != nil {
+		log.Errorf("failed to read collectors of window %d: %s", b.key, err.Error())
+		return nil
+	}
+	return ids
+}
+
+func (b *blockMetricStore) Query(collectorID MetricCollectorID) ([]*aggregator.MetricResult, error) {
+	return b.storage.query(b.key, collectorID)
+}
+
+// Update is a no-op, as the window of the block has completed. The repository rejects updates to sealed windows
+// before they reach the store.
+func (b *blockMetricStore) Update(
+	metricName string,
+	labels map[string]string,
+	value float64,
+	timestamp time.Time,
+	additionalInformation map[string]string,
+) {
+}
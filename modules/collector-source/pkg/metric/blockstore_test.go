@@ -0,0 +1,297 @@
+package metric
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/storage"
+	"github.com/opencost/opencost/modules/collector-source/pkg/util"
+)
+
+func testUpdateSet(timestamp time.Time, value float64) *UpdateSet {
+	return &UpdateSet{
+		Timestamp: timestamp,
+		Updates: []Update{
+			{
+				Name: TestMetric,
+				Labels: map[string]string{
+					"test": "test",
+				},
+				Value: value,
+			},
+		},
+	}
+}
+
+func TestBlockMetricRepository(t *testing.T) {
+	dir := t.TempDir()
+	res1h, _ := util.NewResolution(util.ResolutionConfiguration{
+		Interval:  "1h",
+		Retention: 10,
+	})
+	resolutions := []*util.Resolution{res1h}
+
+	window1 := time.Now().UTC().Truncate(time.Hour).Add(-5 * time.Hour)
+	window2 := window1.Add(time.Hour)
+	updateSets := []*UpdateSet{
+		testUpdateSet(window1, 1),
+		testUpdateSet(window1.Add(30*time.Minute), 2),
+		// the update at the start of window2 also applies to window1, so window1 is not sealed yet
+		testUpdateSet(window2, 3),
+		testUpdateSet(window2.Add(30*time.Minute), 4),
+	}
+
+	memRepo := NewMetricRepository(resolutions, testMetricCollector)
+	blockRepo := NewBlockMetricRepository(resolutions, testMetricCollector, dir, 0)
+	for i, updateSet := range updateSets {
+		memRepo.Update(updateSet)
+		blockRepo.Update(updateSet)
+
+		_, sealed := blockRepo.resolutionStores["1h"].collectors[window1.UnixMilli()].(*blockMetricStore)
+		if want := i == len(updateSets)-1; sealed != want {
+			t.Errorf("after update %d window1 sealed = %t, want %t", i, sealed, want)
+		}
+	}
+
+	if durable := blockRepo.Durable(); !durable.Equal(window2) {
+		t.Errorf("Durable() = %v, want %v", durable, window2)
+	}
+	if durable, limit := memRepo.Durable(), res1h.Limit(); !durable.Equal(limit) {
+		t.Errorf("in memory Durable() = %v, want %v", durable, limit)
+	}
+
+	// a restarted repository serves the sealed window from its block
+	restartedRepo := NewBlockMetricRepository(resolutions, testMetricCollector, dir, 0)
+	for _, repo := range []*MetricRepository{blockRepo, restartedRepo} {
+		memStore, err := memRepo.GetCollector("1h", window1)
+		if err != nil {
+			t.Fatalf("GetCollector() error = %v", err)
+		}
+		blockStore, err := repo.GetCollector("1h", window1)
+		if err != nil {
+			t.Fatalf("GetCollector() error = %v", err)
+		}
+		if ids := blockStore.CollectorIDs(); len(ids) != 2 {
+			t.Errorf("CollectorIDs() = %v, want 2 collectors", ids)
+		}
+		for _, id := range []MetricCollectorID{TestActiveMinutesID, TestAverageID} {
+			want, _ := memStore.Query(id)
+			got, err := blockStore.Query(id)
+			if err != nil {
+				t.Fatalf("Query(%s) error = %v", id, err)
+			}
+			if !resultsEqual(got, want) {
+				t.Errorf("Query(%s) = %v, want %v", id, got, want)
+			}
+		}
+
+		// updates to the sealed window are ignored
+		blockStore.Update(TestMetric, map[string]string{"test": "other"}, 1, window1, nil)
+		if results, _ := blockStore.Query(TestAverageID); len(results) != 1 {
+			t.Errorf("sealed window was updated: %v", results)
+		}
+	}
+
+	// the current window of the restarted repository is restored by replaying updates since it was durable
+	if _, err := restartedRepo.GetCollector("1h", window2); err == nil {
+		t.Errorf("GetCollector() of the unsealed window succeeded before it was restored")
+	}
+}
+
+func TestBlockMetricRepository_LateUpdates(t *testing.T) {
+	dir := t.TempDir()
+	res1h, _ := util.NewResolution(util.ResolutionConfiguration{
+		Interval:  "1h",
+		Retention: 10,
+	})
+	resolutions := []*util.Resolution{res1h}
+
+	window1 := time.Now().UTC().Truncate(time.Hour).Add(-5 * time.Hour)
+	window2 := window1.Add(time.Hour)
+	updateSets := []*UpdateSet{
+		testUpdateSet(window1, 1),
+		testUpdateSet(window2, 3),
+		testUpdateSet(window2.Add(5*time.Minute), 4),
+		// window1 is still open during the grace period, so the late update is applied
+		testUpdateSet(window1.Add(45*time.Minute), 2),
+		testUpdateSet(window2.Add(15*time.Minute), 5),
+	}
+
+	memRepo := NewMetricRepository(resolutions, testMetricCollector)
+	blockRepo := NewBlockMetricRepository(resolutions, testMetricCollector, dir, 10*time.Minute)
+	for i, updateSet := range updateSets {
+		memRepo.Update(updateSet)
+		blockRepo.Update(updateSet)
+
+		_, sealed := blockRepo.resolutionStores["1h"].collectors[window1.UnixMilli()].(*blockMetricStore)
+		if want := i == len(updateSets)-1; sealed != want {
+			t.Errorf("after update %d window1 sealed = %t, want %t", i, sealed, want)
+		}
+	}
+
+	// once window1 is sealed, later updates to it are rejected and counted
+	blockRepo.Update(testUpdateSet(window1.Add(50*time.Minute), 6))
+	if late := blockRepo.LateUpdates(); late["1h"] != 1 {
+		t.Errorf("LateUpdates() = %v, want 1 for resolution 1h", late)
+	}
+
+	memStore, err := memRepo.GetCollector("1h", window1)
+	if err != nil {
+		t.Fatalf("GetCollector() error = %v", err)
+	}
+	blockStore, err := blockRepo.GetCollector("1h", window1)
+	if err != nil {
+		t.Fatalf("GetCollector() error = %v", err)
+	}
+	for _, id := range []MetricCollectorID{TestActiveMinutesID, TestAverageID} {
+		want, _ := memStore.Query(id)
+		got, err := blockStore.Query(id)
+		if err != nil {
+			t.Fatalf("Query(%s) error = %v", id, err)
+		}
+		if !resultsEqual(got, want) {
+			t.Errorf("Query(%s) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestBlockStorage_Compact(t *testing.T) {
+	dir := t.TempDir()
+	res1h, _ := util.NewResolution(util.ResolutionConfiguration{
+		Interval:  "1h",
+		Retention: 100,
+	})
+
+	blocks, err := openBlockStorage(dir, res1h)
+	if err != nil {
+		t.Fatalf("openBlockStorage() error = %v", err)
+	}
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var keys []int64
+	for i := 0; i < 60; i++ {
+		key := start.Add(time.Duration(i) * time.Hour).UnixMilli()
+		store := testMetricCollector()
+		store.Update(TestMetric, map[string]string{"test": "test"}, float64(i), time.UnixMilli(key), nil)
+		if err := blocks.write(key, store); err != nil {
+			t.Fatalf("write() error = %v", err)
+		}
+		keys = append(keys, key)
+	}
+
+	blockFiles := func() int {
+		matches, _ := filepath.Glob(filepath.Join(dir, "1h", "*"+blockExtension))
+		return len(matches)
+	}
+	if n := blockFiles(); n != 60 {
+		t.Fatalf("wrote %d block files, want 60", n)
+	}
+
+	// the first 10 windows are past the limit
+	limitKey := keys[10]
+	if err := blocks.compact(limitKey); err != nil {
+		t.Fatalf("compact() error = %v", err)
+	}
+
+	if got := blocks.keys(); !slices.Equal(got, keys[10:]) {
+		t.Errorf("keys() after compaction = %v, want %v", got, keys[10:])
+	}
+	// 50 windows in blocks of up to 24 windows
+	if n := blockFiles(); n != 3 {
+		t.Errorf("%d block files after compaction, want 3", n)
+	}
+
+	for i, key := range keys[10:] {
+		results, err := blocks.query(key, TestAverageID)
+		if err != nil {
+			t.Fatalf("query() error = %v", err)
+		}
+		if len(results) != 1 || results[0].Values[0].Value != float64(i+10) {
+			t.Errorf("query() of window %d = %v, want %d", i+10, results, i+10)
+		}
+	}
+
+	// compacting again changes nothing
+	if err := blocks.compact(limitKey); err != nil {
+		t.Fatalf("compact() error = %v", err)
+	}
+	if n := blockFiles(); n != 3 {
+		t.Errorf("%d block files after second compaction, want 3", n)
+	}
+
+	// blocks left behind by compaction which stopped before removing them are removed on open, along with
+	// blocks which were being written
+	reopened := func() *blockStorage {
+		b, err := openBlockStorage(dir, res1h)
+		if err != nil {
+			t.Fatalf("openBlockStorage() error = %v", err)
+		}
+		return b
+	}
+	store := testMetricCollector()
+	window, _ := encodeWindow(store)
+	if err := writeBlock(blockFileName(filepath.Join(dir, "1h"), keys[20], keys[20]), []int64{keys[20]}, [][]byte{window}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "1h", "partial"+blockExtension+blockTempExt), []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	blocks = reopened()
+	if got := blocks.keys(); !slices.Equal(got, keys[10:]) {
+		t.Errorf("keys() after reopening = %v, want %v", got, keys[10:])
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "1h", "*")); len(matches) != 3 {
+		t.Errorf("%d files after reopening, want 3: %v", len(matches), matches)
+	}
+}
+
+func TestWalinator_restoreDurable(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewMemoryStorage()
+	res1h, _ := util.NewResolution(util.ResolutionConfiguration{
+		Interval:  "1h",
+		Retention: 10,
+	})
+	resolutions := []*util.Resolution{res1h}
+
+	repo := NewBlockMetricRepository(resolutions, testMetricCollector, dir, 0)
+	wal, _ := NewWalinator("test", "test", store, resolutions, repo)
+
+	window1 := time.Now().UTC().Truncate(time.Hour).Add(-5 * time.Hour)
+	window2 := window1.Add(time.Hour)
+	wal.Update(testUpdateSet(window1, 1))
+	wal.Update(testUpdateSet(window2, 2))
+	wal.Update(testUpdateSet(window2.Add(30*time.Minute), 3))
+
+	// the update of window1 has been written to a block, so it is cleaned from the WAL
+	wal.clean()
+	files, _ := store.List(wal.paths.Dir())
+	if len(files) != 2 {
+		t.Errorf("%d WAL files after cleaning, want 2", len(files))
+	}
+
+	restartedRepo := NewBlockMetricRepository(resolutions, testMetricCollector, dir, 0)
+	wal.updater = restartedRepo
+	wal.restore()
+
+	for _, window := range []time.Time{window1, window2} {
+		want, err := repo.GetCollector("1h", window)
+		if err != nil {
+			t.Fatalf("GetCollector() error = %v", err)
+		}
+		got, err := restartedRepo.GetCollector("1h", window)
+		if err != nil {
+			t.Fatalf("GetCollector() of restored repository error = %v", err)
+		}
+		for _, id := range []MetricCollectorID{TestActiveMinutesID, TestAverageID} {
+			wantResults, _ := want.Query(id)
+			gotResults, _ := got.Query(id)
+			if !resultsEqual(gotResults, wantResults) {
+				t.Errorf("restored Query(%s) of window %v = %v, want %v", id, window, gotResults, wantResults)
+			}
+		}
+	}
+}
@@ -45,11 +45,13 @@ const (
 	RAMLimitsID                                MetricCollectorID = "RAMLimits"
 	RAMUsageAverageID                          MetricCollectorID = "RAMUsageAverage"
 	RAMUsageMaxID                              MetricCollectorID = "RAMUsageMax"
+	RAMUsageQuantilesID                        MetricCollectorID = "RAMUsageQuantiles"
 	CPUCoresAllocatedID                        MetricCollectorID = "CPUCoresAllocated"
 	CPURequestsID                              MetricCollectorID = "CPURequestsID"
 	CPULimitsID                                MetricCollectorID = "CPULimitsID"
 	CPUUsageAverageID                          MetricCollectorID = "CPUUsageAverage"
 	CPUUsageMaxID                              MetricCollectorID = "CPUUsageMax"
+	CPUUsageQuantilesID                        MetricCollectorID = "CPUUsageQuantiles"
 	GPUsRequestedID                            MetricCollectorID = "GPUsRequested"
 	GPUsUsageAverageID                         MetricCollectorID = "GPUsUsageAverage"
 	GPUsUsageMaxID                             MetricCollectorID = "GPUsUsageMax"
@@ -0,0 +1,38 @@
+//go:build darwin || dragonfly || freebsd || illumos || linux || netbsd || openbsd
+
+// The above platforms support mmap()
+
+package metric
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the file at the given path into memory read-only, returning its contents and a
+// function which unmaps them. Pages of the file are only read from disk as they are accessed, and
+// may be evicted under memory pressure, so the memory used by blocks does not grow with retention.
+func mmapFile(path string) ([]byte, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := int(info.Size())
+	if size == 0 {
+		return nil, func() error { return nil }, nil
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
@@ -19,6 +19,29 @@ type MetricRepository struct {
 func NewMetricRepository(
 	resolutions []*util.Resolution,
 	storeFactory MetricStoreFactory,
+) *MetricRepository {
+	return newMetricRepository(resolutions, storeFactory, "", 0)
+}
+
+// NewBlockMetricRepository creates a MetricRepository which keeps only the MetricStore instances of the current
+// windows of each resolution in memory. Once a window has completed and the grace period has passed, the results of
+// its collectors are written to a block file in a sub-directory of dir for the resolution, and served from there.
+// Updates which arrive later for the window are rejected with a warning. Blocks written before a restart are served
+// without replaying the updates of their windows.
+func NewBlockMetricRepository(
+	resolutions []*util.Resolution,
+	storeFactory MetricStoreFactory,
+	dir string,
+	gracePeriod time.Duration,
+) *MetricRepository {
+	return newMetricRepository(resolutions, storeFactory, dir, gracePeriod)
+}
+
+func newMetricRepository(
+	resolutions []*util.Resolution,
+	storeFactory MetricStoreFactory,
+	dir string,
+	gracePeriod time.Duration,
 ) *MetricRepository {
 	resoluationCollectors := make(map[string]*resolutionStores)
 	var limitResolution *util.Resolution
@@ -26,7 +49,7 @@ func NewMetricRepository(
 		if limitResolution == nil || resolution.Limit().Before(limitResolution.Limit()) {
 			limitResolution = resolution
 		}
-		resCollector, err := newResolutionStores(resolution, storeFactory)
+		resCollector, err := newResolutionStores(resolution, storeFactory, dir, gracePeriod)
 		if err != nil {
 			log.Errorf("NewMetricRepository: failed to init resolution metric: %s", err.Error())
 			continue
@@ -68,6 +91,34 @@ func (r *MetricRepository) Update(
 
 }
 
+// LateUpdates returns the number of updates which were rejected by each resolution because their window had already
+// been written to a block.
+func (r *MetricRepository) LateUpdates() map[string]uint64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := make(map[string]uint64, len(r.resolutionStores))
+	for resKey, resCollector := range r.resolutionStores {
+		result[resKey] = resCollector.lateUpdates
+	}
+	return result
+}
+
+// Durable returns the time from which updates have not been persisted to blocks by every resolution, and would
+// need to be applied again to restore the repository after a restart. Resolutions without blocks persist
+// nothing, so for them this is the start of their retention.
+func (r *MetricRepository) Durable() time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var durable time.Time
+	for _, resCollector := range r.resolutionStores {
+		resDurable := resCollector.durable()
+		if durable.IsZero() || resDurable.Before(durable) {
+			durable = resDurable
+		}
+	}
+	return durable
+}
+
 func (r *MetricRepository) Coverage() map[string][]time.Time {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -82,19 +133,41 @@ func (r *MetricRepository) Coverage() map[string][]time.Time {
 	return result
 }
 
-// resolutionStores is a grouping of a resolution and the instances of MetricStore that it is used to manage
+// resolutionStores is a grouping of a resolution and the instances of MetricStore that it is used to manage.
+// If it has blocks, the MetricStore instances of completed windows are sealed by writing them to the blocks
+// and replacing them with read-only stores which query the blocks. Windows are sealed once the grace period
+// has passed since they completed, so that late and out of order updates are still applied.
 type resolutionStores struct {
-	lock       sync.Mutex
-	resolution *util.Resolution
-	collectors map[int64]MetricStore
-	factory    func() MetricStore
+	lock        sync.Mutex
+	resolution  *util.Resolution
+	collectors  map[int64]MetricStore
+	factory     func() MetricStore
+	blocks      *blockStorage
+	gracePeriod time.Duration
+	lateUpdates uint64
 }
 
-func newResolutionStores(resolution *util.Resolution, factory MetricStoreFactory) (*resolutionStores, error) {
+func newResolutionStores(resolution *util.Resolution, factory MetricStoreFactory, dir string, gracePeriod time.Duration) (*resolutionStores, error) {
 	resCol := &resolutionStores{
-		resolution: resolution,
-		collectors: map[int64]MetricStore{},
-		factory:    factory,
+		resolution:  resolution,
+		collectors:  map[int64]MetricStore{},
+		factory:     factory,
+		gracePeriod: gracePeriod,
+	}
+
+	if dir != "" {
+		blocks, err := openBlockStorage(dir, resolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open blocks for resolution '%s': %w", resolution.Interval(), err)
+		}
+		resCol.blocks = blocks
+
+		limitKey := resolution.Limit().UnixMilli()
+		for _, key := range blocks.keys() {
+			if key >= limitKey {
+				resCol.collectors[key] = newBlockMetricStore(blocks, key)
+			}
+		}
 	}
 
 	// Start loop which will remove expired MetricStore
@@ -110,13 +183,21 @@ func newResolutionStores(resolution *util.Resolution, factory MetricStoreFactory
 
 func (r *resolutionStores) clean() {
 	r.lock.Lock()
-	defer r.lock.Unlock()
 	limitKey := r.resolution.Limit().UnixMilli()
 	for key := range r.collectors {
 		if key < limitKey {
 			delete(r.collectors, key)
 		}
 	}
+	r.lock.Unlock()
+
+	// compaction only replaces the blocks behind the stores of completed windows, so it runs without holding
+	// the lock to avoid blocking updates
+	if r.blocks != nil {
+		if err := r.blocks.compact(limitKey); err != nil {
+			log.Errorf("failed to compact blocks: %s", err.Error())
+		}
+	}
 }
 
 func (r *resolutionStores) update(
@@ -143,6 +224,16 @@ func (r *resolutionStores) update(
 		collector = r.factory()
 		r.collectors[key] = collector
 	}
+	if _, sealed := collector.(*blockMetricStore); sealed {
+		r.lateUpdates++
+		log.Warnf(
+			"rejecting update on resolution '%s' because the window of Timestamp '%s' was written to a block %s after it completed",
+			r.resolution.Interval(),
+			updateSet.Timestamp.Format(time.RFC3339),
+			r.gracePeriod,
+		)
+		return
+	}
 
 	for _, update := range updateSet.Updates {
 		collector.Update(update.Name, update.Labels, update.Value, updateSet.Timestamp, update.AdditionalInfo)
@@ -157,6 +248,56 @@ func (r *resolutionStores) update(
 			}
 		}
 	}
+
+	if r.blocks != nil {
+		r.seal(updateSet.Timestamp, limit)
+	}
+}
+
+// seal writes the stores of the windows which ended more than the grace period before the given time to blocks,
+// and replaces them with stores which query the blocks. A window which ends at the time is not sealed, as updates
+// at the start of a window also apply to the previous window.
+func (r *resolutionStores) seal(t time.Time, limit time.Time) {
+	limitKey := limit.UnixMilli()
+	for key, collector := range r.collectors {
+		if _, ok := collector.(*blockMetricStore); ok || key < limitKey {
+			continue
+		}
+		if !r.resolution.End(time.UnixMilli(key).UTC()).Add(r.gracePeriod).Before(t) {
+			continue
+		}
+
+		if err := r.blocks.write(key, collector); err != nil {
+			log.DedupedErrorf(5, "failed to write block for window '%s' of resolution '%s': %s", time.UnixMilli(key).UTC().Format(time.RFC3339), r.resolution.Interval(), err.Error())
+			continue
+		}
+		r.collectors[key] = newBlockMetricStore(r.blocks, key)
+	}
+}
+
+// durable returns the time from which updates to the resolution have not been written to blocks
+func (r *resolutionStores) durable() time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	limit := r.resolution.Limit()
+	if r.blocks == nil {
+		return limit
+	}
+
+	var durable time.Time
+	for key, collector := range r.collectors {
+		if _, ok := collector.(*blockMetricStore); !ok {
+			continue
+		}
+		end := r.resolution.End(time.UnixMilli(key).UTC())
+		if end.After(durable) {
+			durable = end
+		}
+	}
+	if durable.Before(limit) {
+		return limit
+	}
+	return durable
 }
 
 func (r *resolutionStores) getCollector(t time.Time) (MetricStore, error) {
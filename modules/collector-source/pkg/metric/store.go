@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/modules/collector-source/pkg/metric/aggregator"
 )
 
@@ -20,6 +21,9 @@ type MetricStore interface {
 	// updates and query availability.
 	Unregister(collectorID MetricCollectorID) bool
 
+	// CollectorIDs returns the `MetricCollectorID` of each registered metric collector.
+	CollectorIDs() []MetricCollectorID
+
 	// Query accepts a `MetricCollectorID` and returns a slice of `MetricResult` instances for that metric.
 	Query(collectorID MetricCollectorID) ([]*aggregator.MetricResult, error)
 
@@ -76,6 +80,17 @@ func (m *InMemoryMetricStore) Unregister(collectorID MetricCollectorID) bool {
 	return true
 }
 
+func (m *InMemoryMetricStore) CollectorIDs() []MetricCollectorID {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ids := make([]MetricCollectorID, 0, len(m.byCollectorID))
+	for id := range m.byCollectorID {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
 func (m *InMemoryMetricStore) Query(collectorID MetricCollectorID) ([]*aggregator.MetricResult, error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -97,7 +112,24 @@ func (m *InMemoryMetricStore) Update(
 	m.lock.Lock()
 	defer m.lock.Unlock()
 
-	for _, collector := range m.byMetricName[metricName] {
+	collectors := m.byMetricName[metricName]
+	if len(collectors) == 0 {
+		dropMetric(metricName)
+		return
+	}
+
+	for _, collector := range collectors {
 		collector.Update(labels, value, timestamp, additionalInformation)
 	}
 }
+
+// droppedMetrics holds the names of the metrics which have been dropped by any store, as stores are created
+// for each window of each resolution.
+var droppedMetrics sync.Map
+
+// dropMetric logs the name of a metric which no collector tracks the first time its samples are dropped.
+func dropMetric(metricName string) {
+	if _, loaded := droppedMetrics.LoadOrStore(metricName, struct{}{}); !loaded {
+		log.Infof("MetricStore: dropping samples of metric '%s', which no collector tracks", metricName)
+	}
+}
@@ -0,0 +1,15 @@
+package metric
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInMemoryMetricStoreDropsUntrackedMetrics(t *testing.T) {
+	store := NewInMemoryMetricStore()
+	store.Update("untracked_metric", map[string]string{"node": "node1"}, 1, time.Now(), nil)
+
+	if _, ok := droppedMetrics.Load("untracked_metric"); !ok {
+		t.Errorf("expected untracked_metric to be recorded as dropped")
+	}
+}
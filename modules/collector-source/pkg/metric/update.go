@@ -19,3 +19,10 @@ type Update struct {
 type Updater interface {
 	Update(*UpdateSet)
 }
+
+// DurableUpdater is an Updater which persists updates itself, so that updates from before the time returned by
+// Durable do not need to be applied again to restore its state.
+type DurableUpdater interface {
+	Updater
+	Durable() time.Time
+}
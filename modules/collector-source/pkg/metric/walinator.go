@@ -81,13 +81,25 @@ func (w *Walinator) Start() {
 	}()
 }
 
+// limit returns the time before which updates are no longer needed to restore the previous updater(repo), because
+// they are past the retention of every resolution, or because the updater has persisted them itself
+func (w *Walinator) limit() time.Time {
+	limit := w.limitResolution.Limit()
+	if durableUpdater, ok := w.updater.(DurableUpdater); ok {
+		if durable := durableUpdater.Durable(); durable.After(limit) {
+			limit = durable
+		}
+	}
+	return limit
+}
+
 // restore applies updates from wal files to restore the state of the previous updater(repo)
 func (w *Walinator) restore() {
 	fileInfos, err := w.getFileInfos()
 	if err != nil {
 		log.Errorf("failed to retrieve updates files: %s", err.Error())
 	}
-	limit := w.limitResolution.Limit()
+	limit := w.limit()
 
 	workerFn := func(fi fileInfo) *UpdateSet {
 		if fi.timestamp.Before(limit) {
@@ -205,7 +217,7 @@ func (w *Walinator) clean() {
 	if err != nil {
 		log.Errorf("failed to retrieve file info for cleaning: %s", err.Error())
 	}
-	limit := w.limitResolution.Limit()
+	limit := w.limit()
 	for _, fi := range fileInfos {
 		if !limit.After(fi.timestamp) {
 			continue
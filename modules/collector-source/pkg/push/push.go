@@ -0,0 +1,36 @@
+package push
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+)
+
+// PushPath is the path at which a central collector receives the updates pushed by the
+// collector agents of other clusters.
+const PushPath = "/collector/push"
+
+// maxDecodedBytes is the maximum size of a decompressed push request.
+const maxDecodedBytes = 64 * 1024 * 1024
+
+// clusterIDPattern restricts cluster ids to characters which are safe to use in the paths
+// at which the central collector stores the metrics of each cluster.
+var clusterIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Request is the body of a push request, holding the UpdateSets of the scrapes of a single
+// cluster in chronological order along with the info of the cluster.
+type Request struct {
+	ClusterID   string              `json:"clusterId"`
+	ClusterInfo map[string]string   `json:"clusterInfo"`
+	UpdateSets  []*metric.UpdateSet `json:"updateSets"`
+}
+
+// ValidateClusterID returns an error if the cluster id cannot be used to identify the
+// metrics of a cluster on the central collector.
+func ValidateClusterID(clusterID string) error {
+	if !clusterIDPattern.MatchString(clusterID) {
+		return fmt.Errorf("invalid cluster id '%s': must start with a letter or digit and contain only letters, digits, '.', '_' and '-'", clusterID)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+)
+
+type recordingClusterUpdater struct {
+	lock        sync.Mutex
+	fail        bool
+	limit       bool
+	clusterInfo map[string]map[string]string
+	updateSets  map[string][]*metric.UpdateSet
+}
+
+func (u *recordingClusterUpdater) UpdateCluster(clusterID string, clusterInfo map[string]string, updateSets []*metric.UpdateSet) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.fail {
+		return fmt.Errorf("unavailable")
+	}
+	if u.limit {
+		return fmt.Errorf("%w: 1 clusters", ErrClusterLimit)
+	}
+	if u.clusterInfo == nil {
+		u.clusterInfo = make(map[string]map[string]string)
+		u.updateSets = make(map[string][]*metric.UpdateSet)
+	}
+	u.clusterInfo[clusterID] = clusterInfo
+	u.updateSets[clusterID] = append(u.updateSets[clusterID], updateSets...)
+	return nil
+}
+
+func (u *recordingClusterUpdater) setFail(fail bool) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.fail = fail
+}
+
+type staticClusterInfo map[string]string
+
+func (s staticClusterInfo) GetClusterInfo() map[string]string {
+	return s
+}
+
+func newTestServer(t *testing.T, token string) (*httptest.Server, *recordingClusterUpdater) {
+	t.Helper()
+
+	updater := &recordingClusterUpdater{}
+	router := httprouter.New()
+	router.POST(PushPath, NewReceiver(updater, token).Handle)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, updater
+}
+
+func testUpdateSet(timestamp time.Time, value float64) *metric.UpdateSet {
+	return &metric.UpdateSet{
+		Timestamp: timestamp,
+		Updates: []metric.Update{
+			{
+				Name:   "node_total_hourly_cost",
+				Labels: map[string]string{"node": "node1"},
+				Value:  value,
+			},
+		},
+	}
+}
+
+func TestPusher(t *testing.T) {
+	server, updater := newTestServer(t, "secret")
+	clusterInfo := staticClusterInfo{"id": "cluster-1", "name": "Cluster One"}
+
+	pusher, err := NewPusher(PusherConfig{
+		URL:       server.URL + PushPath,
+		Token:     "secret",
+		ClusterID: "cluster-1",
+	}, clusterInfo, nil)
+	if err != nil {
+		t.Fatalf("NewPusher() error = %v", err)
+	}
+
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var want []*metric.UpdateSet
+	for i := range maxBatchUpdateSets + 5 {
+		updateSet := testUpdateSet(t1.Add(time.Duration(i)*time.Minute), float64(i))
+		want = append(want, updateSet)
+		pusher.Update(updateSet)
+	}
+
+	// updates are kept while the central collector fails, and pushed in order once it recovers
+	updater.setFail(true)
+	if err := pusher.Flush(context.Background()); err == nil {
+		t.Fatalf("Flush() succeeded, want error")
+	}
+	if got := pusher.Buffered(); got != len(want) {
+		t.Fatalf("Buffered() after failed push = %d, want %d", got, len(want))
+	}
+
+	updater.setFail(false)
+	if err := pusher.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := pusher.Buffered(); got != 0 {
+		t.Errorf("Buffered() after push = %d, want 0", got)
+	}
+
+	if !reflect.DeepEqual(updater.clusterInfo["cluster-1"], map[string]string(clusterInfo)) {
+		t.Errorf("cluster info = %v, want %v", updater.clusterInfo["cluster-1"], clusterInfo)
+	}
+	got := updater.updateSets["cluster-1"]
+	if len(got) != len(want) {
+		t.Fatalf("received %d update sets, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Timestamp.Equal(want[i].Timestamp) || !reflect.DeepEqual(got[i].Updates, want[i].Updates) {
+			t.Errorf("update set %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPusher_BufferSize(t *testing.T) {
+	pusher, err := NewPusher(PusherConfig{
+		URL:        "http://localhost" + PushPath,
+		ClusterID:  "cluster-1",
+		BufferSize: 3,
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewPusher() error = %v", err)
+	}
+
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		pusher.Update(testUpdateSet(t1.Add(time.Duration(i)*time.Minute), float64(i)))
+	}
+
+	// the oldest updates are dropped
+	if got := pusher.Buffered(); got != 3 {
+		t.Fatalf("Buffered() = %d, want 3", got)
+	}
+	if got := pusher.buffer[0].Updates[0].Value; got != 2 {
+		t.Errorf("oldest buffered value = %v, want 2", got)
+	}
+}
+
+func TestReceiver_Errors(t *testing.T) {
+	tests := map[string]struct {
+		serverToken string
+		limit       bool
+		token       string
+		clusterID   string
+		status      int
+	}{
+		"invalid token": {
+			serverToken: "secret",
+			token:       "wrong",
+			clusterID:   "cluster-1",
+			status:      http.StatusUnauthorized,
+		},
+		"no server token": {
+			serverToken: "",
+			token:       "",
+			clusterID:   "cluster-1",
+			status:      http.StatusUnauthorized,
+		},
+		"invalid cluster id": {
+			serverToken: "secret",
+			token:       "secret",
+			clusterID:   "../cluster-1",
+			status:      http.StatusBadRequest,
+		},
+		"cluster limit": {
+			serverToken: "secret",
+			limit:       true,
+			token:       "secret",
+			clusterID:   "cluster-1",
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server, updater := newTestServer(t, tt.serverToken)
+			updater.limit = tt.limit
+
+			// the cluster id is validated by NewPusher, so the config is set after creating the pusher
+			pusher, err := NewPusher(PusherConfig{URL: server.URL + PushPath, ClusterID: "cluster-1"}, nil, nil)
+			if err != nil {
+				t.Fatalf("NewPusher() error = %v", err)
+			}
+			pusher.config.Token = tt.token
+			pusher.config.ClusterID = tt.clusterID
+
+			err = pusher.push(context.Background(), []*metric.UpdateSet{testUpdateSet(time.Now(), 1)})
+			if err == nil {
+				t.Fatalf("push() succeeded, want status %d", tt.status)
+			}
+			if want := fmt.Sprintf("%d", tt.status); !strings.Contains(err.Error(), want) {
+				t.Errorf("push() error = %v, want status %d", err, tt.status)
+			}
+			if got := isRejected(err); got != (tt.status == http.StatusBadRequest) {
+				t.Errorf("isRejected(%v) = %v", err, got)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:  5 * time.Second,
+		2:  10 * time.Second,
+		4:  40 * time.Second,
+		10: 5 * time.Minute,
+	}
+	for failures, want := range tests {
+		if got := retryBackoff(failures); got != want {
+			t.Errorf("retryBackoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/clusters"
+	"github.com/opencost/opencost/core/pkg/exporter"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/util/atomic"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+)
+
+const (
+	// DefaultInterval is the default interval at which buffered updates are pushed
+	DefaultInterval = time.Minute
+
+	// DefaultBufferSize is the default number of UpdateSets buffered while the central collector
+	// is unreachable, which is a day of scrapes at the default scrape interval of 30s.
+	DefaultBufferSize = 2880
+
+	// maxBatchUpdateSets is the maximum number of UpdateSets sent in a single push request
+	maxBatchUpdateSets = 20
+
+	// initialRetryBackoff and maxRetryBackoff bound the exponential backoff between failed pushes
+	initialRetryBackoff = 5 * time.Second
+	maxRetryBackoff     = 5 * time.Minute
+
+	// pushTimeout is the timeout of a single push request
+	pushTimeout = time.Minute
+)
+
+// PusherConfig configures the destination and buffering of a Pusher
+type PusherConfig struct {
+	// URL is the URL of the push endpoint of the central collector
+	URL string
+	// Token is the bearer token sent with each push request, if it is not empty
+	Token string
+	// ClusterID is the id of the cluster whose updates are pushed
+	ClusterID string
+	// Interval is the interval at which buffered updates are pushed
+	Interval time.Duration
+	// BufferSize is the maximum number of UpdateSets buffered, past which the oldest are dropped
+	BufferSize int
+}
+
+// Pusher is a metric.Updater which buffers the UpdateSets of a cluster's scrapes and pushes them to a
+// central collector. Pushes which fail are retried with exponential backoff, and while the central
+// collector is unreachable UpdateSets are buffered up to the buffer size, dropping the oldest.
+type Pusher struct {
+	lock        sync.Mutex
+	config      PusherConfig
+	clusterInfo clusters.ClusterInfoProvider
+	httpClient  *http.Client
+	encoder     exporter.Encoder[Request]
+	buffer      []*metric.UpdateSet
+	dropped     int
+	runState    atomic.AtomicRunState
+}
+
+func NewPusher(config PusherConfig, clusterInfo clusters.ClusterInfoProvider, httpClient *http.Client) (*Pusher, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("a push URL is required")
+	}
+	if err := ValidateClusterID(config.ClusterID); err != nil {
+		return nil, err
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = DefaultBufferSize
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Pusher{
+		config:      config,
+		clusterInfo: clusterInfo,
+		httpClient:  httpClient,
+		encoder:     exporter.NewGZipEncoder(exporter.NewJSONEncoder[Request]()),
+	}, nil
+}
+
+// Update buffers the UpdateSet to be pushed, dropping the oldest buffered UpdateSet if the buffer is full
+func (p *Pusher) Update(updateSet *metric.UpdateSet) {
+	if updateSet == nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.buffer = append(p.buffer, updateSet)
+	if over := len(p.buffer) - p.config.BufferSize; over > 0 {
+		p.buffer = slices.Delete(p.buffer, 0, over)
+		p.dropped += over
+		log.DedupedWarningf(5, "Pusher: buffer is full, dropped %d of the oldest updates in total", p.dropped)
+	}
+}
+
+// URL returns the URL of the push endpoint which updates are pushed to
+func (p *Pusher) URL() string {
+	return p.config.URL
+}
+
+// Buffered returns the number of UpdateSets waiting to be pushed
+func (p *Pusher) Buffered() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.buffer)
+}
+
+// Start pushes the buffered updates on the configured interval until Stop is called, backing off
+// exponentially while pushes fail.
+func (p *Pusher) Start() {
+	p.runState.WaitForReset()
+	if !p.runState.Start() {
+		log.Info("Pusher: already running")
+		return
+	}
+
+	go func() {
+		failures := 0
+		timer := time.NewTimer(p.config.Interval)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-p.runState.OnStop():
+				p.runState.Reset()
+				return
+			case <-timer.C:
+			}
+
+			wait := p.config.Interval
+			if err := p.Flush(context.Background()); err != nil {
+				failures++
+				wait = retryBackoff(failures)
+				log.Warnf("Pusher: failed to push updates, retrying in %s: %s", wait, err.Error())
+			} else {
+				failures = 0
+			}
+			timer.Reset(wait)
+		}
+	}()
+}
+
+// Stop stops pushing updates. Updates which are still buffered are not pushed.
+func (p *Pusher) Stop() {
+	p.runState.Stop()
+}
+
+// retryBackoff returns the wait before the next push after the given number of consecutive failures
+func retryBackoff(failures int) time.Duration {
+	backoff := initialRetryBackoff
+	for i := 1; i < failures && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxRetryBackoff)
+}
+
+// Flush pushes the buffered UpdateSets in batches, oldest first, until the buffer is empty or a push fails.
+// UpdateSets are only removed from the buffer once they have been received, unless the central collector
+// rejects them as invalid, in which case retrying would not succeed.
+func (p *Pusher) Flush(ctx context.Context) error {
+	for {
+		p.lock.Lock()
+		batch := slices.Clone(p.buffer[:min(len(p.buffer), maxBatchUpdateSets)])
+		p.lock.Unlock()
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := p.push(ctx, batch)
+		if err != nil && !isRejected(err) {
+			return err
+		}
+		if err != nil {
+			log.Errorf("Pusher: dropping %d updates rejected by the central collector: %s", len(batch), err.Error())
+		}
+
+		// the oldest UpdateSets may have been dropped while pushing, so the remaining UpdateSets of the
+		// batch are those at the start of the buffer
+		p.lock.Lock()
+		n := 0
+		for n < len(p.buffer) && slices.Contains(batch, p.buffer[n]) {
+			n++
+		}
+		p.buffer = slices.Delete(p.buffer, 0, n)
+		p.lock.Unlock()
+	}
+}
+
+// rejectedError is returned when the central collector rejects a push as invalid
+type rejectedError struct {
+	err error
+}
+
+func (e *rejectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rejectedError) Unwrap() error {
+	return e.err
+}
+
+func isRejected(err error) bool {
+	var rejected *rejectedError
+	return errors.As(err, &rejected)
+}
+
+func (p *Pusher) push(ctx context.Context, updateSets []*metric.UpdateSet) error {
+	var clusterInfo map[string]string
+	if p.clusterInfo != nil {
+		clusterInfo = p.clusterInfo.GetClusterInfo()
+	}
+
+	body, err := p.encoder.Encode(&Request{
+		ClusterID:   p.config.ClusterID,
+		ClusterInfo: clusterInfo,
+		UpdateSets:  updateSets,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode push request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pushTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Content-Type", "application/json")
+	if p.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		err := fmt.Errorf("push request failed with %s: %s", resp.Status, bytes.TrimSpace(msg))
+		if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusRequestEntityTooLarge {
+			return &rejectedError{err: err}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package push
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/opencost/opencost/core/pkg/util/json"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+	"github.com/opencost/opencost/modules/collector-source/pkg/util"
+)
+
+// ErrClusterLimit is returned by a ClusterUpdater when a cluster which it does not hold yet pushes updates
+// after the maximum number of clusters has been reached.
+var ErrClusterLimit = errors.New("maximum number of clusters reached")
+
+// ClusterUpdater applies the UpdateSets pushed by the collector agent of a cluster
+type ClusterUpdater interface {
+	UpdateCluster(clusterID string, clusterInfo map[string]string, updateSets []*metric.UpdateSet) error
+}
+
+// Receiver accepts the push requests of collector agents, passing the UpdateSets of each cluster
+// to a ClusterUpdater.
+type Receiver struct {
+	updater ClusterUpdater
+	token   string
+}
+
+// NewReceiver creates a Receiver which passes pushed updates to the updater. Requests must carry the
+// token as a bearer token.
+func NewReceiver(updater ClusterUpdater, token string) *Receiver {
+	return &Receiver{
+		updater: updater,
+		token:   token,
+	}
+}
+
+// Handle decodes the gzipped JSON Request in the request body and applies its UpdateSets to the updater.
+func (rc *Receiver) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	if rc.token == "" || !util.IsBearerTokenAuthorized(r, rc.token) {
+		http.Error(w, "invalid push token", http.StatusUnauthorized)
+		return
+	}
+
+	var body io.Reader = io.LimitReader(r.Body, maxDecodedBytes+1)
+	switch encoding := r.Header.Get("Content-Encoding"); encoding {
+	case "gzip":
+		reader, err := gzip.NewReader(body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to decompress request: %s", err), http.StatusBadRequest)
+			return
+		}
+		defer reader.Close()
+		body = reader
+	case "":
+	default:
+		http.Error(w, fmt.Sprintf("unsupported Content-Encoding '%s'", encoding), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxDecodedBytes+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if len(data) > maxDecodedBytes {
+		http.Error(w, "request is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	req := &Request{}
+	if err := json.Unmarshal(data, req); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if err := ValidateClusterID(req.ClusterID); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = rc.updater.UpdateCluster(req.ClusterID, req.ClusterInfo, req.UpdateSets)
+	if errors.Is(err, ErrClusterLimit) {
+		http.Error(w, fmt.Sprintf("not accepting updates of cluster '%s': %s", req.ClusterID, err), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to apply updates of cluster '%s': %s", req.ClusterID, err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
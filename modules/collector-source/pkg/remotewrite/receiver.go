@@ -0,0 +1,148 @@
+package remotewrite
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/klauspost/compress/snappy"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+	"github.com/opencost/opencost/modules/collector-source/pkg/util"
+)
+
+// WritePath is the path at which the remote-write receiver is served, matching the
+// path of the Prometheus receiver.
+const WritePath = "/api/v1/write"
+
+// maxDecodedBytes is the maximum size of a decompressed request, which is well above
+// the request size of Prometheus's default queue configuration.
+const maxDecodedBytes = 32 * 1024 * 1024
+
+// staleNaN is the value Prometheus uses to mark a series as stale.
+const staleNaN uint64 = 0x7ff0000000000002
+
+// Receiver accepts Prometheus remote-write requests, letting Prometheus agents or
+// OpenTelemetry collectors push the metrics which the collector would otherwise scrape.
+type Receiver struct {
+	updater metric.Updater
+	token   string
+}
+
+// NewReceiver creates a Receiver which applies written samples to the updater. Requests must
+// carry the token as a bearer token.
+func NewReceiver(updater metric.Updater, token string) *Receiver {
+	return &Receiver{
+		updater: updater,
+		token:   token,
+	}
+}
+
+// Handle decodes the snappy compressed protobuf WriteRequest in the request body and
+// applies its samples to the updater.
+func (rc *Receiver) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	if rc.token == "" || !util.IsBearerTokenAuthorized(r, rc.token) {
+		http.Error(w, "invalid remote-write token", http.StatusUnauthorized)
+		return
+	}
+
+	if encoding := r.Header.Get("Content-Encoding"); encoding != "" && encoding != "snappy" {
+		http.Error(w, fmt.Sprintf("unsupported Content-Encoding '%s'", encoding), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	compressed, err := io.ReadAll(io.LimitReader(r.Body, maxDecodedBytes+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if len(compressed) > maxDecodedBytes {
+		http.Error(w, "request is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	n, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decompress request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if n > maxDecodedBytes {
+		http.Error(w, "request is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decompress request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	req, err := Unmarshal(data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, updateSet := range toUpdateSets(req) {
+		rc.updater.Update(updateSet)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// toUpdateSets groups the samples of the request by timestamp, returning an UpdateSet
+// for each in chronological order. Series without a name and stale markers are skipped.
+func toUpdateSets(req *WriteRequest) []*metric.UpdateSet {
+	byTimestamp := make(map[int64]*metric.UpdateSet)
+
+	for _, ts := range req.Timeseries {
+		var name string
+		labels := make(map[string]string, len(ts.Labels))
+		for _, l := range ts.Labels {
+			if l.Name == "__name__" {
+				name = l.Value
+				continue
+			}
+			labels[l.Name] = l.Value
+		}
+		if name == "" {
+			log.Debugf("RemoteWrite: skipping series without a name: %v", labels)
+			continue
+		}
+
+		for _, s := range ts.Samples {
+			if math.Float64bits(s.Value) == staleNaN {
+				continue
+			}
+
+			updateSet, ok := byTimestamp[s.Timestamp]
+			if !ok {
+				updateSet = &metric.UpdateSet{
+					Timestamp: time.UnixMilli(s.Timestamp).UTC(),
+				}
+				byTimestamp[s.Timestamp] = updateSet
+			}
+			updateSet.Updates = append(updateSet.Updates, metric.Update{
+				Name:   name,
+				Labels: labels,
+				Value:  s.Value,
+			})
+		}
+	}
+
+	timestamps := make([]int64, 0, len(byTimestamp))
+	for timestamp := range byTimestamp {
+		timestamps = append(timestamps, timestamp)
+	}
+	slices.Sort(timestamps)
+
+	updateSets := make([]*metric.UpdateSet, 0, len(timestamps))
+	for _, timestamp := range timestamps {
+		updateSets = append(updateSets, byTimestamp[timestamp])
+	}
+	return updateSets
+}
@@ -0,0 +1,209 @@
+package remotewrite
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/klauspost/compress/snappy"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+type recordingUpdater struct {
+	lock       sync.Mutex
+	updateSets []*metric.UpdateSet
+}
+
+func (u *recordingUpdater) Update(updateSet *metric.UpdateSet) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.updateSets = append(u.updateSets, updateSet)
+}
+
+const testToken = "test-token"
+
+func newTestServer(t *testing.T) (*httptest.Server, *recordingUpdater) {
+	t.Helper()
+
+	updater := &recordingUpdater{}
+	router := httprouter.New()
+	router.POST(WritePath, NewReceiver(updater, testToken).Handle)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, updater
+}
+
+func TestReceiver(t *testing.T) {
+	server, updater := newTestServer(t)
+	client := NewClient(server.URL+WritePath, testToken, nil)
+
+	t1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(30 * time.Second)
+
+	err := client.Write(context.Background(), &WriteRequest{
+		Timeseries: []TimeSeries{
+			{
+				Labels: []Label{
+					{Name: "__name__", Value: "DCGM_FI_DEV_GPU_UTIL"},
+					{Name: "gpu", Value: "0"},
+					{Name: "pod", Value: "training-0"},
+				},
+				Samples: []Sample{
+					{Value: 75, Timestamp: t2.UnixMilli()},
+					{Value: 50, Timestamp: t1.UnixMilli()},
+				},
+			},
+			{
+				Labels: []Label{
+					{Name: "__name__", Value: "node_total_hourly_cost"},
+					{Name: "node", Value: "node-1"},
+				},
+				Samples: []Sample{
+					{Value: 0.5, Timestamp: t1.UnixMilli()},
+					{Value: math.Float64frombits(staleNaN), Timestamp: t2.UnixMilli()},
+				},
+			},
+			{
+				// series without a name are skipped
+				Labels:  []Label{{Name: "node", Value: "node-1"}},
+				Samples: []Sample{{Value: 1, Timestamp: t1.UnixMilli()}},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := []*metric.UpdateSet{
+		{
+			Timestamp: t1,
+			Updates: []metric.Update{
+				{Name: "DCGM_FI_DEV_GPU_UTIL", Labels: map[string]string{"gpu": "0", "pod": "training-0"}, Value: 50},
+				{Name: "node_total_hourly_cost", Labels: map[string]string{"node": "node-1"}, Value: 0.5},
+			},
+		},
+		{
+			Timestamp: t2,
+			Updates: []metric.Update{
+				{Name: "DCGM_FI_DEV_GPU_UTIL", Labels: map[string]string{"gpu": "0", "pod": "training-0"}, Value: 75},
+			},
+		},
+	}
+	if !reflect.DeepEqual(updater.updateSets, want) {
+		t.Errorf("updates = %v, want %v", updater.updateSets, want)
+	}
+}
+
+func TestReceiver_InvalidRequests(t *testing.T) {
+	server, updater := newTestServer(t)
+
+	tests := map[string]struct {
+		body     []byte
+		encoding string
+		token    string
+		code     int
+	}{
+		"missing token": {
+			body:     snappy.Encode(nil, (&WriteRequest{}).Marshal()),
+			encoding: "snappy",
+			code:     http.StatusUnauthorized,
+		},
+		"invalid token": {
+			body:     snappy.Encode(nil, (&WriteRequest{}).Marshal()),
+			encoding: "snappy",
+			token:    "invalid",
+			code:     http.StatusUnauthorized,
+		},
+		"not snappy": {
+			body:     []byte("not snappy"),
+			encoding: "snappy",
+			token:    testToken,
+			code:     http.StatusBadRequest,
+		},
+		"not protobuf": {
+			body:     snappy.Encode(nil, []byte{0xff, 0xff, 0xff}),
+			encoding: "snappy",
+			token:    testToken,
+			code:     http.StatusBadRequest,
+		},
+		"unsupported encoding": {
+			body:     []byte{},
+			encoding: "gzip",
+			token:    testToken,
+			code:     http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+WritePath, bytes.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Encoding", tt.encoding)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.code {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.code)
+			}
+		})
+	}
+
+	if len(updater.updateSets) != 0 {
+		t.Errorf("invalid requests applied %d updates", len(updater.updateSets))
+	}
+
+	// errors are surfaced by the client
+	err := NewClient(server.URL+"/unknown", testToken, nil).Write(context.Background(), &WriteRequest{})
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Write() error = %v, want 404", err)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	wr := &WriteRequest{
+		Timeseries: []TimeSeries{
+			{
+				Labels:  []Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "node"}},
+				Samples: []Sample{{Value: 1, Timestamp: 1700000000000}, {Value: -2.5, Timestamp: 1700000030000}},
+			},
+		},
+	}
+
+	// append fields which are not collected, such as metadata, which must be skipped
+	b := wr.Marshal()
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte("metadata"))
+	b = protowire.AppendTag(b, 15, protowire.VarintType)
+	b = protowire.AppendVarint(b, 42)
+
+	got, err := Unmarshal(b)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, wr) {
+		t.Errorf("Unmarshal() = %v, want %v", got, wr)
+	}
+
+	if _, err := Unmarshal(b[:len(b)-5]); err == nil {
+		t.Errorf("Unmarshal() of truncated request succeeded")
+	}
+}
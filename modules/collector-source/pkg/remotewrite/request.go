@@ -0,0 +1,208 @@
+package remotewrite
+
+import (
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The field numbers of the Prometheus remote-write 1.0 protobuf messages, see
+// https://github.com/prometheus/prometheus/blob/main/prompb/remote.proto. Metadata,
+// exemplars and native histograms are not collected, so their fields are skipped.
+const (
+	writeRequestTimeseriesField = 1
+
+	timeSeriesLabelsField  = 1
+	timeSeriesSamplesField = 2
+
+	labelNameField  = 1
+	labelValueField = 2
+
+	sampleValueField     = 1
+	sampleTimestampField = 2
+)
+
+// WriteRequest is a Prometheus remote-write request.
+type WriteRequest struct {
+	Timeseries []TimeSeries
+}
+
+// TimeSeries is a series, identified by its labels including "__name__", and its samples.
+type TimeSeries struct {
+	Labels  []Label
+	Samples []Sample
+}
+
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a value and its timestamp, in milliseconds since the epoch.
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// Marshal encodes the request as protobuf.
+func (wr *WriteRequest) Marshal() []byte {
+	var b []byte
+	for _, ts := range wr.Timeseries {
+		b = protowire.AppendTag(b, writeRequestTimeseriesField, protowire.BytesType)
+		b = protowire.AppendBytes(b, ts.marshal())
+	}
+	return b
+}
+
+func (ts *TimeSeries) marshal() []byte {
+	var b []byte
+	for _, l := range ts.Labels {
+		var lb []byte
+		lb = protowire.AppendTag(lb, labelNameField, protowire.BytesType)
+		lb = protowire.AppendString(lb, l.Name)
+		lb = protowire.AppendTag(lb, labelValueField, protowire.BytesType)
+		lb = protowire.AppendString(lb, l.Value)
+
+		b = protowire.AppendTag(b, timeSeriesLabelsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, lb)
+	}
+	for _, s := range ts.Samples {
+		var sb []byte
+		sb = protowire.AppendTag(sb, sampleValueField, protowire.Fixed64Type)
+		sb = protowire.AppendFixed64(sb, math.Float64bits(s.Value))
+		sb = protowire.AppendTag(sb, sampleTimestampField, protowire.VarintType)
+		sb = protowire.AppendVarint(sb, uint64(s.Timestamp))
+
+		b = protowire.AppendTag(b, timeSeriesSamplesField, protowire.BytesType)
+		b = protowire.AppendBytes(b, sb)
+	}
+	return b
+}
+
+// Unmarshal decodes a protobuf encoded request.
+func Unmarshal(b []byte) (*WriteRequest, error) {
+	wr := &WriteRequest{}
+	err := unmarshalFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if num != writeRequestTimeseriesField || typ != protowire.BytesType {
+			return skipField(num, typ, b)
+		}
+
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		ts, err := unmarshalTimeSeries(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid timeseries: %w", err)
+		}
+		wr.Timeseries = append(wr.Timeseries, ts)
+		return n, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wr, nil
+}
+
+func unmarshalTimeSeries(b []byte) (TimeSeries, error) {
+	var ts TimeSeries
+	err := unmarshalFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType || (num != timeSeriesLabelsField && num != timeSeriesSamplesField) {
+			return skipField(num, typ, b)
+		}
+
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+
+		if num == timeSeriesLabelsField {
+			l, err := unmarshalLabel(v)
+			if err != nil {
+				return 0, fmt.Errorf("invalid label: %w", err)
+			}
+			ts.Labels = append(ts.Labels, l)
+		} else {
+			s, err := unmarshalSample(v)
+			if err != nil {
+				return 0, fmt.Errorf("invalid sample: %w", err)
+			}
+			ts.Samples = append(ts.Samples, s)
+		}
+		return n, nil
+	})
+	return ts, err
+}
+
+func unmarshalLabel(b []byte) (Label, error) {
+	var l Label
+	err := unmarshalFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType || (num != labelNameField && num != labelValueField) {
+			return skipField(num, typ, b)
+		}
+
+		v, n := protowire.ConsumeString(b)
+		if n < 0 {
+			return 0, protowire.ParseError(n)
+		}
+		if num == labelNameField {
+			l.Name = v
+		} else {
+			l.Value = v
+		}
+		return n, nil
+	})
+	return l, err
+}
+
+func unmarshalSample(b []byte) (Sample, error) {
+	var s Sample
+	err := unmarshalFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == sampleValueField && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			s.Value = math.Float64frombits(v)
+			return n, nil
+		case num == sampleTimestampField && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return 0, protowire.ParseError(n)
+			}
+			s.Timestamp = int64(v)
+			return n, nil
+		}
+		return skipField(num, typ, b)
+	})
+	return s, err
+}
+
+// unmarshalFields calls the given func with the number, type and remaining bytes of each
+// field of the message, which returns the length of the field's value.
+func unmarshalFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func skipField(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	n := protowire.ConsumeFieldValue(num, typ, b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return n, nil
+}
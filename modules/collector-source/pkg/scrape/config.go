@@ -0,0 +1,252 @@
+package scrape
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/modules/collector-source/pkg/util"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultScrapeScheme  = "http"
+	defaultMetricsPath   = "/metrics"
+	defaultScrapeTimeout = 10 * time.Second
+)
+
+// ScrapeConfigFile is the declarative configuration of the scrape jobs run in addition
+// to the built-in scrapers. The format follows the Prometheus scrape configuration.
+type ScrapeConfigFile struct {
+	ScrapeConfigs []*ScrapeConfig `json:"scrape_configs"`
+}
+
+// ScrapeConfig configures a scrape job, which discovers targets, scrapes each of them,
+// and forwards the allowed metrics to the collector.
+type ScrapeConfig struct {
+	// JobName uniquely identifies the job, and names its scrape events.
+	JobName string `json:"job_name"`
+
+	// ScrapeInterval is how often the job's targets are scraped, e.g. "1m". Defaults to
+	// every scrape of the collector. Intervals shorter than the collector's have no effect.
+	ScrapeInterval string `json:"scrape_interval,omitempty"`
+
+	// ScrapeTimeout is the timeout of each request, e.g. "10s".
+	ScrapeTimeout string `json:"scrape_timeout,omitempty"`
+
+	// Scheme is the default scheme of target URLs, either http or https.
+	Scheme string `json:"scheme,omitempty"`
+
+	// MetricsPath is the default path of target URLs.
+	MetricsPath string `json:"metrics_path,omitempty"`
+
+	BearerToken     string     `json:"bearer_token,omitempty"`
+	BearerTokenFile string     `json:"bearer_token_file,omitempty"`
+	TLSConfig       *TLSConfig `json:"tls_config,omitempty"`
+
+	StaticConfigs       []*StaticConfig       `json:"static_configs,omitempty"`
+	KubernetesSDConfigs []*KubernetesSDConfig `json:"kubernetes_sd_configs,omitempty"`
+
+	// RelabelConfigs are applied to the labels of each discovered target before it is
+	// scraped. The labels of the result which do not begin with "__" are added to each
+	// metric scraped from the target.
+	RelabelConfigs []*RelabelConfig `json:"relabel_configs,omitempty"`
+
+	// MetricRelabelConfigs are applied to the labels of each scraped metric, with its
+	// name as the "__name__" label.
+	MetricRelabelConfigs []*RelabelConfig `json:"metric_relabel_configs,omitempty"`
+
+	// Metrics is the allowlist of metric names to collect. All metrics are collected if
+	// it is empty.
+	Metrics []string `json:"metrics,omitempty"`
+
+	interval util.Interval
+	timeout  time.Duration
+}
+
+// StaticConfig is a fixed list of target addresses, e.g. "exporter.monitoring:9100",
+// with labels applied to each.
+type StaticConfig struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// TLSConfig configures the TLS connections to a job's targets.
+type TLSConfig struct {
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// LoadScrapeConfigs reads and validates the scrape jobs in the YAML or JSON file at
+// the given path.
+func LoadScrapeConfigs(path string) ([]*ScrapeConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scrape config file '%s': %w", path, err)
+	}
+
+	return ParseScrapeConfigs(data)
+}
+
+// ParseScrapeConfigs parses and validates the scrape jobs of a YAML or JSON scrape
+// config file.
+func ParseScrapeConfigs(data []byte) ([]*ScrapeConfig, error) {
+	var file ScrapeConfigFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse scrape configs: %w", err)
+	}
+
+	jobNames := make(map[string]struct{})
+	for _, config := range file.ScrapeConfigs {
+		if config == nil {
+			return nil, fmt.Errorf("scrape config must not be empty")
+		}
+		if err := config.validate(); err != nil {
+			return nil, fmt.Errorf("invalid scrape config '%s': %w", config.JobName, err)
+		}
+
+		if _, ok := jobNames[config.JobName]; ok {
+			return nil, fmt.Errorf("duplicate scrape config job_name '%s'", config.JobName)
+		}
+		jobNames[config.JobName] = struct{}{}
+	}
+
+	return file.ScrapeConfigs, nil
+}
+
+// validate applies the defaults of the config and checks that it is valid.
+func (sc *ScrapeConfig) validate() error {
+	if sc.JobName == "" {
+		return fmt.Errorf("job_name is required")
+	}
+
+	if sc.ScrapeInterval != "" {
+		interval, err := util.NewInterval(sc.ScrapeInterval)
+		if err != nil {
+			return fmt.Errorf("invalid scrape_interval: %w", err)
+		}
+		sc.interval = interval
+	}
+
+	sc.timeout = defaultScrapeTimeout
+	if sc.ScrapeTimeout != "" {
+		timeout, err := time.ParseDuration(sc.ScrapeTimeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid scrape_timeout '%s'", sc.ScrapeTimeout)
+		}
+		sc.timeout = timeout
+	}
+
+	if sc.Scheme == "" {
+		sc.Scheme = defaultScrapeScheme
+	}
+	if sc.Scheme != "http" && sc.Scheme != "https" {
+		return fmt.Errorf("invalid scheme '%s'", sc.Scheme)
+	}
+
+	if sc.MetricsPath == "" {
+		sc.MetricsPath = defaultMetricsPath
+	}
+	if !strings.HasPrefix(sc.MetricsPath, "/") {
+		return fmt.Errorf("metrics_path '%s' must begin with '/'", sc.MetricsPath)
+	}
+
+	if sc.BearerToken != "" && sc.BearerTokenFile != "" {
+		return fmt.Errorf("at most one of bearer_token and bearer_token_file may be set")
+	}
+
+	if len(sc.StaticConfigs) == 0 && len(sc.KubernetesSDConfigs) == 0 {
+		return fmt.Errorf("at least one of static_configs and kubernetes_sd_configs is required")
+	}
+	for _, sd := range sc.KubernetesSDConfigs {
+		if sd == nil {
+			return fmt.Errorf("kubernetes_sd_config must not be empty")
+		}
+		if err := sd.validate(); err != nil {
+			return fmt.Errorf("invalid kubernetes_sd_config: %w", err)
+		}
+	}
+
+	for _, rc := range append(append([]*RelabelConfig{}, sc.RelabelConfigs...), sc.MetricRelabelConfigs...) {
+		if rc == nil {
+			return fmt.Errorf("relabel config must not be empty")
+		}
+		if err := rc.compile(); err != nil {
+			return fmt.Errorf("invalid relabel config: %w", err)
+		}
+	}
+
+	if sc.TLSConfig != nil {
+		if _, err := sc.TLSConfig.build(); err != nil {
+			return fmt.Errorf("invalid tls_config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// httpClient creates the client used to scrape the job's targets.
+func (sc *ScrapeConfig) httpClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if sc.TLSConfig != nil {
+		tlsConfig, err := sc.TLSConfig.build()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   sc.timeout,
+	}, nil
+}
+
+func (tc *TLSConfig) build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         tc.ServerName,
+		InsecureSkipVerify: tc.InsecureSkipVerify,
+	}
+
+	if tc.CAFile != "" {
+		ca, err := os.ReadFile(tc.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in ca_file '%s'", tc.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (tc.CertFile == "") != (tc.KeyFile == "") {
+		return nil, fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if tc.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(tc.CertFile, tc.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// parseSelector parses a Kubernetes label selector, where an empty selector matches
+// everything.
+func parseSelector(selector string) (labels.Selector, error) {
+	if selector == "" {
+		return labels.Everything(), nil
+	}
+	return labels.Parse(selector)
+}
@@ -0,0 +1,147 @@
+package scrape
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScrapeConfigs(t *testing.T) {
+	configs, err := ParseScrapeConfigs([]byte(`
+scrape_configs:
+  - job_name: gpu-exporter
+    scrape_interval: 1m
+    scheme: https
+    bearer_token_file: /var/run/secrets/kubernetes.io/serviceaccount/token
+    tls_config:
+      insecure_skip_verify: true
+    kubernetes_sd_configs:
+      - role: pod
+        namespaces: [gpu]
+        selector: app in (gpu-exporter, custom-gpu-exporter)
+        port: 9400
+    relabel_configs:
+      - source_labels: [__meta_kubernetes_pod_node_name]
+        target_label: node
+    metrics: [DCGM_FI_DEV_GPU_UTIL]
+  - job_name: power-meter
+    static_configs:
+      - targets: ["power-meter.monitoring:8080"]
+        labels:
+          rack: r1
+`))
+	if err != nil {
+		t.Fatalf("ParseScrapeConfigs() error = %v", err)
+	}
+	if len(configs) != 2 {
+		t.Fatalf("ParseScrapeConfigs() returned %d configs, want 2", len(configs))
+	}
+
+	gpu := configs[0]
+	if gpu.JobName != "gpu-exporter" || gpu.Scheme != "https" || gpu.MetricsPath != "/metrics" {
+		t.Errorf("unexpected config: %+v", gpu)
+	}
+	if gpu.interval == nil || gpu.timeout != defaultScrapeTimeout {
+		t.Errorf("unexpected interval %v or timeout %s", gpu.interval, gpu.timeout)
+	}
+	if sd := gpu.KubernetesSDConfigs[0]; sd.Role != KubernetesRolePod || sd.Port != 9400 || sd.selector == nil {
+		t.Errorf("unexpected kubernetes_sd_config: %+v", sd)
+	}
+	if rc := gpu.RelabelConfigs[0]; rc.Action != RelabelReplace || rc.regex == nil {
+		t.Errorf("unexpected relabel config: %+v", rc)
+	}
+
+	power := configs[1]
+	if power.interval != nil || power.Scheme != "http" {
+		t.Errorf("unexpected config: %+v", power)
+	}
+	if static := power.StaticConfigs[0]; static.Targets[0] != "power-meter.monitoring:8080" || static.Labels["rack"] != "r1" {
+		t.Errorf("unexpected static_config: %+v", static)
+	}
+}
+
+func TestParseScrapeConfigs_Invalid(t *testing.T) {
+	const static = `
+    static_configs:
+      - targets: [localhost:9100]`
+
+	tests := map[string]struct {
+		config string
+		errMsg string
+	}{
+		"unknown field": {
+			config: "scrape_configs:\n  - job_name: a\n    unknown: true" + static,
+			errMsg: "unknown field",
+		},
+		"missing job name": {
+			config: "scrape_configs:\n  - scheme: http" + static,
+			errMsg: "job_name is required",
+		},
+		"duplicate job name": {
+			config: "scrape_configs:\n  - job_name: a" + static + "\n  - job_name: a" + static,
+			errMsg: "duplicate",
+		},
+		"no targets": {
+			config: "scrape_configs:\n  - job_name: a",
+			errMsg: "at least one of",
+		},
+		"invalid interval": {
+			config: "scrape_configs:\n  - job_name: a\n    scrape_interval: 1.5m" + static,
+			errMsg: "scrape_interval",
+		},
+		"invalid scheme": {
+			config: "scrape_configs:\n  - job_name: a\n    scheme: ftp" + static,
+			errMsg: "scheme",
+		},
+		"invalid role": {
+			config: "scrape_configs:\n  - job_name: a\n    kubernetes_sd_configs:\n      - role: node",
+			errMsg: "invalid role",
+		},
+		"invalid selector": {
+			config: "scrape_configs:\n  - job_name: a\n    kubernetes_sd_configs:\n      - role: pod\n        selector: 'app in ('",
+			errMsg: "invalid selector",
+		},
+		"invalid relabel config": {
+			config: "scrape_configs:\n  - job_name: a\n    relabel_configs:\n      - action: keep" + static,
+			errMsg: "source_labels",
+		},
+		"both bearer tokens": {
+			config: "scrape_configs:\n  - job_name: a\n    bearer_token: a\n    bearer_token_file: b" + static,
+			errMsg: "bearer_token",
+		},
+		"missing ca file": {
+			config: "scrape_configs:\n  - job_name: a\n    tls_config:\n      ca_file: /does/not/exist" + static,
+			errMsg: "ca_file",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseScrapeConfigs([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("ParseScrapeConfigs() error = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestScrapeConfig_timeout(t *testing.T) {
+	configs, err := ParseScrapeConfigs([]byte(`
+scrape_configs:
+  - job_name: a
+    scrape_timeout: 30s
+    static_configs:
+      - targets: [localhost:9100]
+`))
+	if err != nil {
+		t.Fatalf("ParseScrapeConfigs() error = %v", err)
+	}
+
+	client, err := configs[0].httpClient()
+	if err != nil {
+		t.Fatalf("httpClient() error = %v", err)
+	}
+	if client.Timeout != 30*time.Second {
+		t.Errorf("client timeout = %s, want 30s", client.Timeout)
+	}
+}
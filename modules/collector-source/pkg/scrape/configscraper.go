@@ -0,0 +1,145 @@
+package scrape
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+	"github.com/opencost/opencost/modules/collector-source/pkg/scrape/target"
+	"github.com/opencost/opencost/modules/collector-source/pkg/util"
+)
+
+// newConfiguredScraper creates the Scraper for a validated scrape job.
+func newConfiguredScraper(config *ScrapeConfig, clusterCache clustercache.ClusterCache) (Scraper, error) {
+	client, err := config.httpClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http client for scrape config '%s': %w", config.JobName, err)
+	}
+
+	provider := newConfigTargetProvider(config, client, clusterCache)
+	scraper := newTargetScrapper(config.JobName, provider, config.Metrics, len(config.Metrics) > 0)
+	scraper.metricRelabelConfigs = config.MetricRelabelConfigs
+
+	if config.interval == nil {
+		return scraper, nil
+	}
+	return &intervalScraper{
+		scraper:  scraper,
+		interval: config.interval,
+	}, nil
+}
+
+// ConfigTargetProvider discovers the targets of a scrape job, relabeling each.
+type ConfigTargetProvider struct {
+	config       *ScrapeConfig
+	client       *http.Client
+	clusterCache clustercache.ClusterCache
+}
+
+func newConfigTargetProvider(config *ScrapeConfig, client *http.Client, clusterCache clustercache.ClusterCache) *ConfigTargetProvider {
+	return &ConfigTargetProvider{
+		config:       config,
+		client:       client,
+		clusterCache: clusterCache,
+	}
+}
+
+func (p *ConfigTargetProvider) GetTargets() []target.ScrapeTarget {
+	auth := target.HTTPAuth{
+		BearerToken:     p.config.BearerToken,
+		BearerTokenFile: p.config.BearerTokenFile,
+	}
+
+	var targets []target.ScrapeTarget
+	for _, ls := range p.discover() {
+		u, targetLabels, ok := p.resolve(ls)
+		if !ok {
+			continue
+		}
+
+		log.Debugf("%s: found target: %s", p.config.JobName, u)
+		targets = append(targets, target.NewHTTPTarget(u, p.client, auth, targetLabels))
+	}
+
+	return targets
+}
+
+// discover returns the labels of each target of the static and Kubernetes configs.
+func (p *ConfigTargetProvider) discover() []map[string]string {
+	var discovered []map[string]string
+	for _, static := range p.config.StaticConfigs {
+		for _, address := range static.Targets {
+			ls := make(map[string]string, len(static.Labels)+1)
+			for k, v := range static.Labels {
+				ls[k] = v
+			}
+			ls[AddressLabel] = address
+			discovered = append(discovered, ls)
+		}
+	}
+
+	if len(p.config.KubernetesSDConfigs) > 0 {
+		if p.clusterCache == nil {
+			log.Warnf("%s: kubernetes_sd_configs require the cluster cache", p.config.JobName)
+			return discovered
+		}
+
+		pods := p.clusterCache.GetAllPods()
+		services := p.clusterCache.GetAllServices()
+		for _, sd := range p.config.KubernetesSDConfigs {
+			discovered = append(discovered, sd.discover(pods, services)...)
+		}
+	}
+
+	return discovered
+}
+
+// resolve relabels the discovered labels of a target, returning its URL and the labels
+// to add to its metrics, or false if it was dropped.
+func (p *ConfigTargetProvider) resolve(discovered map[string]string) (string, map[string]string, bool) {
+	discovered[SchemeLabel] = p.config.Scheme
+	discovered[MetricsPathLabel] = p.config.MetricsPath
+
+	ls, ok := relabel(discovered, p.config.RelabelConfigs)
+	if !ok {
+		return "", nil, false
+	}
+
+	address := ls[AddressLabel]
+	if address == "" {
+		log.Warnf("%s: dropping target without an %s label", p.config.JobName, AddressLabel)
+		return "", nil, false
+	}
+
+	u := fmt.Sprintf("%s://%s%s", ls[SchemeLabel], address, ls[MetricsPathLabel])
+
+	targetLabels := make(map[string]string)
+	for k, v := range ls {
+		if !strings.HasPrefix(k, "__") {
+			targetLabels[k] = v
+		}
+	}
+	return u, targetLabels, true
+}
+
+// intervalScraper runs the scraper once per interval, skipping the scrapes of the
+// collector which fall into an interval that has already been scraped.
+type intervalScraper struct {
+	scraper    Scraper
+	interval   util.Interval
+	lastScrape time.Time
+}
+
+func (s *intervalScraper) Scrape() []metric.Update {
+	current := s.interval.Truncate(time.Now().UTC())
+	if current.Equal(s.lastScrape) {
+		return nil
+	}
+	s.lastScrape = current
+
+	return s.scraper.Scrape()
+}
@@ -0,0 +1,129 @@
+package scrape
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/opencost/opencost/modules/collector-source/pkg/metric"
+)
+
+const exporterScrape = `
+# TYPE gpu_power_watts gauge
+gpu_power_watts{gpu="0"} 250
+gpu_power_watts{gpu="1"} 100
+# TYPE gpu_temperature_celsius gauge
+gpu_temperature_celsius{gpu="0"} 60
+`
+
+func TestConfiguredScraper_Scrape(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/custom/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(exporterScrape))
+	}))
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "https://")
+	configs, err := ParseScrapeConfigs([]byte(`
+scrape_configs:
+  - job_name: gpu-power
+    scheme: https
+    metrics_path: /custom/metrics
+    bearer_token: secret
+    tls_config:
+      insecure_skip_verify: true
+    static_configs:
+      - targets: ["` + address + `"]
+        labels:
+          __meta_rack: r1
+      - targets: ["dropped:9100"]
+    relabel_configs:
+      - source_labels: [__address__]
+        regex: dropped:.*
+        action: drop
+      - source_labels: [__meta_rack]
+        target_label: rack
+    metric_relabel_configs:
+      - source_labels: [__name__, gpu]
+        regex: gpu_power_watts;1
+        action: drop
+      - source_labels: [__name__]
+        regex: gpu_(.*)
+        target_label: __name__
+        replacement: node_gpu_$1
+    metrics: [gpu_power_watts]
+`))
+	if err != nil {
+		t.Fatalf("ParseScrapeConfigs() error = %v", err)
+	}
+
+	scraper, err := newConfiguredScraper(configs[0], nil)
+	if err != nil {
+		t.Fatalf("newConfiguredScraper() error = %v", err)
+	}
+
+	got := scraper.Scrape()
+	want := []metric.Update{
+		{
+			Name:   "node_gpu_power_watts",
+			Labels: map[string]string{"gpu": "0", "rack": "r1"},
+			Value:  250,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scrape() = %v, want %v", got, want)
+	}
+}
+
+func TestConfiguredScraper_Interval(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(exporterScrape))
+	}))
+	defer server.Close()
+
+	configs, err := ParseScrapeConfigs([]byte(`
+scrape_configs:
+  - job_name: gpu-power
+    scrape_interval: 1h
+    static_configs:
+      - targets: ["` + strings.TrimPrefix(server.URL, "http://") + `"]
+`))
+	if err != nil {
+		t.Fatalf("ParseScrapeConfigs() error = %v", err)
+	}
+
+	scraper, err := newConfiguredScraper(configs[0], nil)
+	if err != nil {
+		t.Fatalf("newConfiguredScraper() error = %v", err)
+	}
+
+	// without an allowlist, every metric is collected
+	var names []string
+	for _, update := range scraper.Scrape() {
+		names = append(names, update.Name)
+	}
+	sort.Strings(names)
+	if want := []string{"gpu_power_watts", "gpu_power_watts", "gpu_temperature_celsius"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Scrape() names = %v, want %v", names, want)
+	}
+
+	// a second scrape within the same interval is skipped
+	if updates := scraper.Scrape(); updates != nil {
+		t.Errorf("Scrape() within interval = %v, want nil", updates)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
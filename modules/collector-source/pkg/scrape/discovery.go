@@ -0,0 +1,222 @@
+package scrape
+
+import (
+	"cmp"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Labels of a discovered target, which relabeling may read and rewrite. Labels
+// beginning with "__" are removed once relabeling is done.
+const (
+	AddressLabel     = "__address__"
+	SchemeLabel      = "__scheme__"
+	MetricsPathLabel = "__metrics_path__"
+	MetricNameLabel  = "__name__"
+
+	metaLabelPrefix = "__meta_kubernetes_"
+)
+
+// KubernetesRole is the kind of Kubernetes object discovered as targets.
+type KubernetesRole string
+
+const (
+	// KubernetesRolePod discovers each running pod, addressed by its IP.
+	KubernetesRolePod KubernetesRole = "pod"
+
+	// KubernetesRoleService discovers each service, addressed by its DNS name.
+	KubernetesRoleService KubernetesRole = "service"
+
+	// KubernetesRoleEndpoints discovers the running pods selected by each service,
+	// addressed by their IPs. The pods are resolved from the service's selector, as the
+	// cluster cache does not hold Endpoints.
+	KubernetesRoleEndpoints KubernetesRole = "endpoints"
+)
+
+var invalidLabelCharRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// KubernetesSDConfig discovers targets from the objects in the cluster cache.
+type KubernetesSDConfig struct {
+	Role KubernetesRole `json:"role"`
+
+	// Namespaces limits discovery to the given namespaces. All namespaces are
+	// discovered if it is empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Selector is a Kubernetes label selector, e.g. "app=dcgm-exporter", which the
+	// discovered pods (for the pod role) or services (for the service and endpoints roles)
+	// must match.
+	Selector string `json:"selector,omitempty"`
+
+	// Annotations are the annotations, e.g. "prometheus.io/scrape": "true", which the
+	// discovered pods or services must have.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Port is the port of each discovered address. Addresses have no port if it is 0,
+	// in which case relabeling is expected to set one.
+	Port int `json:"port,omitempty"`
+
+	selector labels.Selector
+}
+
+func (c *KubernetesSDConfig) validate() error {
+	switch c.Role {
+	case KubernetesRolePod, KubernetesRoleService, KubernetesRoleEndpoints:
+	default:
+		return fmt.Errorf("invalid role '%s'", c.Role)
+	}
+
+	selector, err := parseSelector(c.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid selector '%s': %w", c.Selector, err)
+	}
+	c.selector = selector
+
+	if c.Port < 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid port %d", c.Port)
+	}
+
+	return nil
+}
+
+// discover returns the labels of each target discovered from the given pods and
+// services, ordered by namespace and name.
+func (c *KubernetesSDConfig) discover(pods []*clustercache.Pod, services []*clustercache.Service) []map[string]string {
+	var targets []map[string]string
+
+	switch c.Role {
+	case KubernetesRolePod:
+		for _, pod := range sortedPods(pods) {
+			if c.inNamespace(pod.Namespace) && isScrapeablePod(pod) && c.matches(pod.Labels, pod.Annotations) {
+				ls := podMetaLabels(pod)
+				ls[AddressLabel] = c.address(pod.Status.PodIP)
+				targets = append(targets, ls)
+			}
+		}
+
+	case KubernetesRoleService:
+		for _, service := range sortedServices(services) {
+			if c.inNamespace(service.Namespace) && c.matches(service.Labels, service.Annotations) {
+				ls := serviceMetaLabels(service)
+				ls[AddressLabel] = c.address(fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace))
+				targets = append(targets, ls)
+			}
+		}
+
+	case KubernetesRoleEndpoints:
+		pods = sortedPods(pods)
+		for _, service := range sortedServices(services) {
+			if !c.inNamespace(service.Namespace) || !c.matches(service.Labels, service.Annotations) {
+				continue
+			}
+			// services without a selector have manually managed endpoints, which cannot
+			// be resolved
+			if len(service.SpecSelector) == 0 {
+				continue
+			}
+
+			selector := labels.SelectorFromSet(service.SpecSelector)
+			for _, pod := range pods {
+				if pod.Namespace != service.Namespace || !isScrapeablePod(pod) || !selector.Matches(labels.Set(pod.Labels)) {
+					continue
+				}
+
+				ls := serviceMetaLabels(service)
+				for k, v := range podMetaLabels(pod) {
+					ls[k] = v
+				}
+				ls[AddressLabel] = c.address(pod.Status.PodIP)
+				targets = append(targets, ls)
+			}
+		}
+	}
+
+	return targets
+}
+
+func (c *KubernetesSDConfig) inNamespace(namespace string) bool {
+	return len(c.Namespaces) == 0 || slices.Contains(c.Namespaces, namespace)
+}
+
+func (c *KubernetesSDConfig) matches(objectLabels, annotations map[string]string) bool {
+	if !c.selector.Matches(labels.Set(objectLabels)) {
+		return false
+	}
+	for k, v := range c.Annotations {
+		if value, ok := annotations[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *KubernetesSDConfig) address(host string) string {
+	if c.Port == 0 {
+		return host
+	}
+	return net.JoinHostPort(host, strconv.Itoa(c.Port))
+}
+
+func isScrapeablePod(pod *clustercache.Pod) bool {
+	return pod.Status.Phase == v1.PodRunning && pod.Status.PodIP != "" && pod.DeletionTimestamp == nil
+}
+
+func podMetaLabels(pod *clustercache.Pod) map[string]string {
+	ls := map[string]string{
+		metaLabelPrefix + "namespace":     pod.Namespace,
+		metaLabelPrefix + "pod_name":      pod.Name,
+		metaLabelPrefix + "pod_ip":        pod.Status.PodIP,
+		metaLabelPrefix + "pod_node_name": pod.Spec.NodeName,
+		metaLabelPrefix + "pod_uid":       string(pod.UID),
+	}
+	addMetaLabels(ls, metaLabelPrefix+"pod_label_", pod.Labels)
+	addMetaLabels(ls, metaLabelPrefix+"pod_annotation_", pod.Annotations)
+	return ls
+}
+
+func serviceMetaLabels(service *clustercache.Service) map[string]string {
+	ls := map[string]string{
+		metaLabelPrefix + "namespace":    service.Namespace,
+		metaLabelPrefix + "service_name": service.Name,
+	}
+	addMetaLabels(ls, metaLabelPrefix+"service_label_", service.Labels)
+	addMetaLabels(ls, metaLabelPrefix+"service_annotation_", service.Annotations)
+	return ls
+}
+
+// addMetaLabels adds each of the values as a label named with the prefix, replacing the
+// characters of names which are not valid in label names with underscores.
+func addMetaLabels(ls map[string]string, prefix string, values map[string]string) {
+	for k, v := range values {
+		ls[prefix+invalidLabelCharRegex.ReplaceAllString(k, "_")] = v
+	}
+}
+
+func sortedPods(pods []*clustercache.Pod) []*clustercache.Pod {
+	sorted := slices.Clone(pods)
+	slices.SortFunc(sorted, func(a, b *clustercache.Pod) int {
+		if a.Namespace != b.Namespace {
+			return cmp.Compare(a.Namespace, b.Namespace)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
+
+func sortedServices(services []*clustercache.Service) []*clustercache.Service {
+	sorted := slices.Clone(services)
+	slices.SortFunc(sorted, func(a, b *clustercache.Service) int {
+		if a.Namespace != b.Namespace {
+			return cmp.Compare(a.Namespace, b.Namespace)
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return sorted
+}
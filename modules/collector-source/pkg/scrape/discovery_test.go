@@ -0,0 +1,160 @@
+package scrape
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestKubernetesSDConfig_discover(t *testing.T) {
+	deleted := time.Now()
+
+	pods := []*clustercache.Pod{
+		{
+			Name:        "exporter-b",
+			Namespace:   "gpu",
+			UID:         "uid-b",
+			Labels:      map[string]string{"app": "gpu-exporter"},
+			Annotations: map[string]string{"prometheus.io/scrape": "true"},
+			Spec:        clustercache.PodSpec{NodeName: "node-b"},
+			Status:      clustercache.PodStatus{PodIP: "10.0.0.2", Phase: v1.PodRunning},
+		},
+		{
+			Name:      "exporter-a",
+			Namespace: "gpu",
+			UID:       "uid-a",
+			Labels:    map[string]string{"app": "gpu-exporter"},
+			Spec:      clustercache.PodSpec{NodeName: "node-a"},
+			Status:    clustercache.PodStatus{PodIP: "10.0.0.1", Phase: v1.PodRunning},
+		},
+		{
+			Name:      "exporter-pending",
+			Namespace: "gpu",
+			Labels:    map[string]string{"app": "gpu-exporter"},
+			Status:    clustercache.PodStatus{Phase: v1.PodPending},
+		},
+		{
+			Name:              "exporter-deleted",
+			Namespace:         "gpu",
+			Labels:            map[string]string{"app": "gpu-exporter"},
+			Status:            clustercache.PodStatus{PodIP: "10.0.0.3", Phase: v1.PodRunning},
+			DeletionTimestamp: &deleted,
+		},
+		{
+			Name:      "power-meter",
+			Namespace: "power",
+			Labels:    map[string]string{"app": "power-meter"},
+			Status:    clustercache.PodStatus{PodIP: "10.0.1.1", Phase: v1.PodRunning},
+		},
+	}
+
+	services := []*clustercache.Service{
+		{
+			Name:         "gpu-exporter",
+			Namespace:    "gpu",
+			Labels:       map[string]string{"app.kubernetes.io/name": "gpu-exporter"},
+			SpecSelector: map[string]string{"app": "gpu-exporter"},
+		},
+		{
+			Name:      "external",
+			Namespace: "gpu",
+			Labels:    map[string]string{"app.kubernetes.io/name": "gpu-exporter"},
+		},
+	}
+
+	podLabels := func(name, uid, ip, node string) map[string]string {
+		return map[string]string{
+			"__meta_kubernetes_namespace":     "gpu",
+			"__meta_kubernetes_pod_name":      name,
+			"__meta_kubernetes_pod_ip":        ip,
+			"__meta_kubernetes_pod_node_name": node,
+			"__meta_kubernetes_pod_uid":       uid,
+			"__meta_kubernetes_pod_label_app": "gpu-exporter",
+		}
+	}
+	serviceLabels := func(name string) map[string]string {
+		return map[string]string{
+			"__meta_kubernetes_namespace":                            "gpu",
+			"__meta_kubernetes_service_name":                         name,
+			"__meta_kubernetes_service_label_app_kubernetes_io_name": "gpu-exporter",
+		}
+	}
+
+	tests := map[string]struct {
+		config KubernetesSDConfig
+		want   []map[string]string
+	}{
+		"pods by selector": {
+			config: KubernetesSDConfig{Role: KubernetesRolePod, Selector: "app=gpu-exporter", Port: 9400},
+			want: []map[string]string{
+				with(podLabels("exporter-a", "uid-a", "10.0.0.1", "node-a"), map[string]string{"__address__": "10.0.0.1:9400"}),
+				with(podLabels("exporter-b", "uid-b", "10.0.0.2", "node-b"), map[string]string{
+					"__address__": "10.0.0.2:9400",
+					"__meta_kubernetes_pod_annotation_prometheus_io_scrape": "true",
+				}),
+			},
+		},
+		"pods by annotation": {
+			config: KubernetesSDConfig{Role: KubernetesRolePod, Annotations: map[string]string{"prometheus.io/scrape": "true"}},
+			want: []map[string]string{
+				with(podLabels("exporter-b", "uid-b", "10.0.0.2", "node-b"), map[string]string{
+					"__address__": "10.0.0.2",
+					"__meta_kubernetes_pod_annotation_prometheus_io_scrape": "true",
+				}),
+			},
+		},
+		"pods by namespace": {
+			config: KubernetesSDConfig{Role: KubernetesRolePod, Namespaces: []string{"power"}, Port: 8080},
+			want: []map[string]string{
+				{
+					"__address__":                     "10.0.1.1:8080",
+					"__meta_kubernetes_namespace":     "power",
+					"__meta_kubernetes_pod_name":      "power-meter",
+					"__meta_kubernetes_pod_ip":        "10.0.1.1",
+					"__meta_kubernetes_pod_node_name": "",
+					"__meta_kubernetes_pod_uid":       "",
+					"__meta_kubernetes_pod_label_app": "power-meter",
+				},
+			},
+		},
+		"services": {
+			config: KubernetesSDConfig{Role: KubernetesRoleService, Selector: "app.kubernetes.io/name=gpu-exporter", Port: 9400},
+			want: []map[string]string{
+				with(serviceLabels("external"), map[string]string{"__address__": "external.gpu.svc:9400"}),
+				with(serviceLabels("gpu-exporter"), map[string]string{"__address__": "gpu-exporter.gpu.svc:9400"}),
+			},
+		},
+		"endpoints": {
+			config: KubernetesSDConfig{Role: KubernetesRoleEndpoints, Selector: "app.kubernetes.io/name=gpu-exporter", Port: 9400},
+			want: []map[string]string{
+				with(with(serviceLabels("gpu-exporter"), podLabels("exporter-a", "uid-a", "10.0.0.1", "node-a")), map[string]string{
+					"__address__": "10.0.0.1:9400",
+				}),
+				with(with(serviceLabels("gpu-exporter"), podLabels("exporter-b", "uid-b", "10.0.0.2", "node-b")), map[string]string{
+					"__address__": "10.0.0.2:9400",
+					"__meta_kubernetes_pod_annotation_prometheus_io_scrape": "true",
+				}),
+			},
+		},
+		"no matches": {
+			config: KubernetesSDConfig{Role: KubernetesRoleEndpoints, Selector: "app=unknown"},
+			want:   nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.config.validate(); err != nil {
+				t.Fatalf("validate() error = %v", err)
+			}
+
+			got := tt.config.discover(pods, services)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discover() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseOpenMetrics reads the input reader containing the OpenMetrics text format, and returns a slice of
+// MetricRecord instances containing the data parsed from the input.
+func ParseOpenMetrics(reader io.Reader) ([]*MetricRecord, error) {
+	return newOpenMetricsParser(reader).parse()
+}
+
+// Parses Metrics from the OpenMetrics text format, see https://prometheus.io/docs/specs/om/open_metrics_spec/.
+//
+// Unlike the Prometheus text format, the format is line based and terminated by "# EOF":
+//
+//	# TYPE metric_family type
+//	# UNIT metric_family unit
+//	# HELP metric_family help
+//	metric_name ["{" label_name "=" `"` label_value `"` { "," ... } "}"] " " value [ " " timestamp ] [ " # " exemplar ]
+//	# EOF
+//
+// In the sample syntax:
+//   - timestamp is a float of seconds since epoch, rather than the milliseconds of the
+//     Prometheus text format, and is kept to microsecond precision.
+//   - exemplars are validated, but are not collected.
+//   - the metric_family_created samples of counters, histograms and summaries carry the
+//     creation time of the series rather than a value, so they are dropped.
+//   - the unit, which must be a suffix of the metric family name, is validated.
+type openMetricsParser struct {
+	reader *bufio.Reader
+	line   int
+
+	// the name and type of the metric family of the last TYPE line
+	family     string
+	familyType string
+}
+
+// creates a new openMetricsParser, which is meant to be used once and discarded
+func newOpenMetricsParser(r io.Reader) *openMetricsParser {
+	return &openMetricsParser{
+		reader: bufio.NewReader(r),
+	}
+}
+
+func (p *openMetricsParser) parse() ([]*MetricRecord, error) {
+	var metrics []*MetricRecord
+
+	for {
+		line, err := p.reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+		if line == "" && err == io.EOF {
+			return nil, fmt.Errorf("[openmetrics parse error] unexpected end of input, expected '# EOF'")
+		}
+		p.line++
+		line = strings.TrimSuffix(line, "\n")
+
+		if line == "# EOF" {
+			// nothing may follow the end of the exposition, other than a trailing newline
+			rest, _ := io.ReadAll(p.reader)
+			if len(rest) > 0 {
+				return nil, p.errorf("unexpected content after '# EOF'")
+			}
+			return metrics, nil
+		}
+
+		if strings.HasPrefix(line, "#") {
+			if err := p.parseMetadata(line); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		metric, err := p.parseSample(line)
+		if err != nil {
+			return nil, err
+		}
+		if metric != nil {
+			metrics = append(metrics, metric)
+		}
+	}
+}
+
+func (p *openMetricsParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("[openmetrics parse error] line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// parseMetadata parses a HELP, TYPE or UNIT line, which are the only comments OpenMetrics allows.
+func (p *openMetricsParser) parseMetadata(line string) error {
+	fields := strings.SplitN(line, " ", 4)
+	if len(fields) < 3 || fields[0] != "#" {
+		return p.errorf("invalid metadata %q", line)
+	}
+
+	keyword, family := fields[1], fields[2]
+	if !isMetricName(family) {
+		return p.errorf("invalid metric family name %q", family)
+	}
+
+	var value string
+	if len(fields) == 4 {
+		value = fields[3]
+	}
+
+	switch keyword {
+	case "HELP":
+	case "TYPE":
+		switch value {
+		case "counter", "gauge", "histogram", "gaugehistogram", "stateset", "info", "summary", "unknown":
+		default:
+			return p.errorf("invalid metric type %q", value)
+		}
+		p.family = family
+		p.familyType = value
+	case "UNIT":
+		if value != "" && !strings.HasSuffix(family, "_"+value) {
+			return p.errorf("unit %q is not a suffix of metric family %q", value, family)
+		}
+	default:
+		return p.errorf("invalid metadata %q", line)
+	}
+
+	return nil
+}
+
+// parseSample parses a sample line, returning nil for samples which are not collected.
+func (p *openMetricsParser) parseSample(line string) (*MetricRecord, error) {
+	name, rest := splitMetricName(line)
+	if !isMetricName(name) {
+		return nil, p.errorf("invalid metric name in %q", line)
+	}
+
+	metric := &MetricRecord{
+		Name: name,
+	}
+
+	if strings.HasPrefix(rest, "{") {
+		labels, r, err := parseOpenMetricsLabels(rest)
+		if err != nil {
+			return nil, p.errorf("%s", err)
+		}
+		metric.Labels = labels
+		rest = r
+	}
+
+	// separate the exemplar, if any, from the value and timestamp
+	rest, exemplar, hasExemplar := strings.Cut(rest, " # ")
+	if hasExemplar {
+		if err := validateExemplar(exemplar); err != nil {
+			return nil, p.errorf("%s", err)
+		}
+	}
+
+	if !strings.HasPrefix(rest, " ") {
+		return nil, p.errorf("expected ' ' before value in %q", line)
+	}
+
+	v, ts, err := parseOpenMetricsValueAndTimestamp(rest[1:])
+	if err != nil {
+		return nil, p.errorf("%s", err)
+	}
+	metric.Value = v
+	metric.Timestamp = ts
+
+	if p.isCreatedSample(name) {
+		return nil, nil
+	}
+
+	return metric, nil
+}
+
+// isCreatedSample returns true if the sample is the creation time of a counter, histogram or
+// summary series.
+func (p *openMetricsParser) isCreatedSample(name string) bool {
+	switch p.familyType {
+	case "counter", "histogram", "summary":
+		return name == p.family+"_created"
+	}
+	return false
+}
+
+// validateExemplar checks the syntax of an exemplar, which is a label set followed by a value
+// and optional timestamp.
+func validateExemplar(exemplar string) error {
+	if !strings.HasPrefix(exemplar, "{") {
+		return fmt.Errorf("invalid exemplar %q", exemplar)
+	}
+
+	_, rest, err := parseOpenMetricsLabels(exemplar)
+	if err != nil {
+		return fmt.Errorf("invalid exemplar %q: %w", exemplar, err)
+	}
+	if !strings.HasPrefix(rest, " ") {
+		return fmt.Errorf("invalid exemplar %q: expected ' ' before value", exemplar)
+	}
+	if _, _, err := parseOpenMetricsValueAndTimestamp(rest[1:]); err != nil {
+		return fmt.Errorf("invalid exemplar %q: %w", exemplar, err)
+	}
+	return nil
+}
+
+// parseOpenMetricsLabels parses the label set at the start of s, returning the labels and the
+// remainder of s following the closing bracket.
+func parseOpenMetricsLabels(s string) (map[string]string, string, error) {
+	// skip '{'
+	s = s[1:]
+
+	var labels map[string]string
+	for {
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok || !isLabelName(name) {
+			return nil, "", fmt.Errorf("invalid label in %q", s)
+		}
+		if !strings.HasPrefix(rest, `"`) {
+			return nil, "", fmt.Errorf("expected '\"' after label name %q", name)
+		}
+
+		value, rest, err := parseOpenMetricsString(rest[1:])
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid value for label %q: %w", name, err)
+		}
+
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[name] = value
+
+		switch {
+		case strings.HasPrefix(rest, ","):
+			s = rest[1:]
+		case strings.HasPrefix(rest, "}"):
+			s = rest
+		default:
+			return nil, "", fmt.Errorf("expected ',' or '}' after label %q", name)
+		}
+	}
+}
+
+// parseOpenMetricsString parses an escaped string following its opening quote, returning the
+// unescaped value and the remainder of s following the closing quote.
+func parseOpenMetricsString(s string) (string, string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			return sb.String(), s[i+1:], nil
+		case '\\':
+			i++
+			if i == len(s) {
+				return "", "", fmt.Errorf("unterminated string")
+			}
+			switch s[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case '\\', '"':
+				sb.WriteByte(s[i])
+			default:
+				return "", "", fmt.Errorf("invalid escape sequence '\\%c'", s[i])
+			}
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated string")
+}
+
+func parseOpenMetricsValueAndTimestamp(s string) (float64, *time.Time, error) {
+	value, timestamp, hasTimestamp := strings.Cut(s, " ")
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0.0, nil, fmt.Errorf("failed to parse value %v: %v", value, err)
+	}
+
+	if !hasTimestamp {
+		return v, nil, nil
+	}
+
+	t, err := strconv.ParseFloat(timestamp, 64)
+	if err != nil || math.IsNaN(t) || math.IsInf(t, 0) {
+		return 0.0, nil, fmt.Errorf("failed to parse timestamp %v", timestamp)
+	}
+
+	sec, frac := math.Modf(t)
+	ts := time.Unix(int64(sec), int64(math.Round(frac*1e6))*int64(time.Microsecond))
+
+	return v, &ts, nil
+}
+
+// splitMetricName returns the metric name at the start of the line, and the remainder of the
+// line following it.
+func splitMetricName(line string) (string, string) {
+	i := strings.IndexAny(line, "{ ")
+	if i < 0 {
+		return line, ""
+	}
+	return line[:i], line[i:]
+}
+
+func isMetricName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, ch := range name {
+		if ch == ':' || ch == '_' || isASCIILetter(ch) || (i > 0 && ch >= '0' && ch <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isLabelName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, ch := range name {
+		if ch == '_' || isASCIILetter(ch) || (i > 0 && ch >= '0' && ch <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isASCIILetter(ch rune) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
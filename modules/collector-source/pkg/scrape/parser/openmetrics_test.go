@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const openMetricsCases = `# HELP DCGM_FI_DEV_GPU_UTIL GPU utilization (in %).
+# TYPE DCGM_FI_DEV_GPU_UTIL gauge
+DCGM_FI_DEV_GPU_UTIL{gpu="0",pod="training-0"} 75 1708014188.74
+# TYPE http_requests counter
+# HELP http_requests Requests served, with "escapes" \ in help.
+http_requests_total{code="200",path="/a\"b\\c\nd"} 1027.0 # {trace_id="KOO5S4vxi0o"} 0.67
+http_requests_created{code="200",path="/a\"b\\c\nd"} 1708014000.5
+# TYPE node_power_watts gauge
+# UNIT node_power_watts watts
+node_power_watts 1.5e2
+# TYPE request_duration_seconds histogram
+# UNIT request_duration_seconds seconds
+request_duration_seconds_bucket{le="0.5"} 3 # {trace_id="a"} 0.25 1708014188.5
+request_duration_seconds_bucket{le="+Inf"} 4
+request_duration_seconds_count 4
+request_duration_seconds_sum 2.5
+request_duration_seconds_created 1708014000
+# TYPE pod_created gauge
+pod_created{pod="a"} 1708014000
+# EOF
+`
+
+func TestParseOpenMetrics(t *testing.T) {
+	ts := time.Unix(1708014188, 740000000)
+
+	want := []*MetricRecord{
+		{Name: "DCGM_FI_DEV_GPU_UTIL", Labels: map[string]string{"gpu": "0", "pod": "training-0"}, Value: 75, Timestamp: &ts},
+		{Name: "http_requests_total", Labels: map[string]string{"code": "200", "path": "/a\"b\\c\nd"}, Value: 1027},
+		{Name: "node_power_watts", Value: 150},
+		{Name: "request_duration_seconds_bucket", Labels: map[string]string{"le": "0.5"}, Value: 3},
+		{Name: "request_duration_seconds_bucket", Labels: map[string]string{"le": "+Inf"}, Value: 4},
+		{Name: "request_duration_seconds_count", Value: 4},
+		{Name: "request_duration_seconds_sum", Value: 2.5},
+		// only the _created samples of counters, histograms and summaries are dropped
+		{Name: "pod_created", Labels: map[string]string{"pod": "a"}, Value: 1708014000},
+	}
+
+	got, err := ParseOpenMetrics(strings.NewReader(openMetricsCases))
+	if err != nil {
+		t.Fatalf("ParseOpenMetrics() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseOpenMetrics() returned %d metrics, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("ParseOpenMetrics()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseOpenMetrics_Errors(t *testing.T) {
+	tests := map[string]string{
+		"missing eof":          "a_metric 1\n",
+		"content after eof":    "a_metric 1\n# EOF\na_metric 2\n",
+		"invalid comment":      "# random comment\na_metric 1\n# EOF\n",
+		"invalid type":         "# TYPE a_metric timer\n# EOF\n",
+		"unit not a suffix":    "# TYPE a_metric gauge\n# UNIT a_metric seconds\n# EOF\n",
+		"invalid timestamp":    "a_metric 1 notatimestamp\n# EOF\n",
+		"invalid value":        "a_metric one\n# EOF\n",
+		"unterminated labels":  "a_metric{a=\"b\" 1\n# EOF\n",
+		"invalid escape":       "a_metric{a=\"\\t\"} 1\n# EOF\n",
+		"invalid exemplar":     "a_metric_total 1 # trace_id=\"a\" 1\n# EOF\n",
+		"invalid metric name":  "1_metric 1\n# EOF\n",
+		"missing value spaces": "a_metric{a=\"b\"}1\n# EOF\n",
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseOpenMetrics(strings.NewReader(input)); err == nil {
+				t.Errorf("ParseOpenMetrics(%q) succeeded, want error", input)
+			}
+		})
+	}
+}
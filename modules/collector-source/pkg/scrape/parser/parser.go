@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"io"
+	"mime"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +23,47 @@ func Parse(reader io.Reader) ([]*MetricRecord, error) {
 	return newParser(reader).parse()
 }
 
+// The media types of the supported exposition formats.
+const (
+	TextMediaType        = "text/plain"
+	OpenMetricsMediaType = "application/openmetrics-text"
+	ProtobufMediaType    = "application/vnd.google.protobuf"
+
+	// protobufMessageType is the "proto" parameter of the only supported protobuf media type.
+	protobufMessageType = "io.prometheus.client.MetricFamily"
+)
+
+// AcceptHeader is the Accept header to request metrics with, preferring the protobuf format, which is
+// the cheapest to parse, then OpenMetrics, then the Prometheus text format.
+const AcceptHeader = ProtobufMediaType + ";proto=" + protobufMessageType + ";encoding=delimited;q=0.7," +
+	OpenMetricsMediaType + ";version=1.0.0;q=0.5," +
+	TextMediaType + ";version=0.0.4;q=0.3,*/*;q=0.1"
+
+// ParseContentType reads the input reader, parsing it as the format of the given Content-Type header
+// value. The Prometheus text format is assumed when the content type is empty or unknown.
+func ParseContentType(reader io.Reader, contentType string) ([]*MetricRecord, error) {
+	if contentType == "" {
+		return Parse(reader)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return Parse(reader)
+	}
+
+	switch mediaType {
+	case OpenMetricsMediaType:
+		return ParseOpenMetrics(reader)
+	case ProtobufMediaType:
+		if params["proto"] != protobufMessageType || params["encoding"] != "delimited" {
+			return nil, fmt.Errorf("unsupported protobuf content type '%s'", contentType)
+		}
+		return ParseProtobuf(reader)
+	default:
+		return Parse(reader)
+	}
+}
+
 // Parses Metrics from raw metric format.
 //
 // metric_name ["{" label_name "=" `"` label_value `"` { "," label_name"=" `"` label_value `"` } [ "," ] "}"] value [ timestamp ]
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// maxMetricFamilyBytes is the maximum size of a single delimited MetricFamily message.
+const maxMetricFamilyBytes = 64 * 1024 * 1024
+
+// ParseProtobuf reads the input reader containing the Prometheus protobuf exposition format, a stream of
+// varint length delimited io.prometheus.client.MetricFamily messages, and returns a slice of MetricRecord
+// instances containing the data parsed from the input.
+//
+// Summaries and histograms are expanded into the same samples as the Prometheus text format: a sample
+// per quantile or bucket, the latter including the "+Inf" bucket, and the _sum and _count samples.
+// Exemplars, created timestamps and native histogram buckets are not collected.
+func ParseProtobuf(reader io.Reader) ([]*MetricRecord, error) {
+	r := bufio.NewReader(reader)
+	opts := protodelim.UnmarshalOptions{
+		MaxSize: maxMetricFamilyBytes,
+	}
+
+	var metrics []*MetricRecord
+	for {
+		family := &dto.MetricFamily{}
+		err := opts.UnmarshalFrom(r, family)
+		if errors.Is(err, io.EOF) {
+			return metrics, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("[protobuf parse error] failed to read metric family: %w", err)
+		}
+
+		metrics, err = appendMetricFamily(metrics, family)
+		if err != nil {
+			return nil, fmt.Errorf("[protobuf parse error] invalid metric family: %w", err)
+		}
+	}
+}
+
+// appendMetricFamily appends the samples of the metric family to metrics.
+func appendMetricFamily(metrics []*MetricRecord, family *dto.MetricFamily) ([]*MetricRecord, error) {
+	name := family.GetName()
+	if !isMetricName(name) {
+		return nil, fmt.Errorf("invalid metric family name %q", name)
+	}
+
+	for _, m := range family.GetMetric() {
+		labels := make(map[string]string, len(m.GetLabel()))
+		for _, label := range m.GetLabel() {
+			if !isLabelName(label.GetName()) {
+				return nil, fmt.Errorf("invalid label name %q of %s", label.GetName(), name)
+			}
+			labels[label.GetName()] = label.GetValue()
+		}
+
+		var timestamp *time.Time
+		if m.TimestampMs != nil {
+			ts := time.UnixMilli(m.GetTimestampMs())
+			timestamp = &ts
+		}
+
+		record := func(name string, value float64, boundLabel string, bound float64) *MetricRecord {
+			return newRecord(name, labels, timestamp, value, boundLabel, bound)
+		}
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			metrics = append(metrics, record(name, m.GetCounter().GetValue(), "", 0))
+		case dto.MetricType_GAUGE:
+			metrics = append(metrics, record(name, m.GetGauge().GetValue(), "", 0))
+		case dto.MetricType_UNTYPED:
+			metrics = append(metrics, record(name, m.GetUntyped().GetValue(), "", 0))
+		case dto.MetricType_SUMMARY:
+			summary := m.GetSummary()
+			for _, q := range summary.GetQuantile() {
+				metrics = append(metrics, record(name, q.GetValue(), "quantile", q.GetQuantile()))
+			}
+			metrics = append(metrics, record(name+"_sum", summary.GetSampleSum(), "", 0))
+			metrics = append(metrics, record(name+"_count", float64(summary.GetSampleCount()), "", 0))
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			histogram := m.GetHistogram()
+			count := float64(histogram.GetSampleCount())
+			if histogram.SampleCountFloat != nil {
+				count = histogram.GetSampleCountFloat()
+			}
+
+			hasInf := false
+			for _, bucket := range histogram.GetBucket() {
+				value := float64(bucket.GetCumulativeCount())
+				if bucket.CumulativeCountFloat != nil {
+					value = bucket.GetCumulativeCountFloat()
+				}
+				metrics = append(metrics, record(name+"_bucket", value, "le", bucket.GetUpperBound()))
+				hasInf = hasInf || math.IsInf(bucket.GetUpperBound(), 1)
+			}
+			if !hasInf {
+				metrics = append(metrics, record(name+"_bucket", count, "le", math.Inf(1)))
+			}
+			metrics = append(metrics, record(name+"_sum", histogram.GetSampleSum(), "", 0))
+			metrics = append(metrics, record(name+"_count", count, "", 0))
+		default:
+			return nil, fmt.Errorf("unsupported type %s of %s", family.GetType(), name)
+		}
+	}
+
+	return metrics, nil
+}
+
+// newRecord returns a MetricRecord for a sample, adding the bound label when set. Each record
+// is given its own copy of the labels.
+func newRecord(name string, labels map[string]string, timestamp *time.Time, value float64, boundLabel string, bound float64) *MetricRecord {
+	var recordLabels map[string]string
+	if len(labels) > 0 || boundLabel != "" {
+		recordLabels = make(map[string]string, len(labels)+1)
+		for k, v := range labels {
+			recordLabels[k] = v
+		}
+		if boundLabel != "" {
+			recordLabels[boundLabel] = formatFloat(bound)
+		}
+	}
+
+	return &MetricRecord{
+		Name:      name,
+		Labels:    recordLabels,
+		Value:     value,
+		Timestamp: timestamp,
+	}
+}
+
+// formatFloat formats quantiles and bucket bounds in the same way as the Prometheus text format.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
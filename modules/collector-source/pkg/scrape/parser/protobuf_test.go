@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+// delimited encodes the metric families as a stream of varint length delimited messages.
+func delimited(t *testing.T, families ...*dto.MetricFamily) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		if _, err := protodelim.MarshalTo(&buf, family); err != nil {
+			t.Fatalf("failed to marshal metric family: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func labelPairs(pairs ...string) []*dto.LabelPair {
+	var labels []*dto.LabelPair
+	for i := 0; i < len(pairs); i += 2 {
+		labels = append(labels, &dto.LabelPair{Name: proto.String(pairs[i]), Value: proto.String(pairs[i+1])})
+	}
+	return labels
+}
+
+func TestParseProtobuf(t *testing.T) {
+	ts := time.UnixMilli(1708014188740)
+
+	input := delimited(t,
+		&dto.MetricFamily{
+			Name: proto.String("DCGM_FI_DEV_GPU_UTIL"),
+			Help: proto.String("GPU utilization (in %)."),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label:       labelPairs("gpu", "0", "pod", "training-0"),
+					Gauge:       &dto.Gauge{Value: proto.Float64(75)},
+					TimestampMs: proto.Int64(ts.UnixMilli()),
+				},
+			},
+		},
+		&dto.MetricFamily{
+			Name: proto.String("http_requests_total"),
+			Type: dto.MetricType_COUNTER.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label: labelPairs("code", "200"),
+					Counter: &dto.Counter{
+						Value: proto.Float64(1027),
+						// exemplars are not collected
+						Exemplar: &dto.Exemplar{Label: labelPairs("trace_id", "abc"), Value: proto.Float64(1)},
+					},
+				},
+			},
+		},
+		&dto.MetricFamily{
+			Name: proto.String("rpc_duration_seconds"),
+			Type: dto.MetricType_SUMMARY.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Summary: &dto.Summary{
+						SampleCount: proto.Uint64(10),
+						SampleSum:   proto.Float64(2.5),
+						Quantile:    []*dto.Quantile{{Quantile: proto.Float64(0.99), Value: proto.Float64(0.5)}},
+					},
+				},
+			},
+		},
+		&dto.MetricFamily{
+			Name: proto.String("request_duration_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{
+				{
+					Label: labelPairs("path", "/"),
+					Histogram: &dto.Histogram{
+						SampleCount: proto.Uint64(4),
+						SampleSum:   proto.Float64(2.5),
+						Bucket: []*dto.Bucket{
+							{CumulativeCount: proto.Uint64(3), UpperBound: proto.Float64(0.5)},
+							{CumulativeCountFloat: proto.Float64(3.5), UpperBound: proto.Float64(1e6)},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	want := []*MetricRecord{
+		{Name: "DCGM_FI_DEV_GPU_UTIL", Labels: map[string]string{"gpu": "0", "pod": "training-0"}, Value: 75, Timestamp: &ts},
+		{Name: "http_requests_total", Labels: map[string]string{"code": "200"}, Value: 1027},
+		{Name: "rpc_duration_seconds", Labels: map[string]string{"quantile": "0.99"}, Value: 0.5},
+		{Name: "rpc_duration_seconds_sum", Value: 2.5},
+		{Name: "rpc_duration_seconds_count", Value: 10},
+		{Name: "request_duration_seconds_bucket", Labels: map[string]string{"path": "/", "le": "0.5"}, Value: 3},
+		{Name: "request_duration_seconds_bucket", Labels: map[string]string{"path": "/", "le": "1e+06"}, Value: 3.5},
+		{Name: "request_duration_seconds_bucket", Labels: map[string]string{"path": "/", "le": "+Inf"}, Value: 4},
+		{Name: "request_duration_seconds_sum", Labels: map[string]string{"path": "/"}, Value: 2.5},
+		{Name: "request_duration_seconds_count", Labels: map[string]string{"path": "/"}, Value: 4},
+	}
+
+	got, err := ParseProtobuf(bytes.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseProtobuf() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseProtobuf() returned %d metrics, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("ParseProtobuf()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// truncated input is an error
+	if _, err := ParseProtobuf(bytes.NewReader(input[:len(input)-3])); err == nil {
+		t.Errorf("ParseProtobuf() of truncated input succeeded")
+	}
+}
+
+func TestParseContentType(t *testing.T) {
+	protobuf := delimited(t, &dto.MetricFamily{
+		Name: proto.String("a_metric"),
+		Type: dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{
+			{Gauge: &dto.Gauge{Value: proto.Float64(1)}},
+		},
+	})
+
+	tests := map[string]struct {
+		input       string
+		contentType string
+		wantErr     bool
+	}{
+		"unknown": {
+			input:       "a_metric 1\n",
+			contentType: "",
+		},
+		"text": {
+			input:       "a_metric 1\n",
+			contentType: "text/plain; version=0.0.4; charset=utf-8",
+		},
+		"openmetrics": {
+			input:       "a_metric 1\n# EOF\n",
+			contentType: "application/openmetrics-text; version=1.0.0; charset=utf-8",
+		},
+		"protobuf": {
+			input:       string(protobuf),
+			contentType: "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=delimited",
+		},
+		"protobuf text encoding": {
+			input:       string(protobuf),
+			contentType: "application/vnd.google.protobuf; proto=io.prometheus.client.MetricFamily; encoding=text",
+			wantErr:     true,
+		},
+	}
+
+	want := []*MetricRecord{{Name: "a_metric", Value: 1}}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseContentType(strings.NewReader(tt.input), tt.contentType)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseContentType() succeeded, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseContentType() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseContentType() = %v, want %v", got, want)
+			}
+		})
+	}
+}
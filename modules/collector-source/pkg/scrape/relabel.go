@@ -0,0 +1,165 @@
+package scrape
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RelabelAction is the action performed by a RelabelConfig.
+type RelabelAction string
+
+const (
+	// RelabelReplace sets the target label to the replacement, expanded with the
+	// regex capture groups, when the regex matches the source labels.
+	RelabelReplace RelabelAction = "replace"
+
+	// RelabelKeep drops the labeled object when the regex does not match the source labels.
+	RelabelKeep RelabelAction = "keep"
+
+	// RelabelDrop drops the labeled object when the regex matches the source labels.
+	RelabelDrop RelabelAction = "drop"
+
+	// RelabelLabelMap copies each label whose name matches the regex to the label named
+	// by the replacement, expanded with the regex capture groups.
+	RelabelLabelMap RelabelAction = "labelmap"
+
+	// RelabelLabelDrop removes each label whose name matches the regex.
+	RelabelLabelDrop RelabelAction = "labeldrop"
+
+	// RelabelLabelKeep removes each label whose name does not match the regex.
+	RelabelLabelKeep RelabelAction = "labelkeep"
+)
+
+const (
+	defaultRelabelSeparator   = ";"
+	defaultRelabelRegex       = "(.*)"
+	defaultRelabelReplacement = "$1"
+)
+
+// RelabelConfig rewrites the labels of a discovered target, or of a scraped metric, in
+// the same manner as Prometheus relabeling.
+type RelabelConfig struct {
+	SourceLabels []string      `json:"source_labels,omitempty"`
+	Separator    string        `json:"separator,omitempty"`
+	Regex        string        `json:"regex,omitempty"`
+	TargetLabel  string        `json:"target_label,omitempty"`
+	Replacement  *string       `json:"replacement,omitempty"`
+	Action       RelabelAction `json:"action,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// compile applies the defaults of the config and compiles its regex.
+func (rc *RelabelConfig) compile() error {
+	if rc.Action == "" {
+		rc.Action = RelabelReplace
+	}
+	if rc.Separator == "" {
+		rc.Separator = defaultRelabelSeparator
+	}
+	if rc.Regex == "" {
+		rc.Regex = defaultRelabelRegex
+	}
+	if rc.Replacement == nil {
+		replacement := defaultRelabelReplacement
+		rc.Replacement = &replacement
+	}
+
+	regex, err := regexp.Compile("^(?:" + rc.Regex + ")$")
+	if err != nil {
+		return fmt.Errorf("invalid regex '%s': %w", rc.Regex, err)
+	}
+	rc.regex = regex
+
+	switch rc.Action {
+	case RelabelReplace:
+		if rc.TargetLabel == "" {
+			return fmt.Errorf("relabel action '%s' requires a target_label", rc.Action)
+		}
+	case RelabelKeep, RelabelDrop:
+		if len(rc.SourceLabels) == 0 {
+			return fmt.Errorf("relabel action '%s' requires source_labels", rc.Action)
+		}
+	case RelabelLabelMap, RelabelLabelDrop, RelabelLabelKeep:
+	default:
+		return fmt.Errorf("unknown relabel action '%s'", rc.Action)
+	}
+
+	return nil
+}
+
+// relabel applies each of the configs to a copy of the labels in order, returning
+// the result, or false if the labeled object should be dropped.
+func relabel(labels map[string]string, configs []*RelabelConfig) (map[string]string, bool) {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		result[k] = v
+	}
+
+	for _, rc := range configs {
+		if !rc.apply(result) {
+			return nil, false
+		}
+	}
+	return result, true
+}
+
+// apply applies the config to the labels in place, returning false if the labeled
+// object should be dropped.
+func (rc *RelabelConfig) apply(labels map[string]string) bool {
+	values := make([]string, 0, len(rc.SourceLabels))
+	for _, name := range rc.SourceLabels {
+		values = append(values, labels[name])
+	}
+	value := strings.Join(values, rc.Separator)
+
+	switch rc.Action {
+	case RelabelKeep:
+		return rc.regex.MatchString(value)
+
+	case RelabelDrop:
+		return !rc.regex.MatchString(value)
+
+	case RelabelReplace:
+		match := rc.regex.FindStringSubmatchIndex(value)
+		if match == nil {
+			return true
+		}
+
+		target := string(rc.regex.ExpandString(nil, rc.TargetLabel, value, match))
+		replacement := string(rc.regex.ExpandString(nil, *rc.Replacement, value, match))
+		if replacement == "" {
+			delete(labels, target)
+		} else {
+			labels[target] = replacement
+		}
+
+	case RelabelLabelMap:
+		mapped := make(map[string]string)
+		for name, v := range labels {
+			if match := rc.regex.FindStringSubmatchIndex(name); match != nil {
+				mapped[string(rc.regex.ExpandString(nil, *rc.Replacement, name, match))] = v
+			}
+		}
+		for name, v := range mapped {
+			labels[name] = v
+		}
+
+	case RelabelLabelDrop:
+		for name := range labels {
+			if rc.regex.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+
+	case RelabelLabelKeep:
+		for name := range labels {
+			if !rc.regex.MatchString(name) {
+				delete(labels, name)
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,177 @@
+package scrape
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_relabel(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+
+	labels := map[string]string{
+		"__address__":                              "10.0.0.1",
+		"__meta_kubernetes_namespace":              "gpu",
+		"__meta_kubernetes_pod_name":               "exporter-1",
+		"__meta_kubernetes_pod_annotation_port":    "9400",
+		"__meta_kubernetes_pod_label_app":          "gpu-exporter",
+		"__meta_kubernetes_pod_label_team_example": "ml",
+	}
+
+	tests := map[string]struct {
+		configs []*RelabelConfig
+		want    map[string]string
+		keep    bool
+	}{
+		"no configs": {
+			configs: nil,
+			want:    labels,
+			keep:    true,
+		},
+		"replace defaults": {
+			configs: []*RelabelConfig{
+				{SourceLabels: []string{"__meta_kubernetes_namespace"}, TargetLabel: "namespace"},
+			},
+			want: with(labels, map[string]string{"namespace": "gpu"}),
+			keep: true,
+		},
+		"replace with capture groups": {
+			configs: []*RelabelConfig{
+				{
+					SourceLabels: []string{"__address__", "__meta_kubernetes_pod_annotation_port"},
+					Regex:        `([^:]+)(?::\d+)?;(\d+)`,
+					Replacement:  ptr("$1:$2"),
+					TargetLabel:  "__address__",
+				},
+			},
+			want: with(labels, map[string]string{"__address__": "10.0.0.1:9400"}),
+			keep: true,
+		},
+		"replace without match": {
+			configs: []*RelabelConfig{
+				{SourceLabels: []string{"__meta_kubernetes_namespace"}, Regex: "kube-system", TargetLabel: "namespace"},
+			},
+			want: labels,
+			keep: true,
+		},
+		"replace with empty value removes label": {
+			configs: []*RelabelConfig{
+				{SourceLabels: []string{"missing"}, TargetLabel: "__address__"},
+			},
+			want: with(labels, map[string]string{"__address__": ""}),
+			keep: true,
+		},
+		"keep": {
+			configs: []*RelabelConfig{
+				{SourceLabels: []string{"__meta_kubernetes_pod_label_app"}, Regex: "gpu-.*", Action: RelabelKeep},
+			},
+			want: labels,
+			keep: true,
+		},
+		"keep without match": {
+			configs: []*RelabelConfig{
+				{SourceLabels: []string{"__meta_kubernetes_pod_label_app"}, Regex: "gpu", Action: RelabelKeep},
+			},
+			keep: false,
+		},
+		"drop": {
+			configs: []*RelabelConfig{
+				{SourceLabels: []string{"__meta_kubernetes_namespace"}, Regex: "gpu", Action: RelabelDrop},
+			},
+			keep: false,
+		},
+		"labelmap": {
+			configs: []*RelabelConfig{
+				{Regex: "__meta_kubernetes_pod_label_(.+)", Action: RelabelLabelMap},
+			},
+			want: with(labels, map[string]string{"app": "gpu-exporter", "team_example": "ml"}),
+			keep: true,
+		},
+		"labeldrop": {
+			configs: []*RelabelConfig{
+				{Regex: "__meta_kubernetes_pod_.*", Action: RelabelLabelDrop},
+			},
+			want: map[string]string{
+				"__address__":                 "10.0.0.1",
+				"__meta_kubernetes_namespace": "gpu",
+			},
+			keep: true,
+		},
+		"labelkeep": {
+			configs: []*RelabelConfig{
+				{Regex: "__address__", Action: RelabelLabelKeep},
+			},
+			want: map[string]string{"__address__": "10.0.0.1"},
+			keep: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, rc := range tt.configs {
+				if err := rc.compile(); err != nil {
+					t.Fatalf("compile() error = %v", err)
+				}
+			}
+
+			got, keep := relabel(labels, tt.configs)
+			if keep != tt.keep {
+				t.Fatalf("relabel() keep = %v, want %v", keep, tt.keep)
+			}
+			if keep && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("relabel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelabelConfig_compile(t *testing.T) {
+	tests := map[string]struct {
+		config  RelabelConfig
+		wantErr bool
+	}{
+		"defaults": {
+			config: RelabelConfig{TargetLabel: "namespace"},
+		},
+		"replace without target": {
+			config:  RelabelConfig{SourceLabels: []string{"a"}},
+			wantErr: true,
+		},
+		"keep without source": {
+			config:  RelabelConfig{Action: RelabelKeep},
+			wantErr: true,
+		},
+		"invalid regex": {
+			config:  RelabelConfig{TargetLabel: "a", Regex: "("},
+			wantErr: true,
+		},
+		"unknown action": {
+			config:  RelabelConfig{Action: "hashmod"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.config.compile(); (err != nil) != tt.wantErr {
+				t.Errorf("compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// with returns a copy of the labels with the given labels set, removing those which
+// are set to an empty value.
+func with(labels map[string]string, set map[string]string) map[string]string {
+	result := make(map[string]string)
+	for k, v := range labels {
+		result[k] = v
+	}
+	for k, v := range set {
+		if v == "" {
+			delete(result, k)
+		} else {
+			result[k] = v
+		}
+	}
+	return result
+}
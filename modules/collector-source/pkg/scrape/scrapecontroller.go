@@ -26,6 +26,7 @@ func NewScrapeController(
 	updater metric.Updater,
 	clusterCache clustercache.ClusterCache,
 	statSummaryClient nodestats.StatSummaryClient,
+	scrapeConfigs []*ScrapeConfig,
 ) *ScrapeController {
 
 	var scrapers []Scraper
@@ -44,6 +45,15 @@ func NewScrapeController(
 	dcgmScraper := newDCGMScrapper(clusterCache)
 	scrapers = append(scrapers, dcgmScraper)
 
+	for _, scrapeConfig := range scrapeConfigs {
+		configuredScraper, err := newConfiguredScraper(scrapeConfig, clusterCache)
+		if err != nil {
+			log.Errorf("scrapecontroller failed to create scraper: %s", err.Error())
+			continue
+		}
+		scrapers = append(scrapers, configuredScraper)
+	}
+
 	si, err := util.NewInterval(scrapeInterval)
 	if err != nil {
 		panic(fmt.Errorf("scrapecontroller failed to create scrape interval: %w", err))
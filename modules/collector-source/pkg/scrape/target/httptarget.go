@@ -0,0 +1,98 @@
+package target
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/opencost/opencost/modules/collector-source/pkg/scrape/parser"
+)
+
+// LabeledTarget is a ScrapeTarget with labels which are added to each of its scraped
+// metrics.
+type LabeledTarget interface {
+	ScrapeTarget
+	Labels() map[string]string
+}
+
+// HTTPAuth holds the credentials sent with each request of an HTTPTarget.
+type HTTPAuth struct {
+	// BearerToken is sent as the bearer token when set.
+	BearerToken string
+
+	// BearerTokenFile is read on each request, so that rotated tokens are picked up,
+	// and sent as the bearer token when set.
+	BearerTokenFile string
+}
+
+// HTTPTarget is a LabeledTarget which loads metrics from a URL using the given client,
+// failing on non-2xx responses.
+type HTTPTarget struct {
+	url    string
+	client *http.Client
+	auth   HTTPAuth
+	labels map[string]string
+}
+
+func NewHTTPTarget(url string, client *http.Client, auth HTTPAuth, labels map[string]string) *HTTPTarget {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTarget{
+		url:    url,
+		client: client,
+		auth:   auth,
+		labels: labels,
+	}
+}
+
+// URL returns the URL the target is loaded from.
+func (t *HTTPTarget) URL() string {
+	return t.url
+}
+
+func (t *HTTPTarget) Labels() map[string]string {
+	return t.labels
+}
+
+func (t *HTTPTarget) Load() (io.Reader, error) {
+	req, err := http.NewRequest(http.MethodGet, t.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", t.url, err)
+	}
+	req.Header.Set("Accept", parser.AcceptHeader)
+
+	token := t.auth.BearerToken
+	if t.auth.BearerTokenFile != "" {
+		b, err := os.ReadFile(t.auth.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bearer token file: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch URL %s: %s", t.url, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", t.url, err)
+	}
+	return &Content{
+		Reader:      bytes.NewReader(body),
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
@@ -8,6 +8,22 @@ type ScrapeTarget interface {
 	Load() (io.Reader, error)
 }
 
+// Content is returned by the Load of targets which negotiate the format of their data, holding
+// its content type so that it is parsed accordingly.
+type Content struct {
+	io.Reader
+	ContentType string
+}
+
+// ContentType returns the content type of the reader returned by a ScrapeTarget, or an empty
+// string if it is unknown.
+func ContentType(r io.Reader) string {
+	if c, ok := r.(*Content); ok {
+		return c.ContentType
+	}
+	return ""
+}
+
 type TargetProvider interface {
 	GetTargets() []ScrapeTarget
 }
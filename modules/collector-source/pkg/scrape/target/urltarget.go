@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/opencost/opencost/modules/collector-source/pkg/scrape/parser"
 )
 
 type UrlTarget struct {
@@ -17,10 +19,19 @@ func NewUrlTarget(url string) *UrlTarget {
 }
 
 func (t *UrlTarget) Load() (io.Reader, error) {
-	resp, err := http.Get(t.url)
+	req, err := http.NewRequest(http.MethodGet, t.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", t.url, err)
+	}
+	req.Header.Set("Accept", parser.AcceptHeader)
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch URL: %w", err)
 	}
 
-	return resp.Body, nil
+	return &Content{
+		Reader:      resp.Body,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
 }
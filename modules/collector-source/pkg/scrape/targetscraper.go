@@ -12,10 +12,11 @@ import (
 )
 
 type TargetScraper struct {
-	name           string // identifier for the scraper
-	targetProvider target.TargetProvider
-	metricNames    map[string]struct{} // filter for which metrics will be processed
-	includeMetrics bool                // toggle to make metrics an include or exclude list
+	name                 string // identifier for the scraper
+	targetProvider       target.TargetProvider
+	metricNames          map[string]struct{} // filter for which metrics will be processed
+	includeMetrics       bool                // toggle to make metrics an include or exclude list
+	metricRelabelConfigs []*RelabelConfig    // relabeling applied to each processed metric
 }
 
 func newTargetScrapper(name string, provider target.TargetProvider, metricNames []string, includeMetrics bool) *TargetScraper {
@@ -39,10 +40,10 @@ func (s *TargetScraper) Scrape() []metric.Update {
 
 	var scrapeFuncs []ScrapeFunc
 	for i := range targets {
-		target := targets[i]
+		scrapeTarget := targets[i]
 		fn := func() []metric.Update {
 			var scrapeResults []metric.Update
-			f, err := target.Load()
+			f, err := scrapeTarget.Load()
 			if err != nil {
 				errLock.Lock()
 				errors = append(errors, err)
@@ -51,7 +52,7 @@ func (s *TargetScraper) Scrape() []metric.Update {
 				log.Errorf("failed to scrape target: %s", err.Error())
 				return scrapeResults
 			}
-			results, err := parser.Parse(f)
+			results, err := parser.ParseContentType(f, target.ContentType(f))
 			if err != nil {
 				errLock.Lock()
 				errors = append(errors, err)
@@ -65,9 +66,13 @@ func (s *TargetScraper) Scrape() []metric.Update {
 				if _, ok := s.metricNames[result.Name]; ok != s.includeMetrics {
 					continue
 				}
+				name, labels, ok := s.relabel(scrapeTarget, result)
+				if !ok {
+					continue
+				}
 				scrapeResults = append(scrapeResults, metric.Update{
-					Name:   result.Name,
-					Labels: result.Labels,
+					Name:   name,
+					Labels: labels,
 					Value:  result.Value,
 				})
 			}
@@ -87,3 +92,39 @@ func (s *TargetScraper) Scrape() []metric.Update {
 
 	return updates
 }
+
+// relabel adds the labels of the target to the metric, then applies the metric relabel
+// configs, returning the resulting name and labels, or false if the metric was dropped.
+func (s *TargetScraper) relabel(scrapeTarget target.ScrapeTarget, result *parser.MetricRecord) (string, map[string]string, bool) {
+	labeled, isLabeled := scrapeTarget.(target.LabeledTarget)
+	if (!isLabeled || len(labeled.Labels()) == 0) && len(s.metricRelabelConfigs) == 0 {
+		return result.Name, result.Labels, true
+	}
+
+	labels := make(map[string]string, len(result.Labels))
+	for k, v := range result.Labels {
+		labels[k] = v
+	}
+	if isLabeled {
+		for k, v := range labeled.Labels() {
+			labels[k] = v
+		}
+	}
+
+	if len(s.metricRelabelConfigs) == 0 {
+		return result.Name, labels, true
+	}
+
+	labels[MetricNameLabel] = result.Name
+	labels, ok := relabel(labels, s.metricRelabelConfigs)
+	if !ok {
+		return "", nil, false
+	}
+
+	name := labels[MetricNameLabel]
+	delete(labels, MetricNameLabel)
+	if name == "" {
+		return "", nil, false
+	}
+	return name, labels, true
+}
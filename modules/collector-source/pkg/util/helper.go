@@ -1,7 +1,9 @@
 package util
 
 import (
+	"crypto/subtle"
 	"hash/fnv"
+	"net/http"
 	"strings"
 )
 
@@ -53,3 +55,10 @@ func ToMap(labels []string, values []string) map[string]string {
 func Ptr[T any](v T) *T {
 	return &v
 }
+
+// IsBearerTokenAuthorized returns true if the request carries the token as a bearer token in its
+// Authorization header. The token is compared in constant time.
+func IsBearerTokenAuthorized(r *http.Request, token string) bool {
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+}
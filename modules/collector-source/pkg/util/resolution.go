@@ -55,6 +55,11 @@ func (r *Resolution) Limit() time.Time {
 	return r.interval.Add(r.interval.Truncate(time.Now()), -(r.retention - 1))
 }
 
+// End returns the time that the interval containing the given time ends, and the next interval begins
+func (r *Resolution) End(t time.Time) time.Time {
+	return r.interval.Add(r.interval.Truncate(t), 1)
+}
+
 // Get returns the interval start time for the given time
 func (r *Resolution) Get(t time.Time) time.Time {
 	return r.interval.Truncate(t)
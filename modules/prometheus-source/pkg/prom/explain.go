@@ -0,0 +1,80 @@
+package prom
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/source"
+)
+
+// queryStats collects the statistics of a single query which are only known to the client that sends it, such as
+// the time the query spent in the queue of the RateLimitedPrometheusClient.
+type queryStats struct {
+	queueTime atomic.Int64
+}
+
+type queryStatsKey struct{}
+
+// withQueryStats returns a copy of the context which carries the given queryStats to the client
+func withQueryStats(ctx context.Context, stats *queryStats) context.Context {
+	return context.WithValue(ctx, queryStatsKey{}, stats)
+}
+
+// queryStatsFrom returns the queryStats carried by the context, or nil if there are none
+func queryStatsFrom(ctx context.Context) *queryStats {
+	stats, _ := ctx.Value(queryStatsKey{}).(*queryStats)
+	return stats
+}
+
+// explainQuery tracks a query made by a Context whose request context carries a source.Explain, so that the query
+// is recorded once its results are parsed.
+type explainQuery struct {
+	explain *source.Explain
+	stats   *queryStats
+	began   time.Time
+}
+
+// beginExplain returns a Context which sends its requests with a queryStats, and the explainQuery recording them, if
+// the request context of ctx carries a source.Explain. Otherwise, it returns ctx and nil.
+func (ctx *Context) beginExplain() (*Context, *explainQuery) {
+	explain := source.ExplainFromContext(ctx.requestCtx)
+	if explain == nil {
+		return ctx, nil
+	}
+
+	eq := &explainQuery{
+		explain: explain,
+		stats:   &queryStats{},
+		began:   time.Now(),
+	}
+
+	explainCtx := *ctx
+	explainCtx.requestCtx = withQueryStats(ctx.requestCtx, eq.stats)
+	return &explainCtx, eq
+}
+
+// record records the query in the source.Explain. start is nil for instant queries.
+func (eq *explainQuery) record(name, query string, start *time.Time, end time.Time, results *source.QueryResults) {
+	if eq == nil {
+		return
+	}
+
+	queueTime := time.Duration(eq.stats.queueTime.Load())
+
+	q := source.ExplainQuery{
+		Source:          source.ExplainSourcePrometheus,
+		Name:            name,
+		Query:           query,
+		Start:           start,
+		End:             end,
+		QueueTimeMs:     source.ExplainMs(queueTime),
+		ExecutionTimeMs: source.ExplainMs(time.Since(eq.began) - queueTime),
+		Series:          len(results.Results),
+	}
+	if results.Error != nil {
+		q.Error = results.Error.Error()
+	}
+
+	eq.explain.RecordQuery(q)
+}
@@ -0,0 +1,108 @@
+package prom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/source"
+)
+
+func TestRateLimitedQueueTimeRecorded(t *testing.T) {
+	t.Parallel()
+
+	promClient := newMockPromClientWith([]*ResponseAndBody{
+		newSuccessfulResponse(),
+		newSuccessfulResponse(),
+	})
+
+	client, err := NewRateLimitedClient(
+		"TestClient",
+		promClient,
+		1,
+		nil,
+		nil,
+		nil,
+		"",
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// occupy the single worker, so that the next request is queued
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, "", nil)
+		client.Do(context.Background(), req)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	stats := &queryStats{}
+	req, err := http.NewRequest(http.MethodPost, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.Do(withQueryStats(context.Background(), stats), req); err != nil {
+		t.Fatal(err)
+	}
+
+	if queueTime := time.Duration(stats.queueTime.Load()); queueTime < 150*time.Millisecond {
+		t.Fatalf("Expected the queue time of the queued request to be recorded. Got: %s", queueTime)
+	}
+}
+
+func TestContext_beginExplain(t *testing.T) {
+	ctx := NewNamedContext(nil, &OpenCostPrometheusConfig{}, AllocationContextName)
+
+	// without an Explain on the request context, nothing is recorded
+	queryCtx, eq := ctx.beginExplain()
+	if queryCtx != ctx || eq != nil {
+		t.Fatalf("Expected no explain query without an Explain on the request context")
+	}
+	eq.record(ctx.name, "up", nil, time.Now(), source.NewQueryResults("up"))
+
+	explain := source.NewExplain()
+	ctx.requestCtx = source.ContextWithExplain(context.Background(), explain)
+
+	queryCtx, eq = ctx.beginExplain()
+	if queryCtx == ctx || eq == nil {
+		t.Fatalf("Expected an explain query with an Explain on the request context")
+	}
+	if queryStatsFrom(queryCtx.requestContext()) != eq.stats {
+		t.Fatalf("Expected the query stats on the request context of the query")
+	}
+	if queryStatsFrom(ctx.requestContext()) != nil {
+		t.Fatalf("Expected the original context to be unchanged")
+	}
+
+	eq.stats.queueTime.Store(int64(time.Second))
+	results := source.NewQueryResults("up")
+	results.Results = []*source.QueryResult{{}, {}}
+	end := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	eq.record(ctx.name, "up", nil, end, results)
+
+	failed := source.NewQueryResults("down")
+	failed.Error = errors.New("boom")
+	eq.record(ctx.name, "down", nil, end, failed)
+
+	report := explain.Report()
+	if len(report.Queries) != 2 {
+		t.Fatalf("Expected 2 recorded queries. Got: %d", len(report.Queries))
+	}
+
+	q := report.Queries[0]
+	if q.Source != source.ExplainSourcePrometheus || q.Name != AllocationContextName || q.Query != "up" || !q.End.Equal(end) {
+		t.Errorf("Unexpected recorded query: %+v", q)
+	}
+	if q.Series != 2 {
+		t.Errorf("Expected 2 series. Got: %d", q.Series)
+	}
+	if q.QueueTimeMs != 1000 {
+		t.Errorf("Expected a queue time of 1000ms. Got: %f", q.QueueTimeMs)
+	}
+	if report.Queries[1].Error != "boom" {
+		t.Errorf("Expected the error of the failed query to be recorded. Got: %q", report.Queries[1].Error)
+	}
+}
@@ -1,7 +1,9 @@
 package prom
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/opencost/opencost/core/pkg/log"
@@ -37,6 +39,16 @@ func newPrometheusMetricsQuerier(
 	}
 }
 
+// WithContext returns a PrometheusMetricsQuerier which sends its queries with the given context, so that queries which
+// are queued or in flight are abandoned once the context is cancelled.
+func (pds *PrometheusMetricsQuerier) WithContext(ctx context.Context) source.MetricsQuerier {
+	return &PrometheusMetricsQuerier{
+		promConfig:   pds.promConfig,
+		promClient:   pds.promClient,
+		promContexts: pds.promContexts.WithContext(ctx),
+	}
+}
+
 func (pds *PrometheusMetricsQuerier) QueryPVPricePerGiBHour(start, end time.Time) *source.Future[source.PVPricePerGiBHourResult] {
 	const queryName = "QueryPVPricePerGiBHour"
 	const pvCostQuery = `avg(avg_over_time(oci_lens_cost_pv_hourly_cost{%s}[%s])) by (%s, persistentvolume, volumename, uid, provider_id)`
@@ -641,6 +653,24 @@ func (pds *PrometheusMetricsQuerier) QueryRAMUsageMax(start, end time.Time) *sou
 	return source.NewFuture(source.DecodeRAMUsageMaxResult, ctx.QueryAtTime(queryRAMUsageMax, end))
 }
 
+func (pds *PrometheusMetricsQuerier) QueryRAMUsageQuantile(start, end time.Time, quantile float64) *source.Future[source.RAMUsageQuantileResult] {
+	const queryName = "QueryRAMUsageQuantile"
+	const queryFmtRAMUsageQuantile = `max(quantile_over_time(%s, container_memory_working_set_bytes{container!="", container_name!="POD", container!="POD", %s}[%s])) by (container_name, container, pod_name, pod, namespace, node, instance, uid, %s)`
+
+	cfg := pds.promConfig
+
+	durStr := timeutil.DurationString(end.Sub(start))
+	if durStr == "" {
+		panic(fmt.Sprintf("failed to parse duration string passed to %s", queryName))
+	}
+
+	queryRAMUsageQuantile := fmt.Sprintf(queryFmtRAMUsageQuantile, formatQuantile(quantile), cfg.ClusterFilter, durStr, cfg.ClusterLabel)
+	log.Debugf(PrometheusMetricsQueryLogFormat, queryName, end.Unix(), queryRAMUsageQuantile)
+
+	ctx := pds.promContexts.NewNamedContext(AllocationContextName)
+	return source.NewFuture(source.DecodeRAMUsageQuantileResult, ctx.QueryAtTime(queryRAMUsageQuantile, end))
+}
+
 func (pds *PrometheusMetricsQuerier) QueryCPUCoresAllocated(start, end time.Time) *source.Future[source.CPUCoresAllocatedResult] {
 	const queryName = "QueryCPUCoresAllocated"
 	const queryFmtCPUCoresAllocated = `avg(avg_over_time(oci_lens_cost_container_cpu_allocation{container!="", container!="POD", node!="", %s}[%s])) by (container, pod, namespace, node, uid, %s)`
@@ -772,6 +802,44 @@ func (pds *PrometheusMetricsQuerier) QueryCPUUsageMax(start, end time.Time) *sou
 	return source.NewFuture(source.DecodeCPUUsageMaxResult, ctx.QueryAtTime(queryCPUUsageMaxSubquery, end))
 }
 
+func (pds *PrometheusMetricsQuerier) QueryCPUUsageQuantile(start, end time.Time, quantile float64) *source.Future[source.CPUUsageQuantileResult] {
+	const queryName = "QueryCPUUsageQuantile"
+	// As with QueryCPUUsageMax, the quantile is taken over the instant-by-instant
+	// irate of CPU usage, preferring the recording rule and falling back to the
+	// more expensive subquery when the recording rule data does not exist.
+	const queryFmtCPUUsageQuantileRecordingRule = `max(quantile_over_time(%s, kubecost_container_cpu_usage_irate{%s}[%s])) by (container_name, container, pod_name, pod, namespace, node, instance, uid, %s)`
+	const queryFmtCPUUsageQuantileSubquery = `max(quantile_over_time(%s, irate(container_cpu_usage_seconds_total{container!="POD", container!="", %s}[%dm])[%s:%dm])) by (container, pod_name, pod, namespace, node, instance, uid, %s)`
+
+	cfg := pds.promConfig
+	durStr := timeutil.DurationString(end.Sub(start))
+	if durStr == "" {
+		panic(fmt.Sprintf("failed to parse duration string passed to %s", queryName))
+	}
+
+	queryCPUUsageQuantileRecordingRule := fmt.Sprintf(queryFmtCPUUsageQuantileRecordingRule, formatQuantile(quantile), cfg.ClusterFilter, durStr, cfg.ClusterLabel)
+	log.Debugf(PrometheusMetricsQueryLogFormat, queryName, end.Unix(), queryCPUUsageQuantileRecordingRule)
+
+	ctx := pds.promContexts.NewNamedContext(AllocationContextName)
+	resCPUUsageQuantileRR := ctx.QueryAtTime(queryCPUUsageQuantileRecordingRule, end)
+	resCPUUsageQuantile, _ := resCPUUsageQuantileRR.Await()
+
+	if len(resCPUUsageQuantile) > 0 {
+		return source.NewFutureFrom(source.DecodeAll(resCPUUsageQuantile, source.DecodeCPUUsageQuantileResult))
+	}
+
+	minsPerResolution := cfg.DataResolutionMinutes
+
+	durStr = pds.durationStringFor(start, end, minsPerResolution, false)
+	if durStr == "" {
+		panic(fmt.Sprintf("failed to parse duration string passed to %s", queryName))
+	}
+
+	queryCPUUsageQuantileSubquery := fmt.Sprintf(queryFmtCPUUsageQuantileSubquery, formatQuantile(quantile), cfg.ClusterFilter, 2*minsPerResolution, durStr, minsPerResolution, cfg.ClusterLabel)
+	log.Debugf(PrometheusMetricsQueryLogFormat, queryName, end.Unix(), queryCPUUsageQuantileSubquery)
+
+	return source.NewFuture(source.DecodeCPUUsageQuantileResult, ctx.QueryAtTime(queryCPUUsageQuantileSubquery, end))
+}
+
 func (pds *PrometheusMetricsQuerier) QueryGPUsRequested(start, end time.Time) *source.Future[source.GPUsRequestedResult] {
 	const queryName = "QueryGPUsRequested"
 	const queryFmtGPUsRequested = `avg(avg_over_time(kube_pod_container_resource_requests{resource=~"nvidia_com_gpu|amd_com_gpu", container!="",container!="POD", node!="", %s}[%s])) by (container, pod, namespace, node, uid, %s)`
@@ -1846,3 +1914,8 @@ func (pds *PrometheusMetricsQuerier) durationStringFor(start, end time.Time, min
 
 	return timeutil.DurationString(dur)
 }
+
+// formatQuantile formats the quantile parameter of a quantile_over_time query.
+func formatQuantile(quantile float64) string {
+	return strconv.FormatFloat(quantile, 'f', -1, 64)
+}
@@ -126,12 +126,14 @@ func TestQueryLogs(t *testing.T) {
 		"QueryRAMLimits":                                func(s, e time.Time) { querier.QueryRAMLimits(s, e) },
 		"QueryRAMUsageAvg":                              func(s, e time.Time) { querier.QueryRAMUsageAvg(s, e) },
 		"QueryRAMUsageMax":                              func(s, e time.Time) { querier.QueryRAMUsageMax(s, e) },
+		"QueryRAMUsageQuantile":                         func(s, e time.Time) { querier.QueryRAMUsageQuantile(s, e, 0.95) },
 		"QueryNodeRAMPricePerGiBHr":                     func(s, e time.Time) { querier.QueryNodeRAMPricePerGiBHr(s, e) },
 		"QueryCPUCoresAllocated":                        func(s, e time.Time) { querier.QueryCPUCoresAllocated(s, e) },
 		"QueryCPURequests":                              func(s, e time.Time) { querier.QueryCPURequests(s, e) },
 		"QueryCPULimits":                                func(s, e time.Time) { querier.QueryCPULimits(s, e) },
 		"QueryCPUUsageAvg":                              func(s, e time.Time) { querier.QueryCPUUsageAvg(s, e) },
 		"QueryCPUUsageMax":                              func(s, e time.Time) { querier.QueryCPUUsageMax(s, e) },
+		"QueryCPUUsageQuantile":                         func(s, e time.Time) { querier.QueryCPUUsageQuantile(s, e, 0.95) },
 		"QueryNodeCPUPricePerHr":                        func(s, e time.Time) { querier.QueryNodeCPUPricePerHr(s, e) },
 		"QueryGPUsAllocated":                            func(s, e time.Time) { querier.QueryGPUsAllocated(s, e) },
 		"QueryGPUsRequested":                            func(s, e time.Time) { querier.QueryGPUsRequested(s, e) },
@@ -22,6 +22,8 @@ import (
 	golog "log"
 
 	prometheus "github.com/prometheus/client_golang/api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var UserAgent = fmt.Sprintf("Opencost/%s", version.Version)
@@ -266,6 +268,13 @@ func (rlpc *RateLimitedPrometheusClient) worker() {
 		ctx := we.ctx
 		req := we.req
 
+		// skip requests which were abandoned while queued, such as those of a client which disconnected, so
+		// that they do not hold up the requests behind them
+		if err := ctx.Err(); err != nil {
+			we.respChan <- &workResponse{err: err}
+			continue
+		}
+
 		// decorate the raw query parameters
 		if rlpc.decorator != nil {
 			req.URL.RawQuery = rlpc.decorator(req.URL.Path, req.URL.Query()).Encode()
@@ -273,6 +282,12 @@ func (rlpc *RateLimitedPrometheusClient) worker() {
 
 		// measure time in queue
 		timeInQueue := time.Since(we.start)
+		if stats := queryStatsFrom(ctx); stats != nil {
+			stats.queueTime.Store(int64(timeInQueue))
+		}
+		trace.SpanFromContext(ctx).AddEvent("dequeued", trace.WithAttributes(
+			attribute.Float64("prometheus.queue_time_ms", float64(timeInQueue)/float64(time.Millisecond)),
+		))
 
 		// Increment outbound counter
 		rlpc.outbound.Add(1)
@@ -297,6 +312,10 @@ func (rlpc *RateLimitedPrometheusClient) worker() {
 				retries--
 
 				status = append(status, &RateLimitResponseStatus{RetriesRemaining: retries, WaitTime: retryAfter})
+				trace.SpanFromContext(ctx).AddEvent("rate limited", trace.WithAttributes(
+					attribute.Int("prometheus.retries_remaining", retries),
+					attribute.Int64("prometheus.retry_after_ms", retryAfter.Milliseconds()),
+				))
 				log.DedupedInfof(50, "Rate Limited Prometheus Request. Waiting for: %d ms. Retries Remaining: %d", retryAfter.Milliseconds(), retries)
 
 				// To prevent total starvation of request threads, hard limit wait time to 10s. We also want quota limits/throttles
@@ -306,14 +325,21 @@ func (rlpc *RateLimitedPrometheusClient) worker() {
 					retryAfter = MaxRetryAfterDuration
 				}
 
-				// execute wait and retry
-				time.Sleep(retryAfter)
+				// execute wait and retry, unless the request is abandoned while waiting
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryAfter):
+				}
+				if ctx.Err() != nil {
+					err = ctx.Err()
+					break
+				}
 				res, body, err = rlpc.client.Do(ctx, req)
 			}
 
 			// if we've broken out of our retry loop and the resp is still rate limited,
 			// then let's generate a meaningful error to pass back
-			if retries == 0 && httputil.IsRateLimited(res, body) {
+			if ctx.Err() == nil && retries == 0 && httputil.IsRateLimited(res, body) {
 				err = &RateLimitedResponseError{RateLimitStatus: status}
 			}
 		}
@@ -331,16 +357,21 @@ func (rlpc *RateLimitedPrometheusClient) worker() {
 	}
 }
 
-// Rate limit and passthrough to prometheus client API
+// Rate limit and passthrough to prometheus client API. If the context is cancelled before the request completes, Do
+// returns the context's error, and the request is skipped if it has not yet left the queue.
 func (rlpc *RateLimitedPrometheusClient) Do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
 	if rlpc.headerXScopeOrgId != "" {
 		req.Header.Set(HeaderXScopeOrgId, rlpc.headerXScopeOrgId)
 	}
 
 	rlpc.auth.Apply(req)
 
-	respChan := make(chan *workResponse)
-	defer close(respChan)
+	// the channel is buffered so that the worker never blocks on a response which is no longer awaited
+	respChan := make(chan *workResponse, 1)
 
 	// request names are used as a debug utility to identify requests in queue
 	contextName := "<none>"
@@ -349,6 +380,8 @@ func (rlpc *RateLimitedPrometheusClient) Do(ctx context.Context, req *http.Reque
 	}
 	query, _ := httputil.GetQuery(req)
 
+	ctx, span := startRequestSpan(ctx, req, contextName)
+
 	rlpc.queue.Enqueue(&workRequest{
 		ctx:         ctx,
 		req:         req,
@@ -359,8 +392,14 @@ func (rlpc *RateLimitedPrometheusClient) Do(ctx context.Context, req *http.Reque
 		query:       query,
 	})
 
-	workRes := <-respChan
-	return workRes.res, workRes.body, workRes.err
+	select {
+	case workRes := <-respChan:
+		endRequestSpan(span, workRes.res, workRes.err)
+		return workRes.res, workRes.body, workRes.err
+	case <-ctx.Done():
+		endRequestSpan(span, nil, ctx.Err())
+		return nil, nil, ctx.Err()
+	}
 }
 
 //--------------------------------------------------------------------------
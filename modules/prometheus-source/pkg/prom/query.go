@@ -25,8 +25,9 @@ const (
 
 // ContextFactory is a factory for creating new Contexts for prometheus queries.
 type ContextFactory struct {
-	client prometheus.Client
-	config *OpenCostPrometheusConfig
+	client     prometheus.Client
+	config     *OpenCostPrometheusConfig
+	requestCtx context.Context
 }
 
 // NewContextFactory creates a new ContextFactory with the provided prometheus client.
@@ -37,14 +38,28 @@ func NewContextFactory(client prometheus.Client, promConfig *OpenCostPrometheusC
 	}
 }
 
+// WithContext returns a copy of the ContextFactory whose Contexts send their requests with the given context, so
+// that the requests are abandoned once it is cancelled.
+func (cf *ContextFactory) WithContext(requestCtx context.Context) *ContextFactory {
+	return &ContextFactory{
+		client:     cf.client,
+		config:     cf.config,
+		requestCtx: requestCtx,
+	}
+}
+
 // NewContext creates a new prometheus query context.
 func (cf *ContextFactory) NewContext() *Context {
-	return NewContext(cf.client, cf.config)
+	ctx := NewContext(cf.client, cf.config)
+	ctx.requestCtx = cf.requestCtx
+	return ctx
 }
 
 // NewContext creates a new named prometheus query context.
 func (cf *ContextFactory) NewNamedContext(name string) *Context {
-	return NewNamedContext(cf.client, cf.config, name)
+	ctx := NewNamedContext(cf.client, cf.config, name)
+	ctx.requestCtx = cf.requestCtx
+	return ctx
 }
 
 // Context wraps a Prometheus client and provides methods for querying and
@@ -54,6 +69,8 @@ type Context struct {
 	config         *OpenCostPrometheusConfig
 	name           string
 	errorCollector *source.QueryErrorCollector
+	// requestCtx is the context requests are sent with, if set
+	requestCtx context.Context
 }
 
 // NewContext creates a new Prometheus querying context from the given client
@@ -75,6 +92,14 @@ func NewNamedContext(client prometheus.Client, config *OpenCostPrometheusConfig,
 	return ctx
 }
 
+// requestContext returns the context which requests are sent with
+func (ctx *Context) requestContext() context.Context {
+	if ctx.requestCtx == nil {
+		return context.Background()
+	}
+	return ctx.requestCtx
+}
+
 // Warnings returns the warnings collected from the Context's ErrorCollector
 func (ctx *Context) Warnings() []*source.QueryWarning {
 	return ctx.errorCollector.Warnings()
@@ -196,7 +221,9 @@ func runQuery(query string, ctx *Context, resCh source.QueryResultsChan, t time.
 	defer errors.HandlePanic()
 	startQuery := time.Now()
 
-	raw, warnings, requestError := ctx.query(query, t)
+	tracedCtx, span := ctx.startQuerySpan("prometheus.query", query, nil, t)
+	queryCtx, eq := tracedCtx.beginExplain()
+	raw, warnings, requestError := queryCtx.query(query, t)
 
 	var parseError error
 
@@ -213,6 +240,8 @@ func runQuery(query string, ctx *Context, resCh source.QueryResultsChan, t time.
 
 	// report all warnings, request, and parse errors (nils will be ignored)
 	ctx.errorCollector.Report(query, warnings, requestError, parseError)
+	eq.record(ctx.name, query, nil, t, results)
+	endQuerySpan(span, results)
 
 	if profileLabel != "" {
 		log.Profile(startQuery, profileLabel)
@@ -235,7 +264,7 @@ func (ctx *Context) RawQuery(query string, t time.Time) ([]byte, error) {
 
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest(http.MethodPost, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx.requestContext(), http.MethodPost, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -249,7 +278,7 @@ func (ctx *Context) RawQuery(query string, t time.Time) ([]byte, error) {
 	// Note that the warnings return value from client.Do() is always nil using this
 	// version of the prometheus client library. We parse the warnings out of the response
 	// body after json decodidng completes.
-	resp, body, err := ctx.Client.Do(context.Background(), req)
+	resp, body, err := ctx.Client.Do(ctx.requestContext(), req)
 	if err != nil {
 		if resp == nil {
 			return nil, fmt.Errorf("query error: '%s' fetching query '%s'", err.Error(), query)
@@ -350,7 +379,9 @@ func runQueryRange(query string, start, end time.Time, step time.Duration, ctx *
 	defer errors.HandlePanic()
 	startQuery := time.Now()
 
-	raw, warnings, requestError := ctx.queryRange(query, start, end, step)
+	tracedCtx, span := ctx.startQuerySpan("prometheus.queryRange", query, &start, end)
+	queryCtx, eq := tracedCtx.beginExplain()
+	raw, warnings, requestError := queryCtx.queryRange(query, start, end, step)
 
 	var parseError error
 
@@ -367,6 +398,8 @@ func runQueryRange(query string, start, end time.Time, step time.Duration, ctx *
 
 	// report all warnings, request, and parse errors (nils will be ignored)
 	ctx.errorCollector.Report(query, warnings, requestError, parseError)
+	eq.record(ctx.name, query, &start, end, results)
+	endQuerySpan(span, results)
 
 	if profileLabel != "" {
 		log.Profile(startQuery, profileLabel)
@@ -385,7 +418,7 @@ func (ctx *Context) RawQueryRange(query string, start, end time.Time, step time.
 	q.Set("step", strconv.FormatFloat(step.Seconds(), 'f', 3, 64))
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest(http.MethodPost, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx.requestContext(), http.MethodPost, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -399,7 +432,7 @@ func (ctx *Context) RawQueryRange(query string, start, end time.Time, step time.
 	// Note that the warnings return value from client.Do() is always nil using this
 	// version of the prometheus client library. We parse the warnings out of the response
 	// body after json decodidng completes.
-	resp, body, err := ctx.Client.Do(context.Background(), req)
+	resp, body, err := ctx.Client.Do(ctx.requestContext(), req)
 	if err != nil {
 		if resp == nil {
 			return nil, fmt.Errorf("Error: %s, Body: %s Query: %s", err.Error(), body, query)
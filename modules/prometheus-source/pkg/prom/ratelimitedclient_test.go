@@ -389,3 +389,62 @@ func TestConcurrentRateLimiting(t *testing.T) {
 		t.Logf("%s\n", rateLimitErr.Error())
 	}
 }
+
+func TestRateLimitedCancelledWhileQueued(t *testing.T) {
+	t.Parallel()
+
+	promClient := newMockPromClientWith([]*ResponseAndBody{
+		newSuccessfulResponse(),
+		newSuccessfulResponse(),
+	})
+
+	client, err := NewRateLimitedClient(
+		"TestClient",
+		promClient,
+		1,
+		nil,
+		nil,
+		nil,
+		"",
+		"",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// occupy the single worker, so that the next request is queued
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req, _ := http.NewRequest(http.MethodPost, "", nil)
+		client.Do(context.Background(), req)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, _, err = client.Do(ctx, req)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v. Got: %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Fatalf("Expected the cancelled request to return before the queued request was executed. Took: %s", elapsed)
+	}
+
+	// the abandoned request is skipped by the worker, rather than sent to prometheus
+	<-done
+	time.Sleep(50 * time.Millisecond)
+	mock := promClient.(*MockPromClient)
+	mock.Lock()
+	defer mock.Unlock()
+	if mock.current != 1 {
+		t.Fatalf("Expected 1 request to be sent. Got: %d", mock.current)
+	}
+}
@@ -0,0 +1,78 @@
+package prom
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/source"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/opencost/opencost/modules/prometheus-source/pkg/prom"
+
+// startQuerySpan starts the span of a query made by the Context, and returns a copy of the Context which sends the
+// query as part of the span. start is nil for instant queries.
+func (ctx *Context) startQuerySpan(name, query string, start *time.Time, end time.Time) (*Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.String("prometheus.context", ctx.name),
+		attribute.String("db.query.text", query),
+		attribute.String("prometheus.end", end.UTC().Format(time.RFC3339)),
+	}
+	if start != nil {
+		attrs = append(attrs, attribute.String("prometheus.start", start.UTC().Format(time.RFC3339)))
+	}
+
+	spanCtx, span := otel.Tracer(tracerName).Start(ctx.requestContext(), name, trace.WithAttributes(attrs...))
+	if !span.IsRecording() {
+		return ctx, span
+	}
+
+	tracedCtx := *ctx
+	tracedCtx.requestCtx = spanCtx
+	return &tracedCtx, span
+}
+
+// endQuerySpan records the number of series returned by the query, or its error, and ends its span.
+func endQuerySpan(span trace.Span, results *source.QueryResults) {
+	if results.Error != nil {
+		span.RecordError(results.Error)
+		span.SetStatus(codes.Error, results.Error.Error())
+	} else {
+		span.SetAttributes(attribute.Int("prometheus.series", len(results.Results)))
+	}
+	span.End()
+}
+
+// startRequestSpan starts the span of a request made through the RateLimitedPrometheusClient, and injects the trace
+// context of the span into the headers of the request, so that traces continue into Prometheus and any proxy in front
+// of it.
+func startRequestSpan(ctx context.Context, req *http.Request, contextName string) (context.Context, trace.Span) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "RateLimitedPrometheusClient.Do",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("prometheus.context", contextName),
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	return ctx, span
+}
+
+// endRequestSpan records the status code of the response, or the error of the request, and ends its span.
+func endRequestSpan(span trace.Span, res *http.Response, err error) {
+	if res != nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
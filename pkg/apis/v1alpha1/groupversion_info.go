@@ -0,0 +1,29 @@
+// Package v1alpha1 contains the v1alpha1 OpenCost configuration resources.
+//
+// +kubebuilder:object:generate=true
+// +groupName=opencost.io
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group and version of the OpenCost configuration resources
+	GroupVersion = schema.GroupVersion{Group: "opencost.io", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the resources of this group version with a scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the resources of this group version to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func init() {
+	SchemeBuilder.Register(
+		&OpenCostPricing{}, &OpenCostPricingList{},
+		&CloudCostIntegration{}, &CloudCostIntegrationList{},
+		&CustomCostSource{}, &CustomCostSourceList{},
+	)
+}
@@ -0,0 +1,192 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Condition types reported on the status of each resource
+const (
+	// ConditionReady indicates whether the resource was valid and applied
+	ConditionReady = "Ready"
+	// ConditionActive indicates whether the billing integrations of a CloudCostIntegration are in use by the
+	// cloud cost pipeline
+	ConditionActive = "Active"
+)
+
+// Condition reasons
+const (
+	ReasonApplied        = "Applied"
+	ReasonInvalidConfig  = "InvalidConfig"
+	ReasonSecretNotFound = "SecretNotFound"
+	ReasonSuperseded     = "Superseded"
+	ReasonUpdateFailed   = "UpdateFailed"
+	ReasonActive         = "Active"
+	ReasonInactive       = "Inactive"
+	ReasonPending        = "Pending"
+)
+
+// SecretKeySelector selects a key of a Secret in the namespace of the resource which refers to it.
+type SecretKeySelector struct {
+	// Name of the Secret
+	Name string `json:"name"`
+	// Key of the Secret's data
+	Key string `json:"key"`
+}
+
+// OpenCostPricingSpec overrides the custom pricing of the cluster. Prices are hourly and unset prices are left
+// unchanged.
+type OpenCostPricingSpec struct {
+	Description  string `json:"description,omitempty"`
+	CurrencyCode string `json:"currencyCode,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	CPU string `json:"CPU,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	SpotCPU string `json:"spotCPU,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	RAM string `json:"RAM,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	SpotRAM string `json:"spotRAM,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	GPU string `json:"GPU,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	SpotGPU string `json:"spotGPU,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	Storage string `json:"storage,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	ZoneNetworkEgress string `json:"zoneNetworkEgress,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	RegionNetworkEgress string `json:"regionNetworkEgress,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	InternetNetworkEgress string `json:"internetNetworkEgress,omitempty"`
+
+	// Discount and NegotiatedDiscount are percentages, e.g. "30%"
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+%?$`
+	Discount string `json:"discount,omitempty"`
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+%?$`
+	NegotiatedDiscount string `json:"negotiatedDiscount,omitempty"`
+
+	// SharedOverhead is a monthly cost shared across all allocations
+	// +kubebuilder:validation:Pattern=`^[0-9]*\.?[0-9]+$`
+	SharedOverhead   string   `json:"sharedOverhead,omitempty"`
+	SharedNamespaces []string `json:"sharedNamespaces,omitempty"`
+}
+
+// OpenCostPricingStatus is the observed state of an OpenCostPricing.
+type OpenCostPricingStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	// AppliedFields are the custom pricing fields set from the spec, which are reset to their defaults when they
+	// are removed from it
+	AppliedFields []string `json:"appliedFields,omitempty"`
+}
+
+// OpenCostPricing configures the custom pricing of the cluster. If there are several, the oldest is applied and
+// the others are marked as superseded.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+type OpenCostPricing struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenCostPricingSpec   `json:"spec,omitempty"`
+	Status OpenCostPricingStatus `json:"status,omitempty"`
+}
+
+// OpenCostPricingList is a list of OpenCostPricing.
+//
+// +kubebuilder:object:root=true
+type OpenCostPricingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenCostPricing `json:"items"`
+}
+
+// CloudCostIntegrationSpec holds billing integrations in the format of the cloud-integration.json file. The
+// configuration is read from ConfigSecretRef if set, so that credentials need not be stored in the resource.
+type CloudCostIntegrationSpec struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config          runtime.RawExtension `json:"config,omitempty"`
+	ConfigSecretRef *SecretKeySelector   `json:"configSecretRef,omitempty"`
+}
+
+// IntegrationStatus is the status of a single billing integration of a CloudCostIntegration.
+type IntegrationStatus struct {
+	Key string `json:"key"`
+	// Type is the type of the integration, e.g. "athena" or "bigquery"
+	Type   string `json:"type"`
+	Active bool   `json:"active"`
+}
+
+// CloudCostIntegrationStatus is the observed state of a CloudCostIntegration.
+type CloudCostIntegrationStatus struct {
+	ObservedGeneration int64               `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition  `json:"conditions,omitempty"`
+	Integrations       []IntegrationStatus `json:"integrations,omitempty"`
+}
+
+// CloudCostIntegration configures billing integrations of the cloud cost pipeline.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Active",type=string,JSONPath=`.status.conditions[?(@.type=="Active")].status`
+type CloudCostIntegration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudCostIntegrationSpec   `json:"spec,omitempty"`
+	Status CloudCostIntegrationStatus `json:"status,omitempty"`
+}
+
+// CloudCostIntegrationList is a list of CloudCostIntegration.
+//
+// +kubebuilder:object:root=true
+type CloudCostIntegrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudCostIntegration `json:"items"`
+}
+
+// CustomCostSourceSpec configures a custom cost plugin. The configuration is the content of the plugin's
+// <plugin>_config.json file, read from ConfigSecretRef if set.
+type CustomCostSourceSpec struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9-]+$`
+	Plugin string `json:"plugin"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config          runtime.RawExtension `json:"config,omitempty"`
+	ConfigSecretRef *SecretKeySelector   `json:"configSecretRef,omitempty"`
+}
+
+// CustomCostSourceStatus is the observed state of a CustomCostSource.
+type CustomCostSourceStatus struct {
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// CustomCostSource configures a custom cost plugin. The custom cost pipeline reloads its plugins whenever a
+// CustomCostSource is applied, changed or deleted.
+//
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Plugin",type=string,JSONPath=`.spec.plugin`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+type CustomCostSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CustomCostSourceSpec   `json:"spec,omitempty"`
+	Status CustomCostSourceStatus `json:"status,omitempty"`
+}
+
+// CustomCostSourceList is a list of CustomCostSource.
+//
+// +kubebuilder:object:root=true
+type CustomCostSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CustomCostSource `json:"items"`
+}
@@ -76,9 +76,8 @@ func (c *Configurations) UnmarshalJSON(bytes []byte) error {
 	// It also coincidentally works if you mix-and-match both the old format and the new
 	// format.
 	// Create inline type to gain access to default Unmarshalling
-	type ConfUnmarshaller *Configurations
-	var conf ConfUnmarshaller = c
-	err := json.Unmarshal(bytes, conf)
+	type ConfUnmarshaller Configurations
+	err := json.Unmarshal(bytes, (*ConfUnmarshaller)(c))
 	// If unmarshal is successful, return
 	if err == nil {
 		return nil
@@ -157,6 +156,11 @@ func (c *Configurations) Insert(keyedConfig cloud.Config) error {
 			c.OCI = &OCIConfigs{}
 		}
 		c.OCI.UsageAPI = append(c.OCI.UsageAPI, keyedConfig.(*oracle.UsageApiConfiguration))
+	case *oracle.CostReportConfiguration:
+		if c.OCI == nil {
+			c.OCI = &OCIConfigs{}
+		}
+		c.OCI.CostReport = append(c.OCI.CostReport, keyedConfig.(*oracle.CostReportConfiguration))
 	default:
 		return fmt.Errorf("Configurations: Insert: failed to insert config of type: %T", keyedConfig)
 	}
@@ -197,6 +201,10 @@ func (c *Configurations) ToSlice() []cloud.KeyedConfig {
 		for _, usageConfig := range c.OCI.UsageAPI {
 			keyedConfigs = append(keyedConfigs, usageConfig)
 		}
+
+		for _, costReportConfig := range c.OCI.CostReport {
+			keyedConfigs = append(keyedConfigs, costReportConfig)
+		}
 	}
 
 	return keyedConfigs
@@ -316,7 +324,8 @@ func (ac *AlibabaConfigs) Equals(that *AlibabaConfigs) bool {
 }
 
 type OCIConfigs struct {
-	UsageAPI []*oracle.UsageApiConfiguration `json:"usageApi,omitempty"`
+	UsageAPI   []*oracle.UsageApiConfiguration   `json:"usageApi,omitempty"`
+	CostReport []*oracle.CostReportConfiguration `json:"costReport,omitempty"`
 }
 
 func (oc *OCIConfigs) Equals(that *OCIConfigs) bool {
@@ -336,6 +345,16 @@ func (oc *OCIConfigs) Equals(that *OCIConfigs) bool {
 			return false
 		}
 	}
+	// Check Cost Report
+	if len(oc.CostReport) != len(that.CostReport) {
+		return false
+	}
+	for i, thisCostReport := range oc.CostReport {
+		thatCostReport := that.CostReport[i]
+		if !thisCostReport.Equals(thatCostReport) {
+			return false
+		}
+	}
 
 	return true
 }
@@ -290,3 +290,26 @@ func TestConfigurations_UnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+// TestConfigurations_UnmarshalJSON_Nested ensures that UnmarshalJSON falls back to the default unmarshalling of
+// Configurations, rather than calling itself, including when Configurations is unmarshalled as a field of another
+// type
+func TestConfigurations_UnmarshalJSON_Nested(t *testing.T) {
+	type wrapper struct {
+		Configurations *Configurations `json:"configurations"`
+	}
+
+	b, err := json.Marshal(wrapper{Configurations: azureConfiguration})
+	if err != nil {
+		t.Fatalf("failed to marshal input")
+	}
+
+	actual := wrapper{}
+	err = json.Unmarshal(b, &actual)
+	if err != nil {
+		t.Fatalf("Unmarshal failed with error %s", err.Error())
+	}
+	if !azureConfiguration.Equals(actual.Configurations) {
+		t.Fatalf("actual Configuration did not match expected")
+	}
+}
@@ -418,6 +418,19 @@ func (c *Controller) RegisterObserver(obs Observer) {
 	c.observers = append(c.observers, obs)
 }
 
+// RegisterWatcher adds a watcher for the given source, replacing any existing watcher for that source, and pulls
+// its configs immediately
+func (c *Controller) RegisterWatcher(source ConfigSource, watcher cloud.KeyedConfigWatcher) {
+	c.lock.Lock()
+	if c.watchers == nil {
+		c.watchers = map[ConfigSource]cloud.KeyedConfigWatcher{}
+	}
+	c.watchers[source] = watcher
+	c.lock.Unlock()
+
+	c.pullWatchers()
+}
+
 func (c *Controller) GetStatus() []Status {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
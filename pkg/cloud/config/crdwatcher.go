@@ -0,0 +1,54 @@
+package config
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/opencost/opencost/pkg/cloud"
+)
+
+// CRDWatcher holds the configs of CloudCostIntegration resources. Unlike the file based watchers, its configs are
+// pushed to it by the CRD reconciler, keyed on the namespaced name of the resource which defined them.
+type CRDWatcher struct {
+	lock    sync.RWMutex
+	configs map[string][]cloud.KeyedConfig
+}
+
+// NewCRDWatcher creates an empty CRDWatcher
+func NewCRDWatcher() *CRDWatcher {
+	return &CRDWatcher{
+		configs: map[string][]cloud.KeyedConfig{},
+	}
+}
+
+// SetConfigs replaces the configs defined by the named resource
+func (cw *CRDWatcher) SetConfigs(name string, configs []cloud.KeyedConfig) {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+	cw.configs[name] = configs
+}
+
+// DeleteConfigs removes the configs defined by the named resource
+func (cw *CRDWatcher) DeleteConfigs(name string) {
+	cw.lock.Lock()
+	defer cw.lock.Unlock()
+	delete(cw.configs, name)
+}
+
+// GetConfigs returns the configs of all resources, ordered by resource name
+func (cw *CRDWatcher) GetConfigs() []cloud.KeyedConfig {
+	cw.lock.RLock()
+	defer cw.lock.RUnlock()
+
+	names := make([]string, 0, len(cw.configs))
+	for name := range cw.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var configs []cloud.KeyedConfig
+	for _, name := range names {
+		configs = append(configs, cw.configs[name]...)
+	}
+	return configs
+}
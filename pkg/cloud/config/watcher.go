@@ -319,6 +319,7 @@ const (
 	MultiCloudSource
 	ConfigFileSource
 	HelmSource
+	CRDSource
 )
 
 func GetConfigSource(str string) ConfigSource {
@@ -331,6 +332,8 @@ func GetConfigSource(str string) ConfigSource {
 		return HelmSource
 	case "multicloud":
 		return MultiCloudSource
+	case "crd":
+		return CRDSource
 	default:
 		return UnknownSource
 	}
@@ -346,6 +349,8 @@ func (cs ConfigSource) String() string {
 		return "helm"
 	case MultiCloudSource:
 		return "multicloud"
+	case CRDSource:
+		return "crd"
 	case UnknownSource:
 		return "unknown"
 	default:
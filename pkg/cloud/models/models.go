@@ -133,6 +133,20 @@ type CustomPricing struct {
 	SpotRAM string `json:"spotRAM"`
 	GPU     string `json:"GPU"`
 	SpotGPU string `json:"spotGPU"`
+	// FargateCPU and FargateRAM are string-encoded floats describing cost per
+	// core-hour of CPU and per GiB-hour of RAM requests of EKS Fargate pods.
+	FargateCPU string `json:"fargateCPU,omitempty"`
+	FargateRAM string `json:"fargateRAM,omitempty"`
+	// AutopilotCPU and AutopilotRAM are string-encoded floats describing cost
+	// per core-hour of CPU and per GiB-hour of RAM requests of GKE Autopilot
+	// pods.
+	AutopilotCPU string `json:"autopilotCPU,omitempty"`
+	AutopilotRAM string `json:"autopilotRAM,omitempty"`
+	// ACICPU and ACIRAM are string-encoded floats describing cost per
+	// core-hour of CPU and per GiB-hour of RAM requests of Azure Container
+	// Instances pods.
+	ACICPU string `json:"aciCPU,omitempty"`
+	ACIRAM string `json:"aciRAM,omitempty"`
 	// Storage is a string-encoded float describing cost per GB-hour of storage
 	// (e.g. PV, disk) resources.
 	Storage                      string `json:"storage"`
@@ -0,0 +1,72 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/oracle/oci-go-sdk/v65/containerengine"
+)
+
+// clusterTypeTTL is how long a detected OKE cluster type is cached for. Basic
+// clusters can be upgraded to enhanced clusters, so the type is periodically
+// refreshed.
+const clusterTypeTTL = time.Hour
+
+// getClusterType returns the OKE cluster type, BASIC_CLUSTER or ENHANCED_CLUSTER,
+// by looking up the cluster of a node pool backing one of the cluster nodes.
+// Clusters are assumed to be enhanced when the type cannot be determined, as
+// virtual nodes and other enhanced features are not available to basic clusters.
+func (o *Oracle) getClusterType() string {
+	o.instanceLock.Lock()
+	if o.clusterType != "" && time.Since(o.clusterTypeRefreshed) < clusterTypeTTL {
+		defer o.instanceLock.Unlock()
+		return o.clusterType
+	}
+	o.instanceLock.Unlock()
+
+	clusterType, err := o.lookupClusterType(context.TODO())
+	if err != nil {
+		log.DedupedWarningf(5, "Oracle: unable to determine OKE cluster type, assuming %s: %s", containerengine.ClusterTypeEnhancedCluster, err)
+		clusterType = string(containerengine.ClusterTypeEnhancedCluster)
+	}
+
+	o.instanceLock.Lock()
+	defer o.instanceLock.Unlock()
+	o.clusterType = clusterType
+	o.clusterTypeRefreshed = time.Now()
+	return clusterType
+}
+
+func (o *Oracle) lookupClusterType(ctx context.Context) (string, error) {
+	var nodePoolID, virtualNodePoolID string
+	for _, node := range o.Clientset.GetAllNodes() {
+		if _, ok := node.Labels[virtualNodeLabel]; ok {
+			// Virtual nodes are only supported by enhanced clusters.
+			return string(containerengine.ClusterTypeEnhancedCluster), nil
+		}
+		if id, ok := node.Annotations[virtualPoolIdAnnotation]; ok && virtualNodePoolID == "" {
+			virtualNodePoolID = id
+		}
+		if id, ok := node.Annotations[nodePoolIdAnnotation]; ok && nodePoolID == "" {
+			nodePoolID = id
+		}
+	}
+	if virtualNodePoolID != "" {
+		return string(containerengine.ClusterTypeEnhancedCluster), nil
+	}
+	if nodePoolID == "" {
+		return "", fmt.Errorf("no nodes are annotated with %s", nodePoolIdAnnotation)
+	}
+
+	client, err := o.getResourceClient()
+	if err != nil {
+		return "", err
+	}
+	clusterID, err := client.GetNodePoolClusterID(ctx, nodePoolID)
+	if err != nil {
+		return "", err
+	}
+	return client.GetClusterType(ctx, clusterID)
+}
@@ -0,0 +1,67 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetClusterType(t *testing.T) {
+	client := &fakeResourceClient{
+		nodePoolClusters: map[string]string{
+			"ocid1.nodepool.basic":    "ocid1.cluster.basic",
+			"ocid1.nodepool.enhanced": "ocid1.cluster.enhanced",
+		},
+		clusterTypes: map[string]string{
+			"ocid1.cluster.basic":    "BASIC_CLUSTER",
+			"ocid1.cluster.enhanced": "ENHANCED_CLUSTER",
+		},
+	}
+	nodePoolNode := func(nodePoolID string) *clustercache.Node {
+		return &clustercache.Node{
+			Annotations: map[string]string{nodePoolIdAnnotation: nodePoolID},
+		}
+	}
+
+	testCases := map[string]struct {
+		nodes    []*clustercache.Node
+		expected string
+		price    float64
+	}{
+		"basic": {
+			nodes:    []*clustercache.Node{nodePoolNode("ocid1.nodepool.basic")},
+			expected: "BASIC_CLUSTER",
+			price:    0.0,
+		},
+		"enhanced": {
+			nodes:    []*clustercache.Node{nodePoolNode("ocid1.nodepool.enhanced")},
+			expected: "ENHANCED_CLUSTER",
+			price:    0.1,
+		},
+		"virtual nodes": {
+			nodes: []*clustercache.Node{
+				nodePoolNode("ocid1.nodepool.basic"),
+				{Labels: map[string]string{virtualNodeLabel: ""}},
+			},
+			expected: "ENHANCED_CLUSTER",
+			price:    0.1,
+		},
+		"unknown node pool": {
+			nodes:    []*clustercache.Node{nodePoolNode("ocid1.nodepool.unknown")},
+			expected: "ENHANCED_CLUSTER",
+			price:    0.1,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			o := testResourceOracle(client, testCase.nodes...)
+			assert.Equal(t, testCase.expected, o.getClusterType())
+
+			platform, price, err := o.ClusterManagementPricing()
+			assert.NoError(t, err)
+			assert.Equal(t, managementPlatformOKE, platform)
+			assert.Equal(t, testCase.price, price)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package oracle
+
+import (
+	"fmt"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/util/json"
+	"github.com/opencost/opencost/pkg/cloud"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+// Oracle writes the cost reports of each tenancy to a bucket named after the tenancy, in its own "bling" namespace
+const (
+	DefaultCostReportNamespace = "bling"
+	DefaultCostReportPrefix    = "reports/cost-csv"
+)
+
+// CostReportConfiguration configures the ingestion of the cost report CSV files which Oracle writes to the
+// reporting bucket of a tenancy. Namespace and Bucket default to the Oracle managed reporting bucket, but can be
+// set to read reports which have been copied to a bucket of the tenancy.
+type CostReportConfiguration struct {
+	TenancyID  string     `json:"tenancyID"`
+	Region     string     `json:"region"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Bucket     string     `json:"bucket,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Authorizer Authorizer `json:"authorizer"`
+}
+
+func (crc *CostReportConfiguration) Validate() error {
+	// Validate Authorizer
+	if crc.Authorizer == nil {
+		return fmt.Errorf("CostReportConfiguration: missing Authorizer")
+	}
+
+	err := crc.Authorizer.Validate()
+	if err != nil {
+		return fmt.Errorf("CostReportConfiguration: %s", err)
+	}
+
+	// Validate base properties
+	if crc.TenancyID == "" {
+		return fmt.Errorf("CostReportConfiguration: missing tenancyID")
+	}
+
+	if crc.Region == "" {
+		return fmt.Errorf("CostReportConfiguration: missing region")
+	}
+
+	return nil
+}
+
+func (crc *CostReportConfiguration) Equals(config cloud.Config) bool {
+	if config == nil {
+		return false
+	}
+	thatConfig, ok := config.(*CostReportConfiguration)
+	if !ok {
+		return false
+	}
+
+	if crc.Authorizer != nil {
+		if !crc.Authorizer.Equals(thatConfig.Authorizer) {
+			return false
+		}
+	} else {
+		if thatConfig.Authorizer != nil {
+			return false
+		}
+	}
+
+	return crc.TenancyID == thatConfig.TenancyID &&
+		crc.Region == thatConfig.Region &&
+		crc.Namespace == thatConfig.Namespace &&
+		crc.Bucket == thatConfig.Bucket &&
+		crc.Prefix == thatConfig.Prefix
+}
+
+func (crc *CostReportConfiguration) Sanitize() cloud.Config {
+	return &CostReportConfiguration{
+		TenancyID:  crc.TenancyID,
+		Region:     crc.Region,
+		Namespace:  crc.Namespace,
+		Bucket:     crc.Bucket,
+		Prefix:     crc.Prefix,
+		Authorizer: crc.Authorizer.Sanitize().(Authorizer),
+	}
+}
+
+func (crc *CostReportConfiguration) Key() string {
+	return fmt.Sprintf("%s/%s", crc.TenancyID, crc.GetBucket())
+}
+
+func (crc *CostReportConfiguration) Provider() string {
+	return opencost.OracleProvider
+}
+
+// GetNamespace returns the object storage namespace of the reporting bucket
+func (crc *CostReportConfiguration) GetNamespace() string {
+	if crc.Namespace == "" {
+		return DefaultCostReportNamespace
+	}
+	return crc.Namespace
+}
+
+// GetBucket returns the name of the reporting bucket
+func (crc *CostReportConfiguration) GetBucket() string {
+	if crc.Bucket == "" {
+		return crc.TenancyID
+	}
+	return crc.Bucket
+}
+
+// GetPrefix returns the prefix of the cost report objects
+func (crc *CostReportConfiguration) GetPrefix() string {
+	if crc.Prefix == "" {
+		return DefaultCostReportPrefix
+	}
+	return crc.Prefix
+}
+
+func (crc *CostReportConfiguration) GetObjectStorageClient() (*objectstorage.ObjectStorageClient, error) {
+	configProvider, err := crc.Authorizer.CreateOCIConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oci config: %s", err.Error())
+	}
+	client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create object storage client: %s", err.Error())
+	}
+	client.SetRegion(crc.Region)
+	return &client, nil
+}
+
+func (crc *CostReportConfiguration) UnmarshalJSON(b []byte) error {
+	var f interface{}
+	err := json.Unmarshal(b, &f)
+	if err != nil {
+		return err
+	}
+
+	fmap := f.(map[string]interface{})
+
+	tenancyId, err := cloud.GetInterfaceValue[string](fmap, "tenancyID")
+	if err != nil {
+		return fmt.Errorf("CostReportConfiguration: UnmarshalJSON: %w", err)
+	}
+	crc.TenancyID = tenancyId
+
+	region, err := cloud.GetInterfaceValue[string](fmap, "region")
+	if err != nil {
+		return fmt.Errorf("CostReportConfiguration: UnmarshalJSON: %w", err)
+	}
+	crc.Region = region
+
+	// Optional properties
+	for property, value := range map[string]*string{"namespace": &crc.Namespace, "bucket": &crc.Bucket, "prefix": &crc.Prefix} {
+		if _, ok := fmap[property]; !ok {
+			continue
+		}
+		*value, err = cloud.GetInterfaceValue[string](fmap, property)
+		if err != nil {
+			return fmt.Errorf("CostReportConfiguration: UnmarshalJSON: %w", err)
+		}
+	}
+
+	authAny, ok := fmap["authorizer"]
+	if !ok {
+		return fmt.Errorf("CostReportConfiguration: UnmarshalJSON: missing authorizer")
+	}
+	authorizer, err := cloud.AuthorizerFromInterface(authAny, SelectAuthorizerByType)
+	if err != nil {
+		return fmt.Errorf("CostReportConfiguration: UnmarshalJSON: %w", err)
+	}
+	crc.Authorizer = authorizer
+
+	return nil
+}
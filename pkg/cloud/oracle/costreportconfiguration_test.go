@@ -0,0 +1,108 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/opencost/opencost/core/pkg/util/json"
+)
+
+func TestCostReportConfiguration_Validate(t *testing.T) {
+	authorizer := &RawConfigProvider{
+		TenancyID:   "tenancyID",
+		UserID:      "userID",
+		Region:      "region",
+		Fingerprint: "fingerprint",
+		PrivateKey:  "key",
+	}
+
+	testCases := map[string]struct {
+		config   CostReportConfiguration
+		expected string
+	}{
+		"valid config": {
+			config: CostReportConfiguration{TenancyID: "tenancyID", Region: "region", Authorizer: authorizer},
+		},
+		"missing authorizer": {
+			config:   CostReportConfiguration{TenancyID: "tenancyID", Region: "region"},
+			expected: "CostReportConfiguration: missing Authorizer",
+		},
+		"missing tenancyID": {
+			config:   CostReportConfiguration{Region: "region", Authorizer: authorizer},
+			expected: "CostReportConfiguration: missing tenancyID",
+		},
+		"missing region": {
+			config:   CostReportConfiguration{TenancyID: "tenancyID", Authorizer: authorizer},
+			expected: "CostReportConfiguration: missing region",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := testCase.config.Validate()
+			actual := ""
+			if err != nil {
+				actual = err.Error()
+			}
+			if actual != testCase.expected {
+				t.Errorf("expected error '%s', got '%s'", testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCostReportConfiguration_Defaults(t *testing.T) {
+	crc := CostReportConfiguration{TenancyID: "ocid1.tenancy.oc1..aaa"}
+	if crc.GetNamespace() != DefaultCostReportNamespace || crc.GetBucket() != crc.TenancyID || crc.GetPrefix() != DefaultCostReportPrefix {
+		t.Errorf("unexpected defaults: %s, %s, %s", crc.GetNamespace(), crc.GetBucket(), crc.GetPrefix())
+	}
+
+	crc.Namespace = "namespace"
+	crc.Bucket = "reports"
+	crc.Prefix = "oci"
+	if crc.GetNamespace() != "namespace" || crc.GetBucket() != "reports" || crc.GetPrefix() != "oci" {
+		t.Errorf("unexpected overrides: %s, %s, %s", crc.GetNamespace(), crc.GetBucket(), crc.GetPrefix())
+	}
+	if crc.Key() != "ocid1.tenancy.oc1..aaa/reports" {
+		t.Errorf("unexpected key: %s", crc.Key())
+	}
+}
+
+func TestCostReportConfiguration_JSON(t *testing.T) {
+	testCases := map[string]CostReportConfiguration{
+		"Nil Authorizer": {
+			TenancyID: "tenancyID",
+			Region:    "region",
+		},
+		"Custom Bucket": {
+			TenancyID: "tenancyID",
+			Region:    "region",
+			Namespace: "namespace",
+			Bucket:    "bucket",
+			Prefix:    "prefix",
+			Authorizer: &RawConfigProvider{
+				TenancyID:   "tenancyID",
+				UserID:      "userID",
+				Region:      "region2",
+				Fingerprint: "fingerprint",
+				PrivateKey:  "key",
+			},
+		},
+	}
+
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			configJSON, err := json.Marshal(config)
+			if err != nil {
+				t.Fatalf("failed to marshal configuration: %s", err.Error())
+			}
+			unmarshalledConfig := &CostReportConfiguration{}
+			err = json.Unmarshal(configJSON, unmarshalledConfig)
+			if err != nil {
+				t.Fatalf("failed to unmarshal configuration: %s", err.Error())
+			}
+			if !config.Equals(unmarshalledConfig) {
+				t.Error("config does not equal unmarshalled config")
+			}
+		})
+	}
+}
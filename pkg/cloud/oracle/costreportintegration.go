@@ -0,0 +1,453 @@
+package oracle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/storage"
+	"github.com/opencost/opencost/pkg/cloud"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/objectstorage"
+)
+
+// Columns of the cost report CSV files
+const (
+	costReportReferenceNo        = "lineItem/referenceNo"
+	costReportTenantID           = "lineItem/tenantId"
+	costReportIntervalStart      = "lineItem/intervalUsageStart"
+	costReportIntervalEnd        = "lineItem/intervalUsageEnd"
+	costReportIsCorrection       = "lineItem/isCorrection"
+	costReportBackReferenceNo    = "lineItem/backreferenceNo"
+	costReportService            = "product/service"
+	costReportCompartmentID      = "product/compartmentId"
+	costReportCompartmentName    = "product/compartmentName"
+	costReportRegion             = "product/region"
+	costReportAvailabilityDomain = "product/availabilityDomain"
+	costReportResourceID         = "product/resourceId"
+	costReportSubscriptionID     = "cost/subscriptionId"
+	costReportBilledQuantity     = "usage/billedQuantity"
+	costReportUnitPrice          = "cost/unitPrice"
+	costReportMyCost             = "cost/myCost"
+	costReportAttributedCost     = "cost/attributedCost"
+	costReportTagPrefix          = "tags/"
+)
+
+// costReportLookahead is how long after the end of a window cost reports are read for line items in the window, as
+// reports are written some time after the usage they contain, and may contain corrections of earlier line items.
+const costReportLookahead = 72 * time.Hour
+
+// costReportTimeLayouts are the formats of the interval timestamps of line items
+var costReportTimeLayouts = []string{"2006-01-02T15:04Z", time.RFC3339, "2006-01-02T15:04:05Z"}
+
+// ReportStore is the subset of storage.Storage used to read cost reports, so that reports can be read from a
+// storage.Storage in place of object storage.
+type ReportStore interface {
+	List(path string) ([]*storage.StorageInfo, error)
+	Read(path string) ([]byte, error)
+}
+
+var _ ReportStore = storage.Storage(nil)
+
+// CostReportIntegration ingests the line items of the cost reports of a tenancy, which carry the resource,
+// compartment and tags of each line item.
+//
+// Costs are mapped as follows:
+//   - NetCost and InvoicedCost are the line item's cost, after negotiated discounts
+//   - ListCost is the billed quantity multiplied by the unit price, which is the cost before any credits or
+//     adjustments included in the line item's cost
+//   - AmortizedNetCost and AmortizedCost are the attributed cost, which spreads commitments over the resources that
+//     consume them, if the report has the column, or otherwise the line item's cost
+type CostReportIntegration struct {
+	CostReportConfiguration
+	ConnectionStatus cloud.ConnectionStatus
+	// Store overrides the object storage bucket from which reports are read
+	Store ReportStore `json:"-"`
+
+	// reports caches the line items of the reports read by the last call of GetCloudCost, keyed on file name, so
+	// that reports are only read and parsed again when they change
+	reportsLock sync.Mutex
+	reports     map[string]*costReport
+}
+
+// costReport holds the parsed line items of a cost report file
+type costReport struct {
+	modTime time.Time
+	size    int64
+	items   []*costReportLineItem
+}
+
+func (cri *CostReportIntegration) GetCloudCost(start time.Time, end time.Time) (*opencost.CloudCostSetRange, error) {
+	store := cri.Store
+	if store == nil {
+		client, err := cri.GetObjectStorageClient()
+		if err != nil {
+			cri.ConnectionStatus = cloud.FailedConnection
+			return nil, fmt.Errorf("getting oracle object storage client: %s", err.Error())
+		}
+		store = &objectStorageReportStore{
+			client:    client,
+			namespace: cri.GetNamespace(),
+			bucket:    cri.GetBucket(),
+		}
+	}
+
+	ccsr, err := opencost.NewCloudCostSetRange(start, end, opencost.AccumulateOptionDay, cri.Key())
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := store.List(cri.GetPrefix())
+	if err != nil {
+		cri.ConnectionStatus = cloud.FailedConnection
+		return nil, fmt.Errorf("listing cost reports: %w", err)
+	}
+
+	cri.reportsLock.Lock()
+	defer cri.reportsLock.Unlock()
+
+	// Line items keyed on reference number, so that corrections replace the line items they reference
+	lineItems := map[string]*costReportLineItem{}
+	var order []string
+	reports := map[string]*costReport{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name, ".csv") && !strings.HasSuffix(file.Name, ".csv.gz") {
+			continue
+		}
+		if file.ModTime.Before(start) || file.ModTime.After(end.Add(costReportLookahead)) {
+			continue
+		}
+
+		report, ok := cri.reports[file.Name]
+		if !ok || !report.modTime.Equal(file.ModTime) || report.size != file.Size {
+			data, err := store.Read(path.Join(cri.GetPrefix(), file.Name))
+			if err != nil {
+				cri.ConnectionStatus = cloud.FailedConnection
+				return nil, fmt.Errorf("reading cost report %s: %w", file.Name, err)
+			}
+
+			items, err := parseCostReport(file.Name, data)
+			if err != nil {
+				cri.ConnectionStatus = cloud.ParseError
+				return nil, fmt.Errorf("parsing cost report %s: %w", file.Name, err)
+			}
+
+			report = &costReport{modTime: file.ModTime, size: file.Size, items: items}
+		}
+		reports[file.Name] = report
+
+		for _, item := range report.items {
+			if item.correction && item.backReferenceNo != "" {
+				delete(lineItems, item.backReferenceNo)
+			}
+			if _, ok := lineItems[item.referenceNo]; !ok {
+				order = append(order, item.referenceNo)
+			}
+			lineItems[item.referenceNo] = item
+		}
+	}
+	cri.reports = reports
+
+	loaded := 0
+	for _, referenceNo := range order {
+		item, ok := lineItems[referenceNo]
+		if !ok {
+			continue
+		}
+		if !item.end.After(start) || !item.start.Before(end) {
+			continue
+		}
+		ccsr.LoadCloudCost(cri.toCloudCost(item))
+		loaded++
+	}
+
+	// Set status to missing data if no line items are in the window and the status isn't already successful
+	if loaded == 0 && cri.ConnectionStatus != cloud.SuccessfulConnection {
+		cri.ConnectionStatus = cloud.MissingData
+		return ccsr, nil
+	}
+
+	cri.ConnectionStatus = cloud.SuccessfulConnection
+	return ccsr, nil
+}
+
+func (cri *CostReportIntegration) GetStatus() cloud.ConnectionStatus {
+	// initialize status if it has not done so; this can happen if the integration is inactive
+	if cri.ConnectionStatus.String() == "" {
+		cri.ConnectionStatus = cloud.InitialStatus
+	}
+	return cri.ConnectionStatus
+}
+
+func (cri *CostReportIntegration) toCloudCost(item *costReportLineItem) *opencost.CloudCost {
+	labels := opencost.CloudCostLabels{}
+	for k, v := range item.tags {
+		labels[k] = v
+	}
+	if item.compartmentID != "" {
+		labels[CompartmentIDLabel] = item.compartmentID
+	}
+	if item.compartmentName != "" {
+		labels[CompartmentNameLabel] = item.compartmentName
+	}
+
+	accountID := item.tenantID
+	if accountID == "" {
+		accountID = cri.TenancyID
+	}
+
+	properties := &opencost.CloudCostProperties{
+		ProviderID:       item.resourceID,
+		Provider:         opencost.OracleProvider,
+		AccountID:        accountID,
+		InvoiceEntityID:  item.subscriptionID,
+		RegionID:         item.region,
+		AvailabilityZone: item.availabilityDomain,
+		Service:          item.service,
+		Category:         SelectOCICategory(item.service),
+		Labels:           labels,
+	}
+
+	return &opencost.CloudCost{
+		Properties: properties,
+		Window:     opencost.NewWindow(&item.start, &item.end),
+		ListCost: opencost.CostMetric{
+			Cost: item.listCost,
+		},
+		NetCost: opencost.CostMetric{
+			Cost: item.cost,
+		},
+		AmortizedNetCost: opencost.CostMetric{
+			Cost: item.amortizedCost,
+		},
+		AmortizedCost: opencost.CostMetric{
+			Cost: item.amortizedCost,
+		},
+		InvoicedCost: opencost.CostMetric{
+			Cost: item.cost,
+		},
+	}
+}
+
+type costReportLineItem struct {
+	referenceNo        string
+	backReferenceNo    string
+	correction         bool
+	tenantID           string
+	start              time.Time
+	end                time.Time
+	service            string
+	compartmentID      string
+	compartmentName    string
+	region             string
+	availabilityDomain string
+	resourceID         string
+	subscriptionID     string
+	listCost           float64
+	cost               float64
+	amortizedCost      float64
+	tags               map[string]string
+}
+
+// parseCostReport parses the line items of a cost report, which is gzipped if its name ends with .gz
+func parseCostReport(name string, data []byte) ([]*costReportLineItem, error) {
+	var reader io.Reader = bytes.NewReader(data)
+	if strings.HasSuffix(name, ".gz") {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("opening gzip: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	tagColumns := map[string]int{}
+	for i, column := range header {
+		columns[column] = i
+		if tag, ok := strings.CutPrefix(column, costReportTagPrefix); ok && tag != "" {
+			tagColumns[tag] = i
+		}
+	}
+
+	for _, column := range []string{costReportReferenceNo, costReportIntervalStart, costReportIntervalEnd, costReportMyCost} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("missing column %s", column)
+		}
+	}
+
+	var items []*costReportLineItem
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading line item: %w", err)
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		item := &costReportLineItem{
+			referenceNo:        value(costReportReferenceNo),
+			backReferenceNo:    value(costReportBackReferenceNo),
+			correction:         strings.EqualFold(value(costReportIsCorrection), "true"),
+			tenantID:           value(costReportTenantID),
+			service:            value(costReportService),
+			compartmentID:      value(costReportCompartmentID),
+			compartmentName:    value(costReportCompartmentName),
+			region:             value(costReportRegion),
+			availabilityDomain: value(costReportAvailabilityDomain),
+			resourceID:         value(costReportResourceID),
+			subscriptionID:     value(costReportSubscriptionID),
+		}
+
+		item.start, err = parseCostReportTime(value(costReportIntervalStart))
+		if err != nil {
+			return nil, fmt.Errorf("line item %s: %w", item.referenceNo, err)
+		}
+		item.end, err = parseCostReportTime(value(costReportIntervalEnd))
+		if err != nil {
+			return nil, fmt.Errorf("line item %s: %w", item.referenceNo, err)
+		}
+
+		item.cost, err = parseCostReportFloat(value(costReportMyCost))
+		if err != nil {
+			return nil, fmt.Errorf("line item %s: %w", item.referenceNo, err)
+		}
+
+		item.listCost = item.cost
+		quantity, quantityErr := parseCostReportFloat(value(costReportBilledQuantity))
+		unitPrice, unitPriceErr := parseCostReportFloat(value(costReportUnitPrice))
+		if quantityErr == nil && unitPriceErr == nil && value(costReportUnitPrice) != "" {
+			item.listCost = quantity * unitPrice
+		}
+
+		item.amortizedCost = item.cost
+		if attributed := value(costReportAttributedCost); attributed != "" {
+			item.amortizedCost, err = parseCostReportFloat(attributed)
+			if err != nil {
+				return nil, fmt.Errorf("line item %s: %w", item.referenceNo, err)
+			}
+		}
+
+		for tag, i := range tagColumns {
+			if i < len(record) && record[i] != "" {
+				if item.tags == nil {
+					item.tags = map[string]string{}
+				}
+				item.tags[tag] = record[i]
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func parseCostReportTime(value string) (time.Time, error) {
+	for _, layout := range costReportTimeLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse time '%s'", value)
+}
+
+func parseCostReportFloat(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse float '%s': %s", value, err.Error())
+	}
+	return f, nil
+}
+
+// objectStorageReportStore reads cost reports from an object storage bucket
+type objectStorageReportStore struct {
+	client    *objectstorage.ObjectStorageClient
+	namespace string
+	bucket    string
+}
+
+func (osrs *objectStorageReportStore) List(prefix string) ([]*storage.StorageInfo, error) {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+
+	var files []*storage.StorageInfo
+	var startWith *string
+	for {
+		resp, err := osrs.client.ListObjects(context.Background(), objectstorage.ListObjectsRequest{
+			NamespaceName: common.String(osrs.namespace),
+			BucketName:    common.String(osrs.bucket),
+			Prefix:        common.String(prefix),
+			Start:         startWith,
+			Fields:        common.String("name,size,timeCreated"),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range resp.Objects {
+			if object.Name == nil {
+				continue
+			}
+			info := &storage.StorageInfo{
+				Name: strings.TrimPrefix(*object.Name, prefix),
+			}
+			if object.Size != nil {
+				info.Size = *object.Size
+			}
+			if object.TimeCreated != nil {
+				info.ModTime = object.TimeCreated.Time
+			}
+			files = append(files, info)
+		}
+
+		if resp.NextStartWith == nil || *resp.NextStartWith == "" {
+			return files, nil
+		}
+		startWith = resp.NextStartWith
+	}
+}
+
+func (osrs *objectStorageReportStore) Read(name string) ([]byte, error) {
+	resp, err := osrs.client.GetObject(context.Background(), objectstorage.GetObjectRequest{
+		NamespaceName: common.String(osrs.namespace),
+		BucketName:    common.String(osrs.bucket),
+		ObjectName:    common.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Content.Close()
+
+	return io.ReadAll(resp.Content)
+}
@@ -0,0 +1,196 @@
+package oracle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/storage"
+	"github.com/opencost/opencost/core/pkg/util"
+	"github.com/opencost/opencost/pkg/cloud"
+)
+
+const testCostReport = `lineItem/referenceNo,lineItem/tenantId,lineItem/intervalUsageStart,lineItem/intervalUsageEnd,product/service,product/compartmentId,product/compartmentName,product/region,product/availabilityDomain,product/resourceId,usage/billedQuantity,cost/subscriptionId,cost/unitPrice,cost/myCost,lineItem/isCorrection,lineItem/backreferenceNo,tags/Operations.CostCenter,tags/team
+1,tenancy,2024-01-01T00:00Z,2024-01-01T01:00Z,COMPUTE,compartment,dev,us-ashburn-1,AD-1,instance,2,subscription,0.5,0.8,false,,42,search
+2,tenancy,2024-01-01T01:00Z,2024-01-01T02:00Z,COMPUTE,compartment,dev,us-ashburn-1,AD-1,instance,2,subscription,0.5,0.8,false,,42,search
+3,tenancy,2024-01-01T00:00Z,2024-01-01T01:00Z,BLOCK_STORAGE,compartment,dev,uk-london-1,,volume,1,subscription,0.1,0.1,false,,,
+4,tenancy,2024-01-01T01:00Z,2024-01-01T02:00Z,COMPUTE,compartment,dev,us-ashburn-1,AD-1,instance,2,subscription,0.5,0.6,true,2,42,search
+5,tenancy,2024-01-03T00:00Z,2024-01-03T01:00Z,COMPUTE,compartment,dev,us-ashburn-1,AD-1,instance,2,subscription,0.5,0.8,false,,42,search
+`
+
+func TestCostReportIntegration_GetCloudCost(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	dir := t.TempDir()
+	store := storage.NewFileStorage(dir)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(testCostReport))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	gz.Close()
+
+	// Reports are selected by the time they were written
+	reports := map[string]time.Time{
+		"0001000000000001.csv.gz": start.Add(6 * time.Hour),
+		"0001000000000000.csv.gz": start.AddDate(0, 0, -1),
+		"notes.txt":               start.Add(6 * time.Hour),
+	}
+	for name, modTime := range reports {
+		err = store.Write(path.Join(DefaultCostReportPrefix, name), buf.Bytes())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		err = os.Chtimes(filepath.Join(dir, DefaultCostReportPrefix, name), modTime, modTime)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	cri := &CostReportIntegration{
+		CostReportConfiguration: CostReportConfiguration{TenancyID: "tenancy", Region: "us-ashburn-1"},
+		Store:                   store,
+	}
+	ccsr, err := cri.GetCloudCost(start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if cri.ConnectionStatus != cloud.SuccessfulConnection {
+		t.Errorf("expected successful connection, got %s", cri.ConnectionStatus)
+	}
+	if len(ccsr.CloudCostSets) != 2 {
+		t.Fatalf("expected 2 daily sets, got %d", len(ccsr.CloudCostSets))
+	}
+	if len(ccsr.CloudCostSets[1].CloudCosts) != 0 {
+		t.Errorf("expected no cloud costs on the second day, got %d", len(ccsr.CloudCostSets[1].CloudCosts))
+	}
+
+	var instance, volume *opencost.CloudCost
+	for _, cc := range ccsr.CloudCostSets[0].CloudCosts {
+		switch cc.Properties.ProviderID {
+		case "instance":
+			instance = cc
+		case "volume":
+			volume = cc
+		}
+	}
+	if instance == nil || volume == nil {
+		t.Fatalf("expected instance and volume cloud costs")
+	}
+
+	// Line item 2 is replaced by its correction, and the report written before the window is not read
+	if !util.IsApproximately(instance.NetCost.Cost, 1.4) {
+		t.Errorf("expected instance net cost 1.4, got %f", instance.NetCost.Cost)
+	}
+	if !util.IsApproximately(instance.ListCost.Cost, 2) {
+		t.Errorf("expected instance list cost 2, got %f", instance.ListCost.Cost)
+	}
+	if instance.Properties.Labels["team"] != "search" || instance.Properties.Labels[CompartmentNameLabel] != "dev" {
+		t.Errorf("unexpected instance labels: %v", instance.Properties.Labels)
+	}
+	if instance.Properties.AvailabilityZone != "AD-1" || volume.Properties.RegionID != "uk-london-1" {
+		t.Errorf("unexpected locations: %s, %s", instance.Properties.AvailabilityZone, volume.Properties.RegionID)
+	}
+	if volume.Properties.Category != opencost.StorageCategory {
+		t.Errorf("expected volume category %s, got %s", opencost.StorageCategory, volume.Properties.Category)
+	}
+}
+
+// countingReportStore counts the reports read from a ReportStore
+type countingReportStore struct {
+	ReportStore
+	reads int
+}
+
+func (crs *countingReportStore) Read(path string) ([]byte, error) {
+	crs.reads++
+	return crs.ReportStore.Read(path)
+}
+
+func TestCostReportIntegration_GetCloudCost_Cache(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	dir := t.TempDir()
+	name := "0001000000000001.csv"
+	reportPath := filepath.Join(dir, DefaultCostReportPrefix, name)
+	fileStore := storage.NewFileStorage(dir)
+
+	writeReport := func(modTime time.Time) {
+		err := fileStore.Write(path.Join(DefaultCostReportPrefix, name), []byte(testCostReport))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		err = os.Chtimes(reportPath, modTime, modTime)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	store := &countingReportStore{ReportStore: fileStore}
+	cri := &CostReportIntegration{
+		CostReportConfiguration: CostReportConfiguration{TenancyID: "tenancy", Region: "us-ashburn-1"},
+		Store:                   store,
+	}
+
+	writeReport(start.Add(6 * time.Hour))
+	for i := 0; i < 2; i++ {
+		_, err := cri.GetCloudCost(start, end)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if store.reads != 1 {
+		t.Errorf("expected an unchanged report to be read once, got %d reads", store.reads)
+	}
+
+	// A report which is written again is read again
+	writeReport(start.Add(7 * time.Hour))
+	_, err := cri.GetCloudCost(start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if store.reads != 2 {
+		t.Errorf("expected a changed report to be read again, got %d reads", store.reads)
+	}
+}
+
+func TestParseCostReport(t *testing.T) {
+	items, err := parseCostReport("report.csv", []byte(testCostReport))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(items) != 5 {
+		t.Fatalf("expected 5 line items, got %d", len(items))
+	}
+
+	item := items[0]
+	if item.resourceID != "instance" || item.region != "us-ashburn-1" || item.compartmentName != "dev" {
+		t.Errorf("unexpected line item properties: %+v", item)
+	}
+	if item.listCost != 1 || item.cost != 0.8 || item.amortizedCost != 0.8 {
+		t.Errorf("unexpected line item costs: list %f, net %f, amortized %f", item.listCost, item.cost, item.amortizedCost)
+	}
+	if item.tags["Operations.CostCenter"] != "42" || item.tags["team"] != "search" {
+		t.Errorf("unexpected line item tags: %v", item.tags)
+	}
+	if items[2].tags != nil {
+		t.Errorf("expected no tags for untagged line item, got %v", items[2].tags)
+	}
+	if !items[3].correction || items[3].backReferenceNo != "2" {
+		t.Errorf("expected a correction of line item 2, got %+v", items[3])
+	}
+
+	_, err = parseCostReport("report.csv", []byte("lineItem/referenceNo\n1\n"))
+	if err == nil {
+		t.Errorf("expected error for report with missing columns")
+	}
+}
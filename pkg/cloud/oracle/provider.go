@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -36,6 +37,12 @@ type Oracle struct {
 	RateCardStore           *RateCardStore
 	ServiceAccountChecks    *models.ServiceAccountChecks
 	DefaultPricing          DefaultPricing
+	instanceLock            sync.Mutex
+	instances               map[string]*instanceDetails
+	resourceClient          resourceClient
+	resourceClientErr       error
+	clusterType             string
+	clusterTypeRefreshed    time.Time
 }
 
 func (o *Oracle) ClusterInfo() (map[string]string, error) {
@@ -272,10 +279,21 @@ func (o *Oracle) ClusterManagementPricing() (string, float64, error) {
 	if managementPlatform != managementPlatformOKE {
 		return "", 0.0, nil // Self-managed cluster.
 	}
+	clusterType := o.getClusterType()
 	o.DownloadPricingDataLock.Lock()
 	defer o.DownloadPricingDataLock.Unlock()
-	// TODO: Support lookup of cluster type, as BASIC_CLUSTER types are free.
-	return managementPlatformOKE, o.RateCardStore.ForManagedCluster("ENHANCED_CLUSTER"), nil
+	return managementPlatformOKE, o.RateCardStore.ForManagedCluster(clusterType), nil
+}
+
+// VirtualNodePodPricing returns the hourly fee charged for each pod running on
+// an OKE virtual node, on top of the pod's OCPU and memory.
+func (o *Oracle) VirtualNodePodPricing() (float64, error) {
+	if err := o.ensurePricingData(); err != nil {
+		return 0.0, err
+	}
+	o.DownloadPricingDataLock.RLock()
+	defer o.DownloadPricingDataLock.RUnlock()
+	return o.RateCardStore.ForVirtualNodePod(), nil
 }
 
 func (o *Oracle) CombinedDiscountForNode(instanceType string, isPreemptible bool, defaultDiscount, negotiatedDiscount float64) float64 {
@@ -319,20 +337,6 @@ func (o *Oracle) ensurePricingData() error {
 	return nil
 }
 
-func (o *Oracle) GetAddresses() ([]byte, error) {
-	return nil, nil
-}
-
-func (o *Oracle) GetDisks() ([]byte, error) {
-	return nil, nil
-}
-
-func (o *Oracle) GetOrphanedResources() ([]models.OrphanedResource, error) {
-	return nil, nil
-}
-
-func (o *Oracle) ApplyReservedInstancePricing(m map[string]*models.Node) {}
-
 func (o *Oracle) ServiceAccountStatus() *models.ServiceAccountStatus {
 	return o.ServiceAccountChecks.GetStatus()
 }
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/pkg/cloud/models"
 )
 
@@ -87,6 +88,16 @@ func (rcs *RateCardStore) ForManagedCluster(clusterType string) float64 {
 	return rc.UnitPrice
 }
 
+// ForVirtualNodePod returns the hourly fee charged for each pod running on a
+// virtual node.
+func (rcs *RateCardStore) ForVirtualNodePod() float64 {
+	rc, ok := rcs.prices[virualNodePartNumber]
+	if !ok {
+		return 0.015
+	}
+	return rc.UnitPrice
+}
+
 func (rcs *RateCardStore) ForEgressRegion(region string, defaultPricing DefaultPricing) (*models.Network, error) {
 	pn := egressRegionPartNumber(region)
 	var egressCost float64
@@ -121,6 +132,42 @@ func (rcs *RateCardStore) ForPVK(pvk models.PVKey, defaultPricing DefaultPricing
 	}, nil
 }
 
+// defaultVPUsPerGB is the performance level of the Balanced block volume tier.
+const defaultVPUsPerGB = 10
+
+// ForBlockVolume retrieves the monthly cost of a block or boot volume, including
+// its performance units. The default storage pricing is used if the rate card is
+// missing either the storage or the performance part.
+func (rcs *RateCardStore) ForBlockVolume(sizeInGBs int64, vpusPerGB *int64, defaultPricing DefaultPricing) (float64, error) {
+	storage, storageOK := rcs.prices[blockVolumePartNumber]
+	performance, performanceOK := rcs.prices[blockVolumePerformancePartNumber]
+	if !storageOK || !performanceOK {
+		missing := blockVolumePartNumber
+		if storageOK {
+			missing = blockVolumePerformancePartNumber
+		}
+		log.DedupedWarningf(5, "Oracle: rate card is missing block volume part %s, using default storage pricing", missing)
+
+		// Default storage pricing is in Gb/Hour
+		cost, err := strconv.ParseFloat(defaultPricing.Storage, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cost * hoursPerMonth * float64(sizeInGBs), nil
+	}
+	vpus := int64(defaultVPUsPerGB)
+	if vpusPerGB != nil {
+		vpus = *vpusPerGB
+	}
+	return float64(sizeInGBs) * (storage.UnitPrice + float64(vpus)*performance.UnitPrice), nil
+}
+
+// ForReservedPublicIP retrieves the hourly cost of a reserved public IP. OCI does
+// not bill for reserved public IPv4 addresses, so they have no rate card.
+func (rcs *RateCardStore) ForReservedPublicIP() float64 {
+	return 0.0
+}
+
 // ForKey retrieves costing metadata for a key.
 func (rcs *RateCardStore) ForKey(key models.Key, defaultPricing DefaultPricing) (*models.Node, models.PricingMetadata, error) {
 	features := strings.Split(key.Features(), ",")
@@ -167,9 +214,11 @@ func (rcs *RateCardStore) ForKey(key models.Key, defaultPricing DefaultPricing)
 		// convert disk price from Tb/hour to Gb/hour
 		diskPrice /= 1000
 		totalPrice := diskPrice + ocpuPrice + memoryPrice + gpuPrice
-		// Add virtual node pricing if it is being used.
+		// Virtual nodes bill each pod for its own OCPU and memory requests,
+		// plus the virtual node fee, so the node itself only carries the fee.
+		// The cost model charges the fee to each pod via VirtualNodePodPricing.
 		if len(features) > 1 && features[1] == "true" {
-			totalPrice += rcs.prices[virualNodePartNumber].UnitPrice
+			totalPrice = rcs.ForVirtualNodePod()
 		}
 		node = &models.Node{
 			Cost:        fmt.Sprintf("%f", totalPrice),
@@ -64,6 +64,26 @@ func TestRCSForKey(t *testing.T) {
 	}
 }
 
+func TestRCSForKeyVirtualNode(t *testing.T) {
+	rcs, server := testSetupRateCardStore(t)
+	defer server.Close()
+
+	key := &oracleKey{
+		instanceType: "Pod.Standard.E4.Flex",
+		labels: map[string]string{
+			virtualNodeLabel: "",
+		},
+	}
+	node, _, err := rcs.ForKey(key, DefaultPricing{})
+	assert.NoError(t, err)
+	// The virtual node only carries the virtual node fee, while pods are billed
+	// at the Pod shape OCPU and memory rates.
+	assertFloatStrings(t, "0.015", node.Cost, 0.0001)
+	assertFloatStrings(t, "0.0125", node.VCPUCost, 0.0001)
+	assertFloatStrings(t, "0.0015", node.RAMCost, 0.0001)
+	assert.InDelta(t, 0.015, rcs.ForVirtualNodePod(), 0.0001)
+}
+
 func TestRCSForPVK(t *testing.T) {
 	rcs, server := testSetupRateCardStore(t)
 	defer server.Close()
@@ -96,6 +116,45 @@ func TestRCSForPVK(t *testing.T) {
 	}
 }
 
+func TestRCSForBlockVolume(t *testing.T) {
+	defaultPricing := DefaultPricing{Storage: "0.0001"}
+	vpus := int64(20)
+
+	testCases := map[string]struct {
+		prices map[string]Price
+		cost   float64
+	}{
+		"rate card": {
+			prices: map[string]Price{
+				blockVolumePartNumber:            {UnitPrice: 0.0255},
+				blockVolumePerformancePartNumber: {UnitPrice: 0.0017},
+			},
+			cost: 100 * (0.0255 + 20*0.0017),
+		},
+		"missing storage part": {
+			prices: map[string]Price{
+				blockVolumePerformancePartNumber: {UnitPrice: 0.0017},
+			},
+			cost: 100 * 0.0001 * hoursPerMonth,
+		},
+		"missing performance part": {
+			prices: map[string]Price{
+				blockVolumePartNumber: {UnitPrice: 0.0255},
+			},
+			cost: 100 * 0.0001 * hoursPerMonth,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rcs := &RateCardStore{prices: testCase.prices}
+			cost, err := rcs.ForBlockVolume(100, &vpus, defaultPricing)
+			assert.NoError(t, err)
+			assert.InDelta(t, testCase.cost, cost, 0.00001)
+		})
+	}
+}
+
 func TestRCSEgressForRegion(t *testing.T) {
 	rcs, server := testSetupRateCardStore(t)
 	defer server.Close()
@@ -0,0 +1,578 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/util/json"
+	"github.com/opencost/opencost/pkg/cloud/models"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/common/auth"
+	"github.com/oracle/oci-go-sdk/v65/containerengine"
+	"github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// instanceCacheTTL is how long instance details, which drive preemptible,
+// burstable and capacity reservation pricing, are cached for.
+const instanceCacheTTL = time.Hour
+
+// preemptibleDiscount is the discount applied to preemptible instances, which are
+// billed at 50% of the on-demand price.
+const preemptibleDiscount = 0.5
+
+// resourceClient wraps the OCI Compute, Block Storage, Virtual Network and
+// Container Engine APIs used to discover cluster instances, volumes, public IPs
+// and the OKE cluster type.
+type resourceClient interface {
+	GetNodePoolClusterID(ctx context.Context, nodePoolID string) (string, error)
+	GetClusterType(ctx context.Context, clusterID string) (string, error)
+	GetInstance(ctx context.Context, instanceID string) (*core.Instance, error)
+	ListVolumes(ctx context.Context, compartmentID string) ([]core.Volume, error)
+	ListVolumeAttachments(ctx context.Context, compartmentID string) ([]core.VolumeAttachment, error)
+	ListBootVolumes(ctx context.Context, compartmentID string) ([]core.BootVolume, error)
+	ListBootVolumeAttachments(ctx context.Context, compartmentID, availabilityDomain string) ([]core.BootVolumeAttachment, error)
+	ListReservedPublicIPs(ctx context.Context, compartmentID string) ([]core.PublicIp, error)
+}
+
+// instanceDetails are the pricing relevant attributes of an OCI instance backing a node.
+type instanceDetails struct {
+	compartmentID         string
+	availabilityDomain    string
+	capacityReservationID string
+	preemptible           bool
+	// baselineOCPUUtilization is the fraction of OCPUs billed for burstable
+	// instances, and 1.0 for all other instances.
+	baselineOCPUUtilization float64
+	refreshed               time.Time
+}
+
+func newInstanceDetails(instance *core.Instance) *instanceDetails {
+	details := &instanceDetails{
+		compartmentID:           stringValue(instance.CompartmentId),
+		availabilityDomain:      stringValue(instance.AvailabilityDomain),
+		capacityReservationID:   stringValue(instance.CapacityReservationId),
+		preemptible:             instance.PreemptibleInstanceConfig != nil,
+		baselineOCPUUtilization: 1.0,
+		refreshed:               time.Now(),
+	}
+	if instance.ShapeConfig != nil {
+		details.baselineOCPUUtilization = baselineOCPUUtilization(instance.ShapeConfig.BaselineOcpuUtilization)
+	}
+	return details
+}
+
+// baselineOCPUUtilization converts an OCI burstable baseline into the fraction of
+// OCPUs which are billed.
+func baselineOCPUUtilization(baseline core.InstanceShapeConfigBaselineOcpuUtilizationEnum) float64 {
+	switch baseline {
+	case core.InstanceShapeConfigBaselineOcpuUtilization8:
+		return 0.125
+	case core.InstanceShapeConfigBaselineOcpuUtilization2:
+		return 0.5
+	default:
+		return 1.0
+	}
+}
+
+// ociDisks is the response body of GetDisks.
+type ociDisks struct {
+	Volumes     []core.Volume     `json:"volumes"`
+	BootVolumes []core.BootVolume `json:"bootVolumes"`
+}
+
+// GetAddresses returns the reserved public IPs in the compartments of the cluster nodes.
+func (o *Oracle) GetAddresses() ([]byte, error) {
+	client, err := o.getResourceClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.TODO()
+	var addresses []core.PublicIp
+	for _, compartmentID := range o.clusterCompartments(ctx, client) {
+		ips, err := client.ListReservedPublicIPs(ctx, compartmentID)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, ips...)
+	}
+	return json.Marshal(addresses)
+}
+
+// GetDisks returns the block volumes and boot volumes in the compartments of the cluster nodes.
+func (o *Oracle) GetDisks() ([]byte, error) {
+	client, err := o.getResourceClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.TODO()
+	disks := ociDisks{}
+	for _, compartmentID := range o.clusterCompartments(ctx, client) {
+		volumes, err := client.ListVolumes(ctx, compartmentID)
+		if err != nil {
+			return nil, err
+		}
+		disks.Volumes = append(disks.Volumes, volumes...)
+		bootVolumes, err := client.ListBootVolumes(ctx, compartmentID)
+		if err != nil {
+			return nil, err
+		}
+		disks.BootVolumes = append(disks.BootVolumes, bootVolumes...)
+	}
+	return json.Marshal(disks)
+}
+
+// GetOrphanedResources returns the unattached block volumes, boot volumes and
+// unassigned reserved public IPs in the compartments of the cluster nodes.
+func (o *Oracle) GetOrphanedResources() ([]models.OrphanedResource, error) {
+	if err := o.ensurePricingData(); err != nil {
+		return nil, err
+	}
+	client, err := o.getResourceClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.TODO()
+
+	o.DownloadPricingDataLock.RLock()
+	defer o.DownloadPricingDataLock.RUnlock()
+
+	var orphanedResources []models.OrphanedResource
+	for _, compartmentID := range o.clusterCompartments(ctx, client) {
+		volumes, err := o.getOrphanedVolumes(ctx, client, compartmentID)
+		if err != nil {
+			return nil, err
+		}
+		orphanedResources = append(orphanedResources, volumes...)
+
+		bootVolumes, err := o.getOrphanedBootVolumes(ctx, client, compartmentID)
+		if err != nil {
+			return nil, err
+		}
+		orphanedResources = append(orphanedResources, bootVolumes...)
+
+		addresses, err := o.getOrphanedAddresses(ctx, client, compartmentID)
+		if err != nil {
+			return nil, err
+		}
+		orphanedResources = append(orphanedResources, addresses...)
+	}
+	return orphanedResources, nil
+}
+
+func (o *Oracle) getOrphanedVolumes(ctx context.Context, client resourceClient, compartmentID string) ([]models.OrphanedResource, error) {
+	volumes, err := client.ListVolumes(ctx, compartmentID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := client.ListVolumeAttachments(ctx, compartmentID)
+	if err != nil {
+		return nil, err
+	}
+	attached := map[string]bool{}
+	for _, attachment := range attachments {
+		if isAttached(string(attachment.GetLifecycleState())) {
+			attached[stringValue(attachment.GetVolumeId())] = true
+		}
+	}
+
+	var orphaned []models.OrphanedResource
+	for _, volume := range volumes {
+		if volume.LifecycleState != core.VolumeLifecycleStateAvailable || attached[stringValue(volume.Id)] {
+			continue
+		}
+		orphaned = append(orphaned, o.orphanedDisk("blockVolume", stringValue(volume.Id), stringValue(volume.DisplayName),
+			stringValue(volume.AvailabilityDomain), compartmentID, volume.SizeInGBs, volume.VpusPerGB))
+	}
+	return orphaned, nil
+}
+
+func (o *Oracle) getOrphanedBootVolumes(ctx context.Context, client resourceClient, compartmentID string) ([]models.OrphanedResource, error) {
+	bootVolumes, err := client.ListBootVolumes(ctx, compartmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Boot volume attachments can only be listed per availability domain.
+	attached := map[string]bool{}
+	listed := map[string]bool{}
+	for _, bootVolume := range bootVolumes {
+		ad := stringValue(bootVolume.AvailabilityDomain)
+		if listed[ad] {
+			continue
+		}
+		listed[ad] = true
+		attachments, err := client.ListBootVolumeAttachments(ctx, compartmentID, ad)
+		if err != nil {
+			return nil, err
+		}
+		for _, attachment := range attachments {
+			if isAttached(string(attachment.LifecycleState)) {
+				attached[stringValue(attachment.BootVolumeId)] = true
+			}
+		}
+	}
+
+	var orphaned []models.OrphanedResource
+	for _, bootVolume := range bootVolumes {
+		if bootVolume.LifecycleState != core.BootVolumeLifecycleStateAvailable || attached[stringValue(bootVolume.Id)] {
+			continue
+		}
+		orphaned = append(orphaned, o.orphanedDisk("bootVolume", stringValue(bootVolume.Id), stringValue(bootVolume.DisplayName),
+			stringValue(bootVolume.AvailabilityDomain), compartmentID, bootVolume.SizeInGBs, bootVolume.VpusPerGB))
+	}
+	return orphaned, nil
+}
+
+func (o *Oracle) getOrphanedAddresses(ctx context.Context, client resourceClient, compartmentID string) ([]models.OrphanedResource, error) {
+	addresses, err := client.ListReservedPublicIPs(ctx, compartmentID)
+	if err != nil {
+		return nil, err
+	}
+	var orphaned []models.OrphanedResource
+	for _, address := range addresses {
+		if address.AssignedEntityId != nil || address.LifecycleState != core.PublicIpLifecycleStateAvailable {
+			continue
+		}
+		cost := o.RateCardStore.ForReservedPublicIP() * hoursPerMonth
+		orphaned = append(orphaned, models.OrphanedResource{
+			Kind:   "address",
+			Region: o.ClusterRegion,
+			Description: map[string]string{
+				"type":          string(address.Lifetime),
+				"compartmentId": compartmentID,
+			},
+			Address:     stringValue(address.IpAddress),
+			Url:         stringValue(address.Id),
+			MonthlyCost: &cost,
+		})
+	}
+	return orphaned, nil
+}
+
+func (o *Oracle) orphanedDisk(diskType, id, name, availabilityDomain, compartmentID string, sizeInGBs, vpusPerGB *int64) models.OrphanedResource {
+	var size int64
+	if sizeInGBs != nil {
+		size = *sizeInGBs
+	}
+	cost, err := o.RateCardStore.ForBlockVolume(size, vpusPerGB, o.DefaultPricing)
+	if err != nil {
+		log.Warnf("Oracle: failed to price orphaned %s %s: %s", diskType, id, err)
+	}
+	return models.OrphanedResource{
+		Kind:   "disk",
+		Region: availabilityDomain,
+		Description: map[string]string{
+			"type":          diskType,
+			"compartmentId": compartmentID,
+		},
+		Size:        &size,
+		DiskName:    name,
+		Url:         id,
+		MonthlyCost: &cost,
+	}
+}
+
+// isAttached reports whether a volume or boot volume attachment lifecycle state
+// holds the volume.
+func isAttached(lifecycleState string) bool {
+	return lifecycleState == string(core.VolumeAttachmentLifecycleStateAttached) ||
+		lifecycleState == string(core.VolumeAttachmentLifecycleStateAttaching)
+}
+
+// ApplyReservedInstancePricing adjusts node pricing for preemptible and burstable
+// instances, and records nodes which run in a capacity reservation. Reserved
+// capacity is billed at on-demand rates while in use, so reserved resources carry
+// the node's own CPU and RAM prices.
+func (o *Oracle) ApplyReservedInstancePricing(nodes map[string]*models.Node) {
+	client, err := o.getResourceClient()
+	if err != nil {
+		log.DedupedWarningf(5, "Oracle: unable to look up instance details for node pricing: %s", err)
+		return
+	}
+	ctx := context.TODO()
+	for name, node := range nodes {
+		// Reset reserved allocation to prevent double allocation
+		node.Reserved = nil
+		if node.ProviderID == "" {
+			continue
+		}
+		details, err := o.getInstanceDetails(ctx, client, node.ProviderID)
+		if err != nil {
+			log.Debugf("Oracle: could not get instance details for node %s: %s", name, err)
+			continue
+		}
+		applyInstancePricing(node, details)
+	}
+}
+
+func applyInstancePricing(node *models.Node, details *instanceDetails) {
+	cpuFactor := details.baselineOCPUUtilization
+	ramFactor := 1.0
+	if details.preemptible {
+		cpuFactor *= preemptibleDiscount
+		ramFactor *= preemptibleDiscount
+		node.UsageType = "preemptible"
+		node.PricingType = models.Spot
+	}
+	if cpuFactor != 1.0 || ramFactor != 1.0 {
+		scaleNodePricing(node, cpuFactor, ramFactor)
+	}
+
+	if details.capacityReservationID != "" {
+		vcpu, _ := strconv.ParseFloat(node.VCPU, 64)
+		ramBytes, _ := strconv.ParseFloat(node.RAMBytes, 64)
+		cpuCost, _ := strconv.ParseFloat(node.VCPUCost, 64)
+		ramCost, _ := strconv.ParseFloat(node.RAMCost, 64)
+		node.Reserved = &models.ReservedInstanceData{
+			ReservedCPU: int64(vcpu),
+			ReservedRAM: int64(ramBytes),
+			CPUCost:     cpuCost,
+			RAMCost:     ramCost,
+		}
+		if node.PricingType != models.Spot {
+			node.PricingType = models.Reserved
+		}
+	}
+}
+
+// scaleNodePricing scales the CPU, RAM and GPU prices of a node, keeping the total
+// node cost consistent with its components. GPUs are discounted with RAM, as
+// neither are subject to burstable baselines.
+func scaleNodePricing(node *models.Node, cpuFactor, ramFactor float64) {
+	total, _ := strconv.ParseFloat(node.Cost, 64)
+	scale := func(price string, factor float64) string {
+		p, err := strconv.ParseFloat(price, 64)
+		if err != nil {
+			return price
+		}
+		total -= p * (1 - factor)
+		return fmt.Sprintf("%f", p*factor)
+	}
+	node.VCPUCost = scale(node.VCPUCost, cpuFactor)
+	node.RAMCost = scale(node.RAMCost, ramFactor)
+	node.GPUCost = scale(node.GPUCost, ramFactor)
+	if node.Cost != "" {
+		node.Cost = fmt.Sprintf("%f", total)
+	}
+}
+
+// getInstanceDetails returns the cached details of an instance, refreshing them
+// from the Compute API once they are older than instanceCacheTTL.
+func (o *Oracle) getInstanceDetails(ctx context.Context, client resourceClient, instanceID string) (*instanceDetails, error) {
+	o.instanceLock.Lock()
+	defer o.instanceLock.Unlock()
+	if o.instances == nil {
+		o.instances = map[string]*instanceDetails{}
+	}
+	if details, ok := o.instances[instanceID]; ok && time.Since(details.refreshed) < instanceCacheTTL {
+		return details, nil
+	}
+	instance, err := client.GetInstance(ctx, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	details := newInstanceDetails(instance)
+	o.instances[instanceID] = details
+	return details, nil
+}
+
+// clusterCompartments returns the compartments containing the cluster's node instances.
+func (o *Oracle) clusterCompartments(ctx context.Context, client resourceClient) []string {
+	compartments := map[string]bool{}
+	for _, node := range o.Clientset.GetAllNodes() {
+		if node.SpecProviderID == "" {
+			continue
+		}
+		details, err := o.getInstanceDetails(ctx, client, node.SpecProviderID)
+		if err != nil {
+			log.Debugf("Oracle: could not get instance details for node %s: %s", node.Name, err)
+			continue
+		}
+		if details.compartmentID != "" {
+			compartments[details.compartmentID] = true
+		}
+	}
+	var result []string
+	for compartmentID := range compartments {
+		result = append(result, compartmentID)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// getResourceClient lazily creates a resourceClient authorized by the instance
+// principal of the node running OpenCost. Failures are remembered, so nodes
+// without an instance principal do not retry on every pricing run.
+func (o *Oracle) getResourceClient() (resourceClient, error) {
+	o.instanceLock.Lock()
+	defer o.instanceLock.Unlock()
+	if o.resourceClient != nil || o.resourceClientErr != nil {
+		return o.resourceClient, o.resourceClientErr
+	}
+	configProvider, err := auth.InstancePrincipalConfigurationProvider()
+	if err != nil {
+		o.resourceClientErr = fmt.Errorf("creating OCI instance principal configuration: %w", err)
+		return nil, o.resourceClientErr
+	}
+	client, err := newSDKResourceClient(configProvider, o.ClusterRegion)
+	if err != nil {
+		o.resourceClientErr = err
+		return nil, err
+	}
+	o.resourceClient = client
+	return client, nil
+}
+
+// sdkResourceClient implements resourceClient with the OCI SDK.
+type sdkResourceClient struct {
+	compute         core.ComputeClient
+	blockstorage    core.BlockstorageClient
+	virtualNetwork  core.VirtualNetworkClient
+	containerEngine containerengine.ContainerEngineClient
+}
+
+func newSDKResourceClient(configProvider common.ConfigurationProvider, region string) (*sdkResourceClient, error) {
+	compute, err := core.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("creating OCI compute client: %w", err)
+	}
+	blockstorage, err := core.NewBlockstorageClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("creating OCI block storage client: %w", err)
+	}
+	virtualNetwork, err := core.NewVirtualNetworkClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("creating OCI virtual network client: %w", err)
+	}
+	containerEngine, err := containerengine.NewContainerEngineClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("creating OCI container engine client: %w", err)
+	}
+	if region != "" {
+		compute.SetRegion(region)
+		blockstorage.SetRegion(region)
+		virtualNetwork.SetRegion(region)
+		containerEngine.SetRegion(region)
+	}
+	return &sdkResourceClient{
+		compute:         compute,
+		blockstorage:    blockstorage,
+		virtualNetwork:  virtualNetwork,
+		containerEngine: containerEngine,
+	}, nil
+}
+
+func (c *sdkResourceClient) GetNodePoolClusterID(ctx context.Context, nodePoolID string) (string, error) {
+	resp, err := c.containerEngine.GetNodePool(ctx, containerengine.GetNodePoolRequest{NodePoolId: &nodePoolID})
+	if err != nil {
+		return "", fmt.Errorf("getting OKE node pool %s: %w", nodePoolID, err)
+	}
+	return stringValue(resp.ClusterId), nil
+}
+
+func (c *sdkResourceClient) GetClusterType(ctx context.Context, clusterID string) (string, error) {
+	resp, err := c.containerEngine.GetCluster(ctx, containerengine.GetClusterRequest{ClusterId: &clusterID})
+	if err != nil {
+		return "", fmt.Errorf("getting OKE cluster %s: %w", clusterID, err)
+	}
+	return string(resp.Type), nil
+}
+
+func (c *sdkResourceClient) GetInstance(ctx context.Context, instanceID string) (*core.Instance, error) {
+	resp, err := c.compute.GetInstance(ctx, core.GetInstanceRequest{InstanceId: &instanceID})
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Instance, nil
+}
+
+func (c *sdkResourceClient) ListVolumes(ctx context.Context, compartmentID string) ([]core.Volume, error) {
+	var volumes []core.Volume
+	req := core.ListVolumesRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.blockstorage.ListVolumes(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("listing OCI volumes: %w", err)
+		}
+		volumes = append(volumes, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return volumes, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *sdkResourceClient) ListVolumeAttachments(ctx context.Context, compartmentID string) ([]core.VolumeAttachment, error) {
+	var attachments []core.VolumeAttachment
+	req := core.ListVolumeAttachmentsRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.compute.ListVolumeAttachments(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("listing OCI volume attachments: %w", err)
+		}
+		attachments = append(attachments, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return attachments, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *sdkResourceClient) ListBootVolumes(ctx context.Context, compartmentID string) ([]core.BootVolume, error) {
+	var bootVolumes []core.BootVolume
+	req := core.ListBootVolumesRequest{CompartmentId: &compartmentID}
+	for {
+		resp, err := c.blockstorage.ListBootVolumes(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("listing OCI boot volumes: %w", err)
+		}
+		bootVolumes = append(bootVolumes, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return bootVolumes, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *sdkResourceClient) ListBootVolumeAttachments(ctx context.Context, compartmentID, availabilityDomain string) ([]core.BootVolumeAttachment, error) {
+	var attachments []core.BootVolumeAttachment
+	req := core.ListBootVolumeAttachmentsRequest{
+		CompartmentId:      &compartmentID,
+		AvailabilityDomain: &availabilityDomain,
+	}
+	for {
+		resp, err := c.compute.ListBootVolumeAttachments(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("listing OCI boot volume attachments: %w", err)
+		}
+		attachments = append(attachments, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return attachments, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
+
+func (c *sdkResourceClient) ListReservedPublicIPs(ctx context.Context, compartmentID string) ([]core.PublicIp, error) {
+	var ips []core.PublicIp
+	req := core.ListPublicIpsRequest{
+		Scope:         core.ListPublicIpsScopeRegion,
+		CompartmentId: &compartmentID,
+		Lifetime:      core.ListPublicIpsLifetimeReserved,
+	}
+	for {
+		resp, err := c.virtualNetwork.ListPublicIps(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("listing OCI reserved public IPs: %w", err)
+		}
+		ips = append(ips, resp.Items...)
+		if resp.OpcNextPage == nil {
+			return ips, nil
+		}
+		req.Page = resp.OpcNextPage
+	}
+}
@@ -0,0 +1,278 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/opencost/opencost/core/pkg/clustercache"
+	"github.com/opencost/opencost/pkg/cloud/models"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNodeCache struct {
+	clustercache.ClusterCache
+	nodes []*clustercache.Node
+}
+
+func (f fakeNodeCache) GetAllNodes() []*clustercache.Node {
+	return f.nodes
+}
+
+type fakeResourceClient struct {
+	nodePoolClusters       map[string]string
+	clusterTypes           map[string]string
+	instances              map[string]core.Instance
+	volumes                []core.Volume
+	volumeAttachments      []core.VolumeAttachment
+	bootVolumes            []core.BootVolume
+	bootVolumeAttachments  []core.BootVolumeAttachment
+	publicIPs              []core.PublicIp
+	getInstanceCalls       int
+	bootAttachmentADCalled []string
+}
+
+func (f *fakeResourceClient) GetNodePoolClusterID(_ context.Context, nodePoolID string) (string, error) {
+	clusterID, ok := f.nodePoolClusters[nodePoolID]
+	if !ok {
+		return "", fmt.Errorf("node pool %s not found", nodePoolID)
+	}
+	return clusterID, nil
+}
+
+func (f *fakeResourceClient) GetClusterType(_ context.Context, clusterID string) (string, error) {
+	clusterType, ok := f.clusterTypes[clusterID]
+	if !ok {
+		return "", fmt.Errorf("cluster %s not found", clusterID)
+	}
+	return clusterType, nil
+}
+
+func (f *fakeResourceClient) GetInstance(_ context.Context, instanceID string) (*core.Instance, error) {
+	f.getInstanceCalls++
+	instance, ok := f.instances[instanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance %s not found", instanceID)
+	}
+	return &instance, nil
+}
+
+func (f *fakeResourceClient) ListVolumes(context.Context, string) ([]core.Volume, error) {
+	return f.volumes, nil
+}
+
+func (f *fakeResourceClient) ListVolumeAttachments(context.Context, string) ([]core.VolumeAttachment, error) {
+	return f.volumeAttachments, nil
+}
+
+func (f *fakeResourceClient) ListBootVolumes(context.Context, string) ([]core.BootVolume, error) {
+	return f.bootVolumes, nil
+}
+
+func (f *fakeResourceClient) ListBootVolumeAttachments(_ context.Context, _ string, availabilityDomain string) ([]core.BootVolumeAttachment, error) {
+	f.bootAttachmentADCalled = append(f.bootAttachmentADCalled, availabilityDomain)
+	return f.bootVolumeAttachments, nil
+}
+
+func (f *fakeResourceClient) ListReservedPublicIPs(context.Context, string) ([]core.PublicIp, error) {
+	return f.publicIPs, nil
+}
+
+func testResourceOracle(client resourceClient, nodes ...*clustercache.Node) *Oracle {
+	return &Oracle{
+		Clientset:     fakeNodeCache{nodes: nodes},
+		ClusterRegion: "us-ashburn-1",
+		RateCardStore: &RateCardStore{
+			prices: map[string]Price{
+				blockVolumePartNumber:            {UnitPrice: 0.0255},
+				blockVolumePerformancePartNumber: {UnitPrice: 0.0017},
+			},
+		},
+		resourceClient: client,
+	}
+}
+
+func TestGetOrphanedResources(t *testing.T) {
+	client := &fakeResourceClient{
+		instances: map[string]core.Instance{
+			"ocid1.instance.a": {
+				Id:                 common.String("ocid1.instance.a"),
+				CompartmentId:      common.String("ocid1.compartment.a"),
+				AvailabilityDomain: common.String("AD-1"),
+			},
+		},
+		volumes: []core.Volume{
+			{
+				Id:                 common.String("ocid1.volume.attached"),
+				DisplayName:        common.String("attached"),
+				AvailabilityDomain: common.String("AD-1"),
+				LifecycleState:     core.VolumeLifecycleStateAvailable,
+				SizeInGBs:          common.Int64(50),
+			},
+			{
+				Id:                 common.String("ocid1.volume.orphaned"),
+				DisplayName:        common.String("orphaned"),
+				AvailabilityDomain: common.String("AD-1"),
+				LifecycleState:     core.VolumeLifecycleStateAvailable,
+				SizeInGBs:          common.Int64(100),
+				VpusPerGB:          common.Int64(20),
+			},
+			{
+				Id:                 common.String("ocid1.volume.terminating"),
+				AvailabilityDomain: common.String("AD-1"),
+				LifecycleState:     core.VolumeLifecycleStateTerminating,
+				SizeInGBs:          common.Int64(100),
+			},
+		},
+		volumeAttachments: []core.VolumeAttachment{
+			core.IScsiVolumeAttachment{
+				VolumeId:       common.String("ocid1.volume.attached"),
+				LifecycleState: core.VolumeAttachmentLifecycleStateAttached,
+			},
+			core.ParavirtualizedVolumeAttachment{
+				VolumeId:       common.String("ocid1.volume.orphaned"),
+				LifecycleState: core.VolumeAttachmentLifecycleStateDetached,
+			},
+		},
+		bootVolumes: []core.BootVolume{
+			{
+				Id:                 common.String("ocid1.bootvolume.attached"),
+				AvailabilityDomain: common.String("AD-1"),
+				LifecycleState:     core.BootVolumeLifecycleStateAvailable,
+				SizeInGBs:          common.Int64(47),
+			},
+			{
+				Id:                 common.String("ocid1.bootvolume.orphaned"),
+				DisplayName:        common.String("boot"),
+				AvailabilityDomain: common.String("AD-2"),
+				LifecycleState:     core.BootVolumeLifecycleStateAvailable,
+				SizeInGBs:          common.Int64(50),
+			},
+		},
+		bootVolumeAttachments: []core.BootVolumeAttachment{
+			{
+				BootVolumeId:   common.String("ocid1.bootvolume.attached"),
+				LifecycleState: core.BootVolumeAttachmentLifecycleStateAttached,
+			},
+		},
+		publicIPs: []core.PublicIp{
+			{
+				Id:               common.String("ocid1.publicip.assigned"),
+				IpAddress:        common.String("10.0.0.1"),
+				AssignedEntityId: common.String("ocid1.privateip.a"),
+				LifecycleState:   core.PublicIpLifecycleStateAssigned,
+				Lifetime:         core.PublicIpLifetimeReserved,
+			},
+			{
+				Id:             common.String("ocid1.publicip.orphaned"),
+				IpAddress:      common.String("10.0.0.2"),
+				LifecycleState: core.PublicIpLifecycleStateAvailable,
+				Lifetime:       core.PublicIpLifetimeReserved,
+			},
+		},
+	}
+	o := testResourceOracle(client,
+		&clustercache.Node{Name: "a", SpecProviderID: "ocid1.instance.a"},
+		&clustercache.Node{Name: "b", SpecProviderID: "ocid1.instance.unknown"},
+	)
+
+	resources, err := o.GetOrphanedResources()
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+	assert.ElementsMatch(t, []string{"AD-1", "AD-2"}, client.bootAttachmentADCalled)
+
+	volume := resources[0]
+	assert.Equal(t, "disk", volume.Kind)
+	assert.Equal(t, "ocid1.volume.orphaned", volume.Url)
+	assert.Equal(t, "orphaned", volume.DiskName)
+	assert.Equal(t, "blockVolume", volume.Description["type"])
+	assert.Equal(t, "ocid1.compartment.a", volume.Description["compartmentId"])
+	assert.Equal(t, int64(100), *volume.Size)
+	assert.InDelta(t, 100*(0.0255+20*0.0017), *volume.MonthlyCost, 1e-9)
+
+	bootVolume := resources[1]
+	assert.Equal(t, "ocid1.bootvolume.orphaned", bootVolume.Url)
+	assert.Equal(t, "bootVolume", bootVolume.Description["type"])
+	assert.Equal(t, "AD-2", bootVolume.Region)
+	assert.InDelta(t, 50*(0.0255+defaultVPUsPerGB*0.0017), *bootVolume.MonthlyCost, 1e-9)
+
+	address := resources[2]
+	assert.Equal(t, "address", address.Kind)
+	assert.Equal(t, "10.0.0.2", address.Address)
+	assert.Equal(t, "us-ashburn-1", address.Region)
+	assert.Equal(t, 0.0, *address.MonthlyCost)
+}
+
+func TestApplyReservedInstancePricing(t *testing.T) {
+	client := &fakeResourceClient{
+		instances: map[string]core.Instance{
+			"ocid1.instance.preemptible": {
+				PreemptibleInstanceConfig: &core.PreemptibleInstanceConfigDetails{
+					PreemptionAction: core.TerminatePreemptionAction{},
+				},
+			},
+			"ocid1.instance.burstable": {
+				ShapeConfig: &core.InstanceShapeConfig{
+					BaselineOcpuUtilization: core.InstanceShapeConfigBaselineOcpuUtilization8,
+				},
+			},
+			"ocid1.instance.reserved": {
+				CapacityReservationId: common.String("ocid1.capacityreservation.a"),
+			},
+			"ocid1.instance.ondemand": {},
+		},
+	}
+	o := testResourceOracle(client)
+
+	testNodePricing := func(providerID string) *models.Node {
+		return &models.Node{
+			ProviderID: providerID,
+			Cost:       "0.300000",
+			VCPU:       "4",
+			VCPUCost:   "0.100000",
+			RAMBytes:   "17179869184",
+			RAMCost:    "0.200000",
+		}
+	}
+	nodes := map[string]*models.Node{
+		"preemptible": testNodePricing("ocid1.instance.preemptible"),
+		"burstable":   testNodePricing("ocid1.instance.burstable"),
+		"reserved":    testNodePricing("ocid1.instance.reserved"),
+		"ondemand":    testNodePricing("ocid1.instance.ondemand"),
+		"unknown":     testNodePricing("ocid1.instance.unknown"),
+	}
+	o.ApplyReservedInstancePricing(nodes)
+
+	preemptible := nodes["preemptible"]
+	assert.True(t, preemptible.IsSpot())
+	assert.Equal(t, models.Spot, preemptible.PricingType)
+	assert.Equal(t, "0.050000", preemptible.VCPUCost)
+	assert.Equal(t, "0.100000", preemptible.RAMCost)
+	assert.Equal(t, "0.150000", preemptible.Cost)
+
+	burstable := nodes["burstable"]
+	assert.False(t, burstable.IsSpot())
+	assert.Equal(t, "0.012500", burstable.VCPUCost)
+	assert.Equal(t, "0.200000", burstable.RAMCost)
+	assert.Equal(t, "0.212500", burstable.Cost)
+
+	reserved := nodes["reserved"]
+	assert.Equal(t, models.Reserved, reserved.PricingType)
+	require.NotNil(t, reserved.Reserved)
+	assert.Equal(t, int64(4), reserved.Reserved.ReservedCPU)
+	assert.Equal(t, int64(17179869184), reserved.Reserved.ReservedRAM)
+	assert.Equal(t, 0.1, reserved.Reserved.CPUCost)
+	assert.Equal(t, "0.300000", reserved.Cost)
+
+	for _, name := range []string{"ondemand", "unknown"} {
+		assert.Equal(t, testNodePricing(nodes[name].ProviderID), nodes[name])
+	}
+
+	// Instance details are cached between pricing runs.
+	calls := client.getInstanceCalls
+	o.ApplyReservedInstancePricing(nodes)
+	assert.Equal(t, calls+1, client.getInstanceCalls)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/opencost/opencost/core/pkg/opencost"
@@ -12,6 +13,23 @@ import (
 	"github.com/oracle/oci-go-sdk/v65/usageapi"
 )
 
+// Labels with which the compartment of each cloud cost is recorded
+const (
+	CompartmentIDLabel   = "oci_compartment_id"
+	CompartmentNameLabel = "oci_compartment_name"
+)
+
+// usageApiPageLimit is the number of items requested per page of summarized usages
+const usageApiPageLimit = 500
+
+// freeformTagNamespace is the namespace reported by the usage api for freeform tags
+const freeformTagNamespace = "None"
+
+// usageApiClient is the subset of usageapi.UsageapiClient used by the integration
+type usageApiClient interface {
+	RequestSummarizedUsages(ctx context.Context, request usageapi.RequestSummarizedUsagesRequest) (usageapi.RequestSummarizedUsagesResponse, error)
+}
+
 type UsageApiIntegration struct {
 	UsageApiConfiguration
 	ConnectionStatus cloud.ConnectionStatus
@@ -24,20 +42,29 @@ func (uai *UsageApiIntegration) GetCloudCost(start time.Time, end time.Time) (*o
 		return nil, fmt.Errorf("getting oracle usage api client: %s", err.Error())
 	}
 
-	req := usageapi.RequestSummarizedUsagesRequest{
-		RequestSummarizedUsagesDetails: usageapi.RequestSummarizedUsagesDetails{
-			Granularity:       usageapi.RequestSummarizedUsagesDetailsGranularityDaily,
-			GroupBy:           []string{"resourceId", "service", "subscriptionId", "tenantName"},
-			IsAggregateByTime: common.Bool(false),
-			TimeUsageStarted:  &common.SDKTime{Time: start},
-			TimeUsageEnded:    &common.SDKTime{Time: end},
-			QueryType:         usageapi.RequestSummarizedUsagesDetailsQueryTypeCost,
-			TenantId:          common.String(uai.TenancyID),
-		},
-		Limit: common.Int(500),
-	}
+	return uai.getCloudCost(context.Background(), client, start, end)
+}
 
-	resp, err := client.RequestSummarizedUsages(context.Background(), req)
+// getCloudCost queries the daily cost of each resource, by SKU so that list costs can be computed from SKU rates,
+// and labels it with the tags of the resource, which are queried separately as grouping costs by tag would count
+// the cost of a resource once for each of its tags.
+//
+// Costs are mapped as follows:
+//   - ListCost is the SKU list rate multiplied by the usage quantity, before any discount
+//   - NetCost and InvoicedCost are the computed amount, which is the cost after discounts
+//   - AmortizedNetCost and AmortizedCost are the attributed cost, which spreads commitments over the resources that
+//     consume them, or the computed amount if there is no attributed cost. The usage api does not report an
+//     amortized cost before discounts.
+func (uai *UsageApiIntegration) getCloudCost(ctx context.Context, client usageApiClient, start time.Time, end time.Time) (*opencost.CloudCostSetRange, error) {
+	items, err := requestAllSummarizedUsages(ctx, client, usageapi.RequestSummarizedUsagesDetails{
+		Granularity:       usageapi.RequestSummarizedUsagesDetailsGranularityDaily,
+		GroupBy:           []string{"resourceId", "service", "skuPartNumber", "subscriptionId", "tenantName", "compartmentId", "compartmentName", "region"},
+		IsAggregateByTime: common.Bool(false),
+		TimeUsageStarted:  &common.SDKTime{Time: start},
+		TimeUsageEnded:    &common.SDKTime{Time: end},
+		QueryType:         usageapi.RequestSummarizedUsagesDetailsQueryTypeCost,
+		TenantId:          common.String(uai.TenancyID),
+	})
 	if err != nil {
 		uai.ConnectionStatus = cloud.FailedConnection
 		return nil, fmt.Errorf("failed to query usage: %w", err)
@@ -49,105 +76,183 @@ func (uai *UsageApiIntegration) GetCloudCost(start time.Time, end time.Time) (*o
 	}
 
 	// Set status to missing data if query comes back empty and the status isn't already successful
-	if len(resp.Items) == 0 && uai.ConnectionStatus != cloud.SuccessfulConnection {
+	if len(items) == 0 && uai.ConnectionStatus != cloud.SuccessfulConnection {
 		uai.ConnectionStatus = cloud.MissingData
 		return ccsr, nil
 	}
 
-	for _, item := range resp.Items {
-		resourceId := ""
-		if item.ResourceId != nil {
-			resourceId = *item.ResourceId
-		}
+	tags, err := uai.getResourceTags(ctx, client, start, end)
+	if err != nil {
+		uai.ConnectionStatus = cloud.FailedConnection
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
 
-		tenantName := ""
-		if item.TenantName != nil {
-			tenantName = *item.TenantName
+	for _, item := range items {
+		if item.IsForecast != nil && *item.IsForecast {
+			continue
 		}
 
-		subscriptionId := ""
-		if item.SubscriptionId != nil {
-			subscriptionId = *item.SubscriptionId
+		cc, err := uai.toCloudCost(item, tags)
+		if err != nil {
+			return nil, err
 		}
 
-		service := ""
-		if item.Service != nil {
-			service = *item.Service
-		}
+		ccsr.LoadCloudCost(cc)
+	}
+
+	uai.ConnectionStatus = cloud.SuccessfulConnection
+	return ccsr, nil
+}
+
+// getResourceTags returns the defined and freeform tags of each resource with usage in the window, keyed on
+// resource id. Defined tags are labelled "<namespace>.<key>", and freeform tags by their key.
+func (uai *UsageApiIntegration) getResourceTags(ctx context.Context, client usageApiClient, start time.Time, end time.Time) (map[string]opencost.CloudCostLabels, error) {
+	items, err := requestAllSummarizedUsages(ctx, client, usageapi.RequestSummarizedUsagesDetails{
+		Granularity:       usageapi.RequestSummarizedUsagesDetailsGranularityDaily,
+		GroupBy:           []string{"resourceId", "tagNamespace", "tagKey", "tagValue"},
+		IsAggregateByTime: common.Bool(true),
+		TimeUsageStarted:  &common.SDKTime{Time: start},
+		TimeUsageEnded:    &common.SDKTime{Time: end},
+		QueryType:         usageapi.RequestSummarizedUsagesDetailsQueryTypeCost,
+		TenantId:          common.String(uai.TenancyID),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		category := SelectOCICategory(service)
+	tags := map[string]opencost.CloudCostLabels{}
+	for _, item := range items {
+		resourceId := stringValue(item.ResourceId)
+		if resourceId == "" {
+			continue
+		}
 
-		// Iterate through the slice of tags, assigning
-		// keys and values to the map of labels
-		labels := opencost.CloudCostLabels{}
 		for _, tag := range item.Tags {
-			if tag.Key == nil || tag.Value == nil {
+			if tag.Key == nil || *tag.Key == "" || tag.Value == nil {
 				continue
 			}
-			labels[*tag.Key] = *tag.Value
-		}
 
-		properties := &opencost.CloudCostProperties{
-			ProviderID:      resourceId,
-			Provider:        opencost.OracleProvider,
-			AccountID:       uai.TenancyID,
-			AccountName:     tenantName,
-			InvoiceEntityID: subscriptionId,
-			RegionID:        uai.Region,
-			Service:         service,
-			Category:        category,
-			Labels:          labels,
+			key := *tag.Key
+			if namespace := stringValue(tag.Namespace); namespace != "" && namespace != freeformTagNamespace {
+				key = namespace + "." + key
+			}
+
+			if _, ok := tags[resourceId]; !ok {
+				tags[resourceId] = opencost.CloudCostLabels{}
+			}
+			tags[resourceId][key] = *tag.Value
 		}
+	}
 
-		winStart := item.TimeUsageStarted.Time
-		winEnd := start.AddDate(0, 0, 1)
+	return tags, nil
+}
 
-		listRate := 0.0
-		if item.ListRate != nil {
-			listRate = float64(*item.ListRate)
-		}
+func (uai *UsageApiIntegration) toCloudCost(item usageapi.UsageSummary, tags map[string]opencost.CloudCostLabels) (*opencost.CloudCost, error) {
+	resourceId := stringValue(item.ResourceId)
+	service := stringValue(item.Service)
 
-		attrCostToParse := ""
-		if item.AttributedCost != nil {
-			attrCostToParse = *item.AttributedCost
-		}
+	labels := opencost.CloudCostLabels{}
+	for k, v := range tags[resourceId] {
+		labels[k] = v
+	}
+	if compartmentId := stringValue(item.CompartmentId); compartmentId != "" {
+		labels[CompartmentIDLabel] = compartmentId
+	}
+	if compartmentName := stringValue(item.CompartmentName); compartmentName != "" {
+		labels[CompartmentNameLabel] = compartmentName
+	}
+
+	region := stringValue(item.Region)
+	if region == "" {
+		region = uai.Region
+	}
+
+	properties := &opencost.CloudCostProperties{
+		ProviderID:      resourceId,
+		Provider:        opencost.OracleProvider,
+		AccountID:       uai.TenancyID,
+		AccountName:     stringValue(item.TenantName),
+		InvoiceEntityID: stringValue(item.SubscriptionId),
+		RegionID:        region,
+		Service:         service,
+		Category:        SelectOCICategory(service),
+		Labels:          labels,
+	}
+
+	if item.TimeUsageStarted == nil {
+		return nil, fmt.Errorf("usage item for resource '%s' has no start time", resourceId)
+	}
+	winStart := item.TimeUsageStarted.Time.UTC().Truncate(24 * time.Hour)
+	winEnd := winStart.AddDate(0, 0, 1)
+
+	netCost := 0.0
+	if item.ComputedAmount != nil {
+		netCost = float64(*item.ComputedAmount)
+	}
+
+	listCost := netCost
+	if item.ListRate != nil && item.ComputedQuantity != nil {
+		listCost = float64(*item.ListRate) * float64(*item.ComputedQuantity)
+	}
 
-		attrCost, err := strconv.ParseFloat(attrCostToParse, 64)
+	amortizedCost := netCost
+	if attributedCost := stringValue(item.AttributedCost); attributedCost != "" {
+		var err error
+		amortizedCost, err = strconv.ParseFloat(attributedCost, 64)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse float '%s': %s", attrCostToParse, err.Error())
+			return nil, fmt.Errorf("unable to parse float '%s': %s", attributedCost, err.Error())
 		}
+	}
 
-		computedAmt := 0.0
-		if item.ComputedAmount != nil {
-			computedAmt = float64(*item.ComputedAmount)
-		}
+	return &opencost.CloudCost{
+		Properties: properties,
+		Window:     opencost.NewWindow(&winStart, &winEnd),
+		ListCost: opencost.CostMetric{
+			Cost: listCost,
+		},
+		NetCost: opencost.CostMetric{
+			Cost: netCost,
+		},
+		AmortizedNetCost: opencost.CostMetric{
+			Cost: amortizedCost,
+		},
+		AmortizedCost: opencost.CostMetric{
+			Cost: amortizedCost,
+		},
+		InvoicedCost: opencost.CostMetric{
+			Cost: netCost,
+		},
+	}, nil
+}
 
-		cc := &opencost.CloudCost{
-			Properties: properties,
-			Window:     opencost.NewWindow(&winStart, &winEnd),
-			//todo: which returned costs go where?
-			ListCost: opencost.CostMetric{
-				Cost: listRate,
-			},
-			NetCost: opencost.CostMetric{
-				Cost: computedAmt,
-			},
-			AmortizedNetCost: opencost.CostMetric{
-				Cost: attrCost,
-			},
-			AmortizedCost: opencost.CostMetric{
-				Cost: attrCost,
-			},
-			InvoicedCost: opencost.CostMetric{
-				Cost: computedAmt,
-			},
+// requestAllSummarizedUsages requests every page of the summarized usages matching the given details
+func requestAllSummarizedUsages(ctx context.Context, client usageApiClient, details usageapi.RequestSummarizedUsagesDetails) ([]usageapi.UsageSummary, error) {
+	var items []usageapi.UsageSummary
+	var page *string
+	for {
+		resp, err := client.RequestSummarizedUsages(ctx, usageapi.RequestSummarizedUsagesRequest{
+			RequestSummarizedUsagesDetails: details,
+			Limit:                          common.Int(usageApiPageLimit),
+			Page:                           page,
+		})
+		if err != nil {
+			return nil, err
 		}
 
-		ccsr.LoadCloudCost(cc)
+		items = append(items, resp.Items...)
+
+		if resp.OpcNextPage == nil || *resp.OpcNextPage == "" {
+			return items, nil
+		}
+		page = resp.OpcNextPage
 	}
+}
 
-	uai.ConnectionStatus = cloud.SuccessfulConnection
-	return ccsr, nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
 }
 
 func (uai *UsageApiIntegration) GetStatus() cloud.ConnectionStatus {
@@ -158,12 +263,15 @@ func (uai *UsageApiIntegration) GetStatus() cloud.ConnectionStatus {
 	return uai.ConnectionStatus
 }
 
+// SelectOCICategory returns the category of an OCI service, as named by the usage api ("Block Storage") or the cost
+// reports ("BLOCK_STORAGE")
 func SelectOCICategory(service string) string {
-	if service == "Compute" {
+	service = strings.ToLower(strings.ReplaceAll(service, "_", " "))
+	if service == "compute" {
 		return opencost.ComputeCategory
-	} else if service == "Block Storage" || service == "Object Storage" {
+	} else if service == "block storage" || service == "object storage" {
 		return opencost.StorageCategory
-	} else if service == "Load Balancer" || service == "Virtual Cloud Network" {
+	} else if service == "load balancer" || service == "virtual cloud network" {
 		return opencost.NetworkCategory
 	} else {
 		return opencost.OtherCategory
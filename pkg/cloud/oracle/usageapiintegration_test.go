@@ -1,12 +1,18 @@
 package oracle
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/util/timeutil"
+	"github.com/opencost/opencost/pkg/cloud"
+	"github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
 )
 
 func TestUsageAPIIntegration_GetCloudCost(t *testing.T) {
@@ -59,3 +65,131 @@ func TestUsageAPIIntegration_GetCloudCost(t *testing.T) {
 		})
 	}
 }
+
+// mockUsageApiClient returns its cost and tag items one per page
+type mockUsageApiClient struct {
+	costItems []usageapi.UsageSummary
+	tagItems  []usageapi.UsageSummary
+	requests  int
+}
+
+func (m *mockUsageApiClient) RequestSummarizedUsages(_ context.Context, req usageapi.RequestSummarizedUsagesRequest) (usageapi.RequestSummarizedUsagesResponse, error) {
+	m.requests++
+
+	items := m.costItems
+	for _, groupBy := range req.GroupBy {
+		if groupBy == "tagKey" {
+			items = m.tagItems
+		}
+	}
+
+	page := 0
+	if req.Page != nil {
+		fmt.Sscanf(*req.Page, "%d", &page)
+	}
+
+	resp := usageapi.RequestSummarizedUsagesResponse{}
+	if page < len(items) {
+		resp.Items = []usageapi.UsageSummary{items[page]}
+	}
+	if page+1 < len(items) {
+		resp.OpcNextPage = common.String(fmt.Sprintf("%d", page+1))
+	}
+	return resp, nil
+}
+
+func TestUsageAPIIntegration_getCloudCost(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 2)
+
+	usage := func(day int, resourceId, region string, listRate, quantity, amount float32, attributed string) usageapi.UsageSummary {
+		dayStart := start.AddDate(0, 0, day)
+		return usageapi.UsageSummary{
+			TimeUsageStarted: &common.SDKTime{Time: dayStart},
+			TimeUsageEnded:   &common.SDKTime{Time: dayStart.AddDate(0, 0, 1)},
+			ResourceId:       common.String(resourceId),
+			Service:          common.String("Compute"),
+			TenantName:       common.String("tenant"),
+			SubscriptionId:   common.String("subscription"),
+			CompartmentId:    common.String("ocid1.compartment.oc1..aaa"),
+			CompartmentName:  common.String("dev"),
+			Region:           common.String(region),
+			ListRate:         common.Float32(listRate),
+			ComputedQuantity: common.Float32(quantity),
+			ComputedAmount:   common.Float32(amount),
+			AttributedCost:   common.String(attributed),
+		}
+	}
+
+	client := &mockUsageApiClient{
+		costItems: []usageapi.UsageSummary{
+			// Two SKUs of one instance on the first day
+			usage(0, "instance", "us-ashburn-1", 1, 10, 8, "7"),
+			usage(0, "instance", "us-ashburn-1", 0.5, 4, 2, ""),
+			usage(1, "instance", "us-ashburn-1", 1, 10, 8, "7"),
+			usage(0, "bucket", "uk-london-1", 1, 1, 1, ""),
+		},
+		tagItems: []usageapi.UsageSummary{
+			{ResourceId: common.String("instance"), Tags: []usageapi.Tag{{Namespace: common.String("Operations"), Key: common.String("CostCenter"), Value: common.String("42")}}},
+			{ResourceId: common.String("instance"), Tags: []usageapi.Tag{{Namespace: common.String("None"), Key: common.String("team"), Value: common.String("search")}}},
+		},
+	}
+
+	uai := &UsageApiIntegration{UsageApiConfiguration: UsageApiConfiguration{TenancyID: "tenancy", Region: "us-phoenix-1"}}
+	ccsr, err := uai.getCloudCost(context.Background(), client, start, end)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if client.requests != 6 {
+		t.Errorf("expected 6 paged requests, got %d", client.requests)
+	}
+	if uai.ConnectionStatus != cloud.SuccessfulConnection {
+		t.Errorf("expected successful connection, got %s", uai.ConnectionStatus)
+	}
+	if len(ccsr.CloudCostSets) != 2 {
+		t.Fatalf("expected 2 daily sets, got %d", len(ccsr.CloudCostSets))
+	}
+
+	var instance, bucket *opencost.CloudCost
+	for _, cc := range ccsr.CloudCostSets[0].CloudCosts {
+		switch cc.Properties.ProviderID {
+		case "instance":
+			instance = cc
+		case "bucket":
+			bucket = cc
+		}
+	}
+	if instance == nil || bucket == nil {
+		t.Fatalf("expected instance and bucket cloud costs on the first day")
+	}
+
+	if instance.ListCost.Cost != 12 {
+		t.Errorf("expected instance list cost 12, got %f", instance.ListCost.Cost)
+	}
+	if instance.NetCost.Cost != 10 || instance.InvoicedCost.Cost != 10 {
+		t.Errorf("expected instance net and invoiced cost 10, got %f and %f", instance.NetCost.Cost, instance.InvoicedCost.Cost)
+	}
+	if instance.AmortizedNetCost.Cost != 9 {
+		t.Errorf("expected instance amortized net cost 9, got %f", instance.AmortizedNetCost.Cost)
+	}
+
+	expectedLabels := opencost.CloudCostLabels{
+		"Operations.CostCenter": "42",
+		"team":                  "search",
+		CompartmentIDLabel:      "ocid1.compartment.oc1..aaa",
+		CompartmentNameLabel:    "dev",
+	}
+	for k, v := range expectedLabels {
+		if instance.Properties.Labels[k] != v {
+			t.Errorf("expected instance label %s=%s, got %s", k, v, instance.Properties.Labels[k])
+		}
+	}
+	if _, ok := bucket.Properties.Labels["team"]; ok {
+		t.Errorf("expected bucket to have no tags")
+	}
+
+	if instance.Properties.RegionID != "us-ashburn-1" || bucket.Properties.RegionID != "uk-london-1" {
+		t.Errorf("expected per item regions, got %s and %s", instance.Properties.RegionID, bucket.Properties.RegionID)
+	}
+}
@@ -18,6 +18,7 @@ type IngestionManager struct {
 	ingestors map[string]*ingestor
 	config    IngestorConfig
 	repo      Repository
+	standby   bool
 }
 
 // NewIngestionManager creates a new IngestionManager and registers it with the provided integration controller
@@ -33,6 +34,7 @@ func NewIngestionManager(controller *config.Controller, repo Repository, ingConf
 		ingestors: map[string]*ingestor{},
 		repo:      repo,
 		config:    ingConf,
+		standby:   ingConf.Standby,
 	}
 	controller.RegisterObserver(im)
 
@@ -73,9 +75,12 @@ func (im *IngestionManager) SetConfigs(configs map[string]cloud.KeyedConfig) {
 	}
 }
 
+// StartAll starts every ingestor, and takes the IngestionManager out of standby so that ingestors created afterwards
+// are started too
 func (im *IngestionManager) StartAll() {
 	im.lock.Lock()
 	defer im.lock.Unlock()
+	im.standby = false
 	var wg sync.WaitGroup
 	wg.Add(len(im.ingestors))
 	for key := range im.ingestors {
@@ -89,9 +94,12 @@ func (im *IngestionManager) StartAll() {
 	wg.Wait()
 }
 
+// StopAll stops every ingestor, and puts the IngestionManager in standby so that ingestors created afterwards are not
+// started until StartAll is called
 func (im *IngestionManager) StopAll() {
 	im.lock.Lock()
 	defer im.lock.Unlock()
+	im.standby = true
 	var wg sync.WaitGroup
 	wg.Add(len(im.ingestors))
 	for key := range im.ingestors {
@@ -104,6 +112,13 @@ func (im *IngestionManager) StopAll() {
 	wg.Wait()
 }
 
+// IsStandby returns true if the ingestors are stopped because this replica is not the leader
+func (im *IngestionManager) IsStandby() bool {
+	im.lock.Lock()
+	defer im.lock.Unlock()
+	return im.standby
+}
+
 func (im *IngestionManager) RebuildAll() {
 	im.lock.Lock()
 	defer im.lock.Unlock()
@@ -199,7 +214,8 @@ func (im *IngestionManager) deleteIngestor(integrationKey string) {
 	delete(im.ingestors, integrationKey)
 }
 
-// createIngestor stops existing ingestor with matching key then creates and starts and new ingestor
+// createIngestor stops existing ingestor with matching key then creates and starts and new ingestor, unless the
+// IngestionManager is in standby
 func (im *IngestionManager) createIngestor(config cloud.KeyedConfig) error {
 	if config == nil {
 		return fmt.Errorf("cannot create ingestor from nil integration")
@@ -212,7 +228,9 @@ func (im *IngestionManager) createIngestor(config cloud.KeyedConfig) error {
 		return fmt.Errorf("IngestionManager: createIngestor: %w", err)
 	}
 
-	ing.Start(false)
+	if !im.standby {
+		ing.Start(false)
+	}
 
 	im.ingestors[config.Key()] = ing
 
@@ -1,6 +1,7 @@
 package cloudcost
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -13,6 +14,10 @@ import (
 	"github.com/opencost/opencost/core/pkg/util/timeutil"
 	"github.com/opencost/opencost/pkg/cloud"
 	"github.com/opencost/opencost/pkg/env"
+	"github.com/opencost/opencost/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // IngestorStatus includes diagnostic values for a given Ingestor
@@ -33,6 +38,8 @@ type IngestorConfig struct {
 	Duration               time.Duration
 	QueryWindow            time.Duration
 	RunWindow              time.Duration
+	// Standby creates ingestors without starting them, so that they only run once started by the leader replica
+	Standby bool
 }
 
 // DefaultIngestorConfiguration retrieves an IngestorConfig from env variables
@@ -59,6 +66,7 @@ type ingestor struct {
 	creationTime time.Time
 	coverage     opencost.Window
 	coverageLock sync.Mutex
+	ingestion    *telemetry.IngestionTracker
 	isRunning    atomic.Bool
 	isStopping   atomic.Bool
 	exitBuildCh  chan string
@@ -87,10 +95,15 @@ func NewIngestor(ingestorConfig IngestorConfig, repo Repository, config cloud.Ke
 		creationTime: now,
 		lastRun:      now,
 		coverage:     opencost.NewClosedWindow(midnight, midnight),
+		ingestion:    telemetry.NewIngestionTracker("cloudcost", ingestorConfig.Resolution),
 	}, nil
 }
 
 func (ing *ingestor) LoadWindow(start, end time.Time) {
+	ing.loadWindow(context.Background(), start, end)
+}
+
+func (ing *ingestor) loadWindow(ctx context.Context, start, end time.Time) {
 	windows, err := opencost.GetWindows(start, end, timeutil.Day)
 	if err != nil {
 		log.Errorf("CloudCost[%s]: ingestor: invalid window %s", ing.key, opencost.NewWindow(&start, &end))
@@ -103,7 +116,7 @@ func (ing *ingestor) LoadWindow(start, end time.Time) {
 			log.Errorf("CloudCost[%s]: ingestor: error when loading window: %s", ing.key, err2.Error())
 		}
 		if !has {
-			ing.BuildWindow(start, end)
+			ing.buildWindow(ctx, start, end)
 			return
 		}
 		ing.expandCoverage(window)
@@ -113,20 +126,39 @@ func (ing *ingestor) LoadWindow(start, end time.Time) {
 }
 
 func (ing *ingestor) BuildWindow(start, end time.Time) {
+	ing.buildWindow(context.Background(), start, end)
+}
+
+// buildWindow builds the window in a span which is a child of the span on the given context
+func (ing *ingestor) buildWindow(ctx context.Context, start, end time.Time) {
+	_, span := telemetry.StartBuild(ctx, tracerName, "CloudCost.BuildWindow", start, end, attribute.String("integration", ing.key))
+	defer span.End()
+
 	log.Infof("CloudCost[%s]: ingestor: building window %s", ing.key, opencost.NewWindow(&start, &end))
 	ccsr, err := ing.integration.GetCloudCost(start, end)
 	if err != nil {
 		log.Errorf("CloudCost[%s]: ingestor: build failed for window %s: %s", ing.key, opencost.NewWindow(&start, &end), err.Error())
+		telemetry.RecordError(span, err)
 		return
 	}
+	saved := 0
 	for _, ccs := range ccsr.CloudCostSets {
 		log.Debugf("BuildWindow[%s]: GetCloudCost: writing cloud costs for window %s: %d", ccs.Integration, ccs.Window, len(ccs.CloudCosts))
 		err2 := ing.repo.Put(ccs)
 		if err2 != nil {
 			log.Errorf("CloudCost[%s]: ingestor: failed to save Cloud Cost Set with window %s: %s", ing.key, ccs.GetWindow().String(), err2.Error())
+			telemetry.RecordError(span, err2)
+		} else {
+			saved++
 		}
 		ing.expandCoverage(ccs.Window)
 	}
+	span.SetAttributes(attribute.Int("cloudcost.sets", len(ccsr.CloudCostSets)))
+
+	// Only saved sets count as ingested, so that failing saves and empty responses are reported as lag
+	if saved > 0 {
+		ing.ingestion.Ingested(ing.key)
+	}
 }
 
 func (ing *ingestor) Start(rebuild bool) {
@@ -142,6 +174,8 @@ func (ing *ingestor) Start(rebuild bool) {
 	ing.exitBuildCh = make(chan string)
 	ing.exitRunCh = make(chan string)
 
+	ing.ingestion.Track(ing.key)
+
 	// Build the store once, advancing backward in time from the earliest
 	// point of coverage.
 	go ing.build(rebuild)
@@ -150,6 +184,11 @@ func (ing *ingestor) Start(rebuild bool) {
 }
 
 func (ing *ingestor) Stop() {
+	// If the ingestor is not running, there are no processes to stop
+	if !ing.isRunning.Load() {
+		return
+	}
+
 	// If already stopping, log that and return.
 	if !ing.isStopping.CompareAndSwap(false, true) {
 		log.Infof("CloudCost: ingestor: is already stopping")
@@ -180,6 +219,8 @@ func (ing *ingestor) Stop() {
 
 	wg.Wait()
 
+	ing.ingestion.Untrack()
+
 	// Declare that the store is officially no longer running. This allows
 	// Start to be called again, restarting the store from scratch.
 	ing.isRunning.Store(false)
@@ -208,6 +249,11 @@ func (ing *ingestor) build(rebuild bool) {
 	limit := opencost.RoundBack(time.Now().UTC().Add(-ing.config.Duration), ing.config.Resolution)
 
 	queryWindowStr := timeutil.FormatStoreResolution(ing.config.QueryWindow)
+
+	ctx, span := otel.Tracer(tracerName).Start(context.Background(), "CloudCost.build", trace.WithAttributes(
+		attribute.String("integration", ing.key),
+		attribute.Bool("rebuild", rebuild),
+	))
 	log.Infof("CloudCost[%s]: ingestor: build[%s]: Starting build back to %s in blocks of %s", ing.key, ing.runID, limit.String(), queryWindowStr)
 
 	// Start with a window of the configured Duration and ending on the given
@@ -225,6 +271,7 @@ func (ing *ingestor) build(rebuild bool) {
 		select {
 		case <-ing.exitBuildCh:
 			log.Debugf("CloudCost[%s]: ingestor: build[%s]: exiting", ing.key, ing.runID)
+			span.End()
 			return
 		default:
 		}
@@ -234,9 +281,9 @@ func (ing *ingestor) build(rebuild bool) {
 
 		// if rebuild is not specified then check for existing coverage on window
 		if rebuild {
-			ing.BuildWindow(s, e)
+			ing.buildWindow(ctx, s, e)
 		} else {
-			ing.LoadWindow(s, e)
+			ing.loadWindow(ctx, s, e)
 		}
 
 		log.Infof("CloudCost[%s]: ingestor: build[%s]:  %s in %v", ing.key, ing.runID, opencost.NewClosedWindow(s, e), time.Since(stepStart))
@@ -250,6 +297,7 @@ func (ing *ingestor) build(rebuild bool) {
 	}
 
 	log.Infof("CloudCost[%s]: ingestor: build[%s]: completed in %v", ing.key, ing.runID, time.Since(buildStart))
+	span.End()
 
 	// In order to be able to Stop, we have to wait on an exit message
 	// here
@@ -274,6 +322,11 @@ func (ing *ingestor) run() {
 			// Wait for next tick
 		}
 
+		ctx, span := otel.Tracer(tracerName).Start(context.Background(), "CloudCost.run", trace.WithAttributes(
+			attribute.String("integration", ing.key),
+			attribute.Int("run", ing.runs),
+		))
+
 		// Start from the last covered time, minus the RunWindow
 		start := ing.lastRun
 		start = start.Add(-ing.config.RunWindow)
@@ -298,7 +351,7 @@ func (ing *ingestor) run() {
 		// 2. Move window forward one Resolution
 		for time.Now().After(s) {
 			profStart := time.Now()
-			ing.BuildWindow(s, e)
+			ing.buildWindow(ctx, s, e)
 
 			log.Debugf("CloudCost[%s]: ingestor: Run[%s]: completed %s in %v", ing.key, ing.runID, opencost.NewWindow(&s, &e), time.Since(profStart))
 
@@ -323,6 +376,7 @@ func (ing *ingestor) run() {
 		ing.coverageLock.Unlock()
 
 		ing.runs++
+		span.End()
 
 		ticker.TickIn(ing.config.RefreshRate)
 	}
@@ -104,6 +104,10 @@ func GetIntegrationFromConfig(kc cloud.KeyedConfig) CloudCostIntegration {
 		return &oracle.UsageApiIntegration{
 			UsageApiConfiguration: *keyedConfig,
 		}
+	case *oracle.CostReportConfiguration:
+		return &oracle.CostReportIntegration{
+			CostReportConfiguration: *keyedConfig,
+		}
 	default:
 		return nil
 	}
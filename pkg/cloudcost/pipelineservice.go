@@ -32,6 +32,17 @@ func NewPipelineService(repo Repository, ic *config.Controller, ingConf Ingestor
 	}
 }
 
+// StartIngestion starts the ingestors of every billing integration, when this replica becomes the leader
+func (dp *PipelineService) StartIngestion() {
+	dp.ingestionManager.StartAll()
+}
+
+// StopIngestion stops the ingestors of every billing integration, when this replica stops leading. The repository
+// continues to serve queries.
+func (dp *PipelineService) StopIngestion() {
+	dp.ingestionManager.StopAll()
+}
+
 // Status merges status values from the config.Controller and the IngestionManager to give a combined view of that state
 // of configs and their ingestion status
 func (dp *PipelineService) Status() []Status {
@@ -94,6 +105,11 @@ func (s *PipelineService) GetCloudCostRebuildHandler() func(w http.ResponseWrite
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		w.Header().Set("Content-Type", "application/json")
 
+		if s.ingestionManager.IsStandby() {
+			http.Error(w, "Cloud Cost ingestion runs on the leader replica", http.StatusServiceUnavailable)
+			return
+		}
+
 		commit := r.URL.Query().Get("commit") == "true" || r.URL.Query().Get("commit") == "1"
 
 		if !commit {
@@ -138,6 +154,11 @@ func (s *PipelineService) GetCloudCostRepairHandler() func(w http.ResponseWriter
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		w.Header().Set("Content-Type", "application/json")
 
+		if s.ingestionManager.IsStandby() {
+			http.Error(w, "Cloud Cost ingestion runs on the leader replica", http.StatusServiceUnavailable)
+			return
+		}
+
 		windowStr := r.URL.Query().Get("window")
 
 		var window opencost.Window
@@ -181,6 +202,11 @@ func (s *PipelineService) GetCloudCostQuerier() Querier {
 	return NewRepositoryQuerier(s.store)
 }
 
+// GetConfigController returns the controller which manages the configs of the billing integrations
+func (s *PipelineService) GetConfigController() *config.Controller {
+	return s.configController
+}
+
 // GetCloudCostStatusHandler creates a handler from a http request which returns a list of the billing integration status
 func (s *PipelineService) GetCloudCostStatusHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	// If Reporting Service is nil, always return 501
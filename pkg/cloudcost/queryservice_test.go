@@ -0,0 +1,199 @@
+package cloudcost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencost/opencost/core/pkg/client"
+	"github.com/opencost/opencost/core/pkg/filter/ast"
+	cloudcostfilter "github.com/opencost/opencost/core/pkg/filter/cloudcost"
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+type stubQuerier struct {
+	request     QueryRequest
+	viewRequest ViewQueryRequest
+
+	ccsr   *opencost.CloudCostSetRange
+	graph  ViewGraphData
+	totals *ViewTotals
+	rows   ViewTableRows
+}
+
+func (s *stubQuerier) Query(_ context.Context, request QueryRequest) (*opencost.CloudCostSetRange, error) {
+	s.request = request
+	return s.ccsr, nil
+}
+
+func (s *stubQuerier) QueryViewGraph(_ context.Context, request ViewQueryRequest) (ViewGraphData, error) {
+	s.viewRequest = request
+	return s.graph, nil
+}
+
+func (s *stubQuerier) QueryViewTotals(_ context.Context, request ViewQueryRequest) (*ViewTotals, error) {
+	s.viewRequest = request
+	return s.totals, nil
+}
+
+func (s *stubQuerier) QueryViewTable(_ context.Context, request ViewQueryRequest) (ViewTableRows, error) {
+	s.viewRequest = request
+	return s.rows, nil
+}
+
+// newQueryServiceClient serves the QueryService handlers over the given querier, and
+// returns an API client for them.
+func newQueryServiceClient(t *testing.T, querier *stubQuerier) *client.Client {
+	t.Helper()
+
+	qs := NewQueryService(querier, querier)
+	router := httprouter.New()
+	router.GET("/cloudCost", qs.GetCloudCostHandler())
+	router.GET("/cloudCost/view/graph", qs.GetCloudCostViewGraphHandler())
+	router.GET("/cloudCost/view/totals", qs.GetCloudCostViewTotalsHandler())
+	router.GET("/cloudCost/view/table", qs.GetCloudCostViewTableHandler(nil))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	c, err := client.NewClient(server.URL)
+	require.NoError(t, err)
+	return c
+}
+
+func TestQueryService_Client(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	ccs := opencost.NewCloudCostSet(start, end)
+	ccs.AggregationProperties = []string{opencost.CloudCostServiceProp}
+	ccs.Insert(opencost.NewCloudCost(start, end, &opencost.CloudCostProperties{
+		Provider: "AWS",
+		Service:  "AmazonEC2",
+		Labels:   opencost.CloudCostLabels{"team": "platform"},
+	}, 0.5, 10, 8, 8, 8, 10))
+
+	row := &ViewTableRow{Name: "AmazonEC2", Labels: map[string]string{"team": "platform"}, KubernetesPercent: 0.5, Cost: 10}
+	querier := &stubQuerier{
+		ccsr: &opencost.CloudCostSetRange{CloudCostSets: []*opencost.CloudCostSet{ccs}},
+		graph: ViewGraphData{{
+			Start: start,
+			End:   end,
+			Items: []ViewGraphDataSetItem{{Name: "AmazonEC2", Value: 10}},
+		}},
+		totals: &ViewTotals{NumResults: 1, Combined: row},
+		rows:   ViewTableRows{row},
+	}
+	c := newQueryServiceClient(t, querier)
+	ctx := context.Background()
+
+	filter := &ast.EqualOp{Left: ast.Identifier{Field: ast.NewField(cloudcostfilter.FieldProvider)}, Right: "AWS"}
+	query := client.CloudCostQuery{
+		Window:     opencost.NewClosedWindow(start, end),
+		Aggregate:  []string{opencost.CloudCostServiceProp},
+		Accumulate: opencost.AccumulateOptionDay,
+		Filter:     filter,
+	}
+
+	ccsr, err := c.QueryCloudCosts(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, start, querier.request.Start)
+	assert.Equal(t, end, querier.request.End)
+	assert.Equal(t, []string{opencost.CloudCostServiceProp}, querier.request.AggregateBy)
+	assert.Equal(t, opencost.AccumulateOptionDay, querier.request.Accumulate)
+	assert.NotNil(t, querier.request.Filter)
+	require.Len(t, ccsr.CloudCostSets, 1)
+	for _, cc := range ccsr.CloudCostSets[0].CloudCosts {
+		assert.Equal(t, "AmazonEC2", cc.Properties.Service)
+		assert.Equal(t, 10.0, cc.ListCost.Cost)
+	}
+
+	viewQuery := client.CloudCostViewQuery{
+		CloudCostQuery: query,
+		CostMetric:     opencost.CostMetricListCost,
+		Limit:          10,
+		Offset:         5,
+		SortBy:         client.CloudCostSortFieldName,
+		SortDirection:  client.SortDirectionAscending,
+	}
+
+	graph, err := c.QueryCloudCostViewGraph(ctx, viewQuery)
+	require.NoError(t, err)
+	assert.Equal(t, opencost.CostMetricListCost, querier.viewRequest.CostMetricName)
+	require.Len(t, graph, 1)
+	assert.Equal(t, start, graph[0].Start)
+	assert.Equal(t, "AmazonEC2", graph[0].Items[0].Name)
+
+	totals, err := c.QueryCloudCostViewTotals(ctx, viewQuery)
+	require.NoError(t, err)
+	assert.Equal(t, 1, totals.NumResults)
+	assert.Equal(t, 10.0, totals.Combined.Cost)
+
+	rows, err := c.QueryCloudCostViewTable(ctx, viewQuery)
+	require.NoError(t, err)
+	assert.Equal(t, 10, querier.viewRequest.Limit)
+	assert.Equal(t, 5, querier.viewRequest.Offset)
+	assert.Equal(t, SortFieldName, querier.viewRequest.SortColumn)
+	assert.Equal(t, SortDirectionAscending, querier.viewRequest.SortDirection)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "AmazonEC2", rows[0].Name)
+	assert.Equal(t, map[string]string{"team": "platform"}, rows[0].Labels)
+}
+
+// TestQueryService_ClientTypes checks that the client's copies of the response types
+// decode every field the server encodes, and encode them back unchanged.
+func TestQueryService_ClientTypes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	row := &ViewTableRow{
+		Name:              "AmazonEC2",
+		Names:             []string{"AmazonEC2"},
+		Token:             "token",
+		Labels:            map[string]string{"team": "platform"},
+		KubernetesPercent: 0.5,
+		Cost:              10,
+	}
+
+	assertClientType(t, ViewGraphData{{
+		Start: start,
+		End:   start.Add(time.Hour),
+		Items: []ViewGraphDataSetItem{{Name: "AmazonEC2", Value: 10}},
+	}}, &client.CloudCostViewGraphData{})
+	assertClientType(t, &ViewTotals{NumResults: 1, Combined: row}, &client.CloudCostViewTotals{})
+	assertClientType(t, row, &client.CloudCostViewTableRow{})
+	assertClientType(t, &Status{
+		Key:              "aws-athena",
+		Source:           "athena",
+		Provider:         "AWS",
+		Active:           true,
+		Valid:            true,
+		LastRun:          start,
+		NextRun:          start.Add(time.Hour),
+		RefreshRate:      "1h",
+		Created:          start,
+		Runs:             3,
+		Coverage:         "1d",
+		ConnectionStatus: "successful",
+	}, &client.CloudCostStatus{})
+}
+
+func assertClientType(t *testing.T, server, mirror any) {
+	t.Helper()
+
+	data, err := json.Marshal(server)
+	require.NoError(t, err)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	require.NoError(t, dec.Decode(mirror), "client type %T is missing fields of %T", mirror, server)
+
+	roundTrip, err := json.Marshal(mirror)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(roundTrip), "client type %T does not match %T", mirror, server)
+}
@@ -12,6 +12,13 @@ import (
 // RepositoryQuerier is an implementation of Querier and ViewQuerier which pulls directly from a Repository
 type RepositoryQuerier struct {
 	repo Repository
+
+	// LabelNormalizer, if set, normalizes the labels of each CloudCost before
+	// filtering and aggregation
+	LabelNormalizer *opencost.LabelNormalizer
+	// CostCenters, if set, assigns each CloudCost its cost center before
+	// filtering and aggregation
+	CostCenters *opencost.CostCenterMapping
 }
 
 func NewRepositoryQuerier(repo Repository) *RepositoryQuerier {
@@ -50,6 +57,8 @@ func (rq *RepositoryQuerier) Query(ctx context.Context, request QueryRequest) (*
 			}
 
 			for _, cc := range ccs.CloudCosts {
+				cc = rq.LabelNormalizer.NormalizeCloudCost(cc)
+				cc = rq.CostCenters.AssignCloudCost(cc)
 				if matcher.Matches(cc) {
 					cloudCostSet.Insert(cc)
 				}
@@ -0,0 +1,79 @@
+package cloudcost
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/storage"
+)
+
+const (
+	storageRepositoryDir = "cloudcost"
+	storageRepositoryExt = ".ccs"
+)
+
+// StorageRepository is an implementation of Repository which persists each CloudCostSet to a storage.WindowStorage,
+// keyed on the billing integration and window start.
+type StorageRepository struct {
+	files *storage.WindowStorage
+}
+
+// NewStorageRepository creates a StorageRepository which stores CloudCostSets in the cloudcost directory of the storage
+func NewStorageRepository(store storage.Storage) *StorageRepository {
+	return &StorageRepository{
+		files: storage.NewWindowStorage(store, storageRepositoryDir, storageRepositoryExt),
+	}
+}
+
+func (s *StorageRepository) Has(startTime time.Time, billingIntegration string) (bool, error) {
+	return s.files.Has(startTime, billingIntegration)
+}
+
+func (s *StorageRepository) Get(startTime time.Time, billingIntegration string) (*opencost.CloudCostSet, error) {
+	b, err := s.files.Read(startTime, billingIntegration)
+	if err != nil {
+		return nil, fmt.Errorf("StorageRepository: Get: %w", err)
+	}
+	if b == nil {
+		return nil, nil
+	}
+
+	ccs := &opencost.CloudCostSet{}
+	err = ccs.UnmarshalBinary(b)
+	if err != nil {
+		return nil, fmt.Errorf("StorageRepository: Get: failed to unmarshal cloud cost set: %w", err)
+	}
+	return ccs, nil
+}
+
+// Keys returns the billing integrations which have at least one CloudCostSet in the storage
+func (s *StorageRepository) Keys() ([]string, error) {
+	return s.files.Keys()
+}
+
+func (s *StorageRepository) Put(ccs *opencost.CloudCostSet) error {
+	if ccs == nil {
+		return fmt.Errorf("StorageRepository: Put: cannot save nil")
+	}
+
+	if ccs.Window.IsOpen() {
+		return fmt.Errorf("StorageRepository: Put: cloud cost set has invalid window %s", ccs.Window.String())
+	}
+
+	if ccs.Integration == "" {
+		return fmt.Errorf("StorageRepository: Put: cloud cost set does not have an integration value")
+	}
+
+	b, err := ccs.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("StorageRepository: Put: failed to marshal cloud cost set: %w", err)
+	}
+
+	return s.files.Write(*ccs.Window.Start(), ccs.Integration, b)
+}
+
+// Expire deletes all CloudCostSets in the storage with a start time before the given limit
+func (s *StorageRepository) Expire(limit time.Time) error {
+	return s.files.Expire(limit)
+}
@@ -0,0 +1,185 @@
+package cloudcost
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/storage"
+	"github.com/opencost/opencost/core/pkg/util/timeutil"
+)
+
+func TestStorageRepository_PutGet(t *testing.T) {
+	defaultStart := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaultEnd := defaultStart.Add(timeutil.Day)
+
+	tests := map[string]struct {
+		data      []*opencost.CloudCostSet
+		input     *opencost.CloudCostSet
+		startTime time.Time
+		key       string
+		want      *opencost.CloudCostSet
+		wantErr   bool
+	}{
+		"nil set": {
+			input:     nil,
+			startTime: defaultStart,
+			key:       "key-1",
+			want:      nil,
+			wantErr:   true,
+		},
+		"invalid window": {
+			input: &opencost.CloudCostSet{
+				Window:      opencost.Window{},
+				Integration: "key-1",
+			},
+			startTime: defaultStart,
+			key:       "key-1",
+			want:      nil,
+			wantErr:   true,
+		},
+		"valid input": {
+			input:     DefaultMockCloudCostSet(defaultStart, defaultEnd, "aws", "key-1"),
+			startTime: defaultStart,
+			key:       "key-1",
+			want:      DefaultMockCloudCostSet(defaultStart, defaultEnd, "aws", "key-1"),
+			wantErr:   false,
+		},
+		"escaped key": {
+			input:     DefaultMockCloudCostSet(defaultStart, defaultEnd, "aws", "account/key-1"),
+			startTime: defaultStart,
+			key:       "account/key-1",
+			want:      DefaultMockCloudCostSet(defaultStart, defaultEnd, "aws", "account/key-1"),
+			wantErr:   false,
+		},
+		"overwrite": {
+			data:      []*opencost.CloudCostSet{DefaultMockCloudCostSet(defaultStart, defaultEnd, "gcp", "key-1")},
+			input:     DefaultMockCloudCostSet(defaultStart, defaultEnd, "aws", "key-1"),
+			startTime: defaultStart,
+			key:       "key-1",
+			want:      DefaultMockCloudCostSet(defaultStart, defaultEnd, "aws", "key-1"),
+			wantErr:   false,
+		},
+		"wrong time": {
+			input:     DefaultMockCloudCostSet(defaultStart, defaultEnd, "aws", "key-1"),
+			startTime: defaultEnd,
+			key:       "key-1",
+			want:      nil,
+			wantErr:   false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewStorageRepository(storage.NewMemoryStorage())
+			for _, ccs := range tt.data {
+				if err := s.Put(ccs); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+			}
+
+			if err := s.Put(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("Put() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			has, err := s.Has(tt.startTime, tt.key)
+			if err != nil {
+				t.Fatalf("Has() error = %v", err)
+			}
+			if has != (tt.want != nil) {
+				t.Errorf("Has() got = %v, want %v", has, tt.want != nil)
+			}
+
+			got, err := s.Get(tt.startTime, tt.key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("Get() got = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || !got.Window.Equal(tt.want.Window) || got.Integration != tt.want.Integration ||
+				!reflect.DeepEqual(got.CloudCosts, tt.want.CloudCosts) {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStorageRepository_Expire(t *testing.T) {
+	dayOne := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	dayTwo := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	dayThree := time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		data     []*opencost.CloudCostSet
+		limit    time.Time
+		wantKeys []string
+		wantHas  map[string][]time.Time
+	}{
+		"no expire": {
+			data: []*opencost.CloudCostSet{
+				DefaultMockCloudCostSet(dayTwo, dayThree, "aws", "key-1"),
+			},
+			limit:    dayTwo,
+			wantKeys: []string{"key-1"},
+			wantHas: map[string][]time.Time{
+				"key-1": {dayTwo},
+			},
+		},
+		"single expire": {
+			data: []*opencost.CloudCostSet{
+				DefaultMockCloudCostSet(dayTwo, dayThree, "aws", "key-1"),
+			},
+			limit:    dayThree,
+			wantKeys: nil,
+			wantHas:  map[string][]time.Time{},
+		},
+		"one key expire": {
+			data: []*opencost.CloudCostSet{
+				DefaultMockCloudCostSet(dayOne, dayTwo, "aws", "key-1"),
+				DefaultMockCloudCostSet(dayOne, dayTwo, "aws", "key-2"),
+				DefaultMockCloudCostSet(dayTwo, dayThree, "aws", "key-2"),
+			},
+			limit:    dayTwo,
+			wantKeys: []string{"key-2"},
+			wantHas: map[string][]time.Time{
+				"key-2": {dayTwo},
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewStorageRepository(storage.NewMemoryStorage())
+			for _, ccs := range tt.data {
+				if err := s.Put(ccs); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+			}
+
+			if err := s.Expire(tt.limit); err != nil {
+				t.Fatalf("Expire() error = %v", err)
+			}
+
+			keys, err := s.Keys()
+			if err != nil {
+				t.Fatalf("Keys() error = %v", err)
+			}
+			sort.Strings(keys)
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("Keys() got = %v, want %v", keys, tt.wantKeys)
+			}
+
+			for key, starts := range tt.wantHas {
+				for _, start := range starts {
+					if has, _ := s.Has(start, key); !has {
+						t.Errorf("Has(%s, %s) got = false, want true", start, key)
+					}
+				}
+			}
+		})
+	}
+}
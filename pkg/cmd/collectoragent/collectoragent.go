@@ -0,0 +1,79 @@
+package collectoragent
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/opencost/opencost/core/pkg/clusters"
+	"github.com/opencost/opencost/core/pkg/kubeconfig"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/nodestats"
+	"github.com/opencost/opencost/core/pkg/version"
+	"github.com/opencost/opencost/modules/collector-source/pkg/collector"
+	"github.com/opencost/opencost/pkg/cloud/provider"
+	cluster "github.com/opencost/opencost/pkg/clustercache"
+	"github.com/opencost/opencost/pkg/cmd/agent"
+	"github.com/opencost/opencost/pkg/config"
+	"github.com/opencost/opencost/pkg/costmodel"
+	"github.com/opencost/opencost/pkg/env"
+	"github.com/opencost/opencost/pkg/util/watcher"
+)
+
+// CollectorAgentOpts contain configuration options that can be passed to the Execute() method
+type CollectorAgentOpts struct {
+	// Stubbed for future configuration
+}
+
+// Execute runs the collector agent, which scrapes the metrics of the local cluster and pushes them to
+// a central collector, configured by the COLLECTOR_PUSH_* environment variables, which serves them
+// along with the metrics of the other clusters pushing to it.
+func Execute(opts *CollectorAgentOpts) error {
+	log.Infof("Starting Collector Agent version %s", version.FriendlyVersion())
+
+	// initialize kubernetes client and cluster cache
+	k8sClient, err := kubeconfig.LoadKubeClient("")
+	if err != nil {
+		return fmt.Errorf("failed to load kubernetes client: %w", err)
+	}
+	clusterCache := cluster.NewKubernetesClusterCache(k8sClient)
+	clusterCache.Run()
+
+	// Create ConfigFileManager for synchronization of shared configuration
+	confManager := config.NewConfigFileManager(nil)
+
+	cloudProviderKey := env.GetCloudProviderAPIKey()
+	cloudProvider, err := provider.NewProvider(clusterCache, cloudProviderKey, confManager)
+	if err != nil {
+		return fmt.Errorf("failed to create cloud provider: %w", err)
+	}
+
+	// the cluster info is pushed along with the metrics, providing the cluster map entry of the cluster
+	// on the central collector
+	var clusterInfoProvider clusters.ClusterInfoProvider = costmodel.NewLocalClusterInfoProvider(k8sClient, cloudProvider)
+
+	installNamespace := env.GetOpencostNamespace()
+	configWatchers := watcher.NewConfigMapWatchers(k8sClient, installNamespace)
+	configWatchers.AddWatcher(provider.ConfigWatcherFor(cloudProvider))
+	configWatchers.Watch()
+
+	nodeStatConf, err := costmodel.NewNodeClientConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to get node client config: %w", err)
+	}
+	clusterConfig, err := kubeconfig.LoadKubeconfig("")
+	if err != nil {
+		return fmt.Errorf("failed to load kube config: %w", err)
+	}
+	nodeStatClient := nodestats.NewNodeStatsSummaryClient(clusterCache, nodeStatConf, clusterConfig)
+
+	collectorAgent, err := collector.NewDefaultCollectorAgent(clusterInfoProvider, clusterCache, nodeStatClient)
+	if err != nil {
+		return fmt.Errorf("failed to create collector agent: %w", err)
+	}
+	collectorAgent.Start()
+
+	rootMux := http.NewServeMux()
+	rootMux.HandleFunc("/healthz", agent.Healthz)
+
+	return http.ListenAndServe(fmt.Sprintf(":%d", env.GetKubecostMetricsPort()), rootMux)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/pkg/cmd/agent"
+	"github.com/opencost/opencost/pkg/cmd/collectoragent"
 	"github.com/opencost/opencost/pkg/cmd/costmodel"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,6 +22,10 @@ const (
 
 	// CommandAgent executes the application in agent mode, which provides only metrics exporting.
 	CommandAgent string = "agent"
+
+	// CommandCollectorAgent executes the application as a collector agent, which pushes the metrics of the
+	// local cluster to a central collector.
+	CommandCollectorAgent string = "collector-agent"
 )
 
 // Execute runs the root command for the application. By default, if no command argument is provided,
@@ -88,6 +93,7 @@ func newRootCommand(costModelCmd *cobra.Command, cmds ...*cobra.Command) *cobra.
 		append([]*cobra.Command{
 			costModelCmd,
 			newAgentCommand(),
+			newCollectorAgentCommand(),
 		}, cmds...)...,
 	)
 
@@ -137,6 +143,23 @@ func newAgentCommand() *cobra.Command {
 	return agentCmd
 }
 
+func newCollectorAgentCommand() *cobra.Command {
+	opts := &collectoragent.CollectorAgentOpts{}
+
+	collectorAgentCmd := &cobra.Command{
+		Use:   CommandCollectorAgent,
+		Short: "Collector agent mode pushes the metrics of the cluster to a central collector.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Init logging here so cobra/viper has processed the command line args and flags
+			// otherwise only envvars are available during init
+			log.InitLogging(true)
+			return collectoragent.Execute(opts)
+		},
+	}
+
+	return collectorAgentCmd
+}
+
 // validate checks the command's use to see if it matches an expected command name.
 func validate(cmd *cobra.Command, command string) error {
 	if cmd.Use != command {
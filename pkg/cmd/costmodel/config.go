@@ -13,6 +13,13 @@ type Config struct {
 	CloudCostEnabled       bool
 	CustomCostEnabled      bool
 	MCPServerEnabled       bool
+	CRDControllerEnabled   bool
+	GRPCServerEnabled      bool
+	GRPCPort               int
+	GraphQLEnabled         bool
+	KubeModelExportEnabled bool
+	LeaderElectionEnabled  bool
+	TelemetryEnabled       bool
 }
 
 func DefaultConfig() *Config {
@@ -22,6 +29,13 @@ func DefaultConfig() *Config {
 		CarbonEstimatesEnabled: env.IsCarbonEstimatesEnabled(),
 		CloudCostEnabled:       env.IsCloudCostEnabled(),
 		MCPServerEnabled:       env.IsMCPServerEnabled(),
+		CRDControllerEnabled:   env.IsCRDControllerEnabled(),
+		GRPCServerEnabled:      env.IsGRPCServerEnabled(),
+		GRPCPort:               env.GetGRPCPort(),
+		GraphQLEnabled:         env.IsGraphQLEnabled(),
+		KubeModelExportEnabled: env.IsKubeModelExportEnabled(),
+		LeaderElectionEnabled:  env.IsLeaderElectionEnabled(),
+		TelemetryEnabled:       env.IsTelemetryEnabled(),
 	}
 }
 
@@ -31,4 +45,10 @@ func (c *Config) log() {
 	log.Infof("Cloud Costs enabled: %t", c.CloudCostEnabled)
 	log.Infof("Custom Costs enabled: %t", c.CustomCostEnabled)
 	log.Infof("MCP Server enabled: %t", c.MCPServerEnabled)
+	log.Infof("CRD Controller enabled: %t", c.CRDControllerEnabled)
+	log.Infof("gRPC Server enabled: %t", c.GRPCServerEnabled)
+	log.Infof("GraphQL enabled: %t", c.GraphQLEnabled)
+	log.Infof("KubeModel export enabled: %t", c.KubeModelExportEnabled)
+	log.Infof("Leader election enabled: %t", c.LeaderElectionEnabled)
+	log.Infof("Telemetry enabled: %t", c.TelemetryEnabled)
 }
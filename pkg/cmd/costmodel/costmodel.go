@@ -3,7 +3,11 @@ package costmodel
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/julienschmidt/httprouter"
@@ -14,18 +18,38 @@ import (
 	"github.com/opencost/opencost/pkg/customcost"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
 
 	mcp_sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	coreenv "github.com/opencost/opencost/core/pkg/env"
 	"github.com/opencost/opencost/core/pkg/errors"
+	"github.com/opencost/opencost/core/pkg/kubeconfig"
 	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/opencost/exporter"
+	"github.com/opencost/opencost/core/pkg/storage"
 	"github.com/opencost/opencost/core/pkg/version"
 	"github.com/opencost/opencost/pkg/costmodel"
+	"github.com/opencost/opencost/pkg/crd"
 	"github.com/opencost/opencost/pkg/env"
 	"github.com/opencost/opencost/pkg/filemanager"
+	"github.com/opencost/opencost/pkg/graphqlapi"
+	"github.com/opencost/opencost/pkg/grpcserver"
+	"github.com/opencost/opencost/pkg/leader"
 	opencost_mcp "github.com/opencost/opencost/pkg/mcp"
 	"github.com/opencost/opencost/pkg/metrics"
+	"github.com/opencost/opencost/pkg/queryjob"
+	"github.com/opencost/opencost/pkg/telemetry"
 )
 
+// kubeModelExportInterval is the interval at which the current kubemodel snapshot
+// windows are exported.
+const kubeModelExportInterval = 5 * time.Minute
+
+// shutdownTimeout is how long in-flight requests and buffered telemetry are given to
+// complete once the process is signalled to stop.
+const shutdownTimeout = 10 * time.Second
+
 func Execute(conf *Config) error {
 	log.Infof("Starting cost-model version %s", version.FriendlyVersion())
 	if conf == nil {
@@ -33,46 +57,139 @@ func Execute(conf *Config) error {
 	}
 	conf.log()
 
+	// SIGTERM stops the servers, after which the deferred shutdowns run
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	if conf.TelemetryEnabled {
+		shutdownTelemetry, err := StartTelemetry(ctx)
+		if err != nil {
+			log.Errorf("Failed to start telemetry: %v", err)
+		} else {
+			defer func() {
+				shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+				defer cancel()
+				if err := shutdownTelemetry(shutdownCtx); err != nil {
+					log.Errorf("Failed to shut down telemetry: %v", err)
+				}
+			}()
+		}
+	}
+
+	// The ingestors and exporters only run on the leader replica, while every replica serves the APIs
+	elector := leader.NewStandalone()
+	var store storage.Storage
+	if conf.LeaderElectionEnabled {
+		le, err := StartLeaderElection(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to start leader election: %w", err)
+		}
+		elector = le
+
+		// followers serve the cloud and custom costs ingested by the leader from the shared storage
+		store, err = storage.TryGetDefaultStorage()
+		if err != nil {
+			return fmt.Errorf("leader election requires shared storage, so that every replica serves cloud and custom costs: %w", err)
+		}
+	}
+
 	router := httprouter.New()
 	var a *costmodel.Accesses
 	var cp models.Provider
+	var jobManager *queryjob.Manager
 	if conf.KubernetesEnabled {
 		a = costmodel.Initialize(router)
-		err := StartExportWorker(context.Background(), a.Model)
-		if err != nil {
-			log.Errorf("couldn't start CSV export worker: %v", err)
-		}
+		model := a.Model
+		elector.Register("CSV export", func(ctx context.Context) {
+			err := StartExportWorker(ctx, model)
+			if err != nil {
+				log.Errorf("couldn't start CSV export worker: %v", err)
+			}
+			<-ctx.Done()
+		})
 
 		// Register OpenCost Specific Endpoints
 		router.GET("/allocation", a.ComputeAllocationHandler)
 		router.GET("/allocation/summary", a.ComputeAllocationHandlerSummary)
 		router.GET("/assets", a.ComputeAssetsHandler)
+		router.GET("/kubemodel", a.ComputeKubeModelHandler)
 		if conf.CarbonEstimatesEnabled {
 			router.GET("/assets/carbon", a.ComputeAssetsCarbonHandler)
 		}
 
+		var err error
+		jobManager, err = RegisterAllocationJobEndpoints(router, a.Model)
+		if err != nil {
+			log.Errorf("Failed to register allocation job endpoints: %v", err)
+		}
+
 		// set cloud provider for cloud cost
 		cp = a.CloudProvider
 	}
 
+	var providerConfig models.ProviderConfig
+	if cp != nil {
+		providerConfig = provider.ExtractConfigFromProviders(cp)
+	}
+
 	var cloudCostPipelineService *cloudcost.PipelineService
 	if conf.CloudCostEnabled {
-		var providerConfig models.ProviderConfig
-		if cp != nil {
-			providerConfig = provider.ExtractConfigFromProviders(cp)
+		cloudCostPipelineService = costmodel.InitializeCloudCost(router, providerConfig, elector, store)
+
+		if a != nil && a.Model.SharingRules != nil && cloudCostPipelineService != nil {
+			a.Model.SharingRules.CloudCostQuerier = cloudCostPipelineService.GetCloudCostQuerier()
 		}
-		cloudCostPipelineService = costmodel.InitializeCloudCost(router, providerConfig)
 	}
 
 	var customCostPipelineService *customcost.PipelineService
 	if conf.CloudCostEnabled {
-		customCostPipelineService = costmodel.InitializeCustomCost(router)
+		var model *costmodel.CostModel
+		if a != nil {
+			model = a.Model
+		}
+		customCostPipelineService = costmodel.InitializeCustomCost(router, model, elector, store)
+	}
+
+	if conf.CRDControllerEnabled && conf.KubernetesEnabled {
+		crdConf := &crd.Config{
+			Namespace:      env.GetOpencostNamespace(),
+			ProviderConfig: providerConfig,
+		}
+		if cloudCostPipelineService != nil {
+			crdConf.ConfigController = cloudCostPipelineService.GetConfigController()
+		}
+		if conf.CloudCostEnabled {
+			crdConf.PluginConfigDir = env.GetPluginConfigDir()
+		}
+		if customCostPipelineService != nil {
+			crdConf.Plugins = customCostPipelineService
+		}
+
+		err := StartCRDController(context.Background(), crdConf)
+		if err != nil {
+			log.Errorf("Failed to start CRD controller: %v", err)
+		}
+	} else if conf.CRDControllerEnabled {
+		log.Warnf("CRD Controller is enabled but Kubernetes is not available.")
 	}
 
 	// this endpoint is intentionally left out of the "if env.IsCustomCostEnabled()" conditional; in the handler, it is
 	// valid for CustomCostPipelineService to be nil
 	router.GET("/customCost/status", customCostPipelineService.GetCustomCostStatusHandler())
 
+	// The GraphQL endpoint resolves each query with whichever queriers are available, so
+	// it does not depend on which services are enabled
+	if conf.GraphQLEnabled {
+		var cloudCostQuerier cloudcost.Querier
+		if cloudCostPipelineService != nil {
+			cloudCostQuerier = cloudCostPipelineService.GetCloudCostQuerier()
+		}
+		err := RegisterGraphQLEndpoint(router, a, cloudCostQuerier)
+		if err != nil {
+			log.Errorf("Failed to register GraphQL endpoint: %v", err)
+		}
+	}
+
 	// Initialize MCP Server if enabled and Kubernetes is available
 	if conf.MCPServerEnabled && a != nil {
 		// Get cloud cost querier if cloud costs are enabled
@@ -89,6 +206,33 @@ func Execute(conf *Config) error {
 		log.Warnf("MCP Server is enabled but Kubernetes is not available. MCP server requires Kubernetes to function.")
 	}
 
+	if conf.KubeModelExportEnabled && a != nil {
+		err := StartKubeModelExport(a, elector)
+		if err != nil {
+			log.Errorf("Failed to start kubemodel export: %v", err)
+		}
+	} else if conf.KubeModelExportEnabled {
+		log.Warnf("KubeModel export is enabled but Kubernetes is not available.")
+	}
+
+	var grpcServer *grpc.Server
+	if conf.GRPCServerEnabled {
+		var cloudCostQuerier cloudcost.Querier
+		if cloudCostPipelineService != nil {
+			cloudCostQuerier = cloudCostPipelineService.GetCloudCostQuerier()
+		}
+		var customCostQuerier customcost.Querier
+		if customCostPipelineService != nil {
+			customCostQuerier = customCostPipelineService.GetCustomCostQuerier()
+		}
+
+		var err error
+		grpcServer, err = StartGRPCServer(conf.GRPCPort, a, cloudCostQuerier, customCostQuerier)
+		if err != nil {
+			log.Errorf("Failed to start gRPC server: %v", err)
+		}
+	}
+
 	apiutil.ApplyContainerDiagnosticEndpoints(router)
 
 	rootMux := http.NewServeMux()
@@ -96,8 +240,40 @@ func Execute(conf *Config) error {
 	rootMux.Handle("/metrics", promhttp.Handler())
 	telemetryHandler := metrics.ResponseMetricMiddleware(rootMux)
 	handler := cors.AllowAll().Handler(telemetryHandler)
+	if conf.TelemetryEnabled {
+		handler = telemetry.HTTPHandler(handler)
+	}
 
-	return http.ListenAndServe(fmt.Sprint(":", conf.Port), errors.PanicHandlerMiddleware(handler))
+	server := &http.Server{
+		Addr:    fmt.Sprint(":", conf.Port),
+		Handler: errors.PanicHandlerMiddleware(handler),
+	}
+	// in-flight requests complete before Execute returns
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		log.Infof("Shutting down cost-model")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if grpcServer != nil {
+			stopGRPCServer(shutdownCtx, grpcServer)
+		}
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Errorf("Failed to shut down server: %v", err)
+		}
+		if jobManager != nil {
+			jobManager.Stop()
+		}
+		elector.Stop()
+	}()
+
+	err := server.ListenAndServe()
+	if err != http.ErrServerClosed {
+		return err
+	}
+	<-shutdown
+	return nil
 }
 
 func StartExportWorker(ctx context.Context, model costmodel.AllocationModel) error {
@@ -135,6 +311,151 @@ func StartExportWorker(ctx context.Context, model costmodel.AllocationModel) err
 	return nil
 }
 
+// StartTelemetry exports traces and metrics over OTLP to the receiver configured in the environment. The returned
+// function flushes and shuts down the providers.
+func StartTelemetry(ctx context.Context) (func(context.Context) error, error) {
+	return telemetry.Start(ctx, telemetry.DefaultConfig())
+}
+
+// StartCRDController starts the reconcilers which configure OpenCost from its custom resources
+func StartCRDController(ctx context.Context, crdConf *crd.Config) error {
+	log.Infof("Starting CRD controller in namespace %s", crdConf.Namespace)
+
+	restConfig, err := kubeconfig.LoadKubeconfig("")
+	if err != nil {
+		return fmt.Errorf("could not load kubeconfig: %w", err)
+	}
+
+	return crd.Start(ctx, restConfig, crdConf)
+}
+
+// StartKubeModelExport starts exporting kubemodel snapshots to the default storage on a schedule, while the elector
+// leads
+func StartKubeModelExport(accesses *costmodel.Accesses, elector leader.Elector) error {
+	store, err := storage.TryGetDefaultStorage()
+	if err != nil {
+		return fmt.Errorf("could not load storage configuration: %w", err)
+	}
+
+	controllers := exporter.NewKubeModelExportControllers(coreenv.GetClusterID(), store, accesses.KubeModel, env.GetKubeModelExportResolutions())
+	if len(controllers.Resolutions()) == 0 {
+		return fmt.Errorf("no kubemodel export controllers were created")
+	}
+
+	elector.Register("kubemodel export", leader.RunWhile(func() {
+		if !controllers.Start(kubeModelExportInterval) {
+			log.Errorf("Failed to start kubemodel export controllers %s", controllers.Name())
+			return
+		}
+		log.Infof("Started kubemodel export controllers %s", controllers.Name())
+	}, controllers.Stop))
+	return nil
+}
+
+// StartLeaderElection campaigns for this replica to lead with the Lease in the OpenCost namespace, until the context
+// is cancelled. The service account requires get, create and update access to leases in the coordination.k8s.io API
+// group, see kubernetes/leader-election/rbac.yaml.
+func StartLeaderElection(ctx context.Context) (*leader.LeaseElector, error) {
+	client, err := kubeconfig.LoadKubeClient("")
+	if err != nil {
+		return nil, fmt.Errorf("could not load kube client: %w", err)
+	}
+
+	identity := env.GetPodName()
+	if identity == "" {
+		identity, err = os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("could not determine the identity of the replica: %w", err)
+		}
+	}
+
+	elector, err := leader.NewLeaseElector(client, leader.LeaseConfig{
+		Namespace:     env.GetOpencostNamespace(),
+		Name:          env.GetLeaderElectionLeaseName(),
+		Identity:      identity,
+		LeaseDuration: env.GetLeaderElectionLeaseDuration(),
+		RenewDeadline: env.GetLeaderElectionRenewDeadline(),
+		RetryPeriod:   env.GetLeaderElectionRetryPeriod(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Starting leader election as %s with lease %s/%s", identity, env.GetOpencostNamespace(), env.GetLeaderElectionLeaseName())
+	go elector.Run(ctx)
+	return elector, nil
+}
+
+// RegisterAllocationJobEndpoints registers the asynchronous allocation query API under
+// /allocation/jobs, returning the Manager which runs the jobs. Job results are kept in
+// the default storage if it is configured, and in memory otherwise.
+func RegisterAllocationJobEndpoints(router *httprouter.Router, model *costmodel.CostModel) (*queryjob.Manager, error) {
+	store, err := storage.TryGetDefaultStorage()
+	if err != nil {
+		log.Infof("Keeping allocation job results in memory: %s", err)
+		store = storage.NewMemoryStorage()
+	}
+
+	manager, err := queryjob.NewManager(store, env.GetQueryJobConcurrency(), env.GetQueryJobQueueSize(), env.GetQueryJobResultTTL())
+	if err != nil {
+		return nil, err
+	}
+
+	service := queryjob.NewQueryService(manager, model)
+	router.POST("/allocation/jobs", service.CreateAllocationJobHandler())
+	router.GET("/allocation/jobs/:id", service.GetJobHandler())
+	router.GET("/allocation/jobs/:id/result", service.GetJobResultHandler())
+	router.DELETE("/allocation/jobs/:id", service.CancelJobHandler())
+	return manager, nil
+}
+
+// RegisterGraphQLEndpoint registers the GraphQL query API at /graphql
+func RegisterGraphQLEndpoint(router *httprouter.Router, accesses *costmodel.Accesses, cloudCostQuerier cloudcost.Querier) error {
+	server, err := graphqlapi.NewServer(accesses, cloudCostQuerier)
+	if err != nil {
+		return err
+	}
+
+	router.GET("/graphql", server.Handle)
+	router.POST("/graphql", server.Handle)
+	return nil
+}
+
+// StartGRPCServer starts the gRPC query API as a background service
+func StartGRPCServer(port int, accesses *costmodel.Accesses, cloudCostQuerier cloudcost.Querier, customCostQuerier customcost.Querier) (*grpc.Server, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on port %d: %w", port, err)
+	}
+
+	server := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	grpcserver.NewServer(accesses, cloudCostQuerier, customCostQuerier).Register(server)
+
+	log.Infof("Starting gRPC server on port %d...", port)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Errorf("gRPC server failed: %v", err)
+		}
+	}()
+
+	return server, nil
+}
+
+// stopGRPCServer gracefully stops the gRPC server, cancelling the RPCs still
+// running once the context is done.
+func stopGRPCServer(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		server.GracefulStop()
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
 // StartMCPServer starts the MCP server as a background service
 func StartMCPServer(ctx context.Context, accesses *costmodel.Accesses, cloudCostQuerier cloudcost.Querier) error {
 	log.Info("Initializing MCP server...")
@@ -181,7 +502,7 @@ func StartMCPServer(ctx context.Context, accesses *costmodel.Accesses, cloudCost
 			Query: queryRequest,
 		}
 
-		mcpResp, err := mcpServer.ProcessMCPRequest(mcpReq)
+		mcpResp, err := mcpServer.ProcessMCPRequest(ctx, mcpReq)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to process allocation request: %w", err)
 		}
@@ -200,7 +521,7 @@ func StartMCPServer(ctx context.Context, accesses *costmodel.Accesses, cloudCost
 			Query: queryRequest,
 		}
 
-		mcpResp, err := mcpServer.ProcessMCPRequest(mcpReq)
+		mcpResp, err := mcpServer.ProcessMCPRequest(ctx, mcpReq)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to process asset request: %w", err)
 		}
@@ -228,7 +549,7 @@ func StartMCPServer(ctx context.Context, accesses *costmodel.Accesses, cloudCost
 			Query: queryRequest,
 		}
 
-		mcpResp, err := mcpServer.ProcessMCPRequest(mcpReq)
+		mcpResp, err := mcpServer.ProcessMCPRequest(ctx, mcpReq)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to process cloud cost request: %w", err)
 		}
@@ -251,7 +572,7 @@ func StartMCPServer(ctx context.Context, accesses *costmodel.Accesses, cloudCost
 			Query: queryRequest,
 		}
 
-		mcpResp, err := mcpServer.ProcessMCPRequest(mcpReq)
+		mcpResp, err := mcpServer.ProcessMCPRequest(ctx, mcpReq)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to process efficiency request: %w", err)
 		}
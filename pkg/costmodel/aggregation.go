@@ -10,6 +10,7 @@ import (
 	"github.com/opencost/opencost/core/pkg/filter/allocation"
 	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/util/httputil"
+	"github.com/opencost/opencost/core/pkg/util/timeutil"
 	"github.com/opencost/opencost/pkg/env"
 )
 
@@ -18,6 +19,10 @@ const (
 	// proportionally, rather than evenly
 	SplitTypeWeighted = "weighted"
 
+	// SplitTypeEven signals that shared costs should be shared evenly, rather
+	// than proportionally
+	SplitTypeEven = "even"
+
 	// UnallocatedSubfield indicates an allocation datum that does not have the
 	// chosen Aggregator; e.g. during aggregation by some label, there may be
 	// cost data that do not have the given label.
@@ -50,6 +55,8 @@ func ParseAggregationProperties(aggregations []string) ([]string, error) {
 					aggregateBy = append(aggregateBy, aggregate)
 				} else if strings.HasPrefix(aggregate, "annotation:") {
 					aggregateBy = append(aggregateBy, aggregate)
+				} else if strings.HasPrefix(aggregate, opencost.CostCenterPropPrefix) {
+					aggregateBy = append(aggregateBy, aggregate)
 				}
 			}
 		}
@@ -91,6 +98,17 @@ func (a *Accesses) ComputeAllocationHandlerSummary(w http.ResponseWriter, r *htt
 	// Get allocation filter if provided
 	allocationFilter := qp.Get("filter", "")
 
+	// IncludeCustomCosts, if true, attributes custom costs to allocations
+	// using the configured custom cost attribution rules.
+	includeCustomCosts := qp.GetBool("includeCustomCosts", false)
+
+	ctx, cancel := QueryContext(r.Context())
+	defer cancel()
+
+	// Explain is an optional parameter, defaulting to false, which if true
+	// returns the queries and computations behind the response in its meta.
+	ctx, explain := explainContext(ctx, qp)
+
 	// Query for AllocationSets in increments of the given step duration,
 	// appending each to the AllocationSetRange.
 	asr := opencost.NewAllocationSetRange()
@@ -99,11 +117,27 @@ func (a *Accesses) ComputeAllocationHandlerSummary(w http.ResponseWriter, r *htt
 		stepEnd := stepStart.Add(step)
 		stepWindow := opencost.NewWindow(&stepStart, &stepEnd)
 
-		as, err := a.Model.ComputeAllocation(*stepWindow.Start(), *stepWindow.End())
+		as, err := a.Model.ComputeAllocation(ctx, *stepWindow.Start(), *stepWindow.End())
 		if err != nil {
 			proto.WriteError(w, proto.InternalServerError(err.Error()))
 			return
 		}
+
+		a.Model.LabelNormalizer.NormalizeAllocations(as)
+		a.Model.CostCenters.AssignAllocations(as)
+
+		if includeCustomCosts {
+			if a.Model.CustomCostAttributor == nil {
+				proto.WriteError(w, proto.BadRequest("custom cost attribution is not configured"))
+				return
+			}
+
+			err = a.Model.CustomCostAttributor.Attribute(ctx, as)
+			if err != nil {
+				proto.WriteError(w, proto.InternalServerError(err.Error()))
+				return
+			}
+		}
 		asr.Append(as)
 
 		stepStart = stepEnd
@@ -140,7 +174,9 @@ func (a *Accesses) ComputeAllocationHandlerSummary(w http.ResponseWriter, r *htt
 
 	// Aggregate, if requested
 	if len(aggregateBy) > 0 {
-		err = asr.AggregateBy(aggregateBy, nil)
+		stageCtx, done := startStage(ctx, "aggregate", *window.Start(), *window.End())
+		err = asr.AggregateByContext(stageCtx, aggregateBy, nil)
+		done(err)
 		if err != nil {
 			proto.WriteError(w, proto.InternalServerError(err.Error()))
 			return
@@ -163,26 +199,33 @@ func (a *Accesses) ComputeAllocationHandlerSummary(w http.ResponseWriter, r *htt
 	}
 	sasr := opencost.NewSummaryAllocationSetRange(sasl...)
 
-	WriteData(w, sasr, nil)
+	writeExplainedData(w, sasr, explain)
 }
 
-// ComputeAllocationHandler computes an AllocationSetRange from the CostModel.
-func (a *Accesses) ComputeAllocationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	w.Header().Set("Content-Type", "application/json")
-
-	qp := httputil.NewQueryParams(r.URL.Query())
-
+// ParseAllocationQuery parses the window and options of an allocation query
+// from its parameters. Every API which queries allocations parses them here,
+// so that they all accept the same options.
+func ParseAllocationQuery(qp httputil.QueryParams) (opencost.Window, *AllocationQueryOptions, error) {
 	// Window is a required field describing the window of time over which to
 	// compute allocation data.
 	window, err := opencost.ParseWindowWithOffset(qp.Get("window", ""), env.GetParsedUTCOffset())
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid 'window' parameter: %s", err), http.StatusBadRequest)
+		return window, nil, fmt.Errorf("invalid 'window' parameter: %s", err)
 	}
 
 	// Step is an optional parameter that defines the duration per-set, i.e.
 	// the window for an AllocationSet, of the AllocationSetRange to be
 	// computed. Defaults to the window size, making one set.
-	step := qp.GetDuration("step", window.Duration())
+	step := window.Duration()
+	if stepStr := qp.Get("step", ""); stepStr != "" {
+		step, err = timeutil.ParseDuration(stepStr)
+		if err != nil {
+			return window, nil, fmt.Errorf("invalid 'step' parameter: %s", err)
+		}
+		if step <= 0 {
+			return window, nil, fmt.Errorf("invalid 'step' parameter: %s must be positive", stepStr)
+		}
+	}
 
 	// Aggregation is an optional comma-separated list of fields by which to
 	// aggregate results. Some fields allow a sub-field, which is distinguished
@@ -191,7 +234,7 @@ func (a *Accesses) ComputeAllocationHandler(w http.ResponseWriter, r *http.Reque
 	aggregations := qp.GetList("aggregate", ",")
 	aggregateBy, err := ParseAggregationProperties(aggregations)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Invalid 'aggregate' parameter: %s", err), http.StatusBadRequest)
+		return window, nil, fmt.Errorf("invalid 'aggregate' parameter: %s", err)
 	}
 
 	// IncludeIdle, if true, uses Asset data to incorporate Idle Allocation
@@ -227,11 +270,93 @@ func (a *Accesses) ComputeAllocationHandler(w http.ResponseWriter, r *http.Reque
 	// Get allocation filter if provided
 	allocationFilter := qp.Get("filter", "")
 
+	// IncludeCustomCosts, if true, attributes custom costs to allocations
+	// using the configured custom cost attribution rules.
+	includeCustomCosts := qp.GetBool("includeCustomCosts", false)
+
+	// IncludeSharedCostBreakdown, if true, breaks down the costs shared with
+	// each allocation by sharing rule.
+	includeSharedCostBreakdown := qp.GetBool("includeSharedCostBreakdown", false)
+
+	// ShareNamespaces is an optional comma-separated list of namespaces whose
+	// costs are shared with the other allocations.
+	shareNamespaces := qp.GetList("shareNamespaces", ",")
+
+	// ShareLabels is an optional comma-separated list of label key:value
+	// pairs, selecting the allocations whose costs are shared with the others.
+	// Example: "app:monitoring,team:platform"
+	var shareLabels map[string][]string
+	for _, shareLabel := range qp.GetList("shareLabels", ",") {
+		key, value, ok := strings.Cut(shareLabel, ":")
+		if !ok || key == "" || value == "" {
+			return window, nil, fmt.Errorf("invalid 'shareLabels' parameter: expected key:value, got '%s'", shareLabel)
+		}
+		if shareLabels == nil {
+			shareLabels = map[string][]string{}
+		}
+		shareLabels[key] = append(shareLabels[key], value)
+	}
+
+	// ShareSplit is an optional parameter, either "weighted" (the default) or
+	// "even", defining how shared costs are split among allocations.
+	var shareSplit string
+	switch split := qp.Get("shareSplit", SplitTypeWeighted); split {
+	case SplitTypeWeighted:
+		shareSplit = opencost.ShareWeighted
+	case SplitTypeEven:
+		shareSplit = opencost.ShareEven
+	default:
+		return window, nil, fmt.Errorf("invalid 'shareSplit' parameter: %s", split)
+	}
+
+	// ShareTenancyCosts, if true, shares the cluster management costs with
+	// the allocations.
+	shareTenancyCosts := qp.GetBool("shareTenancyCosts", false)
+
+	return window, &AllocationQueryOptions{
+		Step:                                  step,
+		AggregateBy:                           aggregateBy,
+		IncludeIdle:                           includeIdle,
+		IdleByNode:                            idleByNode,
+		IncludeProportionalAssetResourceCosts: includeProportionalAssetResourceCosts,
+		IncludeAggregatedMetadata:             includeAggregatedMetadata,
+		SharedLoadBalancer:                    sharedLoadBalancer,
+		Accumulate:                            accumulateBy,
+		ShareIdle:                             shareIdle,
+		Filter:                                allocationFilter,
+		IncludeCustomCosts:                    includeCustomCosts,
+		IncludeSharedCostBreakdown:            includeSharedCostBreakdown,
+		ShareNamespaces:                       shareNamespaces,
+		ShareLabels:                           shareLabels,
+		ShareSplit:                            shareSplit,
+		ShareTenancyCosts:                     shareTenancyCosts,
+	}, nil
+}
+
+// ComputeAllocationHandler computes an AllocationSetRange from the CostModel.
+func (a *Accesses) ComputeAllocationHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	w.Header().Set("Content-Type", "application/json")
+
+	qp := httputil.NewQueryParams(r.URL.Query())
+
+	window, opts, err := ParseAllocationQuery(qp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	// Query allocations with filtering, aggregation, and accumulation.
 	// Filtering is done BEFORE aggregation inside QueryAllocation to ensure
 	// filters can match on all allocation properties (like cluster, node, etc.)
 	// before they are potentially lost or merged during aggregation.
-	asr, err := a.Model.QueryAllocation(window, step, aggregateBy, includeIdle, idleByNode, includeProportionalAssetResourceCosts, includeAggregatedMetadata, sharedLoadBalancer, accumulateBy, shareIdle, allocationFilter)
+	ctx, cancel := QueryContext(r.Context())
+	defer cancel()
+
+	// Explain is an optional parameter, defaulting to false, which if true
+	// returns the queries and computations behind the response in its meta.
+	ctx, explain := explainContext(ctx, qp)
+
+	asr, err := a.Model.QueryAllocation(ctx, window, opts)
 	if err != nil {
 		if strings.Contains(strings.ToLower(err.Error()), "bad request") {
 			proto.WriteError(w, proto.BadRequest(err.Error()))
@@ -242,5 +367,5 @@ func (a *Accesses) ComputeAllocationHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	WriteData(w, asr, nil)
+	writeExplainedData(w, asr, explain)
 }
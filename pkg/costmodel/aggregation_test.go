@@ -1,9 +1,17 @@
 package costmodel
 
 import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/core/pkg/util/httputil"
 )
 
 func TestParseAggregationProperties_Default(t *testing.T) {
@@ -42,3 +50,77 @@ func TestParseAggregationProperties_All(t *testing.T) {
 		t.Fatalf("TestParseAggregationPropertiesDefault: expected length of 0, got: %d", len(got))
 	}
 }
+
+func TestParseAllocationQuery_Share(t *testing.T) {
+	qp := httputil.NewQueryParams(url.Values{
+		"window":            {"2024-01-01T00:00:00Z,2024-01-02T00:00:00Z"},
+		"step":              {"1h"},
+		"shareNamespaces":   {"kube-system,monitoring"},
+		"shareLabels":       {"team:platform,team:infra,app:proxy"},
+		"shareSplit":        {"even"},
+		"shareTenancyCosts": {"true"},
+	})
+
+	_, opts, err := ParseAllocationQuery(qp)
+	if err != nil {
+		t.Fatalf("TestParseAllocationQuery_Share: unexpected error: %s", err)
+	}
+
+	if opts.Step != time.Hour {
+		t.Fatalf("TestParseAllocationQuery_Share: expected step of 1h, got: %s", opts.Step)
+	}
+	if !reflect.DeepEqual(opts.ShareNamespaces, []string{"kube-system", "monitoring"}) {
+		t.Fatalf("TestParseAllocationQuery_Share: unexpected share namespaces: %v", opts.ShareNamespaces)
+	}
+	expectedLabels := map[string][]string{"team": {"platform", "infra"}, "app": {"proxy"}}
+	if !reflect.DeepEqual(opts.ShareLabels, expectedLabels) {
+		t.Fatalf("TestParseAllocationQuery_Share: unexpected share labels: %v", opts.ShareLabels)
+	}
+	if opts.ShareSplit != opencost.ShareEven {
+		t.Fatalf("TestParseAllocationQuery_Share: expected share split %s, got: %s", opencost.ShareEven, opts.ShareSplit)
+	}
+	if !opts.ShareTenancyCosts {
+		t.Fatalf("TestParseAllocationQuery_Share: expected tenancy costs to be shared")
+	}
+}
+
+func TestParseAllocationQuery_Invalid(t *testing.T) {
+	cases := map[string]url.Values{
+		"window":      {"window": {"invalid"}},
+		"step":        {"window": {"1d"}, "step": {"-1h"}},
+		"shareLabels": {"window": {"1d"}, "shareLabels": {"team"}},
+		"shareSplit":  {"window": {"1d"}, "shareSplit": {"random"}},
+	}
+
+	for name, values := range cases {
+		_, _, err := ParseAllocationQuery(httputil.NewQueryParams(values))
+		if err == nil {
+			t.Fatalf("TestParseAllocationQuery_Invalid: expected an error for invalid %s", name)
+		}
+	}
+}
+
+func TestExplainContext(t *testing.T) {
+	ctx, explain := explainContext(context.Background(), httputil.NewQueryParams(url.Values{}))
+	if explain != nil || source.ExplainFromContext(ctx) != nil {
+		t.Fatalf("TestExplainContext: expected no explain without explain=true")
+	}
+
+	rw := httptest.NewRecorder()
+	writeExplainedData(rw, "data", explain)
+	if strings.Contains(rw.Body.String(), `"meta"`) {
+		t.Fatalf("TestExplainContext: expected no meta without explain=true, got: %s", rw.Body.String())
+	}
+
+	ctx, explain = explainContext(context.Background(), httputil.NewQueryParams(url.Values{"explain": {"true"}}))
+	if explain == nil || source.ExplainFromContext(ctx) != explain {
+		t.Fatalf("TestExplainContext: expected explain on the context with explain=true")
+	}
+	explain.RecordQuery(source.ExplainQuery{Source: source.ExplainSourcePrometheus, Query: "up"})
+
+	rw = httptest.NewRecorder()
+	writeExplainedData(rw, "data", explain)
+	if body := rw.Body.String(); !strings.Contains(body, `"explain":{`) || !strings.Contains(body, `"query":"up"`) {
+		t.Fatalf("TestExplainContext: expected the explain report in the meta, got: %s", body)
+	}
+}
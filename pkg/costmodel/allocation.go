@@ -1,6 +1,7 @@
 package costmodel
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -28,12 +29,19 @@ func (cm *CostModel) Name() string {
 
 // ComputeAllocation uses the CostModel instance to compute an AllocationSet
 // for the window defined by the given start and end times. The Allocations
-// returned are unaggregated (i.e. down to the container level).
-func (cm *CostModel) ComputeAllocation(start, end time.Time) (*opencost.AllocationSet, error) {
+// returned are unaggregated (i.e. down to the container level). Queries are
+// abandoned, and the context's error returned, once the context is done.
+func (cm *CostModel) ComputeAllocation(ctx context.Context, start, end time.Time) (*opencost.AllocationSet, error) {
+	explain := source.ExplainFromContext(ctx)
+
+	ctx, span := startSpan(ctx, "CostModel.ComputeAllocation", start, end)
+	defer span.End()
 
 	// If the duration is short enough, compute the AllocationSet directly
 	if end.Sub(start) <= cm.BatchDuration {
-		as, _, err := cm.computeAllocation(start, end)
+		began := time.Now()
+		as, _, err := cm.computeAllocationBatch(ctx, start, end)
+		explain.RecordBatch(start, end, began)
 		return as, err
 	}
 
@@ -61,7 +69,9 @@ func (cm *CostModel) ComputeAllocation(start, end time.Time) (*opencost.Allocati
 		e = s.Add(duration)
 
 		// Compute the individual AllocationSet for just (s, e)
-		as, _, err := cm.computeAllocation(s, e)
+		began := time.Now()
+		as, _, err := cm.computeAllocationBatch(ctx, s, e)
+		explain.RecordBatch(s, e, began)
 		if err != nil {
 			return opencost.NewAllocationSet(start, end), fmt.Errorf("error computing allocation for %s: %s", opencost.NewClosedWindow(s, e), err)
 		}
@@ -188,6 +198,18 @@ func (cm *CostModel) ComputeAllocation(start, end time.Time) (*opencost.Allocati
 				resultAlloc.RawAllocationOnly.RAMBytesUsageMax = alloc.RawAllocationOnly.RAMBytesUsageMax
 			}
 
+			if resultAlloc.RawAllocationOnly.CPUCoreUsagePercentiles == nil {
+				resultAlloc.RawAllocationOnly.CPUCoreUsagePercentiles = alloc.RawAllocationOnly.CPUCoreUsagePercentiles.Clone()
+			} else {
+				resultAlloc.RawAllocationOnly.CPUCoreUsagePercentiles.Max(alloc.RawAllocationOnly.CPUCoreUsagePercentiles)
+			}
+
+			if resultAlloc.RawAllocationOnly.RAMBytesUsagePercentiles == nil {
+				resultAlloc.RawAllocationOnly.RAMBytesUsagePercentiles = alloc.RawAllocationOnly.RAMBytesUsagePercentiles.Clone()
+			} else {
+				resultAlloc.RawAllocationOnly.RAMBytesUsagePercentiles.Max(alloc.RawAllocationOnly.RAMBytesUsagePercentiles)
+			}
+
 			if alloc.RawAllocationOnly.GPUUsageMax != nil && resultAlloc.RawAllocationOnly.GPUUsageMax != nil {
 				if *alloc.RawAllocationOnly.GPUUsageMax > *resultAlloc.RawAllocationOnly.GPUUsageMax {
 					resultAlloc.RawAllocationOnly.GPUUsageMax = alloc.RawAllocationOnly.GPUUsageMax
@@ -216,7 +238,15 @@ func (cm *CostModel) DateRange(limitDays int) (time.Time, time.Time, error) {
 	return cm.DataSource.Metrics().QueryDataCoverage(limitDays)
 }
 
-func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.AllocationSet, map[nodeKey]*nodePricing, error) {
+// computeAllocationBatch computes the AllocationSet of a single batch of ComputeAllocation in its own span.
+func (cm *CostModel) computeAllocationBatch(ctx context.Context, start, end time.Time) (*opencost.AllocationSet, map[nodeKey]*nodePricing, error) {
+	ctx, span := startSpan(ctx, "CostModel.computeAllocation", start, end)
+	as, nodePricing, err := cm.computeAllocation(ctx, start, end)
+	endSpan(span, err)
+	return as, nodePricing, err
+}
+
+func (cm *CostModel) computeAllocation(ctx context.Context, start, end time.Time) (*opencost.AllocationSet, map[nodeKey]*nodePricing, error) {
 	// 1. Build out Pod map from resolution-tuned, batched Pod start/end query
 	// 2. Run and apply the results of the remaining queries to
 	// 3. Build out AllocationSet from completed Pod map
@@ -257,8 +287,12 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 		log.Debugf("CostModel.ComputeAllocation: ingesting UID data from KSM metrics...")
 	}
 
-	err := cm.buildPodMap(window, podMap, ingestPodUID, podUIDKeyMap)
+	err := cm.buildPodMap(ctx, window, podMap, ingestPodUID, podUIDKeyMap)
 	if err != nil {
+		// there is no use in running the remaining queries for an abandoned request
+		if ctx.Err() != nil {
+			return allocSet, nil, ctx.Err()
+		}
 		log.Errorf("CostModel.ComputeAllocation: failed to build pod map: %s", err.Error())
 	}
 	// (2) Run and apply remaining queries
@@ -269,8 +303,8 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 		return allocSet, nil, fmt.Errorf("illegal duration value for %s", opencost.NewClosedWindow(start, end))
 	}
 
-	grp := source.NewQueryGroup()
-	ds := cm.DataSource.Metrics()
+	grp := source.NewQueryGroupWithContext(ctx)
+	ds := source.MetricsWithContext(ctx, cm.DataSource.Metrics())
 
 	resChRAMBytesAllocated := source.WithGroup(grp, ds.QueryRAMBytesAllocated(start, end))
 	resChRAMRequests := source.WithGroup(grp, ds.QueryRAMRequests(start, end))
@@ -283,6 +317,16 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 	resChCPULimits := source.WithGroup(grp, ds.QueryCPULimits(start, end))
 	resChCPUUsageAvg := source.WithGroup(grp, ds.QueryCPUUsageAvg(start, end))
 	resChCPUUsageMax := source.WithGroup(grp, ds.QueryCPUUsageMax(start, end))
+
+	// Usage percentile queries, one per quantile
+	resChCPUUsageQuantiles := map[float64]*source.QueryGroupFuture[source.CPUUsageQuantileResult]{}
+	resChRAMUsageQuantiles := map[float64]*source.QueryGroupFuture[source.RAMUsageQuantileResult]{}
+	if env.IsAllocationUsagePercentilesEnabled() {
+		for _, quantile := range source.UsageQuantiles {
+			resChCPUUsageQuantiles[quantile] = source.WithGroup(grp, ds.QueryCPUUsageQuantile(start, end, quantile))
+			resChRAMUsageQuantiles[quantile] = source.WithGroup(grp, ds.QueryRAMUsageQuantile(start, end, quantile))
+		}
+	}
 	resCPUUsageMax, _ := resChCPUUsageMax.Await()
 	// This avoids logspam if there is no data for either metric (e.g. if
 	// the Prometheus didn't exist in the queried window of time).
@@ -324,8 +368,12 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 	resChNetInternetGiB := source.WithGroup(grp, ds.QueryNetInternetGiB(start, end))
 	resChNetInternetPricePerGiB := source.WithGroup(grp, ds.QueryNetInternetPricePerGiB(start, end))
 
+	// Node labels are also needed to identify the nodes of pod-priced
+	// platforms, when the cluster providers have any.
+	clusterProviders := cm.clusterProviders()
+	queryNodeLabels := env.IsAllocationNodeLabelsEnabled() || podPricingNeedsNodeLabels(clusterProviders)
 	var resChNodeLabels *source.QueryGroupFuture[source.NodeLabelsResult]
-	if env.IsAllocationNodeLabelsEnabled() {
+	if queryNodeLabels {
 		resChNodeLabels = source.WithGroup(grp, ds.QueryNodeLabels(start, end))
 	}
 
@@ -358,6 +406,14 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 	resRAMLimits, _ := resChRAMLimits.Await()
 	resRAMUsageAvg, _ := resChRAMUsageAvg.Await()
 	resRAMUsageMax, _ := resChRAMUsageMax.Await()
+	resCPUUsageQuantiles := map[float64][]*source.CPUUsageQuantileResult{}
+	for quantile, resCh := range resChCPUUsageQuantiles {
+		resCPUUsageQuantiles[quantile], _ = resCh.Await()
+	}
+	resRAMUsageQuantiles := map[float64][]*source.RAMUsageQuantileResult{}
+	for quantile, resCh := range resChRAMUsageQuantiles {
+		resRAMUsageQuantiles[quantile], _ = resCh.Await()
+	}
 	resGPUsRequested, _ := resChGPUsRequested.Await()
 	resGPUsUsageAvg, _ := resChGPUsUsageAvg.Await()
 	resGPUsUsageMax, _ := resChGPUsUsageMax.Await()
@@ -391,7 +447,7 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 	resNetInternetPricePerGiB, _ := resChNetInternetPricePerGiB.Await()
 
 	var resNodeLabels []*source.NodeLabelsResult
-	if env.IsAllocationNodeLabelsEnabled() {
+	if queryNodeLabels {
 		resNodeLabels, _ = resChNodeLabels.Await()
 	}
 	resNamespaceLabels, _ := resChNamespaceLabels.Await()
@@ -425,11 +481,13 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 	applyCPUCoresLimits(podMap, resCPULimits, podUIDKeyMap)
 	applyCPUCoresUsedAvg(podMap, resCPUUsageAvg, podUIDKeyMap)
 	applyCPUCoresUsedMax(podMap, resCPUUsageMax, podUIDKeyMap)
+	applyCPUCoresUsedQuantiles(podMap, resCPUUsageQuantiles, podUIDKeyMap)
 	applyRAMBytesAllocated(podMap, resRAMBytesAllocated, podUIDKeyMap)
 	applyRAMBytesRequested(podMap, resRAMRequests, podUIDKeyMap)
 	applyRAMBytesLimits(podMap, resRAMLimits, podUIDKeyMap)
 	applyRAMBytesUsedAvg(podMap, resRAMUsageAvg, podUIDKeyMap)
 	applyRAMBytesUsedMax(podMap, resRAMUsageMax, podUIDKeyMap)
+	applyRAMBytesUsedQuantiles(podMap, resRAMUsageQuantiles, podUIDKeyMap)
 	applyGPUUsageAvg(podMap, resGPUsUsageAvg, podUIDKeyMap)
 	applyGPUUsageMax(podMap, resGPUsUsageMax, podUIDKeyMap)
 	applyGPUUsageShared(podMap, resIsGpuShared, podUIDKeyMap)
@@ -451,9 +509,10 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 	// At this point, we expect "Node" to be set by one of the above functions
 	// (e.g. applyCPUCoresAllocated, etc.) -- otherwise, node labels will fail
 	// to correctly apply to the pods.
+	allNodeLabels := resToNodeLabels(resNodeLabels)
 	var nodeLabels map[nodeKey]map[string]string
 	if env.IsAllocationNodeLabelsEnabled() {
-		nodeLabels = resToNodeLabels(resNodeLabels)
+		nodeLabels = allNodeLabels
 	}
 	namespaceLabels := resToNamespaceLabels(resNamespaceLabels)
 	podLabels := resToPodLabels(resPodLabels, podUIDKeyMap, ingestPodUID)
@@ -522,7 +581,9 @@ func (cm *CostModel) computeAllocation(start, end time.Time) (*opencost.Allocati
 	applyNodeSpot(nodeMap, resNodeIsSpot)
 	applyNodeDiscount(nodeMap, cm)
 	applyExtendedNodeData(nodeMap, nodeExtendedData)
+	applyPodPricedNodes(nodeMap, allNodeLabels, clusterProviders, cm.Provider)
 	cm.applyNodesToPod(podMap, nodeMap)
+	applyPodPricing(podMap, nodeMap)
 
 	// (3) Build out AllocationSet from Pod map
 	for _, pod := range podMap {
@@ -1,6 +1,7 @@
 package costmodel
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"strconv"
@@ -37,19 +38,19 @@ const (
 
 /* Pod Helpers */
 
-func (cm *CostModel) buildPodMap(window opencost.Window, podMap map[podKey]*pod, ingestPodUID bool, podUIDKeyMap map[podKey][]podKey) error {
+func (cm *CostModel) buildPodMap(ctx context.Context, window opencost.Window, podMap map[podKey]*pod, ingestPodUID bool, podUIDKeyMap map[podKey][]podKey) error {
 	// Assumes that window is positive and closed
 	start, end := *window.Start(), *window.End()
 
-	grp := source.NewQueryGroup()
-	ds := cm.DataSource.Metrics()
+	grp := source.NewQueryGroupWithContext(ctx)
+	ds := source.MetricsWithContext(ctx, cm.DataSource.Metrics())
 	resolution := cm.DataSource.Resolution()
 
 	var resPods []*source.PodsResult
 	var err error
 	maxTries := 3
 	numTries := 0
-	for resPods == nil && numTries < maxTries {
+	for resPods == nil && numTries < maxTries && ctx.Err() == nil {
 		numTries++
 
 		// Submit and profile query
@@ -69,6 +70,10 @@ func (cm *CostModel) buildPodMap(window opencost.Window, podMap map[podKey]*pod,
 		}
 	}
 
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
 	if err != nil {
 		return err
 	}
@@ -413,6 +418,12 @@ func applyCPUCoresUsedMax(podMap map[podKey]*pod, resCPUCoresUsedMax []*source.C
 	}
 }
 
+func applyCPUCoresUsedQuantiles(podMap map[podKey]*pod, resCPUCoresUsedQuantiles map[float64][]*source.CPUUsageQuantileResult, podUIDKeyMap map[podKey][]podKey) {
+	applyUsageQuantiles(podMap, resCPUCoresUsedQuantiles, podUIDKeyMap, "CPU", func(raw *opencost.RawAllocationOnlyData) **opencost.UsagePercentiles {
+		return &raw.CPUCoreUsagePercentiles
+	})
+}
+
 func applyRAMBytesAllocated(podMap map[podKey]*pod, resRAMBytesAllocated []*source.RAMBytesAllocatedResult, podUIDKeyMap map[podKey][]podKey) {
 	for _, res := range resRAMBytesAllocated {
 		key, err := newResultPodKey(res.Cluster, res.Namespace, res.Pod)
@@ -648,6 +659,70 @@ func applyRAMBytesUsedMax(podMap map[podKey]*pod, resRAMBytesUsedMax []*source.R
 	}
 }
 
+func applyRAMBytesUsedQuantiles(podMap map[podKey]*pod, resRAMBytesUsedQuantiles map[float64][]*source.RAMUsageQuantileResult, podUIDKeyMap map[podKey][]podKey) {
+	applyUsageQuantiles(podMap, resRAMBytesUsedQuantiles, podUIDKeyMap, "RAM", func(raw *opencost.RawAllocationOnlyData) **opencost.UsagePercentiles {
+		return &raw.RAMBytesUsagePercentiles
+	})
+}
+
+// applyUsageQuantiles sets the usage percentile of each quantile's results on the
+// UsagePercentiles of the matching container allocations, which are selected from
+// the RawAllocationOnlyData by the percentiles func.
+func applyUsageQuantiles(podMap map[podKey]*pod, resUsageQuantiles map[float64][]*source.ContainerMetricResult, podUIDKeyMap map[podKey][]podKey, resource string, percentiles func(*opencost.RawAllocationOnlyData) **opencost.UsagePercentiles) {
+	for quantile, results := range resUsageQuantiles {
+		for _, res := range results {
+			key, err := newResultPodKey(res.Cluster, res.Namespace, res.Pod)
+			if err != nil {
+				log.DedupedWarningf(10, "CostModel.ComputeAllocation: %s usage quantile result missing field: %s", resource, err)
+				continue
+			}
+
+			container := res.Container
+			if container == "" {
+				log.DedupedWarningf(10, "CostModel.ComputeAllocation: %s usage quantile query result missing 'container': %s", resource, key)
+				continue
+			}
+
+			if len(res.Data) == 0 {
+				continue
+			}
+
+			var pods []*pod
+			if thisPod, ok := podMap[key]; !ok {
+				if uidKeys, ok := podUIDKeyMap[key]; ok {
+					for _, uidKey := range uidKeys {
+						thisPod, ok = podMap[uidKey]
+						if ok {
+							pods = append(pods, thisPod)
+						}
+					}
+				} else {
+					continue
+				}
+			} else {
+				pods = []*pod{thisPod}
+			}
+
+			for _, thisPod := range pods {
+
+				if _, ok := thisPod.Allocations[container]; !ok {
+					thisPod.appendContainer(container)
+				}
+
+				if thisPod.Allocations[container].RawAllocationOnly == nil {
+					thisPod.Allocations[container].RawAllocationOnly = &opencost.RawAllocationOnlyData{}
+				}
+
+				ups := percentiles(thisPod.Allocations[container].RawAllocationOnly)
+				if *ups == nil {
+					*ups = &opencost.UsagePercentiles{}
+				}
+				(*ups).Set(quantile, res.Data[0].Value)
+			}
+		}
+	}
+}
+
 // apply gpu usage average to allocations
 func applyGPUUsageAvg(podMap map[podKey]*pod, resGPUUsageAvg []*source.GPUsUsageAvgResult, podUIDKeyMap map[podKey][]podKey) {
 	// Example PromQueryResult: {container="dcgmproftester12", namespace="gpu", pod="dcgmproftester3-deployment-fc89c8dd6-ph7z5"} 0.997307
@@ -1892,6 +1967,10 @@ func (cm *CostModel) applyNodesToPod(podMap map[podKey]*pod, nodeMap map[nodeKey
 
 			node := cm.getNodePricing(nodeMap, thisNodeKey)
 			alloc.Properties.ProviderID = node.ProviderID
+			// Pods on pod-priced nodes are priced as a whole by applyPodPricing.
+			if node.PodPricing != nil {
+				continue
+			}
 			alloc.CPUCost = alloc.CPUCoreHours * node.CostPerCPUHr
 			alloc.RAMCost = (alloc.RAMByteHours / 1024 / 1024 / 1024) * node.CostPerRAMGiBHr
 			alloc.GPUCost = alloc.GPUHours * node.CostPerGPUHr
@@ -363,4 +363,6 @@ type nodePricing struct {
 	CostPerGPUHr    float64
 	Discount        float64
 	Source          string
+	PodPricing      *podPricingRule
+	CostPerPodHr    float64
 }
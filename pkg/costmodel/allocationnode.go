@@ -43,4 +43,6 @@ type nodePricing struct {
 	CostPerGPUHr    float64
 	Discount        float64
 	Source          string
+	PodPricing      *podPricingRule
+	CostPerPodHr    float64
 }
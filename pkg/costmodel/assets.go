@@ -1,6 +1,7 @@
 package costmodel
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -8,27 +9,49 @@ import (
 	"github.com/opencost/opencost/core/pkg/opencost"
 )
 
-func (cm *CostModel) ComputeAssets(start, end time.Time) (*opencost.AssetSet, error) {
+// ComputeAssets computes the AssetSet for the given window. Queries are abandoned, and the context's error returned,
+// once the context is done.
+func (cm *CostModel) ComputeAssets(ctx context.Context, start, end time.Time) (*opencost.AssetSet, error) {
+	assetSet, _, err := cm.computeAssets(ctx, start, end)
+	return assetSet, err
+}
+
+// computeAssets computes the AssetSet for the given window, along with the set
+// of pod-priced nodes which were excluded from it, as their pods are billed
+// directly and they have no idle cost.
+func (cm *CostModel) computeAssets(ctx context.Context, start, end time.Time) (*opencost.AssetSet, map[nodeKey]bool, error) {
 	assetSet := opencost.NewAssetSet(start, end)
+	podPricedNodes := map[nodeKey]bool{}
 
-	nodeMap, err := cm.ClusterNodes(start, end)
+	ctx, span := startSpan(ctx, "CostModel.computeAssets", start, end)
+	defer span.End()
+
+	stageCtx, done := startStage(ctx, "nodes", start, end)
+	nodeMap, err := cm.ClusterNodes(stageCtx, start, end)
+	done(err)
 	if err != nil {
-		return nil, fmt.Errorf("error computing node assets for %s: %w", opencost.NewClosedWindow(start, end), err)
+		return nil, nil, fmt.Errorf("error computing node assets for %s: %w", opencost.NewClosedWindow(start, end), err)
 	}
 
-	lbMap, err := cm.ClusterLoadBalancers(start, end)
+	stageCtx, done = startStage(ctx, "loadBalancers", start, end)
+	lbMap, err := cm.ClusterLoadBalancers(stageCtx, start, end)
+	done(err)
 	if err != nil {
-		return nil, fmt.Errorf("error computing load balancer assets for %s: %w", opencost.NewClosedWindow(start, end), err)
+		return nil, nil, fmt.Errorf("error computing load balancer assets for %s: %w", opencost.NewClosedWindow(start, end), err)
 	}
 
-	diskMap, err := cm.ClusterDisks(start, end)
+	stageCtx, done = startStage(ctx, "disks", start, end)
+	diskMap, err := cm.ClusterDisks(stageCtx, start, end)
+	done(err)
 	if err != nil {
-		return nil, fmt.Errorf("error computing disk assets for %s: %w", opencost.NewClosedWindow(start, end), err)
+		return nil, nil, fmt.Errorf("error computing disk assets for %s: %w", opencost.NewClosedWindow(start, end), err)
 	}
 
-	clusterManagement, err := cm.ClusterManagement(start, end)
+	stageCtx, done = startStage(ctx, "clusterManagement", start, end)
+	clusterManagement, err := cm.ClusterManagement(stageCtx, start, end)
+	done(err)
 	if err != nil {
-		return nil, fmt.Errorf("error computing cluster management assets for %s: %w", opencost.NewClosedWindow(start, end), err)
+		return nil, nil, fmt.Errorf("error computing cluster management assets for %s: %w", opencost.NewClosedWindow(start, end), err)
 	}
 
 	for _, d := range diskMap {
@@ -103,12 +126,12 @@ func (cm *CostModel) ComputeAssets(start, end time.Time) (*opencost.AssetSet, er
 		assetSet.Insert(cmAsset, nil)
 	}
 
+	clusterProviders := cm.clusterProviders()
 	for _, n := range nodeMap {
-		// check label, to see if node from fargate, if so ignore.
-		if n.Labels != nil {
-			if value, ok := n.Labels["label_eks_amazonaws_com_compute_type"]; ok && value == "fargate" {
-				continue
-			}
+		// pod-priced nodes, such as Fargate, are billed through their pods
+		if podPricingRuleFor(clusterProviders[n.Cluster], n.Name, n.Labels) != nil {
+			podPricedNodes[newNodeKey(n.Cluster, n.Name)] = true
+			continue
 		}
 		s := n.Start
 		if s.Before(start) || s.After(end) {
@@ -165,23 +188,23 @@ func (cm *CostModel) ComputeAssets(start, end time.Time) (*opencost.AssetSet, er
 		assetSet.Insert(node, nil)
 	}
 
-	return assetSet, nil
+	return assetSet, podPricedNodes, nil
 }
 
-func (cm *CostModel) ClusterDisks(start, end time.Time) (map[DiskIdentifier]*Disk, error) {
-	return ClusterDisks(cm.DataSource, cm.Provider, start, end)
+func (cm *CostModel) ClusterDisks(ctx context.Context, start, end time.Time) (map[DiskIdentifier]*Disk, error) {
+	return ClusterDisks(ctx, cm.DataSource, cm.Provider, start, end)
 }
 
-func (cm *CostModel) ClusterLoadBalancers(start, end time.Time) (map[LoadBalancerIdentifier]*LoadBalancer, error) {
-	return ClusterLoadBalancers(cm.DataSource, start, end)
+func (cm *CostModel) ClusterLoadBalancers(ctx context.Context, start, end time.Time) (map[LoadBalancerIdentifier]*LoadBalancer, error) {
+	return ClusterLoadBalancers(ctx, cm.DataSource, start, end)
 }
 
-func (cm *CostModel) ClusterNodes(start, end time.Time) (map[NodeIdentifier]*Node, error) {
-	return ClusterNodes(cm.DataSource, cm.Provider, start, end)
+func (cm *CostModel) ClusterNodes(ctx context.Context, start, end time.Time) (map[NodeIdentifier]*Node, error) {
+	return ClusterNodes(ctx, cm.DataSource, cm.Provider, start, end)
 }
 
-func (cm *CostModel) ClusterManagement(start, end time.Time) (map[ClusterManagementIdentifier]*ClusterManagementCost, error) {
-	return ClusterManagement(cm.DataSource, start, end)
+func (cm *CostModel) ClusterManagement(ctx context.Context, start, end time.Time) (map[ClusterManagementIdentifier]*ClusterManagementCost, error) {
+	return ClusterManagement(ctx, cm.DataSource, start, end)
 }
 
 // propertiesFromCluster populates static cluster properties to individual asset properties
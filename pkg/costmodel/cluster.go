@@ -1,6 +1,7 @@
 package costmodel
 
 import (
+	"context"
 	"net"
 	"strconv"
 	"strings"
@@ -93,11 +94,11 @@ type DiskIdentifier struct {
 	Name    string
 }
 
-func ClusterDisks(dataSource source.OpenCostDataSource, cp models.Provider, start, end time.Time) (map[DiskIdentifier]*Disk, error) {
+func ClusterDisks(ctx context.Context, dataSource source.OpenCostDataSource, cp models.Provider, start, end time.Time) (map[DiskIdentifier]*Disk, error) {
 	resolution := dataSource.Resolution()
 
-	grp := source.NewQueryGroup()
-	mq := dataSource.Metrics()
+	grp := source.NewQueryGroupWithContext(ctx)
+	mq := source.MetricsWithContext(ctx, dataSource.Metrics())
 
 	resChPVCost := source.WithGroup(grp, mq.QueryPVPricePerGiBHour(start, end))
 	resChPVSize := source.WithGroup(grp, mq.QueryPVBytes(start, end))
@@ -500,12 +501,12 @@ func costTimesMinute[T comparable](activeDataMap map[T]activeData, costMap map[T
 	}
 }
 
-func ClusterNodes(dataSource source.OpenCostDataSource, cp models.Provider, start, end time.Time) (map[NodeIdentifier]*Node, error) {
-	mq := dataSource.Metrics()
+func ClusterNodes(ctx context.Context, dataSource source.OpenCostDataSource, cp models.Provider, start, end time.Time) (map[NodeIdentifier]*Node, error) {
+	mq := source.MetricsWithContext(ctx, dataSource.Metrics())
 	resolution := dataSource.Resolution()
 
-	requiredGrp := source.NewQueryGroup()
-	optionalGrp := source.NewQueryGroup()
+	requiredGrp := source.NewQueryGroupWithContext(ctx)
+	optionalGrp := source.NewQueryGroupWithContext(ctx)
 
 	// return errors if these fail
 	resChNodeCPUHourlyCost := source.WithGroup(requiredGrp, mq.QueryNodeCPUPricePerHr(start, end))
@@ -642,11 +643,11 @@ type LoadBalancer struct {
 	Ip         string
 }
 
-func ClusterLoadBalancers(dataSource source.OpenCostDataSource, start, end time.Time) (map[LoadBalancerIdentifier]*LoadBalancer, error) {
+func ClusterLoadBalancers(ctx context.Context, dataSource source.OpenCostDataSource, start, end time.Time) (map[LoadBalancerIdentifier]*LoadBalancer, error) {
 	resolution := dataSource.Resolution()
 
-	grp := source.NewQueryGroup()
-	mq := dataSource.Metrics()
+	grp := source.NewQueryGroupWithContext(ctx)
+	mq := source.MetricsWithContext(ctx, dataSource.Metrics())
 
 	resChLBCost := source.WithGroup(grp, mq.QueryLBPricePerHr(start, end))
 	resChActiveMins := source.WithGroup(grp, mq.QueryLBActiveMinutes(start, end))
@@ -697,11 +698,11 @@ func ClusterLoadBalancers(dataSource source.OpenCostDataSource, start, end time.
 	return loadBalancerMap, nil
 }
 
-func ClusterManagement(dataSource source.OpenCostDataSource, start, end time.Time) (map[ClusterManagementIdentifier]*ClusterManagementCost, error) {
+func ClusterManagement(ctx context.Context, dataSource source.OpenCostDataSource, start, end time.Time) (map[ClusterManagementIdentifier]*ClusterManagementCost, error) {
 	resolution := dataSource.Resolution()
 
-	grp := source.NewQueryGroup()
-	mq := dataSource.Metrics()
+	grp := source.NewQueryGroupWithContext(ctx)
+	mq := source.MetricsWithContext(ctx, dataSource.Metrics())
 
 	resChCMPrice := source.WithGroup(grp, mq.QueryClusterManagementPricePerHr(start, end))
 	resChCMDur := source.WithGroup(grp, mq.QueryClusterManagementDuration(start, end))
@@ -1,6 +1,7 @@
 package costmodel
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"maps"
@@ -13,17 +14,22 @@ import (
 	"github.com/opencost/opencost/core/pkg/clustercache"
 	"github.com/opencost/opencost/core/pkg/clusters"
 	coreenv "github.com/opencost/opencost/core/pkg/env"
+	"github.com/opencost/opencost/core/pkg/filter"
 	"github.com/opencost/opencost/core/pkg/filter/allocation"
+	"github.com/opencost/opencost/core/pkg/filter/ast"
+	"github.com/opencost/opencost/core/pkg/filter/ops"
 	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/source"
 	"github.com/opencost/opencost/core/pkg/util"
 	"github.com/opencost/opencost/core/pkg/util/promutil"
 	costAnalyzerCloud "github.com/opencost/opencost/pkg/cloud/models"
+	"github.com/opencost/opencost/pkg/customcost"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -48,6 +54,22 @@ type CostModel struct {
 	DataSource      source.OpenCostDataSource
 	Provider        costAnalyzerCloud.Provider
 	pricingMetadata *costAnalyzerCloud.PricingMatchMetadata
+
+	// CustomCostAttributor, if set, attributes custom costs to allocations
+	// when requested by QueryAllocation.
+	CustomCostAttributor *customcost.Attributor
+
+	// SharingRules, if set, are resolved for each query window and applied
+	// when aggregating by QueryAllocation.
+	SharingRules *SharingRules
+
+	// LabelNormalizer, if set, normalizes the labels and annotations of
+	// allocations before filtering and aggregation.
+	LabelNormalizer *opencost.LabelNormalizer
+
+	// CostCenters, if set, assigns allocations their cost centers so that
+	// they can be aggregated by the cost center hierarchy.
+	CostCenters *opencost.CostCenterMapping
 }
 
 func NewCostModel(
@@ -1559,16 +1581,56 @@ func measureTime(start time.Time, threshold time.Duration, name string) {
 	}
 }
 
-func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration, aggregate []string, includeIdle, idleByNode, includeProportionalAssetResourceCosts, includeAggregatedMetadata, sharedLoadBalancer bool, accumulateBy opencost.AccumulateOption, shareIdle bool, filterString string) (*opencost.AllocationSetRange, error) {
+// AllocationQueryOptions are the optional parameters of QueryAllocation, which mirror those of /allocation.
+type AllocationQueryOptions struct {
+	// Step is the duration of each AllocationSet computed. Defaults to the duration of the window, making one set.
+	Step                                  time.Duration
+	AggregateBy                           []string
+	IncludeIdle                           bool
+	IdleByNode                            bool
+	IncludeProportionalAssetResourceCosts bool
+	IncludeAggregatedMetadata             bool
+	SharedLoadBalancer                    bool
+	Accumulate                            opencost.AccumulateOption
+	ShareIdle                             bool
+	// Filter is an allocation filter, applied before aggregation
+	Filter             string
+	IncludeCustomCosts bool
+	// IncludeSharedCostBreakdown breaks down the costs shared with each allocation by sharing rule
+	IncludeSharedCostBreakdown bool
+	// ShareNamespaces and ShareLabels select the allocations whose costs are shared with the others, split
+	// by ShareSplit, either opencost.ShareWeighted (the default) or opencost.ShareEven
+	ShareNamespaces []string
+	ShareLabels     map[string][]string
+	ShareSplit      string
+	// ShareTenancyCosts shares the cluster management costs with the allocations
+	ShareTenancyCosts bool
+}
+
+// QueryAllocation computes the AllocationSetRange for the window in steps of the given duration, then filters,
+// aggregates and accumulates it. Queries are abandoned, and the context's error returned, once the context is done.
+func (cm *CostModel) QueryAllocation(ctx context.Context, window opencost.Window, opts *AllocationQueryOptions) (*opencost.AllocationSetRange, error) {
 	// Validate window is legal
 	if window.IsOpen() || window.IsNegative() {
 		return nil, fmt.Errorf("illegal window: %s", window)
 	}
 
+	if opts == nil {
+		opts = &AllocationQueryOptions{}
+	}
+
+	step := opts.Step
+	if step == 0 {
+		step = window.Duration()
+	}
+	if step < 0 {
+		return nil, fmt.Errorf("bad request - illegal step: %s", step)
+	}
+
 	var totalsStore opencost.TotalsStore
 	// Idle is required for proportional asset costs
-	if includeProportionalAssetResourceCosts {
-		if !includeIdle {
+	if opts.IncludeProportionalAssetResourceCosts {
+		if !opts.IncludeIdle {
 			return nil, errors.New("bad request - includeIdle must be set true if includeProportionalAssetResourceCosts is true")
 		}
 		totalsStore = opencost.NewMemoryTotalsStore()
@@ -1577,24 +1639,30 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 	// Begin with empty response
 	asr := opencost.NewAllocationSetRange()
 
+	ctx, span := startSpan(ctx, "CostModel.QueryAllocation", *window.Start(), *window.End(),
+		attribute.String("step", step.String()),
+		attribute.StringSlice("aggregate", opts.AggregateBy),
+	)
+	defer span.End()
+
 	// Query for AllocationSets in increments of the given step duration,
 	// appending each to the response.
 	stepStart := *window.Start()
 	stepEnd := stepStart.Add(step)
 	var isAKS bool
 	for window.End().After(stepStart) {
-		allocSet, err := cm.ComputeAllocation(stepStart, stepEnd)
+		allocSet, err := cm.ComputeAllocation(ctx, stepStart, stepEnd)
 		if err != nil {
 			return nil, fmt.Errorf("error computing allocations for %s: %w", opencost.NewClosedWindow(stepStart, stepEnd), err)
 		}
 
-		if includeIdle {
-			assetSet, err := cm.ComputeAssets(stepStart, stepEnd)
+		if opts.IncludeIdle {
+			assetSet, podPricedNodes, err := cm.computeAssets(ctx, stepStart, stepEnd)
 			if err != nil {
 				return nil, fmt.Errorf("error computing assets for %s: %w", opencost.NewClosedWindow(stepStart, stepEnd), err)
 			}
 
-			if includeProportionalAssetResourceCosts {
+			if opts.IncludeProportionalAssetResourceCosts {
 
 				// AKS is a special case - there can be a maximum of 2
 				// load balancers (1 public and 1 private) in an AKS cluster
@@ -1613,7 +1681,9 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 				}
 			}
 
-			idleSet, err := computeIdleAllocations(allocSet, assetSet, idleByNode)
+			_, done := startStage(ctx, "idle", stepStart, stepEnd)
+			idleSet, err := computeIdleAllocations(withoutPodPricedAllocations(allocSet, podPricedNodes), assetSet, opts.IdleByNode)
+			done(err)
 			if err != nil {
 				return nil, fmt.Errorf("error computing idle allocations for %s: %w", opencost.NewClosedWindow(stepStart, stepEnd), err)
 			}
@@ -1623,6 +1693,22 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 			}
 		}
 
+		cm.LabelNormalizer.NormalizeAllocations(allocSet)
+		cm.CostCenters.AssignAllocations(allocSet)
+
+		if opts.IncludeCustomCosts {
+			if cm.CustomCostAttributor == nil {
+				return nil, errors.New("bad request - custom cost attribution is not configured")
+			}
+
+			stageCtx, done := startStage(ctx, "customCosts", stepStart, stepEnd)
+			err := cm.CustomCostAttributor.Attribute(stageCtx, allocSet)
+			done(err)
+			if err != nil {
+				return nil, fmt.Errorf("error attributing custom costs for %s: %w", opencost.NewClosedWindow(stepStart, stepEnd), err)
+			}
+		}
+
 		asr.Append(allocSet)
 
 		stepStart = stepEnd
@@ -1630,9 +1716,9 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 	}
 
 	// Apply allocation filter BEFORE aggregation if provided
-	if filterString != "" {
+	if opts.Filter != "" {
 		parser := allocation.NewAllocationFilterParser()
-		filterNode, err := parser.Parse(filterString)
+		filterNode, err := parser.Parse(opts.Filter)
 		if err != nil {
 			return nil, fmt.Errorf("invalid filter: %w", err)
 		}
@@ -1657,38 +1743,67 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 	}
 
 	// Set aggregation options and aggregate
+	var err error
 	var shareIdleOpt string
-	if shareIdle {
+	if opts.ShareIdle {
 		shareIdleOpt = opencost.ShareWeighted
 	} else {
 		shareIdleOpt = opencost.ShareNone
 	}
 
-	opts := &opencost.AllocationAggregationOptions{
-		IncludeProportionalAssetResourceCosts: includeProportionalAssetResourceCosts,
-		IdleByNode:                            idleByNode,
-		IncludeAggregatedMetadata:             includeAggregatedMetadata,
+	aggOpts := &opencost.AllocationAggregationOptions{
+		IncludeProportionalAssetResourceCosts: opts.IncludeProportionalAssetResourceCosts,
+		IdleByNode:                            opts.IdleByNode,
+		IncludeAggregatedMetadata:             opts.IncludeAggregatedMetadata,
+		IncludeSharedCostBreakdown:            opts.IncludeSharedCostBreakdown,
 		ShareIdle:                             shareIdleOpt,
+		Share:                                 shareFilter(opts.ShareNamespaces, opts.ShareLabels),
+		SharedNamespaces:                      opts.ShareNamespaces,
+		SharedLabels:                          opts.ShareLabels,
+		ShareSplit:                            opts.ShareSplit,
+	}
+	if aggOpts.ShareSplit == "" {
+		aggOpts.ShareSplit = opencost.ShareWeighted
 	}
 
-	// Aggregate
-	err := asr.AggregateBy(aggregate, opts)
+	if opts.ShareTenancyCosts {
+		aggOpts.SharedHourlyCosts, err = cm.tenancyHourlyCosts(ctx, window)
+		if err != nil {
+			return nil, fmt.Errorf("error computing tenancy costs for %s: %w", window, err)
+		}
+	}
+
+	if cm.SharingRules.Len() > 0 {
+		stageCtx, done := startStage(ctx, "sharingRules", *window.Start(), *window.End())
+		aggOpts.SharingRules, err = cm.SharingRules.Resolve(stageCtx, window)
+		done(err)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving sharing rules for %s: %w", window, err)
+		}
+	}
+
+	// Aggregate, which includes sharing idle and shared costs
+	stageCtx, done := startStage(ctx, "aggregate", *window.Start(), *window.End())
+	err = asr.AggregateByContext(stageCtx, opts.AggregateBy, aggOpts)
+	done(err)
 	if err != nil {
 		return nil, fmt.Errorf("error aggregating for %s: %w", window, err)
 	}
 
 	// Accumulate, if requested
-	if accumulateBy != opencost.AccumulateOptionNone {
-		asr, err = asr.Accumulate(accumulateBy)
+	if opts.Accumulate != opencost.AccumulateOptionNone {
+		_, done := startStage(ctx, "accumulate", *window.Start(), *window.End())
+		asr, err = asr.Accumulate(opts.Accumulate)
+		done(err)
 		if err != nil {
-			log.Errorf("error accumulating by %v: %s", accumulateBy, err)
-			return nil, fmt.Errorf("error accumulating by %v: %s", accumulateBy, err)
+			log.Errorf("error accumulating by %v: %s", opts.Accumulate, err)
+			return nil, fmt.Errorf("error accumulating by %v: %s", opts.Accumulate, err)
 		}
 
 		// when accumulating and returning PARCs, we need the totals for the
 		// accumulated windows to accurately compute a fraction
-		if includeProportionalAssetResourceCosts {
-			assetSet, err := cm.ComputeAssets(*asr.Window().Start(), *asr.Window().End())
+		if opts.IncludeProportionalAssetResourceCosts {
+			assetSet, err := cm.ComputeAssets(ctx, *asr.Window().Start(), *asr.Window().End())
 			if err != nil {
 				return nil, fmt.Errorf("error computing assets for %s: %w", opencost.NewClosedWindow(*asr.Window().Start(), *asr.Window().End()), err)
 			}
@@ -1701,7 +1816,7 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 		}
 	}
 
-	if includeProportionalAssetResourceCosts {
+	if opts.IncludeProportionalAssetResourceCosts {
 
 		for _, as := range asr.Allocations {
 			totalStoreByNode, ok := totalsStore.GetAssetTotalsByNode(as.Start(), as.End())
@@ -1717,7 +1832,7 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 			}
 
 			var totalPublicLbCost, totalPrivateLbCost float64
-			if isAKS && sharedLoadBalancer {
+			if isAKS && opts.SharedLoadBalancer {
 				// loop through all assetTotals, adding all load balancer costs by public and private
 				for _, tot := range totalStoreByNode {
 					if tot.PrivateLoadBalancer {
@@ -1753,7 +1868,7 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 					parc.GPUTotalCost = totals.GPUCost
 					parc.RAMTotalCost = totals.RAMCost
 					parc.PVTotalCost = totals.PersistentVolumeCost
-					if isAKS && sharedLoadBalancer && len(alloc.LoadBalancers) > 0 {
+					if isAKS && opts.SharedLoadBalancer && len(alloc.LoadBalancers) > 0 {
 						// Azure is a special case - use computed totals above
 						// use the lbAllocations in the object to determine if
 						// this PARC is a public or private load balancer
@@ -1783,6 +1898,69 @@ func (cm *CostModel) QueryAllocation(window opencost.Window, step time.Duration,
 	return asr, nil
 }
 
+// AllocationSteps splits the window of an allocation query into the windows of its steps, so that callers
+// can return each AllocationSet as soon as it is computed, and stop between steps. Accumulated queries and
+// open windows are computed as a whole, as accumulation requires every step.
+func AllocationSteps(window opencost.Window, opts *AllocationQueryOptions) []opencost.Window {
+	if opts.Accumulate != opencost.AccumulateOptionNone || window.IsOpen() || opts.Step <= 0 {
+		return []opencost.Window{window}
+	}
+
+	start, end := *window.Start(), *window.End()
+	var steps []opencost.Window
+	for stepStart := start; stepStart.Before(end); stepStart = stepStart.Add(opts.Step) {
+		stepEnd := stepStart.Add(opts.Step)
+		if stepEnd.After(end) {
+			stepEnd = end
+		}
+		steps = append(steps, opencost.NewClosedWindow(stepStart, stepEnd))
+	}
+	return steps
+}
+
+// shareFilter returns a filter matching the allocations in any of the given namespaces, or with any of the
+// given label values, or nil if there are none.
+func shareFilter(namespaces []string, labels map[string][]string) filter.Filter {
+	var operands []ast.FilterNode
+	for _, namespace := range namespaces {
+		operands = append(operands, ops.Eq(allocation.FieldNamespace, namespace))
+	}
+	for key, values := range labels {
+		for _, value := range values {
+			operands = append(operands, ops.Eq(ops.WithKey(allocation.FieldLabel, key), value))
+		}
+	}
+
+	if len(operands) == 0 {
+		return nil
+	}
+	return &ast.OrOp{Operands: operands}
+}
+
+// tenancyHourlyCosts returns the average hourly cluster management cost of each cluster over the window,
+// for sharing among its allocations.
+func (cm *CostModel) tenancyHourlyCosts(ctx context.Context, window opencost.Window) (map[string]float64, error) {
+	start, end := *window.Start(), *window.End()
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+	hours := end.Sub(start).Hours()
+	if hours <= 0 {
+		return nil, nil
+	}
+
+	clusterManagement, err := cm.ClusterManagement(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	costs := map[string]float64{}
+	for _, cman := range clusterManagement {
+		costs[fmt.Sprintf("%s/%s", cman.Cluster, cman.Provisioner)] += cman.Cost / hours
+	}
+	return costs, nil
+}
+
 func computeIdleAllocations(allocSet *opencost.AllocationSet, assetSet *opencost.AssetSet, idleByNode bool) (*opencost.AllocationSet, error) {
 	if !allocSet.Window.Equal(assetSet.Window) {
 		return nil, fmt.Errorf("cannot compute idle allocations for mismatched sets: %s does not equal %s", allocSet.Window, assetSet.Window)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/opencost/opencost/core/pkg/clustercache"
+	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/util"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
@@ -347,3 +348,38 @@ func TestGetContainerAllocation(t *testing.T) {
 		})
 	}
 }
+
+func TestShareFilter(t *testing.T) {
+	assert.Nil(t, shareFilter(nil, nil))
+
+	matcher, err := opencost.NewAllocationMatchCompiler(nil).Compile(shareFilter(
+		[]string{"kube-system"},
+		map[string][]string{"team": {"platform"}},
+	))
+	assert.NoError(t, err)
+
+	newAlloc := func(namespace string, labels map[string]string) *opencost.Allocation {
+		return &opencost.Allocation{Properties: &opencost.AllocationProperties{Namespace: namespace, Labels: labels}}
+	}
+	assert.True(t, matcher.Matches(newAlloc("kube-system", nil)))
+	assert.True(t, matcher.Matches(newAlloc("default", map[string]string{"team": "platform"})))
+	assert.False(t, matcher.Matches(newAlloc("default", map[string]string{"team": "data"})))
+}
+
+func TestAllocationSteps(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := opencost.NewClosedWindow(start, start.Add(60*time.Hour))
+
+	// the last step is truncated to the window
+	assert.Equal(t, []opencost.Window{
+		opencost.NewClosedWindow(start, start.Add(24*time.Hour)),
+		opencost.NewClosedWindow(start.Add(24*time.Hour), start.Add(48*time.Hour)),
+		opencost.NewClosedWindow(start.Add(48*time.Hour), start.Add(60*time.Hour)),
+	}, AllocationSteps(window, &AllocationQueryOptions{Step: 24 * time.Hour}))
+
+	// accumulated queries are computed as a whole
+	assert.Equal(t, []opencost.Window{window}, AllocationSteps(window, &AllocationQueryOptions{
+		Step:       24 * time.Hour,
+		Accumulate: opencost.AccumulateOptionAll,
+	}))
+}
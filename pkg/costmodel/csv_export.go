@@ -12,14 +12,16 @@ import (
 	"strconv"
 	"time"
 
+	export "github.com/opencost/opencost/core/pkg/exporter"
 	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/pkg/env"
 	"github.com/opencost/opencost/pkg/filemanager"
+	"go.opentelemetry.io/otel"
 )
 
 type AllocationModel interface {
-	ComputeAllocation(start, end time.Time) (*opencost.AllocationSet, error)
+	ComputeAllocation(ctx context.Context, start, end time.Time) (*opencost.AllocationSet, error)
 	DateRange(limitDays int) (time.Time, time.Time, error)
 }
 
@@ -32,7 +34,12 @@ func UpdateCSV(ctx context.Context, fileManager filemanager.FileManager, model A
 		LabelsAll:   labelsAll,
 		Labels:      labels,
 	}
-	return exporter.Update(ctx)
+
+	ctx, span := otel.Tracer(tracerName).Start(ctx, "csvExporter.Update")
+	err := exporter.Update(ctx)
+	endSpan(span, err)
+	export.RecordExportRun(ctx, "csv", err)
+	return err
 }
 
 type csvExporter struct {
@@ -328,7 +335,7 @@ func (e *csvExporter) writeCSVToWriter(ctx context.Context, w io.Writer, dates [
 	for _, date := range dates {
 		start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
 		end := start.AddDate(0, 0, 1)
-		data, err := e.Model.ComputeAllocation(start, end)
+		data, err := e.Model.ComputeAllocation(ctx, start, end)
 		if err != nil {
 			return err
 		}
@@ -1,14 +1,22 @@
 package costmodel
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+
 	assetfilter "github.com/opencost/opencost/core/pkg/filter/asset"
 	"github.com/opencost/opencost/core/pkg/filter/ast"
 	"github.com/opencost/opencost/core/pkg/filter/matcher"
+	"github.com/opencost/opencost/core/pkg/kubemodel"
 	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/source"
 	"github.com/opencost/opencost/core/pkg/util/httputil"
 	"github.com/opencost/opencost/pkg/carbon"
 	"github.com/opencost/opencost/pkg/env"
@@ -30,13 +38,20 @@ func (a *Accesses) ComputeAssetsHandler(w http.ResponseWriter, r *http.Request,
 
 	filterString := qp.Get("filter", "")
 
-	assetSet, err := a.ComputeAssetsFromCostmodel(window, filterString)
+	ctx, cancel := QueryContext(r.Context())
+	defer cancel()
+
+	// Explain is an optional parameter, defaulting to false, which if true
+	// returns the queries and computations behind the response in its meta.
+	ctx, explain := explainContext(ctx, qp)
+
+	assetSet, err := a.ComputeAssetsFromCostmodel(ctx, window, filterString)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error getting assets: %s", err), http.StatusInternalServerError)
 		return
 	}
 
-	WriteData(w, assetSet, nil)
+	writeExplainedData(w, assetSet, explain)
 }
 
 // ComputeAllocationHandler returns the assets from the CostModel.
@@ -55,7 +70,10 @@ func (a *Accesses) ComputeAssetsCarbonHandler(w http.ResponseWriter, r *http.Req
 
 	filterString := qp.Get("filter", "")
 
-	assetSet, err := a.ComputeAssetsFromCostmodel(window, filterString)
+	ctx, cancel := QueryContext(r.Context())
+	defer cancel()
+
+	assetSet, err := a.ComputeAssetsFromCostmodel(ctx, window, filterString)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error getting assets: %s", err), http.StatusInternalServerError)
 		return
@@ -70,9 +88,92 @@ func (a *Accesses) ComputeAssetsCarbonHandler(w http.ResponseWriter, r *http.Req
 	WriteData(w, carbonEstimates, nil)
 }
 
-func (a *Accesses) ComputeAssetsFromCostmodel(window opencost.Window, filterString string) (*opencost.AssetSet, error) {
+// ComputeKubeModelHandler returns the kubemodel snapshot of a 10m, 1h or 1d window, encoded as protobuf JSON or, with
+// format=binpb, as binary protobuf.
+func (a *Accesses) ComputeKubeModelHandler(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	qp := httputil.NewQueryParams(r.URL.Query())
+
+	window, err := opencost.ParseWindowWithOffset(qp.Get("window", ""), env.GetParsedUTCOffset())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid 'window' parameter: %s", err), http.StatusBadRequest)
+		return
+	}
+	if window.IsOpen() {
+		http.Error(w, fmt.Sprintf("Invalid 'window' parameter: %s is open", window), http.StatusBadRequest)
+		return
+	}
+	if err := kubemodel.ValidateCurrentWindow(*window.Start(), *window.End(), time.Now()); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid 'window' parameter: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	snapshot, err := a.KubeModel.ComputeSnapshot(*window.Start(), *window.End())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, kubemodel.ErrInvalidWindow) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, fmt.Sprintf("Error computing kubemodel snapshot: %s", err), status)
+		return
+	}
+
+	var data []byte
+	switch format := qp.Get("format", "json"); format {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		data, err = protojson.Marshal(snapshot)
+	case "binpb":
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		data, err = protobuf.Marshal(snapshot)
+	default:
+		http.Error(w, fmt.Sprintf("Invalid 'format' parameter: %s", format), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error encoding kubemodel snapshot: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Write(data)
+}
+
+// QueryContext derives the context of an allocation or asset query from the context of the request which made it,
+// applying the deadline configured by QUERY_TIMEOUT, if any. Queries end when the client goes away or the deadline
+// passes, rather than holding onto the query queue.
+func QueryContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if timeout := env.GetQueryTimeout(); timeout > 0 {
+		return context.WithTimeout(parent, timeout)
+	}
+	return context.WithCancel(parent)
+}
+
+// explainContext attaches a source.Explain to the context if the request asked for one with explain=true, so that
+// the queries and computations behind the response are recorded. Otherwise, the Explain returned is nil.
+func explainContext(ctx context.Context, qp httputil.QueryParams) (context.Context, *source.Explain) {
+	if !qp.GetBool("explain", false) {
+		return ctx, nil
+	}
+
+	explain := source.NewExplain()
+	return source.ContextWithExplain(ctx, explain), explain
+}
+
+// writeExplainedData writes the data as WriteData does, with the report of the Explain, if any, under the explain key
+// of the response meta.
+func writeExplainedData(w http.ResponseWriter, data interface{}, explain *source.Explain) {
+	if explain == nil {
+		WriteData(w, data, nil)
+		return
+	}
+
+	proto.WriteDataWithMeta(w, data, map[string]interface{}{
+		"explain": explain.Report(),
+	})
+}
+
+func (a *Accesses) ComputeAssetsFromCostmodel(ctx context.Context, window opencost.Window, filterString string) (*opencost.AssetSet, error) {
 
-	assetSet, err := a.Model.ComputeAssets(*window.Start(), *window.End())
+	assetSet, err := a.Model.ComputeAssets(ctx, *window.Start(), *window.End())
 	if err != nil {
 		return nil, fmt.Errorf("error computing asset set: %s", err)
 	}
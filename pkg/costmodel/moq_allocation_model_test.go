@@ -4,6 +4,7 @@
 package costmodel
 
 import (
+	"context"
 	"github.com/opencost/opencost/core/pkg/opencost"
 	"sync"
 	"time"
@@ -19,7 +20,7 @@ var _ AllocationModel = &AllocationModelMock{}
 //
 //		// make and configure a mocked AllocationModel
 //		mockedAllocationModel := &AllocationModelMock{
-//			ComputeAllocationFunc: func(start time.Time, end time.Time, resolution time.Duration) (*opencost.AllocationSet, error) {
+//			ComputeAllocationFunc: func(ctx context.Context, start time.Time, end time.Time) (*opencost.AllocationSet, error) {
 //				panic("mock out the ComputeAllocation method")
 //			},
 //			DateRangeFunc: func(limitDays int) (time.Time, time.Time, error) {
@@ -33,7 +34,7 @@ var _ AllocationModel = &AllocationModelMock{}
 //	}
 type AllocationModelMock struct {
 	// ComputeAllocationFunc mocks the ComputeAllocation method.
-	ComputeAllocationFunc func(start time.Time, end time.Time) (*opencost.AllocationSet, error)
+	ComputeAllocationFunc func(ctx context.Context, start time.Time, end time.Time) (*opencost.AllocationSet, error)
 
 	// DateRangeFunc mocks the DateRange method.
 	DateRangeFunc func(limitDays int) (time.Time, time.Time, error)
@@ -42,6 +43,8 @@ type AllocationModelMock struct {
 	calls struct {
 		// ComputeAllocation holds details about calls to the ComputeAllocation method.
 		ComputeAllocation []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Start is the start argument value.
 			Start time.Time
 			// End is the end argument value.
@@ -58,21 +61,23 @@ type AllocationModelMock struct {
 }
 
 // ComputeAllocation calls ComputeAllocationFunc.
-func (mock *AllocationModelMock) ComputeAllocation(start time.Time, end time.Time) (*opencost.AllocationSet, error) {
+func (mock *AllocationModelMock) ComputeAllocation(ctx context.Context, start time.Time, end time.Time) (*opencost.AllocationSet, error) {
 	if mock.ComputeAllocationFunc == nil {
 		panic("AllocationModelMock.ComputeAllocationFunc: method is nil but AllocationModel.ComputeAllocation was just called")
 	}
 	callInfo := struct {
-		Start      time.Time
-		End        time.Time
+		Ctx   context.Context
+		Start time.Time
+		End   time.Time
 	}{
-		Start:      start,
-		End:        end,
+		Ctx:   ctx,
+		Start: start,
+		End:   end,
 	}
 	mock.lockComputeAllocation.Lock()
 	mock.calls.ComputeAllocation = append(mock.calls.ComputeAllocation, callInfo)
 	mock.lockComputeAllocation.Unlock()
-	return mock.ComputeAllocationFunc(start, end)
+	return mock.ComputeAllocationFunc(ctx, start, end)
 }
 
 // ComputeAllocationCalls gets all the calls that were made to ComputeAllocation.
@@ -80,12 +85,14 @@ func (mock *AllocationModelMock) ComputeAllocation(start time.Time, end time.Tim
 //
 //	len(mockedAllocationModel.ComputeAllocationCalls())
 func (mock *AllocationModelMock) ComputeAllocationCalls() []struct {
-	Start      time.Time
-	End        time.Time
+	Ctx   context.Context
+	Start time.Time
+	End   time.Time
 } {
 	var calls []struct {
-		Start      time.Time
-		End        time.Time
+		Ctx   context.Context
+		Start time.Time
+		End   time.Time
 	}
 	mock.lockComputeAllocation.RLock()
 	calls = mock.calls.ComputeAllocation
@@ -0,0 +1,381 @@
+package costmodel
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/pkg/cloud/models"
+)
+
+// podPricingRule describes a serverless platform which bills each pod from its
+// rounded-up resource requests, rather than billing the nodes pods run on.
+// Nodes of these platforms have no meaningful capacity, so they carry no idle
+// cost.
+type podPricingRule struct {
+	Name string
+	// Provider is the cluster provider the platform belongs to. A rule only
+	// applies to clusters of its own provider, as labels like
+	// type=virtual-kubelet are not unique to one platform.
+	Provider string
+	// LabelKey and LabelValue identify the platform's nodes by their sanitized
+	// labels. An empty LabelValue matches any value.
+	LabelKey   string
+	LabelValue string
+	// NodePrefix identifies the platform's nodes by name, for platforms whose
+	// nodes carry no distinguishing label.
+	NodePrefix string
+	// Round rounds a pod's total CPU core and RAM GiB requests up to the
+	// resources the platform bills for. A nil Round bills requests as-is.
+	Round func(cpuCores, ramGiB float64) (float64, float64)
+	// CostPerCPUHr and CostPerRAMGiBHr are the platform's list rates. When
+	// zero, the rates the provider reports for the node are used instead.
+	CostPerCPUHr    float64
+	CostPerRAMGiBHr float64
+	// CustomRates returns the platform's CPU and RAM rates from custom
+	// pricing, which override its list rates when set.
+	CustomRates func(c *models.CustomPricing) (cpu, ram string)
+	// PodFee charges each pod the hourly fee reported by the provider's
+	// podFeeProvider implementation, on top of its CPU and RAM.
+	PodFee bool
+}
+
+// podFeeProvider is implemented by providers which charge an hourly fee for
+// each pod running on their pod-priced nodes, such as OKE virtual nodes.
+type podFeeProvider interface {
+	VirtualNodePodPricing() (float64, error)
+}
+
+// podPricingRules are the supported pod-priced platforms. Rates are fallback
+// on-demand list prices for each platform's reference region (us-east-1,
+// us-central1 and eastus), used when custom pricing does not set the
+// platform's own rates.
+var podPricingRules = []*podPricingRule{
+	{
+		Name:            "fargate",
+		Provider:        opencost.AWSProvider,
+		LabelKey:        "eks_amazonaws_com_compute_type",
+		LabelValue:      "fargate",
+		Round:           roundFargate,
+		CostPerCPUHr:    0.04048,
+		CostPerRAMGiBHr: 0.004445,
+		CustomRates: func(c *models.CustomPricing) (string, string) {
+			return c.FargateCPU, c.FargateRAM
+		},
+	},
+	{
+		Name:            "gke-autopilot",
+		Provider:        opencost.GCPProvider,
+		NodePrefix:      "gk3-",
+		Round:           roundAutopilot,
+		CostPerCPUHr:    0.0445,
+		CostPerRAMGiBHr: 0.0049225,
+		CustomRates: func(c *models.CustomPricing) (string, string) {
+			return c.AutopilotCPU, c.AutopilotRAM
+		},
+	},
+	{
+		Name:            "aci",
+		Provider:        opencost.AzureProvider,
+		LabelKey:        "type",
+		LabelValue:      "virtual-kubelet",
+		Round:           roundACI,
+		CostPerCPUHr:    0.0486,
+		CostPerRAMGiBHr: 0.0054,
+		CustomRates: func(c *models.CustomPricing) (string, string) {
+			return c.ACICPU, c.ACIRAM
+		},
+	},
+	{
+		// OKE virtual node pods are billed at the Pod shape rates the Oracle
+		// provider reports for the node, plus the virtual node fee per pod.
+		Name:     "oke-virtual-node",
+		Provider: opencost.OracleProvider,
+		LabelKey: "node_role_kubernetes_io_virtual_node",
+		PodFee:   true,
+	},
+}
+
+// podPricingRuleFor returns the pod pricing rule matching a node of a cluster
+// of the given provider, identified by its name and sanitized labels, or nil
+// when the node is priced as a whole.
+func podPricingRuleFor(provider, node string, labels map[string]string) *podPricingRule {
+	for _, rule := range podPricingRules {
+		if rule.Provider == provider && rule.matches(node, labels) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// podPricingNeedsNodeLabels returns true if any of the given cluster providers
+// has a pod-priced platform identified by node labels.
+func podPricingNeedsNodeLabels(clusterProviders map[string]string) bool {
+	for _, provider := range clusterProviders {
+		for _, rule := range podPricingRules {
+			if rule.Provider == provider && rule.LabelKey != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clusterProviders returns the provider of each cluster in the cluster map,
+// which selects the pod pricing rules applying to the cluster's nodes.
+func (cm *CostModel) clusterProviders() map[string]string {
+	providers := map[string]string{}
+	if cm == nil || cm.ClusterMap == nil {
+		return providers
+	}
+	for id, ci := range cm.ClusterMap.AsMap() {
+		if ci != nil {
+			providers[id] = ci.Provider
+		}
+	}
+	return providers
+}
+
+func (r *podPricingRule) matches(node string, labels map[string]string) bool {
+	if r.NodePrefix != "" && strings.HasPrefix(node, r.NodePrefix) {
+		return true
+	}
+	if r.LabelKey == "" {
+		return false
+	}
+	// Labels are sanitized, but may still carry the label_ prefix emitted by
+	// kube_node_labels.
+	value, ok := labels[r.LabelKey]
+	if !ok {
+		value, ok = labels["label_"+r.LabelKey]
+	}
+	return ok && (r.LabelValue == "" || value == r.LabelValue)
+}
+
+// round returns the billed CPU cores and RAM GiB for a pod's total requests.
+func (r *podPricingRule) round(cpuCores, ramGiB float64) (float64, float64) {
+	if r.Round == nil {
+		return cpuCores, ramGiB
+	}
+	return r.Round(cpuCores, ramGiB)
+}
+
+// fargateConfigurations are the supported Fargate vCPU sizes, with the minimum
+// and maximum memory, in GiB, available to each, and the increments memory is
+// available in between them.
+var fargateConfigurations = []struct {
+	cpu, minRAM, maxRAM, stepRAM float64
+}{
+	{0.25, 0.5, 2, 1},
+	{0.5, 1, 4, 1},
+	{1, 2, 8, 1},
+	{2, 4, 16, 1},
+	{4, 8, 30, 1},
+	{8, 16, 60, 4},
+	{16, 32, 120, 8},
+}
+
+// roundFargate rounds requests up to the smallest Fargate configuration which
+// fits them. Fargate reserves 256MiB of each pod for Kubernetes components, and
+// bills memory in the configuration's increments.
+func roundFargate(cpuCores, ramGiB float64) (float64, float64) {
+	ramGiB += 0.25
+	for _, conf := range fargateConfigurations {
+		if cpuCores > conf.cpu || ramGiB > conf.maxRAM {
+			continue
+		}
+		ram := math.Max(conf.minRAM, math.Ceil(ramGiB/conf.stepRAM)*conf.stepRAM)
+		if ramGiB <= conf.minRAM {
+			ram = conf.minRAM
+		}
+		return conf.cpu, ram
+	}
+	// Requests exceed the largest configuration, so bill them as-is.
+	return cpuCores, math.Ceil(ramGiB)
+}
+
+// roundAutopilot rounds requests up to 0.25 vCPU increments, with a minimum of
+// 0.25 vCPU and 0.5GiB, and a memory to CPU ratio between 1:1 and 6.5:1.
+func roundAutopilot(cpuCores, ramGiB float64) (float64, float64) {
+	cpuCores = math.Max(cpuCores, 0.25)
+	cpuCores = math.Max(cpuCores, ramGiB/6.5)
+	cpuCores = math.Ceil(cpuCores*4) / 4
+	ramGiB = math.Max(ramGiB, 0.5)
+	ramGiB = math.Max(ramGiB, cpuCores)
+	return cpuCores, ramGiB
+}
+
+// roundACI rounds memory requests up to the 0.1GiB increments Azure Container
+// Instances are billed in.
+func roundACI(cpuCores, ramGiB float64) (float64, float64) {
+	return cpuCores, math.Ceil(ramGiB*10) / 10
+}
+
+// applyPodPricedNodes assigns pod pricing rules to the nodes in the node map
+// which belong to a pod-priced platform, along with any per-pod fee the
+// provider charges for them.
+func applyPodPricedNodes(nodeMap map[nodeKey]*nodePricing, nodeLabels map[nodeKey]map[string]string, clusterProviders map[string]string, provider models.Provider) {
+	customPricing := customPodPricing(provider)
+	var costPerPodHr *float64
+	for key, node := range nodeMap {
+		rule := podPricingRuleFor(clusterProviders[key.Cluster], key.Node, nodeLabels[key])
+		node.PodPricing = rule.withRates(customPricing)
+		if node.PodPricing == nil || !node.PodPricing.PodFee {
+			continue
+		}
+		if costPerPodHr == nil {
+			fee := podFee(provider)
+			costPerPodHr = &fee
+		}
+		node.CostPerPodHr = *costPerPodHr
+	}
+}
+
+// withRates returns the rule with its list rates replaced by any rates set for
+// its platform in custom pricing. Rules which use the node's rates are returned
+// unchanged.
+func (r *podPricingRule) withRates(c *models.CustomPricing) *podPricingRule {
+	if r == nil || r.CustomRates == nil || c == nil {
+		return r
+	}
+	cpu, ram := r.CustomRates(c)
+	costPerCPUHr := parsePodRate(r.Name, "CPU", cpu)
+	costPerRAMGiBHr := parsePodRate(r.Name, "RAM", ram)
+	if costPerCPUHr == 0 && costPerRAMGiBHr == 0 {
+		return r
+	}
+	rule := *r
+	if costPerCPUHr > 0 {
+		rule.CostPerCPUHr = costPerCPUHr
+	}
+	if costPerRAMGiBHr > 0 {
+		rule.CostPerRAMGiBHr = costPerRAMGiBHr
+	}
+	return &rule
+}
+
+// customPodPricing returns the provider's custom pricing, which may set the
+// rates of pod-priced platforms, or nil if it is unavailable.
+func customPodPricing(provider models.Provider) *models.CustomPricing {
+	if provider == nil {
+		return nil
+	}
+	c, err := provider.GetConfig()
+	if err != nil {
+		log.Errorf("CostModel.ComputeAllocation: applyPodPricedNodes: %s", err)
+		return nil
+	}
+	return c
+}
+
+// parsePodRate parses a platform's custom resource rate, returning zero for
+// rates which are unset or invalid.
+func parsePodRate(platform, resource, rate string) float64 {
+	if rate == "" {
+		return 0.0
+	}
+	f, err := strconv.ParseFloat(rate, 64)
+	if err != nil || f < 0 {
+		log.Warnf("CostModel.ComputeAllocation: ignoring invalid %s %s rate %q", platform, resource, rate)
+		return 0.0
+	}
+	return f
+}
+
+// podFee returns the hourly fee the provider charges for each pod on its
+// pod-priced nodes, or zero if it does not charge one.
+func podFee(provider models.Provider) float64 {
+	fp, ok := provider.(podFeeProvider)
+	if !ok {
+		return 0.0
+	}
+	fee, err := fp.VirtualNodePodPricing()
+	if err != nil {
+		log.Errorf("CostModel.ComputeAllocation: failed to get pod fee: %s", err)
+		return 0.0
+	}
+	return fee
+}
+
+// applyPodPricing prices the pods running on pod-priced nodes. Each pod is
+// billed for its rounded-up total CPU and RAM requests, plus any per-pod fee,
+// which are then split between its containers in proportion to their own
+// requests. The fee is divided between CPU and RAM cost in proportion to
+// them. Node pricing must already have been resolved by applyNodesToPod.
+func applyPodPricing(podMap map[podKey]*pod, nodeMap map[nodeKey]*nodePricing) {
+	for _, pod := range podMap {
+		if len(pod.Allocations) == 0 {
+			continue
+		}
+		key := newNodeKey(pod.Key.Cluster, pod.Node)
+		node, ok := nodeMap[key]
+		if !ok || node == nil || node.PodPricing == nil {
+			continue
+		}
+		rule := node.PodPricing
+
+		var cpuRequested, ramRequested float64
+		for _, alloc := range pod.Allocations {
+			cpuRequested += alloc.CPUCoreRequestAverage
+			ramRequested += alloc.RAMBytesRequestAverage / 1024 / 1024 / 1024
+		}
+		cpuBilled, ramBilled := rule.round(cpuRequested, ramRequested)
+
+		costPerCPUHr := node.CostPerCPUHr
+		if rule.CostPerCPUHr > 0 {
+			costPerCPUHr = rule.CostPerCPUHr * (1.0 - node.Discount)
+		}
+		costPerRAMGiBHr := node.CostPerRAMGiBHr
+		if rule.CostPerRAMGiBHr > 0 {
+			costPerRAMGiBHr = rule.CostPerRAMGiBHr * (1.0 - node.Discount)
+		}
+
+		cpuCostPerHr := cpuBilled * costPerCPUHr
+		ramCostPerHr := ramBilled * costPerRAMGiBHr
+		if fee := node.CostPerPodHr * (1.0 - node.Discount); fee > 0 {
+			cpuFraction := 1.0
+			if cpuCostPerHr+ramCostPerHr > 0 {
+				cpuFraction = cpuCostPerHr / (cpuCostPerHr + ramCostPerHr)
+			}
+			cpuCostPerHr += fee * cpuFraction
+			ramCostPerHr += fee * (1.0 - cpuFraction)
+		}
+
+		count := float64(len(pod.Allocations))
+		for _, alloc := range pod.Allocations {
+			cpuShare := 1.0 / count
+			if cpuRequested > 0 {
+				cpuShare = alloc.CPUCoreRequestAverage / cpuRequested
+			}
+			ramShare := 1.0 / count
+			if ramRequested > 0 {
+				ramShare = (alloc.RAMBytesRequestAverage / 1024 / 1024 / 1024) / ramRequested
+			}
+
+			hours := alloc.Minutes() / 60.0
+			alloc.CPUCost = cpuCostPerHr * cpuShare * hours
+			alloc.RAMCost = ramCostPerHr * ramShare * hours
+			alloc.GPUCost = alloc.GPUHours * node.CostPerGPUHr
+		}
+	}
+}
+
+// withoutPodPricedAllocations returns a set containing the allocations of the
+// given set which did not run on pod-priced nodes. Pod-priced nodes have no
+// asset cost, so their allocations must not count towards idle.
+func withoutPodPricedAllocations(allocSet *opencost.AllocationSet, podPricedNodes map[nodeKey]bool) *opencost.AllocationSet {
+	if len(podPricedNodes) == 0 {
+		return allocSet
+	}
+
+	filtered := opencost.NewAllocationSet(*allocSet.Window.Start(), *allocSet.Window.End())
+	for _, alloc := range allocSet.Allocations {
+		if alloc.Properties != nil && podPricedNodes[newNodeKey(alloc.Properties.Cluster, alloc.Properties.Node)] {
+			continue
+		}
+		filtered.Allocations[alloc.Name] = alloc
+	}
+	return filtered
+}
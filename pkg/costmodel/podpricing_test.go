@@ -0,0 +1,317 @@
+package costmodel
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/pkg/cloud/models"
+)
+
+type podPricingTestProvider struct {
+	models.Provider
+	config models.CustomPricing
+	fee    float64
+}
+
+func (p *podPricingTestProvider) GetConfig() (*models.CustomPricing, error) {
+	return &p.config, nil
+}
+
+func (p *podPricingTestProvider) VirtualNodePodPricing() (float64, error) {
+	return p.fee, nil
+}
+
+func TestPodPricingRuleFor(t *testing.T) {
+	cases := map[string]struct {
+		provider string
+		node     string
+		labels   map[string]string
+		expected string
+	}{
+		"fargate": {
+			provider: opencost.AWSProvider,
+			node:     "fargate-ip-10-0-0-1.ec2.internal",
+			labels:   map[string]string{"eks_amazonaws_com_compute_type": "fargate"},
+			expected: "fargate",
+		},
+		"fargate with label prefix": {
+			provider: opencost.AWSProvider,
+			node:     "fargate-ip-10-0-0-1.ec2.internal",
+			labels:   map[string]string{"label_eks_amazonaws_com_compute_type": "fargate"},
+			expected: "fargate",
+		},
+		"ec2": {
+			provider: opencost.AWSProvider,
+			node:     "ip-10-0-0-1.ec2.internal",
+			labels:   map[string]string{"eks_amazonaws_com_compute_type": "ec2"},
+		},
+		"gke autopilot": {
+			provider: opencost.GCPProvider,
+			node:     "gk3-cluster-pool-1-a1b2c3d4-xyz1",
+			expected: "gke-autopilot",
+		},
+		"aci": {
+			provider: opencost.AzureProvider,
+			node:     "virtual-node-aci-linux",
+			labels:   map[string]string{"type": "virtual-kubelet"},
+			expected: "aci",
+		},
+		"virtual-kubelet outside azure": {
+			provider: opencost.OracleProvider,
+			node:     "virtual-kubelet",
+			labels:   map[string]string{"type": "virtual-kubelet"},
+		},
+		"oke virtual node": {
+			provider: opencost.OracleProvider,
+			node:     "10.0.10.5",
+			labels:   map[string]string{"node_role_kubernetes_io_virtual_node": ""},
+			expected: "oke-virtual-node",
+		},
+		"vm": {
+			provider: opencost.GCPProvider,
+			node:     "gke-cluster-default-pool-a1b2c3d4-xyz1",
+			labels:   map[string]string{"kubernetes_io_os": "linux"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rule := podPricingRuleFor(tc.provider, tc.node, tc.labels)
+			if tc.expected == "" {
+				if rule != nil {
+					t.Fatalf("expected no rule, got %s", rule.Name)
+				}
+				return
+			}
+			if rule == nil || rule.Name != tc.expected {
+				t.Fatalf("expected rule %s, got %v", tc.expected, rule)
+			}
+		})
+	}
+}
+
+func TestPodPricingRounding(t *testing.T) {
+	cases := map[string]struct {
+		round       func(float64, float64) (float64, float64)
+		cpu, ram    float64
+		expCPU, exp float64
+	}{
+		"fargate minimum":        {roundFargate, 0.1, 0.1, 0.25, 0.5},
+		"fargate overhead":       {roundFargate, 0.25, 1, 0.25, 2},
+		"fargate cpu bound":      {roundFargate, 0.75, 1, 1, 2},
+		"fargate memory bound":   {roundFargate, 0.25, 3, 0.5, 4},
+		"fargate 8 vcpu minimum": {roundFargate, 6, 4, 8, 16},
+		"fargate 8 vcpu step":    {roundFargate, 8, 17, 8, 20},
+		"fargate 8 vcpu maximum": {roundFargate, 8, 59, 8, 60},
+		"fargate 16 vcpu step":   {roundFargate, 12, 33, 16, 40},
+		"fargate 16 vcpu memory": {roundFargate, 4, 62, 16, 64},
+		"autopilot minimum":      {roundAutopilot, 0.1, 0.1, 0.25, 0.5},
+		"autopilot increment":    {roundAutopilot, 0.3, 1, 0.5, 1},
+		"autopilot memory ratio": {roundAutopilot, 0.25, 4, 0.75, 4},
+		"aci":                    {roundACI, 0.3, 1.01, 0.3, 1.1},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cpu, ram := tc.round(tc.cpu, tc.ram)
+			if math.Abs(cpu-tc.expCPU) > 1e-9 || math.Abs(ram-tc.exp) > 1e-9 {
+				t.Fatalf("expected (%f, %f), got (%f, %f)", tc.expCPU, tc.exp, cpu, ram)
+			}
+		})
+	}
+}
+
+func TestApplyPodPricing(t *testing.T) {
+	fargateNodeKey := newNodeKey("cluster1", "fargate-ip-10-0-0-1.ec2.internal")
+	vmNodeKey := newNodeKey("cluster1", "node")
+	virtualNodeKey := newNodeKey("cluster2", "virtual-node")
+
+	nodeMap := map[nodeKey]*nodePricing{
+		fargateNodeKey: {
+			Name: fargateNodeKey.Node,
+		},
+		vmNodeKey: {
+			Name:            vmNodeKey.Node,
+			CostPerCPUHr:    0.025,
+			CostPerRAMGiBHr: 0.0015,
+		},
+		virtualNodeKey: {
+			Name:            virtualNodeKey.Node,
+			CostPerCPUHr:    0.025,
+			CostPerRAMGiBHr: 0.0015,
+		},
+	}
+	clusterProviders := map[string]string{
+		"cluster1": opencost.AWSProvider,
+		"cluster2": opencost.OracleProvider,
+	}
+	applyPodPricedNodes(nodeMap, map[nodeKey]map[string]string{
+		fargateNodeKey: {"eks_amazonaws_com_compute_type": "fargate"},
+		vmNodeKey:      {"kubernetes_io_os": "linux"},
+		virtualNodeKey: {"node_role_kubernetes_io_virtual_node": ""},
+	}, clusterProviders, nil)
+	if nodeMap[vmNodeKey].PodPricing != nil {
+		t.Fatalf("expected node not to be pod-priced")
+	}
+
+	start := time.Date(2020, 6, 16, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	newAlloc := func(key nodeKey, cpuRequest, ramRequest float64) *opencost.Allocation {
+		return &opencost.Allocation{
+			Start:      start,
+			End:        end,
+			Properties: &opencost.AllocationProperties{Cluster: key.Cluster, Node: key.Node},
+			// Usage above requests is not billed on pod-priced nodes.
+			CPUCoreHours:           8,
+			CPUCoreRequestAverage:  cpuRequest,
+			RAMByteHours:           16 * Gi,
+			RAMBytesRequestAverage: ramRequest,
+		}
+	}
+
+	virtual := newAlloc(virtualNodeKey, 2, 4*Gi)
+	app := newAlloc(fargateNodeKey, 0.75, 0.5*Gi)
+	sidecar := newAlloc(fargateNodeKey, 0.25, 0.5*Gi)
+	podMap := map[podKey]*pod{
+		newPodKey("cluster2", "default", "virtual"): {
+			Key:         newPodKey("cluster2", "default", "virtual"),
+			Node:        virtualNodeKey.Node,
+			Allocations: map[string]*opencost.Allocation{"app": virtual},
+		},
+		newPodKey("cluster1", "default", "fargate"): {
+			Key:         newPodKey("cluster1", "default", "fargate"),
+			Node:        fargateNodeKey.Node,
+			Allocations: map[string]*opencost.Allocation{"app": app, "sidecar": sidecar},
+		},
+	}
+	applyPodPricing(podMap, nodeMap)
+
+	// OKE virtual nodes bill requests as-is at the node's rates.
+	if expected := 2 * 2 * 0.025; math.Abs(virtual.CPUCost-expected) > 1e-9 {
+		t.Errorf("expected CPU cost %f, got %f", expected, virtual.CPUCost)
+	}
+	if expected := 4 * 2 * 0.0015; math.Abs(virtual.RAMCost-expected) > 1e-9 {
+		t.Errorf("expected RAM cost %f, got %f", expected, virtual.RAMCost)
+	}
+
+	// The Fargate pod requests 1 vCPU and 1GiB, which with overhead is billed
+	// as 1 vCPU and 2GiB, split between containers by their requests.
+	if expected := 0.75 * 2 * 0.04048; math.Abs(app.CPUCost-expected) > 1e-9 {
+		t.Errorf("expected CPU cost %f, got %f", expected, app.CPUCost)
+	}
+	if expected := 0.25 * 2 * 0.04048; math.Abs(sidecar.CPUCost-expected) > 1e-9 {
+		t.Errorf("expected CPU cost %f, got %f", expected, sidecar.CPUCost)
+	}
+	if expected := 1 * 2 * 0.004445; math.Abs(app.RAMCost-expected) > 1e-9 {
+		t.Errorf("expected RAM cost %f, got %f", expected, app.RAMCost)
+	}
+
+	// Pod-priced allocations are excluded from idle.
+	allocSet := opencost.NewAllocationSet(start, end)
+	vm := newAlloc(vmNodeKey, 1, Gi)
+	vm.Name = "vm"
+	app.Name = "fargate"
+	allocSet.Allocations[vm.Name] = vm
+	allocSet.Allocations[app.Name] = app
+	filtered := withoutPodPricedAllocations(allocSet, map[nodeKey]bool{fargateNodeKey: true})
+	if len(filtered.Allocations) != 1 || filtered.Allocations["vm"] == nil {
+		t.Errorf("expected only the vm allocation, got %v", filtered.Allocations)
+	}
+}
+
+func TestApplyPodPricingVirtualNodeFee(t *testing.T) {
+	key := newNodeKey("cluster1", "10.0.10.5")
+	nodeMap := map[nodeKey]*nodePricing{
+		key: {
+			Name:            key.Node,
+			CostPerCPUHr:    0.025,
+			CostPerRAMGiBHr: 0.0015,
+		},
+	}
+	applyPodPricedNodes(nodeMap, map[nodeKey]map[string]string{
+		key: {"node_role_kubernetes_io_virtual_node": ""},
+	}, map[string]string{"cluster1": opencost.OracleProvider}, &podPricingTestProvider{fee: 0.015})
+
+	start := time.Date(2020, 6, 16, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+	newAlloc := func(cpuRequest, ramRequest float64) *opencost.Allocation {
+		return &opencost.Allocation{
+			Start:                  start,
+			End:                    end,
+			Properties:             &opencost.AllocationProperties{Cluster: key.Cluster, Node: key.Node},
+			CPUCoreRequestAverage:  cpuRequest,
+			RAMBytesRequestAverage: ramRequest,
+		}
+	}
+	app := newAlloc(1.5, 3*Gi)
+	sidecar := newAlloc(0.5, Gi)
+	podMap := map[podKey]*pod{
+		newPodKey("cluster1", "default", "virtual"): {
+			Key:         newPodKey("cluster1", "default", "virtual"),
+			Node:        key.Node,
+			Allocations: map[string]*opencost.Allocation{"app": app, "sidecar": sidecar},
+		},
+	}
+	applyPodPricing(podMap, nodeMap)
+
+	// The virtual node has no asset, so everything OKE bills for the pod, the
+	// virtual node fee included, must be charged to its containers.
+	billed := 2 * (2*0.025 + 4*0.0015 + 0.015)
+	total := app.CPUCost + app.RAMCost + sidecar.CPUCost + sidecar.RAMCost
+	if math.Abs(total-billed) > 1e-9 {
+		t.Errorf("expected total cost %f, got %f", billed, total)
+	}
+	if app.CPUCost <= 2*1.5*0.025 || app.RAMCost <= 2*3*0.0015 {
+		t.Errorf("expected the fee to be split between CPU and RAM, got CPU %f, RAM %f", app.CPUCost, app.RAMCost)
+	}
+}
+
+func TestApplyPodPricedNodesCustomPricing(t *testing.T) {
+	fargateNodeKey := newNodeKey("cluster1", "fargate-ip-10-0-0-1.ec2.internal")
+	virtualNodeKey := newNodeKey("cluster2", "10.0.10.5")
+	autopilotNodeKey := newNodeKey("cluster3", "gk3-cluster-pool-1-a1b2c3d4-xyz1")
+	aciNodeKey := newNodeKey("cluster4", "virtual-node-aci-linux")
+	nodeMap := map[nodeKey]*nodePricing{
+		fargateNodeKey:   {Name: fargateNodeKey.Node},
+		virtualNodeKey:   {Name: virtualNodeKey.Node},
+		autopilotNodeKey: {Name: autopilotNodeKey.Node},
+		aciNodeKey:       {Name: aciNodeKey.Node},
+	}
+	provider := &podPricingTestProvider{
+		config: models.CustomPricing{FargateCPU: "0.05", FargateRAM: "invalid", AutopilotCPU: "0.06"},
+	}
+	applyPodPricedNodes(nodeMap, map[nodeKey]map[string]string{
+		fargateNodeKey: {"eks_amazonaws_com_compute_type": "fargate"},
+		virtualNodeKey: {"node_role_kubernetes_io_virtual_node": ""},
+		aciNodeKey:     {"type": "virtual-kubelet"},
+	}, map[string]string{
+		"cluster1": opencost.AWSProvider,
+		"cluster2": opencost.OracleProvider,
+		"cluster3": opencost.GCPProvider,
+		"cluster4": opencost.AzureProvider,
+	}, provider)
+
+	// Custom pricing overrides the list rates it sets for each platform, while
+	// invalid rates fall back to them.
+	fargate := nodeMap[fargateNodeKey].PodPricing
+	if fargate == nil || fargate.CostPerCPUHr != 0.05 || fargate.CostPerRAMGiBHr != 0.004445 {
+		t.Errorf("expected Fargate rates (0.05, 0.004445), got %v", fargate)
+	}
+	autopilot := nodeMap[autopilotNodeKey].PodPricing
+	if autopilot == nil || autopilot.CostPerCPUHr != 0.06 || autopilot.CostPerRAMGiBHr != 0.0049225 {
+		t.Errorf("expected GKE Autopilot rates (0.06, 0.0049225), got %v", autopilot)
+	}
+
+	// Platforms without custom rates keep their list rates.
+	aci := nodeMap[aciNodeKey].PodPricing
+	if aci == nil || aci.CostPerCPUHr != 0.0486 || aci.CostPerRAMGiBHr != 0.0054 {
+		t.Errorf("expected ACI rates (0.0486, 0.0054), got %v", aci)
+	}
+
+	// OKE virtual nodes keep using the node's rates.
+	if rule := nodeMap[virtualNodeKey].PodPricing; rule == nil || rule.CostPerCPUHr != 0 {
+		t.Errorf("expected OKE virtual node to use node rates, got %v", rule)
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"time"
 
 	"github.com/opencost/opencost/core/pkg/kubeconfig"
+	"github.com/opencost/opencost/core/pkg/kubemodel"
 	"github.com/opencost/opencost/core/pkg/nodestats"
+	"github.com/opencost/opencost/core/pkg/opencost"
 	"github.com/opencost/opencost/core/pkg/protocol"
 	"github.com/opencost/opencost/core/pkg/source"
 	"github.com/opencost/opencost/core/pkg/storage"
@@ -27,6 +29,7 @@ import (
 	"github.com/opencost/opencost/pkg/cloudcost"
 	"github.com/opencost/opencost/pkg/config"
 	"github.com/opencost/opencost/pkg/customcost"
+	"github.com/opencost/opencost/pkg/leader"
 	"github.com/opencost/opencost/pkg/metrics"
 	"github.com/opencost/opencost/pkg/util/watcher"
 
@@ -37,6 +40,7 @@ import (
 	sysenv "github.com/opencost/opencost/core/pkg/env"
 	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/core/pkg/util/json"
+	"github.com/opencost/opencost/core/pkg/util/jsonutil"
 	"github.com/opencost/opencost/modules/collector-source/pkg/collector"
 	"github.com/opencost/opencost/modules/prometheus-source/pkg/prom"
 	"github.com/opencost/opencost/pkg/cloud/azure"
@@ -78,6 +82,7 @@ type Accesses struct {
 	ConfigFileManager   *config.ConfigFileManager
 	ClusterInfoProvider clusters.ClusterInfoProvider
 	Model               *CostModel
+	KubeModel           *kubemodel.SnapshotBuilder
 	MetricsEmitter      *CostModelMetricsEmitter
 	// SettingsCache stores current state of app settings
 	SettingsCache *cache.Cache
@@ -554,6 +559,9 @@ func Initialize(router *httprouter.Router, additionalConfigWatchers ...*watcher.
 	settingsCache := cache.New(cache.NoExpiration, cache.NoExpiration)
 
 	costModel := NewCostModel(dataSource, cloudProvider, k8sCache, clusterMap, dataSource.BatchDuration())
+	costModel.SharingRules = newSharingRulesFromConfig(dataSource)
+	costModel.LabelNormalizer = newLabelNormalizerFromConfig()
+	costModel.CostCenters = newCostCenterMappingFromConfig()
 	metricsEmitter := NewCostModelMetricsEmitter(k8sCache, cloudProvider, clusterInfoProvider, costModel)
 
 	a := &Accesses{
@@ -565,6 +573,7 @@ func Initialize(router *httprouter.Router, additionalConfigWatchers ...*watcher.
 		ConfigFileManager:   confManager,
 		ClusterInfoProvider: clusterInfoProvider,
 		Model:               costModel,
+		KubeModel:           kubemodel.NewSnapshotBuilder(clusterInfoProvider, k8sCache, dataSource.Metrics()),
 		MetricsEmitter:      metricsEmitter,
 		SettingsCache:       settingsCache,
 	}
@@ -635,14 +644,118 @@ func GetDefaultCollectorStorage() storage.Storage {
 	return storage.NewFileStorage(dir)
 }
 
-// InitializeCloudCost Initializes Cloud Cost pipeline and querier and registers endpoints
-func InitializeCloudCost(router *httprouter.Router, providerConfig models.ProviderConfig) *cloudcost.PipelineService {
+// newSharingRulesFromConfig loads the shared cost distribution rules from the
+// sharing rules config file, if one exists. Metric distributions are queried
+// from the data source when it is backed by Prometheus.
+func newSharingRulesFromConfig(dataSource source.OpenCostDataSource) *SharingRules {
+	path := env.GetSharingRulesConfigFile()
+	config, err := LoadSharingRulesConfig(path)
+	if err != nil {
+		log.Errorf("error loading sharing rules config: %v", err)
+		return nil
+	}
+	if config == nil {
+		return nil
+	}
+
+	sharingRules, err := NewSharingRules(config)
+	if err != nil {
+		log.Errorf("error creating sharing rules from %s: %v", path, err)
+		return nil
+	}
+
+	if pds, ok := dataSource.(*prom.PrometheusDataSource); ok {
+		sharingRules.MetricQuerier = &promSharingMetricQuerier{contexts: pds.PrometheusContexts()}
+	} else if names := sharingRules.MetricRules(); len(names) > 0 {
+		log.Errorf("Sharing rules %s in %s are distributed by metric, which is only supported with a Prometheus data source. They are skipped by allocation queries.", strings.Join(names, ", "), path)
+	}
+
+	log.Infof("Shared cost distribution enabled with %d rules from %s", sharingRules.Len(), path)
+	return sharingRules
+}
+
+// newCostCenterMappingFromConfig loads the cost center hierarchy from the cost
+// centers config file, if one exists.
+func newCostCenterMappingFromConfig() *opencost.CostCenterMapping {
+	path := env.GetCostCentersConfigFile()
+	config := &opencost.CostCenterConfig{}
+	ok, err := jsonutil.LoadFile(path, config)
+	if err != nil {
+		log.Errorf("error loading cost centers config: %v", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	mapping, err := opencost.NewCostCenterMapping(config)
+	if err != nil {
+		log.Errorf("error creating cost center mapping from %s: %v", path, err)
+		return nil
+	}
+
+	log.Infof("Cost center mapping enabled with levels %v from %s", mapping.Levels(), path)
+	return mapping
+}
+
+// labelRulesConfig is the file representation of the label normalization
+// rules.
+type labelRulesConfig struct {
+	Rules []*opencost.LabelRule `json:"rules"`
+}
+
+// newLabelNormalizerFromConfig loads the label normalization rules from the
+// label rules config file, if one exists.
+func newLabelNormalizerFromConfig() *opencost.LabelNormalizer {
+	path := env.GetLabelRulesConfigFile()
+	config := &labelRulesConfig{}
+	ok, err := jsonutil.LoadFile(path, config)
+	if err != nil {
+		log.Errorf("error loading label rules config: %v", err)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	normalizer, err := opencost.NewLabelNormalizer(config.Rules)
+	if err != nil {
+		log.Errorf("error creating label normalizer from %s: %v", path, err)
+		return nil
+	}
+
+	log.Infof("Label normalization enabled with %d rules from %s", len(config.Rules), path)
+	return normalizer
+}
+
+// promSharingMetricQuerier runs each sharing rule metric query in a new
+// Prometheus query context.
+type promSharingMetricQuerier struct {
+	contexts *prom.ContextFactory
+}
+
+func (q *promSharingMetricQuerier) QueryAtTime(query string, t time.Time) source.QueryResultsChan {
+	return q.contexts.NewNamedContext(prom.AllocationContextName).QueryAtTime(query, t)
+}
+
+// InitializeCloudCost Initializes Cloud Cost pipeline and querier and registers endpoints. The ingestors only run
+// while the elector leads. If a store is provided, cloud costs are kept in it rather than in memory, so that they can
+// be read by every replica.
+func InitializeCloudCost(router *httprouter.Router, providerConfig models.ProviderConfig, elector leader.Elector, store storage.Storage) *cloudcost.PipelineService {
 	log.Debugf("Cloud Cost config path: %s", env.GetCloudCostConfigPath())
 	cloudConfigController := cloudconfig.NewMemoryController(providerConfig)
 
-	repo := cloudcost.NewMemoryRepository()
-	cloudCostPipelineService := cloudcost.NewPipelineService(repo, cloudConfigController, cloudcost.DefaultIngestorConfiguration())
+	var repo cloudcost.Repository = cloudcost.NewMemoryRepository()
+	if store != nil {
+		repo = cloudcost.NewStorageRepository(store)
+	}
+	ingConfig := cloudcost.DefaultIngestorConfiguration()
+	ingConfig.Standby = true
+	cloudCostPipelineService := cloudcost.NewPipelineService(repo, cloudConfigController, ingConfig)
+	elector.Register("cloud cost ingestion", leader.RunWhile(cloudCostPipelineService.StartIngestion, cloudCostPipelineService.StopIngestion))
 	repoQuerier := cloudcost.NewRepositoryQuerier(repo)
+	repoQuerier.LabelNormalizer = newLabelNormalizerFromConfig()
+	repoQuerier.CostCenters = newCostCenterMappingFromConfig()
 	cloudCostQueryService := cloudcost.NewQueryService(repoQuerier, repoQuerier)
 
 	router.GET("/cloud/config/export", cloudConfigController.GetExportConfigHandler())
@@ -662,20 +775,44 @@ func InitializeCloudCost(router *httprouter.Router, providerConfig models.Provid
 	return cloudCostPipelineService
 }
 
-func InitializeCustomCost(router *httprouter.Router) *customcost.PipelineService {
-	hourlyRepo := customcost.NewMemoryRepository()
-	dailyRepo := customcost.NewMemoryRepository()
+// InitializeCustomCost Initializes Custom Cost pipeline and querier and registers endpoints. The ingestors only run
+// while the elector leads. If a store is provided, custom costs are kept in it rather than in memory, so that they can
+// be read by every replica.
+func InitializeCustomCost(router *httprouter.Router, model *CostModel, elector leader.Elector, store storage.Storage) *customcost.PipelineService {
+	var hourlyRepo, dailyRepo customcost.Repository = customcost.NewMemoryRepository(), customcost.NewMemoryRepository()
+	if store != nil {
+		hourlyRepo = customcost.NewStorageRepository(store, "customcost/hourly")
+		dailyRepo = customcost.NewStorageRepository(store, "customcost/daily")
+	}
 	ingConfig := customcost.DefaultIngestorConfiguration()
+	ingConfig.Standby = true
 	var err error
 	customCostPipelineService, err := customcost.NewPipelineService(hourlyRepo, dailyRepo, ingConfig)
 	if err != nil {
 		log.Errorf("error instantiating custom cost pipeline service: %v", err)
 		return nil
 	}
+	elector.Register("custom cost ingestion", leader.RunWhile(customCostPipelineService.StartIngestion, customCostPipelineService.StopIngestion))
 
 	customCostQuerier := customcost.NewRepositoryQuerier(hourlyRepo, dailyRepo, ingConfig.HourlyDuration, ingConfig.DailyDuration)
 	customCostQueryService := customcost.NewQueryService(customCostQuerier)
 
+	if model != nil {
+		attributionConfigPath := env.GetCustomCostAttributionConfigPath()
+		attributionConfig, err := customcost.LoadAttributionConfig(attributionConfigPath)
+		if err != nil {
+			log.Errorf("error loading custom cost attribution config: %v", err)
+		} else if attributionConfig != nil {
+			attributor, err := customcost.NewAttributor(customCostQuerier, attributionConfig)
+			if err != nil {
+				log.Errorf("error creating custom cost attributor from %s: %v", attributionConfigPath, err)
+			} else {
+				log.Infof("Custom cost attribution enabled with %d rules from %s", len(attributionConfig.Rules), attributionConfigPath)
+				model.CustomCostAttributor = attributor
+			}
+		}
+	}
+
 	router.GET("/customCost/total", customCostQueryService.GetCustomCostTotalHandler())
 	router.GET("/customCost/timeseries", customCostQueryService.GetCustomCostTimeseriesHandler())
 
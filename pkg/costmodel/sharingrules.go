@@ -0,0 +1,319 @@
+package costmodel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/filter"
+	"github.com/opencost/opencost/core/pkg/filter/allocation"
+	"github.com/opencost/opencost/core/pkg/filter/cloudcost"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/core/pkg/util/jsonutil"
+	"github.com/opencost/opencost/core/pkg/util/timeutil"
+	cloudcostquerier "github.com/opencost/opencost/pkg/cloudcost"
+)
+
+// ShareDistributionMetric distributes a sharing rule's costs in proportion to
+// the values of a custom Prometheus metric, which are resolved to fixed
+// weights before aggregation.
+const ShareDistributionMetric = "metric"
+
+// SharingRulesConfig is the file representation of the shared cost
+// distribution rules applied by QueryAllocation.
+type SharingRulesConfig struct {
+	Rules []SharingRuleConfig `json:"rules"`
+}
+
+// SharingRuleConfig describes the costs shared by a single rule, and how they
+// are distributed among the rule's target allocations.
+type SharingRuleConfig struct {
+	Name   string            `json:"name"`
+	Source SharingRuleSource `json:"source"`
+	// Target is an allocation filter selecting the allocations which receive
+	// the shared costs. Empty shares with all unshared allocations.
+	Target string `json:"target,omitempty"`
+	// DistributeBy is one of "even", "totalCost", "cpuRequest", "ramRequest",
+	// "weights" or "metric". Defaults to "totalCost".
+	DistributeBy string `json:"distributeBy,omitempty"`
+	// WeightBy is the aggregation property, e.g. "namespace" or "label:team",
+	// by which Weights or metric values are keyed.
+	WeightBy string             `json:"weightBy,omitempty"`
+	Weights  map[string]float64 `json:"weights,omitempty"`
+	Metric   *SharingRuleMetric `json:"metric,omitempty"`
+}
+
+// SharingRuleSource describes the costs shared by a rule. Any combination of
+// the fields may be set, and the resulting costs are summed.
+type SharingRuleSource struct {
+	// Filter is an allocation filter selecting the allocations to share
+	Filter string `json:"filter,omitempty"`
+	// HourlyCost is a fixed hourly cost to share
+	HourlyCost float64 `json:"hourlyCost,omitempty"`
+	// CloudCostFilter is a cloud cost filter selecting cloud cost items, the
+	// cost of which is shared at its average hourly rate over the query window
+	CloudCostFilter string `json:"cloudCostFilter,omitempty"`
+	// CostMetric is the cloud cost metric to share. Defaults to
+	// "amortizedNetCost".
+	CostMetric string `json:"costMetric,omitempty"`
+}
+
+// SharingRuleMetric is a Prometheus query used to weight the targets of a
+// rule. The query is evaluated at the end of the query window, with any
+// occurrence of "$window" replaced by the window duration, e.g.
+// `sum(increase(nginx_ingress_controller_requests[$window])) by (namespace)`.
+type SharingRuleMetric struct {
+	Query string `json:"query"`
+	// Label is the label of each result whose value is matched against the
+	// WeightBy property of the rule's targets.
+	Label string `json:"label"`
+}
+
+// LoadSharingRulesConfig reads the sharing rules from the given JSON file. A
+// missing file is not an error and results in a nil config.
+func LoadSharingRulesConfig(path string) (*SharingRulesConfig, error) {
+	config := &SharingRulesConfig{}
+	ok, err := jsonutil.LoadFile(path, config)
+	if err != nil {
+		return nil, fmt.Errorf("loading sharing rules config: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return config, nil
+}
+
+// SharingMetricQuerier runs the Prometheus queries of metric-weighted sharing
+// rules.
+type SharingMetricQuerier interface {
+	QueryAtTime(query string, t time.Time) source.QueryResultsChan
+}
+
+type sharingRule struct {
+	SharingRuleConfig
+	source          filter.Filter
+	target          filter.Filter
+	cloudCostFilter filter.Filter
+	costMetric      opencost.CostMetricName
+	distributeBy    opencost.ShareDistribution
+}
+
+// SharingRules resolves a SharingRulesConfig into the opencost.SharingRules
+// applied during aggregation, querying the cloud costs and metrics that the
+// rules depend on for each query window.
+type SharingRules struct {
+	// CloudCostQuerier, if set, provides the costs of cloud cost sources
+	CloudCostQuerier cloudcostquerier.Querier
+	// MetricQuerier, if set, provides the weights of metric distributions
+	MetricQuerier SharingMetricQuerier
+
+	rules []*sharingRule
+}
+
+// NewSharingRules validates and parses the given config.
+func NewSharingRules(config *SharingRulesConfig) (*SharingRules, error) {
+	if config == nil {
+		return nil, fmt.Errorf("sharing rules config is nil")
+	}
+
+	allocParser := allocation.NewAllocationFilterParser()
+	cloudCostParser := cloudcost.NewCloudCostFilterParser()
+
+	var rules []*sharingRule
+	for i, rule := range config.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("sharing rule %d: missing name", i)
+		}
+
+		sr := &sharingRule{
+			SharingRuleConfig: rule,
+			costMetric:        opencost.CostMetricAmortizedNetCost,
+		}
+
+		var err error
+		if rule.Source.Filter != "" {
+			sr.source, err = allocParser.Parse(rule.Source.Filter)
+			if err != nil {
+				return nil, fmt.Errorf("sharing rule %s: parsing source filter: %w", rule.Name, err)
+			}
+		}
+
+		if rule.Source.CloudCostFilter != "" {
+			sr.cloudCostFilter, err = cloudCostParser.Parse(rule.Source.CloudCostFilter)
+			if err != nil {
+				return nil, fmt.Errorf("sharing rule %s: parsing cloud cost filter: %w", rule.Name, err)
+			}
+		}
+
+		if rule.Source.CostMetric != "" {
+			sr.costMetric, err = opencost.ParseCostMetricName(rule.Source.CostMetric)
+			if err != nil {
+				return nil, fmt.Errorf("sharing rule %s: %w", rule.Name, err)
+			}
+		}
+
+		if sr.source == nil && sr.cloudCostFilter == nil && rule.Source.HourlyCost <= 0 {
+			return nil, fmt.Errorf("sharing rule %s: source requires a filter, hourlyCost or cloudCostFilter", rule.Name)
+		}
+
+		if rule.Target != "" {
+			sr.target, err = allocParser.Parse(rule.Target)
+			if err != nil {
+				return nil, fmt.Errorf("sharing rule %s: parsing target filter: %w", rule.Name, err)
+			}
+		}
+
+		if rule.DistributeBy == ShareDistributionMetric {
+			if rule.Metric == nil || rule.Metric.Query == "" || rule.Metric.Label == "" {
+				return nil, fmt.Errorf("sharing rule %s: metric distribution requires a metric query and label", rule.Name)
+			}
+			sr.distributeBy = opencost.ShareDistributionWeights
+		} else {
+			sr.distributeBy, err = opencost.ParseShareDistribution(rule.DistributeBy)
+			if err != nil {
+				return nil, fmt.Errorf("sharing rule %s: %w", rule.Name, err)
+			}
+		}
+
+		if sr.distributeBy == opencost.ShareDistributionWeights && rule.WeightBy == "" {
+			return nil, fmt.Errorf("sharing rule %s: %s distribution requires weightBy", rule.Name, rule.DistributeBy)
+		}
+
+		rules = append(rules, sr)
+	}
+
+	return &SharingRules{rules: rules}, nil
+}
+
+// Len returns the number of configured rules.
+func (srs *SharingRules) Len() int {
+	if srs == nil {
+		return 0
+	}
+	return len(srs.rules)
+}
+
+// MetricRules returns the names of the rules which are distributed by metric.
+func (srs *SharingRules) MetricRules() []string {
+	if srs == nil {
+		return nil
+	}
+
+	var names []string
+	for _, rule := range srs.rules {
+		if rule.DistributeBy == ShareDistributionMetric {
+			names = append(names, rule.Name)
+		}
+	}
+	return names
+}
+
+// Resolve returns the opencost.SharingRules for the given query window,
+// converting cloud cost sources to hourly costs and metric distributions to
+// weights. Rules distributed by metric are skipped, with a warning, without a
+// MetricQuerier, which is only available with a Prometheus data source.
+func (srs *SharingRules) Resolve(ctx context.Context, window opencost.Window) ([]*opencost.SharingRule, error) {
+	if srs == nil || len(srs.rules) == 0 {
+		return nil, nil
+	}
+
+	if window.IsOpen() || window.Duration() <= 0 {
+		return nil, fmt.Errorf("illegal window: %s", window)
+	}
+
+	result := make([]*opencost.SharingRule, 0, len(srs.rules))
+	for _, rule := range srs.rules {
+		if rule.DistributeBy == ShareDistributionMetric && srs.MetricQuerier == nil {
+			log.DedupedWarningf(5, "CostModel: skipping sharing rule %s: metric distribution is only supported with a Prometheus data source", rule.Name)
+			continue
+		}
+
+		resolved := &opencost.SharingRule{
+			Name:         rule.Name,
+			Source:       rule.source,
+			HourlyCost:   rule.Source.HourlyCost,
+			Target:       rule.target,
+			DistributeBy: rule.distributeBy,
+			WeightBy:     rule.WeightBy,
+			Weights:      rule.Weights,
+		}
+
+		if rule.cloudCostFilter != nil {
+			hourlyCost, err := srs.cloudCostHourlyCost(ctx, rule, window)
+			if err != nil {
+				return nil, fmt.Errorf("sharing rule %s: %w", rule.Name, err)
+			}
+			resolved.HourlyCost += hourlyCost
+		}
+
+		if rule.DistributeBy == ShareDistributionMetric {
+			weights, err := srs.metricWeights(rule, window)
+			if err != nil {
+				return nil, fmt.Errorf("sharing rule %s: %w", rule.Name, err)
+			}
+			resolved.Weights = weights
+		}
+
+		result = append(result, resolved)
+	}
+
+	return result, nil
+}
+
+func (srs *SharingRules) cloudCostHourlyCost(ctx context.Context, rule *sharingRule, window opencost.Window) (float64, error) {
+	if srs.CloudCostQuerier == nil {
+		return 0, fmt.Errorf("cloud cost source requires cloud costs to be enabled")
+	}
+
+	ccsr, err := srs.CloudCostQuerier.Query(ctx, cloudcostquerier.QueryRequest{
+		Start:      *window.Start(),
+		End:        *window.End(),
+		Accumulate: opencost.AccumulateOptionAll,
+		Filter:     rule.cloudCostFilter,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("querying cloud costs: %w", err)
+	}
+
+	total := 0.0
+	for _, ccs := range ccsr.CloudCostSets {
+		for _, cc := range ccs.CloudCosts {
+			cm, err := cc.GetCostMetric(rule.costMetric)
+			if err != nil {
+				return 0, err
+			}
+			total += cm.Cost
+		}
+	}
+
+	return total / window.Hours(), nil
+}
+
+func (srs *SharingRules) metricWeights(rule *sharingRule, window opencost.Window) (map[string]float64, error) {
+	query := strings.ReplaceAll(rule.Metric.Query, "$window", timeutil.DurationString(window.Duration()))
+	results, err := srs.MetricQuerier.QueryAtTime(query, *window.End()).Await()
+	if err != nil {
+		return nil, fmt.Errorf("querying metric: %w", err)
+	}
+
+	weights := map[string]float64{}
+	for _, res := range results {
+		key, err := res.GetString(rule.Metric.Label)
+		if err != nil {
+			log.DedupedWarningf(5, "CostModel: sharing rule %s: metric result missing label %s", rule.Name, rule.Metric.Label)
+			continue
+		}
+
+		if len(res.Values) == 0 {
+			continue
+		}
+
+		weights[key] += res.Values[0].Value
+	}
+
+	return weights, nil
+}
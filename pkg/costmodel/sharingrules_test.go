@@ -0,0 +1,192 @@
+package costmodel
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/core/pkg/util"
+	"github.com/opencost/opencost/pkg/cloudcost"
+)
+
+type mockSharingCloudCostQuerier struct {
+	ccsr *opencost.CloudCostSetRange
+}
+
+func (mq *mockSharingCloudCostQuerier) Query(ctx context.Context, request cloudcost.QueryRequest) (*opencost.CloudCostSetRange, error) {
+	return mq.ccsr, nil
+}
+
+type mockSharingMetricQuerier struct {
+	query   string
+	results []*source.QueryResult
+}
+
+func (mq *mockSharingMetricQuerier) QueryAtTime(query string, t time.Time) source.QueryResultsChan {
+	mq.query = query
+
+	resCh := make(source.QueryResultsChan)
+	go func() {
+		resCh <- &source.QueryResults{Query: query, Results: mq.results}
+	}()
+	return resCh
+}
+
+func TestSharingRules_Resolve(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	window := opencost.NewClosedWindow(start, end)
+
+	ccs := opencost.NewCloudCostSet(start, end)
+	ccs.Insert(opencost.NewCloudCost(start, end, &opencost.CloudCostProperties{ProviderID: "support", Service: "support"}, 0, 0, 0, 48, 0, 0))
+
+	sharingRules, err := NewSharingRules(&SharingRulesConfig{
+		Rules: []SharingRuleConfig{
+			{
+				Name:   "monitoring",
+				Source: SharingRuleSource{Filter: `namespace:"monitoring"`, HourlyCost: 1},
+				Target: `namespace!:"kube-system"`,
+			},
+			{
+				Name:         "ingress",
+				Source:       SharingRuleSource{CloudCostFilter: `service:"support"`},
+				DistributeBy: ShareDistributionMetric,
+				WeightBy:     opencost.AllocationNamespaceProp,
+				Metric: &SharingRuleMetric{
+					Query: `sum(increase(requests_total[$window])) by (namespace)`,
+					Label: "namespace",
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	metricQuerier := &mockSharingMetricQuerier{
+		results: []*source.QueryResult{
+			source.NewQueryResult(map[string]any{"namespace": "payments"}, []*util.Vector{{Value: 30}}, nil),
+			source.NewQueryResult(map[string]any{"namespace": "search"}, []*util.Vector{{Value: 10}}, nil),
+		},
+	}
+	sharingRules.MetricQuerier = metricQuerier
+	sharingRules.CloudCostQuerier = &mockSharingCloudCostQuerier{
+		ccsr: &opencost.CloudCostSetRange{CloudCostSets: []*opencost.CloudCostSet{ccs}, Window: window},
+	}
+
+	rules, err := sharingRules.Resolve(context.Background(), window)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+
+	monitoring := rules[0]
+	if monitoring.Source == nil || monitoring.Target == nil {
+		t.Errorf("expected source and target filters")
+	}
+	if monitoring.DistributeBy != opencost.ShareDistributionTotalCost {
+		t.Errorf("expected default distribution %s, got %s", opencost.ShareDistributionTotalCost, monitoring.DistributeBy)
+	}
+
+	ingress := rules[1]
+	if math.Abs(ingress.HourlyCost-2.0) > 0.0001 {
+		t.Errorf("expected cloud cost hourly cost 2.0, got %f", ingress.HourlyCost)
+	}
+	if ingress.DistributeBy != opencost.ShareDistributionWeights {
+		t.Errorf("expected distribution %s, got %s", opencost.ShareDistributionWeights, ingress.DistributeBy)
+	}
+	if ingress.Weights["payments"] != 30 || ingress.Weights["search"] != 10 {
+		t.Errorf("unexpected weights: %v", ingress.Weights)
+	}
+	if metricQuerier.query != `sum(increase(requests_total[1d])) by (namespace)` {
+		t.Errorf("unexpected metric query: %s", metricQuerier.query)
+	}
+}
+
+func TestSharingRules_Resolve_MetricUnsupported(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := opencost.NewClosedWindow(start, start.Add(24*time.Hour))
+
+	sharingRules, err := NewSharingRules(&SharingRulesConfig{
+		Rules: []SharingRuleConfig{
+			{
+				Name:   "monitoring",
+				Source: SharingRuleSource{HourlyCost: 1},
+			},
+			{
+				Name:         "ingress",
+				Source:       SharingRuleSource{HourlyCost: 1},
+				DistributeBy: ShareDistributionMetric,
+				WeightBy:     opencost.AllocationNamespaceProp,
+				Metric:       &SharingRuleMetric{Query: `sum(requests_total) by (namespace)`, Label: "namespace"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if names := sharingRules.MetricRules(); len(names) != 1 || names[0] != "ingress" {
+		t.Errorf("expected metric rules [ingress], got %v", names)
+	}
+
+	// without a Prometheus data source there is no MetricQuerier, so the
+	// metric rule is skipped and the other rules still apply
+	rules, err := sharingRules.Resolve(context.Background(), window)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rules) != 1 || rules[0].Name != "monitoring" {
+		t.Fatalf("expected only the monitoring rule, got %v", rules)
+	}
+
+	as := opencost.NewAllocationSet(start, start.Add(24*time.Hour))
+	for _, ns := range []string{"payments", "search"} {
+		as.Set(opencost.NewMockUnitAllocation("cluster1/node1/"+ns+"/pod/container", start, 24*time.Hour, &opencost.AllocationProperties{
+			Cluster:   "cluster1",
+			Node:      "node1",
+			Namespace: ns,
+			Pod:       "pod",
+			Container: "container",
+		}))
+	}
+	totalCost := as.TotalCost()
+
+	err = as.AggregateBy([]string{opencost.AllocationNamespaceProp}, &opencost.AllocationAggregationOptions{
+		SharingRules: rules,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error aggregating: %s", err)
+	}
+
+	// the monitoring rule shares 24.0 over the day
+	if math.Abs(as.TotalCost()-(totalCost+24)) > 0.0001 {
+		t.Errorf("expected total cost %f, got %f", totalCost+24, as.TotalCost())
+	}
+}
+
+func TestNewSharingRules_Invalid(t *testing.T) {
+	testCases := map[string]*SharingRulesConfig{
+		"nil config":       nil,
+		"missing name":     {Rules: []SharingRuleConfig{{Source: SharingRuleSource{HourlyCost: 1}}}},
+		"missing source":   {Rules: []SharingRuleConfig{{Name: "a"}}},
+		"bad filter":       {Rules: []SharingRuleConfig{{Name: "a", Source: SharingRuleSource{Filter: `namespace:`}}}},
+		"bad distribution": {Rules: []SharingRuleConfig{{Name: "a", Source: SharingRuleSource{HourlyCost: 1}, DistributeBy: "pods"}}},
+		"missing weightBy": {Rules: []SharingRuleConfig{{Name: "a", Source: SharingRuleSource{HourlyCost: 1}, DistributeBy: "weights"}}},
+		"missing metric":   {Rules: []SharingRuleConfig{{Name: "a", Source: SharingRuleSource{HourlyCost: 1}, DistributeBy: "metric", WeightBy: "namespace"}}},
+	}
+
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSharingRules(config)
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package costmodel
+
+import (
+	"context"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/source"
+	"github.com/opencost/opencost/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/opencost/opencost/pkg/costmodel"
+
+// startSpan starts a span of the named computation over the given window.
+func startSpan(ctx context.Context, name string, start, end time.Time, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("window.start", start.UTC().Format(time.RFC3339)),
+		attribute.String("window.end", end.UTC().Format(time.RFC3339)),
+	)
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error of the computation, if any, and ends its span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		telemetry.RecordError(span, err)
+	}
+	span.End()
+}
+
+// startStage begins both the explain stage and the span of the named stage of a computation over the given window.
+// It returns the context of the span, for the queries made by the stage, and the function which ends the stage with
+// its error.
+func startStage(ctx context.Context, name string, start, end time.Time) (context.Context, func(error)) {
+	done := source.ExplainFromContext(ctx).StartStage(name, start, end)
+	ctx, span := startSpan(ctx, name, start, end)
+
+	return ctx, func(err error) {
+		done()
+		endSpan(span, err)
+	}
+}
@@ -0,0 +1,175 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/util/json"
+	"github.com/opencost/opencost/pkg/apis/v1alpha1"
+	"github.com/opencost/opencost/pkg/cloud"
+	"github.com/opencost/opencost/pkg/cloud/config"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// activeRequeueInterval is how long to wait before checking again whether the integrations of a
+// CloudCostIntegration have been picked up by the config controller, which pulls its watchers periodically.
+const activeRequeueInterval = 15 * time.Second
+
+// secretRequeueInterval is how often resources which read their config from a Secret are reconciled, as changes to
+// Secrets are not watched.
+const secretRequeueInterval = 5 * time.Minute
+
+// CloudIntegrationReconciler feeds the billing integrations of CloudCostIntegration resources to the config
+// controller of the cloud cost pipeline, through a CRDWatcher.
+type CloudIntegrationReconciler struct {
+	client.Client
+	// SecretReader reads the Secrets referenced by CloudCostIntegrations
+	SecretReader     client.Reader
+	ConfigController *config.Controller
+	Watcher          *config.CRDWatcher
+}
+
+// Reconcile validates the integrations of a CloudCostIntegration and hands them to the watcher, then reports
+// whether the config controller has made them active.
+func (r *CloudIntegrationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	name := req.NamespacedName.String()
+
+	cci := &v1alpha1.CloudCostIntegration{}
+	err := r.Get(ctx, req.NamespacedName, cci)
+	if apierrors.IsNotFound(err) {
+		r.Watcher.DeleteConfigs(name)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("getting CloudCostIntegration: %w", err)
+	}
+	if !cci.DeletionTimestamp.IsZero() {
+		r.Watcher.DeleteConfigs(name)
+		return ctrl.Result{}, nil
+	}
+
+	status := cci.Status.DeepCopy()
+	status.ObservedGeneration = cci.Generation
+
+	var result ctrl.Result
+	if cci.Spec.ConfigSecretRef != nil {
+		result.RequeueAfter = secretRequeueInterval
+	}
+
+	configs, reason, err := r.loadConfigs(ctx, cci)
+	if err != nil {
+		log.Warnf("CloudIntegrationReconciler: %s: %s", name, err)
+		r.Watcher.DeleteConfigs(name)
+		status.Integrations = nil
+		setCondition(&status.Conditions, cci.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, reason, err.Error())
+		setCondition(&status.Conditions, cci.Generation, v1alpha1.ConditionActive, metav1.ConditionFalse, v1alpha1.ReasonInactive, "no valid integrations")
+	} else {
+		r.Watcher.SetConfigs(name, configs)
+		setCondition(&status.Conditions, cci.Generation, v1alpha1.ConditionReady, metav1.ConditionTrue, v1alpha1.ReasonApplied,
+			fmt.Sprintf("%d integration(s) applied", len(configs)))
+		if r.setActive(cci.Generation, status, configs) {
+			result.RequeueAfter = activeRequeueInterval
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(status, &cci.Status) {
+		cci.Status = *status
+		err = r.Status().Update(ctx, cci)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating status of CloudCostIntegration: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// loadConfigs reads and validates the integrations of a CloudCostIntegration, returning the reason for the Ready
+// condition on failure.
+func (r *CloudIntegrationReconciler) loadConfigs(ctx context.Context, cci *v1alpha1.CloudCostIntegration) ([]cloud.KeyedConfig, string, error) {
+	data, err := readConfig(ctx, r.SecretReader, cci.Namespace, cci.Spec.Config.Raw, cci.Spec.ConfigSecretRef)
+	if err != nil {
+		return nil, configErrorReason(err), err
+	}
+
+	configurations := &config.Configurations{}
+	err = json.Unmarshal(data, configurations)
+	if err != nil {
+		return nil, v1alpha1.ReasonInvalidConfig, fmt.Errorf("parsing config: %w", err)
+	}
+
+	configs := configurations.ToSlice()
+	if len(configs) == 0 {
+		return nil, v1alpha1.ReasonInvalidConfig, fmt.Errorf("config contains no integrations")
+	}
+
+	for _, conf := range configs {
+		err = conf.Validate()
+		if err != nil {
+			return nil, v1alpha1.ReasonInvalidConfig, fmt.Errorf("invalid integration %s: %w", conf.Key(), err)
+		}
+	}
+
+	return configs, "", nil
+}
+
+// setActive sets the integration statuses and Active condition from the config controller, returning true if any
+// integration has not yet been picked up by the controller.
+func (r *CloudIntegrationReconciler) setActive(generation int64, status *v1alpha1.CloudCostIntegrationStatus, configs []cloud.KeyedConfig) bool {
+	statuses := map[string]config.Status{}
+	if r.ConfigController != nil {
+		for _, s := range r.ConfigController.GetStatus() {
+			if s.Source == config.CRDSource {
+				statuses[s.Key] = s
+			}
+		}
+	}
+
+	pending := false
+	active := 0
+	status.Integrations = make([]v1alpha1.IntegrationStatus, 0, len(configs))
+	for _, conf := range configs {
+		configType, _ := config.ConfigTypeFromConfig(conf)
+		is := v1alpha1.IntegrationStatus{
+			Key:  conf.Key(),
+			Type: configType,
+		}
+
+		s, ok := statuses[conf.Key()]
+		if !ok || !s.Config.Equals(conf) {
+			pending = true
+		} else if s.Active {
+			is.Active = true
+			active++
+		}
+		status.Integrations = append(status.Integrations, is)
+	}
+
+	switch {
+	case pending:
+		setCondition(&status.Conditions, generation, v1alpha1.ConditionActive, metav1.ConditionUnknown, v1alpha1.ReasonPending,
+			"waiting for the cloud cost pipeline to load the integrations")
+	case active == len(configs):
+		setCondition(&status.Conditions, generation, v1alpha1.ConditionActive, metav1.ConditionTrue, v1alpha1.ReasonActive,
+			"all integrations are active")
+	default:
+		setCondition(&status.Conditions, generation, v1alpha1.ConditionActive, metav1.ConditionFalse, v1alpha1.ReasonInactive,
+			fmt.Sprintf("%d of %d integrations are active; the others are disabled or overridden by another source", active, len(configs)))
+	}
+
+	return pending
+}
+
+// SetupWithManager registers the reconciler with the manager
+func (r *CloudIntegrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.CloudCostIntegration{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,72 @@
+package crd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/opencost/opencost/pkg/apis/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// setCondition sets a condition for the given generation of a resource
+func setCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// readConfig returns the configuration of a resource, which is read from the referenced Secret if set, or else the
+// inline config. Secrets are read through the given reader, which should not be cached, so that the controller does
+// not watch every Secret in its namespace.
+func readConfig(ctx context.Context, reader client.Reader, namespace string, inline []byte, ref *v1alpha1.SecretKeySelector) ([]byte, error) {
+	if ref == nil {
+		if len(inline) == 0 {
+			return nil, fmt.Errorf("one of config or configSecretRef is required")
+		}
+		return inline, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret)
+	if err != nil {
+		return nil, &secretError{err: fmt.Errorf("getting secret %s: %w", ref.Name, err)}
+	}
+
+	data, ok := secret.Data[ref.Key]
+	if !ok || len(data) == 0 {
+		return nil, &secretError{err: fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)}
+	}
+
+	return data, nil
+}
+
+// secretError is returned by readConfig when the referenced Secret cannot be read
+type secretError struct {
+	err error
+}
+
+func (se *secretError) Error() string {
+	return se.err.Error()
+}
+
+func (se *secretError) Unwrap() error {
+	return se.err
+}
+
+// configErrorReason returns the condition reason for an error returned by readConfig
+func configErrorReason(err error) string {
+	var se *secretError
+	if errors.As(err, &se) {
+		return v1alpha1.ReasonSecretNotFound
+	}
+	return v1alpha1.ReasonInvalidConfig
+}
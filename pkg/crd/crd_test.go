@@ -0,0 +1,335 @@
+package crd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/pkg/apis/v1alpha1"
+	"github.com/opencost/opencost/pkg/cloud/azure"
+	"github.com/opencost/opencost/pkg/cloud/config"
+	"github.com/opencost/opencost/pkg/cloud/models"
+	"github.com/opencost/opencost/pkg/cloud/provider"
+	coreconfig "github.com/opencost/opencost/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "opencost"
+
+type fakeProviderConfig struct {
+	customPricing models.CustomPricing
+}
+
+func (fpc *fakeProviderConfig) ConfigFileManager() *coreconfig.ConfigFileManager {
+	return nil
+}
+
+func (fpc *fakeProviderConfig) GetCustomPricingData() (*models.CustomPricing, error) {
+	return &fpc.customPricing, nil
+}
+
+func (fpc *fakeProviderConfig) Update(update func(*models.CustomPricing) error) (*models.CustomPricing, error) {
+	err := update(&fpc.customPricing)
+	return &fpc.customPricing, err
+}
+
+func (fpc *fakeProviderConfig) UpdateFromMap(map[string]string) (*models.CustomPricing, error) {
+	return &fpc.customPricing, nil
+}
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	scheme, err := NewScheme()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.OpenCostPricing{}, &v1alpha1.CloudCostIntegration{}, &v1alpha1.CustomCostSource{}).
+		Build()
+}
+
+func request(name string) ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: name}}
+}
+
+func objectMeta(name string, created time.Time) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: testNamespace, Name: name, CreationTimestamp: metav1.NewTime(created), Generation: 1}
+}
+
+func readyCondition(t *testing.T, c client.Client, obj client.Object, conditions func() []metav1.Condition) *metav1.Condition {
+	err := c.Get(context.Background(), client.ObjectKeyFromObject(obj), obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cond := meta.FindStatusCondition(conditions(), v1alpha1.ConditionReady)
+	if cond == nil {
+		t.Fatalf("%s has no Ready condition", obj.GetName())
+	}
+	return cond
+}
+
+func TestPricingReconciler(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	older := &v1alpha1.OpenCostPricing{
+		ObjectMeta: objectMeta("older", now.Add(-time.Hour)),
+		Spec: v1alpha1.OpenCostPricingSpec{
+			CPU:              "0.03",
+			Discount:         "30%",
+			SharedNamespaces: []string{"kube-system", "monitoring"},
+		},
+	}
+	newer := &v1alpha1.OpenCostPricing{
+		ObjectMeta: objectMeta("newer", now),
+		Spec:       v1alpha1.OpenCostPricingSpec{CPU: "0.05"},
+	}
+
+	c := newFakeClient(t, older, newer)
+	pc := &fakeProviderConfig{customPricing: models.CustomPricing{RAM: "0.004"}}
+	r := &PricingReconciler{Client: c, ProviderConfig: pc}
+
+	_, err := r.Reconcile(context.Background(), request("newer"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if pc.customPricing.CPU != "0.03" {
+		t.Errorf("expected CPU 0.03, got %s", pc.customPricing.CPU)
+	}
+	if pc.customPricing.RAM != "0.004" {
+		t.Errorf("expected unset RAM to be unchanged, got %s", pc.customPricing.RAM)
+	}
+	if pc.customPricing.SharedNamespaces != "kube-system,monitoring" {
+		t.Errorf("expected shared namespaces kube-system,monitoring, got %s", pc.customPricing.SharedNamespaces)
+	}
+
+	if cond := readyCondition(t, c, older, func() []metav1.Condition { return older.Status.Conditions }); cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected older to be ready, got %s: %s", cond.Reason, cond.Message)
+	}
+	if cond := readyCondition(t, c, newer, func() []metav1.Condition { return newer.Status.Conditions }); cond.Reason != v1alpha1.ReasonSuperseded {
+		t.Errorf("expected newer to be superseded, got %s", cond.Reason)
+	}
+}
+
+func TestPricingReconciler_RemovedField(t *testing.T) {
+	pricing := &v1alpha1.OpenCostPricing{
+		ObjectMeta: objectMeta("pricing", time.Now().Truncate(time.Second)),
+		Spec:       v1alpha1.OpenCostPricingSpec{CPU: "0.03", RAM: "0.01"},
+	}
+
+	c := newFakeClient(t, pricing)
+	pc := &fakeProviderConfig{customPricing: models.CustomPricing{GPU: "1.5"}}
+	r := &PricingReconciler{Client: c, ProviderConfig: pc}
+
+	_, err := r.Reconcile(context.Background(), request("pricing"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if pc.customPricing.RAM != "0.01" {
+		t.Fatalf("expected RAM 0.01, got %s", pc.customPricing.RAM)
+	}
+
+	err = c.Get(context.Background(), client.ObjectKeyFromObject(pricing), pricing)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	pricing.Spec.RAM = ""
+	err = c.Update(context.Background(), pricing)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = r.Reconcile(context.Background(), request("pricing"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if pc.customPricing.CPU != "0.03" {
+		t.Errorf("expected CPU 0.03, got %s", pc.customPricing.CPU)
+	}
+	if expected := provider.DefaultPricing().RAM; pc.customPricing.RAM != expected {
+		t.Errorf("expected removed RAM to be reset to %s, got %s", expected, pc.customPricing.RAM)
+	}
+	if pc.customPricing.GPU != "1.5" {
+		t.Errorf("expected GPU, which was never applied, to be unchanged, got %s", pc.customPricing.GPU)
+	}
+
+	err = c.Get(context.Background(), client.ObjectKeyFromObject(pricing), pricing)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(pricing.Status.AppliedFields) != 1 || pricing.Status.AppliedFields[0] != "CPU" {
+		t.Errorf("expected applied fields [CPU], got %v", pricing.Status.AppliedFields)
+	}
+}
+
+func TestCloudIntegrationReconciler(t *testing.T) {
+	configurations := &config.Configurations{
+		Azure: &config.AzureConfigs{
+			Storage: []*azure.StorageConfiguration{
+				{
+					SubscriptionID: "subscriptionID",
+					Account:        "accountName",
+					Container:      "containerName",
+					Path:           "containerPath",
+					Cloud:          "azureCloud",
+					Authorizer: &azure.SharedKeyCredential{
+						AccessKey: "accessKey",
+						Account:   "accountName",
+					},
+				},
+			},
+		},
+	}
+	raw, err := json.Marshal(configurations)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	now := time.Now()
+	valid := &v1alpha1.CloudCostIntegration{
+		ObjectMeta: objectMeta("valid", now),
+		Spec:       v1alpha1.CloudCostIntegrationSpec{Config: runtime.RawExtension{Raw: raw}},
+	}
+	fromSecret := &v1alpha1.CloudCostIntegration{
+		ObjectMeta: objectMeta("from-secret", now),
+		Spec:       v1alpha1.CloudCostIntegrationSpec{ConfigSecretRef: &v1alpha1.SecretKeySelector{Name: "missing", Key: "config"}},
+	}
+	invalid := &v1alpha1.CloudCostIntegration{
+		ObjectMeta: objectMeta("invalid", now),
+		Spec:       v1alpha1.CloudCostIntegrationSpec{Config: runtime.RawExtension{Raw: []byte(`{"aws":{}}`)}},
+	}
+
+	c := newFakeClient(t, valid, fromSecret, invalid)
+	watcher := config.NewCRDWatcher()
+	r := &CloudIntegrationReconciler{Client: c, SecretReader: c, Watcher: watcher}
+
+	for _, name := range []string{"valid", "from-secret", "invalid"} {
+		_, err = r.Reconcile(context.Background(), request(name))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	configs := watcher.GetConfigs()
+	if len(configs) != 1 || !configs[0].Equals(configurations.ToSlice()[0]) {
+		t.Errorf("expected the valid integration to be watched, got %v", configs)
+	}
+
+	if cond := readyCondition(t, c, valid, func() []metav1.Condition { return valid.Status.Conditions }); cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected valid to be ready, got %s: %s", cond.Reason, cond.Message)
+	}
+	if len(valid.Status.Integrations) != 1 || valid.Status.Integrations[0].Type != config.AzureStorageConfigType {
+		t.Errorf("expected an azurestorage integration status, got %v", valid.Status.Integrations)
+	}
+	if cond := meta.FindStatusCondition(valid.Status.Conditions, v1alpha1.ConditionActive); cond == nil || cond.Reason != v1alpha1.ReasonPending {
+		t.Errorf("expected valid to be pending activation, got %v", cond)
+	}
+	if cond := readyCondition(t, c, fromSecret, func() []metav1.Condition { return fromSecret.Status.Conditions }); cond.Reason != v1alpha1.ReasonSecretNotFound {
+		t.Errorf("expected from-secret to report a missing secret, got %s", cond.Reason)
+	}
+	if cond := readyCondition(t, c, invalid, func() []metav1.Condition { return invalid.Status.Conditions }); cond.Reason != v1alpha1.ReasonInvalidConfig {
+		t.Errorf("expected invalid to report an invalid config, got %s", cond.Reason)
+	}
+
+	err = c.Delete(context.Background(), valid)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = r.Reconcile(context.Background(), request("valid"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if configs := watcher.GetConfigs(); len(configs) != 0 {
+		t.Errorf("expected no watched integrations after delete, got %d", len(configs))
+	}
+}
+
+func TestCustomCostSourceReconciler(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	source := &v1alpha1.CustomCostSource{
+		ObjectMeta: objectMeta("datadog", now.Add(-time.Hour)),
+		Spec:       v1alpha1.CustomCostSourceSpec{Plugin: "datadog", ConfigSecretRef: &v1alpha1.SecretKeySelector{Name: "datadog", Key: "config.json"}},
+	}
+	duplicate := &v1alpha1.CustomCostSource{
+		ObjectMeta: objectMeta("datadog-2", now),
+		Spec:       v1alpha1.CustomCostSourceSpec{Plugin: "datadog", Config: runtime.RawExtension{Raw: []byte(`{}`)}},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "datadog"},
+		Data:       map[string][]byte{"config.json": []byte(`{"datadog_site":"datadoghq.com"}`)},
+	}
+
+	c := newFakeClient(t, source, duplicate, secret)
+	plugins := &fakePluginReloader{}
+	r := &CustomCostSourceReconciler{Client: c, SecretReader: c, PluginConfigDir: dir, Plugins: plugins}
+
+	for _, name := range []string{"datadog", "datadog-2"} {
+		_, err := r.Reconcile(context.Background(), request(name))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	path := filepath.Join(dir, "datadog_config.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(data) != `{"datadog_site":"datadoghq.com"}` {
+		t.Errorf("unexpected plugin config: %s", data)
+	}
+
+	if cond := readyCondition(t, c, source, func() []metav1.Condition { return source.Status.Conditions }); cond.Status != metav1.ConditionTrue {
+		t.Errorf("expected datadog to be ready, got %s: %s", cond.Reason, cond.Message)
+	}
+	if cond := readyCondition(t, c, duplicate, func() []metav1.Condition { return duplicate.Status.Conditions }); cond.Reason != v1alpha1.ReasonSuperseded {
+		t.Errorf("expected datadog-2 to be superseded, got %s", cond.Reason)
+	}
+	if plugins.reloads != 1 {
+		t.Errorf("expected the plugins to be reloaded once the config is written, got %d reloads", plugins.reloads)
+	}
+
+	// The config is kept while another source configures the plugin
+	for _, obj := range []*v1alpha1.CustomCostSource{duplicate, source} {
+		err = c.Delete(context.Background(), obj)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		_, err = r.Reconcile(context.Background(), request(obj.Name))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, err := os.Stat(path); obj == duplicate && err != nil {
+			t.Errorf("expected plugin config to be kept, got %s", err)
+		}
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected plugin config to be removed, got %v", err)
+	}
+	if plugins.reloads != 2 {
+		t.Errorf("expected the plugins to be reloaded once the config is removed, got %d reloads", plugins.reloads)
+	}
+}
+
+type fakePluginReloader struct {
+	reloads int
+}
+
+func (fpr *fakePluginReloader) ReloadPlugins() error {
+	fpr.reloads++
+	return nil
+}
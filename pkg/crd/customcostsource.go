@@ -0,0 +1,217 @@
+package crd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/pkg/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// pluginConfigSuffix is the suffix of the config files of custom cost plugins, see customcost.getRegisteredPlugins
+const pluginConfigSuffix = "_config.json"
+
+// pluginConfigFinalizer ensures the config file of a CustomCostSource's plugin is removed when it is deleted
+const pluginConfigFinalizer = "opencost.io/plugin-config"
+
+// PluginReloader reloads the custom cost plugins from the plugin config directory, as customcost.PipelineService does
+type PluginReloader interface {
+	ReloadPlugins() error
+}
+
+// CustomCostSourceReconciler writes the config of each CustomCostSource to the plugin config directory, from which
+// the custom cost pipeline loads its plugins, and reloads the plugins whenever a config is written or removed.
+type CustomCostSourceReconciler struct {
+	client.Client
+	// SecretReader reads the Secrets referenced by CustomCostSources
+	SecretReader    client.Reader
+	PluginConfigDir string
+	// Plugins, if set, is reloaded when the plugin configs change
+	Plugins PluginReloader
+}
+
+// Reconcile writes the config file of a CustomCostSource's plugin, and removes it when the CustomCostSource is
+// deleted.
+func (r *CustomCostSourceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ccs := &v1alpha1.CustomCostSource{}
+	err := r.Get(ctx, req.NamespacedName, ccs)
+	if err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !ccs.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, ccs)
+	}
+
+	if controllerutil.AddFinalizer(ccs, pluginConfigFinalizer) {
+		err = r.Update(ctx, ccs)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("adding finalizer to CustomCostSource: %w", err)
+		}
+	}
+
+	status := ccs.Status.DeepCopy()
+	status.ObservedGeneration = ccs.Generation
+
+	var result ctrl.Result
+	if ccs.Spec.ConfigSecretRef != nil {
+		result.RequeueAfter = secretRequeueInterval
+	}
+
+	owner, err := r.pluginOwner(ctx, ccs.Namespace, ccs.Spec.Plugin)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if owner != nil && owner.Name != ccs.Name {
+		setCondition(&status.Conditions, ccs.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, v1alpha1.ReasonSuperseded,
+			fmt.Sprintf("plugin %s is configured by CustomCostSource %s", ccs.Spec.Plugin, owner.Name))
+	} else if changed, reason, err := r.writeConfig(ctx, ccs); err != nil {
+		log.Warnf("CustomCostSourceReconciler: %s: %s", req.NamespacedName, err)
+		setCondition(&status.Conditions, ccs.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, reason, err.Error())
+	} else if changed || !meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionReady) {
+		if err := r.reloadPlugins(); err != nil {
+			log.Warnf("CustomCostSourceReconciler: %s: %s", req.NamespacedName, err)
+			setCondition(&status.Conditions, ccs.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, v1alpha1.ReasonUpdateFailed, err.Error())
+			result.RequeueAfter = secretRequeueInterval
+		} else {
+			setCondition(&status.Conditions, ccs.Generation, v1alpha1.ConditionReady, metav1.ConditionTrue, v1alpha1.ReasonApplied,
+				fmt.Sprintf("config of plugin %s applied", ccs.Spec.Plugin))
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(status, &ccs.Status) {
+		ccs.Status = *status
+		err = r.Status().Update(ctx, ccs)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating status of CustomCostSource: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// pluginOwner returns the oldest CustomCostSource of the namespace which configures the given plugin, which is the
+// one whose config is written.
+func (r *CustomCostSourceReconciler) pluginOwner(ctx context.Context, namespace, plugin string) (*v1alpha1.CustomCostSource, error) {
+	list := &v1alpha1.CustomCostSourceList{}
+	err := r.List(ctx, list, client.InNamespace(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("listing CustomCostSource: %w", err)
+	}
+
+	var owner *v1alpha1.CustomCostSource
+	for i := range list.Items {
+		ccs := &list.Items[i]
+		if ccs.Spec.Plugin != plugin || !ccs.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if owner == nil || ccs.CreationTimestamp.Before(&owner.CreationTimestamp) ||
+			(ccs.CreationTimestamp.Equal(&owner.CreationTimestamp) && ccs.Name < owner.Name) {
+			owner = ccs
+		}
+	}
+
+	return owner, nil
+}
+
+// finalize removes the config file of a deleted CustomCostSource's plugin, unless another CustomCostSource
+// configures the same plugin, then removes the finalizer.
+func (r *CustomCostSourceReconciler) finalize(ctx context.Context, ccs *v1alpha1.CustomCostSource) error {
+	if !controllerutil.ContainsFinalizer(ccs, pluginConfigFinalizer) {
+		return nil
+	}
+
+	owner, err := r.pluginOwner(ctx, ccs.Namespace, ccs.Spec.Plugin)
+	if err != nil {
+		return err
+	}
+
+	if owner == nil && isPluginName(ccs.Spec.Plugin) {
+		err = os.Remove(filepath.Join(r.PluginConfigDir, ccs.Spec.Plugin+pluginConfigSuffix))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing config of plugin %s: %w", ccs.Spec.Plugin, err)
+		}
+
+		err = r.reloadPlugins()
+		if err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(ccs, pluginConfigFinalizer)
+	err = r.Update(ctx, ccs)
+	if err != nil {
+		return fmt.Errorf("removing finalizer from CustomCostSource: %w", err)
+	}
+
+	return nil
+}
+
+// writeConfig writes the config file of the plugin if its content has changed, returning whether it was written,
+// and the reason for the Ready condition on failure.
+func (r *CustomCostSourceReconciler) writeConfig(ctx context.Context, ccs *v1alpha1.CustomCostSource) (bool, string, error) {
+	plugin := ccs.Spec.Plugin
+	if !isPluginName(plugin) {
+		return false, v1alpha1.ReasonInvalidConfig, fmt.Errorf("invalid plugin name %q", plugin)
+	}
+
+	data, err := readConfig(ctx, r.SecretReader, ccs.Namespace, ccs.Spec.Config.Raw, ccs.Spec.ConfigSecretRef)
+	if err != nil {
+		return false, configErrorReason(err), err
+	}
+
+	path := filepath.Join(r.PluginConfigDir, plugin+pluginConfigSuffix)
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, data) {
+		return false, "", nil
+	}
+
+	err = os.MkdirAll(r.PluginConfigDir, 0755)
+	if err != nil {
+		return false, v1alpha1.ReasonUpdateFailed, fmt.Errorf("creating plugin config dir: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0600)
+	if err != nil {
+		return false, v1alpha1.ReasonUpdateFailed, fmt.Errorf("writing plugin config: %w", err)
+	}
+
+	return true, "", nil
+}
+
+// reloadPlugins reloads the plugins, if a PluginReloader is set
+func (r *CustomCostSourceReconciler) reloadPlugins() error {
+	if r.Plugins == nil {
+		return nil
+	}
+
+	err := r.Plugins.ReloadPlugins()
+	if err != nil {
+		return fmt.Errorf("reloading plugins: %w", err)
+	}
+	return nil
+}
+
+// SetupWithManager registers the reconciler with the manager
+func (r *CustomCostSourceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.CustomCostSource{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+func isPluginName(plugin string) bool {
+	return plugin != "" && !strings.ContainsAny(plugin, "_/.")
+}
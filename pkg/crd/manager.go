@@ -0,0 +1,165 @@
+// Package crd configures OpenCost from its custom resources, defined in pkg/apis/v1alpha1. Each resource kind has a
+// reconciler which applies it to the existing configuration mechanism (the custom pricing of the provider config,
+// the cloud cost config controller and the custom cost plugin config directory) and reports status conditions on
+// the resource.
+package crd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/pkg/apis/v1alpha1"
+	"github.com/opencost/opencost/pkg/cloud/config"
+	"github.com/opencost/opencost/pkg/cloud/models"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// Config configures the reconcilers started by Start. Reconcilers whose dependencies are nil are not started.
+type Config struct {
+	// Namespace is the namespace watched for resources
+	Namespace        string
+	ProviderConfig   models.ProviderConfig
+	ConfigController *config.Controller
+	PluginConfigDir  string
+	// Plugins reloads the custom cost plugins when their configs change
+	Plugins PluginReloader
+}
+
+// NewScheme returns a scheme with the core Kubernetes types and the OpenCost resources
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+	err = v1alpha1.AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// Start creates a controller manager for the configured reconcilers, and runs it until the context is done.
+func Start(ctx context.Context, restConfig *rest.Config, conf *Config) error {
+	ctrllog.SetLogger(logr.New(&logSink{}))
+
+	scheme, err := NewScheme()
+	if err != nil {
+		return fmt.Errorf("creating scheme: %w", err)
+	}
+
+	// Custom resources do not support protobuf
+	restConfig = rest.CopyConfig(restConfig)
+	restConfig.ContentType = runtime.ContentTypeJSON
+	restConfig.AcceptContentTypes = runtime.ContentTypeJSON
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme: scheme,
+		Cache: cache.Options{
+			DefaultNamespaces: map[string]cache.Config{conf.Namespace: {}},
+		},
+		// metrics are served by OpenCost itself
+		Metrics:                metricsserver.Options{BindAddress: "0"},
+		HealthProbeBindAddress: "0",
+	})
+	if err != nil {
+		return fmt.Errorf("creating manager: %w", err)
+	}
+
+	if conf.ProviderConfig != nil {
+		err = (&PricingReconciler{
+			Client:         mgr.GetClient(),
+			ProviderConfig: conf.ProviderConfig,
+		}).SetupWithManager(mgr)
+		if err != nil {
+			return fmt.Errorf("setting up OpenCostPricing reconciler: %w", err)
+		}
+	}
+
+	if conf.ConfigController != nil {
+		watcher := config.NewCRDWatcher()
+		conf.ConfigController.RegisterWatcher(config.CRDSource, watcher)
+
+		err = (&CloudIntegrationReconciler{
+			Client:           mgr.GetClient(),
+			SecretReader:     mgr.GetAPIReader(),
+			ConfigController: conf.ConfigController,
+			Watcher:          watcher,
+		}).SetupWithManager(mgr)
+		if err != nil {
+			return fmt.Errorf("setting up CloudCostIntegration reconciler: %w", err)
+		}
+	}
+
+	if conf.PluginConfigDir != "" {
+		err = (&CustomCostSourceReconciler{
+			Client:          mgr.GetClient(),
+			SecretReader:    mgr.GetAPIReader(),
+			PluginConfigDir: conf.PluginConfigDir,
+			Plugins:         conf.Plugins,
+		}).SetupWithManager(mgr)
+		if err != nil {
+			return fmt.Errorf("setting up CustomCostSource reconciler: %w", err)
+		}
+	}
+
+	go func() {
+		err := mgr.Start(ctx)
+		if err != nil {
+			log.Errorf("CRD controller manager stopped: %s", err)
+		}
+	}()
+
+	return nil
+}
+
+// logSink writes the logs of controller-runtime to the OpenCost log. Informational logs are verbose, so are
+// written at debug level.
+type logSink struct {
+	name   string
+	values []any
+}
+
+func (ls *logSink) Init(logr.RuntimeInfo) {}
+
+func (ls *logSink) Enabled(int) bool {
+	return true
+}
+
+func (ls *logSink) Info(_ int, msg string, keysAndValues ...any) {
+	log.Debugf("%s%s %v", ls.prefix(), msg, ls.withValues(keysAndValues))
+}
+
+func (ls *logSink) Error(err error, msg string, keysAndValues ...any) {
+	log.Errorf("%s%s: %s %v", ls.prefix(), msg, err, ls.withValues(keysAndValues))
+}
+
+func (ls *logSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &logSink{name: ls.name, values: ls.withValues(keysAndValues)}
+}
+
+func (ls *logSink) WithName(name string) logr.LogSink {
+	if ls.name != "" {
+		name = ls.name + "." + name
+	}
+	return &logSink{name: name, values: ls.values}
+}
+
+func (ls *logSink) withValues(keysAndValues []any) []any {
+	return append(append([]any{}, ls.values...), keysAndValues...)
+}
+
+func (ls *logSink) prefix() string {
+	if ls.name == "" {
+		return "controller-runtime: "
+	}
+	return "controller-runtime: " + ls.name + ": "
+}
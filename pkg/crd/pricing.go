@@ -0,0 +1,152 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/pkg/apis/v1alpha1"
+	"github.com/opencost/opencost/pkg/cloud/models"
+	"github.com/opencost/opencost/pkg/cloud/provider"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// PricingReconciler applies OpenCostPricing resources to the custom pricing of the provider config. Only one
+// OpenCostPricing is applied: the oldest in the namespace. Fields removed from its spec are reset to their defaults,
+// while deleting it leaves the custom pricing unchanged until another OpenCostPricing is applied.
+type PricingReconciler struct {
+	client.Client
+	ProviderConfig models.ProviderConfig
+}
+
+// Reconcile applies the oldest OpenCostPricing of the request's namespace, and updates the status of each
+// OpenCostPricing in the namespace, so that the others report that they are superseded.
+func (r *PricingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	list := &v1alpha1.OpenCostPricingList{}
+	err := r.List(ctx, list, client.InNamespace(req.Namespace))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("listing OpenCostPricing: %w", err)
+	}
+
+	var pricings []*v1alpha1.OpenCostPricing
+	for i := range list.Items {
+		if list.Items[i].DeletionTimestamp.IsZero() {
+			pricings = append(pricings, &list.Items[i])
+		}
+	}
+	if len(pricings) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	sort.Slice(pricings, func(i, j int) bool {
+		ti, tj := pricings[i].CreationTimestamp, pricings[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return pricings[i].Name < pricings[j].Name
+	})
+
+	for i, pricing := range pricings {
+		status := pricing.Status.DeepCopy()
+		status.ObservedGeneration = pricing.Generation
+
+		if i > 0 {
+			status.AppliedFields = nil
+			setCondition(&status.Conditions, pricing.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, v1alpha1.ReasonSuperseded,
+				fmt.Sprintf("OpenCostPricing %s is applied instead", pricings[0].Name))
+		} else if applied, err := r.apply(&pricing.Spec, pricing.Status.AppliedFields); err != nil {
+			log.Errorf("PricingReconciler: applying %s/%s: %s", pricing.Namespace, pricing.Name, err)
+			setCondition(&status.Conditions, pricing.Generation, v1alpha1.ConditionReady, metav1.ConditionFalse, v1alpha1.ReasonUpdateFailed, err.Error())
+		} else {
+			status.AppliedFields = applied
+			setCondition(&status.Conditions, pricing.Generation, v1alpha1.ConditionReady, metav1.ConditionTrue, v1alpha1.ReasonApplied, "custom pricing applied")
+		}
+
+		if equality.Semantic.DeepEqual(status, &pricing.Status) {
+			continue
+		}
+		pricing.Status = *status
+		err = r.Status().Update(ctx, pricing)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("updating status of OpenCostPricing %s: %w", pricing.Name, err)
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// apply sets each of the fields of the spec which are set on the custom pricing, and resets each of the previously
+// applied fields which are no longer set to its default. It returns the fields which are set.
+func (r *PricingReconciler) apply(spec *v1alpha1.OpenCostPricingSpec, previous []string) ([]string, error) {
+	if r.ProviderConfig == nil {
+		return nil, fmt.Errorf("no provider config is available")
+	}
+
+	fields := map[string]string{
+		"Description":           spec.Description,
+		"CurrencyCode":          spec.CurrencyCode,
+		"CPU":                   spec.CPU,
+		"SpotCPU":               spec.SpotCPU,
+		"RAM":                   spec.RAM,
+		"SpotRAM":               spec.SpotRAM,
+		"GPU":                   spec.GPU,
+		"SpotGPU":               spec.SpotGPU,
+		"Storage":               spec.Storage,
+		"ZoneNetworkEgress":     spec.ZoneNetworkEgress,
+		"RegionNetworkEgress":   spec.RegionNetworkEgress,
+		"InternetNetworkEgress": spec.InternetNetworkEgress,
+		"Discount":              spec.Discount,
+		"NegotiatedDiscount":    spec.NegotiatedDiscount,
+		"SharedOverhead":        spec.SharedOverhead,
+		"SharedNamespaces":      strings.Join(spec.SharedNamespaces, ","),
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	defaults := reflect.ValueOf(provider.DefaultPricing()).Elem()
+
+	var applied []string
+	_, err := r.ProviderConfig.Update(func(cp *models.CustomPricing) error {
+		applied = nil
+		for _, name := range names {
+			value := fields[name]
+			if value != "" {
+				applied = append(applied, name)
+			} else if slices.Contains(previous, name) {
+				value = defaults.FieldByName(name).String()
+			} else {
+				continue
+			}
+
+			err := models.SetCustomPricingField(cp, name, value)
+			if err != nil {
+				return fmt.Errorf("setting %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// SetupWithManager registers the reconciler with the manager
+func (r *PricingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.OpenCostPricing{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,321 @@
+package customcost
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opencost/opencost/core/pkg/filter/matcher"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/util/jsonutil"
+)
+
+// AttributionMetric is the Allocation metric by which an attributed custom cost
+// is distributed across the Allocations matched by an AttributionTarget.
+type AttributionMetric string
+
+const (
+	// AttributionMetricNone attributes the full cost to a single external
+	// Allocation carrying the target's properties, rather than distributing it.
+	AttributionMetricNone      AttributionMetric = ""
+	AttributionMetricCPU       AttributionMetric = "cpu"
+	AttributionMetricRAM       AttributionMetric = "ram"
+	AttributionMetricGPU       AttributionMetric = "gpu"
+	AttributionMetricNetwork   AttributionMetric = "network"
+	AttributionMetricTotalCost AttributionMetric = "totalCost"
+)
+
+// AttributionConfig is the file representation of the custom cost attribution
+// rules. Rules are evaluated in order, and each custom cost line item is
+// attributed by the first rule that it matches.
+type AttributionConfig struct {
+	CostType CostType          `json:"costType,omitempty"`
+	Rules    []AttributionRule `json:"rules"`
+}
+
+// AttributionRule maps the custom costs matching Filter onto the Kubernetes
+// Allocations described by Target.
+type AttributionRule struct {
+	// Name identifies the rule and is used when naming external Allocations
+	Name string `json:"name"`
+	// Filter is a custom cost filter expression, e.g.
+	// `domain:"datadog"+label[team]:"payments"`
+	Filter string `json:"filter"`
+	// Target describes the Allocations to which matching costs are attributed
+	Target AttributionTarget `json:"target"`
+	// DistributeBy optionally distributes matching costs across the target
+	// Allocations in proportion to the given metric
+	DistributeBy AttributionMetric `json:"distributeBy,omitempty"`
+}
+
+// AttributionTarget describes the Kubernetes properties which receive an
+// attributed cost. Empty fields match any value.
+type AttributionTarget struct {
+	Namespace string `json:"namespace,omitempty"`
+	// NamespaceLabel takes the namespace from the given custom cost label,
+	// when present, overriding Namespace.
+	NamespaceLabel string            `json:"namespaceLabel,omitempty"`
+	ControllerKind string            `json:"controllerKind,omitempty"`
+	Controller     string            `json:"controller,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+}
+
+// LoadAttributionConfig reads the attribution rules from the given JSON file.
+// A missing file is not an error and results in a nil config.
+func LoadAttributionConfig(path string) (*AttributionConfig, error) {
+	config := &AttributionConfig{}
+	ok, err := jsonutil.LoadFile(path, config)
+	if err != nil {
+		return nil, fmt.Errorf("loading custom cost attribution config: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return config, nil
+}
+
+type attributionRule struct {
+	AttributionRule
+	matcher matcher.Matcher[*CustomCost]
+}
+
+// Attributor attributes custom costs to the Allocations of an AllocationSet
+// according to a set of AttributionRules.
+type Attributor struct {
+	querier  Querier
+	costType CostType
+	rules    []*attributionRule
+}
+
+// NewAttributor validates and compiles the given config into an Attributor
+// which retrieves custom costs from the provided Querier.
+func NewAttributor(querier Querier, config *AttributionConfig) (*Attributor, error) {
+	if config == nil {
+		return nil, fmt.Errorf("custom cost attribution config is nil")
+	}
+
+	costType := config.CostType
+	if costType == "" {
+		costType = CostTypeBlended
+	} else if _, err := ParseCostType(string(costType)); err != nil {
+		return nil, err
+	}
+
+	parser := NewCustomCostFilterParser()
+	compiler := NewCustomCostMatchCompiler()
+
+	var rules []*attributionRule
+	for i, rule := range config.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("custom cost attribution rule %d: missing name", i)
+		}
+
+		switch rule.DistributeBy {
+		case AttributionMetricNone, AttributionMetricCPU, AttributionMetricRAM, AttributionMetricGPU, AttributionMetricNetwork, AttributionMetricTotalCost:
+		default:
+			return nil, fmt.Errorf("custom cost attribution rule %s: unsupported distributeBy metric: %s", rule.Name, rule.DistributeBy)
+		}
+
+		filter, err := parser.Parse(rule.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("custom cost attribution rule %s: parsing filter: %w", rule.Name, err)
+		}
+
+		m, err := compiler.Compile(filter)
+		if err != nil {
+			return nil, fmt.Errorf("custom cost attribution rule %s: compiling filter: %w", rule.Name, err)
+		}
+
+		rules = append(rules, &attributionRule{
+			AttributionRule: rule,
+			matcher:         m,
+		})
+	}
+
+	return &Attributor{
+		querier:  querier,
+		costType: costType,
+		rules:    rules,
+	}, nil
+}
+
+// Attribute queries the custom costs which were incurred during the window of
+// the given AllocationSet and attributes them to its Allocations as external
+// costs.
+func (a *Attributor) Attribute(ctx context.Context, as *opencost.AllocationSet) error {
+	if a == nil || as == nil || len(a.rules) == 0 {
+		return nil
+	}
+
+	resp, err := a.querier.QueryTotal(ctx, CostTotalRequest{
+		Start:         as.Start(),
+		End:           as.End(),
+		CostType:      a.costType,
+		SortBy:        SortPropertyCost,
+		SortDirection: SortDirectionDesc,
+	})
+	if err != nil {
+		return fmt.Errorf("querying custom costs for %s: %w", as.Window, err)
+	}
+
+	// Custom costs are stored at hourly or daily resolution, so the response
+	// window may be larger than that of the AllocationSet. Only attribute the
+	// portion of the cost which overlaps the AllocationSet window.
+	scale := 1.0
+	if resp.Window.Duration() > as.Window.Duration() && resp.Window.Duration() > 0 {
+		scale = as.Window.Duration().Hours() / resp.Window.Duration().Hours()
+	}
+
+	a.attribute(resp.CustomCosts, scale, as)
+	return nil
+}
+
+// attribute applies the attribution rules to each custom cost, scaling each
+// cost by the given factor.
+func (a *Attributor) attribute(customCosts []*CustomCost, scale float64, as *opencost.AllocationSet) {
+	for _, cc := range customCosts {
+		rule := a.match(cc)
+		if rule == nil {
+			continue
+		}
+
+		cost := float64(cc.Cost) * scale
+		if cost == 0 {
+			continue
+		}
+
+		target := rule.Target
+		if target.NamespaceLabel != "" {
+			if ns, ok := cc.Labels[target.NamespaceLabel]; ok && ns != "" {
+				target.Namespace = ns
+			}
+		}
+
+		if rule.DistributeBy != AttributionMetricNone && distribute(cost, target, rule.DistributeBy, as) {
+			continue
+		}
+
+		err := as.Insert(newAttributedAllocation(rule.Name, cc, cost, target, as))
+		if err != nil {
+			log.Warnf("CustomCost: Attributor: failed to insert attributed allocation for rule %s: %s", rule.Name, err)
+		}
+	}
+}
+
+func (a *Attributor) match(cc *CustomCost) *attributionRule {
+	for _, rule := range a.rules {
+		if rule.matcher.Matches(cc) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// distribute adds the cost to the ExternalCost of each Allocation matching the
+// target, in proportion to the given metric. Returns false if there were no
+// matching Allocations with a non-zero metric.
+func distribute(cost float64, target AttributionTarget, metric AttributionMetric, as *opencost.AllocationSet) bool {
+	weights := map[*opencost.Allocation]float64{}
+	total := 0.0
+	for _, alloc := range as.Allocations {
+		if alloc.IsIdle() || alloc.IsExternal() || !targetMatches(target, alloc.Properties) {
+			continue
+		}
+
+		weight := metricValue(alloc, metric)
+		if weight <= 0 {
+			continue
+		}
+
+		weights[alloc] = weight
+		total += weight
+	}
+
+	if total <= 0 {
+		return false
+	}
+
+	for alloc, weight := range weights {
+		alloc.ExternalCost += cost * weight / total
+	}
+
+	return true
+}
+
+func metricValue(alloc *opencost.Allocation, metric AttributionMetric) float64 {
+	switch metric {
+	case AttributionMetricCPU:
+		return alloc.CPUCoreHours
+	case AttributionMetricRAM:
+		return alloc.RAMByteHours
+	case AttributionMetricGPU:
+		return alloc.GPUHours
+	case AttributionMetricNetwork:
+		return alloc.NetworkTransferBytes + alloc.NetworkReceiveBytes
+	case AttributionMetricTotalCost:
+		return alloc.TotalCost()
+	}
+	return 0
+}
+
+func targetMatches(target AttributionTarget, props *opencost.AllocationProperties) bool {
+	if props == nil {
+		return false
+	}
+
+	if target.Namespace != "" && target.Namespace != props.Namespace {
+		return false
+	}
+
+	if target.ControllerKind != "" && !strings.EqualFold(target.ControllerKind, props.ControllerKind) {
+		return false
+	}
+
+	if target.Controller != "" && target.Controller != props.Controller {
+		return false
+	}
+
+	for k, v := range target.Labels {
+		if props.Labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newAttributedAllocation creates an external Allocation carrying the target
+// properties, so that the cost is aggregated alongside the target workloads.
+func newAttributedAllocation(ruleName string, cc *CustomCost, cost float64, target AttributionTarget, as *opencost.AllocationSet) *opencost.Allocation {
+	labels := opencost.AllocationLabels{}
+	for k, v := range target.Labels {
+		labels[k] = v
+	}
+
+	namespace := target.Namespace
+	if namespace == "" {
+		namespace = opencost.UnallocatedSuffix
+	}
+
+	domain := cc.Domain
+	if domain == "" {
+		domain = opencost.UnallocatedSuffix
+	}
+
+	window := as.Window.Clone()
+	return &opencost.Allocation{
+		Name: strings.Join([]string{namespace, ruleName, domain, opencost.ExternalSuffix}, "/"),
+		Properties: &opencost.AllocationProperties{
+			Namespace:      target.Namespace,
+			ControllerKind: target.ControllerKind,
+			Controller:     target.Controller,
+			Labels:         labels,
+		},
+		Window:       window,
+		Start:        *window.Start(),
+		End:          *window.End(),
+		ExternalCost: cost,
+	}
+}
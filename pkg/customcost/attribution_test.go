@@ -0,0 +1,199 @@
+package customcost
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+type mockQuerier struct {
+	resp *CostResponse
+}
+
+func (mq *mockQuerier) QueryTotal(ctx context.Context, request CostTotalRequest) (*CostResponse, error) {
+	return mq.resp, nil
+}
+
+func (mq *mockQuerier) QueryTimeseries(ctx context.Context, request CostTimeseriesRequest) (*CostTimeseriesResponse, error) {
+	return nil, nil
+}
+
+func newAttributionTestAllocationSet(start, end time.Time) *opencost.AllocationSet {
+	as := opencost.NewAllocationSet(start, end)
+
+	a1 := opencost.NewMockUnitAllocation("cluster1/node1/payments/pod-a/container", start, time.Hour, &opencost.AllocationProperties{
+		Cluster:        "cluster1",
+		Namespace:      "payments",
+		ControllerKind: "deployment",
+		Controller:     "api",
+		Labels:         opencost.AllocationLabels{"team": "payments"},
+	})
+	a1.CPUCoreHours = 3
+
+	a2 := opencost.NewMockUnitAllocation("cluster1/node1/payments/pod-b/container", start, time.Hour, &opencost.AllocationProperties{
+		Cluster:        "cluster1",
+		Namespace:      "payments",
+		ControllerKind: "deployment",
+		Controller:     "worker",
+		Labels:         opencost.AllocationLabels{"team": "payments"},
+	})
+	a2.CPUCoreHours = 1
+
+	a3 := opencost.NewMockUnitAllocation("cluster1/node1/search/pod-c/container", start, time.Hour, &opencost.AllocationProperties{
+		Cluster:   "cluster1",
+		Namespace: "search",
+	})
+
+	as.Set(a1)
+	as.Set(a2)
+	as.Set(a3)
+
+	return as
+}
+
+func TestAttributor_Attribute(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	customCosts := []*CustomCost{
+		{
+			Domain:       "datadog",
+			ResourceName: "apm",
+			Cost:         40,
+			Labels:       map[string]string{"team": "payments"},
+		},
+		{
+			Domain:       "datadog",
+			ResourceName: "logs",
+			Cost:         10,
+			Labels:       map[string]string{"kube_namespace": "search"},
+		},
+		{
+			Domain:             "snowflake",
+			ResourceName:       "warehouse",
+			Cost:               100,
+			ExtendedAttributes: map[string]string{"service_name": "compute"},
+		},
+	}
+
+	config := &AttributionConfig{
+		Rules: []AttributionRule{
+			{
+				Name:         "apm",
+				Filter:       `domain:"datadog"+resourceName:"apm"`,
+				Target:       AttributionTarget{Namespace: "payments"},
+				DistributeBy: AttributionMetricCPU,
+			},
+			{
+				Name:   "logs",
+				Filter: `domain:"datadog"`,
+				Target: AttributionTarget{NamespaceLabel: "kube_namespace"},
+			},
+		},
+	}
+
+	attributor, err := NewAttributor(&mockQuerier{resp: &CostResponse{
+		Window:      opencost.NewClosedWindow(start, end),
+		CustomCosts: customCosts,
+	}}, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	as := newAttributionTestAllocationSet(start, end)
+	err = attributor.Attribute(context.Background(), as)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// apm cost is distributed 3:1 by CPU across the payments allocations
+	if got := as.Get("cluster1/node1/payments/pod-a/container").ExternalCost; !approx(got, 30) {
+		t.Errorf("expected pod-a external cost 30, got %f", got)
+	}
+	if got := as.Get("cluster1/node1/payments/pod-b/container").ExternalCost; !approx(got, 10) {
+		t.Errorf("expected pod-b external cost 10, got %f", got)
+	}
+
+	// logs cost is attributed to an external allocation in the labelled namespace
+	logs := as.Get("search/logs/datadog/" + opencost.ExternalSuffix)
+	if logs == nil {
+		t.Fatalf("expected external allocation for logs")
+	}
+	if logs.Properties.Namespace != "search" {
+		t.Errorf("expected namespace search, got %s", logs.Properties.Namespace)
+	}
+	if !approx(logs.ExternalCost, 10) {
+		t.Errorf("expected logs external cost 10, got %f", logs.ExternalCost)
+	}
+
+	// snowflake matches no rule and is not attributed
+	if got := as.ExternalCost(); !approx(got, 50) {
+		t.Errorf("expected total external cost 50, got %f", got)
+	}
+}
+
+func TestAttributor_AttributeScalesToWindow(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	config := &AttributionConfig{
+		Rules: []AttributionRule{
+			{
+				Name:   "all",
+				Filter: `extendedAttribute[service_name]:"compute"`,
+				Target: AttributionTarget{Namespace: "search"},
+			},
+		},
+	}
+
+	// daily custom costs are scaled down to the hourly allocation window
+	attributor, err := NewAttributor(&mockQuerier{resp: &CostResponse{
+		Window: opencost.NewClosedWindow(start, start.Add(24*time.Hour)),
+		CustomCosts: []*CustomCost{
+			{
+				Domain:             "snowflake",
+				Cost:               240,
+				ExtendedAttributes: map[string]string{"service_name": "compute"},
+			},
+		},
+	}}, config)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	as := newAttributionTestAllocationSet(start, end)
+	err = attributor.Attribute(context.Background(), as)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := as.ExternalCost(); !approx(got, 10) {
+		t.Errorf("expected total external cost 10, got %f", got)
+	}
+}
+
+func TestNewAttributor_Invalid(t *testing.T) {
+	testCases := map[string]*AttributionConfig{
+		"nil config":   nil,
+		"missing name": {Rules: []AttributionRule{{Filter: `domain:"datadog"`}}},
+		"bad filter":   {Rules: []AttributionRule{{Name: "a", Filter: `domain:`}}},
+		"bad metric":   {Rules: []AttributionRule{{Name: "a", Filter: `domain:"datadog"`, DistributeBy: "pods"}}},
+		"bad costType": {CostType: "net", Rules: []AttributionRule{{Name: "a", Filter: `domain:"datadog"`}}},
+	}
+
+	for name, config := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewAttributor(&mockQuerier{}, config)
+			if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 0.0001
+}
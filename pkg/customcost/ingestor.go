@@ -1,6 +1,7 @@
 package customcost
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -19,6 +20,10 @@ import (
 	"github.com/opencost/opencost/core/pkg/util/stringutil"
 	"github.com/opencost/opencost/core/pkg/util/timeutil"
 	"github.com/opencost/opencost/pkg/env"
+	"github.com/opencost/opencost/pkg/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // IngestorStatus includes diagnostic values for a given Ingestor
@@ -37,6 +42,8 @@ type CustomCostIngestorConfig struct {
 	HourlyDuration, DailyDuration        time.Duration
 	DailyQueryWindow, HourlyQueryWindow  time.Duration
 	PluginConfigDir, PluginExecutableDir string
+	// Standby creates the ingestors without starting them, so that they only run once started by the leader replica
+	Standby bool
 }
 
 // DefaultIngestorConfiguration retrieves an CustomCostIngestorConfig from env variables
@@ -61,6 +68,7 @@ type CustomCostIngestor struct {
 	creationTime time.Time
 	coverage     map[string]opencost.Window
 	coverageLock sync.Mutex
+	ingestion    *telemetry.IngestionTracker
 	isRunning    atomic.Bool
 	isStopping   atomic.Bool
 	exitBuildCh  chan string
@@ -95,6 +103,7 @@ func NewCustomCostIngestor(ingestorConfig *CustomCostIngestorConfig, repo Reposi
 		creationTime: now,
 		lastRun:      now,
 		coverage:     map[string]opencost.Window{},
+		ingestion:    telemetry.NewIngestionTracker("customcost", res),
 		plugins:      plugins,
 		resolution:   res,
 		refreshRate:  res,
@@ -102,6 +111,10 @@ func NewCustomCostIngestor(ingestorConfig *CustomCostIngestorConfig, repo Reposi
 }
 
 func (ing *CustomCostIngestor) LoadWindow(start, end time.Time) {
+	ing.loadWindow(context.Background(), start, end)
+}
+
+func (ing *CustomCostIngestor) loadWindow(ctx context.Context, start, end time.Time) {
 	var targets []opencost.Window
 	if ing.resolution == timeutil.Day {
 		oldestDailyDate := time.Now().UTC().Add(-1 * ing.config.DailyDuration).Truncate(timeutil.Day)
@@ -139,7 +152,7 @@ func (ing *CustomCostIngestor) LoadWindow(start, end time.Time) {
 			}
 		}
 		if !allPluginsHave {
-			ing.BuildWindow(*window.Start(), *window.End())
+			ing.buildWindow(ctx, *window.Start(), *window.End())
 		} else {
 			for domain := range ing.plugins {
 				ing.expandCoverage(window, domain)
@@ -151,13 +164,23 @@ func (ing *CustomCostIngestor) LoadWindow(start, end time.Time) {
 }
 
 func (ing *CustomCostIngestor) BuildWindow(start, end time.Time) {
+	ing.buildWindow(context.Background(), start, end)
+}
 
+// buildWindow builds the window for each plugin in spans which are children of the span on the given context
+func (ing *CustomCostIngestor) buildWindow(ctx context.Context, start, end time.Time) {
 	for domain := range ing.plugins {
-		ing.buildSingleDomain(start, end, domain)
+		ing.buildSingleDomain(ctx, start, end, domain)
 	}
 }
 
-func (ing *CustomCostIngestor) buildSingleDomain(start, end time.Time, domain string) {
+func (ing *CustomCostIngestor) buildSingleDomain(ctx context.Context, start, end time.Time, domain string) {
+	_, span := telemetry.StartBuild(ctx, tracerName, "CustomCost.BuildWindow", start, end,
+		attribute.String("plugin", domain),
+		attribute.String("resolution", timeutil.FormatStoreResolution(ing.resolution)),
+	)
+	defer span.End()
+
 	req := &pb.CustomCostRequest{
 		Start:      timestamppb.New(start),
 		End:        timestamppb.New(end),
@@ -168,6 +191,7 @@ func (ing *CustomCostIngestor) buildSingleDomain(start, end time.Time, domain st
 	pluginClient, found := ing.plugins[domain]
 	if !found {
 		log.Errorf("could not find plugin client for plugin %s. Did you initialize the plugin correctly?", domain)
+		telemetry.RecordError(span, fmt.Errorf("could not find plugin client for plugin %s", domain))
 		return
 	}
 
@@ -175,6 +199,7 @@ func (ing *CustomCostIngestor) buildSingleDomain(start, end time.Time, domain st
 	rpcClient, err := pluginClient.Client()
 	if err != nil {
 		log.Errorf("error connecting client for plugin %s: %v", domain, err)
+		telemetry.RecordError(span, err)
 		return
 	}
 
@@ -182,6 +207,7 @@ func (ing *CustomCostIngestor) buildSingleDomain(start, end time.Time, domain st
 	raw, err := rpcClient.Dispense("CustomCostSource")
 	if err != nil {
 		log.Errorf("error creating new plugin client for plugin %s: %v", domain, err)
+		telemetry.RecordError(span, err)
 		return
 	}
 
@@ -189,6 +215,7 @@ func (ing *CustomCostIngestor) buildSingleDomain(start, end time.Time, domain st
 
 	custCostResps := custCostSrc.GetCustomCosts(req)
 	// loop through each customCostResponse, adding to repo
+	saved := 0
 	for _, ccr := range custCostResps {
 
 		// check for errors in response
@@ -197,6 +224,7 @@ func (ing *CustomCostIngestor) buildSingleDomain(start, end time.Time, domain st
 				log.Errorf("error in getting custom costs for plugin %s: %v", domain, errResp)
 			}
 			log.Errorf("not adding any costs for window %v-%v on plugin %s", req.Start, req.End, domain)
+			telemetry.RecordError(span, fmt.Errorf("error in getting custom costs for plugin %s: %s", domain, strings.Join(ccr.Errors, "; ")))
 			continue
 		}
 		log.Debugf("BuildWindow[%s]: GetCustomCost: writing custom costs for window %v-%v: %d", domain, ccr.Start, ccr.End, len(ccr.Costs))
@@ -204,10 +232,19 @@ func (ing *CustomCostIngestor) buildSingleDomain(start, end time.Time, domain st
 		err2 := ing.repo.Put(ccr)
 		if err2 != nil {
 			log.Errorf("CustomCost[%s]: ingestor: failed to save Custom Cost Set with window %v-%v: %s", domain, ccr.Start, ccr.End, err2.Error())
+			telemetry.RecordError(span, err2)
+		} else {
+			saved++
 		}
 
 		ing.expandCoverage(opencost.NewClosedWindow(ccr.Start.AsTime(), ccr.End.AsTime()), domain)
 	}
+	span.SetAttributes(attribute.Int("customcost.responses", len(custCostResps)))
+
+	// Only saved responses count as ingested, so that failing saves and empty responses are reported as lag
+	if saved > 0 {
+		ing.ingestion.Ingested(domain)
+	}
 }
 
 func (ing *CustomCostIngestor) Start(rebuild bool) {
@@ -223,6 +260,10 @@ func (ing *CustomCostIngestor) Start(rebuild bool) {
 	ing.exitBuildCh = make(chan string)
 	ing.exitRunCh = make(chan string)
 
+	for domain := range ing.plugins {
+		ing.ingestion.Track(domain)
+	}
+
 	// Build the store once, advancing backward in time from the earliest
 	// point of coverage.
 	go ing.build(rebuild)
@@ -232,6 +273,11 @@ func (ing *CustomCostIngestor) Start(rebuild bool) {
 }
 
 func (ing *CustomCostIngestor) Stop() {
+	// If the ingestor is not running, there are no processes to stop
+	if !ing.isRunning.Load() {
+		return
+	}
+
 	// If already stopping, log that and return.
 	if !ing.isStopping.CompareAndSwap(false, true) {
 		log.Infof("CustomCost: ingestor: is already stopping")
@@ -262,6 +308,8 @@ func (ing *CustomCostIngestor) Stop() {
 
 	wg.Wait()
 
+	ing.ingestion.Untrack()
+
 	// Declare that the store is officially no longer running. This allows
 	// Start to be called again, restarting the store from scratch.
 	ing.isRunning.Store(false)
@@ -292,13 +340,20 @@ func (ing *CustomCostIngestor) build(rebuild bool) {
 
 	log.Infof("CustomCost[%s]: ingestor: build[%s]: Starting build back to %s in blocks of %s", ing.key, ing.runID, s, ing.resolution)
 
+	ctx, span := otel.Tracer(tracerName).Start(context.Background(), "CustomCost.build", trace.WithAttributes(
+		attribute.String("plugins", ing.key),
+		attribute.String("resolution", timeutil.FormatStoreResolution(ing.resolution)),
+		attribute.Bool("rebuild", rebuild),
+	))
+
 	// if rebuild is not specified then check for existing coverage on window
 	if rebuild {
-		ing.BuildWindow(s, e)
+		ing.buildWindow(ctx, s, e)
 	} else {
-		ing.LoadWindow(s, e)
+		ing.loadWindow(ctx, s, e)
 	}
 
+	span.End()
 	log.Infof("CustomCost[%s]: ingestor: build[%s]: completed in %v", ing.key, ing.runID, time.Since(buildStart))
 
 	// In order to be able to Stop, we have to wait on an exit message
@@ -316,7 +371,7 @@ func (ing *CustomCostIngestor) Rebuild(domain string) error {
 	limit := opencost.RoundBack(time.Now().UTC().Add(-1*targetDur), ing.resolution)
 	e := time.Now().UTC()
 
-	ing.buildSingleDomain(limit, e, domain)
+	ing.buildSingleDomain(context.Background(), limit, e, domain)
 	return nil
 }
 
@@ -337,6 +392,12 @@ func (ing *CustomCostIngestor) run() {
 			// Wait for next tick
 		}
 
+		ctx, span := otel.Tracer(tracerName).Start(context.Background(), "CustomCost.run", trace.WithAttributes(
+			attribute.String("plugins", ing.key),
+			attribute.String("resolution", timeutil.FormatStoreResolution(ing.resolution)),
+			attribute.Int("run", ing.runs),
+		))
+
 		queryWin := ing.config.DailyQueryWindow
 		if ing.resolution == time.Hour {
 			queryWin = ing.config.HourlyQueryWindow
@@ -358,7 +419,7 @@ func (ing *CustomCostIngestor) run() {
 		// 2. Move window forward one Resolution
 		for time.Now().After(s) {
 			profStart := time.Now()
-			ing.BuildWindow(s, e)
+			ing.buildWindow(ctx, s, e)
 
 			log.Debugf("CustomCost[%s]: ingestor: Run[%s]: completed %s in %v", ing.key, ing.runID, opencost.NewWindow(&s, &e), time.Since(profStart))
 
@@ -373,6 +434,7 @@ func (ing *CustomCostIngestor) run() {
 		ing.lastRun = time.Now().UTC()
 
 		ing.runs++
+		span.End()
 
 		ticker.TickIn(ing.refreshRate)
 	}
@@ -50,6 +50,10 @@ func customCostFieldMap(cc *CustomCost, identifier ast.Identifier) (string, erro
 		return cc.Domain, nil
 	case CustomCostCostSourceProp:
 		return cc.CostSource, nil
+	case CustomCostLabelProp:
+		return cc.Labels[identifier.Key], nil
+	case CustomCostExtendedAttributeProp:
+		return cc.ExtendedAttributes[identifier.Key], nil
 	}
 
 	return "", fmt.Errorf("failed to find string identifier on CustomCost: %s", identifier.Field.Name)
@@ -62,5 +66,18 @@ func customCostSliceFieldMap(cc *CustomCost, identifier ast.Identifier) ([]strin
 
 // Maps map fields from a custom cost to a map[string]string value based on an identifier
 func customCostMapFieldMap(cc *CustomCost, identifier ast.Identifier) (map[string]string, error) {
-	return nil, fmt.Errorf("custom costs have no map fields")
+	if cc == nil {
+		return nil, fmt.Errorf("cannot map to nil custom cost")
+	}
+	if identifier.Field == nil {
+		return nil, fmt.Errorf("cannot map field from identifier with nil field")
+	}
+	switch CustomCostProperty(identifier.Field.Name) {
+	case CustomCostLabelProp:
+		return cc.Labels, nil
+	case CustomCostExtendedAttributeProp:
+		return cc.ExtendedAttributes, nil
+	}
+
+	return nil, fmt.Errorf("failed to find map identifier on CustomCost: %s", identifier.Field.Name)
 }
@@ -15,6 +15,8 @@ var customCostFilterFields = []*ast.Field{
 	ast.NewField(CustomCostUsageUnitProp),
 	ast.NewField(CustomCostDomainProp),
 	ast.NewField(CustomCostCostSourceProp),
+	ast.NewMapField(CustomCostLabelProp),
+	ast.NewMapField(CustomCostExtendedAttributeProp),
 }
 
 // NewCustomCostFilterParser creates a new `ast.FilterParser` implementation
@@ -8,6 +8,8 @@ import (
 	"path"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -25,9 +27,14 @@ const execFmt = `%s/%s.ocplugin.%s.%s`
 
 // PipelineService exposes CustomCost pipeline controls and diagnostics endpoints
 type PipelineService struct {
+	lock                          sync.RWMutex
+	config                        CustomCostIngestorConfig
+	plugins                       map[string]*plugin.Client
 	hourlyIngestor, dailyIngestor *CustomCostIngestor
 	hourlyStore, dailyStore       Repository
+	hourlyDuration, dailyDuration time.Duration
 	domains                       []string
+	standby                       atomic.Bool
 }
 
 func getRegisteredPlugins(configDir string, execDir string) (map[string]*plugin.Client, error) {
@@ -112,48 +119,117 @@ func getRegisteredPlugins(configDir string, execDir string) (map[string]*plugin.
 
 // NewPipelineService is a constructor for a PipelineService
 func NewPipelineService(hourlyrepo, dailyrepo Repository, ingConf CustomCostIngestorConfig) (*PipelineService, error) {
-	registeredPlugins, err := getRegisteredPlugins(ingConf.PluginConfigDir, ingConf.PluginExecutableDir)
-	if err != nil {
-		log.Errorf("error getting registered plugins: %v", err)
-		return nil, fmt.Errorf("error getting registered plugins: %v", err)
+	dp := &PipelineService{
+		config:         ingConf,
+		hourlyStore:    hourlyrepo,
+		dailyStore:     dailyrepo,
+		hourlyDuration: ingConf.HourlyDuration,
+		dailyDuration:  ingConf.DailyDuration,
 	}
+	dp.standby.Store(ingConf.Standby)
 
-	hourlyIngestor, err := NewCustomCostIngestor(&ingConf, hourlyrepo, registeredPlugins, time.Hour)
+	err := dp.ReloadPlugins()
 	if err != nil {
+		log.Errorf("error loading custom cost plugins: %v", err)
 		return nil, err
 	}
 
-	hourlyIngestor.Start(false)
+	return dp, nil
+}
+
+// ReloadPlugins loads the plugins configured in the plugin config directory, and restarts ingestion with them, so
+// that changes to the plugin configs take effect without restarting OpenCost.
+func (dp *PipelineService) ReloadPlugins() error {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
 
-	dailyIngestor, err := NewCustomCostIngestor(&ingConf, dailyrepo, registeredPlugins, timeutil.Day)
+	registeredPlugins, err := getRegisteredPlugins(dp.config.PluginConfigDir, dp.config.PluginExecutableDir)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("error getting registered plugins: %v", err)
+	}
+
+	hourlyIngestor, err := NewCustomCostIngestor(&dp.config, dp.hourlyStore, registeredPlugins, time.Hour)
+	if err != nil {
+		killPlugins(registeredPlugins)
+		return err
 	}
 
-	dailyIngestor.Start(false)
+	dailyIngestor, err := NewCustomCostIngestor(&dp.config, dp.dailyStore, registeredPlugins, timeutil.Day)
+	if err != nil {
+		killPlugins(registeredPlugins)
+		return err
+	}
+
+	if dp.hourlyIngestor != nil {
+		dp.hourlyIngestor.Stop()
+	}
+	if dp.dailyIngestor != nil {
+		dp.dailyIngestor.Stop()
+	}
+	killPlugins(dp.plugins)
+
+	if !dp.standby.Load() {
+		hourlyIngestor.Start(false)
+		dailyIngestor.Start(false)
+	}
 
 	var domains []string
-	for domain, _ := range registeredPlugins {
+	for domain := range registeredPlugins {
 		domains = append(domains, domain)
 	}
 
-	return &PipelineService{
-		hourlyIngestor: hourlyIngestor,
-		hourlyStore:    hourlyrepo,
-		dailyStore:     dailyrepo,
-		dailyIngestor:  dailyIngestor,
-		domains:        domains,
-	}, nil
+	dp.plugins = registeredPlugins
+	dp.hourlyIngestor = hourlyIngestor
+	dp.dailyIngestor = dailyIngestor
+	dp.domains = domains
+
+	return nil
+}
+
+// StartIngestion starts the hourly and daily ingestors, when this replica becomes the leader
+func (dp *PipelineService) StartIngestion() {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+	dp.standby.Store(false)
+	dp.hourlyIngestor.Start(false)
+	dp.dailyIngestor.Start(false)
+}
+
+// StopIngestion stops the hourly and daily ingestors, when this replica stops leading. The repositories continue to
+// serve queries.
+func (dp *PipelineService) StopIngestion() {
+	dp.lock.Lock()
+	defer dp.lock.Unlock()
+	dp.standby.Store(true)
+	dp.hourlyIngestor.Stop()
+	dp.dailyIngestor.Stop()
+}
+
+// ingestors returns the hourly and daily ingestors, which are replaced when the plugins are reloaded
+func (dp *PipelineService) ingestors() (*CustomCostIngestor, *CustomCostIngestor) {
+	dp.lock.RLock()
+	defer dp.lock.RUnlock()
+	return dp.hourlyIngestor, dp.dailyIngestor
+}
+
+// killPlugins ends the processes of the given plugins
+func killPlugins(plugins map[string]*plugin.Client) {
+	for _, client := range plugins {
+		client.Kill()
+	}
 }
 
 // Status gives a combined view of the state of configs and the ingestor status
 func (dp *PipelineService) Status() Status {
+	dp.lock.RLock()
+	hourlyIngestor, dailyIngestor, domains := dp.hourlyIngestor, dp.dailyIngestor, dp.domains
+	dp.lock.RUnlock()
 
 	// Pull config status from the config controller
-	ingstatusHourly := dp.hourlyIngestor.Status()
+	ingstatusHourly := hourlyIngestor.Status()
 
 	// Pull config status from the config controller
-	ingstatusDaily := dp.dailyIngestor.Status()
+	ingstatusDaily := dailyIngestor.Status()
 
 	// These are the statuses
 	return Status{
@@ -161,11 +237,19 @@ func (dp *PipelineService) Status() Status {
 		CoverageHourly:    ingstatusHourly.Coverage,
 		RefreshRateHourly: ingstatusHourly.RefreshRate.String(),
 		RefreshRateDaily:  ingstatusDaily.RefreshRate.String(),
-		Domains:           dp.domains,
+		Domains:           domains,
 	}
 
 }
 
+// GetCustomCostQuerier returns a querier that can query data from all custom cost sources
+func (s *PipelineService) GetCustomCostQuerier() Querier {
+	if s == nil {
+		return nil
+	}
+	return NewRepositoryQuerier(s.hourlyStore, s.dailyStore, s.hourlyDuration, s.dailyDuration)
+}
+
 // GetCustomCostRebuildHandler creates a handler from a http request which initiates a rebuild of custom cost pipeline, if a
 // domain is provided then it only rebuilds the specified billing domain
 func (s *PipelineService) GetCustomCostRebuildHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -184,6 +268,11 @@ func (s *PipelineService) GetCustomCostRebuildHandler() func(w http.ResponseWrit
 	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 		w.Header().Set("Content-Type", "application/json")
 
+		if s.standby.Load() {
+			http.Error(w, "Custom Cost ingestion runs on the leader replica", http.StatusServiceUnavailable)
+			return
+		}
+
 		commit := r.URL.Query().Get("commit") == "true" || r.URL.Query().Get("commit") == "1"
 
 		if !commit {
@@ -193,13 +282,15 @@ func (s *PipelineService) GetCustomCostRebuildHandler() func(w http.ResponseWrit
 
 		domain := r.URL.Query().Get("domain")
 
-		err := s.hourlyIngestor.Rebuild(domain)
+		hourlyIngestor, dailyIngestor := s.ingestors()
+
+		err := hourlyIngestor.Rebuild(domain)
 		if err != nil {
 			log.Errorf("error rebuilding hourly ingestor")
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		err = s.dailyIngestor.Rebuild(domain)
+		err = dailyIngestor.Rebuild(domain)
 		if err != nil {
 			log.Errorf("error rebuilding daily ingestor")
 			http.Error(w, err.Error(), http.StatusBadRequest)
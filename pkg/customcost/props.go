@@ -8,16 +8,18 @@ import (
 type CustomCostProperty string
 
 const (
-	CustomCostZoneProp           CustomCostProperty = "zone"
-	CustomCostAccountNameProp                       = "accountName"
-	CustomCostChargeCategoryProp                    = "chargeCategory"
-	CustomCostDescriptionProp                       = "description"
-	CustomCostResourceNameProp                      = "resourceName"
-	CustomCostResourceTypeProp                      = "resourceType"
-	CustomCostProviderIdProp                        = "providerId"
-	CustomCostUsageUnitProp                         = "usageUnit"
-	CustomCostDomainProp                            = "domain"
-	CustomCostCostSourceProp                        = "costSource"
+	CustomCostZoneProp              CustomCostProperty = "zone"
+	CustomCostAccountNameProp                          = "accountName"
+	CustomCostChargeCategoryProp                       = "chargeCategory"
+	CustomCostDescriptionProp                          = "description"
+	CustomCostResourceNameProp                         = "resourceName"
+	CustomCostResourceTypeProp                         = "resourceType"
+	CustomCostProviderIdProp                           = "providerId"
+	CustomCostUsageUnitProp                            = "usageUnit"
+	CustomCostDomainProp                               = "domain"
+	CustomCostCostSourceProp                           = "costSource"
+	CustomCostLabelProp                                = "label"
+	CustomCostExtendedAttributeProp                    = "extendedAttribute"
 )
 
 func ParseCustomCostProperties(props []string) ([]CustomCostProperty, error) {
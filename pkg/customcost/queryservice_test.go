@@ -0,0 +1,160 @@
+package customcost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencost/opencost/core/pkg/client"
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+type stubQuerier struct {
+	totalRequest      CostTotalRequest
+	timeseriesRequest CostTimeseriesRequest
+
+	total      *CostResponse
+	timeseries *CostTimeseriesResponse
+}
+
+func (s *stubQuerier) QueryTotal(_ context.Context, request CostTotalRequest) (*CostResponse, error) {
+	s.totalRequest = request
+	return s.total, nil
+}
+
+func (s *stubQuerier) QueryTimeseries(_ context.Context, request CostTimeseriesRequest) (*CostTimeseriesResponse, error) {
+	s.timeseriesRequest = request
+	return s.timeseries, nil
+}
+
+func TestQueryService_Client(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+	window := opencost.NewClosedWindow(start, end)
+
+	response := &CostResponse{
+		Window:        window,
+		TotalCost:     5,
+		TotalCostType: CostTypeList,
+		CustomCosts:   []*CustomCost{{Id: "1", Domain: "datadog", Cost: 5, CostType: CostTypeList}},
+	}
+	querier := &stubQuerier{
+		total:      response,
+		timeseries: &CostTimeseriesResponse{Window: window, Timeseries: []*CostResponse{response}},
+	}
+
+	qs := NewQueryService(querier)
+	router := httprouter.New()
+	router.GET("/customCost/total", qs.GetCustomCostTotalHandler())
+	router.GET("/customCost/timeseries", qs.GetCustomCostTimeseriesHandler())
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	c, err := client.NewClient(server.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	query := client.CustomCostQuery{
+		Window:        window,
+		Aggregate:     []string{"domain"},
+		Accumulate:    opencost.AccumulateOptionDay,
+		CostType:      client.CustomCostTypeList,
+		SortBy:        client.CustomCostSortPropertyAggregate,
+		SortDirection: client.SortDirectionAscending,
+	}
+
+	total, err := c.QueryCustomCostTotal(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, start, querier.totalRequest.Start)
+	assert.Equal(t, end, querier.totalRequest.End)
+	assert.Equal(t, []CustomCostProperty{CustomCostDomainProp}, querier.totalRequest.AggregateBy)
+	assert.Equal(t, CostTypeList, querier.totalRequest.CostType)
+	assert.Equal(t, SortPropertyAggregate, querier.totalRequest.SortBy)
+	assert.Equal(t, SortDirectionAsc, querier.totalRequest.SortDirection)
+	assert.Equal(t, float32(5), total.TotalCost)
+	assert.Equal(t, client.CustomCostTypeList, total.TotalCostType)
+	require.Len(t, total.CustomCosts, 1)
+	assert.Equal(t, "datadog", total.CustomCosts[0].Domain)
+
+	timeseries, err := c.QueryCustomCostTimeseries(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, opencost.AccumulateOptionDay, querier.timeseriesRequest.Accumulate)
+	require.Len(t, timeseries.Timeseries, 1)
+	assert.Equal(t, float32(5), timeseries.Timeseries[0].TotalCost)
+}
+
+// TestQueryService_ClientTypes checks that the client's copies of the response types
+// decode every field the server encodes, and encode them back unchanged.
+func TestQueryService_ClientTypes(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	window := opencost.NewClosedWindow(start, start.Add(24*time.Hour))
+
+	response := &CostResponse{
+		Window:        window,
+		TotalCost:     5,
+		TotalCostType: CostTypeBilled,
+		CustomCosts: []*CustomCost{{
+			Id:                 "1",
+			Zone:               "us-east-1",
+			AccountName:        "account",
+			ChargeCategory:     "usage",
+			Description:        "hosts",
+			ResourceName:       "host",
+			ResourceType:       "infra",
+			ProviderId:         "provider-id",
+			Cost:               5,
+			ListUnitPrice:      1,
+			UsageQuantity:      5,
+			UsageUnit:          "hosts",
+			Domain:             "datadog",
+			CostSource:         "observability",
+			Aggregate:          "datadog",
+			CostType:           CostTypeBilled,
+			Labels:             map[string]string{"team": "platform"},
+			ExtendedAttributes: map[string]string{"region": "us-east-1"},
+		}},
+	}
+
+	assertClientType(t, response, &client.CustomCostResponse{})
+	assertClientType(t, &CostTimeseriesResponse{Window: window, Timeseries: []*CostResponse{response}}, &client.CustomCostTimeseriesResponse{})
+	assertClientType(t, &Status{
+		Enabled:           true,
+		Domains:           []string{"datadog"},
+		Key:               "key",
+		Source:            "plugin",
+		Provider:          "datadog",
+		Active:            true,
+		Valid:             true,
+		LastRun:           start,
+		NextRun:           start.Add(time.Hour),
+		RefreshRateDaily:  "24h",
+		RefreshRateHourly: "1h",
+		Created:           start,
+		Runs:              3,
+		CoverageHourly:    map[string]opencost.Window{"datadog": window},
+		CoverageDaily:     map[string]opencost.Window{"datadog": window},
+		ConnectionStatus:  "successful",
+	}, &client.CustomCostStatus{})
+}
+
+func assertClientType(t *testing.T, server, mirror any) {
+	t.Helper()
+
+	data, err := json.Marshal(server)
+	require.NoError(t, err)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	require.NoError(t, dec.Decode(mirror), "client type %T is missing fields of %T", mirror, server)
+
+	roundTrip, err := json.Marshal(mirror)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(roundTrip), "client type %T does not match %T", mirror, server)
+}
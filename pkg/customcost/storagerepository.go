@@ -0,0 +1,78 @@
+package customcost
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/model/pb"
+	"github.com/opencost/opencost/core/pkg/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+const storageRepositoryExt = ".pb"
+
+// StorageRepository is an implementation of Repository which persists each CustomCostResponse to a
+// storage.WindowStorage, keyed on the domain and window start.
+type StorageRepository struct {
+	files *storage.WindowStorage
+}
+
+// NewStorageRepository creates a StorageRepository which stores CustomCostResponses in the given directory of the
+// storage. The hourly and daily repositories must use separate directories.
+func NewStorageRepository(store storage.Storage, dir string) *StorageRepository {
+	return &StorageRepository{
+		files: storage.NewWindowStorage(store, dir, storageRepositoryExt),
+	}
+}
+
+func (s *StorageRepository) Has(startTime time.Time, domain string) (bool, error) {
+	return s.files.Has(startTime, domain)
+}
+
+func (s *StorageRepository) Get(startTime time.Time, domain string) (*pb.CustomCostResponse, error) {
+	b, err := s.files.Read(startTime, domain)
+	if err != nil {
+		return nil, fmt.Errorf("StorageRepository: Get: %w", err)
+	}
+	if b == nil {
+		return &pb.CustomCostResponse{}, nil
+	}
+
+	ccr := &pb.CustomCostResponse{}
+	err = proto.Unmarshal(b, ccr)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling data: %w", err)
+	}
+	return ccr, nil
+}
+
+// Keys returns the domains which have at least one CustomCostResponse in the storage
+func (s *StorageRepository) Keys() ([]string, error) {
+	return s.files.Keys()
+}
+
+func (s *StorageRepository) Put(ccr *pb.CustomCostResponse) error {
+	if ccr == nil {
+		return fmt.Errorf("StorageRepository: Put: cannot save nil")
+	}
+
+	if ccr.Start == nil || ccr.End == nil {
+		return fmt.Errorf("StorageRepository: Put: custom cost response has invalid window")
+	}
+
+	if ccr.GetDomain() == "" {
+		return fmt.Errorf("StorageRepository: Put: custom cost response does not have a domain value")
+	}
+
+	b, err := proto.Marshal(ccr)
+	if err != nil {
+		return fmt.Errorf("StorageRepository: Put: custom cost could not be marshalled")
+	}
+
+	return s.files.Write(ccr.Start.AsTime(), ccr.GetDomain(), b)
+}
+
+// Expire deletes all CustomCostResponses in the storage with a start time before the given limit
+func (s *StorageRepository) Expire(limit time.Time) error {
+	return s.files.Expire(limit)
+}
@@ -0,0 +1,86 @@
+package customcost
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/model/pb"
+	"github.com/opencost/opencost/core/pkg/storage"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testCustomCostResponse(domain string, start time.Time, cost float32) *pb.CustomCostResponse {
+	return &pb.CustomCostResponse{
+		Domain: domain,
+		Start:  timestamppb.New(start),
+		End:    timestamppb.New(start.Add(time.Hour)),
+		Costs: []*pb.CustomCost{
+			{
+				Id:         "1",
+				BilledCost: cost,
+			},
+		},
+	}
+}
+
+func TestStorageRepository(t *testing.T) {
+	hourOne := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	hourTwo := hourOne.Add(time.Hour)
+
+	store := storage.NewMemoryStorage()
+	hourly := NewStorageRepository(store, "customcost/hourly")
+	daily := NewStorageRepository(store, "customcost/daily")
+
+	if err := hourly.Put(nil); err == nil {
+		t.Errorf("Put() with nil succeeded, want error")
+	}
+	if err := hourly.Put(&pb.CustomCostResponse{Domain: "datadog"}); err == nil {
+		t.Errorf("Put() without a window succeeded, want error")
+	}
+
+	for _, ccr := range []*pb.CustomCostResponse{
+		testCustomCostResponse("datadog", hourOne, 1),
+		testCustomCostResponse("datadog", hourTwo, 2),
+		testCustomCostResponse("snowflake", hourOne, 3),
+	} {
+		if err := hourly.Put(ccr); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	got, err := hourly.Get(hourTwo, "datadog")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if want := testCustomCostResponse("datadog", hourTwo, 2); !proto.Equal(got, want) {
+		t.Errorf("Get() got = %v, want %v", got, want)
+	}
+
+	// missing data is an empty response, as with the MemoryRepository
+	got, err = daily.Get(hourOne, "datadog")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !proto.Equal(got, &pb.CustomCostResponse{}) {
+		t.Errorf("Get() from the daily repository got = %v, want empty response", got)
+	}
+
+	if err := hourly.Expire(hourTwo); err != nil {
+		t.Fatalf("Expire() error = %v", err)
+	}
+	keys, err := hourly.Keys()
+	if err != nil {
+		t.Fatalf("Keys() error = %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"datadog"}) {
+		t.Errorf("Keys() after Expire() got = %v, want [datadog]", keys)
+	}
+	if has, _ := hourly.Has(hourOne, "datadog"); has {
+		t.Errorf("Has() got = true for an expired response, want false")
+	}
+	if has, _ := hourly.Has(hourTwo, "datadog"); !has {
+		t.Errorf("Has() got = false, want true")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/opencost/opencost/core/pkg/log"
 	"github.com/opencost/opencost/core/pkg/model/pb"
 	"github.com/opencost/opencost/core/pkg/opencost"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 type CostType string
@@ -75,6 +76,9 @@ type CustomCost struct {
 	CostSource     string   `json:"cost_source"`
 	Aggregate      string   `json:"aggregate"`
 	CostType       CostType `json:"cost_type"`
+
+	Labels             map[string]string `json:"labels,omitempty"`
+	ExtendedAttributes map[string]string `json:"extended_attributes,omitempty"`
 }
 
 type CostTimeseriesResponse struct {
@@ -136,12 +140,38 @@ func ParseCustomCostResponse(ccResponse *pb.CustomCostResponse, costType CostTyp
 			Domain:         ccResponse.GetDomain(),
 			CostSource:     ccResponse.GetCostSource(),
 			CostType:       selectedCostType,
+
+			Labels:             cost.GetLabels(),
+			ExtendedAttributes: parseExtendedAttributes(cost.GetExtendedAttributes()),
 		})
 	}
 
 	return customCosts
 }
 
+// parseExtendedAttributes flattens the string-valued FOCUS extended attributes
+// of a custom cost into a map keyed by the attribute's proto field name, e.g.
+// "service_name" or "sub_account_id".
+func parseExtendedAttributes(ea *pb.CustomCostExtendedAttributes) map[string]string {
+	if ea == nil {
+		return nil
+	}
+
+	attributes := map[string]string{}
+	ea.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if fd.Kind() == protoreflect.StringKind {
+			attributes[string(fd.Name())] = v.String()
+		}
+		return true
+	})
+
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	return attributes
+}
+
 func determineCost(cc *pb.CustomCost, costType CostType) (float32, CostType) {
 	switch costType {
 	// if the cost type is blended, first check if the billed cost is non-zero
@@ -221,6 +251,28 @@ func (cc *CustomCost) Add(other *CustomCost) {
 		cc.Aggregate = ""
 	}
 
+	cc.Labels = intersectLabels(cc.Labels, other.Labels)
+	cc.ExtendedAttributes = intersectLabels(cc.ExtendedAttributes, other.ExtendedAttributes)
+}
+
+// intersectLabels returns the key/value pairs which are shared by both maps
+func intersectLabels(this, that map[string]string) map[string]string {
+	if len(this) == 0 || len(that) == 0 {
+		return nil
+	}
+
+	result := map[string]string{}
+	for k, v := range this {
+		if thatV, ok := that[k]; ok && thatV == v {
+			result[k] = v
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
 }
 
 type CustomCostSet struct {
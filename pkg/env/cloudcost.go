@@ -7,6 +7,7 @@ import (
 const (
 	CloudCostConfigControllerStateFile = "cloud-configurations.json"
 	CloudIntegrationConfigFile         = "cloud-integration.json"
+	CustomCostAttributionConfigFile    = "custom-cost-attribution.json"
 	AzureBillingDataDownloadPath       = "db/cloudcost"
 )
 
@@ -40,6 +41,10 @@ func GetCloudCostConfigPath() string {
 	return env.GetPathFromConfig(CloudIntegrationConfigFile)
 }
 
+func GetCustomCostAttributionConfigPath() string {
+	return env.GetPathFromConfig(CustomCostAttributionConfigFile)
+}
+
 func GetCloudCostMonthToDateInterval() int {
 	return env.GetInt(CloudCostMonthToDateIntervalVar, 6)
 }
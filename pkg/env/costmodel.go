@@ -1,7 +1,12 @@
 package env
 
 import (
+	"strings"
+	"time"
+
 	"github.com/opencost/opencost/core/pkg/env"
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/util/timeutil"
 )
 
 // FilePaths
@@ -10,6 +15,9 @@ const (
 	ClusterCacheFile
 	GCPAuthSecretFile        = "key.json"
 	MetricConfigFile         = "metrics.json"
+	SharingRulesConfigFile   = "sharing-rules.json"
+	CostCentersConfigFile    = "cost-centers.json"
+	LabelRulesConfigFile     = "label-rules.json"
 	DefaultLocalCollectorDir = "collector"
 )
 
@@ -90,6 +98,46 @@ const (
 	// MCP Server
 	MCPServerEnabledEnvVar = "MCP_SERVER_ENABLED"
 	MCPHTTPPortEnvVar      = "MCP_HTTP_PORT"
+
+	// CRD based configuration
+	CRDControllerEnabledEnvVar = "CRD_CONTROLLER_ENABLED"
+
+	// gRPC Query API
+	GRPCServerEnabledEnvVar = "GRPC_SERVER_ENABLED"
+	GRPCPortEnvVar          = "GRPC_PORT"
+
+	// GraphQL Query API
+	GraphQLEnabledEnvVar = "GRAPHQL_ENABLED"
+
+	// kubemodel snapshot export
+	KubeModelExportEnabledEnvVar     = "KUBEMODEL_EXPORT_ENABLED"
+	KubeModelExportResolutionsEnvVar = "KUBEMODEL_EXPORT_RESOLUTIONS"
+
+	// Asynchronous query jobs
+	QueryJobConcurrencyEnvVar = "QUERY_JOB_CONCURRENCY"
+	QueryJobQueueSizeEnvVar   = "QUERY_JOB_QUEUE_SIZE"
+	QueryJobResultTTLEnvVar   = "QUERY_JOB_RESULT_TTL"
+
+	// Allocation usage percentiles
+	AllocationUsagePercentilesEnabledEnvVar = "ALLOCATION_USAGE_PERCENTILES_ENABLED"
+
+	// Leader election
+	LeaderElectionEnabledEnvVar       = "LEADER_ELECTION_ENABLED"
+	LeaderElectionLeaseNameEnvVar     = "LEADER_ELECTION_LEASE_NAME"
+	LeaderElectionLeaseDurationEnvVar = "LEADER_ELECTION_LEASE_DURATION"
+	LeaderElectionRenewDeadlineEnvVar = "LEADER_ELECTION_RENEW_DEADLINE"
+	LeaderElectionRetryPeriodEnvVar   = "LEADER_ELECTION_RETRY_PERIOD"
+	PodNameEnvVar                     = "POD_NAME"
+
+	// Per-request query deadline
+	QueryTimeoutEnvVar = "QUERY_TIMEOUT"
+
+	// OpenTelemetry traces and metrics
+	TelemetryEnabledEnvVar              = "TELEMETRY_ENABLED"
+	TelemetryOTLPEndpointEnvVar         = "TELEMETRY_OTLP_ENDPOINT"
+	TelemetryOTLPInsecureEnvVar         = "TELEMETRY_OTLP_INSECURE"
+	TelemetryTraceSampleRatioEnvVar     = "TELEMETRY_TRACE_SAMPLE_RATIO"
+	TelemetryMetricExportIntervalEnvVar = "TELEMETRY_METRIC_EXPORT_INTERVAL"
 )
 
 func GetGCPAuthSecretFilePath() string {
@@ -363,6 +411,18 @@ func GetMetricConfigFile() string {
 	return env.GetPathFromConfig(MetricConfigFile)
 }
 
+func GetSharingRulesConfigFile() string {
+	return env.GetPathFromConfig(SharingRulesConfigFile)
+}
+
+func GetCostCentersConfigFile() string {
+	return env.GetPathFromConfig(CostCentersConfigFile)
+}
+
+func GetLabelRulesConfigFile() string {
+	return env.GetPathFromConfig(LabelRulesConfigFile)
+}
+
 func GetLocalCollectorDirectory() string {
 	dir := env.Get(LocalCollectorDirectoryEnvVar, DefaultLocalCollectorDir)
 	return env.GetPathFromConfig(dir)
@@ -384,3 +444,154 @@ func IsMCPServerEnabled() bool {
 func GetMCPHTTPPort() int {
 	return env.GetInt(MCPHTTPPortEnvVar, 8081)
 }
+
+// IsCRDControllerEnabled returns the environment variable value for CRDControllerEnabledEnvVar which represents
+// whether or not OpenCost is configured from its custom resources.
+func IsCRDControllerEnabled() bool {
+	return env.GetBool(CRDControllerEnabledEnvVar, false)
+}
+
+// IsGRPCServerEnabled returns the environment variable value for GRPCServerEnabledEnvVar which represents
+// whether or not the gRPC query API is served.
+func IsGRPCServerEnabled() bool {
+	return env.GetBool(GRPCServerEnabledEnvVar, false)
+}
+
+// GetGRPCPort returns the environment variable value for GRPCPortEnvVar which represents
+// the port for the gRPC query API.
+func GetGRPCPort() int {
+	return env.GetInt(GRPCPortEnvVar, 9004)
+}
+
+// IsGraphQLEnabled returns the environment variable value for GraphQLEnabledEnvVar which represents
+// whether or not the GraphQL query API is served at /graphql.
+func IsGraphQLEnabled() bool {
+	return env.GetBool(GraphQLEnabledEnvVar, false)
+}
+
+// IsKubeModelExportEnabled returns the environment variable value for KubeModelExportEnabledEnvVar which represents
+// whether or not kubemodel snapshots are exported to the default storage.
+func IsKubeModelExportEnabled() bool {
+	return env.GetBool(KubeModelExportEnabledEnvVar, false)
+}
+
+// GetKubeModelExportResolutions returns the environment variable value for KubeModelExportResolutionsEnvVar which
+// represents the comma separated window durations of exported kubemodel snapshots, each of which must be 10m, 1h
+// or 1d. Defaults to 1h and 1d.
+func GetKubeModelExportResolutions() []time.Duration {
+	values := env.GetList(KubeModelExportResolutionsEnvVar, ",")
+	if len(values) == 0 {
+		return []time.Duration{time.Hour, 24 * time.Hour}
+	}
+
+	resolutions := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		resolution, err := timeutil.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			log.Warnf("Invalid %s value '%s': %s", KubeModelExportResolutionsEnvVar, value, err)
+			continue
+		}
+		resolutions = append(resolutions, resolution)
+	}
+	return resolutions
+}
+
+// GetQueryJobConcurrency returns the environment variable value for QueryJobConcurrencyEnvVar which represents
+// the maximum number of asynchronous query jobs which run at once.
+func GetQueryJobConcurrency() int {
+	return env.GetInt(QueryJobConcurrencyEnvVar, 2)
+}
+
+// GetQueryJobQueueSize returns the environment variable value for QueryJobQueueSizeEnvVar which represents
+// the number of query jobs which can wait to run before further jobs are rejected.
+func GetQueryJobQueueSize() int {
+	return env.GetInt(QueryJobQueueSizeEnvVar, 100)
+}
+
+// GetQueryJobResultTTL returns the environment variable value for QueryJobResultTTLEnvVar which represents
+// how long completed asynchronous query jobs and their results are kept.
+func GetQueryJobResultTTL() time.Duration {
+	return env.GetDuration(QueryJobResultTTLEnvVar, 24*time.Hour)
+}
+
+// IsAllocationUsagePercentilesEnabled returns the environment variable value for AllocationUsagePercentilesEnabledEnvVar
+// which represents whether the CPU and RAM usage percentiles of allocations are queried. They are disabled by default,
+// as they add to the number of queries made by each allocation computation.
+func IsAllocationUsagePercentilesEnabled() bool {
+	return env.GetBool(AllocationUsagePercentilesEnabledEnvVar, false)
+}
+
+// IsLeaderElectionEnabled returns the environment variable value for LeaderElectionEnabledEnvVar which represents
+// whether replicas elect a leader with a Kubernetes Lease, limiting ingestion and export to the leader.
+func IsLeaderElectionEnabled() bool {
+	return env.GetBool(LeaderElectionEnabledEnvVar, false)
+}
+
+// GetLeaderElectionLeaseName returns the environment variable value for LeaderElectionLeaseNameEnvVar which represents
+// the name of the Lease in the OpenCost namespace which replicas elect the leader with.
+func GetLeaderElectionLeaseName() string {
+	return env.Get(LeaderElectionLeaseNameEnvVar, "opencost-leader")
+}
+
+// GetLeaderElectionLeaseDuration returns the environment variable value for LeaderElectionLeaseDurationEnvVar which
+// represents how long followers wait before taking over a lease which the leader has not renewed.
+func GetLeaderElectionLeaseDuration() time.Duration {
+	return env.GetDuration(LeaderElectionLeaseDurationEnvVar, 15*time.Second)
+}
+
+// GetLeaderElectionRenewDeadline returns the environment variable value for LeaderElectionRenewDeadlineEnvVar which
+// represents how long the leader retries renewing the lease before it stops leading.
+func GetLeaderElectionRenewDeadline() time.Duration {
+	return env.GetDuration(LeaderElectionRenewDeadlineEnvVar, 10*time.Second)
+}
+
+// GetLeaderElectionRetryPeriod returns the environment variable value for LeaderElectionRetryPeriodEnvVar which
+// represents the interval between attempts to acquire or renew the lease.
+func GetLeaderElectionRetryPeriod() time.Duration {
+	return env.GetDuration(LeaderElectionRetryPeriodEnvVar, 2*time.Second)
+}
+
+// GetPodName returns the environment variable value for PodNameEnvVar which represents the name of the pod of this
+// replica, which identifies it in leader election.
+func GetPodName() string {
+	return env.Get(PodNameEnvVar, "")
+}
+
+// GetQueryTimeout returns the environment variable value for QueryTimeoutEnvVar which represents the deadline of
+// allocation and asset queries made through the API. A value of 0 disables the deadline, so that queries only end
+// when they complete or the client goes away.
+func GetQueryTimeout() time.Duration {
+	return env.GetDuration(QueryTimeoutEnvVar, 0)
+}
+
+// IsTelemetryEnabled returns the environment variable value for TelemetryEnabledEnvVar which represents whether
+// traces and metrics are exported over OTLP.
+func IsTelemetryEnabled() bool {
+	return env.GetBool(TelemetryEnabledEnvVar, false)
+}
+
+// GetTelemetryOTLPEndpoint returns the environment variable value for TelemetryOTLPEndpointEnvVar which represents
+// the host:port of the OTLP gRPC receiver, e.g. an OpenTelemetry Collector or Tempo, which traces and metrics are
+// exported to.
+func GetTelemetryOTLPEndpoint() string {
+	return env.Get(TelemetryOTLPEndpointEnvVar, "localhost:4317")
+}
+
+// IsTelemetryOTLPInsecure returns the environment variable value for TelemetryOTLPInsecureEnvVar which represents
+// whether the connection to the OTLP receiver is made without TLS.
+func IsTelemetryOTLPInsecure() bool {
+	return env.GetBool(TelemetryOTLPInsecureEnvVar, false)
+}
+
+// GetTelemetryTraceSampleRatio returns the environment variable value for TelemetryTraceSampleRatioEnvVar which
+// represents the fraction of traces started by OpenCost which are sampled. Traces continued from a sampled parent,
+// such as a traced HTTP request, are always sampled.
+func GetTelemetryTraceSampleRatio() float64 {
+	return env.GetFloat64(TelemetryTraceSampleRatioEnvVar, 1.0)
+}
+
+// GetTelemetryMetricExportInterval returns the environment variable value for TelemetryMetricExportIntervalEnvVar
+// which represents the interval at which metrics are exported.
+func GetTelemetryMetricExportInterval() time.Duration {
+	return env.GetDuration(TelemetryMetricExportIntervalEnvVar, time.Minute)
+}
@@ -0,0 +1,398 @@
+// Package graphqlapi implements a GraphQL API over the cost data model, allowing
+// clients to select only the fields of allocations, assets, cloud costs and
+// network insights which they need.
+package graphqlapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/julienschmidt/httprouter"
+
+	networkinsightfilter "github.com/opencost/opencost/core/pkg/filter/networkinsight"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/util/httputil"
+	"github.com/opencost/opencost/pkg/cloudcost"
+	"github.com/opencost/opencost/pkg/costmodel"
+	"github.com/opencost/opencost/pkg/env"
+)
+
+// Server serves GraphQL queries using the same queriers which back the HTTP
+// APIs. Any of its dependencies may be nil, in which case the corresponding
+// fields resolve to an error.
+type Server struct {
+	accesses         *costmodel.Accesses
+	cloudCostQuerier cloudcost.Querier
+	schema           graphql.Schema
+}
+
+// NewServer creates a new Server.
+func NewServer(accesses *costmodel.Accesses, cloudCostQuerier cloudcost.Querier) (*Server, error) {
+	s := &Server{
+		accesses:         accesses,
+		cloudCostQuerier: cloudCostQuerier,
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: s.queryType(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating graphql schema: %w", err)
+	}
+	s.schema = schema
+
+	return s, nil
+}
+
+func (s *Server) queryType() *graphql.Object {
+	windowArg := &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The window to query, e.g. \"7d\" or \"2024-01-01T00:00:00Z,2024-01-02T00:00:00Z\".",
+	}
+	aggregateArg := &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+		Description: "The properties by which to aggregate, e.g. [\"namespace\", \"label:app\"].",
+	}
+	filterArg := &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "A filter in the filter language of the queried type.",
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"allocations": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(allocationSetType))),
+				Args: graphql.FieldConfigArgument{
+					"window":    windowArg,
+					"aggregate": aggregateArg,
+					"filter":    filterArg,
+					"step": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "The duration of each AllocationSet. Defaults to the duration of the window.",
+					},
+					"accumulate": &graphql.ArgumentConfig{
+						Type:        graphql.Boolean,
+						Description: "Sums the AllocationSets into one. Equivalent to accumulateBy \"all\".",
+					},
+					"accumulateBy": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "Sums the AllocationSets by the given period, e.g. \"day\" or \"week\".",
+					},
+					"includeIdle":                           {Type: graphql.Boolean},
+					"idleByNode":                            {Type: graphql.Boolean},
+					"shareIdle":                             {Type: graphql.Boolean},
+					"shareLB":                               {Type: graphql.Boolean},
+					"includeProportionalAssetResourceCosts": {Type: graphql.Boolean},
+					"includeAggregatedMetadata":             {Type: graphql.Boolean},
+					"includeCustomCosts":                    {Type: graphql.Boolean},
+					"includeSharedCostBreakdown":            {Type: graphql.Boolean},
+					"shareNamespaces": &graphql.ArgumentConfig{
+						Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+						Description: "The namespaces whose costs are shared with the other allocations.",
+					},
+					"shareLabels": &graphql.ArgumentConfig{
+						Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+						Description: "The labels, as key:value pairs, of the allocations whose costs are shared with the others.",
+					},
+					"shareSplit": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "How shared costs are split, either \"weighted\" (the default) or \"even\".",
+					},
+					"shareTenancyCosts": &graphql.ArgumentConfig{
+						Type:        graphql.Boolean,
+						Description: "Shares the cluster management costs with the allocations.",
+					},
+				},
+				Resolve: s.resolveAllocations,
+			},
+			"assets": &graphql.Field{
+				Type: assetSetType,
+				Args: graphql.FieldConfigArgument{
+					"window":    windowArg,
+					"aggregate": aggregateArg,
+					"filter":    filterArg,
+				},
+				Resolve: s.resolveAssets,
+			},
+			"cloudCosts": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(cloudCostSetType))),
+				Args: graphql.FieldConfigArgument{
+					"window":    windowArg,
+					"aggregate": aggregateArg,
+					"filter":    filterArg,
+					"accumulate": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "Sums the CloudCostSets by the given period, e.g. \"day\" or \"all\".",
+					},
+				},
+				Resolve: s.resolveCloudCosts,
+			},
+			"networkInsights": &graphql.Field{
+				Type: networkInsightSetType,
+				Args: graphql.FieldConfigArgument{
+					"window":    windowArg,
+					"aggregate": aggregateArg,
+					"filter":    filterArg,
+				},
+				Resolve: s.resolveNetworkInsights,
+			},
+		},
+	})
+}
+
+func (s *Server) resolveAllocations(p graphql.ResolveParams) (any, error) {
+	if s.accesses == nil || s.accesses.Model == nil {
+		return nil, fmt.Errorf("allocation queries require Kubernetes to be enabled")
+	}
+
+	window, opts, err := costmodel.ParseAllocationQuery(httputil.NewQueryParams(allocationQueryValues(p.Args)))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := costmodel.QueryContext(p.Context)
+	defer cancel()
+
+	asr, err := s.accesses.Model.QueryAllocation(ctx, window, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return asr.Allocations, nil
+}
+
+func (s *Server) resolveAssets(p graphql.ResolveParams) (any, error) {
+	if s.accesses == nil || s.accesses.Model == nil {
+		return nil, fmt.Errorf("asset queries require Kubernetes to be enabled")
+	}
+
+	window, err := parseWindow(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	if window.IsOpen() {
+		return nil, fmt.Errorf("invalid window '%s': window must be closed", stringArg(p.Args, "window"))
+	}
+
+	props, err := opencost.ParseAssetProperties(stringsArg(p.Args, "aggregate"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregate: %w", err)
+	}
+
+	ctx, cancel := costmodel.QueryContext(p.Context)
+	defer cancel()
+
+	assetSet, err := s.accesses.ComputeAssetsFromCostmodel(ctx, window, stringArg(p.Args, "filter"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(props) > 0 {
+		aggregateBy := make([]string, len(props))
+		for i, prop := range props {
+			aggregateBy[i] = string(prop)
+		}
+		if err := assetSet.AggregateBy(aggregateBy, nil); err != nil {
+			return nil, fmt.Errorf("aggregating assets: %w", err)
+		}
+	}
+
+	return assetSet, nil
+}
+
+func (s *Server) resolveCloudCosts(p graphql.ResolveParams) (any, error) {
+	if s.cloudCostQuerier == nil {
+		return nil, fmt.Errorf("cloud cost queries require cloud costs to be enabled")
+	}
+
+	values := url.Values{}
+	values.Set("window", stringArg(p.Args, "window"))
+	if aggregate := stringsArg(p.Args, "aggregate"); len(aggregate) > 0 {
+		values.Set("aggregate", strings.Join(aggregate, ","))
+	}
+	if accumulate := stringArg(p.Args, "accumulate"); accumulate != "" {
+		values.Set("accumulate", accumulate)
+	}
+	if filter := stringArg(p.Args, "filter"); filter != "" {
+		values.Set("filter", filter)
+	}
+
+	request, err := cloudcost.ParseCloudCostRequest(httputil.NewQueryParams(values))
+	if err != nil {
+		return nil, err
+	}
+
+	ccsr, err := s.cloudCostQuerier.Query(p.Context, *request)
+	if err != nil {
+		return nil, fmt.Errorf("querying cloud costs: %w", err)
+	}
+	if ccsr == nil {
+		return []*opencost.CloudCostSet{}, nil
+	}
+
+	return ccsr.CloudCostSets, nil
+}
+
+func (s *Server) resolveNetworkInsights(p graphql.ResolveParams) (any, error) {
+	if s.accesses == nil || s.accesses.Model == nil {
+		return nil, fmt.Errorf("network insight queries require Kubernetes to be enabled")
+	}
+
+	window, err := parseWindow(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	if window.IsOpen() {
+		return nil, fmt.Errorf("invalid window '%s': window must be closed", stringArg(p.Args, "window"))
+	}
+
+	var aggregateBy []opencost.NetworkInsightProperty
+	for _, agg := range stringsArg(p.Args, "aggregate") {
+		switch agg {
+		case opencost.NetworkInsightsCluster, opencost.NetworkInsightsNamespace, opencost.NetworkInsightsPod:
+			aggregateBy = append(aggregateBy, opencost.NetworkInsightProperty(agg))
+		default:
+			return nil, fmt.Errorf("invalid aggregate: unsupported network insight property '%s'", agg)
+		}
+	}
+
+	nis, err := s.accesses.Model.ComputeNetworkInsights(*window.Start(), *window.End())
+	if err != nil {
+		return nil, err
+	}
+
+	if filterStr := stringArg(p.Args, "filter"); filterStr != "" && !nis.IsEmpty() {
+		filter, err := networkinsightfilter.NewNetworkInsightFilterParser().Parse(filterStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		if err := nis.FilterOn(filter); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(aggregateBy) > 0 {
+		if err := nis.AggregateBy(aggregateBy); err != nil {
+			return nil, fmt.Errorf("aggregating network insights: %w", err)
+		}
+	}
+
+	return nis, nil
+}
+
+// request is a GraphQL request, as sent in the body of a POST request or the
+// parameters of a GET request.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handle executes the GraphQL request sent as a GET or POST request.
+func (s *Server) Handle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	var req request
+
+	switch r.Method {
+	case http.MethodGet:
+		qp := r.URL.Query()
+		req.Query = qp.Get("query")
+		req.OperationName = qp.Get("operationName")
+		if variables := qp.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid 'variables' parameter: %s", err), http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %s", err), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if req.Query == "" {
+		http.Error(w, "Missing 'query'", http.StatusBadRequest)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         s.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        r.Context(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, fmt.Sprintf("Error encoding response: %s", err), http.StatusInternalServerError)
+	}
+}
+
+// allocationQueryArgs maps the arguments of the allocations query which are
+// named differently from the parameters of the HTTP API.
+var allocationQueryArgs = map[string]string{
+	"shareLB": "sharelb",
+}
+
+// allocationQueryValues converts the arguments of the allocations query into
+// the parameters of the HTTP API, so that both are parsed the same way.
+func allocationQueryValues(args map[string]any) url.Values {
+	values := url.Values{}
+	for name, arg := range args {
+		param := name
+		if renamed, ok := allocationQueryArgs[name]; ok {
+			param = renamed
+		}
+
+		switch arg := arg.(type) {
+		case string:
+			values.Set(param, arg)
+		case bool:
+			values.Set(param, strconv.FormatBool(arg))
+		case []any:
+			values.Set(param, strings.Join(stringsArg(args, name), ","))
+		}
+	}
+	return values
+}
+
+func parseWindow(args map[string]any) (opencost.Window, error) {
+	windowStr := stringArg(args, "window")
+	window, err := opencost.ParseWindowWithOffset(windowStr, env.GetParsedUTCOffset())
+	if err != nil {
+		return window, fmt.Errorf("invalid window '%s': %w", windowStr, err)
+	}
+	return window, nil
+}
+
+func stringArg(args map[string]any, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func boolArg(args map[string]any, name string) bool {
+	b, _ := args[name].(bool)
+	return b
+}
+
+func stringsArg(args map[string]any, name string) []string {
+	values, _ := args[name].([]any)
+
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
@@ -0,0 +1,267 @@
+package graphqlapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/pkg/cloudcost"
+	"github.com/opencost/opencost/pkg/costmodel"
+)
+
+type fakeCloudCostQuerier struct {
+	request cloudcost.QueryRequest
+	ccsr    *opencost.CloudCostSetRange
+}
+
+func (f *fakeCloudCostQuerier) Query(_ context.Context, request cloudcost.QueryRequest) (*opencost.CloudCostSetRange, error) {
+	f.request = request
+	return f.ccsr, nil
+}
+
+func newTestRouter(t *testing.T, server *Server) *httprouter.Router {
+	t.Helper()
+
+	router := httprouter.New()
+	router.GET("/graphql", server.Handle)
+	router.POST("/graphql", server.Handle)
+	return router
+}
+
+func post(t *testing.T, handler http.Handler, query string, variables map[string]any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(request{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)))
+	return w
+}
+
+func TestCloudCostsQuery(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	ccs := opencost.NewCloudCostSet(start, end)
+	ccs.Insert(opencost.NewCloudCost(start, end, &opencost.CloudCostProperties{
+		Provider: "AWS",
+		Service:  "AmazonEC2",
+		Labels:   opencost.CloudCostLabels{"team": "platform", "env": "prod"},
+	}, 0.5, 10, 8, 8, 8, 10))
+
+	querier := &fakeCloudCostQuerier{
+		ccsr: &opencost.CloudCostSetRange{CloudCostSets: []*opencost.CloudCostSet{ccs}},
+	}
+	server, err := NewServer(nil, querier)
+	require.NoError(t, err)
+
+	w := post(t, newTestRouter(t, server), `
+		query($window: String!) {
+			cloudCosts(window: $window, aggregate: ["service"], accumulate: "day", filter: "provider:\"AWS\"") {
+				start
+				cloudCosts {
+					properties { service labels { key value } }
+					listCost { cost }
+				}
+			}
+		}`, map[string]any{"window": "2024-01-01T00:00:00Z,2024-01-02T00:00:00Z"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	assert.Equal(t, start, querier.request.Start)
+	assert.Equal(t, end, querier.request.End)
+	assert.Equal(t, []string{opencost.CloudCostServiceProp}, querier.request.AggregateBy)
+	assert.Equal(t, opencost.AccumulateOptionDay, querier.request.Accumulate)
+	assert.NotNil(t, querier.request.Filter)
+
+	assert.JSONEq(t, `{"data":{"cloudCosts":[{
+		"start": "2024-01-01T00:00:00Z",
+		"cloudCosts": [{
+			"properties": {
+				"service": "AmazonEC2",
+				"labels": [{"key": "env", "value": "prod"}, {"key": "team", "value": "platform"}]
+			},
+			"listCost": {"cost": 10}
+		}]
+	}]}}`, w.Body.String())
+}
+
+func TestAllocationFieldSelection(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	as := opencost.NewAllocationSet(start, start.Add(24*time.Hour),
+		opencost.NewMockUnitAllocation("kubecost", start, 24*time.Hour, &opencost.AllocationProperties{Namespace: "kubecost"}),
+		opencost.NewMockUnitAllocation("default", start, 24*time.Hour, &opencost.AllocationProperties{Namespace: "default"}),
+	)
+
+	// Serve a fixed AllocationSet, as computing one requires a CostModel
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"allocations": &graphql.Field{
+					Type: graphql.NewList(allocationSetType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return []*opencost.AllocationSet{as}, nil
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ allocations { allocations { name totalCost properties { namespace } } } }`,
+	})
+	require.Empty(t, result.Errors)
+
+	actual, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"allocations":[{"allocations":[
+		{"name": "default", "totalCost": 6, "properties": {"namespace": "default"}},
+		{"name": "kubecost", "totalCost": 6, "properties": {"namespace": "kubecost"}}
+	]}]}`, string(actual))
+}
+
+func TestQueryErrors(t *testing.T) {
+	server, err := NewServer(nil, &fakeCloudCostQuerier{})
+	require.NoError(t, err)
+	router := newTestRouter(t, server)
+
+	cases := map[string]struct {
+		query   string
+		message string
+	}{
+		"allocations unavailable": {
+			query:   `{ allocations(window: "1d") { totalCost } }`,
+			message: "allocation queries require Kubernetes to be enabled",
+		},
+		"assets unavailable": {
+			query:   `{ assets(window: "1d") { totalCost } }`,
+			message: "asset queries require Kubernetes to be enabled",
+		},
+		"network insights unavailable": {
+			query:   `{ networkInsights(window: "1d") { start } }`,
+			message: "network insight queries require Kubernetes to be enabled",
+		},
+		"invalid cloud cost aggregate": {
+			query: `{ cloudCosts(window: "1d", aggregate: ["invalid"]) { start } }`,
+		},
+		"unknown field": {
+			query: `{ cloudCosts(window: "1d") { unknown } }`,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(c.query), nil))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var result struct {
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			require.NotEmpty(t, result.Errors)
+			if c.message != "" {
+				assert.Equal(t, c.message, result.Errors[0].Message)
+			}
+		})
+	}
+}
+
+func TestAllocationArgs(t *testing.T) {
+	// Invalid arguments are rejected before the CostModel is queried
+	server, err := NewServer(&costmodel.Accesses{Model: &costmodel.CostModel{}}, nil)
+	require.NoError(t, err)
+	router := newTestRouter(t, server)
+
+	cases := map[string]struct {
+		args    string
+		message string
+	}{
+		"zero step": {
+			args:    `step: "0s"`,
+			message: "invalid 'step' parameter: 0s must be positive",
+		},
+		"negative step": {
+			args:    `step: "-1h"`,
+			message: "invalid 'step' parameter: -1h must be positive",
+		},
+		"share labels": {
+			args:    `shareLabels: ["team"]`,
+			message: "invalid 'shareLabels' parameter: expected key:value, got 'team'",
+		},
+		"share split": {
+			args:    `shareNamespaces: ["kube-system"], shareSplit: "random"`,
+			message: "invalid 'shareSplit' parameter: random",
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			query := fmt.Sprintf(`{ allocations(window: "1d", %s) { totalCost } }`, c.args)
+			w := post(t, router, query, nil)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var result struct {
+				Errors []struct {
+					Message string `json:"message"`
+				} `json:"errors"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+			require.NotEmpty(t, result.Errors)
+			assert.Equal(t, c.message, result.Errors[0].Message)
+		})
+	}
+}
+
+func TestAllocationQueryValues(t *testing.T) {
+	values := allocationQueryValues(map[string]any{
+		"window":            "1d",
+		"aggregate":         []any{"namespace", "label:app"},
+		"shareLB":           true,
+		"shareNamespaces":   []any{"kube-system", "monitoring"},
+		"shareTenancyCosts": false,
+	})
+
+	assert.Equal(t, url.Values{
+		"window":            {"1d"},
+		"aggregate":         {"namespace,label:app"},
+		"sharelb":           {"true"},
+		"shareNamespaces":   {"kube-system,monitoring"},
+		"shareTenancyCosts": {"false"},
+	}, values)
+}
+
+func TestInvalidRequests(t *testing.T) {
+	server, err := NewServer(nil, nil)
+	require.NoError(t, err)
+	router := newTestRouter(t, server)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?query={}&variables=invalid", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader([]byte("invalid"))))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
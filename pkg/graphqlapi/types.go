@@ -0,0 +1,390 @@
+package graphqlapi
+
+import (
+	"math"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+)
+
+// field creates a field whose value is resolved from a source of type T.
+func field[T any](typ graphql.Output, get func(T) any) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			src, ok := p.Source.(T)
+			if !ok {
+				return nil, nil
+			}
+			return get(src), nil
+		},
+	}
+}
+
+// floatField creates a Float field whose value is resolved from a source of type
+// T. NaN and infinite values, which cannot be serialized, resolve to null.
+func floatField[T any](get func(T) float64) *graphql.Field {
+	return field(graphql.Float, func(src T) any {
+		f := get(src)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil
+		}
+		return f
+	})
+}
+
+func stringField[T any](get func(T) string) *graphql.Field {
+	return field(graphql.String, func(src T) any { return get(src) })
+}
+
+func timeField[T any](get func(T) any) *graphql.Field {
+	return field(graphql.DateTime, get)
+}
+
+// label is a key-value pair of a map of labels or annotations, which GraphQL
+// cannot represent directly.
+type label struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// labels converts a map to a list of labels sorted by key.
+func labels[M ~map[string]string](m M) []label {
+	ls := make([]label, 0, len(m))
+	for k, v := range m {
+		ls = append(ls, label{Key: k, Value: v})
+	}
+	sort.Slice(ls, func(i, j int) bool { return ls[i].Key < ls[j].Key })
+	return ls
+}
+
+// sortedValues returns the non-nil values of a map sorted by key, so that lists
+// are returned in a stable order.
+func sortedValues[M ~map[string]*V, V any](m M) []*V {
+	keys := make([]string, 0, len(m))
+	for key, value := range m {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	values := make([]*V, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, m[key])
+	}
+	return values
+}
+
+func labelsField[T any, M ~map[string]string](get func(T) M) *graphql.Field {
+	return field(graphql.NewList(graphql.NewNonNull(labelType)), func(src T) any { return labels(get(src)) })
+}
+
+var labelType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Label",
+	Description: "A key-value pair of a label or annotation.",
+	Fields: graphql.Fields{
+		"key":   stringField(func(l label) string { return l.Key }),
+		"value": stringField(func(l label) string { return l.Value }),
+	},
+})
+
+var allocationPropertiesType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "AllocationProperties",
+	Fields: graphql.Fields{
+		"cluster":        stringField(func(p *opencost.AllocationProperties) string { return p.Cluster }),
+		"node":           stringField(func(p *opencost.AllocationProperties) string { return p.Node }),
+		"container":      stringField(func(p *opencost.AllocationProperties) string { return p.Container }),
+		"controller":     stringField(func(p *opencost.AllocationProperties) string { return p.Controller }),
+		"controllerKind": stringField(func(p *opencost.AllocationProperties) string { return p.ControllerKind }),
+		"namespace":      stringField(func(p *opencost.AllocationProperties) string { return p.Namespace }),
+		"pod":            stringField(func(p *opencost.AllocationProperties) string { return p.Pod }),
+		"providerID":     stringField(func(p *opencost.AllocationProperties) string { return p.ProviderID }),
+		"services": field(graphql.NewList(graphql.NewNonNull(graphql.String)), func(p *opencost.AllocationProperties) any {
+			return p.Services
+		}),
+		"labels":               labelsField(func(p *opencost.AllocationProperties) opencost.AllocationLabels { return p.Labels }),
+		"annotations":          labelsField(func(p *opencost.AllocationProperties) opencost.AllocationAnnotations { return p.Annotations }),
+		"namespaceLabels":      labelsField(func(p *opencost.AllocationProperties) opencost.AllocationLabels { return p.NamespaceLabels }),
+		"namespaceAnnotations": labelsField(func(p *opencost.AllocationProperties) opencost.AllocationAnnotations { return p.NamespaceAnnotations }),
+	},
+})
+
+var allocationType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Allocation",
+	Fields: graphql.Fields{
+		"name": stringField(func(a *opencost.Allocation) string { return a.Name }),
+		"properties": field(allocationPropertiesType, func(a *opencost.Allocation) any {
+			return a.Properties
+		}),
+		"start":                      timeField(func(a *opencost.Allocation) any { return a.Start }),
+		"end":                        timeField(func(a *opencost.Allocation) any { return a.End }),
+		"minutes":                    floatField((*opencost.Allocation).Minutes),
+		"cpuCores":                   floatField((*opencost.Allocation).CPUCores),
+		"cpuCoreHours":               floatField(func(a *opencost.Allocation) float64 { return a.CPUCoreHours }),
+		"cpuCoreRequestAverage":      floatField(func(a *opencost.Allocation) float64 { return a.CPUCoreRequestAverage }),
+		"cpuCoreUsageAverage":        floatField(func(a *opencost.Allocation) float64 { return a.CPUCoreUsageAverage }),
+		"cpuCoreLimitAverage":        floatField(func(a *opencost.Allocation) float64 { return a.CPUCoreLimitAverage }),
+		"cpuCost":                    floatField(func(a *opencost.Allocation) float64 { return a.CPUCost }),
+		"cpuCostAdjustment":          floatField(func(a *opencost.Allocation) float64 { return a.CPUCostAdjustment }),
+		"cpuEfficiency":              floatField((*opencost.Allocation).CPUEfficiency),
+		"gpuCount":                   floatField((*opencost.Allocation).GPUs),
+		"gpuHours":                   floatField(func(a *opencost.Allocation) float64 { return a.GPUHours }),
+		"gpuCost":                    floatField(func(a *opencost.Allocation) float64 { return a.GPUCost }),
+		"gpuCostAdjustment":          floatField(func(a *opencost.Allocation) float64 { return a.GPUCostAdjustment }),
+		"gpuEfficiency":              floatField((*opencost.Allocation).GPUEfficiency),
+		"networkTransferBytes":       floatField(func(a *opencost.Allocation) float64 { return a.NetworkTransferBytes }),
+		"networkReceiveBytes":        floatField(func(a *opencost.Allocation) float64 { return a.NetworkReceiveBytes }),
+		"networkCost":                floatField(func(a *opencost.Allocation) float64 { return a.NetworkCost }),
+		"networkCrossZoneCost":       floatField(func(a *opencost.Allocation) float64 { return a.NetworkCrossZoneCost }),
+		"networkCrossRegionCost":     floatField(func(a *opencost.Allocation) float64 { return a.NetworkCrossRegionCost }),
+		"networkInternetCost":        floatField(func(a *opencost.Allocation) float64 { return a.NetworkInternetCost }),
+		"networkCostAdjustment":      floatField(func(a *opencost.Allocation) float64 { return a.NetworkCostAdjustment }),
+		"loadBalancerCost":           floatField(func(a *opencost.Allocation) float64 { return a.LoadBalancerCost }),
+		"loadBalancerCostAdjustment": floatField(func(a *opencost.Allocation) float64 { return a.LoadBalancerCostAdjustment }),
+		"pvBytes":                    floatField((*opencost.Allocation).PVBytes),
+		"pvByteHours":                floatField((*opencost.Allocation).PVByteHours),
+		"pvCost":                     floatField((*opencost.Allocation).PVCost),
+		"pvCostAdjustment":           floatField(func(a *opencost.Allocation) float64 { return a.PVCostAdjustment }),
+		"ramBytes":                   floatField((*opencost.Allocation).RAMBytes),
+		"ramByteHours":               floatField(func(a *opencost.Allocation) float64 { return a.RAMByteHours }),
+		"ramBytesRequestAverage":     floatField(func(a *opencost.Allocation) float64 { return a.RAMBytesRequestAverage }),
+		"ramBytesUsageAverage":       floatField(func(a *opencost.Allocation) float64 { return a.RAMBytesUsageAverage }),
+		"ramBytesLimitAverage":       floatField(func(a *opencost.Allocation) float64 { return a.RAMBytesLimitAverage }),
+		"ramCost":                    floatField(func(a *opencost.Allocation) float64 { return a.RAMCost }),
+		"ramCostAdjustment":          floatField(func(a *opencost.Allocation) float64 { return a.RAMCostAdjustment }),
+		"ramEfficiency":              floatField((*opencost.Allocation).RAMEfficiency),
+		"sharedCost":                 floatField(func(a *opencost.Allocation) float64 { return a.SharedCost }),
+		"externalCost":               floatField(func(a *opencost.Allocation) float64 { return a.ExternalCost }),
+		"totalEfficiency":            floatField((*opencost.Allocation).TotalEfficiency),
+		"totalCost":                  floatField((*opencost.Allocation).TotalCost),
+	},
+})
+
+var allocationSetType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "AllocationSet",
+	Fields: graphql.Fields{
+		"start": timeField(func(as *opencost.AllocationSet) any { return as.Window.Start() }),
+		"end":   timeField(func(as *opencost.AllocationSet) any { return as.Window.End() }),
+		"allocations": field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(allocationType))), func(as *opencost.AllocationSet) any {
+			return sortedValues(as.Allocations)
+		}),
+		"totalCost": floatField((*opencost.AllocationSet).TotalCost),
+	},
+})
+
+var assetPropertiesType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "AssetProperties",
+	Fields: graphql.Fields{
+		"category":   stringField(func(p *opencost.AssetProperties) string { return p.Category }),
+		"provider":   stringField(func(p *opencost.AssetProperties) string { return p.Provider }),
+		"account":    stringField(func(p *opencost.AssetProperties) string { return p.Account }),
+		"project":    stringField(func(p *opencost.AssetProperties) string { return p.Project }),
+		"service":    stringField(func(p *opencost.AssetProperties) string { return p.Service }),
+		"cluster":    stringField(func(p *opencost.AssetProperties) string { return p.Cluster }),
+		"name":       stringField(func(p *opencost.AssetProperties) string { return p.Name }),
+		"providerID": stringField(func(p *opencost.AssetProperties) string { return p.ProviderID }),
+	},
+})
+
+// keyedAsset pairs an Asset with its key in the AssetSet, which identifies the
+// asset after aggregation.
+type keyedAsset struct {
+	key   string
+	asset opencost.Asset
+}
+
+// Assets of every type share a single GraphQL type. Fields which do not apply
+// to the type of an asset resolve to null.
+var assetType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Asset",
+	Fields: graphql.Fields{
+		"key":  stringField(func(a keyedAsset) string { return a.key }),
+		"type": stringField(func(a keyedAsset) string { return a.asset.Type().String() }),
+		"properties": field(assetPropertiesType, func(a keyedAsset) any {
+			return a.asset.GetProperties()
+		}),
+		"labels":     labelsField(func(a keyedAsset) opencost.AssetLabels { return a.asset.GetLabels() }),
+		"start":      timeField(func(a keyedAsset) any { return a.asset.GetStart() }),
+		"end":        timeField(func(a keyedAsset) any { return a.asset.GetEnd() }),
+		"minutes":    floatField(func(a keyedAsset) float64 { return a.asset.Minutes() }),
+		"adjustment": floatField(func(a keyedAsset) float64 { return a.asset.GetAdjustment() }),
+		"totalCost":  floatField(func(a keyedAsset) float64 { return a.asset.TotalCost() }),
+
+		"nodeType":     nodeField(graphql.String, func(n *opencost.Node) any { return n.NodeType }),
+		"cpuCoreHours": nodeField(graphql.Float, func(n *opencost.Node) any { return n.CPUCoreHours }),
+		"ramByteHours": nodeField(graphql.Float, func(n *opencost.Node) any { return n.RAMByteHours }),
+		"gpuHours":     nodeField(graphql.Float, func(n *opencost.Node) any { return n.GPUHours }),
+		"gpuCount":     nodeField(graphql.Float, func(n *opencost.Node) any { return n.GPUCount }),
+		"cpuCost":      nodeField(graphql.Float, func(n *opencost.Node) any { return n.CPUCost }),
+		"gpuCost":      nodeField(graphql.Float, func(n *opencost.Node) any { return n.GPUCost }),
+		"ramCost":      nodeField(graphql.Float, func(n *opencost.Node) any { return n.RAMCost }),
+		"discount":     nodeField(graphql.Float, func(n *opencost.Node) any { return n.Discount }),
+		"preemptible":  nodeField(graphql.Float, func(n *opencost.Node) any { return n.Preemptible }),
+
+		"byteHours":      diskField(graphql.Float, func(d *opencost.Disk) any { return d.ByteHours }),
+		"storageClass":   diskField(graphql.String, func(d *opencost.Disk) any { return d.StorageClass }),
+		"volumeName":     diskField(graphql.String, func(d *opencost.Disk) any { return d.VolumeName }),
+		"claimName":      diskField(graphql.String, func(d *opencost.Disk) any { return d.ClaimName }),
+		"claimNamespace": diskField(graphql.String, func(d *opencost.Disk) any { return d.ClaimNamespace }),
+		"local":          diskField(graphql.Float, func(d *opencost.Disk) any { return d.Local }),
+
+		"private": field(graphql.Boolean, func(a keyedAsset) any {
+			if lb, ok := a.asset.(*opencost.LoadBalancer); ok {
+				return lb.Private
+			}
+			return nil
+		}),
+		"ip": field(graphql.String, func(a keyedAsset) any {
+			if lb, ok := a.asset.(*opencost.LoadBalancer); ok {
+				return lb.Ip
+			}
+			return nil
+		}),
+		"credit": field(graphql.Float, func(a keyedAsset) any {
+			if c, ok := a.asset.(*opencost.Cloud); ok {
+				return c.Credit
+			}
+			return nil
+		}),
+	},
+})
+
+func nodeField(typ graphql.Output, get func(*opencost.Node) any) *graphql.Field {
+	return field(typ, func(a keyedAsset) any {
+		if n, ok := a.asset.(*opencost.Node); ok {
+			return get(n)
+		}
+		return nil
+	})
+}
+
+func diskField(typ graphql.Output, get func(*opencost.Disk) any) *graphql.Field {
+	return field(typ, func(a keyedAsset) any {
+		if d, ok := a.asset.(*opencost.Disk); ok {
+			return get(d)
+		}
+		return nil
+	})
+}
+
+var assetSetType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "AssetSet",
+	Fields: graphql.Fields{
+		"start": timeField(func(as *opencost.AssetSet) any { return as.Window.Start() }),
+		"end":   timeField(func(as *opencost.AssetSet) any { return as.Window.End() }),
+		"assets": field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(assetType))), func(as *opencost.AssetSet) any {
+			assets := make([]keyedAsset, 0, len(as.Assets))
+			for key, asset := range as.Assets {
+				if asset != nil {
+					assets = append(assets, keyedAsset{key: key, asset: asset})
+				}
+			}
+			sort.Slice(assets, func(i, j int) bool { return assets[i].key < assets[j].key })
+			return assets
+		}),
+		"totalCost": floatField((*opencost.AssetSet).TotalCost),
+	},
+})
+
+var costMetricType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CostMetric",
+	Fields: graphql.Fields{
+		"cost":              floatField(func(cm opencost.CostMetric) float64 { return cm.Cost }),
+		"kubernetesPercent": floatField(func(cm opencost.CostMetric) float64 { return cm.KubernetesPercent }),
+	},
+})
+
+var cloudCostPropertiesType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CloudCostProperties",
+	Fields: graphql.Fields{
+		"providerID":        stringField(func(p *opencost.CloudCostProperties) string { return p.ProviderID }),
+		"provider":          stringField(func(p *opencost.CloudCostProperties) string { return p.Provider }),
+		"accountID":         stringField(func(p *opencost.CloudCostProperties) string { return p.AccountID }),
+		"accountName":       stringField(func(p *opencost.CloudCostProperties) string { return p.AccountName }),
+		"invoiceEntityID":   stringField(func(p *opencost.CloudCostProperties) string { return p.InvoiceEntityID }),
+		"invoiceEntityName": stringField(func(p *opencost.CloudCostProperties) string { return p.InvoiceEntityName }),
+		"regionID":          stringField(func(p *opencost.CloudCostProperties) string { return p.RegionID }),
+		"availabilityZone":  stringField(func(p *opencost.CloudCostProperties) string { return p.AvailabilityZone }),
+		"service":           stringField(func(p *opencost.CloudCostProperties) string { return p.Service }),
+		"category":          stringField(func(p *opencost.CloudCostProperties) string { return p.Category }),
+		"labels":            labelsField(func(p *opencost.CloudCostProperties) opencost.CloudCostLabels { return p.Labels }),
+	},
+})
+
+var cloudCostType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CloudCost",
+	Fields: graphql.Fields{
+		"properties": field(cloudCostPropertiesType, func(cc *opencost.CloudCost) any {
+			return cc.Properties
+		}),
+		"start":            timeField(func(cc *opencost.CloudCost) any { return cc.Window.Start() }),
+		"end":              timeField(func(cc *opencost.CloudCost) any { return cc.Window.End() }),
+		"listCost":         field(costMetricType, func(cc *opencost.CloudCost) any { return cc.ListCost }),
+		"netCost":          field(costMetricType, func(cc *opencost.CloudCost) any { return cc.NetCost }),
+		"amortizedNetCost": field(costMetricType, func(cc *opencost.CloudCost) any { return cc.AmortizedNetCost }),
+		"invoicedCost":     field(costMetricType, func(cc *opencost.CloudCost) any { return cc.InvoicedCost }),
+		"amortizedCost":    field(costMetricType, func(cc *opencost.CloudCost) any { return cc.AmortizedCost }),
+	},
+})
+
+var cloudCostSetType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CloudCostSet",
+	Fields: graphql.Fields{
+		"start": timeField(func(ccs *opencost.CloudCostSet) any { return ccs.Window.Start() }),
+		"end":   timeField(func(ccs *opencost.CloudCostSet) any { return ccs.Window.End() }),
+		"aggregationProperties": field(graphql.NewList(graphql.NewNonNull(graphql.String)), func(ccs *opencost.CloudCostSet) any {
+			return ccs.AggregationProperties
+		}),
+		"cloudCosts": field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(cloudCostType))), func(ccs *opencost.CloudCostSet) any {
+			return sortedValues(ccs.CloudCosts)
+		}),
+	},
+})
+
+var networkDetailType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "NetworkDetail",
+	Fields: graphql.Fields{
+		"cost":             floatField(func(nd *opencost.NetworkDetail) float64 { return nd.Cost }),
+		"bytes":            floatField(func(nd *opencost.NetworkDetail) float64 { return nd.Bytes }),
+		"endPoint":         stringField(func(nd *opencost.NetworkDetail) string { return nd.EndPoint }),
+		"trafficDirection": stringField(func(nd *opencost.NetworkDetail) string { return string(nd.TrafficDirection) }),
+		"trafficType":      stringField(func(nd *opencost.NetworkDetail) string { return string(nd.TrafficType) }),
+	},
+})
+
+var networkInsightType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "NetworkInsight",
+	Fields: graphql.Fields{
+		"cluster":                stringField(func(ni *opencost.NetworkInsight) string { return ni.Cluster }),
+		"namespace":              stringField(func(ni *opencost.NetworkInsight) string { return ni.Namespace }),
+		"controller":             stringField(func(ni *opencost.NetworkInsight) string { return ni.Controller }),
+		"pod":                    stringField(func(ni *opencost.NetworkInsight) string { return ni.Pod }),
+		"node":                   stringField(func(ni *opencost.NetworkInsight) string { return ni.Node }),
+		"region":                 stringField(func(ni *opencost.NetworkInsight) string { return ni.Region }),
+		"zone":                   stringField(func(ni *opencost.NetworkInsight) string { return ni.Zone }),
+		"labels":                 labelsField(func(ni *opencost.NetworkInsight) map[string]string { return ni.Labels }),
+		"networkCost":            floatField(func(ni *opencost.NetworkInsight) float64 { return ni.NetworkTotalCost }),
+		"networkCrossZoneCost":   floatField(func(ni *opencost.NetworkInsight) float64 { return ni.NetworkCrossZoneCost }),
+		"networkCrossRegionCost": floatField(func(ni *opencost.NetworkInsight) float64 { return ni.NetworkCrossRegionCost }),
+		"networkInternetCost":    floatField(func(ni *opencost.NetworkInsight) float64 { return ni.NetworkInternetCost }),
+		"egressBytes":            floatField((*opencost.NetworkInsight).GetTotalEgressByte),
+		"ingressBytes":           floatField((*opencost.NetworkInsight).GetTotalIngressByte),
+		"networkDetails": field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(networkDetailType))), func(ni *opencost.NetworkInsight) any {
+			return sortedValues(ni.NetworkDetails)
+		}),
+	},
+})
+
+var networkInsightSetType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "NetworkInsightSet",
+	Fields: graphql.Fields{
+		"start": timeField(func(nis *opencost.NetworkInsightSet) any { return nis.Window.Start() }),
+		"end":   timeField(func(nis *opencost.NetworkInsightSet) any { return nis.Window.End() }),
+		"networkInsights": field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(networkInsightType))), func(nis *opencost.NetworkInsightSet) any {
+			return sortedValues(nis.NetworkInsights)
+		}),
+	},
+})
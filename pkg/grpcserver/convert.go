@@ -0,0 +1,298 @@
+package grpcserver
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/opencost/opencost/core/pkg/model/pb/query"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/pkg/customcost"
+)
+
+// timestamp converts a window boundary, which may be open, to a protobuf
+// timestamp.
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func allocationSetToProto(as *opencost.AllocationSet) *query.AllocationSet {
+	if as == nil {
+		return &query.AllocationSet{}
+	}
+
+	set := &query.AllocationSet{
+		Start:       timestamp(as.Window.Start()),
+		End:         timestamp(as.Window.End()),
+		Allocations: make([]*query.Allocation, 0, len(as.Allocations)),
+	}
+	for _, alloc := range as.Allocations {
+		if alloc == nil {
+			continue
+		}
+		set.Allocations = append(set.Allocations, allocationToProto(alloc))
+	}
+	return set
+}
+
+func allocationToProto(alloc *opencost.Allocation) *query.Allocation {
+	pb := &query.Allocation{
+		Name:                       alloc.Name,
+		Start:                      timestamppb.New(alloc.Start),
+		End:                        timestamppb.New(alloc.End),
+		CpuCoreHours:               alloc.CPUCoreHours,
+		CpuCoreRequestAverage:      alloc.CPUCoreRequestAverage,
+		CpuCoreUsageAverage:        alloc.CPUCoreUsageAverage,
+		CpuCost:                    alloc.CPUCost,
+		CpuCostAdjustment:          alloc.CPUCostAdjustment,
+		GpuHours:                   alloc.GPUHours,
+		GpuCost:                    alloc.GPUCost,
+		GpuCostAdjustment:          alloc.GPUCostAdjustment,
+		NetworkTransferBytes:       alloc.NetworkTransferBytes,
+		NetworkReceiveBytes:        alloc.NetworkReceiveBytes,
+		NetworkCost:                alloc.NetworkCost,
+		NetworkCostAdjustment:      alloc.NetworkCostAdjustment,
+		LoadBalancerCost:           alloc.LoadBalancerCost,
+		LoadBalancerCostAdjustment: alloc.LoadBalancerCostAdjustment,
+		PvByteHours:                alloc.PVByteHours(),
+		PvCost:                     alloc.PVCost(),
+		PvCostAdjustment:           alloc.PVCostAdjustment,
+		RamByteHours:               alloc.RAMByteHours,
+		RamBytesRequestAverage:     alloc.RAMBytesRequestAverage,
+		RamBytesUsageAverage:       alloc.RAMBytesUsageAverage,
+		RamCost:                    alloc.RAMCost,
+		RamCostAdjustment:          alloc.RAMCostAdjustment,
+		SharedCost:                 alloc.SharedCost,
+		ExternalCost:               alloc.ExternalCost,
+		TotalCost:                  alloc.TotalCost(),
+	}
+
+	if props := alloc.Properties; props != nil {
+		pb.Properties = &query.AllocationProperties{
+			Cluster:              props.Cluster,
+			Node:                 props.Node,
+			Container:            props.Container,
+			Controller:           props.Controller,
+			ControllerKind:       props.ControllerKind,
+			Namespace:            props.Namespace,
+			Pod:                  props.Pod,
+			Services:             props.Services,
+			ProviderId:           props.ProviderID,
+			Labels:               props.Labels,
+			Annotations:          props.Annotations,
+			NamespaceLabels:      props.NamespaceLabels,
+			NamespaceAnnotations: props.NamespaceAnnotations,
+		}
+	}
+
+	if len(alloc.SharedCostBreakdown) > 0 {
+		pb.SharedCostBreakdown = make(map[string]*query.SharedCostBreakdown, len(alloc.SharedCostBreakdown))
+		for name, scb := range alloc.SharedCostBreakdown {
+			pb.SharedCostBreakdown[name] = &query.SharedCostBreakdown{
+				Name:             scb.Name,
+				TotalCost:        scb.TotalCost,
+				CpuCost:          scb.CPUCost,
+				GpuCost:          scb.GPUCost,
+				RamCost:          scb.RAMCost,
+				PvCost:           scb.PVCost,
+				NetworkCost:      scb.NetworkCost,
+				LoadBalancerCost: scb.LBCost,
+				ExternalCost:     scb.ExternalCost,
+			}
+		}
+	}
+
+	return pb
+}
+
+func assetSetToProto(as *opencost.AssetSet) *query.AssetSet {
+	if as == nil {
+		return &query.AssetSet{}
+	}
+
+	set := &query.AssetSet{
+		Start:  timestamp(as.Window.Start()),
+		End:    timestamp(as.Window.End()),
+		Assets: make([]*query.Asset, 0, len(as.Assets)),
+	}
+	for _, asset := range as.Assets {
+		if asset == nil {
+			continue
+		}
+		set.Assets = append(set.Assets, assetToProto(asset))
+	}
+	return set
+}
+
+func assetToProto(asset opencost.Asset) *query.Asset {
+	pb := &query.Asset{
+		Type:       asset.Type().String(),
+		Labels:     asset.GetLabels(),
+		Start:      timestamppb.New(asset.GetStart()),
+		End:        timestamppb.New(asset.GetEnd()),
+		Minutes:    asset.Minutes(),
+		Adjustment: asset.GetAdjustment(),
+		TotalCost:  asset.TotalCost(),
+	}
+
+	if props := asset.GetProperties(); props != nil {
+		pb.Properties = &query.AssetProperties{
+			Category:   props.Category,
+			Provider:   props.Provider,
+			Account:    props.Account,
+			Project:    props.Project,
+			Service:    props.Service,
+			Cluster:    props.Cluster,
+			Name:       props.Name,
+			ProviderId: props.ProviderID,
+		}
+	}
+
+	switch a := asset.(type) {
+	case *opencost.Node:
+		pb.NodeType = a.NodeType
+		pb.CpuCoreHours = a.CPUCoreHours
+		pb.RamByteHours = a.RAMByteHours
+		pb.GpuHours = a.GPUHours
+		pb.GpuCount = a.GPUCount
+		pb.CpuCost = a.CPUCost
+		pb.GpuCost = a.GPUCost
+		pb.RamCost = a.RAMCost
+		pb.Discount = a.Discount
+		pb.Preemptible = a.Preemptible
+	case *opencost.Disk:
+		pb.ByteHours = a.ByteHours
+		pb.StorageClass = a.StorageClass
+		pb.VolumeName = a.VolumeName
+		pb.ClaimName = a.ClaimName
+		pb.ClaimNamespace = a.ClaimNamespace
+		pb.Local = a.Local
+	case *opencost.LoadBalancer:
+		pb.Private = a.Private
+		pb.Ip = a.Ip
+	case *opencost.Cloud:
+		pb.Credit = a.Credit
+	}
+
+	return pb
+}
+
+func cloudCostSetRangeToProto(ccsr *opencost.CloudCostSetRange) *query.CloudCostSetRange {
+	resp := &query.CloudCostSetRange{}
+	if ccsr == nil {
+		return resp
+	}
+
+	for _, ccs := range ccsr.CloudCostSets {
+		if ccs == nil {
+			continue
+		}
+
+		set := &query.CloudCostSet{
+			Start:                 timestamp(ccs.Window.Start()),
+			End:                   timestamp(ccs.Window.End()),
+			AggregationProperties: ccs.AggregationProperties,
+			CloudCosts:            make([]*query.CloudCost, 0, len(ccs.CloudCosts)),
+		}
+		for _, cc := range ccs.CloudCosts {
+			if cc == nil {
+				continue
+			}
+			set.CloudCosts = append(set.CloudCosts, cloudCostToProto(cc))
+		}
+		resp.Sets = append(resp.Sets, set)
+	}
+	return resp
+}
+
+func cloudCostToProto(cc *opencost.CloudCost) *query.CloudCost {
+	pb := &query.CloudCost{
+		Start:            timestamp(cc.Window.Start()),
+		End:              timestamp(cc.Window.End()),
+		ListCost:         costMetricToProto(cc.ListCost),
+		NetCost:          costMetricToProto(cc.NetCost),
+		AmortizedNetCost: costMetricToProto(cc.AmortizedNetCost),
+		InvoicedCost:     costMetricToProto(cc.InvoicedCost),
+		AmortizedCost:    costMetricToProto(cc.AmortizedCost),
+	}
+
+	if props := cc.Properties; props != nil {
+		pb.Properties = &query.CloudCostProperties{
+			ProviderId:        props.ProviderID,
+			Provider:          props.Provider,
+			AccountId:         props.AccountID,
+			AccountName:       props.AccountName,
+			InvoiceEntityId:   props.InvoiceEntityID,
+			InvoiceEntityName: props.InvoiceEntityName,
+			RegionId:          props.RegionID,
+			AvailabilityZone:  props.AvailabilityZone,
+			Service:           props.Service,
+			Category:          props.Category,
+			Labels:            props.Labels,
+		}
+	}
+
+	return pb
+}
+
+func costMetricToProto(cm opencost.CostMetric) *query.CostMetric {
+	return &query.CostMetric{
+		Cost:              cm.Cost,
+		KubernetesPercent: cm.KubernetesPercent,
+	}
+}
+
+func customCostTimeseriesToProto(resp *customcost.CostTimeseriesResponse) *query.CustomCostTimeseries {
+	if resp == nil {
+		return &query.CustomCostTimeseries{}
+	}
+
+	ts := &query.CustomCostTimeseries{
+		Start:      timestamp(resp.Window.Start()),
+		End:        timestamp(resp.Window.End()),
+		Timeseries: make([]*query.CustomCostSet, 0, len(resp.Timeseries)),
+	}
+	for _, cr := range resp.Timeseries {
+		if cr == nil {
+			continue
+		}
+
+		set := &query.CustomCostSet{
+			Start:         timestamp(cr.Window.Start()),
+			End:           timestamp(cr.Window.End()),
+			TotalCost:     float64(cr.TotalCost),
+			TotalCostType: string(cr.TotalCostType),
+			CustomCosts:   make([]*query.CustomCost, 0, len(cr.CustomCosts)),
+		}
+		for _, cc := range cr.CustomCosts {
+			if cc == nil {
+				continue
+			}
+			set.CustomCosts = append(set.CustomCosts, &query.CustomCost{
+				Id:                 cc.Id,
+				Zone:               cc.Zone,
+				AccountName:        cc.AccountName,
+				ChargeCategory:     cc.ChargeCategory,
+				Description:        cc.Description,
+				ResourceName:       cc.ResourceName,
+				ResourceType:       cc.ResourceType,
+				ProviderId:         cc.ProviderId,
+				Cost:               float64(cc.Cost),
+				ListUnitPrice:      float64(cc.ListUnitPrice),
+				UsageQuantity:      float64(cc.UsageQuantity),
+				UsageUnit:          cc.UsageUnit,
+				Domain:             cc.Domain,
+				CostSource:         cc.CostSource,
+				Aggregate:          cc.Aggregate,
+				CostType:           string(cc.CostType),
+				Labels:             cc.Labels,
+				ExtendedAttributes: cc.ExtendedAttributes,
+			})
+		}
+		ts.Timeseries = append(ts.Timeseries, set)
+	}
+	return ts
+}
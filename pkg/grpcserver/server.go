@@ -0,0 +1,220 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/opencost/opencost/core/pkg/model/pb/query"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/core/pkg/util/httputil"
+	"github.com/opencost/opencost/pkg/cloudcost"
+	"github.com/opencost/opencost/pkg/costmodel"
+	"github.com/opencost/opencost/pkg/customcost"
+	"github.com/opencost/opencost/pkg/env"
+)
+
+// Server implements the gRPC QueryService on top of the same queriers which
+// back the HTTP APIs. Any of its dependencies may be nil, in which case the
+// corresponding RPCs return an Unavailable status.
+type Server struct {
+	query.UnimplementedQueryServiceServer
+
+	accesses          *costmodel.Accesses
+	cloudCostQuerier  cloudcost.Querier
+	customCostQuerier customcost.Querier
+}
+
+// NewServer creates a new Server.
+func NewServer(accesses *costmodel.Accesses, cloudCostQuerier cloudcost.Querier, customCostQuerier customcost.Querier) *Server {
+	return &Server{
+		accesses:          accesses,
+		cloudCostQuerier:  cloudCostQuerier,
+		customCostQuerier: customCostQuerier,
+	}
+}
+
+// Register registers the QueryService with the given gRPC server.
+func (s *Server) Register(gs *grpc.Server) {
+	query.RegisterQueryServiceServer(gs, s)
+}
+
+// QueryAllocations computes allocations step by step, sending each
+// AllocationSet to the client as soon as it is computed.
+func (s *Server) QueryAllocations(req *query.AllocationRequest, stream query.QueryService_QueryAllocationsServer) error {
+	if s.accesses == nil || s.accesses.Model == nil {
+		return status.Error(codes.Unavailable, "allocation queries require Kubernetes to be enabled")
+	}
+
+	window, opts, err := costmodel.ParseAllocationQuery(httputil.NewQueryParams(allocationQueryValues(req)))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx, cancel := costmodel.QueryContext(stream.Context())
+	defer cancel()
+
+	queryAllocation := func(w opencost.Window) error {
+		asr, err := s.accesses.Model.QueryAllocation(ctx, w, opts)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return status.FromContextError(ctxErr).Err()
+			}
+			if strings.Contains(strings.ToLower(err.Error()), "bad request") {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		for _, as := range asr.Allocations {
+			if err := stream.Send(allocationSetToProto(as)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, step := range costmodel.AllocationSteps(window, opts) {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := queryAllocation(step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allocationQueryValues converts an AllocationRequest into the parameters of
+// the HTTP API, so that both are parsed the same way.
+func allocationQueryValues(req *query.AllocationRequest) url.Values {
+	values := url.Values{}
+	setString := func(name, value string) {
+		if value != "" {
+			values.Set(name, value)
+		}
+	}
+	setBool := func(name string, value bool) {
+		if value {
+			values.Set(name, strconv.FormatBool(value))
+		}
+	}
+
+	setString("window", req.GetWindow())
+	setString("step", req.GetStep())
+	setString("aggregate", strings.Join(req.GetAggregate(), ","))
+	setString("filter", req.GetFilter())
+	setBool("accumulate", req.GetAccumulate())
+	setString("accumulateBy", req.GetAccumulateBy())
+	setBool("includeIdle", req.GetIncludeIdle())
+	setBool("idleByNode", req.GetIdleByNode())
+	setBool("shareIdle", req.GetShareIdle())
+	setBool("sharelb", req.GetShareLb())
+	setBool("includeProportionalAssetResourceCosts", req.GetIncludeProportionalAssetResourceCosts())
+	setBool("includeAggregatedMetadata", req.GetIncludeAggregatedMetadata())
+	setBool("includeCustomCosts", req.GetIncludeCustomCosts())
+	setBool("includeSharedCostBreakdown", req.GetIncludeSharedCostBreakdown())
+	setString("shareNamespaces", strings.Join(req.GetShareNamespaces(), ","))
+	setString("shareLabels", strings.Join(req.GetShareLabels(), ","))
+	setString("shareSplit", req.GetShareSplit())
+	setBool("shareTenancyCosts", req.GetShareTenancyCosts())
+	return values
+}
+
+// QueryAssets computes the assets of the requested window.
+func (s *Server) QueryAssets(ctx context.Context, req *query.AssetRequest) (*query.AssetSet, error) {
+	if s.accesses == nil || s.accesses.Model == nil {
+		return nil, status.Error(codes.Unavailable, "asset queries require Kubernetes to be enabled")
+	}
+
+	window, err := opencost.ParseWindowWithOffset(req.GetWindow(), env.GetParsedUTCOffset())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid window '%s': %s", req.GetWindow(), err)
+	}
+	if window.IsOpen() {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid window '%s': window must be closed", req.GetWindow())
+	}
+
+	ctx, cancel := costmodel.QueryContext(ctx)
+	defer cancel()
+
+	assetSet, err := s.accesses.ComputeAssetsFromCostmodel(ctx, window, req.GetFilter())
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, status.FromContextError(ctxErr).Err()
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return assetSetToProto(assetSet), nil
+}
+
+// QueryCloudCosts queries the cloud costs of the requested window.
+func (s *Server) QueryCloudCosts(ctx context.Context, req *query.CloudCostRequest) (*query.CloudCostSetRange, error) {
+	if s.cloudCostQuerier == nil {
+		return nil, status.Error(codes.Unavailable, "cloud cost queries require cloud costs to be enabled")
+	}
+
+	request, err := cloudcost.ParseCloudCostRequest(queryParams(map[string]string{
+		"window":     req.GetWindow(),
+		"aggregate":  strings.Join(req.GetAggregate(), ","),
+		"accumulate": req.GetAccumulate(),
+		"filter":     req.GetFilter(),
+	}))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ccsr, err := s.cloudCostQuerier.Query(ctx, *request)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("querying cloud costs: %s", err))
+	}
+
+	return cloudCostSetRangeToProto(ccsr), nil
+}
+
+// QueryCustomCosts queries the custom costs of the requested window.
+func (s *Server) QueryCustomCosts(ctx context.Context, req *query.CustomCostRequest) (*query.CustomCostTimeseries, error) {
+	if s.customCostQuerier == nil {
+		return nil, status.Error(codes.Unavailable, "custom cost queries require custom costs to be enabled")
+	}
+
+	request, err := customcost.ParseCustomCostTimeseriesRequest(queryParams(map[string]string{
+		"window":        req.GetWindow(),
+		"aggregate":     strings.Join(req.GetAggregate(), ","),
+		"accumulate":    req.GetAccumulate(),
+		"filter":        req.GetFilter(),
+		"costType":      req.GetCostType(),
+		"sortBy":        req.GetSortBy(),
+		"sortDirection": req.GetSortDirection(),
+	}))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp, err := s.customCostQuerier.QueryTimeseries(ctx, *request)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("querying custom costs: %s", err))
+	}
+
+	return customCostTimeseriesToProto(resp), nil
+}
+
+// queryParams adapts request fields to the query parameters parsed by the HTTP
+// APIs, so that both APIs share the same defaults and validation. Empty values
+// are omitted so that defaults apply.
+func queryParams(params map[string]string) httputil.QueryParams {
+	values := url.Values{}
+	for key, value := range params {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	return httputil.NewQueryParams(values)
+}
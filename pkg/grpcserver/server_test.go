@@ -0,0 +1,157 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/opencost/opencost/core/pkg/model/pb/query"
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/pkg/cloudcost"
+	"github.com/opencost/opencost/pkg/costmodel"
+)
+
+type fakeCloudCostQuerier struct {
+	request cloudcost.QueryRequest
+	ccsr    *opencost.CloudCostSetRange
+}
+
+func (f *fakeCloudCostQuerier) Query(_ context.Context, request cloudcost.QueryRequest) (*opencost.CloudCostSetRange, error) {
+	f.request = request
+	return f.ccsr, nil
+}
+
+func newTestClient(t *testing.T, server *Server) query.QueryServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	server.Register(gs)
+	go gs.Serve(listener)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return query.NewQueryServiceClient(conn)
+}
+
+func TestQueryCloudCosts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	ccs := opencost.NewCloudCostSet(start, end)
+	ccs.AggregationProperties = []string{opencost.CloudCostServiceProp}
+	ccs.Insert(opencost.NewCloudCost(start, end, &opencost.CloudCostProperties{
+		Provider: "AWS",
+		Service:  "AmazonEC2",
+		Labels:   opencost.CloudCostLabels{"team": "platform"},
+	}, 0.5, 10, 8, 8, 8, 10))
+
+	querier := &fakeCloudCostQuerier{
+		ccsr: &opencost.CloudCostSetRange{CloudCostSets: []*opencost.CloudCostSet{ccs}},
+	}
+	client := newTestClient(t, NewServer(nil, querier, nil))
+
+	resp, err := client.QueryCloudCosts(context.Background(), &query.CloudCostRequest{
+		Window:    "2024-01-01T00:00:00Z,2024-01-02T00:00:00Z",
+		Aggregate: []string{"service"},
+		Filter:    `provider:"AWS"`,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, start, querier.request.Start)
+	assert.Equal(t, end, querier.request.End)
+	assert.Equal(t, []string{opencost.CloudCostServiceProp}, querier.request.AggregateBy)
+	assert.NotNil(t, querier.request.Filter)
+
+	require.Len(t, resp.GetSets(), 1)
+	set := resp.GetSets()[0]
+	assert.Equal(t, start, set.GetStart().AsTime())
+	assert.Equal(t, []string{opencost.CloudCostServiceProp}, set.GetAggregationProperties())
+	require.Len(t, set.GetCloudCosts(), 1)
+	cc := set.GetCloudCosts()[0]
+	assert.Equal(t, "AmazonEC2", cc.GetProperties().GetService())
+	assert.Equal(t, map[string]string{"team": "platform"}, cc.GetProperties().GetLabels())
+	assert.Equal(t, 10.0, cc.GetListCost().GetCost())
+	assert.Equal(t, 0.5, cc.GetNetCost().GetKubernetesPercent())
+}
+
+func TestQueryErrors(t *testing.T) {
+	client := newTestClient(t, NewServer(nil, &fakeCloudCostQuerier{}, nil))
+	ctx := context.Background()
+
+	stream, err := client.QueryAllocations(ctx, &query.AllocationRequest{Window: "1d"})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = client.QueryAssets(ctx, &query.AssetRequest{Window: "1d"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = client.QueryCustomCosts(ctx, &query.CustomCostRequest{Window: "1d"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	_, err = client.QueryCloudCosts(ctx, &query.CloudCostRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.QueryCloudCosts(ctx, &query.CloudCostRequest{Window: "1d", Aggregate: []string{"invalid"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestQueryAllocationsInvalidArgument(t *testing.T) {
+	// Invalid requests are rejected before the CostModel is queried
+	client := newTestClient(t, NewServer(&costmodel.Accesses{Model: &costmodel.CostModel{}}, nil, nil))
+
+	requests := map[string]*query.AllocationRequest{
+		"step":         {Window: "1d", Step: "-1h"},
+		"share labels": {Window: "1d", ShareLabels: []string{"team"}},
+		"share split":  {Window: "1d", ShareNamespaces: []string{"kube-system"}, ShareSplit: "random"},
+	}
+	for name, req := range requests {
+		t.Run(name, func(t *testing.T) {
+			stream, err := client.QueryAllocations(context.Background(), req)
+			require.NoError(t, err)
+			_, err = stream.Recv()
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestAllocationToProtoSharedCostBreakdown(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	alloc := &opencost.Allocation{
+		Name:       "app",
+		Start:      start,
+		End:        start.Add(time.Hour),
+		SharedCost: 3,
+		SharedCostBreakdown: opencost.SharedCostBreakdowns{
+			"monitoring": {Name: "monitoring", TotalCost: 3, CPUCost: 2, RAMCost: 1},
+		},
+	}
+
+	pb := allocationToProto(alloc)
+	require.Len(t, pb.GetSharedCostBreakdown(), 1)
+	scb := pb.GetSharedCostBreakdown()["monitoring"]
+	assert.Equal(t, "monitoring", scb.GetName())
+	assert.Equal(t, 3.0, scb.GetTotalCost())
+	assert.Equal(t, 2.0, scb.GetCpuCost())
+	assert.Equal(t, 1.0, scb.GetRamCost())
+
+	assert.Empty(t, allocationToProto(&opencost.Allocation{Name: "app"}).GetSharedCostBreakdown())
+}
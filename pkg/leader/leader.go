@@ -0,0 +1,124 @@
+package leader
+
+import (
+	"context"
+	"sync"
+
+	"github.com/opencost/opencost/core/pkg/log"
+)
+
+// Elector gates the subsystems which must only run on a single replica, such as the cloud cost ingestors and the
+// exporters, so that when several replicas run they only run on the leader, while every replica serves the read APIs.
+type Elector interface {
+	// Register adds a subsystem which is run each time this replica becomes the leader. The context passed to run is
+	// cancelled when the replica stops leading, and run must stop the subsystem and return once it is. If the replica
+	// is already the leader, the subsystem is run immediately.
+	Register(name string, run func(ctx context.Context))
+
+	// IsLeader returns true if this replica is currently the leader.
+	IsLeader() bool
+
+	// Stop stops the running subsystems, and waits for them to return. It is called when the replica shuts down.
+	Stop()
+}
+
+// subsystem is a subsystem registered with an Elector
+type subsystem struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// subsystems runs the registered subsystems while this replica leads. It is shared by the Elector implementations.
+type subsystems struct {
+	lock       sync.Mutex
+	subsystems []subsystem
+	leading    bool
+	cancel     context.CancelFunc
+	ctx        context.Context
+	wg         sync.WaitGroup
+}
+
+func (s *subsystems) Register(name string, run func(ctx context.Context)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sub := subsystem{name: name, run: run}
+	s.subsystems = append(s.subsystems, sub)
+	if s.leading {
+		s.start(sub)
+	}
+}
+
+func (s *subsystems) IsLeader() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.leading
+}
+
+func (s *subsystems) Stop() {
+	s.stopLeading()
+}
+
+// startLeading runs each registered subsystem until the context of the term is cancelled, or stopLeading is called.
+// Nothing is started if the term has already ended.
+func (s *subsystems) startLeading(term context.Context) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.leading || term.Err() != nil {
+		return
+	}
+	s.leading = true
+	s.ctx, s.cancel = context.WithCancel(term)
+	for _, sub := range s.subsystems {
+		s.start(sub)
+	}
+}
+
+// start runs the subsystem with the context of the current term. The caller must hold the lock.
+func (s *subsystems) start(sub subsystem) {
+	log.Infof("Leader: starting %s", sub.name)
+	s.wg.Add(1)
+	go func(ctx context.Context) {
+		defer s.wg.Done()
+		sub.run(ctx)
+		log.Infof("Leader: stopped %s", sub.name)
+	}(s.ctx)
+}
+
+// stopLeading cancels the context of the running subsystems, and waits for them to stop
+func (s *subsystems) stopLeading() {
+	s.lock.Lock()
+	if !s.leading {
+		s.lock.Unlock()
+		return
+	}
+	s.leading = false
+	s.cancel()
+	s.lock.Unlock()
+
+	s.wg.Wait()
+}
+
+// standalone is an Elector for a single replica, which is always the leader
+type standalone struct {
+	subsystems
+}
+
+// NewStandalone returns an Elector which always leads, so registered subsystems run immediately. It is used when
+// leader election is disabled.
+func NewStandalone() Elector {
+	s := &standalone{}
+	s.startLeading(context.Background())
+	return s
+}
+
+// RunWhile returns a subsystem run function for a subsystem with separate start and stop functions, which starts
+// it and then stops it once the context is cancelled.
+func RunWhile(start func(), stop func()) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		start()
+		<-ctx.Done()
+		stop()
+	}
+}
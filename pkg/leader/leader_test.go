@@ -0,0 +1,175 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// counter counts the runs of a subsystem, and how many are running
+type counter struct {
+	runs    atomic.Int32
+	running atomic.Int32
+}
+
+func (c *counter) run(ctx context.Context) {
+	c.runs.Add(1)
+	c.running.Add(1)
+	<-ctx.Done()
+	c.running.Add(-1)
+}
+
+func waitFor(t *testing.T, desc string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", desc)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStandalone(t *testing.T) {
+	elector := NewStandalone()
+	if !elector.IsLeader() {
+		t.Fatalf("IsLeader() = false, want true")
+	}
+
+	c := &counter{}
+	elector.Register("test", c.run)
+	waitFor(t, "subsystem to run", func() bool { return c.running.Load() == 1 })
+}
+
+func TestStandalone_Stop(t *testing.T) {
+	elector := NewStandalone()
+	c := &counter{}
+	elector.Register("test", c.run)
+	waitFor(t, "subsystem to run", func() bool { return c.running.Load() == 1 })
+
+	elector.Stop()
+	if elector.IsLeader() || c.running.Load() != 0 {
+		t.Errorf("subsystem still runs after Stop()")
+	}
+}
+
+func TestSubsystems_TermEnded(t *testing.T) {
+	s := &subsystems{}
+	c := &counter{}
+	s.Register("test", c.run)
+
+	// the subsystems of a term stop when it ends, without stopLeading
+	term, end := context.WithCancel(context.Background())
+	s.startLeading(term)
+	waitFor(t, "subsystem to run", func() bool { return c.running.Load() == 1 })
+	end()
+	waitFor(t, "subsystem to stop", func() bool { return c.running.Load() == 0 })
+	s.stopLeading()
+
+	// a term which ended before it started runs nothing
+	s.startLeading(term)
+	if s.IsLeader() || c.runs.Load() != 1 {
+		t.Errorf("subsystems started for a term which had ended")
+	}
+}
+
+func TestRunWhile(t *testing.T) {
+	var started, stopped atomic.Bool
+	run := RunWhile(func() { started.Store(true) }, func() { stopped.Store(true) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		run(ctx)
+		close(done)
+	}()
+
+	waitFor(t, "start", started.Load)
+	if stopped.Load() {
+		t.Fatalf("stopped before the context was cancelled")
+	}
+	cancel()
+	<-done
+	if !stopped.Load() {
+		t.Errorf("not stopped after the context was cancelled")
+	}
+}
+
+func newTestLeaseElector(t *testing.T, client *fake.Clientset, identity string) *LeaseElector {
+	t.Helper()
+	elector, err := NewLeaseElector(client, LeaseConfig{
+		Namespace:     "opencost",
+		Name:          "opencost-leader",
+		Identity:      identity,
+		LeaseDuration: time.Second,
+		RenewDeadline: 500 * time.Millisecond,
+		RetryPeriod:   100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewLeaseElector() error = %v", err)
+	}
+	return elector
+}
+
+func TestLeaseElector(t *testing.T) {
+	client := fake.NewClientset()
+
+	elector1 := newTestLeaseElector(t, client, "replica-1")
+	counter1 := &counter{}
+	elector1.Register("test", counter1.run)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	done1 := make(chan struct{})
+	go func() {
+		elector1.Run(ctx1)
+		close(done1)
+	}()
+	waitFor(t, "replica-1 to lead", elector1.IsLeader)
+	waitFor(t, "subsystem of replica-1 to run", func() bool { return counter1.running.Load() == 1 })
+
+	elector2 := newTestLeaseElector(t, client, "replica-2")
+	counter2 := &counter{}
+	elector2.Register("test", counter2.run)
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	go elector2.Run(ctx2)
+
+	// replica-2 follows while replica-1 holds the lease
+	time.Sleep(500 * time.Millisecond)
+	if elector2.IsLeader() || counter2.runs.Load() != 0 {
+		t.Fatalf("replica-2 leads while replica-1 holds the lease")
+	}
+
+	// replica-1 releases the lease when it stops, and replica-2 takes over
+	cancel1()
+	<-done1
+	if elector1.IsLeader() || counter1.running.Load() != 0 {
+		t.Errorf("replica-1 still leads after stopping")
+	}
+	waitFor(t, "replica-2 to lead", elector2.IsLeader)
+	waitFor(t, "subsystem of replica-2 to run", func() bool { return counter2.running.Load() == 1 })
+}
+
+func TestNewLeaseElector_InvalidConfig(t *testing.T) {
+	client := fake.NewClientset()
+
+	if _, err := NewLeaseElector(client, LeaseConfig{Namespace: "opencost", Name: "opencost-leader"}); err == nil {
+		t.Errorf("NewLeaseElector() without an identity succeeded, want error")
+	}
+
+	_, err := NewLeaseElector(client, LeaseConfig{
+		Namespace:     "opencost",
+		Name:          "opencost-leader",
+		Identity:      "replica-1",
+		LeaseDuration: time.Second,
+		RenewDeadline: 2 * time.Second,
+		RetryPeriod:   100 * time.Millisecond,
+	})
+	if err == nil {
+		t.Errorf("NewLeaseElector() with a renew deadline longer than the lease duration succeeded, want error")
+	}
+}
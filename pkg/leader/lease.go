@@ -0,0 +1,100 @@
+package leader
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaseConfig configures the Lease used to elect the leader of the replicas
+type LeaseConfig struct {
+	// Namespace and Name identify the Lease, which is shared by every replica
+	Namespace string
+	Name      string
+	// Identity is the unique identity of this replica, usually its pod name
+	Identity string
+	// LeaseDuration is how long followers wait before taking over a lease which is not renewed
+	LeaseDuration time.Duration
+	// RenewDeadline is how long the leader retries renewing the lease before it stops leading
+	RenewDeadline time.Duration
+	// RetryPeriod is the interval between attempts to acquire or renew the lease
+	RetryPeriod time.Duration
+}
+
+// LeaseElector is an Elector which elects the leader of the replicas with a Kubernetes Lease
+type LeaseElector struct {
+	subsystems
+	config leaderelection.LeaderElectionConfig
+}
+
+// NewLeaseElector creates a LeaseElector for the Lease of the config. The replica does not campaign to lead until
+// Run is called.
+func NewLeaseElector(client kubernetes.Interface, config LeaseConfig) (*LeaseElector, error) {
+	if config.Identity == "" {
+		return nil, fmt.Errorf("leader election requires an identity for the replica")
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: config.Namespace,
+			Name:      config.Name,
+		},
+		Client: client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: config.Identity,
+		},
+	}
+
+	le := &LeaseElector{}
+	le.config = leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   config.LeaseDuration,
+		RenewDeadline:   config.RenewDeadline,
+		RetryPeriod:     config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.Name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Infof("Leader: %s is now the leader of lease %s/%s", config.Identity, config.Namespace, config.Name)
+				// the subsystems run with the context of the term, which is cancelled as soon as the lease is lost,
+				// as this callback may run after OnStoppedLeading
+				le.startLeading(ctx)
+			},
+			OnStoppedLeading: func() {
+				log.Infof("Leader: %s stopped leading lease %s/%s", config.Identity, config.Namespace, config.Name)
+				le.stopLeading()
+			},
+			OnNewLeader: func(identity string) {
+				if identity != config.Identity {
+					log.Infof("Leader: %s is the leader of lease %s/%s", identity, config.Namespace, config.Name)
+				}
+			},
+		},
+	}
+
+	// validate the timings up front, rather than when the replica starts campaigning
+	if _, err := leaderelection.NewLeaderElector(le.config); err != nil {
+		return nil, fmt.Errorf("invalid leader election config: %w", err)
+	}
+	return le, nil
+}
+
+// Run campaigns to lead until the context is cancelled. A replica which stops leading, because it failed to renew
+// the lease, campaigns again once its subsystems have stopped. The lease is released when the context is cancelled,
+// so that another replica can take over without waiting for the lease to expire.
+func (le *LeaseElector) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		elector, err := leaderelection.NewLeaderElector(le.config)
+		if err != nil {
+			log.Errorf("Leader: failed to create leader elector: %s", err.Error())
+			return
+		}
+		elector.Run(ctx)
+	}
+}
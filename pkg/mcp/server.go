@@ -337,6 +337,10 @@ type EfficiencyMetric struct {
 	RAMBytesRequested float64 `json:"ramBytesRequested"`
 	RAMBytesUsed      float64 `json:"ramBytesUsed"`
 
+	// Usage percentiles over the window, if queried
+	CPUCoreUsagePercentiles  *opencost.UsagePercentiles `json:"cpuCoreUsagePercentiles,omitempty"`
+	RAMBytesUsagePercentiles *opencost.UsagePercentiles `json:"ramBytesUsagePercentiles,omitempty"`
+
 	// Recommendations (based on actual usage with buffer)
 	RecommendedCPURequest float64 `json:"recommendedCpuRequest"` // Recommended CPU cores
 	RecommendedRAMRequest float64 `json:"recommendedRamRequest"` // Recommended RAM bytes
@@ -377,7 +381,7 @@ func NewMCPServer(costModel *costmodel.CostModel, provider models.Provider, clou
 
 // ProcessMCPRequest processes an MCP request and returns an MCP response.
 
-func (s *MCPServer) ProcessMCPRequest(request *MCPRequest) (*MCPResponse, error) {
+func (s *MCPServer) ProcessMCPRequest(ctx context.Context, request *MCPRequest) (*MCPResponse, error) {
 	// 1. Validate Request
 	if err := validate.Struct(request); err != nil {
 		return nil, fmt.Errorf("validation failed: %w", err)
@@ -391,13 +395,13 @@ func (s *MCPServer) ProcessMCPRequest(request *MCPRequest) (*MCPResponse, error)
 
 	switch request.Query.QueryType {
 	case AllocationQueryType:
-		data, err = s.QueryAllocations(request.Query)
+		data, err = s.QueryAllocations(ctx, request.Query)
 	case AssetQueryType:
-		data, err = s.QueryAssets(request.Query)
+		data, err = s.QueryAssets(ctx, request.Query)
 	case CloudCostQueryType:
-		data, err = s.QueryCloudCosts(request.Query)
+		data, err = s.QueryCloudCosts(ctx, request.Query)
 	case EfficiencyQueryType:
-		data, err = s.QueryEfficiency(request.Query)
+		data, err = s.QueryEfficiency(ctx, request.Query)
 	default:
 		return nil, fmt.Errorf("unsupported query type: %s", request.Query.QueryType)
 	}
@@ -433,7 +437,7 @@ func generateQueryID() string {
 	return fmt.Sprintf("query-%s", hex.EncodeToString(bytes))
 }
 
-func (s *MCPServer) QueryAllocations(query *OpenCostQueryRequest) (*AllocationResponse, error) {
+func (s *MCPServer) QueryAllocations(ctx context.Context, query *OpenCostQueryRequest) (*AllocationResponse, error) {
 	// 1. Parse Window
 	window, err := opencost.ParseWindowWithOffset(query.Window, 0) // 0 offset for UTC
 	if err != nil {
@@ -495,19 +499,18 @@ func (s *MCPServer) QueryAllocations(query *OpenCostQueryRequest) (*AllocationRe
 	}
 
 	// 4. Call the existing QueryAllocation function with all parameters
-	asr, err := s.costModel.QueryAllocation(
-		window,
-		step,
-		aggregateBy,
-		includeIdle,
-		idleByNode,
-		includeProportionalAssetResourceCosts,
-		includeAggregatedMetadata,
-		sharedLoadBalancer,
-		accumulateBy,
-		shareIdle,
-		filterString,
-	)
+	asr, err := s.costModel.QueryAllocation(ctx, window, &costmodel.AllocationQueryOptions{
+		Step:                                  step,
+		AggregateBy:                           aggregateBy,
+		IncludeIdle:                           includeIdle,
+		IdleByNode:                            idleByNode,
+		IncludeProportionalAssetResourceCosts: includeProportionalAssetResourceCosts,
+		IncludeAggregatedMetadata:             includeAggregatedMetadata,
+		SharedLoadBalancer:                    sharedLoadBalancer,
+		Accumulate:                            accumulateBy,
+		ShareIdle:                             shareIdle,
+		Filter:                                filterString,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query allocations: %w", err)
 	}
@@ -573,7 +576,7 @@ func transformAllocationSet(allocSet *opencost.AllocationSet) *AllocationRespons
 	}
 }
 
-func (s *MCPServer) QueryAssets(query *OpenCostQueryRequest) (*AssetResponse, error) {
+func (s *MCPServer) QueryAssets(ctx context.Context, query *OpenCostQueryRequest) (*AssetResponse, error) {
 	// 1. Parse Window
 	window, err := opencost.ParseWindowWithOffset(query.Window, 0) // 0 offset for UTC
 	if err != nil {
@@ -585,7 +588,7 @@ func (s *MCPServer) QueryAssets(query *OpenCostQueryRequest) (*AssetResponse, er
 	end := *window.End()
 
 	// 3. Call CostModel to get the asset set
-	assetSet, err := s.costModel.ComputeAssets(start, end)
+	assetSet, err := s.costModel.ComputeAssets(ctx, start, end)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute assets: %w", err)
 	}
@@ -713,7 +716,7 @@ func transformAssetSet(assetSet *opencost.AssetSet) *AssetResponse {
 }
 
 // QueryCloudCosts translates an MCP query into a CloudCost repository query and transforms the result.
-func (s *MCPServer) QueryCloudCosts(query *OpenCostQueryRequest) (*CloudCostResponse, error) {
+func (s *MCPServer) QueryCloudCosts(ctx context.Context, query *OpenCostQueryRequest) (*CloudCostResponse, error) {
 	// 1. Check if cloud cost querier is available
 	if s.cloudQuerier == nil {
 		return nil, fmt.Errorf("cloud cost querier not configured - check cloud-integration.json file")
@@ -738,7 +741,7 @@ func (s *MCPServer) QueryCloudCosts(query *OpenCostQueryRequest) (*CloudCostResp
 	}
 
 	// 5. Query the repository (this handles multiple cloud providers automatically)
-	ccsr, err := s.cloudQuerier.Query(context.TODO(), request)
+	ccsr, err := s.cloudQuerier.Query(ctx, request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query cloud costs: %w", err)
 	}
@@ -980,7 +983,7 @@ func transformCloudCostSetRange(ccsr *opencost.CloudCostSetRange) *CloudCostResp
 }
 
 // QueryEfficiency queries allocation data and computes efficiency metrics with recommendations.
-func (s *MCPServer) QueryEfficiency(query *OpenCostQueryRequest) (*EfficiencyResponse, error) {
+func (s *MCPServer) QueryEfficiency(ctx context.Context, query *OpenCostQueryRequest) (*EfficiencyResponse, error) {
 	// 1. Parse Window
 	window, err := opencost.ParseWindowWithOffset(query.Window, 0)
 	if err != nil {
@@ -1026,19 +1029,11 @@ func (s *MCPServer) QueryEfficiency(query *OpenCostQueryRequest) (*EfficiencyRes
 	// 4. Query allocations with the specified parameters
 	// Use the entire window as step to get aggregated data
 	step := window.Duration()
-	asr, err := s.costModel.QueryAllocation(
-		window,
-		step,
-		aggregateBy,
-		false, // includeIdle
-		false, // idleByNode
-		false, // includeProportionalAssetResourceCosts
-		false, // includeAggregatedMetadata
-		false, // sharedLoadBalancer
-		opencost.AccumulateOptionNone,
-		false, // shareIdle
-		filterString,
-	)
+	asr, err := s.costModel.QueryAllocation(ctx, window, &costmodel.AllocationQueryOptions{
+		Step:        step,
+		AggregateBy: aggregateBy,
+		Filter:      filterString,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query allocations: %w", err)
 	}
@@ -1165,6 +1160,12 @@ func computeEfficiencyMetric(alloc *opencost.Allocation, bufferMultiplier float6
 	costSavings := currentTotalCost - recommendedTotalCost
 	costSavingsPercent := safeDiv(costSavings, currentTotalCost) * 100
 
+	var cpuUsagePercentiles, ramUsagePercentiles *opencost.UsagePercentiles
+	if alloc.RawAllocationOnly != nil {
+		cpuUsagePercentiles = alloc.RawAllocationOnly.CPUCoreUsagePercentiles.Clone()
+		ramUsagePercentiles = alloc.RawAllocationOnly.RAMBytesUsagePercentiles.Clone()
+	}
+
 	return &EfficiencyMetric{
 		Name:                       alloc.Name,
 		CPUEfficiency:              cpuEfficiency,
@@ -1173,6 +1174,8 @@ func computeEfficiencyMetric(alloc *opencost.Allocation, bufferMultiplier float6
 		CPUCoresUsed:               cpuCoresUsed,
 		RAMBytesRequested:          ramBytesRequested,
 		RAMBytesUsed:               ramBytesUsed,
+		CPUCoreUsagePercentiles:    cpuUsagePercentiles,
+		RAMBytesUsagePercentiles:   ramUsagePercentiles,
 		RecommendedCPURequest:      recommendedCPU,
 		RecommendedRAMRequest:      recommendedRAM,
 		ResultingCPUEfficiency:     resultingCPUEff,
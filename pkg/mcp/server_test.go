@@ -608,7 +608,7 @@ func TestQueryCloudCosts_QuerierCapture(t *testing.T) {
 		},
 	}
 
-	_, err := s.QueryCloudCosts(req)
+	_, err := s.QueryCloudCosts(context.Background(), req)
 	require.NoError(t, err)
 
 	assert.Equal(t, []string{"provider", "service"}, dq.last.AggregateBy)
@@ -633,7 +633,7 @@ func TestProcessMCPRequest_CloudCostDispatch(t *testing.T) {
 		},
 	}
 
-	resp, err := s.ProcessMCPRequest(req)
+	resp, err := s.ProcessMCPRequest(context.Background(), req)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	require.NotNil(t, resp.Data)
@@ -648,7 +648,7 @@ func TestProcessMCPRequest_UnsupportedType(t *testing.T) {
 			Window:    "1d",
 		},
 	}
-	_, err := s.ProcessMCPRequest(req)
+	_, err := s.ProcessMCPRequest(context.Background(), req)
 	require.Error(t, err)
 }
 
@@ -661,7 +661,7 @@ func TestProcessMCPRequest_ValidationError(t *testing.T) {
 			Window:    "",
 		},
 	}
-	_, err := s.ProcessMCPRequest(req)
+	_, err := s.ProcessMCPRequest(context.Background(), req)
 	require.Error(t, err)
 }
 
@@ -829,7 +829,7 @@ func TestQueryCloudCosts_NilCloudQuerier(t *testing.T) {
 		Window:    "24h",
 	}
 
-	_, err := s.QueryCloudCosts(req)
+	_, err := s.QueryCloudCosts(context.Background(), req)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "cloud cost querier not configured")
 }
@@ -842,7 +842,7 @@ func TestQueryCloudCosts_InvalidWindow(t *testing.T) {
 		Window:    "invalid-window",
 	}
 
-	_, err := s.QueryCloudCosts(req)
+	_, err := s.QueryCloudCosts(context.Background(), req)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse window")
 }
@@ -855,7 +855,7 @@ func TestQueryAssets_InvalidWindow(t *testing.T) {
 		Window:    "invalid-window",
 	}
 
-	_, err := s.QueryAssets(req)
+	_, err := s.QueryAssets(context.Background(), req)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse window")
 }
@@ -868,7 +868,7 @@ func TestQueryAllocations_InvalidWindow(t *testing.T) {
 		Window:    "invalid-window",
 	}
 
-	_, err := s.QueryAllocations(req)
+	_, err := s.QueryAllocations(context.Background(), req)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse window")
 }
@@ -884,7 +884,7 @@ func TestProcessMCPRequest_ResponseMetadata(t *testing.T) {
 		},
 	}
 
-	resp, err := s.ProcessMCPRequest(req)
+	resp, err := s.ProcessMCPRequest(context.Background(), req)
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 
@@ -1074,6 +1074,36 @@ func TestComputeEfficiencyMetric_ValidAllocation(t *testing.T) {
 	assert.Greater(t, result.CostSavings, 0.0)
 }
 
+func TestComputeEfficiencyMetric_UsagePercentiles(t *testing.T) {
+	now := time.Now()
+	alloc := &opencost.Allocation{
+		Name:                   "test-pod",
+		Start:                  now.Add(-24 * time.Hour),
+		End:                    now,
+		CPUCoreHours:           24.0,
+		RAMByteHours:           24.0e9,
+		CPUCoreRequestAverage:  2.0,
+		RAMBytesRequestAverage: 2.0e9,
+		RawAllocationOnly: &opencost.RawAllocationOnlyData{
+			CPUCoreUsagePercentiles:  &opencost.UsagePercentiles{P50: 0.5, P90: 1.0, P95: 1.5, P99: 1.8},
+			RAMBytesUsagePercentiles: &opencost.UsagePercentiles{P50: 0.5e9, P90: 1.0e9, P95: 1.2e9, P99: 1.5e9},
+		},
+	}
+
+	result := computeEfficiencyMetric(alloc, 1.2)
+
+	require.NotNil(t, result)
+	assert.Equal(t, alloc.RawAllocationOnly.CPUCoreUsagePercentiles, result.CPUCoreUsagePercentiles)
+	assert.Equal(t, alloc.RawAllocationOnly.RAMBytesUsagePercentiles, result.RAMBytesUsagePercentiles)
+
+	// percentiles are omitted if they were not queried
+	alloc.RawAllocationOnly = nil
+	result = computeEfficiencyMetric(alloc, 1.2)
+	require.NotNil(t, result)
+	assert.Nil(t, result.CPUCoreUsagePercentiles)
+	assert.Nil(t, result.RAMBytesUsagePercentiles)
+}
+
 func TestComputeEfficiencyMetric_CustomBufferMultiplier(t *testing.T) {
 	now := time.Now()
 	alloc := &opencost.Allocation{
@@ -1291,7 +1321,7 @@ func TestQueryEfficiency_InvalidWindow(t *testing.T) {
 		Window:    "invalid-window",
 	}
 
-	_, err := s.QueryEfficiency(req)
+	_, err := s.QueryEfficiency(context.Background(), req)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse window")
 }
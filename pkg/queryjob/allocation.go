@@ -0,0 +1,41 @@
+package queryjob
+
+import (
+	"context"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/pkg/costmodel"
+)
+
+// AllocationQuerier computes allocations, as CostModel does.
+type AllocationQuerier interface {
+	QueryAllocation(ctx context.Context, window opencost.Window, opts *costmodel.AllocationQueryOptions) (*opencost.AllocationSetRange, error)
+}
+
+// AllocationTask returns a Task which runs the query, computing one step at a time
+// so that progress can be reported and cancellation takes effect between steps.
+func AllocationTask(querier AllocationQuerier, window opencost.Window, opts *costmodel.AllocationQueryOptions) Task {
+	return func(ctx context.Context, progress ProgressFunc) (any, error) {
+		steps := costmodel.AllocationSteps(window, opts)
+		progress(0, len(steps))
+
+		result := opencost.NewAllocationSetRange()
+		for i, step := range steps {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			asr, err := querier.QueryAllocation(ctx, step, opts)
+			if err != nil {
+				return nil, err
+			}
+			for _, as := range asr.Allocations {
+				result.Append(as)
+			}
+
+			progress(i+1, len(steps))
+		}
+
+		return result, nil
+	}
+}
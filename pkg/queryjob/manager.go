@@ -0,0 +1,497 @@
+package queryjob
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/storage"
+)
+
+// jobDir is the storage directory which jobs and their results are written to.
+const jobDir = "queryjobs"
+
+// resultSuffix is the suffix of the file holding a job's result, next to the
+// file holding the job itself.
+const resultSuffix = ".result.json"
+
+var (
+	// ErrNotFound is returned when a job does not exist, or has expired.
+	ErrNotFound = errors.New("job not found")
+
+	// ErrNotReady is returned when the result of a job is requested before
+	// the job has succeeded.
+	ErrNotReady = errors.New("job result is not ready")
+
+	// ErrCompleted is returned when canceling a job which has already completed.
+	ErrCompleted = errors.New("job has already completed")
+
+	// ErrQueueFull is returned when submitting a job while the queue of jobs
+	// waiting to run is full.
+	ErrQueueFull = errors.New("job queue is full")
+)
+
+// Status is the state of a job.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	StatusCanceled  Status = "canceled"
+)
+
+// IsComplete returns true if the job has finished, successfully or not.
+func (s Status) IsComplete() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+// Job describes the state and progress of a submitted task.
+type Job struct {
+	ID          string     `json:"id"`
+	Status      Status     `json:"status"`
+	Completed   int        `json:"completed"`
+	Total       int        `json:"total"`
+	Progress    float64    `json:"progress"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ProgressFunc is called by a Task to report the number of units of work it has
+// completed out of the total.
+type ProgressFunc func(completed, total int)
+
+// Task computes the result of a job. The result is encoded as JSON once the task
+// returns. Tasks should stop early with the context's error once it is done.
+type Task func(ctx context.Context, progress ProgressFunc) (any, error)
+
+type job struct {
+	Job
+	task   Task
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Manager runs submitted tasks in the background on a fixed number of workers,
+// queueing at most a fixed number of tasks for them, and writes jobs and their
+// results to storage. Jobs are read back from storage when they are not known to
+// the Manager, e.g. when they were submitted to another replica sharing the
+// storage. Completed jobs and their results are removed once their TTL has
+// elapsed.
+type Manager struct {
+	store    storage.Storage
+	ttl      time.Duration
+	queue    chan *job
+	lock     sync.Mutex
+	jobs     map[string]*job
+	saveLock sync.Mutex
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewManager creates a new Manager which runs at most concurrency tasks at once,
+// queues at most queueSize tasks waiting to run, and keeps completed jobs and their
+// results in the given storage for ttl.
+func NewManager(store storage.Storage, concurrency int, queueSize int, ttl time.Duration) (*Manager, error) {
+	if store == nil {
+		return nil, fmt.Errorf("storage is required")
+	}
+	if concurrency <= 0 {
+		return nil, fmt.Errorf("invalid concurrency %d", concurrency)
+	}
+	if queueSize <= 0 {
+		return nil, fmt.Errorf("invalid queue size %d", queueSize)
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("invalid ttl %s", ttl)
+	}
+
+	m := &Manager{
+		store: store,
+		ttl:   ttl,
+		queue: make(chan *job, queueSize),
+		jobs:  make(map[string]*job),
+		stop:  make(chan struct{}),
+	}
+	for range concurrency {
+		go m.work()
+	}
+	go m.cleanup()
+
+	return m, nil
+}
+
+// Stop cancels every job which has not completed, and stops the workers and the
+// removal of expired jobs.
+func (m *Manager) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.stop)
+
+		m.lock.Lock()
+		defer m.lock.Unlock()
+		for _, j := range m.jobs {
+			j.cancel()
+		}
+	})
+}
+
+// Submit queues the task to be run, returning the new job, or ErrQueueFull if the
+// queue is full.
+func (m *Manager) Submit(task Task) (Job, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		Job: Job{
+			ID:        uuid.NewString(),
+			Status:    StatusQueued,
+			CreatedAt: time.Now().UTC(),
+		},
+		task:   task,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	m.lock.Lock()
+	select {
+	case m.queue <- j:
+	default:
+		m.lock.Unlock()
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+	m.jobs[j.ID] = j
+	submitted := j.Job
+	m.lock.Unlock()
+
+	m.save(j)
+
+	return submitted, nil
+}
+
+// Get returns the current state of the job with the given id.
+func (m *Manager) Get(id string) (Job, error) {
+	m.lock.Lock()
+	j, ok := m.jobs[id]
+	if ok {
+		defer m.lock.Unlock()
+		return j.Job, nil
+	}
+	m.lock.Unlock()
+
+	stored, err := m.load(id)
+	if err != nil {
+		return Job{}, err
+	}
+	if stored.isExpired(time.Now()) {
+		return Job{}, ErrNotFound
+	}
+	return *stored, nil
+}
+
+// Result returns the JSON encoded result of the job with the given id, or
+// ErrNotReady if the job has not succeeded.
+func (m *Manager) Result(id string) ([]byte, error) {
+	j, err := m.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if j.Status != StatusSucceeded {
+		return nil, ErrNotReady
+	}
+
+	data, err := m.store.Read(resultPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("reading result of job %s: %w", id, err)
+	}
+	return data, nil
+}
+
+// Cancel cancels the job with the given id. Running tasks are canceled through
+// their context, so they stop once they next check it. Only jobs submitted to
+// this Manager can be canceled.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.lock.Lock()
+	j, ok := m.jobs[id]
+	if !ok {
+		m.lock.Unlock()
+
+		stored, err := m.Get(id)
+		if err == nil && stored.Status.IsComplete() {
+			return stored, ErrCompleted
+		}
+		return Job{}, ErrNotFound
+	}
+	if j.Status.IsComplete() {
+		defer m.lock.Unlock()
+		return j.Job, ErrCompleted
+	}
+
+	j.cancel()
+	m.complete(j, StatusCanceled, context.Canceled)
+	canceled := j.Job
+	m.lock.Unlock()
+
+	m.save(j)
+	return canceled, nil
+}
+
+// work runs queued jobs until the Manager is stopped.
+func (m *Manager) work() {
+	for {
+		select {
+		case <-m.stop:
+			return
+		case j := <-m.queue:
+			m.run(j)
+		}
+	}
+}
+
+func (m *Manager) run(j *job) {
+	defer j.cancel()
+
+	m.lock.Lock()
+	if j.Status.IsComplete() || j.ctx.Err() != nil {
+		m.lock.Unlock()
+		return
+	}
+	now := time.Now().UTC()
+	j.Status = StatusRunning
+	j.StartedAt = &now
+	m.lock.Unlock()
+
+	m.save(j)
+
+	result, err := j.task(j.ctx, func(completed, total int) {
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		j.Completed = completed
+		j.Total = total
+		if total > 0 {
+			j.Progress = float64(completed) / float64(total)
+		}
+	})
+	if err == nil {
+		err = m.write(j.ID, result)
+	}
+
+	m.lock.Lock()
+
+	// the job may have been canceled while its task was running, in which case any
+	// result it wrote will not be read
+	if j.Status.IsComplete() {
+		m.lock.Unlock()
+		if err == nil {
+			m.removeResult(j.ID)
+		}
+		return
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		m.complete(j, StatusCanceled, err)
+	case err != nil:
+		log.Warnf("QueryJob: job %s failed: %s", j.ID, err)
+		m.complete(j, StatusFailed, err)
+	default:
+		j.Progress = 1
+		m.complete(j, StatusSucceeded, nil)
+	}
+	m.lock.Unlock()
+
+	m.save(j)
+}
+
+func (m *Manager) write(id string, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding result: %w", err)
+	}
+
+	if err := m.store.Write(resultPath(id), data); err != nil {
+		return fmt.Errorf("writing result: %w", err)
+	}
+	return nil
+}
+
+// save writes the current state of the job to storage. Saves are serialized, and
+// each writes the latest state, so an earlier state never overwrites a later one.
+func (m *Manager) save(j *job) {
+	m.saveLock.Lock()
+	defer m.saveLock.Unlock()
+
+	m.lock.Lock()
+	current := j.Job
+	m.lock.Unlock()
+
+	data, err := json.Marshal(current)
+	if err == nil {
+		err = m.store.Write(jobPath(current.ID), data)
+	}
+	if err != nil {
+		log.Warnf("QueryJob: failed to save job %s: %s", current.ID, err)
+	}
+}
+
+// load reads the job with the given id from storage.
+func (m *Manager) load(id string) (*Job, error) {
+	exists, err := m.store.Exists(jobPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("reading job %s: %w", id, err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	data, err := m.store.Read(jobPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("reading job %s: %w", id, err)
+	}
+	stored := &Job{}
+	if err := json.Unmarshal(data, stored); err != nil {
+		return nil, fmt.Errorf("decoding job %s: %w", id, err)
+	}
+	return stored, nil
+}
+
+// isExpired returns true if the job has completed and its TTL has elapsed.
+func (j *Job) isExpired(now time.Time) bool {
+	return j.ExpiresAt != nil && !now.Before(*j.ExpiresAt)
+}
+
+// complete marks the job as complete. The lock must be held.
+func (m *Manager) complete(j *job, status Status, err error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(m.ttl)
+
+	j.Status = status
+	j.CompletedAt = &now
+	j.ExpiresAt = &expiresAt
+	if err != nil {
+		j.Error = err.Error()
+	}
+}
+
+// cleanup periodically removes expired jobs and their results until the Manager
+// is stopped.
+func (m *Manager) cleanup() {
+	interval := min(m.ttl, time.Minute)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.removeExpired(time.Now())
+		}
+	}
+}
+
+func (m *Manager) removeExpired(now time.Time) {
+	var expired []*job
+
+	m.lock.Lock()
+	for id, j := range m.jobs {
+		if j.isExpired(now) {
+			expired = append(expired, j)
+			delete(m.jobs, id)
+		}
+	}
+	m.lock.Unlock()
+
+	for _, j := range expired {
+		m.remove(j.ID)
+	}
+
+	// Memory storage only holds the jobs of this Manager, which are all known to it
+	if m.store.StorageType() != storage.StorageTypeMemory {
+		m.removeExpiredStored(now)
+	}
+}
+
+// removeExpiredStored removes the expired jobs in storage which are not known to
+// the Manager, e.g. those submitted to another replica, or before a restart. Jobs
+// which never completed, because the process running them stopped, and results
+// without a job, are removed once they are older than the TTL.
+func (m *Manager) removeExpiredStored(now time.Time) {
+	files, err := m.store.List(jobDir)
+	if err != nil {
+		log.Warnf("QueryJob: failed to list stored jobs: %s", err)
+		return
+	}
+
+	jobs := map[string]bool{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name, resultSuffix) {
+			jobs[strings.TrimSuffix(file.Name, ".json")] = true
+		}
+	}
+
+	for _, file := range files {
+		stale := now.Sub(file.ModTime) >= m.ttl
+
+		if id, ok := strings.CutSuffix(file.Name, resultSuffix); ok {
+			if !jobs[id] && stale {
+				m.removeResult(id)
+			}
+			continue
+		}
+
+		id := strings.TrimSuffix(file.Name, ".json")
+		m.lock.Lock()
+		_, known := m.jobs[id]
+		m.lock.Unlock()
+		if known {
+			continue
+		}
+
+		stored, err := m.load(id)
+		if err != nil {
+			log.Warnf("QueryJob: failed to read stored job %s: %s", id, err)
+			continue
+		}
+		if stored.isExpired(now) || (!stored.Status.IsComplete() && stale) {
+			m.remove(id)
+		}
+	}
+}
+
+// remove removes the job with the given id and its result from storage.
+func (m *Manager) remove(id string) {
+	m.removeResult(id)
+	if err := m.store.Remove(jobPath(id)); err != nil {
+		log.Warnf("QueryJob: failed to remove job %s: %s", id, err)
+	}
+}
+
+func (m *Manager) removeResult(id string) {
+	exists, err := m.store.Exists(resultPath(id))
+	if err == nil && !exists {
+		return
+	}
+	if err := m.store.Remove(resultPath(id)); err != nil {
+		log.Warnf("QueryJob: failed to remove result of job %s: %s", id, err)
+	}
+}
+
+func jobPath(id string) string {
+	return path.Join(jobDir, id+".json")
+}
+
+func resultPath(id string) string {
+	return path.Join(jobDir, id+resultSuffix)
+}
@@ -0,0 +1,275 @@
+package queryjob
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencost/opencost/core/pkg/storage"
+)
+
+func newTestManager(t *testing.T, concurrency int) (*Manager, *storage.MemoryStorage) {
+	t.Helper()
+
+	store := storage.NewMemoryStorage()
+	manager, err := NewManager(store, concurrency, 10, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(manager.Stop)
+
+	return manager, store
+}
+
+func submit(t *testing.T, manager *Manager, task Task) Job {
+	t.Helper()
+
+	job, err := manager.Submit(task)
+	require.NoError(t, err)
+	return job
+}
+
+func waitForStatus(t *testing.T, manager *Manager, id string, status Status) Job {
+	t.Helper()
+
+	var job Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = manager.Get(id)
+		require.NoError(t, err)
+		return job.Status == status
+	}, 5*time.Second, 5*time.Millisecond)
+
+	return job
+}
+
+// blockingTask returns a task which runs until it is released or canceled.
+func blockingTask(release <-chan struct{}) Task {
+	return func(ctx context.Context, progress ProgressFunc) (any, error) {
+		select {
+		case <-release:
+			return "done", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func TestNewManager(t *testing.T) {
+	_, err := NewManager(nil, 1, 1, time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewManager(storage.NewMemoryStorage(), 0, 1, time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewManager(storage.NewMemoryStorage(), 1, 0, time.Hour)
+	assert.Error(t, err)
+
+	_, err = NewManager(storage.NewMemoryStorage(), 1, 1, 0)
+	assert.Error(t, err)
+}
+
+func TestJobSucceeded(t *testing.T) {
+	manager, store := newTestManager(t, 1)
+
+	job := submit(t, manager, func(ctx context.Context, progress ProgressFunc) (any, error) {
+		progress(1, 2)
+		progress(2, 2)
+		return map[string]int{"value": 1}, nil
+	})
+	assert.Equal(t, StatusQueued, job.Status)
+
+	job = waitForStatus(t, manager, job.ID, StatusSucceeded)
+	assert.Equal(t, 2, job.Completed)
+	assert.Equal(t, 2, job.Total)
+	assert.Equal(t, 1.0, job.Progress)
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.CompletedAt)
+	assert.Equal(t, job.CompletedAt.Add(time.Hour), *job.ExpiresAt)
+
+	result, err := manager.Result(job.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value": 1}`, string(result))
+
+	exists, err := store.Exists(resultPath(job.ID))
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestJobFailed(t *testing.T) {
+	manager, _ := newTestManager(t, 1)
+
+	job := submit(t, manager, func(ctx context.Context, progress ProgressFunc) (any, error) {
+		return nil, errors.New("query failed")
+	})
+
+	job = waitForStatus(t, manager, job.ID, StatusFailed)
+	assert.Equal(t, "query failed", job.Error)
+
+	_, err := manager.Result(job.ID)
+	assert.ErrorIs(t, err, ErrNotReady)
+}
+
+func TestJobCanceled(t *testing.T) {
+	manager, _ := newTestManager(t, 1)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	running := submit(t, manager, blockingTask(release))
+	waitForStatus(t, manager, running.ID, StatusRunning)
+
+	// concurrency is 1, so the second job waits for the first
+	queued := submit(t, manager, blockingTask(release))
+	job, err := manager.Get(queued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusQueued, job.Status)
+
+	for _, id := range []string{queued.ID, running.ID} {
+		job, err := manager.Cancel(id)
+		require.NoError(t, err)
+		assert.Equal(t, StatusCanceled, job.Status)
+
+		_, err = manager.Cancel(id)
+		assert.ErrorIs(t, err, ErrCompleted)
+
+		_, err = manager.Result(id)
+		assert.ErrorIs(t, err, ErrNotReady)
+	}
+
+	// the canceled job releases its slot to the next job
+	next := submit(t, manager, func(ctx context.Context, progress ProgressFunc) (any, error) {
+		return "done", nil
+	})
+	waitForStatus(t, manager, next.ID, StatusSucceeded)
+
+	_, err = manager.Cancel("unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestConcurrency(t *testing.T) {
+	manager, _ := newTestManager(t, 2)
+
+	release := make(chan struct{})
+	jobs := make([]Job, 3)
+	for i := range jobs {
+		jobs[i] = submit(t, manager, blockingTask(release))
+	}
+
+	require.Eventually(t, func() bool {
+		counts := map[Status]int{}
+		for _, job := range jobs {
+			job, err := manager.Get(job.ID)
+			require.NoError(t, err)
+			counts[job.Status]++
+		}
+		return counts[StatusRunning] == 2 && counts[StatusQueued] == 1
+	}, 5*time.Second, 5*time.Millisecond)
+
+	close(release)
+	for _, job := range jobs {
+		waitForStatus(t, manager, job.ID, StatusSucceeded)
+	}
+}
+
+func TestRemoveExpired(t *testing.T) {
+	manager, store := newTestManager(t, 1)
+
+	job := submit(t, manager, func(ctx context.Context, progress ProgressFunc) (any, error) {
+		return "done", nil
+	})
+	job = waitForStatus(t, manager, job.ID, StatusSucceeded)
+
+	manager.removeExpired(job.ExpiresAt.Add(-time.Second))
+	_, err := manager.Get(job.ID)
+	require.NoError(t, err)
+
+	manager.removeExpired(*job.ExpiresAt)
+	_, err = manager.Get(job.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	exists, err := store.Exists(resultPath(job.ID))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestQueueFull(t *testing.T) {
+	manager, err := NewManager(storage.NewMemoryStorage(), 1, 1, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(manager.Stop)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	running := submit(t, manager, blockingTask(release))
+	waitForStatus(t, manager, running.ID, StatusRunning)
+	submit(t, manager, blockingTask(release))
+
+	// the worker is busy and the queue holds one job, so further jobs are rejected
+	_, err = manager.Submit(blockingTask(release))
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestStoredJobs(t *testing.T) {
+	// Managers sharing storage, e.g. the replicas of a highly available deployment
+	store := storage.NewFileStorage(t.TempDir())
+	manager, err := NewManager(store, 1, 1, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(manager.Stop)
+	other, err := NewManager(store, 1, 1, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(other.Stop)
+
+	job := submit(t, manager, func(ctx context.Context, progress ProgressFunc) (any, error) {
+		return "done", nil
+	})
+	job = waitForStatus(t, manager, job.ID, StatusSucceeded)
+
+	stored, err := other.Get(job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSucceeded, stored.Status)
+	result, err := other.Result(job.ID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"done"`, string(result))
+	_, err = other.Cancel(job.ID)
+	assert.ErrorIs(t, err, ErrCompleted)
+
+	// jobs which are not known to the manager are removed from storage once expired
+	other.removeExpired(job.ExpiresAt.Add(-time.Second))
+	_, err = other.Get(job.ID)
+	require.NoError(t, err)
+
+	other.removeExpired(*job.ExpiresAt)
+	_, err = manager.Get(job.ID)
+	require.NoError(t, err, "jobs known to a manager are removed by that manager")
+	for _, p := range []string{jobPath(job.ID), resultPath(job.ID)} {
+		exists, err := store.Exists(p)
+		require.NoError(t, err)
+		assert.False(t, exists)
+	}
+}
+
+func TestRemoveAbandonedJobs(t *testing.T) {
+	store := storage.NewFileStorage(t.TempDir())
+	manager, err := NewManager(store, 1, 1, time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(manager.Stop)
+
+	// a job left running by a replica which stopped, and a result without a job
+	require.NoError(t, store.Write(jobPath("abandoned"), []byte(`{"id":"abandoned","status":"running"}`)))
+	require.NoError(t, store.Write(resultPath("orphaned"), []byte(`"done"`)))
+
+	manager.removeExpired(time.Now())
+	job, err := manager.Get("abandoned")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, job.Status)
+
+	manager.removeExpired(time.Now().Add(time.Hour))
+	_, err = manager.Get("abandoned")
+	assert.ErrorIs(t, err, ErrNotFound)
+	exists, err := store.Exists(resultPath("orphaned"))
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,134 @@
+package queryjob
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/opencost/opencost/core/pkg/protocol"
+	"github.com/opencost/opencost/core/pkg/util/httputil"
+	"github.com/opencost/opencost/pkg/costmodel"
+)
+
+var proto = protocol.HTTP()
+
+// QueryService surfaces endpoints for submitting allocation queries as jobs, polling
+// their status, downloading their results and canceling them.
+type QueryService struct {
+	Manager           *Manager
+	AllocationQuerier AllocationQuerier
+}
+
+func NewQueryService(manager *Manager, allocationQuerier AllocationQuerier) *QueryService {
+	return &QueryService{
+		Manager:           manager,
+		AllocationQuerier: allocationQuerier,
+	}
+}
+
+// CreateAllocationJobHandler submits an allocation query as a job. It accepts the
+// same parameters as /allocation, in either the query string or a form body, and
+// responds with the queued job, or a 429 if the queue of jobs is full.
+func (s *QueryService) CreateAllocationJobHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if s == nil || s.Manager == nil || s.AllocationQuerier == nil {
+			proto.WriteError(w, proto.NotImplemented("Allocation jobs are not available"))
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			proto.WriteError(w, proto.BadRequest(err.Error()))
+			return
+		}
+
+		window, opts, err := costmodel.ParseAllocationQuery(httputil.NewQueryParams(r.Form))
+		if err != nil {
+			proto.WriteError(w, proto.BadRequest(err.Error()))
+			return
+		}
+		if window.IsOpen() {
+			proto.WriteError(w, proto.BadRequest("invalid 'window' parameter: window must be closed"))
+			return
+		}
+
+		job, err := s.Manager.Submit(AllocationTask(s.AllocationQuerier, window, opts))
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+
+		proto.WriteResponse(w, proto.NewResponse(http.StatusAccepted).WithData(job))
+	}
+}
+
+// GetJobHandler responds with the status and progress of the job.
+func (s *QueryService) GetJobHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if s == nil || s.Manager == nil {
+			proto.WriteError(w, proto.NotImplemented("Allocation jobs are not available"))
+			return
+		}
+
+		job, err := s.Manager.Get(ps.ByName("id"))
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+
+		proto.WriteData(w, job)
+	}
+}
+
+// GetJobResultHandler responds with the result of the job, in the same form as the
+// response of the query which it ran. Jobs which have not succeeded respond with a
+// 409.
+func (s *QueryService) GetJobResultHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if s == nil || s.Manager == nil {
+			proto.WriteError(w, proto.NotImplemented("Allocation jobs are not available"))
+			return
+		}
+
+		result, err := s.Manager.Result(ps.ByName("id"))
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+
+		proto.WriteData(w, json.RawMessage(result))
+	}
+}
+
+// CancelJobHandler cancels the job, responding with its updated state. Jobs which
+// have already completed respond with a 409.
+func (s *QueryService) CancelJobHandler() func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if s == nil || s.Manager == nil {
+			proto.WriteError(w, proto.NotImplemented("Allocation jobs are not available"))
+			return
+		}
+
+		job, err := s.Manager.Cancel(ps.ByName("id"))
+		if err != nil {
+			writeJobError(w, err)
+			return
+		}
+
+		proto.WriteData(w, job)
+	}
+}
+
+func writeJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		proto.WriteError(w, proto.NotFound())
+	case errors.Is(err, ErrNotReady), errors.Is(err, ErrCompleted):
+		proto.WriteError(w, *proto.NewError(err, http.StatusConflict))
+	case errors.Is(err, ErrQueueFull):
+		proto.WriteError(w, *proto.NewError(err, http.StatusTooManyRequests))
+	default:
+		proto.WriteError(w, proto.InternalServerError(err.Error()))
+	}
+}
@@ -0,0 +1,180 @@
+package queryjob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/opencost/opencost/core/pkg/opencost"
+	"github.com/opencost/opencost/pkg/costmodel"
+)
+
+type fakeAllocationQuerier struct {
+	lock         sync.Mutex
+	windows      []opencost.Window
+	accumulateBy []opencost.AccumulateOption
+}
+
+func (f *fakeAllocationQuerier) QueryAllocation(ctx context.Context, window opencost.Window, opts *costmodel.AllocationQueryOptions) (*opencost.AllocationSetRange, error) {
+	f.lock.Lock()
+	f.windows = append(f.windows, window)
+	f.accumulateBy = append(f.accumulateBy, opts.Accumulate)
+	f.lock.Unlock()
+
+	start := *window.Start()
+	as := opencost.NewAllocationSet(start, *window.End(),
+		opencost.NewMockUnitAllocation("default", start, window.Duration(), &opencost.AllocationProperties{Namespace: "default"}),
+	)
+	return opencost.NewAllocationSetRange(as), nil
+}
+
+func newTestRouter(t *testing.T, querier AllocationQuerier) *httprouter.Router {
+	t.Helper()
+
+	manager, _ := newTestManager(t, 1)
+	service := NewQueryService(manager, querier)
+
+	router := httprouter.New()
+	router.POST("/allocation/jobs", service.CreateAllocationJobHandler())
+	router.GET("/allocation/jobs/:id", service.GetJobHandler())
+	router.GET("/allocation/jobs/:id/result", service.GetJobResultHandler())
+	router.DELETE("/allocation/jobs/:id", service.CancelJobHandler())
+	return router
+}
+
+func do(t *testing.T, router http.Handler, method, target string, body url.Values, data any) int {
+	t.Helper()
+
+	r := httptest.NewRequest(method, target, strings.NewReader(body.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	if data != nil && w.Code < http.StatusBadRequest {
+		response := struct {
+			Data any `json:"data"`
+		}{Data: data}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	}
+	return w.Code
+}
+
+func TestAllocationJob(t *testing.T) {
+	querier := &fakeAllocationQuerier{}
+	router := newTestRouter(t, querier)
+
+	var job Job
+	code := do(t, router, http.MethodPost, "/allocation/jobs?step=1d", url.Values{
+		"window":    {"2024-01-01T00:00:00Z,2024-01-03T12:00:00Z"},
+		"aggregate": {"namespace"},
+	}, &job)
+	require.Equal(t, http.StatusAccepted, code)
+	require.NotEmpty(t, job.ID)
+
+	require.Eventually(t, func() bool {
+		require.Equal(t, http.StatusOK, do(t, router, http.MethodGet, "/allocation/jobs/"+job.ID, nil, &job))
+		return job.Status == StatusSucceeded
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, 3, job.Completed)
+	assert.Equal(t, 3, job.Total)
+
+	// each step is computed separately, the last of which is truncated to the window
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []opencost.Window{
+		opencost.NewClosedWindow(start, start.Add(24*time.Hour)),
+		opencost.NewClosedWindow(start.Add(24*time.Hour), start.Add(48*time.Hour)),
+		opencost.NewClosedWindow(start.Add(48*time.Hour), start.Add(60*time.Hour)),
+	}, querier.windows)
+
+	var result []map[string]*opencost.Allocation
+	require.Equal(t, http.StatusOK, do(t, router, http.MethodGet, "/allocation/jobs/"+job.ID+"/result", nil, &result))
+	require.Len(t, result, 3)
+	assert.Equal(t, "default", result[2]["default"].Properties.Namespace)
+	assert.Equal(t, start.Add(48*time.Hour), *result[2]["default"].Window.Start())
+
+	// completed jobs cannot be canceled
+	assert.Equal(t, http.StatusConflict, do(t, router, http.MethodDelete, "/allocation/jobs/"+job.ID, nil, nil))
+}
+
+func TestAccumulatedAllocationJob(t *testing.T) {
+	querier := &fakeAllocationQuerier{}
+	router := newTestRouter(t, querier)
+
+	var job Job
+	code := do(t, router, http.MethodPost, "/allocation/jobs", url.Values{
+		"window":     {"2024-01-01T00:00:00Z,2024-01-03T00:00:00Z"},
+		"step":       {"1d"},
+		"accumulate": {"true"},
+	}, &job)
+	require.Equal(t, http.StatusAccepted, code)
+
+	require.Eventually(t, func() bool {
+		require.Equal(t, http.StatusOK, do(t, router, http.MethodGet, "/allocation/jobs/"+job.ID, nil, &job))
+		return job.Status == StatusSucceeded
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, 1, job.Total)
+
+	require.Len(t, querier.windows, 1)
+	assert.Equal(t, 48*time.Hour, querier.windows[0].Duration())
+	assert.Equal(t, opencost.AccumulateOptionAll, querier.accumulateBy[0])
+}
+
+func TestAllocationJobErrors(t *testing.T) {
+	router := newTestRouter(t, &fakeAllocationQuerier{})
+
+	cases := map[string]struct {
+		method string
+		target string
+		body   url.Values
+		code   int
+	}{
+		"missing window": {
+			method: http.MethodPost,
+			target: "/allocation/jobs",
+			code:   http.StatusBadRequest,
+		},
+		"invalid step": {
+			method: http.MethodPost,
+			target: "/allocation/jobs",
+			body:   url.Values{"window": {"1d"}, "step": {"-1h"}},
+			code:   http.StatusBadRequest,
+		},
+		"unknown job": {
+			method: http.MethodGet,
+			target: "/allocation/jobs/unknown",
+			code:   http.StatusNotFound,
+		},
+		"unknown job result": {
+			method: http.MethodGet,
+			target: "/allocation/jobs/unknown/result",
+			code:   http.StatusNotFound,
+		},
+		"cancel unknown job": {
+			method: http.MethodDelete,
+			target: "/allocation/jobs/unknown",
+			code:   http.StatusNotFound,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.code, do(t, router, c.method, c.target, c.body, nil))
+		})
+	}
+
+	var unavailable *QueryService
+	router = httprouter.New()
+	router.POST("/allocation/jobs", unavailable.CreateAllocationJobHandler())
+	assert.Equal(t, http.StatusNotImplemented, do(t, router, http.MethodPost, "/allocation/jobs", nil, nil))
+}
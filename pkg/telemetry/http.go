@@ -0,0 +1,29 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// untracedPaths are the paths of the probes and scrapes which are requested continuously, and would otherwise
+// dominate the sampled traces
+var untracedPaths = map[string]struct{}{
+	"/healthz": {},
+	"/metrics": {},
+}
+
+// HTTPHandler wraps the handler so that each request it serves is traced in a span, which continues the trace of the
+// client if the request carries a trace context. The span is on the context of the request, so that the spans of the
+// computations made for the request are its children.
+func HTTPHandler(handler http.Handler) http.Handler {
+	return otelhttp.NewHandler(handler, ServiceName,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			_, untraced := untracedPaths[r.URL.Path]
+			return !untraced
+		}),
+	)
+}
@@ -0,0 +1,91 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// IngestionTracker traces the windows built by an ingestor of the given source, e.g. cloudcost or customcost, and
+// records when it last ingested the data of each of its integrations, reporting their ingestion lag while tracked.
+type IngestionTracker struct {
+	source     string
+	resolution time.Duration
+	created    time.Time
+	lock       sync.Mutex
+	last       map[string]time.Time
+	untrack    []func()
+}
+
+// NewIngestionTracker creates an IngestionTracker for an ingestor of the source and resolution. Integrations which
+// have not ingested data are reported as lagging since the tracker was created.
+func NewIngestionTracker(source string, resolution time.Duration) *IngestionTracker {
+	return &IngestionTracker{
+		source:     source,
+		resolution: resolution,
+		created:    time.Now().UTC(),
+		last:       map[string]time.Time{},
+	}
+}
+
+// StartBuild starts the span of an ingestor building a window, as a child of the span on the given context. The window
+// bounds are added to the given attributes.
+func StartBuild(ctx context.Context, tracerName, spanName string, start, end time.Time, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("window.start", start.UTC().Format(time.RFC3339)),
+		attribute.String("window.end", end.UTC().Format(time.RFC3339)),
+	)
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attrs...))
+}
+
+// Track reports the ingestion lag of the integrations until Untrack is called
+func (it *IngestionTracker) Track(integrations ...string) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+	for _, integration := range integrations {
+		it.untrack = append(it.untrack, TrackIngestionLag(it.source, integration, it.resolution, func() time.Time {
+			return it.LastIngested(integration)
+		}))
+	}
+}
+
+// Untrack stops reporting the ingestion lag of the tracked integrations
+func (it *IngestionTracker) Untrack() {
+	it.lock.Lock()
+	untrack := it.untrack
+	it.untrack = nil
+	it.lock.Unlock()
+
+	for _, fn := range untrack {
+		fn()
+	}
+}
+
+// Ingested records that data of the integration was ingested now
+func (it *IngestionTracker) Ingested(integration string) {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+	it.last[integration] = time.Now().UTC()
+}
+
+// LastIngested returns the time data of the integration was last ingested, or the creation time of the tracker if
+// none has been
+func (it *IngestionTracker) LastIngested(integration string) time.Time {
+	it.lock.Lock()
+	defer it.lock.Unlock()
+	if t, ok := it.last[integration]; ok {
+		return t
+	}
+	return it.created
+}
+
+// RecordError records the error on the span, and sets the status of the span to error
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
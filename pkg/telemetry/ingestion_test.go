@@ -0,0 +1,22 @@
+package telemetry
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIngestionTracker_LastIngested(t *testing.T) {
+	it := NewIngestionTracker("test", time.Hour)
+
+	if got := it.LastIngested("a"); !got.Equal(it.created) {
+		t.Fatalf("expected creation time %s before ingestion, got %s", it.created, got)
+	}
+
+	it.Ingested("a")
+	if got := it.LastIngested("a"); got.Before(it.created) {
+		t.Fatalf("expected ingestion time after creation time %s, got %s", it.created, got)
+	}
+	if got := it.LastIngested("b"); !got.Equal(it.created) {
+		t.Fatalf("expected creation time %s for integration which has not ingested, got %s", it.created, got)
+	}
+}
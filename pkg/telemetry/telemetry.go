@@ -0,0 +1,184 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.32.0"
+
+	"github.com/opencost/opencost/core/pkg/log"
+	"github.com/opencost/opencost/core/pkg/util/timeutil"
+	"github.com/opencost/opencost/core/pkg/version"
+	"github.com/opencost/opencost/pkg/env"
+)
+
+const (
+	// ServiceName is the service.name of the traces and metrics exported by OpenCost
+	ServiceName = "opencost"
+
+	meterName = "github.com/opencost/opencost/pkg/telemetry"
+)
+
+// Config configures the export of traces and metrics over OTLP.
+type Config struct {
+	// Endpoint is the host:port of the OTLP gRPC receiver
+	Endpoint string
+	// Insecure disables TLS on the connection to the receiver
+	Insecure bool
+	// SampleRatio is the fraction of root traces which are sampled. Traces with a sampled parent are always sampled.
+	SampleRatio float64
+	// MetricExportInterval is the interval at which metrics are exported
+	MetricExportInterval time.Duration
+}
+
+// DefaultConfig returns the Config read from the environment.
+func DefaultConfig() Config {
+	return Config{
+		Endpoint:             env.GetTelemetryOTLPEndpoint(),
+		Insecure:             env.IsTelemetryOTLPInsecure(),
+		SampleRatio:          env.GetTelemetryTraceSampleRatio(),
+		MetricExportInterval: env.GetTelemetryMetricExportInterval(),
+	}
+}
+
+// Start sets the global TracerProvider and MeterProvider to ones which export over OTLP to the configured endpoint,
+// and the global propagator to W3C trace context and baggage, so that traces continue across services. Spans and
+// instruments created from the otel package before Start are forwarded to the new providers. The returned function
+// flushes and shuts down both providers.
+func Start(ctx context.Context, conf Config) (func(context.Context) error, error) {
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion(version.FriendlyVersion()),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating resource: %w", err)
+	}
+
+	traceOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(conf.Endpoint)}
+	metricOpts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(conf.Endpoint)}
+	if conf.Insecure {
+		traceOpts = append(traceOpts, otlptracegrpc.WithInsecure())
+		metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+	}
+
+	traceExporter, err := otlptracegrpc.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating trace exporter: %w", err)
+	}
+
+	metricExporter, err := otlpmetricgrpc.New(ctx, metricOpts...)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("creating metric exporter: %w", err), traceExporter.Shutdown(ctx))
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+		sdktrace.WithResource(res),
+	)
+
+	interval := conf.MetricExportInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(interval))),
+		sdkmetric.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	log.Infof("Exporting traces and metrics over OTLP to %s with a trace sample ratio of %g", conf.Endpoint, conf.SampleRatio)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}, nil
+}
+
+// lagKey identifies the ingestor of an integration whose lag is tracked
+type lagKey struct {
+	source      string
+	integration string
+	resolution  string
+}
+
+var (
+	lagLock    sync.Mutex
+	lagSources = map[lagKey]func() time.Time{}
+)
+
+// ingestionLag is created from the global MeterProvider, which forwards it, and its callback, to the provider set by
+// Start
+var ingestionLag = newIngestionLagGauge()
+
+func newIngestionLagGauge() metric.Float64ObservableGauge {
+	meter := otel.Meter(meterName)
+
+	gauge, err := meter.Float64ObservableGauge(
+		"opencost.ingestion.lag",
+		metric.WithUnit("s"),
+		metric.WithDescription("The time since an ingestor last ingested data from its integration"),
+	)
+	if err != nil {
+		log.Errorf("failed to create ingestion lag gauge: %s", err)
+		return gauge
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		lagLock.Lock()
+		defer lagLock.Unlock()
+
+		for key, lastIngested := range lagSources {
+			o.ObserveFloat64(gauge, time.Since(lastIngested()).Seconds(), metric.WithAttributes(
+				attribute.String("source", key.source),
+				attribute.String("integration", key.integration),
+				attribute.String("resolution", key.resolution),
+			))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		log.Errorf("failed to register ingestion lag callback: %s", err)
+	}
+
+	return gauge
+}
+
+// TrackIngestionLag reports the lag of the ingestor of the given source, e.g. cloudcost or customcost, for the given
+// integration and resolution, as the time since lastIngested, until the returned function is called. lastIngested is
+// called each time metrics are collected, so it must be safe for concurrent use.
+func TrackIngestionLag(source, integration string, resolution time.Duration, lastIngested func() time.Time) func() {
+	key := lagKey{
+		source:      source,
+		integration: integration,
+		resolution:  timeutil.FormatStoreResolution(resolution),
+	}
+
+	lagLock.Lock()
+	defer lagLock.Unlock()
+	lagSources[key] = lastIngested
+
+	return func() {
+		lagLock.Lock()
+		defer lagLock.Unlock()
+		delete(lagSources, key)
+	}
+}
@@ -0,0 +1,187 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	export "github.com/opencost/opencost/core/pkg/exporter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	collectormetrics "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// collector is an in-process OTLP gRPC receiver, which keeps the spans and metrics exported to it
+type collector struct {
+	collectortrace.UnimplementedTraceServiceServer
+
+	lock    sync.Mutex
+	spans   []*tracepb.Span
+	metrics []*metricspb.Metric
+}
+
+func (c *collector) Export(ctx context.Context, req *collectortrace.ExportTraceServiceRequest) (*collectortrace.ExportTraceServiceResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	return &collectortrace.ExportTraceServiceResponse{}, nil
+}
+
+// metricsServer receives the metrics for the collector, as the trace and metrics services both define Export
+type metricsServer struct {
+	collectormetrics.UnimplementedMetricsServiceServer
+	c *collector
+}
+
+func (s metricsServer) Export(ctx context.Context, req *collectormetrics.ExportMetricsServiceRequest) (*collectormetrics.ExportMetricsServiceResponse, error) {
+	s.c.lock.Lock()
+	defer s.c.lock.Unlock()
+
+	for _, rm := range req.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			s.c.metrics = append(s.c.metrics, sm.Metrics...)
+		}
+	}
+	return &collectormetrics.ExportMetricsServiceResponse{}, nil
+}
+
+func (c *collector) span(name string) *tracepb.Span {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, s := range c.spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *collector) metric(name string) *metricspb.Metric {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, m := range c.metrics {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// startCollector starts an in-process OTLP collector, and returns it along with its endpoint
+func startCollector(t *testing.T) (*collector, string) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %s", err)
+	}
+
+	c := &collector{}
+	server := grpc.NewServer()
+	collectortrace.RegisterTraceServiceServer(server, c)
+	collectormetrics.RegisterMetricsServiceServer(server, metricsServer{c: c})
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return c, listener.Addr().String()
+}
+
+func TestStart(t *testing.T) {
+	c, endpoint := startCollector(t)
+
+	// instruments are created before Start, as they are by the packages which import otel
+	stopTracking := TrackIngestionLag("cloudcost", "test-integration", 24*time.Hour, func() time.Time {
+		return time.Now().Add(-time.Hour)
+	})
+	defer stopTracking()
+
+	// the global providers only forward instruments created before them the first time they are set, so Start is only
+	// called once per test binary
+	shutdown, err := Start(context.Background(), Config{
+		Endpoint:             endpoint,
+		Insecure:             true,
+		SampleRatio:          1.0,
+		MetricExportInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("failed to start telemetry: %s", err)
+	}
+
+	handlerSpans := map[string]trace.SpanContext{}
+	handler := HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerSpans[r.URL.Path] = trace.SpanContextFromContext(r.Context())
+
+		_, span := otel.Tracer("test").Start(r.Context(), "compute")
+		span.End()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/allocation", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	export.RecordExportRun(context.Background(), "csv", nil)
+	export.RecordExportRun(context.Background(), "csv", errors.New("failed"))
+
+	// shutting down flushes the spans and metrics
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("failed to shut down telemetry: %s", err)
+	}
+
+	request := c.span("GET /allocation")
+	if request == nil {
+		t.Fatalf("Expected a span for the HTTP request")
+	}
+	if trace.SpanID(request.SpanId) != handlerSpans["/allocation"].SpanID() {
+		t.Errorf("Expected the span of the HTTP request on the context of the handler")
+	}
+	compute := c.span("compute")
+	if compute == nil {
+		t.Fatalf("Expected a span for the computation")
+	}
+	if trace.SpanID(compute.ParentSpanId) != trace.SpanID(request.SpanId) {
+		t.Errorf("Expected the computation span to be a child of the HTTP request span")
+	}
+	if c.span("GET /healthz") != nil || handlerSpans["/healthz"].IsValid() {
+		t.Errorf("Expected no span for the health check")
+	}
+
+	lag := c.metric("opencost.ingestion.lag")
+	if lag == nil {
+		t.Fatalf("Expected the ingestion lag metric")
+	}
+	points := lag.GetGauge().GetDataPoints()
+	if len(points) != 1 || points[0].GetAsDouble() < time.Hour.Seconds() {
+		t.Errorf("Expected an ingestion lag of at least an hour. Got: %v", points)
+	}
+
+	runs := c.metric("opencost.export.runs")
+	if runs == nil {
+		t.Fatalf("Expected the export runs metric")
+	}
+	successes := map[bool]int64{}
+	for _, p := range runs.GetSum().GetDataPoints() {
+		for _, attr := range p.Attributes {
+			if attr.Key == "success" {
+				successes[attr.Value.GetBoolValue()] += p.GetAsInt()
+			}
+		}
+	}
+	if successes[true] != 1 || successes[false] != 1 {
+		t.Errorf("Expected 1 successful and 1 failed export run. Got: %v", successes)
+	}
+}